@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// StatementMonthLayout 对账单月份的格式
+const StatementMonthLayout = "200601"
+
+// Balance 账号的余额
+type Balance struct {
+	Balance int64
+	// Frozen 申请了提现还没有打款的
+	Frozen   int64
+	Currency string
+}
+
+// Available 可以用的钱
+func (b Balance) Available() int64 {
+	return b.Balance - b.Frozen
+}
+
+// Statement 某个账号某个月的对账单
+type Statement struct {
+	Account     int64
+	AccountType AccountType
+	Month       time.Time
+	Currency    string
+	// OpeningBalance 期初余额，也就是这个月之前所有流水的和
+	OpeningBalance int64
+	ClosingBalance int64
+	TotalIn        int64
+	// TotalOut 支出，正数
+	TotalOut   int64
+	Activities []Activity
+}
@@ -1,9 +1,22 @@
 package domain
 
+// Credit 入账，复式记账，Items 里面的金额按照币种加起来必须是 0。
+// 比如说打赏，作者和平台的账号是正数，清算账号是负数
 type Credit struct {
 	Biz   string
 	BizId int64
 	Items []CreditItem
+	// FeeRuleId 分账用的平台抽成规则，记在每一条流水上方便财务核对。
+	// 不是按照规则分账的就是 0
+	FeeRuleId int64
+}
+
+// Debit 出账，Items 里面的金额是正数，代表从这个账号扣多少钱。
+// 钱是付到外部去了，所以对手方是清算账号，不需要调用者传
+type Debit struct {
+	Biz   string
+	BizId int64
+	Items []CreditItem
 }
 
 type CreditItem struct {
@@ -12,6 +25,9 @@ type CreditItem struct {
 	AccountType AccountType
 	Amt         int64
 	Currency    string
+	// SettleCurrency 入账到哪个币种的账号，为空或者和 Currency 一样就不用换汇。
+	// 比如说用户用美元打赏，作者的账号是人民币的
+	SettleCurrency string
 }
 
 type AccountType uint8
@@ -24,4 +40,7 @@ const (
 	AccountTypeUnknown = iota
 	AccountTypeReward
 	AccountTypeSystem
+	// AccountTypeClearing 清算账号，代表外部的资金，比如说第三方支付。
+	// 钱进来的时候记负数，出去的时候记正数
+	AccountTypeClearing
 )
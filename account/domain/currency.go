@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"errors"
+	"math/big"
+)
+
+// DefaultCurrency 以前只有人民币，没有传币种的都是人民币
+const DefaultCurrency = "CNY"
+
+var ErrInvalidRate = errors.New("汇率格式不对")
+
+// exponents 每个币种的最小单位是 10 的多少次方分之一，
+// 比如说人民币是分，所以是 2；日元没有更小的单位，所以是 0。
+// 没有列出来的都是 2
+var exponents = map[string]int{
+	"JPY": 0,
+	"KRW": 0,
+	"VND": 0,
+	"BHD": 3,
+	"KWD": 3,
+}
+
+// Exponent 币种最小单位的小数位数
+func Exponent(currency string) int {
+	if e, ok := exponents[currency]; ok {
+		return e
+	}
+	return 2
+}
+
+// Rate 汇率，1 元（不是分）的 From 可以换 Value 元的 To。
+// Value 是十进制的小数，比如说 7.1，记账的时候原样记下来
+type Rate struct {
+	From  string
+	To    string
+	Value string
+}
+
+// Convert 把 From 的金额（最小单位）换算成 To 的金额（最小单位），四舍五入
+func (r Rate) Convert(amt int64) (int64, error) {
+	v, ok := new(big.Rat).SetString(r.Value)
+	if !ok || v.Sign() <= 0 {
+		return 0, ErrInvalidRate
+	}
+	res := new(big.Rat).Mul(new(big.Rat).SetInt64(amt), v)
+	// 两个币种最小单位的位数可能不一样
+	diff := Exponent(r.To) - Exponent(r.From)
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(diff))), nil))
+	if diff >= 0 {
+		res.Mul(res, scale)
+	} else {
+		res.Quo(res, scale)
+	}
+	return round(res), nil
+}
+
+// round 四舍五入，负数也是远离 0 的方向
+func round(x *big.Rat) int64 {
+	q, m := new(big.Int).QuoRem(x.Num(), x.Denom(), new(big.Int))
+	m.Abs(m).Lsh(m, 1)
+	if m.Cmp(x.Denom()) >= 0 {
+		if x.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q.Int64()
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
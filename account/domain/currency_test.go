@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRate_Convert(t *testing.T) {
+	testCases := []struct {
+		name string
+		rate Rate
+		amt  int64
+
+		want    int64
+		wantErr error
+	}{
+		{
+			name: "美元换人民币",
+			rate: Rate{From: "USD", To: "CNY", Value: "7.1"},
+			amt:  100,
+			want: 710,
+		},
+		{
+			name: "四舍五入",
+			rate: Rate{From: "USD", To: "CNY", Value: "7.125"},
+			amt:  1,
+			want: 7,
+		},
+		{
+			name: "负数也是四舍五入",
+			rate: Rate{From: "USD", To: "CNY", Value: "7.15"},
+			amt:  -10,
+			want: -72,
+		},
+		{
+			name: "日元没有分",
+			rate: Rate{From: "JPY", To: "CNY", Value: "0.048"},
+			amt:  1000,
+			want: 4800,
+		},
+		{
+			name: "换成日元",
+			rate: Rate{From: "CNY", To: "JPY", Value: "20.5"},
+			amt:  1001,
+			want: 205,
+		},
+		{
+			name:    "汇率不对",
+			rate:    Rate{From: "USD", To: "CNY", Value: "abc"},
+			amt:     100,
+			wantErr: ErrInvalidRate,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.rate.Convert(tc.amt)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, res)
+		})
+	}
+}
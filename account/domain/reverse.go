@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// Reverse 冲正，把之前入账的钱按照比例扣回来，比如说退款
+type Reverse struct {
+	Biz   string
+	BizId int64
+	// ReverseNo 一次冲正的唯一标识，用来去重
+	ReverseNo string
+	Amt       int64
+}
+
+// Activity 账号的一条流水。
+// 冲正的流水 ReverseNo 不为空，金额和原本入账的符号相反。
+// CreditItem 里面的 Amt 和 Currency 是入账之后的，也就是账号的币种
+type Activity struct {
+	Id int64
+	CreditItem
+	// OrigAmt 和 OrigCurrency 是换汇之前的，没有换汇的话和 Amt、Currency 一样
+	OrigAmt      int64
+	OrigCurrency string
+	// Rate 换汇用的汇率，没有换汇就是 1
+	Rate string
+	// FeeRuleId 分账用的平台抽成规则，冲正的流水和原本的入账一样
+	FeeRuleId int64
+	Biz       string
+	BizId     int64
+	ReverseNo string
+	Ctime     time.Time
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+// Withdrawal 提现，把账号里面的钱转到用户自己的银行卡或者零钱里面
+type Withdrawal struct {
+	Id int64
+	// WithdrawNO 调用方生成的唯一标识，用来去重
+	WithdrawNO  string
+	Uid         int64
+	Account     int64
+	AccountType AccountType
+	Amt         int64
+	Currency    string
+	Status      WithdrawalStatus
+	// TxnID 第三方打款的流水号
+	TxnID string
+	// Reason 失败或者被拒绝的原因
+	Reason string
+	Ctime  time.Time
+	Utime  time.Time
+}
+
+// WithdrawalStatus 提现的状态机：
+// Requested -> Approved -> Paid
+// Requested -> Failed，审核不通过
+// Approved -> Failed，打款失败
+type WithdrawalStatus uint8
+
+func (s WithdrawalStatus) AsUint8() uint8 {
+	return uint8(s)
+}
+
+const (
+	WithdrawalStatusUnknown WithdrawalStatus = iota
+	// WithdrawalStatusRequested 已申请，钱已经冻结
+	WithdrawalStatusRequested
+	// WithdrawalStatusApproved 审核通过，正在打款
+	WithdrawalStatusApproved
+	// WithdrawalStatusPaid 打款成功，钱已经从账号里面扣掉了
+	WithdrawalStatusPaid
+	// WithdrawalStatusFailed 被拒绝或者打款失败，钱已经解冻
+	WithdrawalStatusFailed
+)
+
+// Transfer 第三方打款的结果
+type Transfer struct {
+	TxnID string
+	// Status 只会是 Approved（还在处理）、Paid 或者 Failed
+	Status WithdrawalStatus
+	Reason string
+}
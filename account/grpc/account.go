@@ -11,11 +11,13 @@ import (
 
 type AccountServiceServer struct {
 	accountv1.UnimplementedAccountServiceServer
-	svc service.AccountService
+	svc  service.AccountService
+	wsvc service.WithdrawalService
 }
 
-func NewAccountServiceServer(svc service.AccountService) *AccountServiceServer {
-	return &AccountServiceServer{svc: svc}
+func NewAccountServiceServer(svc service.AccountService,
+	wsvc service.WithdrawalService) *AccountServiceServer {
+	return &AccountServiceServer{svc: svc, wsvc: wsvc}
 }
 
 func (a *AccountServiceServer) Credit(ctx context.Context,
@@ -24,10 +26,178 @@ func (a *AccountServiceServer) Credit(ctx context.Context,
 	return &accountv1.CreditResponse{}, err
 }
 
+func (a *AccountServiceServer) Reverse(ctx context.Context,
+	req *accountv1.ReverseRequest) (*accountv1.ReverseResponse, error) {
+	err := a.svc.Reverse(ctx, domain.Reverse{
+		Biz:       req.GetBiz(),
+		BizId:     req.GetBizId(),
+		ReverseNo: req.GetReverseNo(),
+		Amt:       req.GetAmt(),
+	})
+	return &accountv1.ReverseResponse{}, err
+}
+
+func (a *AccountServiceServer) Debit(ctx context.Context,
+	req *accountv1.DebitRequest) (*accountv1.DebitResponse, error) {
+	err := a.svc.Debit(ctx, domain.Debit{
+		Biz:   req.GetBiz(),
+		BizId: req.GetBizId(),
+		Items: slice.Map(req.GetItems(), func(idx int, src *accountv1.CreditItem) domain.CreditItem {
+			return a.itemToDomain(src)
+		}),
+	})
+	return &accountv1.DebitResponse{}, err
+}
+
+func (a *AccountServiceServer) RequestWithdrawal(ctx context.Context,
+	req *accountv1.RequestWithdrawalRequest) (*accountv1.RequestWithdrawalResponse, error) {
+	w, err := a.wsvc.RequestWithdrawal(ctx, domain.Withdrawal{
+		WithdrawNO:  req.GetWithdrawNo(),
+		Uid:         req.GetUid(),
+		Account:     req.GetAccount(),
+		AccountType: domain.AccountType(req.GetAccountType()),
+		Amt:         req.GetAmt(),
+		Currency:    req.GetCurrency(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &accountv1.RequestWithdrawalResponse{Withdrawal: a.withdrawalToDTO(w)}, nil
+}
+
+func (a *AccountServiceServer) ApproveWithdrawal(ctx context.Context,
+	req *accountv1.ApproveWithdrawalRequest) (*accountv1.ApproveWithdrawalResponse, error) {
+	w, err := a.wsvc.ApproveWithdrawal(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &accountv1.ApproveWithdrawalResponse{Withdrawal: a.withdrawalToDTO(w)}, nil
+}
+
+func (a *AccountServiceServer) RejectWithdrawal(ctx context.Context,
+	req *accountv1.RejectWithdrawalRequest) (*accountv1.RejectWithdrawalResponse, error) {
+	err := a.wsvc.RejectWithdrawal(ctx, req.GetId(), req.GetReason())
+	return &accountv1.RejectWithdrawalResponse{}, err
+}
+
+func (a *AccountServiceServer) GetWithdrawal(ctx context.Context,
+	req *accountv1.GetWithdrawalRequest) (*accountv1.GetWithdrawalResponse, error) {
+	w, err := a.wsvc.GetWithdrawal(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &accountv1.GetWithdrawalResponse{Withdrawal: a.withdrawalToDTO(w)}, nil
+}
+
+func (a *AccountServiceServer) ListWithdrawals(ctx context.Context,
+	req *accountv1.ListWithdrawalsRequest) (*accountv1.ListWithdrawalsResponse, error) {
+	ws, err := a.wsvc.ListWithdrawals(ctx, req.GetUid(), int(req.GetOffset()), int(req.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+	return &accountv1.ListWithdrawalsResponse{
+		Withdrawals: slice.Map(ws, func(idx int, src domain.Withdrawal) *accountv1.Withdrawal {
+			return a.withdrawalToDTO(src)
+		}),
+	}, nil
+}
+
+func (a *AccountServiceServer) GetBalance(ctx context.Context,
+	req *accountv1.GetBalanceRequest) (*accountv1.GetBalanceResponse, error) {
+	b, err := a.svc.GetBalance(ctx, req.GetAccount(), domain.AccountType(req.GetAccountType()),
+		a.currency(req.GetCurrency()))
+	if err != nil {
+		return nil, err
+	}
+	return &accountv1.GetBalanceResponse{
+		Balance:   b.Balance,
+		Frozen:    b.Frozen,
+		Available: b.Available(),
+		Currency:  b.Currency,
+	}, nil
+}
+
+func (a *AccountServiceServer) ListActivities(ctx context.Context,
+	req *accountv1.ListActivitiesRequest) (*accountv1.ListActivitiesResponse, error) {
+	acts, err := a.svc.ListActivities(ctx, req.GetAccount(), domain.AccountType(req.GetAccountType()),
+		a.currency(req.GetCurrency()), int(req.GetOffset()), int(req.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+	return &accountv1.ListActivitiesResponse{Activities: a.activitiesToDTO(acts)}, nil
+}
+
+func (a *AccountServiceServer) GetStatement(ctx context.Context,
+	req *accountv1.GetStatementRequest) (*accountv1.GetStatementResponse, error) {
+	st, err := a.svc.GetStatement(ctx, req.GetAccount(), domain.AccountType(req.GetAccountType()),
+		a.currency(req.GetCurrency()), req.GetMonth())
+	if err != nil {
+		return nil, err
+	}
+	return &accountv1.GetStatementResponse{
+		Statement: &accountv1.Statement{
+			Account:        st.Account,
+			AccountType:    accountv1.AccountType(st.AccountType),
+			Month:          st.Month.Format(domain.StatementMonthLayout),
+			Currency:       st.Currency,
+			OpeningBalance: st.OpeningBalance,
+			ClosingBalance: st.ClosingBalance,
+			TotalIn:        st.TotalIn,
+			TotalOut:       st.TotalOut,
+			Activities:     a.activitiesToDTO(st.Activities),
+		},
+	}, nil
+}
+
+func (a *AccountServiceServer) activitiesToDTO(acts []domain.Activity) []*accountv1.Activity {
+	return slice.Map(acts, func(idx int, src domain.Activity) *accountv1.Activity {
+		return &accountv1.Activity{
+			Id:           src.Id,
+			Biz:          src.Biz,
+			BizId:        src.BizId,
+			Amt:          src.Amt,
+			Currency:     src.Currency,
+			ReverseNo:    src.ReverseNo,
+			Ctime:        src.Ctime.UnixMilli(),
+			OrigAmt:      src.OrigAmt,
+			OrigCurrency: src.OrigCurrency,
+			Rate:         src.Rate,
+			FeeRuleId:    src.FeeRuleId,
+		}
+	})
+}
+
+// currency 以前的调用方不传币种，都是人民币
+func (a *AccountServiceServer) currency(c string) string {
+	if c == "" {
+		return domain.DefaultCurrency
+	}
+	return c
+}
+
+func (a *AccountServiceServer) withdrawalToDTO(w domain.Withdrawal) *accountv1.Withdrawal {
+	return &accountv1.Withdrawal{
+		Id:          w.Id,
+		WithdrawNo:  w.WithdrawNO,
+		Uid:         w.Uid,
+		Account:     w.Account,
+		AccountType: accountv1.AccountType(w.AccountType),
+		Amt:         w.Amt,
+		Currency:    w.Currency,
+		// 两边取值是一样的
+		Status: accountv1.WithdrawalStatus(w.Status),
+		TxnId:  w.TxnID,
+		Reason: w.Reason,
+		Ctime:  w.Ctime.UnixMilli(),
+		Utime:  w.Utime.UnixMilli(),
+	}
+}
+
 func (a *AccountServiceServer) toDomain(c *accountv1.CreditRequest) domain.Credit {
 	return domain.Credit{
-		Biz:   c.Biz,
-		BizId: c.BizId,
+		Biz:       c.Biz,
+		BizId:     c.BizId,
+		FeeRuleId: c.FeeRuleId,
 		Items: slice.Map(c.Items, func(idx int, src *accountv1.CreditItem) domain.CreditItem {
 			return a.itemToDomain(src)
 		}),
@@ -40,8 +210,9 @@ func (a *AccountServiceServer) itemToDomain(c *accountv1.CreditItem) domain.Cred
 		Amt:     c.Amt,
 		Uid:     c.Uid,
 		// 两者取值都是一样的，我偷个懒，直接转
-		AccountType: domain.AccountType(c.AccountType),
-		Currency:    c.Currency,
+		AccountType:    domain.AccountType(c.AccountType),
+		Currency:       c.Currency,
+		SettleCurrency: c.SettleCurrency,
 	}
 }
 
@@ -5,6 +5,7 @@ import (
 	"gitee.com/geekbang/basic-go/webook/account/grpc"
 	"gitee.com/geekbang/basic-go/webook/account/integration/startup"
 	"gitee.com/geekbang/basic-go/webook/account/repository/dao"
+	"gitee.com/geekbang/basic-go/webook/account/service"
 	accountv1 "gitee.com/geekbang/basic-go/webook/api/proto/gen/account/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -27,7 +28,8 @@ func (s *AccountServiceServerTestSuite) SetupSuite() {
 
 func (s *AccountServiceServerTestSuite) TearDownTest() {
 	s.db.Exec("TRUNCATE TABLE `accounts`")
-	//s.db.Exec("TRUNCATE TABLE `account_activities`")
+	s.db.Exec("TRUNCATE TABLE `withdrawals`")
+	s.db.Exec("TRUNCATE TABLE `account_activities`")
 }
 
 func (s *AccountServiceServerTestSuite) TestCredit() {
@@ -66,6 +68,7 @@ func (s *AccountServiceServerTestSuite) TestCredit() {
 					Type:     uint8(accountv1.AccountType_AccountTypeReward),
 					Balance:  100,
 					Currency: "CNY",
+					Version:  1,
 				}, usrAccount)
 			},
 			req: &accountv1.CreditRequest{
@@ -84,6 +87,11 @@ func (s *AccountServiceServerTestSuite) TestCredit() {
 						Amt:         10,
 						Currency:    "CNY",
 					},
+					{
+						AccountType: accountv1.AccountType_AccountTypeClearing,
+						Amt:         -110,
+						Currency:    "CNY",
+					},
 				},
 			},
 		},
@@ -119,11 +127,79 @@ func (s *AccountServiceServerTestSuite) TestCredit() {
 					Type:     uint8(accountv1.AccountType_AccountTypeReward),
 					Balance:  400,
 					Currency: "CNY",
+					Version:  1,
 				}, usrAccount)
 			},
 			req: &accountv1.CreditRequest{
 				Biz:   "test",
 				BizId: 123,
+				Items: []*accountv1.CreditItem{
+					{
+						Account:     123,
+						AccountType: accountv1.AccountType_AccountTypeReward,
+						Amt:         100,
+						Currency:    "CNY",
+						Uid:         1025,
+					},
+					{
+						AccountType: accountv1.AccountType_AccountTypeClearing,
+						Amt:         -100,
+						Currency:    "CNY",
+					},
+				},
+			},
+		},
+		{
+			name:   "美元换成人民币入账",
+			before: func(t *testing.T) {},
+			after: func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+				defer cancel()
+				var usrAccount dao.Account
+				err := s.db.WithContext(ctx).Where("uid = ?", 1026).
+					First(&usrAccount).Error
+				require.NoError(t, err)
+				assert.Equal(t, "CNY", usrAccount.Currency)
+				assert.Equal(t, int64(710), usrAccount.Balance)
+				var act dao.AccountActivity
+				err = s.db.WithContext(ctx).
+					Where("biz = ? AND biz_id = ? AND uid = ?", "test", 125, 1026).
+					First(&act).Error
+				require.NoError(t, err)
+				assert.Equal(t, int64(710), act.Amount)
+				assert.Equal(t, "CNY", act.Currency)
+				assert.Equal(t, int64(100), act.OrigAmount)
+				assert.Equal(t, "USD", act.OrigCurrency)
+				assert.Equal(t, "7.1", act.Rate)
+			},
+			req: &accountv1.CreditRequest{
+				Biz:   "test",
+				BizId: 125,
+				Items: []*accountv1.CreditItem{
+					{
+						Account:        123,
+						AccountType:    accountv1.AccountType_AccountTypeReward,
+						Amt:            100,
+						Currency:       "USD",
+						SettleCurrency: "CNY",
+						Uid:            1026,
+					},
+					{
+						AccountType:    accountv1.AccountType_AccountTypeClearing,
+						Amt:            -100,
+						Currency:       "USD",
+						SettleCurrency: "CNY",
+					},
+				},
+			},
+		},
+		{
+			name:   "入账不平",
+			before: func(t *testing.T) {},
+			after:  func(t *testing.T) {},
+			req: &accountv1.CreditRequest{
+				Biz:   "test",
+				BizId: 124,
 				Items: []*accountv1.CreditItem{
 					{
 						Account:     123,
@@ -134,6 +210,7 @@ func (s *AccountServiceServerTestSuite) TestCredit() {
 					},
 				},
 			},
+			wantErr: service.ErrUnbalancedCredit,
 		},
 	}
 	for _, tc := range testCases {
@@ -146,6 +223,113 @@ func (s *AccountServiceServerTestSuite) TestCredit() {
 	}
 }
 
+func (s *AccountServiceServerTestSuite) TestWithdrawal() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	// 通过入账把钱打进来，这样对账单里面才有流水
+	_, err := s.server.Credit(ctx, &accountv1.CreditRequest{
+		Biz:   "test",
+		BizId: time.Now().UnixMilli(),
+		Items: []*accountv1.CreditItem{
+			{
+				Account:     2048,
+				AccountType: accountv1.AccountType_AccountTypeReward,
+				Amt:         300,
+				Currency:    "CNY",
+				Uid:         2048,
+			},
+			{
+				AccountType: accountv1.AccountType_AccountTypeClearing,
+				Amt:         -300,
+				Currency:    "CNY",
+			},
+		},
+	})
+	require.NoError(t, err)
+	assertAccount := func(balance, frozen int64) {
+		var acc dao.Account
+		err := s.db.WithContext(ctx).Where("uid = ?", 2048).First(&acc).Error
+		require.NoError(t, err)
+		assert.Equal(t, balance, acc.Balance)
+		assert.Equal(t, frozen, acc.Frozen)
+	}
+	req := &accountv1.RequestWithdrawalRequest{
+		WithdrawNo:  "withdraw-1",
+		Uid:         2048,
+		Account:     2048,
+		AccountType: accountv1.AccountType_AccountTypeReward,
+		Amt:         100,
+		Currency:    "CNY",
+	}
+
+	// 申请之后冻结，重复申请只冻结一次
+	resp, err := s.server.RequestWithdrawal(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, accountv1.WithdrawalStatus_WithdrawalStatusRequested, resp.Withdrawal.Status)
+	resp2, err := s.server.RequestWithdrawal(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, resp.Withdrawal.Id, resp2.Withdrawal.Id)
+	assertAccount(300, 100)
+
+	// 可用余额只剩 200 了
+	_, err = s.server.RequestWithdrawal(ctx, &accountv1.RequestWithdrawalRequest{
+		WithdrawNo:  "withdraw-2",
+		Uid:         2048,
+		Account:     2048,
+		AccountType: accountv1.AccountType_AccountTypeReward,
+		Amt:         250,
+		Currency:    "CNY",
+	})
+	assert.Equal(t, dao.ErrInsufficientBalance, err)
+
+	// 打款成功，扣钱并且记账
+	approved, err := s.server.ApproveWithdrawal(ctx, &accountv1.ApproveWithdrawalRequest{Id: resp.Withdrawal.Id})
+	require.NoError(t, err)
+	assert.Equal(t, accountv1.WithdrawalStatus_WithdrawalStatusPaid, approved.Withdrawal.Status)
+	assert.Equal(t, "local-withdraw-1", approved.Withdrawal.TxnId)
+	assertAccount(200, 0)
+	var act dao.AccountActivity
+	err = s.db.WithContext(ctx).Where("biz = ? AND biz_id = ?", "withdraw", resp.Withdrawal.Id).
+		First(&act).Error
+	require.NoError(t, err)
+	assert.Equal(t, int64(-100), act.Amount)
+	bal, err := s.server.GetBalance(ctx, &accountv1.GetBalanceRequest{
+		Account:     2048,
+		AccountType: accountv1.AccountType_AccountTypeReward,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(200), bal.Available)
+	st, err := s.server.GetStatement(ctx, &accountv1.GetStatementRequest{
+		Account:     2048,
+		AccountType: accountv1.AccountType_AccountTypeReward,
+		Month:       time.Now().Format("200601"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), st.Statement.OpeningBalance)
+	assert.Equal(t, int64(300), st.Statement.TotalIn)
+	assert.Equal(t, int64(100), st.Statement.TotalOut)
+	assert.Equal(t, int64(200), st.Statement.ClosingBalance)
+
+	// 拒绝之后解冻
+	resp, err = s.server.RequestWithdrawal(ctx, &accountv1.RequestWithdrawalRequest{
+		WithdrawNo:  "withdraw-3",
+		Uid:         2048,
+		Account:     2048,
+		AccountType: accountv1.AccountType_AccountTypeReward,
+		Amt:         50,
+		Currency:    "CNY",
+	})
+	require.NoError(t, err)
+	assertAccount(200, 50)
+	_, err = s.server.RejectWithdrawal(ctx, &accountv1.RejectWithdrawalRequest{
+		Id: resp.Withdrawal.Id, Reason: "实名信息不一致"})
+	require.NoError(t, err)
+	assertAccount(200, 0)
+	_, err = s.server.ApproveWithdrawal(ctx, &accountv1.ApproveWithdrawalRequest{Id: resp.Withdrawal.Id})
+	assert.Equal(t, dao.ErrWithdrawalStatus, err)
+}
+
 func TestAccountServiceServer(t *testing.T) {
 	suite.Run(t, new(AccountServiceServerTestSuite))
 }
@@ -0,0 +1,15 @@
+package startup
+
+import (
+	"gitee.com/geekbang/basic-go/webook/account/service"
+	"gitee.com/geekbang/basic-go/webook/account/service/rate"
+)
+
+// InitRateProvider 测试用固定的汇率，1 美元换 7.1 人民币
+func InitRateProvider() service.RateProvider {
+	p, err := rate.NewStaticProvider("CNY", map[string]string{"USD": "7.1"})
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
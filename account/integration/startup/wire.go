@@ -7,14 +7,21 @@ import (
 	"gitee.com/geekbang/basic-go/webook/account/repository"
 	"gitee.com/geekbang/basic-go/webook/account/repository/dao"
 	"gitee.com/geekbang/basic-go/webook/account/service"
+	"gitee.com/geekbang/basic-go/webook/account/service/transfer"
 	"github.com/google/wire"
 )
 
 func InitAccountService() *grpc.AccountServiceServer {
 	wire.Build(InitTestDB,
+		InitRateProvider,
 		dao.NewCreditGORMDAO,
 		repository.NewAccountRepository,
 		service.NewAccountService,
+		dao.NewWithdrawalGORMDAO,
+		repository.NewWithdrawalRepository,
+		InitLogger,
+		transfer.NewLocalProvider,
+		service.NewWithdrawalService,
 		grpc.NewAccountServiceServer)
 	return new(grpc.AccountServiceServer)
 }
@@ -11,6 +11,7 @@ import (
 	"gitee.com/geekbang/basic-go/webook/account/repository"
 	"gitee.com/geekbang/basic-go/webook/account/repository/dao"
 	"gitee.com/geekbang/basic-go/webook/account/service"
+	"gitee.com/geekbang/basic-go/webook/account/service/transfer"
 )
 
 // Injectors from wire.go:
@@ -19,7 +20,13 @@ func InitAccountService() *grpc.AccountServiceServer {
 	gormDB := InitTestDB()
 	accountDAO := dao.NewCreditGORMDAO(gormDB)
 	accountRepository := repository.NewAccountRepository(accountDAO)
-	accountService := service.NewAccountService(accountRepository)
-	accountServiceServer := grpc.NewAccountServiceServer(accountService)
+	rateProvider := InitRateProvider()
+	accountService := service.NewAccountService(accountRepository, rateProvider)
+	withdrawalDAO := dao.NewWithdrawalGORMDAO(gormDB)
+	withdrawalRepository := repository.NewWithdrawalRepository(withdrawalDAO)
+	loggerV1 := InitLogger()
+	transferProvider := transfer.NewLocalProvider(loggerV1)
+	withdrawalService := service.NewWithdrawalService(withdrawalRepository, transferProvider, loggerV1)
+	accountServiceServer := grpc.NewAccountServiceServer(accountService, withdrawalService)
 	return accountServiceServer
 }
@@ -0,0 +1,25 @@
+package ioc
+
+import (
+	"gitee.com/geekbang/basic-go/webook/account/service"
+	"gitee.com/geekbang/basic-go/webook/account/service/rate"
+	"github.com/spf13/viper"
+)
+
+func InitRateProvider() service.RateProvider {
+	type Config struct {
+		File string `yaml:"file"`
+	}
+	c := Config{
+		File: "config/rates.yaml",
+	}
+	err := viper.UnmarshalKey("rate", &c)
+	if err != nil {
+		panic(err)
+	}
+	p, err := rate.NewStaticFileProvider(c.File)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
@@ -0,0 +1,26 @@
+package ioc
+
+import (
+	"gitee.com/geekbang/basic-go/webook/account/service"
+	"gitee.com/geekbang/basic-go/webook/account/service/transfer"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/spf13/viper"
+)
+
+// InitTransferProvider 还没有接入真的打款渠道。
+// 本地渠道不会真的转账，只能在开发和测试环境打开，没有打开就拒绝所有打款
+func InitTransferProvider(l logger.LoggerV1) service.TransferProvider {
+	type Config struct {
+		Local bool `yaml:"local"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("transfer", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	if cfg.Local {
+		l.Warn("使用本地打款渠道，不会真的转账，线上千万不要打开")
+		return transfer.NewLocalProvider(l)
+	}
+	return transfer.NewDisabledProvider()
+}
@@ -5,6 +5,7 @@ import (
 	"gitee.com/geekbang/basic-go/webook/account/domain"
 	"gitee.com/geekbang/basic-go/webook/account/repository/cache"
 	"gitee.com/geekbang/basic-go/webook/account/repository/dao"
+	"github.com/ecodeclub/ekit/slice"
 	"time"
 )
 
@@ -25,21 +26,141 @@ func (a *accountRepository) SetUnique(ctx context.Context, c domain.Credit) erro
 	return a.cache.SetUnique(ctx, c)
 }
 
-func (a *accountRepository) AddCredit(ctx context.Context, c domain.Credit) error {
-	activities := make([]dao.AccountActivity, 0, len(c.Items))
+func (a *accountRepository) AddCredit(ctx context.Context, biz string, bizId int64, acts []domain.Activity) error {
+	return a.dao.AddActivities(ctx, a.activitiesToEntity(biz, bizId, "", acts)...)
+}
+
+func (a *accountRepository) AddReverse(ctx context.Context, r domain.Reverse,
+	fn func(acts []domain.Activity) ([]domain.Activity, error)) error {
+	return a.dao.Reverse(ctx, r.Biz, r.BizId, func(acts []dao.AccountActivity) ([]dao.AccountActivity, error) {
+		res, err := fn(a.activitiesToDomain(acts))
+		if err != nil {
+			return nil, err
+		}
+		return a.activitiesToEntity(r.Biz, r.BizId, r.ReverseNo, res), nil
+	})
+}
+
+func (a *accountRepository) activitiesToEntity(biz string, bizId int64, reverseNo string,
+	acts []domain.Activity) []dao.AccountActivity {
 	now := time.Now().UnixMilli()
-	for _, itm := range c.Items {
+	return slice.Map(acts, func(idx int, act domain.Activity) dao.AccountActivity {
+		return dao.AccountActivity{
+			Uid:          act.Uid,
+			Biz:          biz,
+			BizId:        bizId,
+			Account:      act.Account,
+			AccountType:  act.AccountType.AsUint8(),
+			Amount:       act.Amt,
+			Currency:     act.Currency,
+			OrigAmount:   act.OrigAmt,
+			OrigCurrency: act.OrigCurrency,
+			Rate:         act.Rate,
+			FeeRuleId:    act.FeeRuleId,
+			ReverseNo:    reverseNo,
+			Ctime:        now,
+			Utime:        now,
+		}
+	})
+}
+
+func (a *accountRepository) AddDebit(ctx context.Context, d domain.Debit) error {
+	activities := make([]dao.AccountActivity, 0, len(d.Items)+1)
+	now := time.Now().UnixMilli()
+	// 每个币种的钱都付到外面去了，对手方是清算账号
+	clearing := make(map[string]int64, 1)
+	for _, itm := range d.Items {
+		clearing[itm.Currency] += itm.Amt
 		activities = append(activities, dao.AccountActivity{
 			Uid:         itm.Uid,
-			Biz:         c.Biz,
-			BizId:       c.BizId,
+			Biz:         d.Biz,
+			BizId:       d.BizId,
 			Account:     itm.Account,
 			AccountType: itm.AccountType.AsUint8(),
-			Amount:      itm.Amt,
-			Currency:    itm.Currency,
-			Ctime:       now,
-			Utime:       now,
+			// 出账的流水是负数
+			Amount:       -itm.Amt,
+			Currency:     itm.Currency,
+			OrigAmount:   -itm.Amt,
+			OrigCurrency: itm.Currency,
+			Rate:         "1",
+			Ctime:        now,
+			Utime:        now,
 		})
 	}
-	return a.dao.AddActivities(ctx, activities...)
+	for currency, amt := range clearing {
+		activities = append(activities, dao.AccountActivity{
+			Biz:          d.Biz,
+			BizId:        d.BizId,
+			AccountType:  domain.AccountTypeClearing,
+			Amount:       amt,
+			Currency:     currency,
+			OrigAmount:   amt,
+			OrigCurrency: currency,
+			Rate:         "1",
+			Ctime:        now,
+			Utime:        now,
+		})
+	}
+	return a.dao.Debit(ctx, activities...)
+}
+
+func (a *accountRepository) FindActivities(ctx context.Context, biz string, bizId int64) ([]domain.Activity, error) {
+	acts, err := a.dao.FindActivities(ctx, biz, bizId)
+	return a.activitiesToDomain(acts), err
+}
+
+func (a *accountRepository) GetBalance(ctx context.Context, account int64, typ domain.AccountType,
+	currency string) (domain.Balance, error) {
+	acc, err := a.dao.GetAccount(ctx, account, typ.AsUint8(), currency)
+	return domain.Balance{
+		Balance:  acc.Balance,
+		Frozen:   acc.Frozen,
+		Currency: acc.Currency,
+	}, err
+}
+
+func (a *accountRepository) FindAccountActivities(ctx context.Context, account int64, typ domain.AccountType,
+	currency string, offset, limit int) ([]domain.Activity, error) {
+	acts, err := a.dao.FindAccountActivities(ctx, account, typ.AsUint8(), currency, offset, limit)
+	return a.activitiesToDomain(acts), err
+}
+
+func (a *accountRepository) FindActivitiesBetween(ctx context.Context, account int64, typ domain.AccountType,
+	currency string, start, end time.Time) ([]domain.Activity, error) {
+	acts, err := a.dao.FindActivitiesBetween(ctx, account, typ.AsUint8(), currency, start.UnixMilli(), end.UnixMilli())
+	return a.activitiesToDomain(acts), err
+}
+
+func (a *accountRepository) BalanceAt(ctx context.Context, account int64, typ domain.AccountType,
+	currency string, t time.Time) (int64, error) {
+	return a.dao.SumBefore(ctx, account, typ.AsUint8(), currency, t.UnixMilli())
+}
+
+func (a *accountRepository) activitiesToDomain(acts []dao.AccountActivity) []domain.Activity {
+	return slice.Map(acts, func(idx int, act dao.AccountActivity) domain.Activity {
+		// 以前的流水没有记录换汇，都是没换汇的
+		if act.OrigCurrency == "" {
+			act.OrigAmount = act.Amount
+			act.OrigCurrency = act.Currency
+			act.Rate = "1"
+		}
+		return domain.Activity{
+			Id: act.Id,
+			CreditItem: domain.CreditItem{
+				Uid:         act.Uid,
+				Account:     act.Account,
+				AccountType: domain.AccountType(act.AccountType),
+				Amt:         act.Amount,
+				Currency:    act.Currency,
+			},
+			OrigAmt:      act.OrigAmount,
+			OrigCurrency: act.OrigCurrency,
+			Rate:         act.Rate,
+			FeeRuleId:    act.FeeRuleId,
+			Biz:          act.Biz,
+			BizId:        act.BizId,
+			ReverseNo:    act.ReverseNo,
+			Ctime:        time.UnixMilli(act.Ctime),
+		}
+	})
 }
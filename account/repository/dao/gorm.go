@@ -2,11 +2,21 @@ package dao
 
 import (
 	"context"
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/account/domain"
+	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"time"
 )
 
+const (
+	// maxConflictRetries 版本号冲突之后最多重试几次
+	maxConflictRetries = 3
+	// conflictBackoff 每一次重试多等这么久，错开并发修改同一个账号的人
+	conflictBackoff = 10 * time.Millisecond
+)
+
 type AccountGORMDAO struct {
 	db *gorm.DB
 }
@@ -16,28 +26,227 @@ func NewCreditGORMDAO(db *gorm.DB) AccountDAO {
 }
 
 func (c *AccountGORMDAO) AddActivities(ctx context.Context, activities ...AccountActivity) error {
-	return c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		now := time.Now().UnixMilli()
-		// 针对每一个 activity，入账
-		for _, act := range activities {
-			err := c.db.Clauses(clause.OnConflict{
-				DoUpdates: clause.Assignments(map[string]any{
-					"balance": gorm.Expr("`balance`+?", act.Amount),
-					"utime":   now,
-				}),
-			}).Create(&Account{
-				Uid:      act.Uid,
-				Account:  act.Account,
-				Type:     act.AccountType,
-				Balance:  act.Amount,
-				Currency: act.Currency,
-				Utime:    now,
-				Ctime:    now,
-			}).Error
-			if err != nil {
-				return err
-			}
+	// 入账和冲正不检查余额，冲正之后余额可能是负数，也就是用户欠平台的钱
+	return c.post(ctx, activities, false)
+}
+
+func (c *AccountGORMDAO) Debit(ctx context.Context, activities ...AccountActivity) error {
+	return c.post(ctx, activities, true)
+}
+
+func (c *AccountGORMDAO) Reverse(ctx context.Context, biz string, bizId int64,
+	fn func(acts []AccountActivity) ([]AccountActivity, error)) error {
+	return transaction(ctx, c.db, func(tx *gorm.DB) error {
+		// 锁住这个业务的流水，并发冲正的时候后面的要等前面的提交了，
+		// 才能看到前面冲正了多少，这样加起来不会超过原本入账的金额
+		var acts []AccountActivity
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("biz = ? AND biz_id = ?", biz, bizId).
+			Order("id").
+			Find(&acts).Error
+		if err != nil {
+			return err
+		}
+		reversal, err := fn(acts)
+		if err != nil || len(reversal) == 0 {
+			return err
 		}
-		return tx.Create(&activities).Error
+		return c.postTx(tx, reversal, false)
+	})
+}
+
+// post 修改每一个账号的余额，然后记流水。check 为 true 的时候，扣钱的账号可用余额不能小于 0
+func (c *AccountGORMDAO) post(ctx context.Context, activities []AccountActivity, check bool) error {
+	return transaction(ctx, c.db, func(tx *gorm.DB) error {
+		return c.postTx(tx, activities, check)
 	})
 }
+
+func (c *AccountGORMDAO) postTx(tx *gorm.DB, activities []AccountActivity, check bool) error {
+	now := time.Now().UnixMilli()
+	for _, act := range activities {
+		err := changeAccount(tx, accountChange{
+			Uid:      act.Uid,
+			Account:  act.Account,
+			Type:     act.AccountType,
+			Currency: act.Currency,
+			Balance:  act.Amount,
+			Check:    check && act.Amount < 0,
+		}, now)
+		if err != nil {
+			return err
+		}
+	}
+	err := tx.Create(&activities).Error
+	if isDuplicate(err) {
+		return ErrDuplicateActivity
+	}
+	return err
+}
+
+func (c *AccountGORMDAO) FindActivities(ctx context.Context, biz string, bizId int64) ([]AccountActivity, error) {
+	var res []AccountActivity
+	err := c.db.WithContext(ctx).
+		Where("biz = ? AND biz_id = ?", biz, bizId).
+		Order("id").
+		Find(&res).Error
+	return res, err
+}
+
+func (c *AccountGORMDAO) GetAccount(ctx context.Context, account int64, typ uint8, currency string) (Account, error) {
+	var res Account
+	err := c.db.WithContext(ctx).
+		Where("account = ? AND type = ? AND currency = ?", account, typ, currency).
+		First(&res).Error
+	return res, err
+}
+
+func (c *AccountGORMDAO) FindAccountActivities(ctx context.Context, account int64, typ uint8, currency string,
+	offset, limit int) ([]AccountActivity, error) {
+	var res []AccountActivity
+	err := c.db.WithContext(ctx).
+		Where("account = ? AND account_type = ? AND currency = ?", account, typ, currency).
+		Order("id DESC").Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (c *AccountGORMDAO) FindActivitiesBetween(ctx context.Context, account int64, typ uint8, currency string,
+	start, end int64) ([]AccountActivity, error) {
+	var res []AccountActivity
+	err := c.db.WithContext(ctx).
+		Where("account = ? AND account_type = ? AND currency = ? AND ctime >= ? AND ctime < ?",
+			account, typ, currency, start, end).
+		Order("id").
+		Find(&res).Error
+	return res, err
+}
+
+func (c *AccountGORMDAO) SumBefore(ctx context.Context, account int64, typ uint8, currency string, t int64) (int64, error) {
+	var res int64
+	err := c.db.WithContext(ctx).Model(&AccountActivity{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("account = ? AND account_type = ? AND currency = ? AND ctime < ?", account, typ, currency, t).
+		Scan(&res).Error
+	return res, err
+}
+
+// accountChange 一个账号的余额变化
+type accountChange struct {
+	Uid      int64
+	Account  int64
+	Type     uint8
+	Currency string
+	Balance  int64
+	Frozen   int64
+	// Check 为 true 的时候，变化之后可用余额不能小于 0，并且账号必须是 Uid 的
+	Check bool
+}
+
+// changeAccount 修改余额，账号不存在就创建。
+// 用户的账号用版本号做乐观锁，冲突了返回 ErrVersionConflict，由 transaction 整个重试。
+// 清算账号和系统账号每一笔入账都要改，用乐观锁的话并发入账基本都会冲突，所以直接原子加减
+func changeAccount(tx *gorm.DB, c accountChange, now int64) error {
+	if !c.Check && (c.Type == domain.AccountTypeClearing || c.Type == domain.AccountTypeSystem) {
+		return incrAccount(tx, c, now)
+	}
+	var acc Account
+	err := tx.Where("account = ? AND type = ? AND currency = ?", c.Account, c.Type, c.Currency).
+		First(&acc).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if c.Check {
+			return ErrInsufficientBalance
+		}
+		err = tx.Create(&Account{
+			Uid:      c.Uid,
+			Account:  c.Account,
+			Type:     c.Type,
+			Balance:  c.Balance,
+			Frozen:   c.Frozen,
+			Currency: c.Currency,
+			Version:  1,
+			Utime:    now,
+			Ctime:    now,
+		}).Error
+		if isDuplicate(err) {
+			// 别人先创建了
+			return ErrVersionConflict
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if c.Check && (acc.Uid != c.Uid || acc.Balance+c.Balance-(acc.Frozen+c.Frozen) < 0) {
+		return ErrInsufficientBalance
+	}
+	// 不检查余额的扣钱（冲正）也不能动冻结的钱，不然提现打款之后余额就是负数了。
+	// 没有冻结的钱的时候才允许扣成负数
+	if c.Balance < 0 && acc.Frozen+c.Frozen > 0 && acc.Balance+c.Balance < acc.Frozen+c.Frozen {
+		return ErrInsufficientBalance
+	}
+	res := tx.Model(&Account{}).
+		Where("id = ? AND version = ?", acc.Id, acc.Version).
+		Updates(map[string]any{
+			"balance": acc.Balance + c.Balance,
+			"frozen":  acc.Frozen + c.Frozen,
+			"version": acc.Version + 1,
+			"utime":   now,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// incrAccount 原子加减余额，账号不存在就创建。不检查余额，也不会冲突
+func incrAccount(tx *gorm.DB, c accountChange, now int64) error {
+	return tx.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"balance": gorm.Expr("`balance`+?", c.Balance),
+			"frozen":  gorm.Expr("`frozen`+?", c.Frozen),
+			"version": gorm.Expr("`version`+1"),
+			"utime":   now,
+		}),
+	}).Create(&Account{
+		Uid:      c.Uid,
+		Account:  c.Account,
+		Type:     c.Type,
+		Balance:  c.Balance,
+		Frozen:   c.Frozen,
+		Currency: c.Currency,
+		Version:  1,
+		Utime:    now,
+		Ctime:    now,
+	}).Error
+}
+
+// transaction 执行事务，账号的版本号冲突了就等一会儿，整个事务重试
+func transaction(ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	var err error
+	for i := 0; i < maxConflictRetries; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(conflictBackoff * time.Duration(i)):
+			}
+		}
+		err = db.WithContext(ctx).Transaction(fn)
+		if !errors.Is(err, ErrVersionConflict) {
+			return err
+		}
+	}
+	return err
+}
+
+func isDuplicate(err error) bool {
+	if me, ok := err.(*mysql.MySQLError); ok {
+		const duplicateErr uint16 = 1062
+		return me.Number == duplicateErr
+	}
+	return false
+}
@@ -0,0 +1,281 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	mysqlDriver "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+)
+
+func TestAccountGORMDAO_Reverse(t *testing.T) {
+	lockSQL := regexp.QuoteMeta("SELECT * FROM `account_activities` WHERE biz = ? AND biz_id = ? ORDER BY id FOR UPDATE")
+	actCols := []string{"id", "biz", "biz_id", "account", "account_type", "amount", "currency"}
+	testCases := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+		fn   func(acts []AccountActivity) ([]AccountActivity, error)
+
+		wantActs []AccountActivity
+		wantErr  error
+	}{
+		{
+			name: "锁住原本的流水之后再冲正",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockSQL).WithArgs("reward", int64(1)).
+					WillReturnRows(sqlmock.NewRows(actCols).AddRow(1, "reward", 1, 123, 1, 90, "CNY"))
+				mock.ExpectQuery("SELECT \\* FROM `accounts` .*").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "balance", "version"}).AddRow(10, 90, 2))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `accounts` SET `balance`=?,`frozen`=?,`utime`=?,`version`=? "+
+					"WHERE id = ? AND version = ?")).
+					WithArgs(int64(45), int64(0), sqlmock.AnyArg(), int64(3), int64(10), int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO `account_activities` .*").
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectCommit()
+			},
+			fn: func(acts []AccountActivity) ([]AccountActivity, error) {
+				return []AccountActivity{{Biz: "reward", BizId: 1, Account: 123, AccountType: 1,
+					Amount: -45, Currency: "CNY", ReverseNo: "r1"}}, nil
+			},
+			wantActs: []AccountActivity{{Id: 1, Biz: "reward", BizId: 1, Account: 123, AccountType: 1,
+				Amount: 90, Currency: "CNY"}},
+		},
+		{
+			name: "已经冲正过了，什么都不做",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockSQL).
+					WillReturnRows(sqlmock.NewRows(actCols).AddRow(1, "reward", 1, 123, 1, 90, "CNY"))
+				mock.ExpectCommit()
+			},
+			fn: func(acts []AccountActivity) ([]AccountActivity, error) {
+				return nil, nil
+			},
+			wantActs: []AccountActivity{{Id: 1, Biz: "reward", BizId: 1, Account: 123, AccountType: 1,
+				Amount: 90, Currency: "CNY"}},
+		},
+		{
+			// 提现冻结了 60，冲正之后余额只有 45，打款之后就是负数了
+			name: "会动到冻结的钱",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockSQL).
+					WillReturnRows(sqlmock.NewRows(actCols).AddRow(1, "reward", 1, 123, 1, 90, "CNY"))
+				mock.ExpectQuery("SELECT \\* FROM `accounts` .*").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "balance", "frozen", "version"}).AddRow(10, 90, 60, 2))
+				mock.ExpectRollback()
+			},
+			fn: func(acts []AccountActivity) ([]AccountActivity, error) {
+				return []AccountActivity{{Biz: "reward", BizId: 1, Account: 123, AccountType: 1,
+					Amount: -45, Currency: "CNY", ReverseNo: "r1"}}, nil
+			},
+			wantActs: []AccountActivity{{Id: 1, Biz: "reward", BizId: 1, Account: 123, AccountType: 1,
+				Amount: 90, Currency: "CNY"}},
+			wantErr: ErrInsufficientBalance,
+		},
+		{
+			name: "超过了入账金额",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockSQL).WillReturnRows(sqlmock.NewRows(actCols))
+				mock.ExpectRollback()
+			},
+			fn: func(acts []AccountActivity) ([]AccountActivity, error) {
+				return nil, errors.New("冲正金额超过了入账金额")
+			},
+			wantActs: []AccountActivity{},
+			wantErr:  errors.New("冲正金额超过了入账金额"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			tc.mock(mock)
+			dao := NewCreditGORMDAO(openMockDB(t, sqlDB))
+			var got []AccountActivity
+			err = dao.Reverse(context.Background(), "reward", 1,
+				func(acts []AccountActivity) ([]AccountActivity, error) {
+					got = acts
+					return tc.fn(acts)
+				})
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantActs, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAccountGORMDAO_AddActivities(t *testing.T) {
+	selectSQL := regexp.QuoteMeta("SELECT * FROM `accounts` WHERE account = ? AND type = ? AND currency = ? " +
+		"ORDER BY `accounts`.`id` LIMIT ?")
+	updateSQL := regexp.QuoteMeta("UPDATE `accounts` SET `balance`=?,`frozen`=?,`utime`=?,`version`=? " +
+		"WHERE id = ? AND version = ?")
+	incrSQL := regexp.QuoteMeta("ON DUPLICATE KEY UPDATE `balance`=`balance`+?,`frozen`=`frozen`+?," +
+		"`utime`=?,`version`=`version`+1")
+	accCols := []string{"id", "uid", "account", "type", "currency", "balance", "version"}
+	usr := AccountActivity{Uid: 1024, Biz: "reward", BizId: 1, Account: 123, AccountType: 1,
+		Amount: 90, Currency: "CNY"}
+	sys := AccountActivity{Biz: "reward", BizId: 1, AccountType: 2, Amount: 10, Currency: "CNY"}
+	clearing := AccountActivity{Biz: "reward", BizId: 1, AccountType: 3, Amount: -100, Currency: "CNY"}
+	testCases := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+		acts []AccountActivity
+
+		wantErr error
+	}{
+		{
+			// 系统账号和清算账号不读，直接加，并发入账不会冲突
+			name: "入账",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectSQL).WithArgs(int64(123), uint8(1), "CNY", 1).
+					WillReturnRows(sqlmock.NewRows(accCols).AddRow(10, 1024, 123, 1, "CNY", 100, 2))
+				mock.ExpectExec(updateSQL).
+					WithArgs(int64(190), int64(0), sqlmock.AnyArg(), int64(3), int64(10), int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(incrSQL).WillReturnResult(sqlmock.NewResult(11, 1))
+				mock.ExpectExec(incrSQL).WillReturnResult(sqlmock.NewResult(12, 1))
+				mock.ExpectExec("INSERT INTO `account_activities` .*").
+					WillReturnResult(sqlmock.NewResult(1, 3))
+				mock.ExpectCommit()
+			},
+			acts: []AccountActivity{usr, sys, clearing},
+		},
+		{
+			name: "版本号冲突，重试成功",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectSQL).
+					WillReturnRows(sqlmock.NewRows(accCols).AddRow(10, 1024, 123, 1, "CNY", 100, 2))
+				mock.ExpectExec(updateSQL).
+					WithArgs(int64(190), int64(0), sqlmock.AnyArg(), int64(3), int64(10), int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+				// 重新读到别人改过之后的余额
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectSQL).
+					WillReturnRows(sqlmock.NewRows(accCols).AddRow(10, 1024, 123, 1, "CNY", 150, 3))
+				mock.ExpectExec(updateSQL).
+					WithArgs(int64(240), int64(0), sqlmock.AnyArg(), int64(4), int64(10), int64(3)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(incrSQL).WillReturnResult(sqlmock.NewResult(11, 1))
+				mock.ExpectExec("INSERT INTO `account_activities` .*").
+					WillReturnResult(sqlmock.NewResult(1, 2))
+				mock.ExpectCommit()
+			},
+			acts: []AccountActivity{usr, func() AccountActivity {
+				c := clearing
+				c.Amount = -90
+				return c
+			}()},
+		},
+		{
+			name: "一直冲突",
+			mock: func(mock sqlmock.Sqlmock) {
+				for i := 0; i < maxConflictRetries; i++ {
+					mock.ExpectBegin()
+					mock.ExpectQuery(selectSQL).
+						WillReturnRows(sqlmock.NewRows(accCols).AddRow(10, 1024, 123, 1, "CNY", 100, 2+i))
+					mock.ExpectExec(updateSQL).WillReturnResult(sqlmock.NewResult(0, 0))
+					mock.ExpectRollback()
+				}
+			},
+			acts:    []AccountActivity{usr},
+			wantErr: ErrVersionConflict,
+		},
+		{
+			// 两个人同时创建账号，后面的违反唯一索引，重试的时候就能读到了
+			name: "并发创建账号",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectSQL).WillReturnRows(sqlmock.NewRows(accCols))
+				mock.ExpectExec("INSERT INTO `accounts` .*").
+					WillReturnError(&mysqlDriver.MySQLError{Number: 1062})
+				mock.ExpectRollback()
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectSQL).
+					WillReturnRows(sqlmock.NewRows(accCols).AddRow(10, 1024, 123, 1, "CNY", 100, 1))
+				mock.ExpectExec(updateSQL).
+					WithArgs(int64(190), int64(0), sqlmock.AnyArg(), int64(2), int64(10), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO `account_activities` .*").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			acts: []AccountActivity{usr},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			tc.mock(mock)
+			dao := NewCreditGORMDAO(openMockDB(t, sqlDB))
+			err = dao.AddActivities(context.Background(), tc.acts...)
+			assert.Equal(t, tc.wantErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWithdrawalGORMDAO_Claim(t *testing.T) {
+	claimSQL := regexp.QuoteMeta("UPDATE `withdrawals` SET `status`=?,`utime`=GREATEST(?, utime + 1) " +
+		"WHERE id = ? AND status = ? AND utime = ?")
+	testCases := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+
+		wantErr error
+	}{
+		{
+			name: "认领成功",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(claimSQL).
+					WithArgs(uint8(2), sqlmock.AnyArg(), int64(1), uint8(1), int64(1000)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "别人先认领了",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(claimSQL).
+					WithArgs(uint8(2), sqlmock.AnyArg(), int64(1), uint8(1), int64(1000)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: ErrWithdrawalStatus,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			tc.mock(mock)
+			dao := NewWithdrawalGORMDAO(openMockDB(t, sqlDB))
+			err = dao.Claim(context.Background(), 1, 1, 1000)
+			assert.Equal(t, tc.wantErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func openMockDB(t *testing.T, sqlDB *sql.DB) *gorm.DB {
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+	return db
+}
@@ -7,7 +7,18 @@ import (
 )
 
 func InitTables(db *gorm.DB) error {
-	err := db.AutoMigrate(&Account{}, &AccountActivity{})
+	err := db.AutoMigrate(&Account{}, &AccountActivity{}, &Withdrawal{})
+	if err != nil {
+		return err
+	}
+	// AutoMigrate 只会创建新的索引，旧的唯一索引要自己删掉，
+	// 不然冲正的流水会和原本入账的流水冲突
+	err = dropIndexes(db, &AccountActivity{}, "biz_type_id")
+	if err != nil {
+		return err
+	}
+	// 账号的唯一索引加上了币种，旧的 account_type 不删的话，同一个账号还是只能有一个币种
+	err = dropIndexes(db, &Account{}, "account_type")
 	if err != nil {
 		return err
 	}
@@ -18,8 +29,30 @@ func InitTables(db *gorm.DB) error {
 	_ = db.Create(&Account{
 		Type:     domain.AccountTypeSystem,
 		Currency: "CNY",
+		Version:  1,
 		Ctime:    now,
 		Utime:    now,
 	}).Error
+	_ = db.Create(&Account{
+		Type:     domain.AccountTypeClearing,
+		Currency: "CNY",
+		Version:  1,
+		Ctime:    now,
+		Utime:    now,
+	}).Error
+	return nil
+}
+
+func dropIndexes(db *gorm.DB, model any, names ...string) error {
+	m := db.Migrator()
+	for _, name := range names {
+		if !m.HasIndex(model, name) {
+			continue
+		}
+		err := m.DropIndex(model, name)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -1,9 +1,63 @@
 package dao
 
-import "context"
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDuplicateActivity   = errors.New("重复记账")
+	ErrInsufficientBalance = errors.New("账号可用余额不足")
+	ErrDuplicateWithdrawal = errors.New("重复的提现申请")
+	ErrWithdrawalStatus    = errors.New("提现状态已经变了")
+	ErrWithdrawalNotFound  = gorm.ErrRecordNotFound
+	// ErrVersionConflict 账号被并发修改了，重试几次之后还是冲突才会返回
+	ErrVersionConflict = errors.New("账号被并发修改了")
+	ErrAccountNotFound = gorm.ErrRecordNotFound
+)
 
 type AccountDAO interface {
+	// AddActivities 违反唯一索引的时候返回 ErrDuplicateActivity
 	AddActivities(ctx context.Context, activities ...AccountActivity) error
+	// FindActivities 某个业务的所有流水，包括冲正的
+	FindActivities(ctx context.Context, biz string, bizId int64) ([]AccountActivity, error)
+	// Reverse 锁住某个业务的所有流水，交给 fn 算出冲正的流水再记账，
+	// 所以同一个业务的冲正是串行的。fn 没有返回流水就什么都不做。
+	// 账号里面有冻结的钱，并且扣完之后余额不够冻结的钱，返回 ErrInsufficientBalance
+	Reverse(ctx context.Context, biz string, bizId int64,
+		fn func(acts []AccountActivity) ([]AccountActivity, error)) error
+	// Debit 出账，activities 里面的金额都是负数。
+	// 任何一个账号可用余额不够都返回 ErrInsufficientBalance，重复出账返回 ErrDuplicateActivity
+	Debit(ctx context.Context, activities ...AccountActivity) error
+
+	GetAccount(ctx context.Context, account int64, typ uint8, currency string) (Account, error)
+	// FindAccountActivities 某个账号的流水，新的在前面
+	FindAccountActivities(ctx context.Context, account int64, typ uint8, currency string,
+		offset, limit int) ([]AccountActivity, error)
+	// FindActivitiesBetween 某个账号在 [start, end) 的流水，旧的在前面
+	FindActivitiesBetween(ctx context.Context, account int64, typ uint8, currency string,
+		start, end int64) ([]AccountActivity, error)
+	// SumBefore 某个账号在 t 之前所有流水的和，也就是 t 时刻的余额
+	SumBefore(ctx context.Context, account int64, typ uint8, currency string, t int64) (int64, error)
+}
+
+type WithdrawalDAO interface {
+	// Insert 冻结账号里面的钱，同时创建提现记录。
+	// 可用余额不够返回 ErrInsufficientBalance，WithdrawNO 重复返回 ErrDuplicateWithdrawal
+	Insert(ctx context.Context, w Withdrawal) (int64, error)
+	GetById(ctx context.Context, id int64) (Withdrawal, error)
+	GetByWithdrawNO(ctx context.Context, withdrawNO string) (Withdrawal, error)
+	FindByUid(ctx context.Context, uid int64, offset, limit int) ([]Withdrawal, error)
+	// Claim 把提现改成审核通过，并且更新 utime。
+	// 只有状态和 utime 都没变的时候才会成功，否则返回 ErrWithdrawalStatus，
+	// 这样同一个提现同一时刻只有一个人去打款
+	Claim(ctx context.Context, id int64, status uint8, utime int64) error
+	// Complete 打款成功，扣掉冻结的钱，同时记账。
+	// acts 是提现账号的出账流水和清算账号的对手方流水
+	Complete(ctx context.Context, w Withdrawal, txnID string, acts ...AccountActivity) error
+	// Fail 提现失败，冻结的钱解冻。w.Status 是失败之前的状态
+	Fail(ctx context.Context, w Withdrawal, reason string) error
 }
 
 // Account 账号本体
@@ -14,12 +68,17 @@ type Account struct {
 	// 我账号是哪个用户的账号
 	Uid int64
 
-	// 唯一标识一个账号
-	Account int64 `gorm:"uniqueIndex:account_type"`
-	Type    uint8 `gorm:"uniqueIndex:account_type"`
+	// 唯一标识一个账号，同一个用户可以有多个币种的账号
+	// 以前的 account_type 索引在 InitTables 里面删掉
+	Account  int64  `gorm:"uniqueIndex:account_type_currency"`
+	Type     uint8  `gorm:"uniqueIndex:account_type_currency"`
+	Currency string `gorm:"type:varchar(8);uniqueIndex:account_type_currency"`
 
-	Balance  int64
-	Currency string
+	Balance int64
+	// Frozen 申请了提现还没有打款的钱，可用余额是 Balance - Frozen
+	Frozen int64
+	// Version 乐观锁，每次修改余额都要加一
+	Version int64
 
 	Utime int64
 	Ctime int64
@@ -31,17 +90,27 @@ type AccountActivity struct {
 	Id  int64 `gorm:"primaryKey,autoIncrement"`
 	Uid int64
 
-	// 在 biz, biz_id, account 和 account_id 上创建一个联合唯一索引
-	// 这样可以确保记账的时候不会重复记账
-	Biz   string `gorm:"uniqueIndex:biz_type_id"`
-	BizId int64  `gorm:"uniqueIndex:biz_type_id"`
+	// 在 biz, biz_id, account, account_id 和 reverse_no 上创建一个联合唯一索引
+	// 这样可以确保记账和冲正的时候不会重复记账。
+	// 以前的 biz_type_id 索引在 InitTables 里面删掉
+	Biz   string `gorm:"uniqueIndex:biz_type_id_reverse"`
+	BizId int64  `gorm:"uniqueIndex:biz_type_id_reverse"`
 
-	Account     int64 `gorm:"index:account_type;uniqueIndex:biz_type_id"`
-	AccountType uint8 `gorm:"index:account_type;uniqueIndex:biz_type_id"`
+	Account     int64 `gorm:"index:account_type;uniqueIndex:biz_type_id_reverse"`
+	AccountType uint8 `gorm:"index:account_type;uniqueIndex:biz_type_id_reverse"`
 
 	// TYPE 入账还是出账
 	Amount   int64
 	Currency string
+	// OrigAmount 和 OrigCurrency 是换汇之前的，Rate 是用的汇率。
+	// 以前的数据 OrigCurrency 是空字符串，代表没有换汇
+	OrigAmount   int64
+	OrigCurrency string `gorm:"type:varchar(8)"`
+	Rate         string `gorm:"type:varchar(32)"`
+	FeeRuleId    int64
+
+	// ReverseNo 冲正的流水才有，正常入账的是空字符串
+	ReverseNo string `gorm:"type:varchar(128);uniqueIndex:biz_type_id_reverse"`
 
 	Utime int64
 	Ctime int64
@@ -50,3 +119,18 @@ type AccountActivity struct {
 func (AccountActivity) TableName() string {
 	return "account_activities"
 }
+
+type Withdrawal struct {
+	Id          int64  `gorm:"primaryKey,autoIncrement"`
+	WithdrawNO  string `gorm:"type:varchar(128);unique"`
+	Uid         int64  `gorm:"index"`
+	Account     int64
+	AccountType uint8
+	Amt         int64
+	Currency    string
+	Status      uint8
+	TxnID       string `gorm:"type:varchar(128)"`
+	Reason      string `gorm:"type:varchar(256)"`
+	Utime       int64
+	Ctime       int64
+}
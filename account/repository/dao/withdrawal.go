@@ -0,0 +1,146 @@
+package dao
+
+import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/account/domain"
+	"gorm.io/gorm"
+	"time"
+)
+
+type WithdrawalGORMDAO struct {
+	db *gorm.DB
+}
+
+func NewWithdrawalGORMDAO(db *gorm.DB) WithdrawalDAO {
+	return &WithdrawalGORMDAO{db: db}
+}
+
+func (d *WithdrawalGORMDAO) Insert(ctx context.Context, w Withdrawal) (int64, error) {
+	now := time.Now().UnixMilli()
+	w.Ctime = now
+	w.Utime = now
+	err := transaction(ctx, d.db, func(tx *gorm.DB) error {
+		// 同时校验了账号是不是这个用户的
+		err := changeAccount(tx, accountChange{
+			Uid:      w.Uid,
+			Account:  w.Account,
+			Type:     w.AccountType,
+			Currency: w.Currency,
+			Frozen:   w.Amt,
+			Check:    true,
+		}, now)
+		if err != nil {
+			return err
+		}
+		err = tx.Create(&w).Error
+		if isDuplicate(err) {
+			return ErrDuplicateWithdrawal
+		}
+		return err
+	})
+	return w.Id, err
+}
+
+func (d *WithdrawalGORMDAO) GetById(ctx context.Context, id int64) (Withdrawal, error) {
+	var res Withdrawal
+	err := d.db.WithContext(ctx).Where("id = ?", id).First(&res).Error
+	return res, err
+}
+
+func (d *WithdrawalGORMDAO) GetByWithdrawNO(ctx context.Context, withdrawNO string) (Withdrawal, error) {
+	var res Withdrawal
+	err := d.db.WithContext(ctx).Where("withdraw_no = ?", withdrawNO).First(&res).Error
+	return res, err
+}
+
+func (d *WithdrawalGORMDAO) FindByUid(ctx context.Context, uid int64, offset, limit int) ([]Withdrawal, error) {
+	var res []Withdrawal
+	err := d.db.WithContext(ctx).Where("uid = ?", uid).
+		Order("id DESC").Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (d *WithdrawalGORMDAO) Claim(ctx context.Context, id int64, status uint8, utime int64) error {
+	res := d.db.WithContext(ctx).Model(&Withdrawal{}).
+		Where("id = ? AND status = ? AND utime = ?", id, status, utime).
+		Updates(map[string]any{
+			"status": domain.WithdrawalStatusApproved.AsUint8(),
+			// 同一毫秒里面再认领也要让 utime 变掉
+			"utime": gorm.Expr("GREATEST(?, utime + 1)", time.Now().UnixMilli()),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrWithdrawalStatus
+	}
+	return nil
+}
+
+func (d *WithdrawalGORMDAO) Complete(ctx context.Context, w Withdrawal, txnID string, acts ...AccountActivity) error {
+	return transaction(ctx, d.db, func(tx *gorm.DB) error {
+		now := time.Now().UnixMilli()
+		err := d.updateStatus(tx, w.Id, domain.WithdrawalStatusApproved.AsUint8(), map[string]any{
+			"status": domain.WithdrawalStatusPaid.AsUint8(),
+			"txn_id": txnID,
+			"utime":  now,
+		})
+		if err != nil {
+			return err
+		}
+		// 冻结的钱扣掉，对手方的账号正常记账
+		for _, act := range acts {
+			c := accountChange{
+				Uid:      act.Uid,
+				Account:  act.Account,
+				Type:     act.AccountType,
+				Currency: act.Currency,
+				Balance:  act.Amount,
+			}
+			if act.Account == w.Account && act.AccountType == w.AccountType && act.Currency == w.Currency {
+				c.Frozen = -w.Amt
+			}
+			err = changeAccount(tx, c, now)
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Create(&acts).Error
+	})
+}
+
+func (d *WithdrawalGORMDAO) Fail(ctx context.Context, w Withdrawal, reason string) error {
+	return transaction(ctx, d.db, func(tx *gorm.DB) error {
+		now := time.Now().UnixMilli()
+		err := d.updateStatus(tx, w.Id, w.Status, map[string]any{
+			"status": domain.WithdrawalStatusFailed.AsUint8(),
+			"reason": reason,
+			"utime":  now,
+		})
+		if err != nil {
+			return err
+		}
+		return changeAccount(tx, accountChange{
+			Uid:      w.Uid,
+			Account:  w.Account,
+			Type:     w.AccountType,
+			Currency: w.Currency,
+			Frozen:   -w.Amt,
+		}, now)
+	})
+}
+
+// updateStatus 用状态做乐观锁，保证每一次状态变化只有一个人能成功
+func (d *WithdrawalGORMDAO) updateStatus(db *gorm.DB, id int64, from uint8, updates map[string]any) error {
+	res := db.Model(&Withdrawal{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrWithdrawalStatus
+	}
+	return nil
+}
@@ -3,11 +3,57 @@ package repository
 import (
 	"context"
 	"gitee.com/geekbang/basic-go/webook/account/domain"
+	"gitee.com/geekbang/basic-go/webook/account/repository/dao"
+	"time"
+)
+
+var (
+	ErrDuplicateReverse    = dao.ErrDuplicateActivity
+	ErrDuplicateDebit      = dao.ErrDuplicateActivity
+	ErrInsufficientBalance = dao.ErrInsufficientBalance
+	ErrDuplicateWithdrawal = dao.ErrDuplicateWithdrawal
+	ErrWithdrawalStatus    = dao.ErrWithdrawalStatus
+	ErrWithdrawalNotFound  = dao.ErrWithdrawalNotFound
+	ErrAccountNotFound     = dao.ErrAccountNotFound
 )
 
 type AccountRepository interface {
-	AddCredit(ctx context.Context, c domain.Credit) error
+	// AddCredit acts 是已经换好汇的流水
+	AddCredit(ctx context.Context, biz string, bizId int64, acts []domain.Activity) error
 	// CheckUnique 如果返回了 error 就说明重复记账了
 	CheckUnique(ctx context.Context, c domain.Credit) error
 	SetUnique(ctx context.Context, c domain.Credit) error
+	// AddReverse 把这个业务现在所有的流水交给 fn，fn 返回每个账号要扣回来的钱，都是负数。
+	// 同一个业务的冲正是串行的，fn 看到的流水里面有之前所有的冲正。
+	// fn 没有返回流水就什么都不做。会动到冻结的钱的时候返回 ErrInsufficientBalance
+	AddReverse(ctx context.Context, r domain.Reverse,
+		fn func(acts []domain.Activity) ([]domain.Activity, error)) error
+	FindActivities(ctx context.Context, biz string, bizId int64) ([]domain.Activity, error)
+	// AddDebit 可用余额不够返回 ErrInsufficientBalance，重复出账返回 ErrDuplicateDebit
+	AddDebit(ctx context.Context, d domain.Debit) error
+
+	GetBalance(ctx context.Context, account int64, typ domain.AccountType, currency string) (domain.Balance, error)
+	// FindAccountActivities 新的在前面
+	FindAccountActivities(ctx context.Context, account int64, typ domain.AccountType, currency string,
+		offset, limit int) ([]domain.Activity, error)
+	// FindActivitiesBetween [start, end) 的流水，旧的在前面
+	FindActivitiesBetween(ctx context.Context, account int64, typ domain.AccountType, currency string,
+		start, end time.Time) ([]domain.Activity, error)
+	// BalanceAt t 时刻的余额，用流水算出来的
+	BalanceAt(ctx context.Context, account int64, typ domain.AccountType, currency string, t time.Time) (int64, error)
+}
+
+type WithdrawalRepository interface {
+	// CreateWithdrawal 冻结账号里面的钱并且创建提现记录
+	CreateWithdrawal(ctx context.Context, w domain.Withdrawal) (int64, error)
+	GetWithdrawal(ctx context.Context, id int64) (domain.Withdrawal, error)
+	GetWithdrawalByNO(ctx context.Context, withdrawNO string) (domain.Withdrawal, error)
+	FindWithdrawals(ctx context.Context, uid int64, offset, limit int) ([]domain.Withdrawal, error)
+	// ClaimWithdrawal 认领这一次打款，w 是刚刚查出来的。
+	// 别人已经认领了或者状态变了，返回 ErrWithdrawalStatus
+	ClaimWithdrawal(ctx context.Context, w domain.Withdrawal) error
+	// CompleteWithdrawal 打款成功，扣钱并且记账
+	CompleteWithdrawal(ctx context.Context, w domain.Withdrawal, txnID string) error
+	// FailWithdrawal 提现失败，解冻。w.Status 是失败之前的状态
+	FailWithdrawal(ctx context.Context, w domain.Withdrawal, reason string) error
 }
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/account/domain"
+	"gitee.com/geekbang/basic-go/webook/account/repository/dao"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+)
+
+// withdrawBiz 提现出账的流水里面的 biz，biz_id 是提现记录的 id
+const withdrawBiz = "withdraw"
+
+type withdrawalRepository struct {
+	dao dao.WithdrawalDAO
+}
+
+func NewWithdrawalRepository(dao dao.WithdrawalDAO) WithdrawalRepository {
+	return &withdrawalRepository{dao: dao}
+}
+
+func (w *withdrawalRepository) CreateWithdrawal(ctx context.Context, wd domain.Withdrawal) (int64, error) {
+	return w.dao.Insert(ctx, w.toEntity(wd))
+}
+
+func (w *withdrawalRepository) GetWithdrawal(ctx context.Context, id int64) (domain.Withdrawal, error) {
+	res, err := w.dao.GetById(ctx, id)
+	return w.toDomain(res), err
+}
+
+func (w *withdrawalRepository) GetWithdrawalByNO(ctx context.Context, withdrawNO string) (domain.Withdrawal, error) {
+	res, err := w.dao.GetByWithdrawNO(ctx, withdrawNO)
+	return w.toDomain(res), err
+}
+
+func (w *withdrawalRepository) FindWithdrawals(ctx context.Context, uid int64, offset, limit int) ([]domain.Withdrawal, error) {
+	res, err := w.dao.FindByUid(ctx, uid, offset, limit)
+	return slice.Map(res, func(idx int, src dao.Withdrawal) domain.Withdrawal {
+		return w.toDomain(src)
+	}), err
+}
+
+func (w *withdrawalRepository) ClaimWithdrawal(ctx context.Context, wd domain.Withdrawal) error {
+	return w.dao.Claim(ctx, wd.Id, wd.Status.AsUint8(), wd.Utime.UnixMilli())
+}
+
+func (w *withdrawalRepository) CompleteWithdrawal(ctx context.Context, wd domain.Withdrawal, txnID string) error {
+	now := time.Now().UnixMilli()
+	return w.dao.Complete(ctx, w.toEntity(wd), txnID, dao.AccountActivity{
+		Uid:          wd.Uid,
+		Biz:          withdrawBiz,
+		BizId:        wd.Id,
+		Account:      wd.Account,
+		AccountType:  wd.AccountType.AsUint8(),
+		Amount:       -wd.Amt,
+		Currency:     wd.Currency,
+		OrigAmount:   -wd.Amt,
+		OrigCurrency: wd.Currency,
+		Rate:         "1",
+		Ctime:        now,
+		Utime:        now,
+	}, dao.AccountActivity{
+		// 钱打到外面去了
+		Biz:          withdrawBiz,
+		BizId:        wd.Id,
+		AccountType:  domain.AccountTypeClearing,
+		Amount:       wd.Amt,
+		Currency:     wd.Currency,
+		OrigAmount:   wd.Amt,
+		OrigCurrency: wd.Currency,
+		Rate:         "1",
+		Ctime:        now,
+		Utime:        now,
+	})
+}
+
+func (w *withdrawalRepository) FailWithdrawal(ctx context.Context, wd domain.Withdrawal, reason string) error {
+	return w.dao.Fail(ctx, w.toEntity(wd), reason)
+}
+
+func (w *withdrawalRepository) toEntity(wd domain.Withdrawal) dao.Withdrawal {
+	return dao.Withdrawal{
+		Id:          wd.Id,
+		WithdrawNO:  wd.WithdrawNO,
+		Uid:         wd.Uid,
+		Account:     wd.Account,
+		AccountType: wd.AccountType.AsUint8(),
+		Amt:         wd.Amt,
+		Currency:    wd.Currency,
+		Status:      wd.Status.AsUint8(),
+		TxnID:       wd.TxnID,
+		Reason:      wd.Reason,
+	}
+}
+
+func (w *withdrawalRepository) toDomain(wd dao.Withdrawal) domain.Withdrawal {
+	return domain.Withdrawal{
+		Id:          wd.Id,
+		WithdrawNO:  wd.WithdrawNO,
+		Uid:         wd.Uid,
+		Account:     wd.Account,
+		AccountType: domain.AccountType(wd.AccountType),
+		Amt:         wd.Amt,
+		Currency:    wd.Currency,
+		Status:      domain.WithdrawalStatus(wd.Status),
+		TxnID:       wd.TxnID,
+		Reason:      wd.Reason,
+		Ctime:       time.UnixMilli(wd.Ctime),
+		Utime:       time.UnixMilli(wd.Utime),
+	}
+}
@@ -2,25 +2,49 @@ package service
 
 import (
 	"context"
+	"errors"
 	"gitee.com/geekbang/basic-go/webook/account/domain"
 	"gitee.com/geekbang/basic-go/webook/account/repository"
+	"time"
+)
+
+var (
+	ErrCreditNotFound   = errors.New("没有找到入账记录")
+	ErrReverseExceeded  = errors.New("冲正金额超过了入账金额")
+	ErrInvalidAmount    = errors.New("金额必须大于 0")
+	ErrUnbalancedCredit = errors.New("入账不平，换汇前后每个币种加起来都必须是 0")
+	ErrInvalidMonth     = errors.New("月份格式不对，应该是 200601 这种")
+
+	ErrInsufficientBalance = repository.ErrInsufficientBalance
+	ErrAccountNotFound     = repository.ErrAccountNotFound
 )
 
 type accountService struct {
-	repo repository.AccountRepository
+	repo  repository.AccountRepository
+	rates RateProvider
 }
 
-func NewAccountService(repo repository.AccountRepository) AccountService {
-	return &accountService{repo: repo}
+func NewAccountService(repo repository.AccountRepository, rates RateProvider) AccountService {
+	return &accountService{repo: repo, rates: rates}
 }
 
 func (a *accountService) Credit(ctx context.Context, cr domain.Credit) error {
+	if !balanced(cr.Items) {
+		return ErrUnbalancedCredit
+	}
 	err := a.repo.CheckUnique(ctx, cr)
 	if err != nil {
 		return err
 	}
+	acts, err := a.settle(ctx, cr.Items)
+	if err != nil {
+		return err
+	}
+	for i := range acts {
+		acts[i].FeeRuleId = cr.FeeRuleId
+	}
 	// 我这里是有唯一索引的
-	err = a.repo.AddCredit(ctx, cr)
+	err = a.repo.AddCredit(ctx, cr.Biz, cr.BizId, acts)
 	if err == nil {
 		// 注意这些部分失败是没有什么问题的
 		// 因为我们始终有一个兜底，就是唯一索引。
@@ -31,3 +55,329 @@ func (a *accountService) Credit(ctx context.Context, cr domain.Credit) error {
 	}
 	return err
 }
+
+func (a *accountService) Reverse(ctx context.Context, r domain.Reverse) error {
+	err := a.repo.AddReverse(ctx, r, func(acts []domain.Activity) ([]domain.Activity, error) {
+		for _, act := range acts {
+			if act.ReverseNo == r.ReverseNo && r.ReverseNo != "" {
+				// 已经冲正过了
+				return nil, nil
+			}
+		}
+		return reverseItems(acts, r.Amt)
+	})
+	if errors.Is(err, repository.ErrDuplicateReverse) {
+		// 唯一索引兜底
+		return nil
+	}
+	return err
+}
+
+func (a *accountService) Debit(ctx context.Context, d domain.Debit) error {
+	for _, itm := range d.Items {
+		if itm.Amt <= 0 {
+			return ErrInvalidAmount
+		}
+	}
+	err := a.repo.AddDebit(ctx, d)
+	if errors.Is(err, repository.ErrDuplicateDebit) {
+		// 已经扣过了
+		return nil
+	}
+	return err
+}
+
+// settle 把每一条入账换成入账账号的币种，汇率在这一刻定下来，记在流水上
+func (a *accountService) settle(ctx context.Context, items []domain.CreditItem) ([]domain.Activity, error) {
+	rates := make(map[string]domain.Rate, 1)
+	res := make([]domain.Activity, 0, len(items))
+	for _, itm := range items {
+		act := domain.Activity{
+			CreditItem:   itm,
+			OrigAmt:      itm.Amt,
+			OrigCurrency: itm.Currency,
+			Rate:         "1",
+		}
+		if itm.SettleCurrency != "" && itm.SettleCurrency != itm.Currency {
+			// 同一次入账用同一个汇率
+			pair := itm.Currency + "/" + itm.SettleCurrency
+			rate, ok := rates[pair]
+			if !ok {
+				var err error
+				rate, err = a.rates.Rate(ctx, itm.Currency, itm.SettleCurrency)
+				if err != nil {
+					return nil, err
+				}
+				rates[pair] = rate
+			}
+			amt, err := rate.Convert(itm.Amt)
+			if err != nil {
+				return nil, err
+			}
+			act.Amt = amt
+			act.Currency = itm.SettleCurrency
+			act.Rate = rate.Value
+		}
+		res = append(res, act)
+	}
+	// 同一个币种入账，有的换汇有的不换，换完就不平了
+	if !fixRounding(res) {
+		return nil, ErrUnbalancedCredit
+	}
+	return res, nil
+}
+
+func (a *accountService) GetBalance(ctx context.Context, account int64, typ domain.AccountType,
+	currency string) (domain.Balance, error) {
+	return a.repo.GetBalance(ctx, account, typ, currency)
+}
+
+func (a *accountService) ListActivities(ctx context.Context, account int64, typ domain.AccountType,
+	currency string, offset, limit int) ([]domain.Activity, error) {
+	return a.repo.FindAccountActivities(ctx, account, typ, currency, offset, limit)
+}
+
+func (a *accountService) GetStatement(ctx context.Context, account int64, typ domain.AccountType,
+	currency string, month string) (domain.Statement, error) {
+	start, err := time.ParseInLocation(domain.StatementMonthLayout, month, time.Local)
+	if err != nil {
+		return domain.Statement{}, ErrInvalidMonth
+	}
+	end := start.AddDate(0, 1, 0)
+	b, err := a.repo.GetBalance(ctx, account, typ, currency)
+	if err != nil {
+		return domain.Statement{}, err
+	}
+	opening, err := a.repo.BalanceAt(ctx, account, typ, currency, start)
+	if err != nil {
+		return domain.Statement{}, err
+	}
+	acts, err := a.repo.FindActivitiesBetween(ctx, account, typ, currency, start, end)
+	if err != nil {
+		return domain.Statement{}, err
+	}
+	res := domain.Statement{
+		Account:        account,
+		AccountType:    typ,
+		Month:          start,
+		Currency:       b.Currency,
+		OpeningBalance: opening,
+		ClosingBalance: opening,
+		Activities:     acts,
+	}
+	for _, act := range acts {
+		res.ClosingBalance += act.Amt
+		if act.Amt > 0 {
+			res.TotalIn += act.Amt
+		} else {
+			res.TotalOut -= act.Amt
+		}
+	}
+	return res, nil
+}
+
+// balanced 每个币种加起来都是 0
+func balanced(items []domain.CreditItem) bool {
+	if len(items) == 0 {
+		return false
+	}
+	sums := make(map[string]int64, 1)
+	for _, itm := range items {
+		sums[itm.Currency] += itm.Amt
+	}
+	for _, sum := range sums {
+		if sum != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// fixRounding 换汇是每一条分别四舍五入的，加起来可能差了一点。
+// 差的部分算在同一个币种里面金额最大的那一条上，保证每个币种还是平的。
+// 差得比四舍五入能差出来的还多，就说明本来就不平，返回 false。
+// 没有换汇的币种不处理
+func fixRounding(acts []domain.Activity) bool {
+	sums := make(map[string]int64, 1)
+	counts := make(map[string]int64, 1)
+	converted := make(map[string]bool, 1)
+	largest := make(map[string]int, 1)
+	for i, act := range acts {
+		sums[act.Currency] += act.Amt
+		counts[act.Currency]++
+		if act.Currency != act.OrigCurrency {
+			converted[act.Currency] = true
+		}
+		if j, ok := largest[act.Currency]; !ok || abs(act.Amt) > abs(acts[j].Amt) {
+			largest[act.Currency] = i
+		}
+	}
+	for currency := range converted {
+		if abs(sums[currency]) > counts[currency] {
+			return false
+		}
+	}
+	for currency := range converted {
+		acts[largest[currency]].Amt -= sums[currency]
+	}
+	return true
+}
+
+// reverseItems 按照原本入账的比例分摊 amt，除不尽的部分给剩余金额最多的账号。
+// amt 是换汇之前的金额，分摊也是按照换汇之前的金额算的，
+// 每个账号扣回来的钱按照当时入账的汇率折算，不会用现在的汇率。
+// 收钱的账号和对手方（金额是负数的，一般是清算账号）分开分摊，两边都是 amt，
+// 所以冲正的流水也是平的。以前没有对手方的入账，就只冲正收钱的账号。
+// 最后一次冲正会把每个账号剩下的钱全部扣掉，保证全部冲正之后每个账号都刚好扣完
+func reverseItems(acts []domain.Activity, amt int64) ([]domain.Activity, error) {
+	type key struct {
+		account     int64
+		accountType domain.AccountType
+		currency    string
+	}
+	// reversal 每个账号已经冲正了多少，符号和冲正的流水一样
+	type reversal struct {
+		orig int64
+		amt  int64
+	}
+	var total, reversedTotal int64
+	credits := make([]domain.Activity, 0, len(acts))
+	reversed := make(map[key]reversal, len(acts))
+	for _, act := range acts {
+		if act.ReverseNo == "" {
+			credits = append(credits, act)
+			if act.OrigAmt > 0 {
+				total += act.OrigAmt
+			}
+			continue
+		}
+		k := key{act.Account, act.AccountType, act.Currency}
+		r := reversed[k]
+		r.orig += act.OrigAmt
+		r.amt += act.Amt
+		reversed[k] = r
+		if act.OrigAmt < 0 {
+			reversedTotal -= act.OrigAmt
+		}
+	}
+	if len(credits) == 0 || total <= 0 {
+		return nil, ErrCreditNotFound
+	}
+	if amt <= 0 || reversedTotal+amt > total {
+		return nil, ErrReverseExceeded
+	}
+	final := reversedTotal+amt == total
+	var pos, neg []int
+	for i, c := range credits {
+		if c.OrigAmt > 0 {
+			pos = append(pos, i)
+		} else if c.OrigAmt < 0 {
+			neg = append(neg, i)
+		}
+	}
+	deltas := make([]int64, len(credits))
+	for _, idxs := range [][]int{pos, neg} {
+		if len(idxs) == 0 {
+			continue
+		}
+		amts := make([]int64, len(idxs))
+		remaining := make([]int64, len(idxs))
+		for j, i := range idxs {
+			c := credits[i]
+			amts[j] = abs(c.OrigAmt)
+			remaining[j] = abs(c.OrigAmt + reversed[key{c.Account, c.AccountType, c.Currency}].orig)
+		}
+		d, err := allocate(amts, remaining, amt, final)
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range idxs {
+			deltas[i] = d[j]
+		}
+	}
+	res := make([]domain.Activity, 0, len(credits))
+	for i, c := range credits {
+		if deltas[i] == 0 {
+			continue
+		}
+		r := reversed[key{c.Account, c.AccountType, c.Currency}]
+		origLeft := abs(c.OrigAmt + r.orig)
+		left := abs(c.Amt + r.amt)
+		// 这个账号扣完了就把剩下的全部扣掉，不然按照入账时候的汇率折算
+		settled := left
+		if deltas[i] < origLeft {
+			settled = deltas[i] * abs(c.Amt) / abs(c.OrigAmt)
+			if settled > left {
+				settled = left
+			}
+		}
+		sign := int64(1)
+		if c.OrigAmt > 0 {
+			sign = -1
+		}
+		res = append(res, domain.Activity{
+			CreditItem: domain.CreditItem{
+				Uid:         c.Uid,
+				Account:     c.Account,
+				AccountType: c.AccountType,
+				Amt:         sign * settled,
+				Currency:    c.Currency,
+			},
+			OrigAmt:      sign * deltas[i],
+			OrigCurrency: c.OrigCurrency,
+			Rate:         c.Rate,
+			FeeRuleId:    c.FeeRuleId,
+		})
+	}
+	// 原本的入账是平的，按照同样的汇率折算，最多差一点四舍五入
+	fixRounding(res)
+	return res, nil
+}
+
+// allocate 按照 amts 的比例分摊 amt，每一份不超过 remaining。
+// final 为 true 的时候直接把 remaining 全部分掉
+func allocate(amts, remaining []int64, amt int64, final bool) ([]int64, error) {
+	var total int64
+	for _, a := range amts {
+		total += a
+	}
+	deltas := make([]int64, len(amts))
+	var allocated int64
+	for i := range amts {
+		if final {
+			deltas[i] = remaining[i]
+		} else {
+			deltas[i] = amt * amts[i] / total
+			if deltas[i] > remaining[i] {
+				deltas[i] = remaining[i]
+			}
+		}
+		allocated += deltas[i]
+	}
+	for allocated < amt {
+		largest := -1
+		for i := range amts {
+			if remaining[i]-deltas[i] > 0 &&
+				(largest < 0 || remaining[i]-deltas[i] > remaining[largest]-deltas[largest]) {
+				largest = i
+			}
+		}
+		if largest < 0 {
+			return nil, ErrReverseExceeded
+		}
+		d := remaining[largest] - deltas[largest]
+		if d > amt-allocated {
+			d = amt - allocated
+		}
+		deltas[largest] += d
+		allocated += d
+	}
+	return deltas, nil
+}
+
+func abs(a int64) int64 {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
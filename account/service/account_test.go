@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/account/domain"
+	"gitee.com/geekbang/basic-go/webook/account/repository"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_accountService_Reverse(t *testing.T) {
+	usr := domain.CreditItem{Uid: 1024, Account: 123,
+		AccountType: domain.AccountTypeReward, Amt: 90, Currency: "CNY"}
+	sys := domain.CreditItem{AccountType: domain.AccountTypeSystem, Amt: 10, Currency: "CNY"}
+	act := func(c domain.CreditItem, amt int64, reverseNo string) domain.Activity {
+		c.Amt = amt
+		return domain.Activity{CreditItem: c, OrigAmt: amt, OrigCurrency: c.Currency,
+			Rate: "1", ReverseNo: reverseNo}
+	}
+	credits := []domain.Activity{act(usr, 90, ""), act(sys, 10, "")}
+	testCases := []struct {
+		name string
+		repo *fakeReverseRepo
+		r    domain.Reverse
+
+		wantActs []domain.Activity
+		wantErr  error
+	}{
+		{
+			name:     "冲正一部分",
+			repo:     &fakeReverseRepo{acts: credits},
+			r:        domain.Reverse{Biz: "reward", BizId: 1, ReverseNo: "r1", Amt: 50},
+			wantActs: []domain.Activity{act(usr, -45, ""), act(sys, -5, "")},
+		},
+		{
+			name: "同一个 ReverseNo 已经冲正过了",
+			repo: &fakeReverseRepo{acts: append(append([]domain.Activity{}, credits...),
+				act(usr, -45, "r1"), act(sys, -5, "r1"))},
+			r: domain.Reverse{Biz: "reward", BizId: 1, ReverseNo: "r1", Amt: 50},
+		},
+		{
+			// 前一个冲正提交了之后才能看到流水，所以加起来不会超过原本入账的金额
+			name: "并发冲正，前面的已经冲正了一部分",
+			repo: &fakeReverseRepo{acts: append(append([]domain.Activity{}, credits...),
+				act(usr, -45, "r1"), act(sys, -5, "r1"))},
+			r:       domain.Reverse{Biz: "reward", BizId: 1, ReverseNo: "r2", Amt: 60},
+			wantErr: ErrReverseExceeded,
+		},
+		{
+			name:     "唯一索引冲突，已经冲正过了",
+			repo:     &fakeReverseRepo{acts: credits, err: repository.ErrDuplicateReverse},
+			r:        domain.Reverse{Biz: "reward", BizId: 1, ReverseNo: "r1", Amt: 50},
+			wantActs: []domain.Activity{act(usr, -45, ""), act(sys, -5, "")},
+		},
+		{
+			name:     "数据库错误",
+			repo:     &fakeReverseRepo{acts: credits, err: errors.New("mock db error")},
+			r:        domain.Reverse{Biz: "reward", BizId: 1, ReverseNo: "r1", Amt: 50},
+			wantActs: []domain.Activity{act(usr, -45, ""), act(sys, -5, "")},
+			wantErr:  errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := NewAccountService(tc.repo, nil)
+			err := svc.Reverse(context.Background(), tc.r)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantActs, tc.repo.added)
+		})
+	}
+}
+
+// fakeReverseRepo 把 acts 当成数据库里面已经有的流水，记下 fn 算出来的冲正流水
+type fakeReverseRepo struct {
+	repository.AccountRepository
+	acts []domain.Activity
+	// err 记账的时候返回的错误
+	err   error
+	added []domain.Activity
+}
+
+func (f *fakeReverseRepo) AddReverse(ctx context.Context, r domain.Reverse,
+	fn func(acts []domain.Activity) ([]domain.Activity, error)) error {
+	res, err := fn(f.acts)
+	if err != nil {
+		return err
+	}
+	f.added = res
+	return f.err
+}
+
+func Test_reverseItems(t *testing.T) {
+	// 打赏 100，作者拿 90，平台拿 10
+	usr := domain.CreditItem{Uid: 1024, Account: 123,
+		AccountType: domain.AccountTypeReward, Amt: 90, Currency: "CNY"}
+	sys := domain.CreditItem{AccountType: domain.AccountTypeSystem, Amt: 10, Currency: "CNY"}
+	clearing := domain.CreditItem{AccountType: domain.AccountTypeClearing, Amt: -100, Currency: "CNY"}
+	// 没有换汇的流水
+	act := func(c domain.CreditItem, amt int64, reverseNo string) domain.Activity {
+		c.Amt = amt
+		return domain.Activity{CreditItem: c, OrigAmt: amt, OrigCurrency: c.Currency,
+			Rate: "1", ReverseNo: reverseNo}
+	}
+	// 打赏 100 美元，按照 7.1 的汇率换成人民币入账
+	converted := func(c domain.CreditItem, orig, amt int64, reverseNo string) domain.Activity {
+		c.Amt = amt
+		return domain.Activity{CreditItem: c, OrigAmt: orig, OrigCurrency: "USD",
+			Rate: "7.1", ReverseNo: reverseNo}
+	}
+	credits := []domain.Activity{act(usr, 90, ""), act(sys, 10, "")}
+	testCases := []struct {
+		name string
+		acts []domain.Activity
+		amt  int64
+
+		wantActs []domain.Activity
+		wantErr  error
+	}{
+		{
+			name:     "全额冲正",
+			acts:     credits,
+			amt:      100,
+			wantActs: []domain.Activity{act(usr, -90, ""), act(sys, -10, "")},
+		},
+		{
+			name:     "部分冲正，按比例分摊",
+			acts:     credits,
+			amt:      50,
+			wantActs: []domain.Activity{act(usr, -45, ""), act(sys, -5, "")},
+		},
+		{
+			name:     "除不尽，余数给剩余最多的账号",
+			acts:     credits,
+			amt:      15,
+			wantActs: []domain.Activity{act(usr, -14, ""), act(sys, -1, "")},
+		},
+		{
+			name: "最后一次冲正把剩下的全部扣掉",
+			acts: append(append([]domain.Activity{}, credits...),
+				act(usr, -45, "r1"), act(sys, -5, "r1")),
+			amt:      50,
+			wantActs: []domain.Activity{act(usr, -45, ""), act(sys, -5, "")},
+		},
+		{
+			name: "超过了剩下的金额",
+			acts: append(append([]domain.Activity{}, credits...),
+				act(usr, -45, "r1"), act(sys, -5, "r1")),
+			amt:     60,
+			wantErr: ErrReverseExceeded,
+		},
+		{
+			name: "有对手方的入账，两边都冲正",
+			acts: []domain.Activity{act(usr, 90, ""), act(sys, 10, ""), act(clearing, -100, "")},
+			amt:  15,
+			wantActs: []domain.Activity{act(usr, -14, ""), act(sys, -1, ""),
+				act(clearing, 15, "")},
+		},
+		{
+			name: "有对手方的入账，最后一次冲正",
+			acts: []domain.Activity{act(usr, 90, ""), act(sys, 10, ""), act(clearing, -100, ""),
+				act(usr, -45, "r1"), act(sys, -5, "r1"), act(clearing, 50, "r1")},
+			amt: 50,
+			wantActs: []domain.Activity{act(usr, -45, ""), act(sys, -5, ""),
+				act(clearing, 50, "")},
+		},
+		{
+			name: "换汇的入账，按照入账时候的汇率扣回来",
+			acts: []domain.Activity{converted(usr, 90, 639, ""), converted(sys, 10, 71, ""),
+				converted(clearing, -100, -710, "")},
+			amt: 15,
+			// 106.5 舍掉了，加起来还是平的
+			wantActs: []domain.Activity{converted(usr, -14, -99, ""), converted(sys, -1, -7, ""),
+				converted(clearing, 15, 106, "")},
+		},
+		{
+			name: "换汇的入账，最后一次冲正把剩下的全部扣掉",
+			acts: []domain.Activity{converted(usr, 90, 639, ""), converted(sys, 10, 71, ""),
+				converted(clearing, -100, -710, ""),
+				converted(usr, -14, -99, "r1"), converted(sys, -1, -7, "r1"),
+				converted(clearing, 15, 106, "r1")},
+			amt: 85,
+			wantActs: []domain.Activity{converted(usr, -76, -540, ""), converted(sys, -9, -64, ""),
+				converted(clearing, 85, 604, "")},
+		},
+		{
+			name:    "没有入账记录",
+			amt:     10,
+			wantErr: ErrCreditNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			acts, err := reverseItems(tc.acts, tc.amt)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantActs, acts)
+		})
+	}
+}
+
+func Test_fixRounding(t *testing.T) {
+	testCases := []struct {
+		name string
+		acts []domain.Activity
+
+		wantAmts []int64
+		want     bool
+	}{
+		{
+			name: "差一点，算在金额最大的那一条上",
+			acts: []domain.Activity{
+				{CreditItem: domain.CreditItem{Amt: 640, Currency: "CNY"}, OrigAmt: 90, OrigCurrency: "USD"},
+				{CreditItem: domain.CreditItem{Amt: 71, Currency: "CNY"}, OrigAmt: 10, OrigCurrency: "USD"},
+				{CreditItem: domain.CreditItem{Amt: -710, Currency: "CNY"}, OrigAmt: -100, OrigCurrency: "USD"},
+			},
+			wantAmts: []int64{640, 71, -711},
+			want:     true,
+		},
+		{
+			name: "有的换汇有的不换",
+			acts: []domain.Activity{
+				{CreditItem: domain.CreditItem{Amt: 710, Currency: "CNY"}, OrigAmt: 100, OrigCurrency: "USD"},
+				{CreditItem: domain.CreditItem{Amt: -100, Currency: "USD"}, OrigAmt: -100, OrigCurrency: "USD"},
+			},
+			wantAmts: []int64{710, -100},
+		},
+		{
+			name: "没有换汇的不处理",
+			acts: []domain.Activity{
+				{CreditItem: domain.CreditItem{Amt: -1, Currency: "CNY"}, OrigAmt: -1, OrigCurrency: "CNY"},
+			},
+			wantAmts: []int64{-1},
+			want:     true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ok := fixRounding(tc.acts)
+			assert.Equal(t, tc.want, ok)
+			assert.Equal(t, tc.wantAmts, slice.Map(tc.acts, func(idx int, src domain.Activity) int64 {
+				return src.Amt
+			}))
+		})
+	}
+}
+
+func Test_balanced(t *testing.T) {
+	testCases := []struct {
+		name  string
+		items []domain.CreditItem
+		want  bool
+	}{
+		{
+			name: "平的",
+			items: []domain.CreditItem{
+				{Account: 123, Amt: 90, Currency: "CNY"},
+				{AccountType: domain.AccountTypeSystem, Amt: 10, Currency: "CNY"},
+				{AccountType: domain.AccountTypeClearing, Amt: -100, Currency: "CNY"},
+			},
+			want: true,
+		},
+		{
+			name: "没有对手方",
+			items: []domain.CreditItem{
+				{Account: 123, Amt: 90, Currency: "CNY"},
+				{AccountType: domain.AccountTypeSystem, Amt: 10, Currency: "CNY"},
+			},
+		},
+		{
+			name: "加起来是 0，但是币种不一样",
+			items: []domain.CreditItem{
+				{Account: 123, Amt: 100, Currency: "CNY"},
+				{AccountType: domain.AccountTypeClearing, Amt: -100, Currency: "USD"},
+			},
+		},
+		{
+			name: "没有账号",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, balanced(tc.items))
+		})
+	}
+}
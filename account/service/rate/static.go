@@ -0,0 +1,71 @@
+package rate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitee.com/geekbang/basic-go/webook/account/domain"
+	"gitee.com/geekbang/basic-go/webook/account/service"
+	"github.com/spf13/viper"
+	"math/big"
+	"strings"
+)
+
+var ErrUnsupportedCurrency = errors.New("不支持的币种")
+
+// StaticProvider 固定的汇率，配置的是 1 元各个币种可以换多少基准币种。
+// 比如说基准币种是 CNY，那么 USD: 7.1 就是 1 美元换 7.1 人民币
+type StaticProvider struct {
+	base  string
+	rates map[string]*big.Rat
+}
+
+func NewStaticProvider(base string, rates map[string]string) (service.RateProvider, error) {
+	res := &StaticProvider{
+		base:  strings.ToUpper(base),
+		rates: make(map[string]*big.Rat, len(rates)+1),
+	}
+	for currency, val := range rates {
+		v, ok := new(big.Rat).SetString(val)
+		if !ok || v.Sign() <= 0 {
+			return nil, fmt.Errorf("%w %s: %s", domain.ErrInvalidRate, currency, val)
+		}
+		// viper 会把 key 变成小写
+		res.rates[strings.ToUpper(currency)] = v
+	}
+	res.rates[res.base] = big.NewRat(1, 1)
+	return res, nil
+}
+
+// NewStaticFileProvider 从文件里面读汇率，格式是：
+//
+//	base: CNY
+//	rates:
+//	  USD: "7.1"
+func NewStaticFileProvider(path string) (service.RateProvider, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	err := v.ReadInConfig()
+	if err != nil {
+		return nil, err
+	}
+	return NewStaticProvider(v.GetString("base"), v.GetStringMapString("rates"))
+}
+
+func (p *StaticProvider) Rate(ctx context.Context, from, to string) (domain.Rate, error) {
+	if from == to {
+		return domain.Rate{From: from, To: to, Value: "1"}, nil
+	}
+	f, ok := p.rates[from]
+	if !ok {
+		return domain.Rate{}, fmt.Errorf("%w %s", ErrUnsupportedCurrency, from)
+	}
+	t, ok := p.rates[to]
+	if !ok {
+		return domain.Rate{}, fmt.Errorf("%w %s", ErrUnsupportedCurrency, to)
+	}
+	val := new(big.Rat).Quo(f, t).FloatString(8)
+	// 记账的时候不要一堆 0
+	val = strings.TrimRight(strings.TrimRight(val, "0"), ".")
+	return domain.Rate{From: from, To: to, Value: val}, nil
+}
@@ -0,0 +1,23 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/account/domain"
+	"gitee.com/geekbang/basic-go/webook/account/service"
+)
+
+var ErrTransferDisabled = errors.New("没有配置打款渠道")
+
+// DisabledProvider 没有配置打款渠道的时候用，所有打款都失败。
+// 提现会停在审核通过的状态，钱还是冻结的，配置好渠道之后再审批一次就可以
+type DisabledProvider struct {
+}
+
+func NewDisabledProvider() service.TransferProvider {
+	return &DisabledProvider{}
+}
+
+func (p *DisabledProvider) Transfer(ctx context.Context, w domain.Withdrawal) (domain.Transfer, error) {
+	return domain.Transfer{}, ErrTransferDisabled
+}
@@ -0,0 +1,30 @@
+package transfer
+
+import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/account/domain"
+	"gitee.com/geekbang/basic-go/webook/account/service"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+)
+
+// LocalProvider 本地的打款渠道，不会真的转账，马上就成功。
+// 用来在开发和测试环境把提现流程跑通，线上不能用
+type LocalProvider struct {
+	l logger.LoggerV1
+}
+
+func NewLocalProvider(l logger.LoggerV1) service.TransferProvider {
+	return &LocalProvider{l: l}
+}
+
+func (p *LocalProvider) Transfer(ctx context.Context, w domain.Withdrawal) (domain.Transfer, error) {
+	p.l.Info("模拟打款",
+		logger.String("withdraw_no", w.WithdrawNO),
+		logger.Int64("uid", w.Uid),
+		logger.Int64("amt", w.Amt))
+	// 流水号由 WithdrawNO 决定，重复打款返回的结果是一样的
+	return domain.Transfer{
+		TxnID:  "local-" + w.WithdrawNO,
+		Status: domain.WithdrawalStatusPaid,
+	}, nil
+}
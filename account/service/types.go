@@ -6,5 +6,47 @@ import (
 )
 
 type AccountService interface {
+	// Credit 入账，Items 按照币种加起来不是 0 的话返回 ErrUnbalancedCredit。
+	// 设置了 SettleCurrency 的会按照当前的汇率换汇之后入账，换汇之后也必须是平的
 	Credit(ctx context.Context, cr domain.Credit) error
+	// Reverse 冲正，按照原本入账的比例从每个账号扣回来，
+	// 多次冲正加起来不会超过原本入账的金额
+	Reverse(ctx context.Context, r domain.Reverse) error
+	// Debit 出账，冻结的钱不能用。同一个 Biz + BizId 重复出账是幂等的
+	Debit(ctx context.Context, d domain.Debit) error
+
+	// GetBalance 同一个账号每个币种的余额是分开的
+	GetBalance(ctx context.Context, account int64, typ domain.AccountType, currency string) (domain.Balance, error)
+	// ListActivities 账号的流水，新的在前面
+	ListActivities(ctx context.Context, account int64, typ domain.AccountType, currency string,
+		offset, limit int) ([]domain.Activity, error)
+	// GetStatement 某个月的对账单，month 的格式是 domain.StatementMonthLayout
+	GetStatement(ctx context.Context, account int64, typ domain.AccountType, currency string,
+		month string) (domain.Statement, error)
+}
+
+// WithdrawalService 提现，状态机见 domain.WithdrawalStatus
+type WithdrawalService interface {
+	// RequestWithdrawal 申请提现，会冻结账号里面的钱。
+	// 同一个 WithdrawNO 重复申请返回之前的提现记录
+	RequestWithdrawal(ctx context.Context, w domain.Withdrawal) (domain.Withdrawal, error)
+	// ApproveWithdrawal 审核通过并且打款。
+	// 上一次打款没有结果的话，再调用一次会重新打款
+	ApproveWithdrawal(ctx context.Context, id int64) (domain.Withdrawal, error)
+	// RejectWithdrawal 审核不通过，解冻
+	RejectWithdrawal(ctx context.Context, id int64, reason string) error
+	GetWithdrawal(ctx context.Context, id int64) (domain.Withdrawal, error)
+	ListWithdrawals(ctx context.Context, uid int64, offset, limit int) ([]domain.Withdrawal, error)
+}
+
+// RateProvider 汇率，from 和 to 一样的时候汇率是 1。
+// 不支持的币种返回 rate.ErrUnsupportedCurrency
+type RateProvider interface {
+	Rate(ctx context.Context, from, to string) (domain.Rate, error)
+}
+
+// TransferProvider 第三方打款，比如说微信商家转账。
+// 同一个 WithdrawNO 重复调用不会重复打款，而是返回之前的结果
+type TransferProvider interface {
+	Transfer(ctx context.Context, w domain.Withdrawal) (domain.Transfer, error)
 }
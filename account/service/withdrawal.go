@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/account/domain"
+	"gitee.com/geekbang/basic-go/webook/account/repository"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+)
+
+var (
+	ErrWithdrawalStatus   = repository.ErrWithdrawalStatus
+	ErrWithdrawalNotFound = repository.ErrWithdrawalNotFound
+)
+
+type withdrawalService struct {
+	repo     repository.WithdrawalRepository
+	provider TransferProvider
+	l        logger.LoggerV1
+}
+
+func NewWithdrawalService(repo repository.WithdrawalRepository,
+	provider TransferProvider, l logger.LoggerV1) WithdrawalService {
+	return &withdrawalService{repo: repo, provider: provider, l: l}
+}
+
+func (s *withdrawalService) RequestWithdrawal(ctx context.Context, w domain.Withdrawal) (domain.Withdrawal, error) {
+	if w.Amt <= 0 {
+		return domain.Withdrawal{}, ErrInvalidAmount
+	}
+	w.Status = domain.WithdrawalStatusRequested
+	id, err := s.repo.CreateWithdrawal(ctx, w)
+	if errors.Is(err, repository.ErrDuplicateWithdrawal) {
+		// 重复申请，钱只冻结了一次
+		return s.repo.GetWithdrawalByNO(ctx, w.WithdrawNO)
+	}
+	if err != nil {
+		return domain.Withdrawal{}, err
+	}
+	return s.repo.GetWithdrawal(ctx, id)
+}
+
+func (s *withdrawalService) ApproveWithdrawal(ctx context.Context, id int64) (domain.Withdrawal, error) {
+	w, err := s.repo.GetWithdrawal(ctx, id)
+	if err != nil {
+		return domain.Withdrawal{}, err
+	}
+	switch w.Status {
+	case domain.WithdrawalStatusRequested, domain.WithdrawalStatusApproved:
+		// Approved 是上一次打款没有结果，再打一次，第三方会按照 WithdrawNO 去重。
+		// 先认领，并发审核的时候只有一个人会去打款
+		err = s.repo.ClaimWithdrawal(ctx, w)
+		if err != nil {
+			return w, err
+		}
+		w.Status = domain.WithdrawalStatusApproved
+	case domain.WithdrawalStatusPaid:
+		return w, nil
+	default:
+		return w, ErrWithdrawalStatus
+	}
+	return s.transfer(ctx, w)
+}
+
+// transfer 打款，w 必须是审核通过的
+func (s *withdrawalService) transfer(ctx context.Context, w domain.Withdrawal) (domain.Withdrawal, error) {
+	res, err := s.provider.Transfer(ctx, w)
+	if err != nil {
+		// 不知道有没有打款成功，保持审核通过的状态，等下一次重试
+		return w, err
+	}
+	switch res.Status {
+	case domain.WithdrawalStatusPaid:
+		err = s.repo.CompleteWithdrawal(ctx, w, res.TxnID)
+		if errors.Is(err, ErrWithdrawalStatus) {
+			// 上一次认领的人已经把结果记下来了
+			nw, er := s.repo.GetWithdrawal(ctx, w.Id)
+			if er == nil && nw.Status == domain.WithdrawalStatusPaid {
+				return nw, nil
+			}
+		}
+		if err != nil {
+			s.l.Error("打款成功了，但是扣钱失败了，快来修数据啊！！！", logger.Error(err),
+				logger.Int64("id", w.Id),
+				logger.String("txn_id", res.TxnID))
+			return w, err
+		}
+		w.Status = domain.WithdrawalStatusPaid
+		w.TxnID = res.TxnID
+	case domain.WithdrawalStatusFailed:
+		err = s.repo.FailWithdrawal(ctx, w, res.Reason)
+		if err != nil {
+			return w, err
+		}
+		w.Status = domain.WithdrawalStatusFailed
+		w.Reason = res.Reason
+	}
+	return w, nil
+}
+
+func (s *withdrawalService) RejectWithdrawal(ctx context.Context, id int64, reason string) error {
+	w, err := s.repo.GetWithdrawal(ctx, id)
+	if err != nil {
+		return err
+	}
+	if w.Status != domain.WithdrawalStatusRequested {
+		return ErrWithdrawalStatus
+	}
+	return s.repo.FailWithdrawal(ctx, w, reason)
+}
+
+func (s *withdrawalService) GetWithdrawal(ctx context.Context, id int64) (domain.Withdrawal, error) {
+	return s.repo.GetWithdrawal(ctx, id)
+}
+
+func (s *withdrawalService) ListWithdrawals(ctx context.Context, uid int64, offset, limit int) ([]domain.Withdrawal, error) {
+	return s.repo.FindWithdrawals(ctx, uid, offset, limit)
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/account/domain"
+	"gitee.com/geekbang/basic-go/webook/account/repository"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func Test_withdrawalService_ApproveWithdrawal(t *testing.T) {
+	utime := time.UnixMilli(1000)
+	wd := func(status domain.WithdrawalStatus) domain.Withdrawal {
+		return domain.Withdrawal{Id: 1, WithdrawNO: "w1", Uid: 1024, Account: 123,
+			AccountType: domain.AccountTypeReward, Amt: 100, Currency: "CNY",
+			Status: status, Utime: utime}
+	}
+	testCases := []struct {
+		name     string
+		repo     *fakeWithdrawalRepo
+		provider *fakeTransferProvider
+
+		want domain.Withdrawal
+		// wantClaimed 认领的时候传进去的提现
+		wantClaimed   []domain.Withdrawal
+		wantTransfers int
+		wantCompleted bool
+		wantFailed    string
+		wantErr       error
+	}{
+		{
+			name:     "审核通过，打款成功",
+			repo:     &fakeWithdrawalRepo{ws: []domain.Withdrawal{wd(domain.WithdrawalStatusRequested)}},
+			provider: &fakeTransferProvider{res: domain.Transfer{TxnID: "t1", Status: domain.WithdrawalStatusPaid}},
+			want: func() domain.Withdrawal {
+				w := wd(domain.WithdrawalStatusPaid)
+				w.TxnID = "t1"
+				return w
+			}(),
+			wantClaimed:   []domain.Withdrawal{wd(domain.WithdrawalStatusRequested)},
+			wantTransfers: 1,
+			wantCompleted: true,
+		},
+		{
+			name:     "打款失败，解冻",
+			repo:     &fakeWithdrawalRepo{ws: []domain.Withdrawal{wd(domain.WithdrawalStatusRequested)}},
+			provider: &fakeTransferProvider{res: domain.Transfer{Status: domain.WithdrawalStatusFailed, Reason: "实名不一致"}},
+			want: func() domain.Withdrawal {
+				w := wd(domain.WithdrawalStatusFailed)
+				w.Reason = "实名不一致"
+				return w
+			}(),
+			wantClaimed:   []domain.Withdrawal{wd(domain.WithdrawalStatusRequested)},
+			wantTransfers: 1,
+			wantFailed:    "实名不一致",
+		},
+		{
+			name:          "还在处理，保持审核通过",
+			repo:          &fakeWithdrawalRepo{ws: []domain.Withdrawal{wd(domain.WithdrawalStatusRequested)}},
+			provider:      &fakeTransferProvider{res: domain.Transfer{Status: domain.WithdrawalStatusApproved}},
+			want:          wd(domain.WithdrawalStatusApproved),
+			wantClaimed:   []domain.Withdrawal{wd(domain.WithdrawalStatusRequested)},
+			wantTransfers: 1,
+		},
+		{
+			name:          "上一次没有结果，重新认领再打一次",
+			repo:          &fakeWithdrawalRepo{ws: []domain.Withdrawal{wd(domain.WithdrawalStatusApproved)}},
+			provider:      &fakeTransferProvider{res: domain.Transfer{TxnID: "t1", Status: domain.WithdrawalStatusPaid}},
+			want:          func() domain.Withdrawal { w := wd(domain.WithdrawalStatusPaid); w.TxnID = "t1"; return w }(),
+			wantClaimed:   []domain.Withdrawal{wd(domain.WithdrawalStatusApproved)},
+			wantTransfers: 1,
+			wantCompleted: true,
+		},
+		{
+			name:     "已经打款了",
+			repo:     &fakeWithdrawalRepo{ws: []domain.Withdrawal{wd(domain.WithdrawalStatusPaid)}},
+			provider: &fakeTransferProvider{},
+			want:     wd(domain.WithdrawalStatusPaid),
+		},
+		{
+			name:     "已经失败了",
+			repo:     &fakeWithdrawalRepo{ws: []domain.Withdrawal{wd(domain.WithdrawalStatusFailed)}},
+			provider: &fakeTransferProvider{},
+			want:     wd(domain.WithdrawalStatusFailed),
+			wantErr:  ErrWithdrawalStatus,
+		},
+		{
+			// 并发审核，别人先认领了，不能再打款
+			name: "认领失败",
+			repo: &fakeWithdrawalRepo{ws: []domain.Withdrawal{wd(domain.WithdrawalStatusRequested)},
+				claimErr: repository.ErrWithdrawalStatus},
+			provider:    &fakeTransferProvider{},
+			want:        wd(domain.WithdrawalStatusRequested),
+			wantClaimed: []domain.Withdrawal{wd(domain.WithdrawalStatusRequested)},
+			wantErr:     ErrWithdrawalStatus,
+		},
+		{
+			name:          "打款超时，不知道结果",
+			repo:          &fakeWithdrawalRepo{ws: []domain.Withdrawal{wd(domain.WithdrawalStatusRequested)}},
+			provider:      &fakeTransferProvider{err: errors.New("mock timeout")},
+			want:          wd(domain.WithdrawalStatusApproved),
+			wantClaimed:   []domain.Withdrawal{wd(domain.WithdrawalStatusRequested)},
+			wantTransfers: 1,
+			wantErr:       errors.New("mock timeout"),
+		},
+		{
+			// 上一个认领的人打款成功并且已经记账了，第三方按照 WithdrawNO 去重返回了同一个结果
+			name: "别人已经记账了",
+			repo: &fakeWithdrawalRepo{ws: []domain.Withdrawal{wd(domain.WithdrawalStatusApproved),
+				func() domain.Withdrawal { w := wd(domain.WithdrawalStatusPaid); w.TxnID = "t1"; return w }()},
+				completeErr: repository.ErrWithdrawalStatus},
+			provider:      &fakeTransferProvider{res: domain.Transfer{TxnID: "t1", Status: domain.WithdrawalStatusPaid}},
+			want:          func() domain.Withdrawal { w := wd(domain.WithdrawalStatusPaid); w.TxnID = "t1"; return w }(),
+			wantClaimed:   []domain.Withdrawal{wd(domain.WithdrawalStatusApproved)},
+			wantTransfers: 1,
+			wantCompleted: true,
+		},
+		{
+			name: "打款成功，扣钱失败",
+			repo: &fakeWithdrawalRepo{ws: []domain.Withdrawal{wd(domain.WithdrawalStatusRequested)},
+				completeErr: errors.New("mock db error")},
+			provider:      &fakeTransferProvider{res: domain.Transfer{TxnID: "t1", Status: domain.WithdrawalStatusPaid}},
+			want:          wd(domain.WithdrawalStatusApproved),
+			wantClaimed:   []domain.Withdrawal{wd(domain.WithdrawalStatusRequested)},
+			wantTransfers: 1,
+			wantCompleted: true,
+			wantErr:       errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := NewWithdrawalService(tc.repo, tc.provider, logger.NewNopLogger())
+			w, err := svc.ApproveWithdrawal(context.Background(), 1)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, w)
+			assert.Equal(t, tc.wantClaimed, tc.repo.claimed)
+			assert.Equal(t, tc.wantTransfers, tc.provider.cnt)
+			assert.Equal(t, tc.wantCompleted, tc.repo.completed)
+			assert.Equal(t, tc.wantFailed, tc.repo.failed)
+		})
+	}
+}
+
+// fakeWithdrawalRepo 每次 GetWithdrawal 按顺序返回 ws 里面的一个，最后一个会一直返回
+type fakeWithdrawalRepo struct {
+	repository.WithdrawalRepository
+	ws   []domain.Withdrawal
+	gets int
+
+	claimErr    error
+	completeErr error
+
+	claimed   []domain.Withdrawal
+	completed bool
+	failed    string
+}
+
+func (f *fakeWithdrawalRepo) GetWithdrawal(ctx context.Context, id int64) (domain.Withdrawal, error) {
+	idx := f.gets
+	if idx >= len(f.ws) {
+		idx = len(f.ws) - 1
+	}
+	f.gets++
+	return f.ws[idx], nil
+}
+
+func (f *fakeWithdrawalRepo) ClaimWithdrawal(ctx context.Context, w domain.Withdrawal) error {
+	f.claimed = append(f.claimed, w)
+	return f.claimErr
+}
+
+func (f *fakeWithdrawalRepo) CompleteWithdrawal(ctx context.Context, w domain.Withdrawal, txnID string) error {
+	f.completed = true
+	return f.completeErr
+}
+
+func (f *fakeWithdrawalRepo) FailWithdrawal(ctx context.Context, w domain.Withdrawal, reason string) error {
+	f.failed = reason
+	return nil
+}
+
+type fakeTransferProvider struct {
+	res domain.Transfer
+	err error
+	cnt int
+}
+
+func (f *fakeTransferProvider) Transfer(ctx context.Context, w domain.Withdrawal) (domain.Transfer, error) {
+	f.cnt++
+	return f.res, f.err
+}
@@ -18,9 +18,14 @@ func Init() *wego.App {
 		ioc.InitLogger,
 		ioc.InitEtcdClient,
 		ioc.InitGRPCxServer,
+		ioc.InitRateProvider,
 		dao.NewCreditGORMDAO,
 		repository.NewAccountRepository,
 		service.NewAccountService,
+		dao.NewWithdrawalGORMDAO,
+		repository.NewWithdrawalRepository,
+		ioc.InitTransferProvider,
+		service.NewWithdrawalService,
 		grpc.NewAccountServiceServer,
 		wire.Struct(new(wego.App), "GRPCServer"))
 	return new(wego.App)
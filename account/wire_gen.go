@@ -21,10 +21,15 @@ func Init() *wego.App {
 	db := ioc.InitDB()
 	accountDAO := dao.NewCreditGORMDAO(db)
 	accountRepository := repository.NewAccountRepository(accountDAO)
-	accountService := service.NewAccountService(accountRepository)
-	accountServiceServer := grpc.NewAccountServiceServer(accountService)
-	client := ioc.InitEtcdClient()
+	rateProvider := ioc.InitRateProvider()
+	accountService := service.NewAccountService(accountRepository, rateProvider)
+	withdrawalDAO := dao.NewWithdrawalGORMDAO(db)
+	withdrawalRepository := repository.NewWithdrawalRepository(withdrawalDAO)
 	loggerV1 := ioc.InitLogger()
+	transferProvider := ioc.InitTransferProvider(loggerV1)
+	withdrawalService := service.NewWithdrawalService(withdrawalRepository, transferProvider, loggerV1)
+	accountServiceServer := grpc.NewAccountServiceServer(accountService, withdrawalService)
+	client := ioc.InitEtcdClient()
 	server := ioc.InitGRPCxServer(accountServiceServer, client, loggerV1)
 	app := &wego.App{
 		GRPCServer: server,
@@ -0,0 +1,111 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: job/v1/job.proto
+
+package jobv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	JobExecutorService_Execute_FullMethodName = "/job.v1.JobExecutorService/Execute"
+)
+
+// JobExecutorServiceClient is the client API for JobExecutorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type JobExecutorServiceClient interface {
+	// Execute 执行一次任务，超时由调用方通过 deadline 控制
+	Execute(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (*ExecuteResponse, error)
+}
+
+type jobExecutorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewJobExecutorServiceClient(cc grpc.ClientConnInterface) JobExecutorServiceClient {
+	return &jobExecutorServiceClient{cc}
+}
+
+func (c *jobExecutorServiceClient) Execute(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (*ExecuteResponse, error) {
+	out := new(ExecuteResponse)
+	err := c.cc.Invoke(ctx, JobExecutorService_Execute_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// JobExecutorServiceServer is the server API for JobExecutorService service.
+// All implementations must embed UnimplementedJobExecutorServiceServer
+// for forward compatibility
+type JobExecutorServiceServer interface {
+	// Execute 执行一次任务，超时由调用方通过 deadline 控制
+	Execute(context.Context, *ExecuteRequest) (*ExecuteResponse, error)
+	mustEmbedUnimplementedJobExecutorServiceServer()
+}
+
+// UnimplementedJobExecutorServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedJobExecutorServiceServer struct {
+}
+
+func (UnimplementedJobExecutorServiceServer) Execute(context.Context, *ExecuteRequest) (*ExecuteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Execute not implemented")
+}
+func (UnimplementedJobExecutorServiceServer) mustEmbedUnimplementedJobExecutorServiceServer() {}
+
+// UnsafeJobExecutorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to JobExecutorServiceServer will
+// result in compilation errors.
+type UnsafeJobExecutorServiceServer interface {
+	mustEmbedUnimplementedJobExecutorServiceServer()
+}
+
+func RegisterJobExecutorServiceServer(s grpc.ServiceRegistrar, srv JobExecutorServiceServer) {
+	s.RegisterService(&JobExecutorService_ServiceDesc, srv)
+}
+
+func _JobExecutorService_Execute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobExecutorServiceServer).Execute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: JobExecutorService_Execute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobExecutorServiceServer).Execute(ctx, req.(*ExecuteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// JobExecutorService_ServiceDesc is the grpc.ServiceDesc for JobExecutorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var JobExecutorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "job.v1.JobExecutorService",
+	HandlerType: (*JobExecutorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Execute",
+			Handler:    _JobExecutorService_Execute_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "job/v1/job.proto",
+}
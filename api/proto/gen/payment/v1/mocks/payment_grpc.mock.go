@@ -60,6 +60,26 @@ func (mr *MockWechatPaymentServiceClientMockRecorder) GetPayment(ctx, in any, op
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayment", reflect.TypeOf((*MockWechatPaymentServiceClient)(nil).GetPayment), varargs...)
 }
 
+// GetRefund mocks base method.
+func (m *MockWechatPaymentServiceClient) GetRefund(ctx context.Context, in *pmtv1.GetRefundRequest, opts ...grpc.CallOption) (*pmtv1.GetRefundResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetRefund", varargs...)
+	ret0, _ := ret[0].(*pmtv1.GetRefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefund indicates an expected call of GetRefund.
+func (mr *MockWechatPaymentServiceClientMockRecorder) GetRefund(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefund", reflect.TypeOf((*MockWechatPaymentServiceClient)(nil).GetRefund), varargs...)
+}
+
 // NativePrePay mocks base method.
 func (m *MockWechatPaymentServiceClient) NativePrePay(ctx context.Context, in *pmtv1.PrePayRequest, opts ...grpc.CallOption) (*pmtv1.NativePrePayResponse, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +100,26 @@ func (mr *MockWechatPaymentServiceClientMockRecorder) NativePrePay(ctx, in any,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NativePrePay", reflect.TypeOf((*MockWechatPaymentServiceClient)(nil).NativePrePay), varargs...)
 }
 
+// Refund mocks base method.
+func (m *MockWechatPaymentServiceClient) Refund(ctx context.Context, in *pmtv1.RefundRequest, opts ...grpc.CallOption) (*pmtv1.RefundResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Refund", varargs...)
+	ret0, _ := ret[0].(*pmtv1.RefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockWechatPaymentServiceClientMockRecorder) Refund(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockWechatPaymentServiceClient)(nil).Refund), varargs...)
+}
+
 // MockWechatPaymentServiceServer is a mock of WechatPaymentServiceServer interface.
 type MockWechatPaymentServiceServer struct {
 	ctrl     *gomock.Controller
@@ -118,6 +158,21 @@ func (mr *MockWechatPaymentServiceServerMockRecorder) GetPayment(arg0, arg1 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayment", reflect.TypeOf((*MockWechatPaymentServiceServer)(nil).GetPayment), arg0, arg1)
 }
 
+// GetRefund mocks base method.
+func (m *MockWechatPaymentServiceServer) GetRefund(arg0 context.Context, arg1 *pmtv1.GetRefundRequest) (*pmtv1.GetRefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefund", arg0, arg1)
+	ret0, _ := ret[0].(*pmtv1.GetRefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefund indicates an expected call of GetRefund.
+func (mr *MockWechatPaymentServiceServerMockRecorder) GetRefund(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefund", reflect.TypeOf((*MockWechatPaymentServiceServer)(nil).GetRefund), arg0, arg1)
+}
+
 // NativePrePay mocks base method.
 func (m *MockWechatPaymentServiceServer) NativePrePay(arg0 context.Context, arg1 *pmtv1.PrePayRequest) (*pmtv1.NativePrePayResponse, error) {
 	m.ctrl.T.Helper()
@@ -133,6 +188,21 @@ func (mr *MockWechatPaymentServiceServerMockRecorder) NativePrePay(arg0, arg1 an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NativePrePay", reflect.TypeOf((*MockWechatPaymentServiceServer)(nil).NativePrePay), arg0, arg1)
 }
 
+// Refund mocks base method.
+func (m *MockWechatPaymentServiceServer) Refund(arg0 context.Context, arg1 *pmtv1.RefundRequest) (*pmtv1.RefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", arg0, arg1)
+	ret0, _ := ret[0].(*pmtv1.RefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockWechatPaymentServiceServerMockRecorder) Refund(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockWechatPaymentServiceServer)(nil).Refund), arg0, arg1)
+}
+
 // mustEmbedUnimplementedWechatPaymentServiceServer mocks base method.
 func (m *MockWechatPaymentServiceServer) mustEmbedUnimplementedWechatPaymentServiceServer() {
 	m.ctrl.T.Helper()
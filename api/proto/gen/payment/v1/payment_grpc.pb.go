@@ -23,18 +23,23 @@ const _ = grpc.SupportPackageIsVersion7
 const (
 	WechatPaymentService_NativePrePay_FullMethodName = "/pmt.v1.WechatPaymentService/NativePrePay"
 	WechatPaymentService_GetPayment_FullMethodName   = "/pmt.v1.WechatPaymentService/GetPayment"
+	WechatPaymentService_Refund_FullMethodName       = "/pmt.v1.WechatPaymentService/Refund"
+	WechatPaymentService_GetRefund_FullMethodName    = "/pmt.v1.WechatPaymentService/GetRefund"
 )
 
 // WechatPaymentServiceClient is the client API for WechatPaymentService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type WechatPaymentServiceClient interface {
-	//	这个设计是认为，Prepay 的请求应该是不同的支付方式都是一样的
-	//
+	//  这个设计是认为，Prepay 的请求应该是不同的支付方式都是一样的
 	// 但是我们认为响应会是不一样的
 	// buf:lint:ignore RPC_REQUEST_STANDARD_NAME
 	NativePrePay(ctx context.Context, in *PrePayRequest, opts ...grpc.CallOption) (*NativePrePayResponse, error)
 	GetPayment(ctx context.Context, in *GetPaymentRequest, opts ...grpc.CallOption) (*GetPaymentResponse, error)
+	// Refund 退款，支持全额和部分退款。
+	// 退款是异步的，结果通过退款事件通知，也可以用 GetRefund 查询
+	Refund(ctx context.Context, in *RefundRequest, opts ...grpc.CallOption) (*RefundResponse, error)
+	GetRefund(ctx context.Context, in *GetRefundRequest, opts ...grpc.CallOption) (*GetRefundResponse, error)
 }
 
 type wechatPaymentServiceClient struct {
@@ -63,16 +68,37 @@ func (c *wechatPaymentServiceClient) GetPayment(ctx context.Context, in *GetPaym
 	return out, nil
 }
 
+func (c *wechatPaymentServiceClient) Refund(ctx context.Context, in *RefundRequest, opts ...grpc.CallOption) (*RefundResponse, error) {
+	out := new(RefundResponse)
+	err := c.cc.Invoke(ctx, WechatPaymentService_Refund_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wechatPaymentServiceClient) GetRefund(ctx context.Context, in *GetRefundRequest, opts ...grpc.CallOption) (*GetRefundResponse, error) {
+	out := new(GetRefundResponse)
+	err := c.cc.Invoke(ctx, WechatPaymentService_GetRefund_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WechatPaymentServiceServer is the server API for WechatPaymentService service.
 // All implementations must embed UnimplementedWechatPaymentServiceServer
 // for forward compatibility
 type WechatPaymentServiceServer interface {
-	//	这个设计是认为，Prepay 的请求应该是不同的支付方式都是一样的
-	//
+	//  这个设计是认为，Prepay 的请求应该是不同的支付方式都是一样的
 	// 但是我们认为响应会是不一样的
 	// buf:lint:ignore RPC_REQUEST_STANDARD_NAME
 	NativePrePay(context.Context, *PrePayRequest) (*NativePrePayResponse, error)
 	GetPayment(context.Context, *GetPaymentRequest) (*GetPaymentResponse, error)
+	// Refund 退款，支持全额和部分退款。
+	// 退款是异步的，结果通过退款事件通知，也可以用 GetRefund 查询
+	Refund(context.Context, *RefundRequest) (*RefundResponse, error)
+	GetRefund(context.Context, *GetRefundRequest) (*GetRefundResponse, error)
 	mustEmbedUnimplementedWechatPaymentServiceServer()
 }
 
@@ -86,6 +112,12 @@ func (UnimplementedWechatPaymentServiceServer) NativePrePay(context.Context, *Pr
 func (UnimplementedWechatPaymentServiceServer) GetPayment(context.Context, *GetPaymentRequest) (*GetPaymentResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPayment not implemented")
 }
+func (UnimplementedWechatPaymentServiceServer) Refund(context.Context, *RefundRequest) (*RefundResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Refund not implemented")
+}
+func (UnimplementedWechatPaymentServiceServer) GetRefund(context.Context, *GetRefundRequest) (*GetRefundResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRefund not implemented")
+}
 func (UnimplementedWechatPaymentServiceServer) mustEmbedUnimplementedWechatPaymentServiceServer() {}
 
 // UnsafeWechatPaymentServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -135,6 +167,42 @@ func _WechatPaymentService_GetPayment_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WechatPaymentService_Refund_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WechatPaymentServiceServer).Refund(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WechatPaymentService_Refund_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WechatPaymentServiceServer).Refund(ctx, req.(*RefundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WechatPaymentService_GetRefund_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRefundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WechatPaymentServiceServer).GetRefund(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WechatPaymentService_GetRefund_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WechatPaymentServiceServer).GetRefund(ctx, req.(*GetRefundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // WechatPaymentService_ServiceDesc is the grpc.ServiceDesc for WechatPaymentService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -150,6 +218,14 @@ var WechatPaymentService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetPayment",
 			Handler:    _WechatPaymentService_GetPayment_Handler,
 		},
+		{
+			MethodName: "Refund",
+			Handler:    _WechatPaymentService_Refund_Handler,
+		},
+		{
+			MethodName: "GetRefund",
+			Handler:    _WechatPaymentService_GetRefund_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "payment/v1/payment.proto",
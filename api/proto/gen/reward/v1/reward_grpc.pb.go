@@ -19,8 +19,21 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	RewardService_PreReward_FullMethodName = "/reward.v1.RewardService/PreReward"
-	RewardService_GetReward_FullMethodName = "/reward.v1.RewardService/GetReward"
+	RewardService_PreReward_FullMethodName          = "/reward.v1.RewardService/PreReward"
+	RewardService_GetReward_FullMethodName          = "/reward.v1.RewardService/GetReward"
+	RewardService_GetTargetStats_FullMethodName     = "/reward.v1.RewardService/GetTargetStats"
+	RewardService_GetCreatorStats_FullMethodName    = "/reward.v1.RewardService/GetCreatorStats"
+	RewardService_TopSupporters_FullMethodName      = "/reward.v1.RewardService/TopSupporters"
+	RewardService_SaveFeeRule_FullMethodName        = "/reward.v1.RewardService/SaveFeeRule"
+	RewardService_GetFeeRule_FullMethodName         = "/reward.v1.RewardService/GetFeeRule"
+	RewardService_ListFeeRules_FullMethodName       = "/reward.v1.RewardService/ListFeeRules"
+	RewardService_SaveMemberPlan_FullMethodName     = "/reward.v1.RewardService/SaveMemberPlan"
+	RewardService_ListMemberPlans_FullMethodName    = "/reward.v1.RewardService/ListMemberPlans"
+	RewardService_Subscribe_FullMethodName          = "/reward.v1.RewardService/Subscribe"
+	RewardService_GetMemberOrder_FullMethodName     = "/reward.v1.RewardService/GetMemberOrder"
+	RewardService_GetSubscription_FullMethodName    = "/reward.v1.RewardService/GetSubscription"
+	RewardService_CancelSubscription_FullMethodName = "/reward.v1.RewardService/CancelSubscription"
+	RewardService_CheckEntitlement_FullMethodName   = "/reward.v1.RewardService/CheckEntitlement"
 )
 
 // RewardServiceClient is the client API for RewardService service.
@@ -29,6 +42,29 @@ const (
 type RewardServiceClient interface {
 	PreReward(ctx context.Context, in *PreRewardRequest, opts ...grpc.CallOption) (*PreRewardResponse, error)
 	GetReward(ctx context.Context, in *GetRewardRequest, opts ...grpc.CallOption) (*GetRewardResponse, error)
+	// 统计和排行榜，只统计支付成功的，不同币种分开统计
+	// GetTargetStats 被打赏的东西的总数和打赏最多的人
+	GetTargetStats(ctx context.Context, in *GetTargetStatsRequest, opts ...grpc.CallOption) (*GetTargetStatsResponse, error)
+	// GetCreatorStats 作者某一天和这个月收到的打赏
+	GetCreatorStats(ctx context.Context, in *GetCreatorStatsRequest, opts ...grpc.CallOption) (*GetCreatorStatsResponse, error)
+	// TopSupporters 给作者打赏最多的人
+	TopSupporters(ctx context.Context, in *TopSupportersRequest, opts ...grpc.CallOption) (*TopSupportersResponse, error)
+	// 平台抽成规则，给运营后台用
+	SaveFeeRule(ctx context.Context, in *SaveFeeRuleRequest, opts ...grpc.CallOption) (*SaveFeeRuleResponse, error)
+	GetFeeRule(ctx context.Context, in *GetFeeRuleRequest, opts ...grpc.CallOption) (*GetFeeRuleResponse, error)
+	ListFeeRules(ctx context.Context, in *ListFeeRulesRequest, opts ...grpc.CallOption) (*ListFeeRulesResponse, error)
+	// 付费会员，按月付费
+	// SaveMemberPlan 作者创建或者修改自己的会员方案
+	SaveMemberPlan(ctx context.Context, in *SaveMemberPlanRequest, opts ...grpc.CallOption) (*SaveMemberPlanResponse, error)
+	ListMemberPlans(ctx context.Context, in *ListMemberPlansRequest, opts ...grpc.CallOption) (*ListMemberPlansResponse, error)
+	// Subscribe 订阅，返回要付钱的订单
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (*SubscribeResponse, error)
+	GetMemberOrder(ctx context.Context, in *GetMemberOrderRequest, opts ...grpc.CallOption) (*GetMemberOrderResponse, error)
+	GetSubscription(ctx context.Context, in *GetSubscriptionRequest, opts ...grpc.CallOption) (*GetSubscriptionResponse, error)
+	// CancelSubscription 取消之后不会再续费，这个周期结束之前还是会员
+	CancelSubscription(ctx context.Context, in *CancelSubscriptionRequest, opts ...grpc.CallOption) (*CancelSubscriptionResponse, error)
+	// CheckEntitlement 现在是不是作者的会员
+	CheckEntitlement(ctx context.Context, in *CheckEntitlementRequest, opts ...grpc.CallOption) (*CheckEntitlementResponse, error)
 }
 
 type rewardServiceClient struct {
@@ -57,12 +93,152 @@ func (c *rewardServiceClient) GetReward(ctx context.Context, in *GetRewardReques
 	return out, nil
 }
 
+func (c *rewardServiceClient) GetTargetStats(ctx context.Context, in *GetTargetStatsRequest, opts ...grpc.CallOption) (*GetTargetStatsResponse, error) {
+	out := new(GetTargetStatsResponse)
+	err := c.cc.Invoke(ctx, RewardService_GetTargetStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rewardServiceClient) GetCreatorStats(ctx context.Context, in *GetCreatorStatsRequest, opts ...grpc.CallOption) (*GetCreatorStatsResponse, error) {
+	out := new(GetCreatorStatsResponse)
+	err := c.cc.Invoke(ctx, RewardService_GetCreatorStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rewardServiceClient) TopSupporters(ctx context.Context, in *TopSupportersRequest, opts ...grpc.CallOption) (*TopSupportersResponse, error) {
+	out := new(TopSupportersResponse)
+	err := c.cc.Invoke(ctx, RewardService_TopSupporters_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rewardServiceClient) SaveFeeRule(ctx context.Context, in *SaveFeeRuleRequest, opts ...grpc.CallOption) (*SaveFeeRuleResponse, error) {
+	out := new(SaveFeeRuleResponse)
+	err := c.cc.Invoke(ctx, RewardService_SaveFeeRule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rewardServiceClient) GetFeeRule(ctx context.Context, in *GetFeeRuleRequest, opts ...grpc.CallOption) (*GetFeeRuleResponse, error) {
+	out := new(GetFeeRuleResponse)
+	err := c.cc.Invoke(ctx, RewardService_GetFeeRule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rewardServiceClient) ListFeeRules(ctx context.Context, in *ListFeeRulesRequest, opts ...grpc.CallOption) (*ListFeeRulesResponse, error) {
+	out := new(ListFeeRulesResponse)
+	err := c.cc.Invoke(ctx, RewardService_ListFeeRules_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rewardServiceClient) SaveMemberPlan(ctx context.Context, in *SaveMemberPlanRequest, opts ...grpc.CallOption) (*SaveMemberPlanResponse, error) {
+	out := new(SaveMemberPlanResponse)
+	err := c.cc.Invoke(ctx, RewardService_SaveMemberPlan_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rewardServiceClient) ListMemberPlans(ctx context.Context, in *ListMemberPlansRequest, opts ...grpc.CallOption) (*ListMemberPlansResponse, error) {
+	out := new(ListMemberPlansResponse)
+	err := c.cc.Invoke(ctx, RewardService_ListMemberPlans_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rewardServiceClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (*SubscribeResponse, error) {
+	out := new(SubscribeResponse)
+	err := c.cc.Invoke(ctx, RewardService_Subscribe_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rewardServiceClient) GetMemberOrder(ctx context.Context, in *GetMemberOrderRequest, opts ...grpc.CallOption) (*GetMemberOrderResponse, error) {
+	out := new(GetMemberOrderResponse)
+	err := c.cc.Invoke(ctx, RewardService_GetMemberOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rewardServiceClient) GetSubscription(ctx context.Context, in *GetSubscriptionRequest, opts ...grpc.CallOption) (*GetSubscriptionResponse, error) {
+	out := new(GetSubscriptionResponse)
+	err := c.cc.Invoke(ctx, RewardService_GetSubscription_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rewardServiceClient) CancelSubscription(ctx context.Context, in *CancelSubscriptionRequest, opts ...grpc.CallOption) (*CancelSubscriptionResponse, error) {
+	out := new(CancelSubscriptionResponse)
+	err := c.cc.Invoke(ctx, RewardService_CancelSubscription_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rewardServiceClient) CheckEntitlement(ctx context.Context, in *CheckEntitlementRequest, opts ...grpc.CallOption) (*CheckEntitlementResponse, error) {
+	out := new(CheckEntitlementResponse)
+	err := c.cc.Invoke(ctx, RewardService_CheckEntitlement_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RewardServiceServer is the server API for RewardService service.
 // All implementations must embed UnimplementedRewardServiceServer
 // for forward compatibility
 type RewardServiceServer interface {
 	PreReward(context.Context, *PreRewardRequest) (*PreRewardResponse, error)
 	GetReward(context.Context, *GetRewardRequest) (*GetRewardResponse, error)
+	// 统计和排行榜，只统计支付成功的，不同币种分开统计
+	// GetTargetStats 被打赏的东西的总数和打赏最多的人
+	GetTargetStats(context.Context, *GetTargetStatsRequest) (*GetTargetStatsResponse, error)
+	// GetCreatorStats 作者某一天和这个月收到的打赏
+	GetCreatorStats(context.Context, *GetCreatorStatsRequest) (*GetCreatorStatsResponse, error)
+	// TopSupporters 给作者打赏最多的人
+	TopSupporters(context.Context, *TopSupportersRequest) (*TopSupportersResponse, error)
+	// 平台抽成规则，给运营后台用
+	SaveFeeRule(context.Context, *SaveFeeRuleRequest) (*SaveFeeRuleResponse, error)
+	GetFeeRule(context.Context, *GetFeeRuleRequest) (*GetFeeRuleResponse, error)
+	ListFeeRules(context.Context, *ListFeeRulesRequest) (*ListFeeRulesResponse, error)
+	// 付费会员，按月付费
+	// SaveMemberPlan 作者创建或者修改自己的会员方案
+	SaveMemberPlan(context.Context, *SaveMemberPlanRequest) (*SaveMemberPlanResponse, error)
+	ListMemberPlans(context.Context, *ListMemberPlansRequest) (*ListMemberPlansResponse, error)
+	// Subscribe 订阅，返回要付钱的订单
+	Subscribe(context.Context, *SubscribeRequest) (*SubscribeResponse, error)
+	GetMemberOrder(context.Context, *GetMemberOrderRequest) (*GetMemberOrderResponse, error)
+	GetSubscription(context.Context, *GetSubscriptionRequest) (*GetSubscriptionResponse, error)
+	// CancelSubscription 取消之后不会再续费，这个周期结束之前还是会员
+	CancelSubscription(context.Context, *CancelSubscriptionRequest) (*CancelSubscriptionResponse, error)
+	// CheckEntitlement 现在是不是作者的会员
+	CheckEntitlement(context.Context, *CheckEntitlementRequest) (*CheckEntitlementResponse, error)
 	mustEmbedUnimplementedRewardServiceServer()
 }
 
@@ -76,6 +252,45 @@ func (UnimplementedRewardServiceServer) PreReward(context.Context, *PreRewardReq
 func (UnimplementedRewardServiceServer) GetReward(context.Context, *GetRewardRequest) (*GetRewardResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetReward not implemented")
 }
+func (UnimplementedRewardServiceServer) GetTargetStats(context.Context, *GetTargetStatsRequest) (*GetTargetStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTargetStats not implemented")
+}
+func (UnimplementedRewardServiceServer) GetCreatorStats(context.Context, *GetCreatorStatsRequest) (*GetCreatorStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCreatorStats not implemented")
+}
+func (UnimplementedRewardServiceServer) TopSupporters(context.Context, *TopSupportersRequest) (*TopSupportersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TopSupporters not implemented")
+}
+func (UnimplementedRewardServiceServer) SaveFeeRule(context.Context, *SaveFeeRuleRequest) (*SaveFeeRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveFeeRule not implemented")
+}
+func (UnimplementedRewardServiceServer) GetFeeRule(context.Context, *GetFeeRuleRequest) (*GetFeeRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFeeRule not implemented")
+}
+func (UnimplementedRewardServiceServer) ListFeeRules(context.Context, *ListFeeRulesRequest) (*ListFeeRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFeeRules not implemented")
+}
+func (UnimplementedRewardServiceServer) SaveMemberPlan(context.Context, *SaveMemberPlanRequest) (*SaveMemberPlanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveMemberPlan not implemented")
+}
+func (UnimplementedRewardServiceServer) ListMemberPlans(context.Context, *ListMemberPlansRequest) (*ListMemberPlansResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMemberPlans not implemented")
+}
+func (UnimplementedRewardServiceServer) Subscribe(context.Context, *SubscribeRequest) (*SubscribeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedRewardServiceServer) GetMemberOrder(context.Context, *GetMemberOrderRequest) (*GetMemberOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMemberOrder not implemented")
+}
+func (UnimplementedRewardServiceServer) GetSubscription(context.Context, *GetSubscriptionRequest) (*GetSubscriptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSubscription not implemented")
+}
+func (UnimplementedRewardServiceServer) CancelSubscription(context.Context, *CancelSubscriptionRequest) (*CancelSubscriptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelSubscription not implemented")
+}
+func (UnimplementedRewardServiceServer) CheckEntitlement(context.Context, *CheckEntitlementRequest) (*CheckEntitlementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckEntitlement not implemented")
+}
 func (UnimplementedRewardServiceServer) mustEmbedUnimplementedRewardServiceServer() {}
 
 // UnsafeRewardServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -125,6 +340,240 @@ func _RewardService_GetReward_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RewardService_GetTargetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTargetStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RewardServiceServer).GetTargetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RewardService_GetTargetStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RewardServiceServer).GetTargetStats(ctx, req.(*GetTargetStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RewardService_GetCreatorStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCreatorStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RewardServiceServer).GetCreatorStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RewardService_GetCreatorStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RewardServiceServer).GetCreatorStats(ctx, req.(*GetCreatorStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RewardService_TopSupporters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopSupportersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RewardServiceServer).TopSupporters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RewardService_TopSupporters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RewardServiceServer).TopSupporters(ctx, req.(*TopSupportersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RewardService_SaveFeeRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveFeeRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RewardServiceServer).SaveFeeRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RewardService_SaveFeeRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RewardServiceServer).SaveFeeRule(ctx, req.(*SaveFeeRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RewardService_GetFeeRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFeeRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RewardServiceServer).GetFeeRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RewardService_GetFeeRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RewardServiceServer).GetFeeRule(ctx, req.(*GetFeeRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RewardService_ListFeeRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFeeRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RewardServiceServer).ListFeeRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RewardService_ListFeeRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RewardServiceServer).ListFeeRules(ctx, req.(*ListFeeRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RewardService_SaveMemberPlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveMemberPlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RewardServiceServer).SaveMemberPlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RewardService_SaveMemberPlan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RewardServiceServer).SaveMemberPlan(ctx, req.(*SaveMemberPlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RewardService_ListMemberPlans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMemberPlansRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RewardServiceServer).ListMemberPlans(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RewardService_ListMemberPlans_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RewardServiceServer).ListMemberPlans(ctx, req.(*ListMemberPlansRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RewardService_Subscribe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubscribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RewardServiceServer).Subscribe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RewardService_Subscribe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RewardServiceServer).Subscribe(ctx, req.(*SubscribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RewardService_GetMemberOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMemberOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RewardServiceServer).GetMemberOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RewardService_GetMemberOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RewardServiceServer).GetMemberOrder(ctx, req.(*GetMemberOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RewardService_GetSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RewardServiceServer).GetSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RewardService_GetSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RewardServiceServer).GetSubscription(ctx, req.(*GetSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RewardService_CancelSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RewardServiceServer).CancelSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RewardService_CancelSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RewardServiceServer).CancelSubscription(ctx, req.(*CancelSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RewardService_CheckEntitlement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckEntitlementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RewardServiceServer).CheckEntitlement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RewardService_CheckEntitlement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RewardServiceServer).CheckEntitlement(ctx, req.(*CheckEntitlementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // RewardService_ServiceDesc is the grpc.ServiceDesc for RewardService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -140,6 +589,58 @@ var RewardService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetReward",
 			Handler:    _RewardService_GetReward_Handler,
 		},
+		{
+			MethodName: "GetTargetStats",
+			Handler:    _RewardService_GetTargetStats_Handler,
+		},
+		{
+			MethodName: "GetCreatorStats",
+			Handler:    _RewardService_GetCreatorStats_Handler,
+		},
+		{
+			MethodName: "TopSupporters",
+			Handler:    _RewardService_TopSupporters_Handler,
+		},
+		{
+			MethodName: "SaveFeeRule",
+			Handler:    _RewardService_SaveFeeRule_Handler,
+		},
+		{
+			MethodName: "GetFeeRule",
+			Handler:    _RewardService_GetFeeRule_Handler,
+		},
+		{
+			MethodName: "ListFeeRules",
+			Handler:    _RewardService_ListFeeRules_Handler,
+		},
+		{
+			MethodName: "SaveMemberPlan",
+			Handler:    _RewardService_SaveMemberPlan_Handler,
+		},
+		{
+			MethodName: "ListMemberPlans",
+			Handler:    _RewardService_ListMemberPlans_Handler,
+		},
+		{
+			MethodName: "Subscribe",
+			Handler:    _RewardService_Subscribe_Handler,
+		},
+		{
+			MethodName: "GetMemberOrder",
+			Handler:    _RewardService_GetMemberOrder_Handler,
+		},
+		{
+			MethodName: "GetSubscription",
+			Handler:    _RewardService_GetSubscription_Handler,
+		},
+		{
+			MethodName: "CancelSubscription",
+			Handler:    _RewardService_CancelSubscription_Handler,
+		},
+		{
+			MethodName: "CheckEntitlement",
+			Handler:    _RewardService_CheckEntitlement_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "reward/v1/reward.proto",
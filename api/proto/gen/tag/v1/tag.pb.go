@@ -498,6 +498,156 @@ func (x *GetBizTagsResponse) GetTags() []*Tag {
 	return nil
 }
 
+type BatchGetBizTagsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Biz    string  `protobuf:"bytes,1,opt,name=biz,proto3" json:"biz,omitempty"`
+	BizIds []int64 `protobuf:"varint,2,rep,packed,name=biz_ids,json=bizIds,proto3" json:"biz_ids,omitempty"`
+}
+
+func (x *BatchGetBizTagsRequest) Reset() {
+	*x = BatchGetBizTagsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tag_v1_tag_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchGetBizTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetBizTagsRequest) ProtoMessage() {}
+
+func (x *BatchGetBizTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tag_v1_tag_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetBizTagsRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetBizTagsRequest) Descriptor() ([]byte, []int) {
+	return file_tag_v1_tag_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BatchGetBizTagsRequest) GetBiz() string {
+	if x != nil {
+		return x.Biz
+	}
+	return ""
+}
+
+func (x *BatchGetBizTagsRequest) GetBizIds() []int64 {
+	if x != nil {
+		return x.BizIds
+	}
+	return nil
+}
+
+type BizTags struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tags []*Tag `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+}
+
+func (x *BizTags) Reset() {
+	*x = BizTags{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tag_v1_tag_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BizTags) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BizTags) ProtoMessage() {}
+
+func (x *BizTags) ProtoReflect() protoreflect.Message {
+	mi := &file_tag_v1_tag_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BizTags.ProtoReflect.Descriptor instead.
+func (*BizTags) Descriptor() ([]byte, []int) {
+	return file_tag_v1_tag_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BizTags) GetTags() []*Tag {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type BatchGetBizTagsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// key 是 biz_id，没有标签的不在里面
+	BizTags map[int64]*BizTags `protobuf:"bytes,1,rep,name=biz_tags,json=bizTags,proto3" json:"biz_tags,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *BatchGetBizTagsResponse) Reset() {
+	*x = BatchGetBizTagsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tag_v1_tag_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchGetBizTagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetBizTagsResponse) ProtoMessage() {}
+
+func (x *BatchGetBizTagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tag_v1_tag_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetBizTagsResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetBizTagsResponse) Descriptor() ([]byte, []int) {
+	return file_tag_v1_tag_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchGetBizTagsResponse) GetBizTags() map[int64]*BizTags {
+	if x != nil {
+		return x.BizTags
+	}
+	return nil
+}
+
 var File_tag_v1_tag_proto protoreflect.FileDescriptor
 
 var file_tag_v1_tag_proto_rawDesc = []byte{
@@ -534,34 +684,58 @@ var file_tag_v1_tag_proto_rawDesc = []byte{
 	0x52, 0x03, 0x75, 0x69, 0x64, 0x22, 0x35, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x42, 0x69, 0x7a, 0x54,
 	0x61, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x04, 0x74,
 	0x61, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x74, 0x61, 0x67, 0x2e,
-	0x76, 0x31, 0x2e, 0x54, 0x61, 0x67, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x32, 0x94, 0x02, 0x0a,
-	0x0a, 0x54, 0x61, 0x67, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x40, 0x0a, 0x09, 0x43,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x61, 0x67, 0x12, 0x18, 0x2e, 0x74, 0x61, 0x67, 0x2e, 0x76,
-	0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x61, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x19, 0x2e, 0x74, 0x61, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61,
-	0x74, 0x65, 0x54, 0x61, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a,
-	0x0a, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x54, 0x61, 0x67, 0x73, 0x12, 0x19, 0x2e, 0x74, 0x61,
-	0x67, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x54, 0x61, 0x67, 0x73, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x74, 0x61, 0x67, 0x2e, 0x76, 0x31, 0x2e,
-	0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x54, 0x61, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x3a, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x54, 0x61, 0x67, 0x73, 0x12, 0x16, 0x2e,
-	0x74, 0x61, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x61, 0x67, 0x73, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x74, 0x61, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x47,
-	0x65, 0x74, 0x54, 0x61, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43,
-	0x0a, 0x0a, 0x47, 0x65, 0x74, 0x42, 0x69, 0x7a, 0x54, 0x61, 0x67, 0x73, 0x12, 0x19, 0x2e, 0x74,
-	0x61, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x69, 0x7a, 0x54, 0x61, 0x67, 0x73,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x74, 0x61, 0x67, 0x2e, 0x76, 0x31,
-	0x2e, 0x47, 0x65, 0x74, 0x42, 0x69, 0x7a, 0x54, 0x61, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x42, 0x8e, 0x01, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x2e, 0x74, 0x61, 0x67, 0x2e,
-	0x76, 0x31, 0x42, 0x08, 0x54, 0x61, 0x67, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x3d,
-	0x67, 0x69, 0x74, 0x65, 0x65, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x65, 0x65, 0x6b, 0x62, 0x61,
-	0x6e, 0x67, 0x2f, 0x62, 0x61, 0x73, 0x69, 0x63, 0x2d, 0x67, 0x6f, 0x2f, 0x77, 0x65, 0x62, 0x6f,
-	0x6f, 0x6b, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x65, 0x6e,
-	0x2f, 0x74, 0x61, 0x67, 0x2f, 0x76, 0x31, 0x3b, 0x74, 0x61, 0x67, 0x76, 0x31, 0xa2, 0x02, 0x03,
-	0x54, 0x58, 0x58, 0xaa, 0x02, 0x06, 0x54, 0x61, 0x67, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x06, 0x54,
-	0x61, 0x67, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x12, 0x54, 0x61, 0x67, 0x5c, 0x56, 0x31, 0x5c, 0x47,
-	0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x07, 0x54, 0x61, 0x67,
-	0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x76, 0x31, 0x2e, 0x54, 0x61, 0x67, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x22, 0x43, 0x0a, 0x16,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x42, 0x69, 0x7a, 0x54, 0x61, 0x67, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x62, 0x69, 0x7a, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x62, 0x69, 0x7a, 0x12, 0x17, 0x0a, 0x07, 0x62, 0x69, 0x7a, 0x5f,
+	0x69, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x03, 0x52, 0x06, 0x62, 0x69, 0x7a, 0x49, 0x64,
+	0x73, 0x22, 0x2a, 0x0a, 0x07, 0x42, 0x69, 0x7a, 0x54, 0x61, 0x67, 0x73, 0x12, 0x1f, 0x0a, 0x04,
+	0x74, 0x61, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x74, 0x61, 0x67,
+	0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x67, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x22, 0xaf, 0x01,
+	0x0a, 0x17, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x42, 0x69, 0x7a, 0x54, 0x61, 0x67,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x08, 0x62, 0x69, 0x7a,
+	0x5f, 0x74, 0x61, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x74, 0x61,
+	0x67, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x42, 0x69, 0x7a,
+	0x54, 0x61, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x42, 0x69, 0x7a,
+	0x54, 0x61, 0x67, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x62, 0x69, 0x7a, 0x54, 0x61,
+	0x67, 0x73, 0x1a, 0x4b, 0x0a, 0x0c, 0x42, 0x69, 0x7a, 0x54, 0x61, 0x67, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x25, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x74, 0x61, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x69, 0x7a,
+	0x54, 0x61, 0x67, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x32,
+	0xe8, 0x02, 0x0a, 0x0a, 0x54, 0x61, 0x67, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x40,
+	0x0a, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x61, 0x67, 0x12, 0x18, 0x2e, 0x74, 0x61,
+	0x67, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x61, 0x67, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x74, 0x61, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x61, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x43, 0x0a, 0x0a, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x54, 0x61, 0x67, 0x73, 0x12, 0x19,
+	0x2e, 0x74, 0x61, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x54, 0x61,
+	0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x74, 0x61, 0x67, 0x2e,
+	0x76, 0x31, 0x2e, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x54, 0x61, 0x67, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x54, 0x61, 0x67, 0x73,
+	0x12, 0x16, 0x2e, 0x74, 0x61, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x61, 0x67,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x74, 0x61, 0x67, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x61, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x43, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x42, 0x69, 0x7a, 0x54, 0x61, 0x67, 0x73, 0x12,
+	0x19, 0x2e, 0x74, 0x61, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x69, 0x7a, 0x54,
+	0x61, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x74, 0x61, 0x67,
+	0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x69, 0x7a, 0x54, 0x61, 0x67, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x52, 0x0a, 0x0f, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47,
+	0x65, 0x74, 0x42, 0x69, 0x7a, 0x54, 0x61, 0x67, 0x73, 0x12, 0x1e, 0x2e, 0x74, 0x61, 0x67, 0x2e,
+	0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x42, 0x69, 0x7a, 0x54, 0x61,
+	0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x74, 0x61, 0x67, 0x2e,
+	0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x42, 0x69, 0x7a, 0x54, 0x61,
+	0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x8e, 0x01, 0x0a, 0x0a, 0x63,
+	0x6f, 0x6d, 0x2e, 0x74, 0x61, 0x67, 0x2e, 0x76, 0x31, 0x42, 0x08, 0x54, 0x61, 0x67, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x3d, 0x67, 0x69, 0x74, 0x65, 0x65, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x67, 0x65, 0x65, 0x6b, 0x62, 0x61, 0x6e, 0x67, 0x2f, 0x62, 0x61, 0x73, 0x69, 0x63, 0x2d,
+	0x67, 0x6f, 0x2f, 0x77, 0x65, 0x62, 0x6f, 0x6f, 0x6b, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x74, 0x61, 0x67, 0x2f, 0x76, 0x31, 0x3b, 0x74,
+	0x61, 0x67, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x54, 0x58, 0x58, 0xaa, 0x02, 0x06, 0x54, 0x61, 0x67,
+	0x2e, 0x56, 0x31, 0xca, 0x02, 0x06, 0x54, 0x61, 0x67, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x12, 0x54,
+	0x61, 0x67, 0x5c, 0x56, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0xea, 0x02, 0x07, 0x54, 0x61, 0x67, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -576,35 +750,44 @@ func file_tag_v1_tag_proto_rawDescGZIP() []byte {
 	return file_tag_v1_tag_proto_rawDescData
 }
 
-var file_tag_v1_tag_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_tag_v1_tag_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_tag_v1_tag_proto_goTypes = []interface{}{
-	(*Tag)(nil),                // 0: tag.v1.Tag
-	(*AttachTagsRequest)(nil),  // 1: tag.v1.AttachTagsRequest
-	(*AttachTagsResponse)(nil), // 2: tag.v1.AttachTagsResponse
-	(*CreateTagRequest)(nil),   // 3: tag.v1.CreateTagRequest
-	(*CreateTagResponse)(nil),  // 4: tag.v1.CreateTagResponse
-	(*GetTagsRequest)(nil),     // 5: tag.v1.GetTagsRequest
-	(*GetTagsResponse)(nil),    // 6: tag.v1.GetTagsResponse
-	(*GetBizTagsRequest)(nil),  // 7: tag.v1.GetBizTagsRequest
-	(*GetBizTagsResponse)(nil), // 8: tag.v1.GetBizTagsResponse
+	(*Tag)(nil),                     // 0: tag.v1.Tag
+	(*AttachTagsRequest)(nil),       // 1: tag.v1.AttachTagsRequest
+	(*AttachTagsResponse)(nil),      // 2: tag.v1.AttachTagsResponse
+	(*CreateTagRequest)(nil),        // 3: tag.v1.CreateTagRequest
+	(*CreateTagResponse)(nil),       // 4: tag.v1.CreateTagResponse
+	(*GetTagsRequest)(nil),          // 5: tag.v1.GetTagsRequest
+	(*GetTagsResponse)(nil),         // 6: tag.v1.GetTagsResponse
+	(*GetBizTagsRequest)(nil),       // 7: tag.v1.GetBizTagsRequest
+	(*GetBizTagsResponse)(nil),      // 8: tag.v1.GetBizTagsResponse
+	(*BatchGetBizTagsRequest)(nil),  // 9: tag.v1.BatchGetBizTagsRequest
+	(*BizTags)(nil),                 // 10: tag.v1.BizTags
+	(*BatchGetBizTagsResponse)(nil), // 11: tag.v1.BatchGetBizTagsResponse
+	nil,                             // 12: tag.v1.BatchGetBizTagsResponse.BizTagsEntry
 }
 var file_tag_v1_tag_proto_depIdxs = []int32{
-	0, // 0: tag.v1.CreateTagResponse.tag:type_name -> tag.v1.Tag
-	0, // 1: tag.v1.GetTagsResponse.tag:type_name -> tag.v1.Tag
-	0, // 2: tag.v1.GetBizTagsResponse.tags:type_name -> tag.v1.Tag
-	3, // 3: tag.v1.TagService.CreateTag:input_type -> tag.v1.CreateTagRequest
-	1, // 4: tag.v1.TagService.AttachTags:input_type -> tag.v1.AttachTagsRequest
-	5, // 5: tag.v1.TagService.GetTags:input_type -> tag.v1.GetTagsRequest
-	7, // 6: tag.v1.TagService.GetBizTags:input_type -> tag.v1.GetBizTagsRequest
-	4, // 7: tag.v1.TagService.CreateTag:output_type -> tag.v1.CreateTagResponse
-	2, // 8: tag.v1.TagService.AttachTags:output_type -> tag.v1.AttachTagsResponse
-	6, // 9: tag.v1.TagService.GetTags:output_type -> tag.v1.GetTagsResponse
-	8, // 10: tag.v1.TagService.GetBizTags:output_type -> tag.v1.GetBizTagsResponse
-	7, // [7:11] is the sub-list for method output_type
-	3, // [3:7] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	0,  // 0: tag.v1.CreateTagResponse.tag:type_name -> tag.v1.Tag
+	0,  // 1: tag.v1.GetTagsResponse.tag:type_name -> tag.v1.Tag
+	0,  // 2: tag.v1.GetBizTagsResponse.tags:type_name -> tag.v1.Tag
+	0,  // 3: tag.v1.BizTags.tags:type_name -> tag.v1.Tag
+	12, // 4: tag.v1.BatchGetBizTagsResponse.biz_tags:type_name -> tag.v1.BatchGetBizTagsResponse.BizTagsEntry
+	10, // 5: tag.v1.BatchGetBizTagsResponse.BizTagsEntry.value:type_name -> tag.v1.BizTags
+	3,  // 6: tag.v1.TagService.CreateTag:input_type -> tag.v1.CreateTagRequest
+	1,  // 7: tag.v1.TagService.AttachTags:input_type -> tag.v1.AttachTagsRequest
+	5,  // 8: tag.v1.TagService.GetTags:input_type -> tag.v1.GetTagsRequest
+	7,  // 9: tag.v1.TagService.GetBizTags:input_type -> tag.v1.GetBizTagsRequest
+	9,  // 10: tag.v1.TagService.BatchGetBizTags:input_type -> tag.v1.BatchGetBizTagsRequest
+	4,  // 11: tag.v1.TagService.CreateTag:output_type -> tag.v1.CreateTagResponse
+	2,  // 12: tag.v1.TagService.AttachTags:output_type -> tag.v1.AttachTagsResponse
+	6,  // 13: tag.v1.TagService.GetTags:output_type -> tag.v1.GetTagsResponse
+	8,  // 14: tag.v1.TagService.GetBizTags:output_type -> tag.v1.GetBizTagsResponse
+	11, // 15: tag.v1.TagService.BatchGetBizTags:output_type -> tag.v1.BatchGetBizTagsResponse
+	11, // [11:16] is the sub-list for method output_type
+	6,  // [6:11] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_tag_v1_tag_proto_init() }
@@ -721,6 +904,42 @@ func file_tag_v1_tag_proto_init() {
 				return nil
 			}
 		}
+		file_tag_v1_tag_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchGetBizTagsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tag_v1_tag_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BizTags); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tag_v1_tag_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchGetBizTagsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -728,7 +947,7 @@ func file_tag_v1_tag_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_tag_v1_tag_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -2,6 +2,7 @@ package main
 
 import (
 	"gitee.com/geekbang/basic-go/webook/internal/events"
+	"gitee.com/geekbang/basic-go/webook/internal/job"
 	"github.com/gin-gonic/gin"
 	"github.com/robfig/cron/v3"
 )
@@ -10,4 +11,5 @@ type App struct {
 	server    *gin.Engine
 	consumers []events.Consumer
 	cron      *cron.Cron
+	scheduler *job.Scheduler
 }
@@ -10,6 +10,16 @@ type Article struct {
 	Status  ArticleStatus
 	Ctime   time.Time
 	Utime   time.Time
+	// PublishAt 定时发表的时间，只有 ArticleStatusScheduled 的时候才有意义
+	PublishAt time.Time
+	// Series 所属的系列和前后篇，只有读者查看的时候才有
+	Series SeriesNav
+	// Rendered 发表的时候从 Content 渲染出来的结果，草稿没有
+	Rendered RenderedContent
+	// Collaborators 合作者。读者查看的时候只有已经接受邀请的
+	Collaborators []ArticleCollaborator
+	// MembersOnly 只有作者的付费会员才能看
+	MembersOnly bool
 	// 12 周作业
 	// 这种做法就是把点赞收藏的数据，看做是 Article 本身的一部分
 	//
@@ -17,6 +27,10 @@ type Article struct {
 }
 
 func (a Article) Abstract() string {
+	// 发表过的文章，用渲染时候算好的纯文本摘要，不会带上 Markdown 标记
+	if a.Rendered.Abstract != "" {
+		return a.Rendered.Abstract
+	}
 	str := []rune(a.Content)
 	// 只取部分作为摘要
 	if len(str) > 128 {
@@ -40,8 +54,33 @@ const (
 	ArticleStatusPublished
 	// ArticleStatusPrivate 仅自己可见
 	ArticleStatusPrivate
+	// ArticleStatusScheduled 等待定时发表
+	ArticleStatusScheduled
+	// ArticleStatusReviewing 已经提交审核，审核通过之后才会发表
+	ArticleStatusReviewing
 )
 
+// RenderedContent Markdown 渲染之后的内容
+type RenderedContent struct {
+	// HTML 已经过滤掉了危险的标签和属性，可以直接展示
+	HTML string
+	// Toc 按照标题在文中出现的顺序排列
+	Toc []TocItem
+	// Abstract 纯文本摘要
+	Abstract string
+	// ReadingMinutes 预计阅读时间，单位是分钟
+	ReadingMinutes int
+}
+
+// TocItem 目录里面的一项，对应文章里面的一个标题
+type TocItem struct {
+	// Level 标题的级别，1 到 6
+	Level int
+	// Anchor 标题在 HTML 里面的 id，用来跳转
+	Anchor string
+	Title  string
+}
+
 type Author struct {
 	Id   int64
 	Name string
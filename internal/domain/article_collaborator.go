@@ -0,0 +1,74 @@
+package domain
+
+import "time"
+
+// ArticleCollaborator 文章的合作者。创作者本人不算在里面
+type ArticleCollaborator struct {
+	ArticleId int64
+	// User 被邀请的人，Name 只有读者查看的时候才有
+	User      Author
+	Role      CollaboratorRole
+	Status    CollaboratorStatus
+	InviterId int64
+	Ctime     time.Time
+	Utime     time.Time
+}
+
+type CollaboratorRole uint8
+
+func (r CollaboratorRole) ToUint8() uint8 {
+	return uint8(r)
+}
+
+func (r CollaboratorRole) Valid() bool {
+	return r == CollaboratorRoleEditor || r == CollaboratorRoleReviewer
+}
+
+const (
+	CollaboratorRoleUnknown CollaboratorRole = iota
+	// CollaboratorRoleEditor 编辑，可以修改、发表和撤回
+	CollaboratorRoleEditor
+	// CollaboratorRoleReviewer 审阅，只能看草稿和历史版本
+	CollaboratorRoleReviewer
+)
+
+type CollaboratorStatus uint8
+
+func (s CollaboratorStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+const (
+	CollaboratorStatusUnknown CollaboratorStatus = iota
+	// CollaboratorStatusInvited 已经邀请，但是对方还没有接受
+	CollaboratorStatusInvited
+	// CollaboratorStatusAccepted 已经接受邀请
+	CollaboratorStatusAccepted
+)
+
+// CanView 创作者本人和已经接受邀请的合作者都可以看草稿
+func (a Article) CanView(uid int64) bool {
+	if a.Author.Id == uid {
+		return true
+	}
+	for _, c := range a.Collaborators {
+		if c.User.Id == uid && c.Status == CollaboratorStatusAccepted {
+			return true
+		}
+	}
+	return false
+}
+
+// CanEdit 只有创作者本人和编辑可以修改
+func (a Article) CanEdit(uid int64) bool {
+	if a.Author.Id == uid {
+		return true
+	}
+	for _, c := range a.Collaborators {
+		if c.User.Id == uid && c.Status == CollaboratorStatusAccepted &&
+			c.Role == CollaboratorRoleEditor {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidCursor = errors.New("非法的分页游标")
+
+// ArticleCursor 文章列表的分页游标，指向上一页的最后一篇文章。
+// 列表按照 utime 倒序，utime 相同的按照 id 倒序，
+// 下一页就是排在这篇文章后面的那些
+type ArticleCursor struct {
+	// Utime 毫秒数
+	Utime int64
+	Id    int64
+}
+
+// IsZero 零值代表第一页
+func (c ArticleCursor) IsZero() bool {
+	return c.Utime == 0 && c.Id == 0
+}
+
+// Encode 编码成对前端不透明的字符串，前端原样传回来就可以
+func (c ArticleCursor) Encode() string {
+	if c.IsZero() {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(
+		[]byte(fmt.Sprintf("%d:%d", c.Utime, c.Id)))
+}
+
+// ParseArticleCursor 空字符串代表第一页
+func ParseArticleCursor(token string) (ArticleCursor, error) {
+	if token == "" {
+		return ArticleCursor{}, nil
+	}
+	val, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return ArticleCursor{}, ErrInvalidCursor
+	}
+	var c ArticleCursor
+	_, err = fmt.Sscanf(string(val), "%d:%d", &c.Utime, &c.Id)
+	if err != nil || c.Utime <= 0 || c.Id < 0 {
+		return ArticleCursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// NextArticleCursor 根据这一页的数据算出下一页的游标。
+// 没有取够一页，说明后面已经没有了，返回零值
+func NextArticleCursor(arts []Article, limit int) ArticleCursor {
+	if len(arts) == 0 || len(arts) < limit {
+		return ArticleCursor{}
+	}
+	last := arts[len(arts)-1]
+	return ArticleCursor{Utime: last.Utime.UnixMilli(), Id: last.Id}
+}
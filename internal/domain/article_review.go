@@ -0,0 +1,75 @@
+package domain
+
+import "time"
+
+// ArticleReview 一篇文章当前的审核单。
+// 一篇文章只有一个审核单，重新提交的时候会覆盖掉上一次提交的内容
+type ArticleReview struct {
+	ArticleId int64
+	AuthorId  int64
+	// ReviewerId 认领了这个审核单的人，还没有人认领的时候是 0
+	ReviewerId int64
+	Status     ReviewStatus
+	// Title 和 Content 是提交审核时候的快照，审核通过之后发表的就是这个快照。
+	// 提交之后作者再修改草稿，不会影响到审核中的内容
+	Title    string
+	Content  string
+	Comments []ReviewComment
+	Ctime    time.Time
+	Utime    time.Time
+}
+
+// ReviewComment 每一次状态变更都会留下一条记录，审核意见可以为空
+type ReviewComment struct {
+	ReviewerId int64
+	// Status 这一次变更之后的状态
+	Status  ReviewStatus
+	Comment string
+	Ctime   time.Time
+}
+
+type ReviewStatus uint8
+
+func (s ReviewStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+const (
+	ReviewStatusUnknown ReviewStatus = iota
+	// ReviewStatusSubmitted 已经提交，等待审核的人认领
+	ReviewStatusSubmitted
+	// ReviewStatusInReview 已经有人认领，正在审核
+	ReviewStatusInReview
+	// ReviewStatusChangesRequested 需要作者修改之后重新提交
+	ReviewStatusChangesRequested
+	// ReviewStatusApproved 审核通过，已经同步到线上库
+	ReviewStatusApproved
+	// ReviewStatusRejected 审核不通过
+	ReviewStatusRejected
+)
+
+// reviewTransitions 合法的状态变更，key 是变更之后的状态，value 是变更之前允许的状态。
+// 审核通过允许从审核通过再来一次，这样同步线上库失败了可以重试
+var reviewTransitions = map[ReviewStatus][]ReviewStatus{
+	ReviewStatusSubmitted: {ReviewStatusUnknown, ReviewStatusChangesRequested,
+		ReviewStatusApproved, ReviewStatusRejected},
+	ReviewStatusInReview:         {ReviewStatusSubmitted},
+	ReviewStatusChangesRequested: {ReviewStatusInReview},
+	ReviewStatusApproved:         {ReviewStatusInReview, ReviewStatusApproved},
+	ReviewStatusRejected:         {ReviewStatusInReview},
+}
+
+// ReviewSourceStatus 变更到 to 之前，审核单可以处于哪些状态
+func ReviewSourceStatus(to ReviewStatus) []ReviewStatus {
+	return reviewTransitions[to]
+}
+
+// CanTransitTo 能不能从 s 变更到 to
+func (s ReviewStatus) CanTransitTo(to ReviewStatus) bool {
+	for _, from := range reviewTransitions[to] {
+		if from == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// ArticleRevision 文章的一个历史版本，每次保存、发表都会生成一个，生成之后就不会再修改
+type ArticleRevision struct {
+	Id        int64
+	ArticleId int64
+	// 产生这个版本的人
+	Author  Author
+	Title   string
+	Content string
+	Status  ArticleStatus
+	Ctime   time.Time
+}
+
+type DiffOp uint8
+
+const (
+	// DiffOpEqual 两边都有
+	DiffOpEqual DiffOp = iota
+	// DiffOpInsert 新版本多出来的
+	DiffOpInsert
+	// DiffOpDelete 新版本删掉的
+	DiffOpDelete
+)
+
+// DiffLine 按行比较的结果
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// ArticleRevisionDiff 两个版本之间的差异
+type ArticleRevisionDiff struct {
+	From    ArticleRevision
+	To      ArticleRevision
+	Title   []DiffLine
+	Content []DiffLine
+}
@@ -11,13 +11,180 @@ type Job struct {
 	// Cron 表达式
 	Expression string
 	Executor   string
+	// Target 远程执行器用来找到执行的服务。
+	// HTTP 执行器是配置好的 endpoint 的名字，gRPC 执行器是注册在 etcd 上的服务名
+	Target     string
 	Cfg        string
 	CancelFunc func()
+
+	// MaxRetries 一次调度里面，执行失败之后最多再重试几次
+	MaxRetries int
+	// Backoff 第一次重试之前等多久，之后每次翻倍
+	Backoff time.Duration
+	// Timeout 每一次执行的超时时间，为 0 就是不限制
+	Timeout time.Duration
+	// MaxFailures 连续失败多少次调度之后进入 dead 状态，不再调度。为 0 就是不限制
+	MaxFailures int
+	// Failures 已经连续失败了多少次调度
+	Failures int
+	// Label 只有带了这个标签的节点才能执行，为空就是哪个节点都可以
+	Label string
+	// Weight 执行的时候占用节点多少容量，为 0 就是 1
+	Weight int64
+	Status JobStatus
+	// Owner 正在执行的节点，没有在执行就是空的
+	Owner string
+	// NextRunAt 数据库里面记录的下一次执行时间
+	NextRunAt time.Time
+	// LogicalDate 下一次执行的逻辑日期，格式是 JobLogicalDateLayout。
+	// 下游任务要等上游在同一个逻辑日期都执行成功了才会执行，
+	// 它的逻辑日期来自上游执行过的那天，而不是自己的下次执行时间
+	LogicalDate    string
+	UpstreamPolicy JobUpstreamPolicy
+	Ctime          time.Time
+	Utime          time.Time
+}
+
+// cronParser 支持秒，也支持 @every 这种写法
+var cronParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour |
+	cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// ValidateExpression 校验 Cron 表达式，用的是和 NextTime 一样的解析器
+func (j Job) ValidateExpression() error {
+	_, err := cronParser.Parse(j.Expression)
+	return err
 }
 
 func (j Job) NextTime() time.Time {
-	c := cron.NewParser(cron.Second | cron.Minute | cron.Hour |
-		cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
-	s, _ := c.Parse(j.Expression)
+	s, _ := cronParser.Parse(j.Expression)
 	return s.Next(time.Now())
 }
+
+// Cost 执行的时候占用节点多少容量
+func (j Job) Cost() int64 {
+	if j.Weight <= 0 {
+		return 1
+	}
+	return j.Weight
+}
+
+// RetryBackoff 第 attempt 次重试之前要等多久，attempt 从 1 开始
+func (j Job) RetryBackoff(attempt int) time.Duration {
+	return j.Backoff << (attempt - 1)
+}
+
+// JobLogicalDateLayout 逻辑日期的格式
+const JobLogicalDateLayout = "20060102"
+
+type JobUpstreamPolicy uint8
+
+func (p JobUpstreamPolicy) ToUint8() uint8 {
+	return uint8(p)
+}
+
+const (
+	// JobUpstreamPolicyBlock 上游失败了就一直等，直到上游补跑成功
+	JobUpstreamPolicyBlock JobUpstreamPolicy = iota
+	// JobUpstreamPolicySkip 上游失败了，这个逻辑日期就跳过，下游的下游也会跟着跳过
+	JobUpstreamPolicySkip
+)
+
+type JobStatus uint8
+
+func (s JobStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+const (
+	// JobStatusWaiting 等待调度
+	JobStatusWaiting JobStatus = iota
+	// JobStatusRunning 已经被某个节点抢占了
+	JobStatusRunning
+	// JobStatusPaused 不再需要调度了
+	JobStatusPaused
+	// JobStatusDead 连续失败太多次，要管理员手动恢复
+	JobStatusDead
+)
+
+// JobRun 任务的一次执行记录，重试也会单独记录一次
+type JobRun struct {
+	Id    int64
+	JobId int64
+	// Node 在哪个节点上执行的
+	Node string
+	// Attempt 这次调度里面的第几次执行，从 0 开始
+	Attempt int
+	Start   time.Time
+	End     time.Time
+	Result  JobRunResult
+	Err     string
+}
+
+type JobRunResult uint8
+
+func (r JobRunResult) ToUint8() uint8 {
+	return uint8(r)
+}
+
+const (
+	JobRunResultUnknown JobRunResult = iota
+	JobRunResultSuccess
+	JobRunResultFailed
+	JobRunResultTimeout
+)
+
+// JobDependency JobId 依赖 UpstreamId
+type JobDependency struct {
+	JobId      int64
+	UpstreamId int64
+}
+
+// JobInstance 任务在某个逻辑日期的最终结果
+type JobInstance struct {
+	JobId       int64
+	LogicalDate string
+	Status      JobInstanceStatus
+	Utime       time.Time
+}
+
+type JobInstanceStatus uint8
+
+func (s JobInstanceStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+const (
+	// JobInstanceStatusUnknown 这个逻辑日期还没有执行
+	JobInstanceStatusUnknown JobInstanceStatus = iota
+	JobInstanceStatusSuccess
+	JobInstanceStatusFailed
+	JobInstanceStatusSkipped
+)
+
+// JobRunGraph 某个逻辑日期里面，有依赖关系的任务的执行情况
+type JobRunGraph struct {
+	LogicalDate string
+	Nodes       []JobRunNode
+	Edges       []JobDependency
+}
+
+type JobRunNode struct {
+	Job    Job
+	Status JobInstanceStatus
+}
+
+// JobNode 调度节点，定时通过心跳上报自己的负载
+type JobNode struct {
+	Name   string
+	Labels []string
+	// Capacity 最多同时执行多少权重的任务
+	Capacity int64
+	// Load 正在执行的任务的权重之和
+	Load  int64
+	Utime time.Time
+}
+
+// Free 还能再执行多少权重的任务
+func (n JobNode) Free() int64 {
+	return n.Capacity - n.Load
+}
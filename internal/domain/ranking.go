@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// DefaultRankingList 全站热榜
+const DefaultRankingList = "global"
+
+// RankingSignal 计算热度可以用到的一项数据
+type RankingSignal uint8
+
+const (
+	RankingSignalRead RankingSignal = iota
+	RankingSignalLike
+	RankingSignalCollect
+)
+
+// RankingSignals 一篇文章计算热度用到的数据
+type RankingSignals struct {
+	ReadCnt    int64
+	LikeCnt    int64
+	CollectCnt int64
+}
+
+// RankingList 一个热榜
+type RankingList struct {
+	// Key 热榜的名字，每个热榜单独缓存
+	Key string
+	// Strategy 用哪个热度算法
+	Strategy string
+	// Tag 只统计打了这个标签的文章，为空就是全站
+	Tag string
+	// Window 只统计这段时间之内更新过的文章
+	Window time.Duration
+	// N 取前多少篇
+	N int
+	// HalfLife 大于 0 就是实时热榜：消费阅读、点赞和收藏的消息，
+	// 直接在 Redis 的 sorted set 里面累加分数，分数每过一个 HalfLife 衰减一半。
+	// 定时任务算出来的结果用来纠正实时累加的偏差
+	HalfLife time.Duration
+}
+
+// Streaming 是不是实时热榜
+func (r RankingList) Streaming() bool {
+	return r.HalfLife > 0
+}
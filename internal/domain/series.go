@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// Series 系列，或者说专栏。一个系列里面的文章是有顺序的
+type Series struct {
+	Id          int64
+	Title       string
+	Description string
+	Author      Author
+	// Articles 按照章节顺序排列。
+	// 创作者看到的是全部文章，读者只能看到已经发表的文章
+	Articles []Article
+	Ctime    time.Time
+	Utime    time.Time
+}
+
+// SeriesNav 读者看文章的时候，所属的系列以及上一篇、下一篇
+type SeriesNav struct {
+	SeriesId    int64
+	SeriesTitle string
+	// Id 为 0 说明没有上一篇
+	Prev SeriesChapter
+	// Id 为 0 说明没有下一篇
+	Next SeriesChapter
+}
+
+// SeriesChapter 系列导航里面的一篇文章，只需要展示链接
+type SeriesChapter struct {
+	Id    int64
+	Title string
+}
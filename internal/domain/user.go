@@ -19,9 +19,25 @@ type User struct {
 
 	WechatInfo WechatInfo
 
+	Role UserRole
+
 	//Addr Address
 }
 
+// UserRole 用户的角色，会放进 JWT 里面
+type UserRole uint8
+
+func (r UserRole) ToUint8() uint8 {
+	return uint8(r)
+}
+
+const (
+	// UserRoleNormal 普通用户
+	UserRoleNormal UserRole = iota
+	// UserRoleAdmin 管理员，可以用 /admin 下面的接口
+	UserRoleAdmin
+)
+
 // TodayIsBirthday 判定今天是不是我的生日
 func (u User) TodayIsBirthday() bool {
 	now := time.Now()
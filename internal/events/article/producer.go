@@ -2,13 +2,25 @@ package article
 
 import (
 	"encoding/json"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
 	"github.com/IBM/sarama"
 )
 
-const TopicReadEvent = "article_read"
+const (
+	TopicReadEvent = "article_read"
+	// TopicReviewEvent 审核单的每一次状态变更
+	TopicReviewEvent = "article_review_event"
+	// topicFeedEvent 和 topicSyncArticle 是 feed 和 search 订阅的，
+	// 只有审核通过的文章才会发过去
+	topicFeedEvent   = "article_feed_event"
+	topicSyncArticle = "sync_article_event"
+)
 
+//go:generate mockgen -source=./producer.go -package=evtmocks -destination=mocks/producer.mock.go Producer
 type Producer interface {
 	ProduceReadEvent(evt ReadEvent) error
+	// ProduceReviewEvent 审核通过的时候，还会通知 feed 和 search
+	ProduceReviewEvent(evt ReviewEvent) error
 }
 
 type ReadEvent struct {
@@ -21,6 +33,36 @@ type BatchReadEvent struct {
 	Uids []int64
 }
 
+type ReviewEvent struct {
+	Aid        int64
+	AuthorId   int64
+	ReviewerId int64
+	// Status 变更之后的状态，对应 domain.ReviewStatus
+	Status  uint8
+	Comment string
+	// Title 和 Content 只有审核通过的时候才有，会员专享的文章没有 Content
+	Title       string
+	Content     string
+	MembersOnly bool
+	// Utime 毫秒数
+	Utime int64
+}
+
+// feedEvent 对应 feed 里面的 ArticleFeedEvent
+type feedEvent struct {
+	Uid int64
+	Aid int64
+}
+
+// syncArticleEvent 对应 search 里面的 ArticleEvent
+type syncArticleEvent struct {
+	Id          int64  `json:"id"`
+	Title       string `json:"title"`
+	Status      int32  `json:"status"`
+	Content     string `json:"content"`
+	MembersOnly bool   `json:"members_only"`
+}
+
 type SaramaSyncProducer struct {
 	producer sarama.SyncProducer
 }
@@ -30,12 +72,41 @@ func NewSaramaSyncProducer(producer sarama.SyncProducer) Producer {
 }
 
 func (s *SaramaSyncProducer) ProduceReadEvent(evt ReadEvent) error {
+	return s.produce(TopicReadEvent, evt)
+}
+
+func (s *SaramaSyncProducer) ProduceReviewEvent(evt ReviewEvent) error {
+	if evt.MembersOnly {
+		// 会员专享的文章，下游（比如说搜索）只能拿到标题，不然不是会员也能看到全文
+		evt.Content = ""
+	}
+	err := s.produce(TopicReviewEvent, evt)
+	if err != nil || evt.Status != domain.ReviewStatusApproved.ToUint8() {
+		return err
+	}
+	err = s.produce(topicFeedEvent, feedEvent{
+		Uid: evt.AuthorId,
+		Aid: evt.Aid,
+	})
+	if err != nil {
+		return err
+	}
+	return s.produce(topicSyncArticle, syncArticleEvent{
+		Id:          evt.Aid,
+		Title:       evt.Title,
+		Status:      int32(domain.ArticleStatusPublished),
+		Content:     evt.Content,
+		MembersOnly: evt.MembersOnly,
+	})
+}
+
+func (s *SaramaSyncProducer) produce(topic string, evt any) error {
 	val, err := json.Marshal(evt)
 	if err != nil {
 		return err
 	}
 	_, _, err = s.producer.SendMessage(&sarama.ProducerMessage{
-		Topic: TopicReadEvent,
+		Topic: topic,
 		Value: sarama.StringEncoder(val),
 	})
 	return err
@@ -0,0 +1,72 @@
+package article
+
+import (
+	"encoding/json"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSaramaSyncProducer_ProduceReviewEvent(t *testing.T) {
+	testCases := []struct {
+		name string
+		evt  ReviewEvent
+
+		wantTopics  []string
+		wantContent string
+	}{
+		{
+			name: "审核通过，通知 feed 和 search",
+			evt: ReviewEvent{Aid: 1, AuthorId: 123, Status: domain.ReviewStatusApproved.ToUint8(),
+				Title: "标题", Content: "内容"},
+			wantTopics:  []string{TopicReviewEvent, topicFeedEvent, topicSyncArticle},
+			wantContent: "内容",
+		},
+		{
+			// 不是会员也能搜到全文的话，会员就白买了
+			name: "会员专享的文章不带内容",
+			evt: ReviewEvent{Aid: 1, AuthorId: 123, Status: domain.ReviewStatusApproved.ToUint8(),
+				Title: "标题", Content: "内容", MembersOnly: true},
+			wantTopics: []string{TopicReviewEvent, topicFeedEvent, topicSyncArticle},
+		},
+		{
+			name:       "没有审核通过，只发审核的消息",
+			evt:        ReviewEvent{Aid: 1, AuthorId: 123, Status: domain.ReviewStatusRejected.ToUint8()},
+			wantTopics: []string{TopicReviewEvent},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &fakeSyncProducer{}
+			err := NewSaramaSyncProducer(p).ProduceReviewEvent(tc.evt)
+			require.NoError(t, err)
+			topics := make([]string, 0, len(p.msgs))
+			for _, msg := range p.msgs {
+				topics = append(topics, msg.Topic)
+				val, err := msg.Value.Encode()
+				require.NoError(t, err)
+				// 所有的消息里面都不能有会员专享的内容
+				var evt struct {
+					Content string `json:"content"`
+				}
+				require.NoError(t, json.Unmarshal(val, &evt))
+				if msg.Topic != topicFeedEvent {
+					assert.Equal(t, tc.wantContent, evt.Content)
+				}
+			}
+			assert.Equal(t, tc.wantTopics, topics)
+		})
+	}
+}
+
+type fakeSyncProducer struct {
+	sarama.SyncProducer
+	msgs []*sarama.ProducerMessage
+}
+
+func (f *fakeSyncProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	f.msgs = append(f.msgs, msg)
+	return 0, int64(len(f.msgs)), nil
+}
@@ -0,0 +1,107 @@
+package ranking
+
+import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"gitee.com/geekbang/basic-go/webook/internal/events/article"
+	"gitee.com/geekbang/basic-go/webook/internal/service"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"gitee.com/geekbang/basic-go/webook/pkg/saramax"
+	"github.com/IBM/sarama"
+	"time"
+)
+
+// topicInteractiveEvent 交互服务点赞、收藏的时候发的
+const topicInteractiveEvent = "interactive_sync"
+
+// InteractiveEvent 和交互服务里面的定义保持一致
+type InteractiveEvent struct {
+	// Type 1-点赞 2-收藏 3-取消点赞
+	Type  int64  `json:"type"`
+	Biz   string `json:"biz"`
+	BizId int64  `json:"bizId"`
+	Uid   int64  `json:"uid"`
+}
+
+// RankingEventConsumer 消费阅读、点赞和收藏的消息，实时更新热榜
+type RankingEventConsumer struct {
+	client sarama.Client
+	svc    service.RankingService
+	l      logger.LoggerV1
+}
+
+func NewRankingEventConsumer(client sarama.Client,
+	svc service.RankingService,
+	l logger.LoggerV1) *RankingEventConsumer {
+	return &RankingEventConsumer{client: client, svc: svc, l: l}
+}
+
+// Start 阅读和交互是两种消息，分开两个消费者组
+func (r *RankingEventConsumer) Start() error {
+	readCg, err := sarama.NewConsumerGroupFromClient("ranking_read", r.client)
+	if err != nil {
+		return err
+	}
+	intrCg, err := sarama.NewConsumerGroupFromClient("ranking_interactive", r.client)
+	if err != nil {
+		return err
+	}
+	go func() {
+		er := readCg.Consume(context.Background(),
+			[]string{article.TopicReadEvent},
+			saramax.NewHandler[article.ReadEvent](r.l, r.ConsumeRead))
+		if er != nil {
+			r.l.Error("退出了消费循环异常", logger.Error(er))
+		}
+	}()
+	go func() {
+		er := intrCg.Consume(context.Background(),
+			[]string{topicInteractiveEvent},
+			saramax.NewHandler[InteractiveEvent](r.l, r.ConsumeInteractive))
+		if er != nil {
+			r.l.Error("退出了消费循环异常", logger.Error(er))
+		}
+	}()
+	return nil
+}
+
+func (r *RankingEventConsumer) ConsumeRead(msg *sarama.ConsumerMessage,
+	evt article.ReadEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return r.svc.IncrScore(ctx, evt.Aid, domain.RankingSignalRead, 1, r.eventTime(msg))
+}
+
+func (r *RankingEventConsumer) ConsumeInteractive(msg *sarama.ConsumerMessage,
+	evt InteractiveEvent) error {
+	if evt.Biz != "article" {
+		return nil
+	}
+	var (
+		signal domain.RankingSignal
+		delta  int64 = 1
+	)
+	switch evt.Type {
+	case 1:
+		signal = domain.RankingSignalLike
+	case 2:
+		signal = domain.RankingSignalCollect
+	case 3:
+		signal = domain.RankingSignalLike
+		delta = -1
+	default:
+		r.l.Error("未知的交互类型", logger.Int64("type", evt.Type))
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return r.svc.IncrScore(ctx, evt.BizId, signal, delta, r.eventTime(msg))
+}
+
+// eventTime 消息里面没有时间，用 Kafka 记录的时间
+func (r *RankingEventConsumer) eventTime(msg *sarama.ConsumerMessage) time.Time {
+	if msg.Timestamp.IsZero() {
+		return time.Now()
+	}
+	return msg.Timestamp
+}
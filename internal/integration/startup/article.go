@@ -0,0 +1,8 @@
+package startup
+
+import "gitee.com/geekbang/basic-go/webook/internal/service"
+
+// InitArticleReviewConfig 集成测试默认发表不需要审核
+func InitArticleReviewConfig() service.ReviewConfig {
+	return service.ReviewConfig{}
+}
@@ -0,0 +1,8 @@
+package startup
+
+import "gitee.com/geekbang/basic-go/webook/internal/job"
+
+// InitSchedulerConfig 集成测试里面用默认的配置
+func InitSchedulerConfig() job.SchedulerConfig {
+	return job.SchedulerConfig{}
+}
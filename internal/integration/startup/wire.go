@@ -46,6 +46,17 @@ var articlSvcProvider = wire.NewSet(
 	InitRewardServiceClient,
 	service.NewArticleService)
 
+var seriesSvcProvider = wire.NewSet(
+	dao.NewSeriesGORMDAO,
+	repository.NewSeriesRepository,
+	service.NewSeriesService)
+
+var reviewSvcProvider = wire.NewSet(
+	dao.NewArticleReviewGORMDAO,
+	repository.NewCachedArticleReviewRepository,
+	InitArticleReviewConfig,
+	service.NewArticleReviewService)
+
 var interactiveSvcSet = wire.NewSet(dao2.NewGORMInteractiveDAO,
 	cache2.NewInteractiveRedisCache,
 	repository2.NewCachedInteractiveRepository,
@@ -58,7 +69,10 @@ func InitWebServer() *gin.Engine {
 		thirdPartySet,
 		userSvcProvider,
 		articlSvcProvider,
+		seriesSvcProvider,
+		reviewSvcProvider,
 		interactiveSvcSet,
+		jobProviderSet,
 		// cache 部分
 		cache.NewCodeCache,
 
@@ -75,6 +89,9 @@ func InitWebServer() *gin.Engine {
 		// handler 部分
 		web.NewUserHandler,
 		web.NewArticleHandler,
+		web.NewSeriesHandler,
+		web.NewArticleReviewHandler,
+		web.NewJobHandler,
 		web.NewOAuth2WechatHandler,
 		ijwt.NewRedisJWTHandler,
 		ioc.InitGinMiddlewares,
@@ -99,6 +116,9 @@ func InitArticleHandler(dao dao.ArticleDAO) *web.ArticleHandler {
 		InitRewardServiceClient,
 		repository.NewCachedArticleRepository,
 		cache.NewArticleRedisCache,
+		dao.NewSeriesGORMDAO,
+		repository.NewSeriesRepository,
+		reviewSvcProvider,
 		service.NewArticleService,
 		article.NewSaramaSyncProducer,
 		web.NewArticleHandler)
@@ -106,6 +126,6 @@ func InitArticleHandler(dao dao.ArticleDAO) *web.ArticleHandler {
 }
 
 func InitJobScheduler() *job.Scheduler {
-	wire.Build(jobProviderSet, thirdPartySet, job.NewScheduler)
+	wire.Build(jobProviderSet, thirdPartySet, InitSchedulerConfig, job.NewScheduler)
 	return &job.Scheduler{}
 }
@@ -49,8 +49,14 @@ func InitWebServer() *gin.Engine {
 	client := InitSaramaClient()
 	syncProducer := InitSyncProducer(client)
 	producer := article.NewSaramaSyncProducer(syncProducer)
-	articleService := service.NewArticleService(articleRepository, producer)
+	seriesDAO := dao.NewSeriesGORMDAO(db)
+	seriesRepository := repository.NewSeriesRepository(seriesDAO, userRepository)
+	articleReviewDAO := dao.NewArticleReviewGORMDAO(db)
+	articleReviewRepository := repository.NewCachedArticleReviewRepository(articleReviewDAO, articleCache)
+	reviewConfig := InitArticleReviewConfig()
+	articleReviewService := service.NewArticleReviewService(articleReviewRepository, articleRepository, producer, reviewConfig, loggerV1)
 	rewardServiceClient := InitRewardServiceClient()
+	articleService := service.NewArticleService(articleRepository, seriesRepository, articleReviewService, producer, rewardServiceClient, loggerV1)
 	interactiveDAO := dao2.NewGORMInteractiveDAO(db)
 	interactiveCache := cache2.NewInteractiveRedisCache(cmdable)
 	interactiveRepository := repository2.NewCachedInteractiveRepository(interactiveDAO, loggerV1, interactiveCache)
@@ -59,7 +65,14 @@ func InitWebServer() *gin.Engine {
 	articleHandler := web.NewArticleHandler(loggerV1, articleService, rewardServiceClient, interactiveServiceClient)
 	wechatService := InitWechatService(loggerV1)
 	oAuth2WechatHandler := web.NewOAuth2WechatHandler(wechatService, handler, userService)
-	engine := ioc.InitWebServer(v, userHandler, articleHandler, oAuth2WechatHandler)
+	seriesService := service.NewSeriesService(seriesRepository)
+	seriesHandler := web.NewSeriesHandler(loggerV1, seriesService)
+	articleReviewHandler := web.NewArticleReviewHandler(loggerV1, articleReviewService)
+	jobDAO := dao.NewGORMJobDAO(db)
+	cronJobRepository := repository.NewPreemptJobRepository(jobDAO)
+	cronJobService := service.NewCronJobService(cronJobRepository, loggerV1)
+	jobHandler := web.NewJobHandler(loggerV1, cronJobService)
+	engine := ioc.InitWebServer(v, userHandler, articleHandler, seriesHandler, articleReviewHandler, jobHandler, oAuth2WechatHandler)
 	return engine
 }
 
@@ -84,8 +97,14 @@ func InitArticleHandler(dao3 dao.ArticleDAO) *web.ArticleHandler {
 	client := InitSaramaClient()
 	syncProducer := InitSyncProducer(client)
 	producer := article.NewSaramaSyncProducer(syncProducer)
-	articleService := service.NewArticleService(articleRepository, producer)
+	seriesDAO := dao.NewSeriesGORMDAO(db)
+	seriesRepository := repository.NewSeriesRepository(seriesDAO, userRepository)
+	articleReviewDAO := dao.NewArticleReviewGORMDAO(db)
+	articleReviewRepository := repository.NewCachedArticleReviewRepository(articleReviewDAO, articleCache)
+	reviewConfig := InitArticleReviewConfig()
+	articleReviewService := service.NewArticleReviewService(articleReviewRepository, articleRepository, producer, reviewConfig, loggerV1)
 	rewardServiceClient := InitRewardServiceClient()
+	articleService := service.NewArticleService(articleRepository, seriesRepository, articleReviewService, producer, rewardServiceClient, loggerV1)
 	interactiveDAO := dao2.NewGORMInteractiveDAO(db)
 	interactiveCache := cache2.NewInteractiveRedisCache(cmdable)
 	interactiveRepository := repository2.NewCachedInteractiveRepository(interactiveDAO, loggerV1, interactiveCache)
@@ -101,7 +120,8 @@ func InitJobScheduler() *job.Scheduler {
 	cronJobRepository := repository.NewPreemptJobRepository(jobDAO)
 	loggerV1 := InitLogger()
 	cronJobService := service.NewCronJobService(cronJobRepository, loggerV1)
-	scheduler := job.NewScheduler(cronJobService, loggerV1)
+	schedulerConfig := InitSchedulerConfig()
+	scheduler := job.NewScheduler(cronJobService, loggerV1, schedulerConfig)
 	return scheduler
 }
 
@@ -119,4 +139,8 @@ var userSvcProvider = wire.NewSet(dao.NewUserDAO, cache.NewUserCache, repository
 
 var articlSvcProvider = wire.NewSet(repository.NewCachedArticleRepository, cache.NewArticleRedisCache, dao.NewArticleGORMDAO, InitRewardServiceClient, service.NewArticleService)
 
+var seriesSvcProvider = wire.NewSet(dao.NewSeriesGORMDAO, repository.NewSeriesRepository, service.NewSeriesService)
+
+var reviewSvcProvider = wire.NewSet(dao.NewArticleReviewGORMDAO, repository.NewCachedArticleReviewRepository, InitArticleReviewConfig, service.NewArticleReviewService)
+
 var interactiveSvcSet = wire.NewSet(dao2.NewGORMInteractiveDAO, cache2.NewInteractiveRedisCache, repository2.NewCachedInteractiveRepository, service2.NewInteractiveService, ioc.InitIntrClient)
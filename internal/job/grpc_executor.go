@@ -0,0 +1,94 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	jobv1 "gitee.com/geekbang/basic-go/webook/api/proto/gen/job/v1"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"gitee.com/geekbang/basic-go/webook/pkg/grpcx"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"sync"
+	"time"
+)
+
+// GrpcService 一个实现了 JobExecutorService 的服务
+type GrpcService struct {
+	// Token 放在 authorization 元数据里面，为空就不带
+	Token string
+	// Timeout 为 0 就只受任务自己的超时时间控制
+	Timeout time.Duration
+}
+
+// GrpcExecutor 通过 etcd 找到 Job.Target 这个服务，调用它的 Execute 方法。
+// 只有配置过的服务才能调用
+type GrpcExecutor struct {
+	etcd     *clientv3.Client
+	services map[string]GrpcService
+
+	lock    sync.Mutex
+	clients map[string]jobv1.JobExecutorServiceClient
+}
+
+func NewGrpcExecutor(etcd *clientv3.Client, services map[string]GrpcService) *GrpcExecutor {
+	return &GrpcExecutor{
+		etcd:     etcd,
+		services: services,
+		clients:  make(map[string]jobv1.JobExecutorServiceClient, len(services)),
+	}
+}
+
+func (g *GrpcExecutor) Name() string {
+	return "grpc"
+}
+
+func (g *GrpcExecutor) Exec(ctx context.Context, j domain.Job) error {
+	svc, ok := g.services[j.Target]
+	if !ok {
+		return fmt.Errorf("未配置 gRPC 服务 %s", j.Target)
+	}
+	client, err := g.client(j.Target)
+	if err != nil {
+		return err
+	}
+	if svc.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, svc.Timeout)
+		defer cancel()
+	}
+	if svc.Token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+svc.Token)
+	}
+	resp, err := client.Execute(ctx, &jobv1.ExecuteRequest{
+		JobId: j.Id,
+		Name:  j.Name,
+		Cfg:   j.Cfg,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.GetCode() != 0 {
+		return fmt.Errorf("执行任务失败，code %d，msg %s", resp.GetCode(), resp.GetMsg())
+	}
+	return nil
+}
+
+// client 第一次用到的时候才去连接
+func (g *GrpcExecutor) client(name string) (jobv1.JobExecutorServiceClient, error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	client, ok := g.clients[name]
+	if ok {
+		return client, nil
+	}
+	cc, err := grpcx.NewEtcdClientConn(g.etcd, name,
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	client = jobv1.NewJobExecutorServiceClient(cc)
+	g.clients[name] = client
+	return client, nil
+}
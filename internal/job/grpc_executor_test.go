@@ -0,0 +1,101 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	jobv1 "gitee.com/geekbang/basic-go/webook/api/proto/gen/job/v1"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"testing"
+	"time"
+)
+
+func TestGrpcExecutor_Exec(t *testing.T) {
+	testCases := []struct {
+		name   string
+		target string
+		client *fakeJobExecutorClient
+
+		wantErr  error
+		wantAuth []string
+		wantDDL  bool
+	}{
+		{
+			name:   "执行成功，带上 token 和超时时间",
+			target: "reward",
+			client: &fakeJobExecutorClient{resp: &jobv1.ExecuteResponse{}},
+
+			wantAuth: []string{"Bearer reward_token"},
+			wantDDL:  true,
+		},
+		{
+			name:   "没有配置 token 和超时时间",
+			target: "search",
+			client: &fakeJobExecutorClient{resp: &jobv1.ExecuteResponse{}},
+		},
+		{
+			name:    "没有配置的服务",
+			target:  "unknown",
+			wantErr: fmt.Errorf("未配置 gRPC 服务 %s", "unknown"),
+		},
+		{
+			name:   "服务返回了错误码",
+			target: "reward",
+			client: &fakeJobExecutorClient{resp: &jobv1.ExecuteResponse{Code: 1, Msg: "db error"}},
+
+			wantErr:  fmt.Errorf("执行任务失败，code %d，msg %s", 1, "db error"),
+			wantAuth: []string{"Bearer reward_token"},
+			wantDDL:  true,
+		},
+		{
+			name:   "调用失败",
+			target: "reward",
+			client: &fakeJobExecutorClient{err: errors.New("mock rpc error")},
+
+			wantErr:  errors.New("mock rpc error"),
+			wantAuth: []string{"Bearer reward_token"},
+			wantDDL:  true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			exec := NewGrpcExecutor(nil, map[string]GrpcService{
+				"reward": {Token: "reward_token", Timeout: time.Minute},
+				"search": {},
+			})
+			if tc.client != nil {
+				// 不走 etcd
+				exec.clients[tc.target] = tc.client
+			}
+			err := exec.Exec(context.Background(), domain.Job{
+				Id: 1, Name: "member_renew", Target: tc.target, Cfg: "cfg",
+			})
+			assert.Equal(t, tc.wantErr, err)
+			if tc.client == nil {
+				return
+			}
+			assert.Equal(t, &jobv1.ExecuteRequest{JobId: 1, Name: "member_renew", Cfg: "cfg"}, tc.client.req)
+			md, _ := metadata.FromOutgoingContext(tc.client.ctx)
+			assert.Equal(t, tc.wantAuth, md.Get("authorization"))
+			_, ok := tc.client.ctx.Deadline()
+			assert.Equal(t, tc.wantDDL, ok)
+		})
+	}
+}
+
+type fakeJobExecutorClient struct {
+	ctx  context.Context
+	req  *jobv1.ExecuteRequest
+	resp *jobv1.ExecuteResponse
+	err  error
+}
+
+func (f *fakeJobExecutorClient) Execute(ctx context.Context, in *jobv1.ExecuteRequest,
+	opts ...grpc.CallOption) (*jobv1.ExecuteResponse, error) {
+	f.ctx = ctx
+	f.req = in
+	return f.resp, f.err
+}
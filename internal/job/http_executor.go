@@ -0,0 +1,91 @@
+package job
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"net/http"
+	"time"
+)
+
+// HttpEndpoint 一个可以执行任务的 HTTP 接口
+type HttpEndpoint struct {
+	URL string
+	// Token 放在 Authorization 头部里面，为空就不带
+	Token string
+	// Timeout 为 0 就只受任务自己的超时时间控制
+	Timeout time.Duration
+}
+
+// HttpExecutor 把任务 POST 到 Job.Target 对应的 endpoint。
+// 对方按照 {"code": 0, "msg": ""} 的格式返回，code 不为 0 就是执行失败
+type HttpExecutor struct {
+	client    *http.Client
+	endpoints map[string]HttpEndpoint
+}
+
+func NewHttpExecutor(client *http.Client, endpoints map[string]HttpEndpoint) *HttpExecutor {
+	return &HttpExecutor{client: client, endpoints: endpoints}
+}
+
+func (h *HttpExecutor) Name() string {
+	return "http"
+}
+
+type httpExecReq struct {
+	JobId int64  `json:"jobId"`
+	Name  string `json:"name"`
+	Cfg   string `json:"cfg"`
+}
+
+type httpExecResp struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+func (h *HttpExecutor) Exec(ctx context.Context, j domain.Job) error {
+	ep, ok := h.endpoints[j.Target]
+	if !ok {
+		return fmt.Errorf("未配置 HTTP endpoint %s", j.Target)
+	}
+	if ep.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ep.Timeout)
+		defer cancel()
+	}
+	body, err := json.Marshal(httpExecReq{
+		JobId: j.Id,
+		Name:  j.Name,
+		Cfg:   j.Cfg,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if ep.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+ep.Token)
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("执行任务失败，HTTP 状态码 %d", resp.StatusCode)
+	}
+	var res httpExecResp
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	if err != nil {
+		return fmt.Errorf("解析执行结果失败 %w", err)
+	}
+	if res.Code != 0 {
+		return fmt.Errorf("执行任务失败，code %d，msg %s", res.Code, res.Msg)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHttpExecutor_Exec(t *testing.T) {
+	testCases := []struct {
+		name    string
+		handler http.HandlerFunc
+		ep      HttpEndpoint
+		target  string
+
+		wantErr error
+	}{
+		{
+			name: "执行成功",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "Bearer my_token", r.Header.Get("Authorization"))
+				var req httpExecReq
+				err := json.NewDecoder(r.Body).Decode(&req)
+				require.NoError(t, err)
+				assert.Equal(t, httpExecReq{JobId: 1, Name: "test_job", Cfg: "我的配置"}, req)
+				_, _ = w.Write([]byte(`{"code":0,"msg":"OK"}`))
+			},
+			ep:     HttpEndpoint{Token: "my_token"},
+			target: "test",
+		},
+		{
+			name: "业务执行失败",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"code":5,"msg":"系统错误"}`))
+			},
+			target:  "test",
+			wantErr: errors.New("执行任务失败，code 5，msg 系统错误"),
+		},
+		{
+			name: "HTTP 状态码不对",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			},
+			target:  "test",
+			wantErr: errors.New("执行任务失败，HTTP 状态码 401"),
+		},
+		{
+			name: "超时",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(time.Second):
+				}
+			},
+			ep:      HttpEndpoint{Timeout: time.Millisecond * 10},
+			target:  "test",
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name:    "没有配置",
+			handler: func(w http.ResponseWriter, r *http.Request) {},
+			target:  "unknown",
+			wantErr: errors.New("未配置 HTTP endpoint unknown"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(tc.handler)
+			defer server.Close()
+			tc.ep.URL = server.URL
+			exec := NewHttpExecutor(server.Client(), map[string]HttpEndpoint{
+				"test": tc.ep,
+			})
+			err := exec.Exec(context.Background(), domain.Job{
+				Id:     1,
+				Name:   "test_job",
+				Target: tc.target,
+				Cfg:    "我的配置",
+			})
+			if errors.Is(tc.wantErr, context.DeadlineExceeded) {
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+				return
+			}
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
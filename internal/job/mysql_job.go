@@ -2,11 +2,15 @@ package job
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"gitee.com/geekbang/basic-go/webook/internal/domain"
 	"gitee.com/geekbang/basic-go/webook/internal/service"
 	"gitee.com/geekbang/basic-go/webook/pkg/logger"
 	"golang.org/x/sync/semaphore"
+	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -42,24 +46,69 @@ func (l *LocalFuncExecutor) Exec(ctx context.Context, j domain.Job) error {
 	return fn(ctx, j)
 }
 
+// SchedulerConfig 当前节点的配置
+type SchedulerConfig struct {
+	// Node 节点名字，为空就用 hostname
+	Node string
+	// Labels 节点的标签，任务要求了标签的，只有带了这个标签的节点能执行
+	Labels []string
+	// Capacity 最多同时执行多少权重的任务，为 0 就是 100
+	Capacity int64
+}
+
 type Scheduler struct {
 	dbTimeout time.Duration
+	// node 执行记录里面的节点名字
+	node   string
+	labels []string
 
 	svc service.CronJobService
 
 	executors map[string]Executor
 	l         logger.LoggerV1
 
-	limiter *semaphore.Weighted
+	capacity int64
+	limiter  *semaphore.Weighted
+	// load 正在执行的任务的权重之和
+	load atomic.Int64
+
+	// mu 保护 running 和 stopRunning
+	mu sync.Mutex
+	// running 正在执行的任务都用它派生的 ctx。
+	// 心跳失败之后别的节点会认为这个节点挂了，抢走它的任务，
+	// 所以心跳失败就要取消掉，免得同一个任务执行两次
+	running     context.Context
+	stopRunning context.CancelFunc
+
+	heartbeatInterval time.Duration
+	// pollInterval 没有任务可以抢的时候，等多久再抢
+	pollInterval time.Duration
 }
 
-func NewScheduler(svc service.CronJobService, l logger.LoggerV1) *Scheduler {
+func NewScheduler(svc service.CronJobService, l logger.LoggerV1, cfg SchedulerConfig) *Scheduler {
+	node := cfg.Node
+	if node == "" {
+		var err error
+		node, err = os.Hostname()
+		if err != nil {
+			node = "unknown"
+		}
+	}
+	capacity := cfg.Capacity
+	if capacity <= 0 {
+		capacity = 100
+	}
 	return &Scheduler{
-		svc:       svc,
-		dbTimeout: time.Second,
-		limiter:   semaphore.NewWeighted(100),
-		l:         l,
-		executors: map[string]Executor{},
+		svc:               svc,
+		node:              node,
+		labels:            cfg.Labels,
+		dbTimeout:         time.Second,
+		capacity:          capacity,
+		limiter:           semaphore.NewWeighted(capacity),
+		l:                 l,
+		executors:         map[string]Executor{},
+		heartbeatInterval: time.Second * 5,
+		pollInterval:      time.Second,
 	}
 }
 
@@ -68,23 +117,42 @@ func (s *Scheduler) RegisterExecutor(exec Executor) {
 }
 
 func (s *Scheduler) Schedule(ctx context.Context) error {
+	s.resetRunning(ctx)
+	go s.heartbeat(ctx)
 	for {
 		// 放弃调度了
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
+		// 至少要有一点空闲才去抢
 		err := s.limiter.Acquire(ctx, 1)
 		if err != nil {
 			return err
 		}
 		dbCtx, cancel := context.WithTimeout(ctx, s.dbTimeout)
-		j, err := s.svc.Preempt(dbCtx)
+		j, err := s.svc.Preempt(dbCtx, s.snapshot())
 		cancel()
 		if err != nil {
-			// 有 Error
-			// 最简单的做法就是直接下一轮，也可以睡一段时间
+			// 没有任务可以抢，或者数据库有问题，都等一会再抢
+			s.limiter.Release(1)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(s.pollInterval):
+			}
 			continue
 		}
+		// 抢的时候已经按照空闲的容量过滤了，这里不会等太久
+		cost := j.Cost()
+		if cost > 1 {
+			err = s.limiter.Acquire(ctx, cost-1)
+			if err != nil {
+				s.limiter.Release(1)
+				j.CancelFunc()
+				return err
+			}
+		}
+		s.load.Add(cost)
 
 		// 肯定要调度执行 j
 		exec, ok := s.executors[j.Executor]
@@ -93,20 +161,34 @@ func (s *Scheduler) Schedule(ctx context.Context) error {
 			s.l.Error("找不到执行器",
 				logger.Int64("jid", j.Id),
 				logger.String("executor", j.Executor))
+			s.done(cost)
+			j.CancelFunc()
 			continue
 		}
 
+		runCtx := s.runningCtx()
 		go func() {
 			defer func() {
-				s.limiter.Release(1)
+				s.done(cost)
 				// 这边要释放掉
 				j.CancelFunc()
 			}()
-			err1 := exec.Exec(ctx, j)
+			err1 := s.execWithRetry(runCtx, exec, j)
+			if err1 != nil && runCtx.Err() != nil {
+				// 是放弃调度或者心跳失败导致的，不算这个任务失败
+				return
+			}
 			if err1 != nil {
 				s.l.Error("执行任务失败",
 					logger.Int64("jid", j.Id),
+					logger.Int("failures", j.Failures+1),
 					logger.Error(err1))
+				err1 = s.svc.Fail(ctx, j)
+				if err1 != nil {
+					s.l.Error("记录任务失败次数失败",
+						logger.Int64("jid", j.Id),
+						logger.Error(err1))
+				}
 				return
 			}
 			err1 = s.svc.ResetNextTime(ctx, j)
@@ -118,3 +200,117 @@ func (s *Scheduler) Schedule(ctx context.Context) error {
 		}()
 	}
 }
+
+func (s *Scheduler) done(cost int64) {
+	s.load.Add(-cost)
+	s.limiter.Release(cost)
+}
+
+// snapshot 当前节点的负载
+func (s *Scheduler) snapshot() domain.JobNode {
+	return domain.JobNode{
+		Name:     s.node,
+		Labels:   s.labels,
+		Capacity: s.capacity,
+		Load:     s.load.Load(),
+	}
+}
+
+// heartbeat 定时上报负载，别的节点靠这个来判断要不要让出任务，
+// 以及这个节点是不是已经挂了
+func (s *Scheduler) heartbeat(ctx context.Context) {
+	ticker := time.NewTicker(s.heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		s.beat(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// beat 上报一次心跳，失败了就停掉正在执行的任务
+func (s *Scheduler) beat(ctx context.Context) {
+	dbCtx, cancel := context.WithTimeout(ctx, s.dbTimeout)
+	err := s.svc.Heartbeat(dbCtx, s.snapshot())
+	cancel()
+	if err != nil && ctx.Err() == nil {
+		s.l.Error("上报心跳失败，停止正在执行的任务", logger.Error(err))
+		s.resetRunning(ctx)
+	}
+}
+
+// resetRunning 取消之前执行的任务，之后执行的任务用新的 ctx
+func (s *Scheduler) resetRunning(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopRunning != nil {
+		s.stopRunning()
+	}
+	s.running, s.stopRunning = context.WithCancel(ctx)
+}
+
+func (s *Scheduler) runningCtx() context.Context {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
+
+// execWithRetry 失败了就按照指数退避重试，返回最后一次执行的错误
+func (s *Scheduler) execWithRetry(ctx context.Context, exec Executor, j domain.Job) error {
+	var err error
+	for attempt := 0; attempt <= j.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				// 放弃调度了，也就不用再重试了
+				return err
+			case <-time.After(j.RetryBackoff(attempt)):
+			}
+		}
+		err = s.exec(ctx, exec, j, attempt)
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// exec 执行一次，并且记录下来
+func (s *Scheduler) exec(ctx context.Context, exec Executor, j domain.Job, attempt int) error {
+	execCtx := ctx
+	if j.Timeout > 0 {
+		var cancel context.CancelFunc
+		execCtx, cancel = context.WithTimeout(ctx, j.Timeout)
+		defer cancel()
+	}
+	run := domain.JobRun{
+		JobId:   j.Id,
+		Node:    s.node,
+		Attempt: attempt,
+		Start:   time.Now(),
+	}
+	err := exec.Exec(execCtx, j)
+	run.End = time.Now()
+	switch {
+	case err == nil:
+		run.Result = domain.JobRunResultSuccess
+	case errors.Is(err, context.DeadlineExceeded) || execCtx.Err() == context.DeadlineExceeded:
+		run.Result = domain.JobRunResultTimeout
+		run.Err = err.Error()
+	default:
+		run.Result = domain.JobRunResultFailed
+		run.Err = err.Error()
+	}
+	dbCtx, cancel := context.WithTimeout(context.Background(), s.dbTimeout)
+	defer cancel()
+	er := s.svc.RecordRun(dbCtx, run)
+	if er != nil {
+		s.l.Error("记录任务执行失败",
+			logger.Int64("jid", j.Id),
+			logger.Error(er))
+	}
+	return err
+}
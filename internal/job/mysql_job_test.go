@@ -0,0 +1,143 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"gitee.com/geekbang/basic-go/webook/internal/service"
+	svcmocks "gitee.com/geekbang/basic-go/webook/internal/service/mocks"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestScheduler_execWithRetry(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) service.CronJobService
+		// 每一次执行的结果
+		results []error
+		job     domain.Job
+
+		wantErr error
+		wantCnt int
+	}{
+		{
+			name: "重试之后成功",
+			mock: func(ctrl *gomock.Controller) service.CronJobService {
+				svc := svcmocks.NewMockCronJobService(ctrl)
+				gomock.InOrder(
+					svc.EXPECT().RecordRun(gomock.Any(), runMatcher{attempt: 0, result: domain.JobRunResultFailed}).Return(nil),
+					svc.EXPECT().RecordRun(gomock.Any(), runMatcher{attempt: 1, result: domain.JobRunResultFailed}).Return(nil),
+					// 记录失败也不影响执行结果
+					svc.EXPECT().RecordRun(gomock.Any(), runMatcher{attempt: 2, result: domain.JobRunResultSuccess}).
+						Return(errors.New("mock db error")),
+				)
+				return svc
+			},
+			results: []error{errors.New("失败1"), errors.New("失败2"), nil},
+			job:     domain.Job{Id: 1, Name: "test_job", MaxRetries: 2, Backoff: time.Millisecond},
+			wantCnt: 3,
+		},
+		{
+			name: "重试次数用完",
+			mock: func(ctrl *gomock.Controller) service.CronJobService {
+				svc := svcmocks.NewMockCronJobService(ctrl)
+				svc.EXPECT().RecordRun(gomock.Any(), runMatcher{result: domain.JobRunResultFailed}).Return(nil)
+				svc.EXPECT().RecordRun(gomock.Any(), runMatcher{attempt: 1, result: domain.JobRunResultFailed}).Return(nil)
+				return svc
+			},
+			results: []error{errors.New("失败1"), errors.New("失败2"), nil},
+			job:     domain.Job{Id: 1, Name: "test_job", MaxRetries: 1, Backoff: time.Millisecond},
+			wantErr: errors.New("失败2"),
+			wantCnt: 2,
+		},
+		{
+			name: "超时",
+			mock: func(ctrl *gomock.Controller) service.CronJobService {
+				svc := svcmocks.NewMockCronJobService(ctrl)
+				svc.EXPECT().RecordRun(gomock.Any(), runMatcher{result: domain.JobRunResultTimeout}).Return(nil)
+				return svc
+			},
+			results: []error{context.DeadlineExceeded},
+			job:     domain.Job{Id: 1, Name: "test_job", Timeout: time.Millisecond},
+			wantErr: context.DeadlineExceeded,
+			wantCnt: 1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := NewScheduler(tc.mock(ctrl), logger.NewNopLogger(), SchedulerConfig{})
+			exec := NewLocalFuncExecutor()
+			cnt := 0
+			exec.RegisterFunc("test_job", func(ctx context.Context, j domain.Job) error {
+				err := tc.results[cnt]
+				cnt++
+				return err
+			})
+			err := s.execWithRetry(context.Background(), exec, tc.job)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCnt, cnt)
+		})
+	}
+}
+
+func TestScheduler_beat(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) service.CronJobService
+
+		wantStopped bool
+	}{
+		{
+			name: "心跳成功，任务继续执行",
+			mock: func(ctrl *gomock.Controller) service.CronJobService {
+				svc := svcmocks.NewMockCronJobService(ctrl)
+				svc.EXPECT().Heartbeat(gomock.Any(), gomock.Any()).Return(nil)
+				return svc
+			},
+		},
+		{
+			name: "心跳失败，停掉正在执行的任务",
+			mock: func(ctrl *gomock.Controller) service.CronJobService {
+				svc := svcmocks.NewMockCronJobService(ctrl)
+				svc.EXPECT().Heartbeat(gomock.Any(), gomock.Any()).
+					Return(errors.New("mock db error"))
+				return svc
+			},
+			wantStopped: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := NewScheduler(tc.mock(ctrl), logger.NewNopLogger(), SchedulerConfig{Node: "node-1"})
+			s.resetRunning(context.Background())
+			runCtx := s.runningCtx()
+			s.beat(context.Background())
+			assert.Equal(t, tc.wantStopped, runCtx.Err() != nil)
+			// 之后抢到的任务不受影响
+			assert.NoError(t, s.runningCtx().Err())
+		})
+	}
+}
+
+// runMatcher 只比较第几次执行和执行结果
+type runMatcher struct {
+	attempt int
+	result  domain.JobRunResult
+}
+
+func (m runMatcher) Matches(x any) bool {
+	r, ok := x.(domain.JobRun)
+	return ok && r.JobId == 1 && r.Attempt == m.attempt && r.Result == m.result
+}
+
+func (m runMatcher) String() string {
+	return "attempt 和 result 一致"
+}
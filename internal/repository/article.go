@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"gitee.com/geekbang/basic-go/webook/internal/domain"
 	"gitee.com/geekbang/basic-go/webook/internal/repository/cache"
 	"gitee.com/geekbang/basic-go/webook/internal/repository/dao"
@@ -18,13 +19,33 @@ type ArticleRepository interface {
 	Update(ctx context.Context, art domain.Article) error
 	Sync(ctx context.Context, art domain.Article) (int64, error)
 	SyncStatus(ctx context.Context, uid int64, id int64, status domain.ArticleStatus) error
-	GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error)
+	// GetByAuthor 游标为零值的时候就是第一页
+	GetByAuthor(ctx context.Context, uid int64, cursor domain.ArticleCursor, limit int) ([]domain.Article, error)
 	GetById(ctx context.Context, id int64) (domain.Article, error)
 	// GetPubById 在 12 周作业里面，你需要额外加一个 uid 参数
 	GetPubById(ctx context.Context, id int64) (domain.Article, error)
-	ListPub(ctx context.Context, start time.Time, offset int, limit int) ([]domain.Article, error)
+	ListPub(ctx context.Context, cursor domain.ArticleCursor, limit int) ([]domain.Article, error)
+	// ListPubByIds 只返回已经发表的，不保证顺序，也不会填充创作者的名字
+	ListPubByIds(ctx context.Context, ids []int64) ([]domain.Article, error)
+	ListRevisions(ctx context.Context, artId int64, offset int, limit int) ([]domain.ArticleRevision, error)
+	GetRevisionById(ctx context.Context, id int64) (domain.ArticleRevision, error)
+	ListScheduled(ctx context.Context, uid int64) ([]domain.Article, error)
+	ListDueScheduled(ctx context.Context, now time.Time, limit int) ([]domain.Article, error)
+	UpdateSchedule(ctx context.Context, uid int64, id int64, status domain.ArticleStatus, publishAt time.Time) error
+
+	InviteCollaborator(ctx context.Context, c domain.ArticleCollaborator) error
+	AcceptCollaborator(ctx context.Context, aid int64, uid int64) error
+	RemoveCollaborator(ctx context.Context, operator int64, aid int64, uid int64) error
 }
 
+var (
+	ErrArticleNoPermission = dao.ErrArticleNoPermission
+	ErrArticleReviewing    = dao.ErrArticleReviewing
+)
+
+// firstPageSize 缓存的第一页有多少篇
+const firstPageSize = 100
+
 type CachedArticleRepository struct {
 	dao   dao.ArticleDAO
 	cache cache.ArticleCache
@@ -42,8 +63,67 @@ func (c *CachedArticleRepository) Cache() cache.ArticleCache {
 	return c.cache
 }
 
-func (c *CachedArticleRepository) ListPub(ctx context.Context, start time.Time, offset int, limit int) ([]domain.Article, error) {
-	arts, err := c.dao.ListPub(ctx, start, offset, limit)
+func (c *CachedArticleRepository) ListRevisions(ctx context.Context, artId int64, offset int, limit int) ([]domain.ArticleRevision, error) {
+	revs, err := c.dao.ListRevisions(ctx, artId, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.ArticleRevision, domain.ArticleRevision](revs,
+		func(idx int, src dao.ArticleRevision) domain.ArticleRevision {
+			return c.revisionToDomain(src)
+		}), nil
+}
+
+func (c *CachedArticleRepository) GetRevisionById(ctx context.Context, id int64) (domain.ArticleRevision, error) {
+	rev, err := c.dao.GetRevisionById(ctx, id)
+	if err != nil {
+		return domain.ArticleRevision{}, err
+	}
+	return c.revisionToDomain(rev), nil
+}
+
+func (c *CachedArticleRepository) ListScheduled(ctx context.Context, uid int64) ([]domain.Article, error) {
+	arts, err := c.dao.ListScheduled(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.Article, domain.Article](arts, func(idx int, src dao.Article) domain.Article {
+		return c.ToDomain(src)
+	}), nil
+}
+
+func (c *CachedArticleRepository) ListDueScheduled(ctx context.Context, now time.Time, limit int) ([]domain.Article, error) {
+	arts, err := c.dao.ListDueScheduled(ctx, now.UnixMilli(), limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.Article, domain.Article](arts, func(idx int, src dao.Article) domain.Article {
+		return c.ToDomain(src)
+	}), nil
+}
+
+func (c *CachedArticleRepository) UpdateSchedule(ctx context.Context, uid int64, id int64,
+	status domain.ArticleStatus, publishAt time.Time) error {
+	err := c.dao.UpdateSchedule(ctx, uid, id, status.ToUint8(), c.toMilli(publishAt))
+	if err == nil {
+		c.delFirstPages(ctx, id, uid)
+	}
+	return err
+}
+
+func (c *CachedArticleRepository) ListPub(ctx context.Context, cursor domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	arts, err := c.dao.ListPub(ctx, cursor.Utime, cursor.Id, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.PublishedArticle, domain.Article](arts,
+		func(idx int, src dao.PublishedArticle) domain.Article {
+			return c.ToDomain(dao.Article(src))
+		}), nil
+}
+
+func (c *CachedArticleRepository) ListPubByIds(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	arts, err := c.dao.ListPubByIds(ctx, ids)
 	if err != nil {
 		return nil, err
 	}
@@ -71,6 +151,10 @@ func (c *CachedArticleRepository) GetPubById(ctx context.Context, id int64) (dom
 		//return res, nil
 	}
 	res.Author.Name = author.Nickname
+	res.Collaborators, err = c.pubCollaborators(ctx, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 		defer cancel()
@@ -85,6 +169,11 @@ func (c *CachedArticleRepository) GetPubById(ctx context.Context, id int64) (dom
 func (c *CachedArticleRepository) GetById(ctx context.Context, id int64) (domain.Article, error) {
 	res, err := c.cache.Get(ctx, id)
 	if err == nil {
+		// 缓存可能是列表预加载的，没有合作者，所以合作者总是从数据库里面查
+		res.Collaborators, err = c.listCollaborators(ctx, id)
+		if err != nil {
+			return domain.Article{}, err
+		}
 		return res, nil
 	}
 	art, err := c.dao.GetById(ctx, id)
@@ -92,6 +181,11 @@ func (c *CachedArticleRepository) GetById(ctx context.Context, id int64) (domain
 		return domain.Article{}, err
 	}
 	res = c.ToDomain(art)
+	// 判断有没有权限看草稿要用到
+	res.Collaborators, err = c.listCollaborators(ctx, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
 	go func() {
 		er := c.cache.Set(ctx, res)
 		if er != nil {
@@ -101,20 +195,25 @@ func (c *CachedArticleRepository) GetById(ctx context.Context, id int64) (domain
 	return res, nil
 }
 
-func (c *CachedArticleRepository) GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error) {
+func (c *CachedArticleRepository) GetByAuthor(ctx context.Context, uid int64, cursor domain.ArticleCursor, limit int) ([]domain.Article, error) {
 	// 首先第一步，判定要不要查询缓存
-	// 事实上， limit <= 100 都可以查询缓存
-	if offset == 0 && limit == 100 {
-		//if offset == 0 && limit <= 100 {
+	// 缓存里面是前 100 篇，所以只要是第一页，limit <= 100 都可以查询缓存
+	firstPage := cursor.IsZero() && limit <= firstPageSize
+	if firstPage {
 		res, err := c.cache.GetFirstPage(ctx, uid)
 		if err == nil {
-			return res, err
+			return c.firstN(res, limit), err
 		} else {
 			// 要考虑记录日志
 			// 缓存未命中，你是可以忽略的
 		}
 	}
-	arts, err := c.dao.GetByAuthor(ctx, uid, offset, limit)
+	size := limit
+	if firstPage {
+		// 第一页总是取满，这样才能回写缓存
+		size = firstPageSize
+	}
+	arts, err := c.dao.GetByAuthor(ctx, uid, cursor.Utime, cursor.Id, size)
 	if err != nil {
 		return nil, err
 	}
@@ -122,18 +221,22 @@ func (c *CachedArticleRepository) GetByAuthor(ctx context.Context, uid int64, of
 		return c.ToDomain(src)
 	})
 
-	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-		defer cancel()
-		if offset == 0 && limit == 100 {
+	if firstPage {
+		// SetFirstPage 会修改 Content，所以要复制一份
+		page := make([]domain.Article, len(res))
+		copy(page, res)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
 			// 缓存回写失败，不一定是大问题，但有可能是大问题
-			err = c.cache.SetFirstPage(ctx, uid, res)
-			if err != nil {
+			er := c.cache.SetFirstPage(ctx, uid, page)
+			if er != nil {
 				// 记录日志
 				// 我需要监控这里
 			}
-		}
-	}()
+		}()
+	}
+	res = c.firstN(res, limit)
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 		defer cancel()
@@ -142,24 +245,138 @@ func (c *CachedArticleRepository) GetByAuthor(ctx context.Context, uid int64, of
 	return res, nil
 }
 
-func (c *CachedArticleRepository) SyncStatus(ctx context.Context, uid int64, id int64, status domain.ArticleStatus) error {
-	err := c.dao.SyncStatus(ctx, uid, id, status.ToUint8())
+func (c *CachedArticleRepository) InviteCollaborator(ctx context.Context, collab domain.ArticleCollaborator) error {
+	err := c.dao.InsertCollaborator(ctx, dao.ArticleCollaborator{
+		ArticleId: collab.ArticleId,
+		Uid:       collab.User.Id,
+		Role:      collab.Role.ToUint8(),
+		Status:    collab.Status.ToUint8(),
+		InviterId: collab.InviterId,
+	})
+	if err == nil {
+		c.delCollaboratorCache(ctx, collab.ArticleId, collab.User.Id)
+	}
+	return err
+}
+
+func (c *CachedArticleRepository) AcceptCollaborator(ctx context.Context, aid int64, uid int64) error {
+	err := c.dao.AcceptCollaborator(ctx, aid, uid)
 	if err == nil {
-		er := c.cache.DelFirstPage(ctx, uid)
+		c.delCollaboratorCache(ctx, aid, uid)
+	}
+	return err
+}
+
+func (c *CachedArticleRepository) RemoveCollaborator(ctx context.Context, operator int64, aid int64, uid int64) error {
+	err := c.dao.DeleteCollaborator(ctx, operator, aid, uid)
+	if err == nil {
+		c.delCollaboratorCache(ctx, aid, uid)
+	}
+	return err
+}
+
+// delCollaboratorCache 合作者变了，文章详情、线上文章和所有相关的人的第一页都要删掉
+func (c *CachedArticleRepository) delCollaboratorCache(ctx context.Context, aid int64, uid int64) {
+	er := c.cache.Del(ctx, aid)
+	if er != nil {
+		// 记录日志
+	}
+	er = c.cache.DelPub(ctx, aid)
+	if er != nil {
+		// 记录日志
+	}
+	c.delFirstPages(ctx, aid, uid)
+}
+
+// delFirstPages 文章出现在创作者和每一个合作者的第一页里面，都要删掉。
+// uid 是这一次操作的人，被移除的合作者已经查不到了，所以要单独传进来
+func (c *CachedArticleRepository) delFirstPages(ctx context.Context, aid int64, uid int64) {
+	uids := []int64{uid}
+	art, err := c.dao.GetById(ctx, aid)
+	if err == nil && art.AuthorId != uid {
+		uids = append(uids, art.AuthorId)
+	}
+	collabs, err := c.dao.ListCollaborators(ctx, aid)
+	if err == nil {
+		for _, collab := range collabs {
+			if collab.Uid != uid {
+				uids = append(uids, collab.Uid)
+			}
+		}
+	}
+	for _, u := range uids {
+		er := c.cache.DelFirstPage(ctx, u)
 		if er != nil {
 			// 也要记录日志
 		}
 	}
+}
+
+func (c *CachedArticleRepository) listCollaborators(ctx context.Context, aid int64) ([]domain.ArticleCollaborator, error) {
+	collabs, err := c.dao.ListCollaborators(ctx, aid)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.ArticleCollaborator, domain.ArticleCollaborator](collabs,
+		func(idx int, src dao.ArticleCollaborator) domain.ArticleCollaborator {
+			return c.collaboratorToDomain(src)
+		}), nil
+}
+
+// pubCollaborators 读者只能看到已经接受邀请的合作者，并且要带上名字
+func (c *CachedArticleRepository) pubCollaborators(ctx context.Context, aid int64) ([]domain.ArticleCollaborator, error) {
+	collabs, err := c.listCollaborators(ctx, aid)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.ArticleCollaborator, 0, len(collabs))
+	for _, collab := range collabs {
+		if collab.Status != domain.CollaboratorStatusAccepted {
+			continue
+		}
+		u, err := c.userRepo.FindById(ctx, collab.User.Id)
+		if err != nil {
+			return nil, err
+		}
+		collab.User.Name = u.Nickname
+		res = append(res, collab)
+	}
+	return res, nil
+}
+
+func (c *CachedArticleRepository) collaboratorToDomain(collab dao.ArticleCollaborator) domain.ArticleCollaborator {
+	return domain.ArticleCollaborator{
+		ArticleId: collab.ArticleId,
+		User: domain.Author{
+			Id: collab.Uid,
+		},
+		Role:      domain.CollaboratorRole(collab.Role),
+		Status:    domain.CollaboratorStatus(collab.Status),
+		InviterId: collab.InviterId,
+		Ctime:     time.UnixMilli(collab.Ctime),
+		Utime:     time.UnixMilli(collab.Utime),
+	}
+}
+
+func (c *CachedArticleRepository) firstN(arts []domain.Article, n int) []domain.Article {
+	if len(arts) > n {
+		return arts[:n]
+	}
+	return arts
+}
+
+func (c *CachedArticleRepository) SyncStatus(ctx context.Context, uid int64, id int64, status domain.ArticleStatus) error {
+	err := c.dao.SyncStatus(ctx, uid, id, status.ToUint8())
+	if err == nil {
+		c.delFirstPages(ctx, id, uid)
+	}
 	return err
 }
 
 func (c *CachedArticleRepository) Sync(ctx context.Context, art domain.Article) (int64, error) {
 	id, err := c.dao.Sync(ctx, c.toEntity(art))
 	if err == nil {
-		er := c.cache.DelFirstPage(ctx, art.Author.Id)
-		if er != nil {
-			// 也要记录日志
-		}
+		c.delFirstPages(ctx, id, art.Author.Id)
 	}
 	// 在这里尝试，设置缓存
 	go func() {
@@ -239,10 +456,7 @@ func (c *CachedArticleRepository) SyncV1(ctx context.Context, art domain.Article
 func (c *CachedArticleRepository) Update(ctx context.Context, art domain.Article) error {
 	err := c.dao.UpdateById(ctx, c.toEntity(art))
 	if err == nil {
-		er := c.cache.DelFirstPage(ctx, art.Author.Id)
-		if er != nil {
-			// 也要记录日志
-		}
+		c.delFirstPages(ctx, art.Id, art.Author.Id)
 	}
 	return err
 }
@@ -284,12 +498,36 @@ func (c *CachedArticleRepository) toEntity(art domain.Article) dao.Article {
 		Content:  art.Content,
 		AuthorId: art.Author.Id,
 		//Status:   uint8(art.Status),
-		Status: art.Status.ToUint8(),
+		Status:    art.Status.ToUint8(),
+		PublishAt: c.toMilli(art.PublishAt),
+
+		RenderedContent: art.Rendered.HTML,
+		Toc:             c.tocToString(art.Rendered.Toc),
+		Abstract:        art.Rendered.Abstract,
+		ReadingMinutes:  art.Rendered.ReadingMinutes,
+		MembersOnly:     art.MembersOnly,
+	}
+}
+
+func (c *CachedArticleRepository) tocToString(toc []domain.TocItem) string {
+	if len(toc) == 0 {
+		return ""
+	}
+	// 都是基本类型，不会出错
+	val, _ := json.Marshal(toc)
+	return string(val)
+}
+
+// toMilli 零值的时间转成 0，而不是一个负数
+func (c *CachedArticleRepository) toMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
 	}
+	return t.UnixMilli()
 }
 
 func (c *CachedArticleRepository) ToDomain(art dao.Article) domain.Article {
-	return domain.Article{
+	res := domain.Article{
 		Id:      art.Id,
 		Title:   art.Title,
 		Content: art.Content,
@@ -297,9 +535,37 @@ func (c *CachedArticleRepository) ToDomain(art dao.Article) domain.Article {
 			// 这里有一个错误
 			Id: art.AuthorId,
 		},
-		Ctime:  time.UnixMilli(art.Ctime),
-		Utime:  time.UnixMilli(art.Utime),
-		Status: domain.ArticleStatus(art.Status),
+		Ctime:       time.UnixMilli(art.Ctime),
+		Utime:       time.UnixMilli(art.Utime),
+		Status:      domain.ArticleStatus(art.Status),
+		MembersOnly: art.MembersOnly,
+		Rendered: domain.RenderedContent{
+			HTML:           art.RenderedContent,
+			Abstract:       art.Abstract,
+			ReadingMinutes: art.ReadingMinutes,
+		},
+	}
+	if art.PublishAt > 0 {
+		res.PublishAt = time.UnixMilli(art.PublishAt)
+	}
+	if art.Toc != "" {
+		// 目录坏了不影响看文章
+		_ = json.Unmarshal([]byte(art.Toc), &res.Rendered.Toc)
+	}
+	return res
+}
+
+func (c *CachedArticleRepository) revisionToDomain(rev dao.ArticleRevision) domain.ArticleRevision {
+	return domain.ArticleRevision{
+		Id:        rev.Id,
+		ArticleId: rev.ArticleId,
+		Author: domain.Author{
+			Id: rev.AuthorId,
+		},
+		Title:   rev.Title,
+		Content: rev.Content,
+		Status:  domain.ArticleStatus(rev.Status),
+		Ctime:   time.UnixMilli(rev.Ctime),
 	}
 }
 
@@ -22,7 +22,7 @@ func NewCachedGRPCArticleRepository(dao dao.ArticleDAO, cache cache.ArticleCache
 	return &CachedGRPCArticleRepository{dao: dao, cache: cache, userRepo: userRepo, intrRepo: intrRepo}
 }
 
-func (c *CachedGRPCArticleRepository) ListPub(ctx context.Context, start time.Time, offset int, limit int) ([]domain.Article, error) {
+func (c *CachedGRPCArticleRepository) ListPub(ctx context.Context, cursor domain.ArticleCursor, limit int) ([]domain.Article, error) {
 	panic("implement me")
 }
 
@@ -83,7 +83,7 @@ func (c *CachedGRPCArticleRepository) GetById(ctx context.Context, id int64) (do
 	return res, nil
 }
 
-func (c *CachedGRPCArticleRepository) GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error) {
+func (c *CachedGRPCArticleRepository) GetByAuthor(ctx context.Context, uid int64, cursor domain.ArticleCursor, limit int) ([]domain.Article, error) {
 	panic("implement me")
 }
 
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"gitee.com/geekbang/basic-go/webook/internal/repository/cache"
+	"gitee.com/geekbang/basic-go/webook/internal/repository/dao"
+	"github.com/ecodeclub/ekit/slice"
+	"gorm.io/gorm"
+	"time"
+)
+
+var ErrReviewStatusConflict = dao.ErrReviewStatusConflict
+
+//go:generate mockgen -source=./article_review.go -package=repomocks -destination=./mocks/article_review.mock.go ArticleReviewRepository
+type ArticleReviewRepository interface {
+	// Submit 提交审核，已经有审核单的话，要处于 from 里面的状态才能重新提交
+	Submit(ctx context.Context, r domain.ArticleReview, from []domain.ReviewStatus) error
+	Claim(ctx context.Context, aid int64, reviewer int64) error
+	// Transit 返回变更之后的审核单，不包含审核记录
+	Transit(ctx context.Context, aid int64, reviewer int64,
+		from []domain.ReviewStatus, to domain.ReviewStatus, comment string) (domain.ArticleReview, error)
+	// GetByArticle 包含全部审核记录，没有提交过审核的时候返回零值
+	GetByArticle(ctx context.Context, aid int64) (domain.ArticleReview, error)
+	// ListQueue 不包含审核记录
+	ListQueue(ctx context.Context, reviewer int64, offset int, limit int) ([]domain.ArticleReview, error)
+}
+
+type CachedArticleReviewRepository struct {
+	dao dao.ArticleReviewDAO
+	// 审核会修改草稿的状态，所以要删掉作者的列表缓存
+	cache cache.ArticleCache
+}
+
+func NewCachedArticleReviewRepository(dao dao.ArticleReviewDAO,
+	cache cache.ArticleCache) ArticleReviewRepository {
+	return &CachedArticleReviewRepository{dao: dao, cache: cache}
+}
+
+func (c *CachedArticleReviewRepository) Submit(ctx context.Context,
+	r domain.ArticleReview, from []domain.ReviewStatus) error {
+	err := c.dao.Submit(ctx, c.toEntity(r), c.toInts(from))
+	if err == nil {
+		er := c.cache.DelFirstPage(ctx, r.AuthorId)
+		if er != nil {
+			// 也要记录日志
+		}
+	}
+	return err
+}
+
+func (c *CachedArticleReviewRepository) Claim(ctx context.Context, aid int64, reviewer int64) error {
+	return c.dao.Claim(ctx, aid, reviewer)
+}
+
+func (c *CachedArticleReviewRepository) Transit(ctx context.Context, aid int64, reviewer int64,
+	from []domain.ReviewStatus, to domain.ReviewStatus, comment string) (domain.ArticleReview, error) {
+	r, err := c.dao.Transit(ctx, aid, reviewer, c.toInts(from), to.ToUint8(), comment)
+	if err != nil {
+		return domain.ArticleReview{}, err
+	}
+	er := c.cache.DelFirstPage(ctx, r.AuthorId)
+	if er != nil {
+		// 也要记录日志
+	}
+	return c.toDomain(r), nil
+}
+
+func (c *CachedArticleReviewRepository) GetByArticle(ctx context.Context, aid int64) (domain.ArticleReview, error) {
+	r, err := c.dao.GetByArticle(ctx, aid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.ArticleReview{}, nil
+	}
+	if err != nil {
+		return domain.ArticleReview{}, err
+	}
+	comments, err := c.dao.ListComments(ctx, aid)
+	if err != nil {
+		return domain.ArticleReview{}, err
+	}
+	res := c.toDomain(r)
+	res.Comments = slice.Map[dao.ArticleReviewComment, domain.ReviewComment](comments,
+		func(idx int, src dao.ArticleReviewComment) domain.ReviewComment {
+			return domain.ReviewComment{
+				ReviewerId: src.ReviewerId,
+				Status:     domain.ReviewStatus(src.Status),
+				Comment:    src.Comment,
+				Ctime:      time.UnixMilli(src.Ctime),
+			}
+		})
+	return res, nil
+}
+
+func (c *CachedArticleReviewRepository) ListQueue(ctx context.Context,
+	reviewer int64, offset int, limit int) ([]domain.ArticleReview, error) {
+	rs, err := c.dao.ListQueue(ctx, reviewer, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.ArticleReview, domain.ArticleReview](rs,
+		func(idx int, src dao.ArticleReview) domain.ArticleReview {
+			return c.toDomain(src)
+		}), nil
+}
+
+func (c *CachedArticleReviewRepository) toInts(status []domain.ReviewStatus) []int {
+	return slice.Map[domain.ReviewStatus, int](status,
+		func(idx int, src domain.ReviewStatus) int {
+			return int(src)
+		})
+}
+
+func (c *CachedArticleReviewRepository) toEntity(r domain.ArticleReview) dao.ArticleReview {
+	return dao.ArticleReview{
+		ArticleId:  r.ArticleId,
+		AuthorId:   r.AuthorId,
+		ReviewerId: r.ReviewerId,
+		Status:     r.Status.ToUint8(),
+		Title:      r.Title,
+		Content:    r.Content,
+	}
+}
+
+func (c *CachedArticleReviewRepository) toDomain(r dao.ArticleReview) domain.ArticleReview {
+	return domain.ArticleReview{
+		ArticleId:  r.ArticleId,
+		AuthorId:   r.AuthorId,
+		ReviewerId: r.ReviewerId,
+		Status:     domain.ReviewStatus(r.Status),
+		Title:      r.Title,
+		Content:    r.Content,
+		Ctime:      time.UnixMilli(r.Ctime),
+		Utime:      time.UnixMilli(r.Utime),
+	}
+}
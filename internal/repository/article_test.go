@@ -2,12 +2,16 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"gitee.com/geekbang/basic-go/webook/internal/repository/cache"
+	cachemocks "gitee.com/geekbang/basic-go/webook/internal/repository/cache/mocks"
 	"gitee.com/geekbang/basic-go/webook/internal/repository/dao"
 	daomocks "gitee.com/geekbang/basic-go/webook/internal/repository/dao/mocks"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"testing"
+	"time"
 )
 
 func TestCachedArticleRepository_SyncV1(t *testing.T) {
@@ -90,3 +94,157 @@ func TestCachedArticleRepository_SyncV1(t *testing.T) {
 		})
 	}
 }
+
+func TestCachedArticleRepository_GetByAuthor(t *testing.T) {
+	daoArts := []dao.Article{
+		{Id: 3, AuthorId: 123, Ctime: 1000, Utime: 3000},
+		{Id: 2, AuthorId: 123, Ctime: 1000, Utime: 2000},
+		{Id: 1, AuthorId: 123, Ctime: 1000, Utime: 1000},
+	}
+	domainArts := []domain.Article{
+		{Id: 3, Author: domain.Author{Id: 123}, Ctime: time.UnixMilli(1000), Utime: time.UnixMilli(3000)},
+		{Id: 2, Author: domain.Author{Id: 123}, Ctime: time.UnixMilli(1000), Utime: time.UnixMilli(2000)},
+		{Id: 1, Author: domain.Author{Id: 123}, Ctime: time.UnixMilli(1000), Utime: time.UnixMilli(1000)},
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (dao.ArticleDAO, cache.ArticleCache)
+
+		cursor domain.ArticleCursor
+		limit  int
+
+		wantArts []domain.Article
+		wantErr  error
+	}{
+		{
+			name: "第一页命中缓存",
+			mock: func(ctrl *gomock.Controller) (dao.ArticleDAO, cache.ArticleCache) {
+				d := daomocks.NewMockArticleDAO(ctrl)
+				c := cachemocks.NewMockArticleCache(ctrl)
+				c.EXPECT().GetFirstPage(gomock.Any(), int64(123)).Return(domainArts, nil)
+				return d, c
+			},
+			limit:    2,
+			wantArts: domainArts[:2],
+		},
+		{
+			name: "第一页没有命中缓存，取满一页回写缓存",
+			mock: func(ctrl *gomock.Controller) (dao.ArticleDAO, cache.ArticleCache) {
+				d := daomocks.NewMockArticleDAO(ctrl)
+				c := cachemocks.NewMockArticleCache(ctrl)
+				c.EXPECT().GetFirstPage(gomock.Any(), int64(123)).
+					Return(nil, errors.New("缓存未命中"))
+				d.EXPECT().GetByAuthor(gomock.Any(), int64(123), int64(0), int64(0), 100).
+					Return(daoArts, nil)
+				c.EXPECT().SetFirstPage(gomock.Any(), int64(123), gomock.Any()).
+					Return(nil).AnyTimes()
+				c.EXPECT().Set(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+				return d, c
+			},
+			limit:    2,
+			wantArts: domainArts[:2],
+		},
+		{
+			name: "不是第一页，不查缓存",
+			mock: func(ctrl *gomock.Controller) (dao.ArticleDAO, cache.ArticleCache) {
+				d := daomocks.NewMockArticleDAO(ctrl)
+				c := cachemocks.NewMockArticleCache(ctrl)
+				d.EXPECT().GetByAuthor(gomock.Any(), int64(123), int64(3000), int64(3), 2).
+					Return(daoArts[1:], nil)
+				c.EXPECT().Set(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+				return d, c
+			},
+			cursor:   domain.ArticleCursor{Utime: 3000, Id: 3},
+			limit:    2,
+			wantArts: domainArts[1:],
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			d, c := tc.mock(ctrl)
+			repo := NewCachedArticleRepository(d, nil, c)
+			arts, err := repo.GetByAuthor(context.Background(), 123, tc.cursor, tc.limit)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantArts, arts)
+		})
+	}
+}
+
+func TestCachedArticleRepository_GetById(t *testing.T) {
+	collabs := []dao.ArticleCollaborator{
+		{ArticleId: 1, Uid: 456, Role: 1, Status: 2, InviterId: 123, Ctime: 1000, Utime: 2000},
+	}
+	wantCollabs := []domain.ArticleCollaborator{
+		{ArticleId: 1, User: domain.Author{Id: 456}, Role: 1, Status: 2, InviterId: 123,
+			Ctime: time.UnixMilli(1000), Utime: time.UnixMilli(2000)},
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (dao.ArticleDAO, cache.ArticleCache)
+
+		wantArt domain.Article
+		wantErr error
+	}{
+		{
+			name: "命中缓存，合作者从数据库里面查",
+			mock: func(ctrl *gomock.Controller) (dao.ArticleDAO, cache.ArticleCache) {
+				d := daomocks.NewMockArticleDAO(ctrl)
+				c := cachemocks.NewMockArticleCache(ctrl)
+				// 列表预加载的缓存，没有合作者
+				c.EXPECT().Get(gomock.Any(), int64(1)).
+					Return(domain.Article{Id: 1, Author: domain.Author{Id: 123}}, nil)
+				d.EXPECT().ListCollaborators(gomock.Any(), int64(1)).Return(collabs, nil)
+				return d, c
+			},
+			wantArt: domain.Article{Id: 1, Author: domain.Author{Id: 123}, Collaborators: wantCollabs},
+		},
+		{
+			name: "命中缓存，查合作者失败",
+			mock: func(ctrl *gomock.Controller) (dao.ArticleDAO, cache.ArticleCache) {
+				d := daomocks.NewMockArticleDAO(ctrl)
+				c := cachemocks.NewMockArticleCache(ctrl)
+				c.EXPECT().Get(gomock.Any(), int64(1)).
+					Return(domain.Article{Id: 1, Author: domain.Author{Id: 123}}, nil)
+				d.EXPECT().ListCollaborators(gomock.Any(), int64(1)).
+					Return(nil, errors.New("mock db error"))
+				return d, c
+			},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			d, c := tc.mock(ctrl)
+			repo := NewCachedArticleRepository(d, nil, c)
+			art, err := repo.GetById(context.Background(), 1)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantArt, art)
+		})
+	}
+}
+
+func TestCachedArticleRepository_AcceptCollaborator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	d := daomocks.NewMockArticleDAO(ctrl)
+	c := cachemocks.NewMockArticleCache(ctrl)
+	d.EXPECT().AcceptCollaborator(gomock.Any(), int64(1), int64(456)).Return(nil)
+	c.EXPECT().Del(gomock.Any(), int64(1)).Return(nil)
+	c.EXPECT().DelPub(gomock.Any(), int64(1)).Return(nil)
+	d.EXPECT().GetById(gomock.Any(), int64(1)).Return(dao.Article{Id: 1, AuthorId: 123}, nil)
+	d.EXPECT().ListCollaborators(gomock.Any(), int64(1)).Return([]dao.ArticleCollaborator{
+		{ArticleId: 1, Uid: 456}, {ArticleId: 1, Uid: 789},
+	}, nil)
+	// 创作者、接受邀请的人和别的合作者的第一页都要删掉
+	c.EXPECT().DelFirstPage(gomock.Any(), int64(456)).Return(nil)
+	c.EXPECT().DelFirstPage(gomock.Any(), int64(123)).Return(nil)
+	c.EXPECT().DelFirstPage(gomock.Any(), int64(789)).Return(nil)
+	repo := NewCachedArticleRepository(d, nil, c)
+	err := repo.AcceptCollaborator(context.Background(), 1, 456)
+	assert.NoError(t, err)
+}
@@ -16,6 +16,7 @@ type ArticleCache interface {
 	DelFirstPage(ctx context.Context, uid int64) error
 	Get(ctx context.Context, id int64) (domain.Article, error)
 	Set(ctx context.Context, art domain.Article) error
+	Del(ctx context.Context, id int64) error
 	GetPub(ctx context.Context, id int64) (domain.Article, error)
 	SetPub(ctx context.Context, res domain.Article) error
 	DelPub(ctx context.Context, id int64) error
@@ -29,6 +30,10 @@ func (a *ArticleRedisCache) DelFirstPage(ctx context.Context, uid int64) error {
 	return a.client.Del(ctx, a.firstKey(uid)).Err()
 }
 
+func (a *ArticleRedisCache) Del(ctx context.Context, id int64) error {
+	return a.client.Del(ctx, a.key(id)).Err()
+}
+
 func (a *ArticleRedisCache) DelPub(ctx context.Context, id int64) error {
 	return a.client.Del(ctx, a.pubKey(id)).Err()
 }
@@ -0,0 +1,22 @@
+-- 实时热榜的 sorted set
+local key = KEYS[1]
+-- 计算衰减的起点，纠偏的时候会重置
+local epochKey = KEYS[2]
+local member = ARGV[1]
+local weight = tonumber(ARGV[2])
+-- 毫秒
+local now = tonumber(ARGV[3])
+local halfLife = tonumber(ARGV[4])
+-- 最多保留多少篇文章
+local capacity = tonumber(ARGV[5])
+
+local epoch = tonumber(redis.call("GET", epochKey))
+if epoch == nil then
+    epoch = now
+    redis.call("SET", epochKey, epoch)
+end
+-- 越晚发生的行为分数越高，等价于之前的分数都衰减了
+local delta = weight * math.pow(2, (now - epoch) / halfLife)
+redis.call("ZINCRBY", key, delta, member)
+redis.call("ZREMRANGEBYRANK", key, 0, -capacity - 1)
+return 1
@@ -2,15 +2,31 @@ package cache
 
 import (
 	"context"
+	_ "embed"
 	"encoding/json"
+	"fmt"
 	"gitee.com/geekbang/basic-go/webook/internal/domain"
 	"github.com/redis/go-redis/v9"
+	"strconv"
 	"time"
 )
 
+//go:embed lua/incr_ranking_score.lua
+var luaIncrRankingScore string
+
 type RankingCache interface {
-	Set(ctx context.Context, arts []domain.Article) error
-	Get(ctx context.Context) ([]domain.Article, error)
+	// Set key 是热榜的名字，每个热榜单独存
+	Set(ctx context.Context, key string, arts []domain.Article) error
+	Get(ctx context.Context, key string) ([]domain.Article, error)
+
+	// IncrScore 实时热榜，at 这个时候发生的行为给 aid 加上 weight 分。
+	// 只保留分数最高的 capacity 篇
+	IncrScore(ctx context.Context, key string, aid int64, weight float64,
+		at time.Time, halfLife time.Duration, capacity int) error
+	// ReplaceScores 用定时任务算出来的分数覆盖实时热榜，epoch 是这些分数的衰减起点
+	ReplaceScores(ctx context.Context, key string, scores map[int64]float64, epoch time.Time) error
+	// GetTopIds 实时热榜里面分数最高的 n 篇
+	GetTopIds(ctx context.Context, key string, n int) ([]int64, error)
 }
 
 type RankingRedisCache struct {
@@ -19,7 +35,7 @@ type RankingRedisCache struct {
 	expiration time.Duration
 }
 
-func (r *RankingRedisCache) Set(ctx context.Context, arts []domain.Article) error {
+func (r *RankingRedisCache) Set(ctx context.Context, key string, arts []domain.Article) error {
 	for i := range arts {
 		arts[i].Content = arts[i].Abstract()
 	}
@@ -27,11 +43,11 @@ func (r *RankingRedisCache) Set(ctx context.Context, arts []domain.Article) erro
 	if err != nil {
 		return err
 	}
-	return r.client.Set(ctx, r.key, val, r.expiration).Err()
+	return r.client.Set(ctx, r.listKey(key), val, r.expiration).Err()
 }
 
-func (r *RankingRedisCache) Get(ctx context.Context) ([]domain.Article, error) {
-	val, err := r.client.Get(ctx, r.key).Bytes()
+func (r *RankingRedisCache) Get(ctx context.Context, key string) ([]domain.Article, error) {
+	val, err := r.client.Get(ctx, r.listKey(key)).Bytes()
 	if err != nil {
 		return nil, err
 	}
@@ -40,6 +56,58 @@ func (r *RankingRedisCache) Get(ctx context.Context) ([]domain.Article, error) {
 	return res, err
 }
 
+func (r *RankingRedisCache) IncrScore(ctx context.Context, key string, aid int64, weight float64,
+	at time.Time, halfLife time.Duration, capacity int) error {
+	return r.client.Eval(ctx, luaIncrRankingScore,
+		[]string{r.scoreKey(key), r.epochKey(key)},
+		aid, weight, at.UnixMilli(), halfLife.Milliseconds(), capacity).Err()
+}
+
+func (r *RankingRedisCache) ReplaceScores(ctx context.Context, key string,
+	scores map[int64]float64, epoch time.Time) error {
+	members := make([]redis.Z, 0, len(scores))
+	for aid, score := range scores {
+		members = append(members, redis.Z{Score: score, Member: aid})
+	}
+	// 分数和衰减起点要一起换掉
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, r.scoreKey(key))
+	if len(members) > 0 {
+		pipe.ZAdd(ctx, r.scoreKey(key), members...)
+	}
+	pipe.Set(ctx, r.epochKey(key), epoch.UnixMilli(), 0)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *RankingRedisCache) GetTopIds(ctx context.Context, key string, n int) ([]int64, error) {
+	vals, err := r.client.ZRevRange(ctx, r.scoreKey(key), 0, int64(n-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]int64, 0, len(vals))
+	for _, val := range vals {
+		aid, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, aid)
+	}
+	return res, nil
+}
+
+func (r *RankingRedisCache) listKey(key string) string {
+	return fmt.Sprintf("%s:%s", r.key, key)
+}
+
+func (r *RankingRedisCache) scoreKey(key string) string {
+	return fmt.Sprintf("ranking:score:%s", key)
+}
+
+func (r *RankingRedisCache) epochKey(key string) string {
+	return fmt.Sprintf("ranking:epoch:%s", key)
+}
+
 func NewRankingRedisCache(client redis.Cmdable) RankingCache {
 	return &RankingRedisCache{
 		client:     client,
@@ -14,29 +14,119 @@ type ArticleDAO interface {
 	UpdateById(ctx context.Context, entity Article) error
 	Sync(ctx context.Context, entity Article) (int64, error)
 	SyncStatus(ctx context.Context, uid int64, id int64, status uint8) error
-	GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]Article, error)
+	// GetByAuthor 按照 utime, id 倒序，取排在 (utime, id) 后面的 limit 篇。
+	// utime 为 0 代表第一页
+	GetByAuthor(ctx context.Context, uid int64, utime int64, id int64, limit int) ([]Article, error)
 	GetById(ctx context.Context, id int64) (Article, error)
 	GetPubById(ctx context.Context, id int64) (PublishedArticle, error)
-	ListPub(ctx context.Context, start time.Time, offset int, limit int) ([]PublishedArticle, error)
+	// ListPub 和 GetByAuthor 一样的分页方式
+	ListPub(ctx context.Context, utime int64, id int64, limit int) ([]PublishedArticle, error)
+	// ListPubByIds 只返回已经发表的，不保证顺序
+	ListPubByIds(ctx context.Context, ids []int64) ([]PublishedArticle, error)
+	ListRevisions(ctx context.Context, artId int64, offset int, limit int) ([]ArticleRevision, error)
+	GetRevisionById(ctx context.Context, id int64) (ArticleRevision, error)
+	ListScheduled(ctx context.Context, uid int64) ([]Article, error)
+	// ListDueScheduled 找到 publishAt 之前应该发表的定时文章
+	ListDueScheduled(ctx context.Context, publishAt int64, limit int) ([]Article, error)
+	// UpdateSchedule 只会修改处于定时发表状态的文章
+	UpdateSchedule(ctx context.Context, uid int64, id int64, status uint8, publishAt int64) error
+
+	// InsertCollaborator 只有创作者本人可以邀请，重复邀请会覆盖之前的角色
+	InsertCollaborator(ctx context.Context, c ArticleCollaborator) error
+	AcceptCollaborator(ctx context.Context, aid int64, uid int64) error
+	// DeleteCollaborator 创作者可以移除任何人，合作者只能移除自己
+	DeleteCollaborator(ctx context.Context, operator int64, aid int64, uid int64) error
+	ListCollaborators(ctx context.Context, aid int64) ([]ArticleCollaborator, error)
 }
 
 type ArticleGORMDAO struct {
 	db *gorm.DB
 }
 
-func (a *ArticleGORMDAO) ListPub(ctx context.Context, start time.Time, offset int, limit int) ([]PublishedArticle, error) {
+func (a *ArticleGORMDAO) ListRevisions(ctx context.Context, artId int64, offset int, limit int) ([]ArticleRevision, error) {
+	var res []ArticleRevision
+	err := a.db.WithContext(ctx).
+		Where("article_id = ?", artId).
+		Offset(offset).Limit(limit).
+		Order("id DESC").
+		Find(&res).Error
+	return res, err
+}
+
+func (a *ArticleGORMDAO) GetRevisionById(ctx context.Context, id int64) (ArticleRevision, error) {
+	var res ArticleRevision
+	err := a.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&res).Error
+	return res, err
+}
+
+func (a *ArticleGORMDAO) ListScheduled(ctx context.Context, uid int64) ([]Article, error) {
+	var res []Article
+	err := a.db.WithContext(ctx).
+		Where("author_id = ? AND status = ?", uid, articleStatusScheduled).
+		Order("publish_at ASC").
+		Find(&res).Error
+	return res, err
+}
+
+func (a *ArticleGORMDAO) ListDueScheduled(ctx context.Context, publishAt int64, limit int) ([]Article, error) {
+	var res []Article
+	err := a.db.WithContext(ctx).
+		Where("status = ? AND publish_at <= ?", articleStatusScheduled, publishAt).
+		Order("publish_at ASC").
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (a *ArticleGORMDAO) UpdateSchedule(ctx context.Context, uid int64, id int64, status uint8, publishAt int64) error {
+	res := a.db.WithContext(ctx).Model(&Article{}).
+		Where("id = ? AND author_id = ? AND status = ?", id, uid, articleStatusScheduled).
+		Updates(map[string]any{
+			"status":     status,
+			"publish_at": publishAt,
+			"utime":      time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("ID 不对或者创作者不对，或者文章不是定时发表状态")
+	}
+	return nil
+}
+
+func (a *ArticleGORMDAO) ListPub(ctx context.Context, utime int64, id int64, limit int) ([]PublishedArticle, error) {
 	ctx, cancel := context.WithTimeout(ctx, time.Millisecond*100)
 	defer cancel()
 	var res []PublishedArticle
 	const ArticleStatusPublished = 2
+	err := a.afterCursor(a.db.WithContext(ctx), utime, id).
+		Where("status = ?", ArticleStatusPublished).
+		Order("utime DESC, id DESC").
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (a *ArticleGORMDAO) ListPubByIds(ctx context.Context, ids []int64) ([]PublishedArticle, error) {
+	var res []PublishedArticle
 	err := a.db.WithContext(ctx).
-		Where("utime < ? AND status = ?",
-			start.UnixMilli(), ArticleStatusPublished).
-		Offset(offset).Limit(limit).
+		Where("id IN ? AND status = ?", ids, articleStatusPublished).
 		Find(&res).Error
 	return res, err
 }
 
+// afterCursor 按照 utime DESC, id DESC 排序的时候，排在 (utime, id) 后面的数据。
+// 不管中间有没有新发表的文章，都不会重复也不会遗漏
+func (a *ArticleGORMDAO) afterCursor(db *gorm.DB, utime int64, id int64) *gorm.DB {
+	if utime == 0 {
+		return db
+	}
+	return db.Where("(utime < ? OR (utime = ? AND id < ?))", utime, utime, id)
+}
+
 func (a *ArticleGORMDAO) GetPubById(ctx context.Context, id int64) (PublishedArticle, error) {
 	var res PublishedArticle
 	err := a.db.WithContext(ctx).
@@ -52,13 +142,16 @@ func (a *ArticleGORMDAO) GetById(ctx context.Context, id int64) (Article, error)
 	return art, err
 }
 
-func (a *ArticleGORMDAO) GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]Article, error) {
+func (a *ArticleGORMDAO) GetByAuthor(ctx context.Context, uid int64, utime int64, id int64, limit int) ([]Article, error) {
 	var arts []Article
-	err := a.db.WithContext(ctx).
-		Where("author_id = ?", uid).
-		Offset(offset).Limit(limit).
+	// 合作者也能在自己的列表里面看到
+	collaborated := a.db.Model(&ArticleCollaborator{}).Select("article_id").
+		Where("uid = ? AND status = ?", uid, collaboratorStatusAccepted)
+	err := a.afterCursor(a.db.WithContext(ctx), utime, id).
+		Where("(author_id = ? OR id IN (?))", uid, collaborated).
+		Limit(limit).
 		// a ASC, B DESC
-		Order("utime DESC").
+		Order("utime DESC, id DESC").
 		Find(&arts).Error
 	return arts, err
 }
@@ -66,20 +159,21 @@ func (a *ArticleGORMDAO) GetByAuthor(ctx context.Context, uid int64, offset int,
 func (a *ArticleGORMDAO) SyncStatus(ctx context.Context, uid int64, id int64, status uint8) error {
 	now := time.Now().UnixMilli()
 	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		res := tx.Model(&Article{}).
-			Where("id = ? and author_id = ?", uid, id).
+		_, err := a.checkEditable(tx, id, uid)
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&Article{}).
+			Where("id = ?", id).
 			Updates(map[string]any{
 				"utime":  now,
 				"status": status,
-			})
-		if res.Error != nil {
-			return res.Error
-		}
-		if res.RowsAffected != 1 {
-			return errors.New("ID 不对或者创作者不对")
+			}).Error
+		if err != nil {
+			return err
 		}
 		return tx.Model(&PublishedArticle{}).
-			Where("id = ?", uid).
+			Where("id = ?", id).
 			Updates(map[string]any{
 				"utime":  now,
 				"status": status,
@@ -95,7 +189,8 @@ func (a *ArticleGORMDAO) Sync(ctx context.Context, art Article) (int64, error) {
 		)
 		dao := NewArticleGORMDAO(tx)
 		if id > 0 {
-			err = dao.UpdateById(ctx, art)
+			// 可能是编辑在发表，线上库里面的作者还是创作者本人
+			art.AuthorId, err = a.update(tx, art, time.Now().UnixMilli())
 		} else {
 			id, err = dao.Insert(ctx, art)
 		}
@@ -114,10 +209,15 @@ func (a *ArticleGORMDAO) Sync(ctx context.Context, art Article) (int64, error) {
 			// sqlite INSERT XXX ON CONFLICT DO UPDATES WHERE
 			Columns: []clause.Column{{Name: "id"}},
 			DoUpdates: clause.Assignments(map[string]interface{}{
-				"title":   pubArt.Title,
-				"content": pubArt.Content,
-				"utime":   now,
-				"status":  pubArt.Status,
+				"title":            pubArt.Title,
+				"content":          pubArt.Content,
+				"utime":            now,
+				"status":           pubArt.Status,
+				"rendered_content": pubArt.RenderedContent,
+				"toc":              pubArt.Toc,
+				"abstract":         pubArt.Abstract,
+				"reading_minutes":  pubArt.ReadingMinutes,
+				"members_only":     pubArt.MembersOnly,
 			}),
 		}).Create(&pubArt).Error
 		return err
@@ -172,29 +272,82 @@ func (a *ArticleGORMDAO) SyncV1(ctx context.Context, art Article) (int64, error)
 
 func (a *ArticleGORMDAO) UpdateById(ctx context.Context, art Article) error {
 	now := time.Now().UnixMilli()
-	res := a.db.WithContext(ctx).Model(&art).
-		Where("id = ? AND author_id = ?", art.Id, art.AuthorId).Updates(map[string]any{
-		"title":   art.Title,
-		"content": art.Content,
-		"status":  art.Status,
-		"utime":   now,
+	// 在同一个事务里面记录历史版本，保证每一次修改都有迹可循
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		_, err := a.update(tx, art, now)
+		return err
+	})
+}
+
+// update art.AuthorId 是修改的人，可以是创作者本人，也可以是编辑。
+// 返回文章真正的创作者
+func (a *ArticleGORMDAO) update(tx *gorm.DB, art Article, now int64) (int64, error) {
+	owner, err := a.checkEditable(tx, art.Id, art.AuthorId)
+	if err != nil {
+		return 0, err
+	}
+	db := tx.Model(&Article{}).Where("id = ?", art.Id)
+	if art.Status != articleStatusPublished {
+		// 审核中的草稿不能修改，只有审核通过的时候会同步过来
+		db = db.Where("status <> ?", articleStatusReviewing)
+	}
+	res := db.Updates(map[string]any{
+		"title":        art.Title,
+		"content":      art.Content,
+		"status":       art.Status,
+		"publish_at":   art.PublishAt,
+		"members_only": art.MembersOnly,
+		"utime":        now,
 	})
 	if res.Error != nil {
-		return res.Error
+		return 0, res.Error
 	}
-	// 我怎么知道有没有更新数据？
 	if res.RowsAffected == 0 {
+		return 0, ErrArticleReviewing
+	}
+	// 历史版本里面记录的是真正动手修改的人
+	return owner, tx.Create(newArticleRevision(art, now)).Error
+}
+
+// checkEditable 创作者本人或者已经接受邀请的编辑才能修改，返回文章真正的创作者
+func (a *ArticleGORMDAO) checkEditable(tx *gorm.DB, id int64, uid int64) (int64, error) {
+	var art Article
+	err := tx.Select("id", "author_id").Where("id = ?", id).First(&art).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, ErrArticleNoPermission
+	}
+	if err != nil {
+		return 0, err
+	}
+	if art.AuthorId == uid {
+		return uid, nil
+	}
+	var cnt int64
+	err = tx.Model(&ArticleCollaborator{}).
+		Where("article_id = ? AND uid = ? AND role = ? AND status = ?",
+			id, uid, collaboratorRoleEditor, collaboratorStatusAccepted).
+		Count(&cnt).Error
+	if err != nil {
+		return 0, err
+	}
+	if cnt == 0 {
 		// 创作者不对，说明有人在瞎搞
-		return errors.New("ID 不对或者创作者不对")
+		return 0, ErrArticleNoPermission
 	}
-	return nil
+	return art.AuthorId, nil
 }
 
 func (a *ArticleGORMDAO) Insert(ctx context.Context, art Article) (int64, error) {
 	now := time.Now().UnixMilli()
 	art.Ctime = now
 	art.Utime = now
-	err := a.db.WithContext(ctx).Create(&art).Error
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&art).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(newArticleRevision(art, now)).Error
+	})
 	return art.Id, err
 }
 
@@ -211,13 +364,55 @@ type Article struct {
 	// 我要根据创作者ID来查询
 	AuthorId int64 `gorm:"index" bson:"author_id,omitempty"`
 	Status   uint8 `bson:"status,omitempty"`
-	Ctime    int64 `bson:"ctime,omitempty"`
-	// 更新时间
-	Utime int64 `bson:"utime,omitempty"`
+	// 定时发表的时间，用来找出到期的文章
+	PublishAt int64 `gorm:"index" bson:"publish_at,omitempty"`
+	Ctime     int64 `bson:"ctime,omitempty"`
+	// 更新时间，列表按照它来分页
+	Utime int64 `gorm:"index" bson:"utime,omitempty"`
+
+	// 下面这些是发表的时候从 Content 渲染出来的，只有线上库有意义
+	RenderedContent string `gorm:"type=BLOB" bson:"rendered_content,omitempty"`
+	// 目录，JSON 格式
+	Toc            string `gorm:"type=BLOB" bson:"toc,omitempty"`
+	Abstract       string `gorm:"type=varchar(1024)" bson:"abstract,omitempty"`
+	ReadingMinutes int    `bson:"reading_minutes,omitempty"`
+	// MembersOnly 只有作者的付费会员才能看
+	MembersOnly bool `bson:"members_only,omitempty"`
 }
 
+// 对应 domain.ArticleStatus
+const (
+	articleStatusUnpublished = 1
+	articleStatusPublished   = 2
+	articleStatusScheduled   = 4
+	articleStatusReviewing   = 5
+)
+
 type PublishedArticle Article
 
+// ArticleRevision 文章的历史版本，只会插入，不会更新
+type ArticleRevision struct {
+	Id int64 `gorm:"primaryKey,autoIncrement" bson:"id,omitempty"`
+	// 按照文章来查询历史版本
+	ArticleId int64  `gorm:"index" bson:"article_id,omitempty"`
+	AuthorId  int64  `bson:"author_id,omitempty"`
+	Title     string `gorm:"type=varchar(4096)" bson:"title,omitempty"`
+	Content   string `gorm:"type=BLOB" bson:"content,omitempty"`
+	Status    uint8  `bson:"status,omitempty"`
+	Ctime     int64  `bson:"ctime,omitempty"`
+}
+
+func newArticleRevision(art Article, now int64) *ArticleRevision {
+	return &ArticleRevision{
+		ArticleId: art.Id,
+		AuthorId:  art.AuthorId,
+		Title:     art.Title,
+		Content:   art.Content,
+		Status:    art.Status,
+		Ctime:     now,
+	}
+}
+
 type PublishedArticleV1 struct {
 	Article
 }
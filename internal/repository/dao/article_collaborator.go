@@ -0,0 +1,109 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+var ErrArticleNoPermission = errors.New("ID 不对或者没有权限")
+
+// 对应 domain.CollaboratorRole 和 domain.CollaboratorStatus
+const (
+	collaboratorRoleEditor     = 1
+	collaboratorStatusInvited  = 1
+	collaboratorStatusAccepted = 2
+)
+
+func (a *ArticleGORMDAO) InsertCollaborator(ctx context.Context, c ArticleCollaborator) error {
+	now := time.Now().UnixMilli()
+	c.Ctime = now
+	c.Utime = now
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var cnt int64
+		err := tx.Model(&Article{}).
+			Where("id = ? AND author_id = ?", c.ArticleId, c.InviterId).
+			Count(&cnt).Error
+		if err != nil {
+			return err
+		}
+		if cnt == 0 {
+			return ErrArticleNoPermission
+		}
+		// 重新邀请的话，要等对方再接受一次
+		return tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.Assignments(map[string]any{
+				"role":       c.Role,
+				"status":     c.Status,
+				"inviter_id": c.InviterId,
+				"utime":      now,
+			}),
+		}).Create(&c).Error
+	})
+}
+
+func (a *ArticleGORMDAO) AcceptCollaborator(ctx context.Context, aid int64, uid int64) error {
+	res := a.db.WithContext(ctx).Model(&ArticleCollaborator{}).
+		Where("article_id = ? AND uid = ? AND status = ?", aid, uid, collaboratorStatusInvited).
+		Updates(map[string]any{
+			"status": collaboratorStatusAccepted,
+			"utime":  time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrArticleNoPermission
+	}
+	return nil
+}
+
+func (a *ArticleGORMDAO) DeleteCollaborator(ctx context.Context, operator int64, aid int64, uid int64) error {
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if operator != uid {
+			var cnt int64
+			err := tx.Model(&Article{}).
+				Where("id = ? AND author_id = ?", aid, operator).
+				Count(&cnt).Error
+			if err != nil {
+				return err
+			}
+			if cnt == 0 {
+				return ErrArticleNoPermission
+			}
+		}
+		res := tx.Where("article_id = ? AND uid = ?", aid, uid).
+			Delete(&ArticleCollaborator{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrArticleNoPermission
+		}
+		return nil
+	})
+}
+
+func (a *ArticleGORMDAO) ListCollaborators(ctx context.Context, aid int64) ([]ArticleCollaborator, error) {
+	var res []ArticleCollaborator
+	err := a.db.WithContext(ctx).
+		Where("article_id = ?", aid).
+		Order("id ASC").
+		Find(&res).Error
+	return res, err
+}
+
+// ArticleCollaborator 文章的合作者，创作者本人不在这里面
+type ArticleCollaborator struct {
+	Id        int64 `gorm:"primaryKey,autoIncrement" bson:"id,omitempty"`
+	ArticleId int64 `gorm:"uniqueIndex:uk_article_uid" bson:"article_id,omitempty"`
+	// 按照合作者来找文章
+	Uid       int64 `gorm:"uniqueIndex:uk_article_uid;index" bson:"uid,omitempty"`
+	Role      uint8 `bson:"role,omitempty"`
+	Status    uint8 `bson:"status,omitempty"`
+	InviterId int64 `bson:"inviter_id,omitempty"`
+	Ctime     int64 `bson:"ctime,omitempty"`
+	Utime     int64 `bson:"utime,omitempty"`
+}
@@ -0,0 +1,217 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+var (
+	ErrReviewStatusConflict = errors.New("审核单不存在，或者状态不对，或者不是你认领的")
+	ErrArticleReviewing     = errors.New("文章正在审核，不能修改")
+)
+
+// 对应 domain.ReviewStatus
+const (
+	reviewStatusSubmitted        = 1
+	reviewStatusInReview         = 2
+	reviewStatusChangesRequested = 3
+	reviewStatusRejected         = 5
+)
+
+type ArticleReviewDAO interface {
+	// Submit 提交审核，已经有审核单的话，要处于 from 里面的状态才能重新提交。
+	// 同时把草稿标记为审核中。
+	// from 不能用 []uint8，GORM 会把它当成一个 []byte，IN 就永远匹配不上
+	Submit(ctx context.Context, r ArticleReview, from []int) error
+	// Claim 认领一个等待审核的审核单
+	Claim(ctx context.Context, aid int64, reviewer int64) error
+	// Transit 只有审核单是 reviewer 认领的，并且处于 from 里面的状态，才能变更到 to。
+	// 返回变更之后的审核单
+	Transit(ctx context.Context, aid int64, reviewer int64,
+		from []int, to uint8, comment string) (ArticleReview, error)
+	GetByArticle(ctx context.Context, aid int64) (ArticleReview, error)
+	// ListComments 按照时间顺序排列
+	ListComments(ctx context.Context, aid int64) ([]ArticleReviewComment, error)
+	// ListQueue 还没有人认领的，加上 reviewer 自己认领了还没有处理完的，先提交的在前面
+	ListQueue(ctx context.Context, reviewer int64, offset int, limit int) ([]ArticleReview, error)
+}
+
+type ArticleReviewGORMDAO struct {
+	db *gorm.DB
+}
+
+func NewArticleReviewGORMDAO(db *gorm.DB) ArticleReviewDAO {
+	return &ArticleReviewGORMDAO{db: db}
+}
+
+func (a *ArticleReviewGORMDAO) Submit(ctx context.Context, r ArticleReview, from []int) error {
+	now := time.Now().UnixMilli()
+	r.Status = reviewStatusSubmitted
+	r.ReviewerId = 0
+	r.Utime = now
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var old ArticleReview
+		err := tx.Where("article_id = ?", r.ArticleId).First(&old).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			r.Ctime = now
+			err = tx.Create(&r).Error
+		case err != nil:
+			return err
+		default:
+			// 乐观锁，避免和审核的人并发修改
+			res := tx.Model(&ArticleReview{}).
+				Where("article_id = ? AND status IN ?", r.ArticleId, from).
+				Updates(map[string]any{
+					"author_id":   r.AuthorId,
+					"reviewer_id": 0,
+					"status":      r.Status,
+					"title":       r.Title,
+					"content":     r.Content,
+					"utime":       now,
+				})
+			err = res.Error
+			if err == nil && res.RowsAffected == 0 {
+				err = ErrReviewStatusConflict
+			}
+		}
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&Article{}).Where("id = ?", r.ArticleId).
+			Updates(map[string]any{
+				"status": articleStatusReviewing,
+				"utime":  now,
+			}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(&ArticleReviewComment{
+			ArticleId: r.ArticleId,
+			Status:    r.Status,
+			Ctime:     now,
+		}).Error
+	})
+}
+
+func (a *ArticleReviewGORMDAO) Claim(ctx context.Context, aid int64, reviewer int64) error {
+	now := time.Now().UnixMilli()
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&ArticleReview{}).
+			Where("article_id = ? AND status = ?", aid, reviewStatusSubmitted).
+			Updates(map[string]any{
+				"reviewer_id": reviewer,
+				"status":      reviewStatusInReview,
+				"utime":       now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrReviewStatusConflict
+		}
+		return tx.Create(&ArticleReviewComment{
+			ArticleId:  aid,
+			ReviewerId: reviewer,
+			Status:     reviewStatusInReview,
+			Ctime:      now,
+		}).Error
+	})
+}
+
+func (a *ArticleReviewGORMDAO) Transit(ctx context.Context, aid int64, reviewer int64,
+	from []int, to uint8, comment string) (ArticleReview, error) {
+	now := time.Now().UnixMilli()
+	var res ArticleReview
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		upd := tx.Model(&ArticleReview{}).
+			Where("article_id = ? AND reviewer_id = ? AND status IN ?", aid, reviewer, from).
+			Updates(map[string]any{
+				"status": to,
+				"utime":  now,
+			})
+		if upd.Error != nil {
+			return upd.Error
+		}
+		if upd.RowsAffected == 0 {
+			return ErrReviewStatusConflict
+		}
+		if to == reviewStatusChangesRequested || to == reviewStatusRejected {
+			// 草稿回到未发表，作者可以接着修改。
+			// 审核通过的时候，同步线上库会顺便更新草稿的状态
+			err := tx.Model(&Article{}).
+				Where("id = ? AND status = ?", aid, articleStatusReviewing).
+				Updates(map[string]any{
+					"status": articleStatusUnpublished,
+					"utime":  now,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		err := tx.Create(&ArticleReviewComment{
+			ArticleId:  aid,
+			ReviewerId: reviewer,
+			Status:     to,
+			Comment:    comment,
+			Ctime:      now,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("article_id = ?", aid).First(&res).Error
+	})
+	return res, err
+}
+
+func (a *ArticleReviewGORMDAO) GetByArticle(ctx context.Context, aid int64) (ArticleReview, error) {
+	var res ArticleReview
+	err := a.db.WithContext(ctx).Where("article_id = ?", aid).First(&res).Error
+	return res, err
+}
+
+func (a *ArticleReviewGORMDAO) ListComments(ctx context.Context, aid int64) ([]ArticleReviewComment, error) {
+	var res []ArticleReviewComment
+	err := a.db.WithContext(ctx).
+		Where("article_id = ?", aid).
+		Order("id ASC").
+		Find(&res).Error
+	return res, err
+}
+
+func (a *ArticleReviewGORMDAO) ListQueue(ctx context.Context, reviewer int64, offset int, limit int) ([]ArticleReview, error) {
+	var res []ArticleReview
+	err := a.db.WithContext(ctx).
+		Where("status = ? OR (status = ? AND reviewer_id = ?)",
+			reviewStatusSubmitted, reviewStatusInReview, reviewer).
+		Order("utime ASC").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+// ArticleReview 一篇文章只有一个审核单
+type ArticleReview struct {
+	Id        int64 `gorm:"primaryKey,autoIncrement"`
+	ArticleId int64 `gorm:"uniqueIndex"`
+	AuthorId  int64
+	// 审核队列按照状态和认领人来查询
+	ReviewerId int64  `gorm:"index:idx_status_reviewer,priority:2"`
+	Status     uint8  `gorm:"index:idx_status_reviewer,priority:1"`
+	Title      string `gorm:"type=varchar(4096)"`
+	Content    string `gorm:"type=BLOB"`
+	Ctime      int64
+	Utime      int64
+}
+
+// ArticleReviewComment 审核单的每一次状态变更，只会插入，不会更新
+type ArticleReviewComment struct {
+	Id         int64 `gorm:"primaryKey,autoIncrement"`
+	ArticleId  int64 `gorm:"index"`
+	ReviewerId int64
+	Status     uint8
+	Comment    string `gorm:"type=varchar(4096)"`
+	Ctime      int64
+}
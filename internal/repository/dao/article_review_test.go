@@ -0,0 +1,131 @@
+package dao
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+)
+
+func TestArticleReviewGORMDAO_Submit(t *testing.T) {
+	selectSQL := regexp.QuoteMeta("SELECT * FROM `article_reviews` WHERE article_id = ? " +
+		"ORDER BY `article_reviews`.`id` LIMIT ?")
+	resubmitSQL := regexp.QuoteMeta("UPDATE `article_reviews` SET `author_id`=?,`content`=?,`reviewer_id`=?," +
+		"`status`=?,`title`=?,`utime`=? WHERE article_id = ? AND status IN (?,?)")
+	testCases := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+
+		wantErr error
+	}{
+		{
+			name: "修改之后重新提交",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectSQL).WithArgs(int64(1), 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "article_id", "status"}).
+						AddRow(1, 1, reviewStatusChangesRequested))
+				mock.ExpectExec(resubmitSQL).
+					WithArgs(int64(123), "新的内容", 0, reviewStatusSubmitted, "新的标题", sqlmock.AnyArg(),
+						int64(1), reviewStatusChangesRequested, reviewStatusRejected).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `articles` SET `status`=?,`utime`=? WHERE id = ?")).
+					WithArgs(articleStatusReviewing, sqlmock.AnyArg(), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO `article_review_comments` .*").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "正在审核，不能重新提交",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectSQL).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "article_id", "status"}).
+						AddRow(1, 1, reviewStatusInReview))
+				mock.ExpectExec(resubmitSQL).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: ErrReviewStatusConflict,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			tc.mock(mock)
+			dao := NewArticleReviewGORMDAO(openMockDB(t, sqlDB))
+			err = dao.Submit(context.Background(), ArticleReview{
+				ArticleId: 1,
+				AuthorId:  123,
+				Title:     "新的标题",
+				Content:   "新的内容",
+			}, []int{reviewStatusChangesRequested, reviewStatusRejected})
+			assert.Equal(t, tc.wantErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestArticleReviewGORMDAO_Transit(t *testing.T) {
+	transitSQL := regexp.QuoteMeta("UPDATE `article_reviews` SET `status`=?,`utime`=? " +
+		"WHERE article_id = ? AND reviewer_id = ? AND status IN (?,?)")
+	testCases := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+
+		want    ArticleReview
+		wantErr error
+	}{
+		{
+			name: "要求修改，草稿回到未发表",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(transitSQL).
+					WithArgs(reviewStatusChangesRequested, sqlmock.AnyArg(), int64(1), int64(456),
+						reviewStatusSubmitted, reviewStatusInReview).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `articles` SET `status`=?,`utime`=? WHERE id = ? AND status = ?")).
+					WithArgs(articleStatusUnpublished, sqlmock.AnyArg(), int64(1), articleStatusReviewing).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO `article_review_comments` .*").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `article_reviews` WHERE article_id = ? " +
+					"ORDER BY `article_reviews`.`id` LIMIT ?")).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "article_id", "author_id", "reviewer_id", "status"}).
+						AddRow(1, 1, 123, 456, reviewStatusChangesRequested))
+				mock.ExpectCommit()
+			},
+			want: ArticleReview{Id: 1, ArticleId: 1, AuthorId: 123, ReviewerId: 456,
+				Status: reviewStatusChangesRequested},
+		},
+		{
+			name: "不是自己认领的",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(transitSQL).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: ErrReviewStatusConflict,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			tc.mock(mock)
+			dao := NewArticleReviewGORMDAO(openMockDB(t, sqlDB))
+			r, err := dao.Transit(context.Background(), 1, 456,
+				[]int{reviewStatusSubmitted, reviewStatusInReview},
+				reviewStatusChangesRequested, "标题要改")
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, r)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
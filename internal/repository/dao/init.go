@@ -14,8 +14,18 @@ func InitTables(db *gorm.DB) error {
 	return db.AutoMigrate(&User{},
 		&Article{},
 		&PublishedArticle{},
+		&ArticleRevision{},
+		&ArticleCollaborator{},
+		&ArticleReview{},
+		&ArticleReviewComment{},
+		&Series{},
+		&SeriesArticle{},
 		&AsyncSms{},
 		&Job{},
+		&JobRun{},
+		&JobDependency{},
+		&JobInstance{},
+		&JobNode{},
 	)
 }
 
@@ -45,5 +55,32 @@ func InitCollection(mdb *mongo.Database) error {
 			Keys: bson.D{bson.E{"author_id", 1}},
 		},
 	})
+	if err != nil {
+		return err
+	}
+	revCol := mdb.Collection("article_revisions")
+	_, err = revCol.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{bson.E{"id", 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{bson.E{"article_id", 1}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	collabCol := mdb.Collection("article_collaborators")
+	_, err = collabCol.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{bson.E{"article_id", 1},
+				bson.E{"uid", 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{bson.E{"uid", 1}},
+		},
+	})
 	return err
 }
@@ -2,17 +2,79 @@ package dao
 
 import (
 	"context"
+	"errors"
+	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
 type JobDAO interface {
-	Preempt(ctx context.Context) (Job, error)
-	Release(ctx context.Context, jid int64) error
-	UpdateUtime(ctx context.Context, id int64) error
-	UpdateNextTime(ctx context.Context, id int64, t time.Time) error
+	// Insert 如果同名的任务已经存在，就用 j 覆盖它的配置。
+	// 状态不变，表达式变了才重新计算下次执行时间
+	Insert(ctx context.Context, j Job) error
+	// Create 管理员创建任务，同名的任务已经存在就返回 ErrDuplicateJob
+	Create(ctx context.Context, j Job) (int64, error)
+	// Update 修改任务的配置，下次执行时间也一起修改
+	Update(ctx context.Context, j Job) error
+	GetById(ctx context.Context, id int64) (Job, error)
+	// Preempt n 是抢占的节点，只会抢这个节点能执行的任务。
+	// 刚到期的任务，如果有负载更低的节点能执行，就先留给它。
+	// 返回的 Job 里面 Owner 就是 n，抢占之后的修改都要带上 owner，
+	// 任务已经被别的节点抢走了就什么都不改
+	Preempt(ctx context.Context, n JobNode) (Job, error)
+	Release(ctx context.Context, jid int64, owner string) error
+	UpdateUtime(ctx context.Context, id int64, owner string) error
+	// UpdateNextTime 执行成功，连续失败的次数也清零。
+	// date 是这一次执行的逻辑日期，会记录为成功
+	UpdateNextTime(ctx context.Context, id int64, owner string, t time.Time, date string) error
+	// IncrFailures 这次调度失败了，连续失败的次数到了 maxFailures 就进入 dead 状态。
+	// maxFailures 为 0 就是不限制。date 这个逻辑日期会记录为失败。
+	// 只修改还在运行的任务，执行过程中被暂停的任务不会变成 dead
+	IncrFailures(ctx context.Context, id int64, owner string, t time.Time, maxFailures int, date string) error
+	// Skip 上游失败了，这个逻辑日期不执行，记录为跳过，并且释放任务
+	Skip(ctx context.Context, id int64, owner string, t time.Time, date string) error
+	// List 按照 id 排序
+	List(ctx context.Context, offset int, limit int) ([]Job, error)
+	ListByIds(ctx context.Context, ids []int64) ([]Job, error)
+	// ListByStatus 按照 utime 倒序
+	ListByStatus(ctx context.Context, status int, offset int, limit int) ([]Job, error)
+
+	// 下面这些状态不对的时候，都返回 ErrJobStatusConflict
+
+	// Pause 等待调度或者正在执行的任务，暂停之后不再调度，正在执行的这一次不受影响
+	Pause(ctx context.Context, id int64) error
+	// Resume 把暂停的或者 dead 状态的任务恢复调度
+	Resume(ctx context.Context, id int64, t time.Time) error
+	// Trigger 等待调度的任务马上执行一次。
+	// date 不为空的时候，按照这个逻辑日期执行，用来补跑之前失败的
+	Trigger(ctx context.Context, id int64, date string) error
+	// Delete 正在执行的任务不能删除，依赖关系也一起删掉
+	Delete(ctx context.Context, id int64) error
+
+	// SetUpstreams 覆盖任务的全部上游
+	SetUpstreams(ctx context.Context, id int64, upstreams []int64) error
+	// ListDependencies 所有的依赖关系，任务不会很多，所以一次全部查出来
+	ListDependencies(ctx context.Context) ([]JobDependency, error)
+	// HasUnsuccessfulUpstream 这个逻辑日期里面，有没有上游不是执行成功的
+	HasUnsuccessfulUpstream(ctx context.Context, id int64, date string) (bool, error)
+	ListInstances(ctx context.Context, date string) ([]JobInstance, error)
+
+	// Heartbeat 节点上报负载，没有就插入
+	Heartbeat(ctx context.Context, n JobNode) error
+	// ListNodes 按照名字排序
+	ListNodes(ctx context.Context) ([]JobNode, error)
+
+	InsertRun(ctx context.Context, r JobRun) error
+	// ListRuns 按照开始时间倒序
+	ListRuns(ctx context.Context, jid int64, offset int, limit int) ([]JobRun, error)
 }
 
+var (
+	ErrJobStatusConflict = errors.New("任务的状态不对")
+	ErrDuplicateJob      = errors.New("任务的名字冲突")
+)
+
 type GORMJobDAO struct {
 	db *gorm.DB
 }
@@ -21,7 +83,75 @@ func NewGORMJobDAO(db *gorm.DB) JobDAO {
 	return &GORMJobDAO{db: db}
 }
 
-func (dao *GORMJobDAO) Preempt(ctx context.Context) (Job, error) {
+func (dao *GORMJobDAO) Insert(ctx context.Context, j Job) error {
+	now := time.Now().UnixMilli()
+	j.Ctime = now
+	j.Utime = now
+	j.LogicalDate = logicalDate(j.NextTime)
+	// MySQL 按照顺序赋值，所以 next_time 和 logical_date 要在 expression 前面，
+	// 这样比较的还是旧的表达式。有上游的任务，逻辑日期跟着上游走
+	sameExpr := "expression = VALUES(expression)"
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "name"}},
+		DoUpdates: append(clause.Set{
+			clause.Assignment{Column: clause.Column{Name: "next_time"},
+				Value: gorm.Expr("IF(" + sameExpr + ", next_time, VALUES(next_time))")},
+			clause.Assignment{Column: clause.Column{Name: "logical_date"},
+				Value: gorm.Expr("IF(" + sameExpr + " OR " + hasUpstream + ", logical_date, VALUES(logical_date))")},
+		}, clause.AssignmentColumns([]string{"executor", "target", "expression", "cfg",
+			"max_retries", "backoff", "timeout", "max_failures", "label", "weight", "utime"})...),
+	}).Create(&j).Error
+}
+
+func (dao *GORMJobDAO) Create(ctx context.Context, j Job) (int64, error) {
+	now := time.Now().UnixMilli()
+	j.Ctime = now
+	j.Utime = now
+	j.LogicalDate = logicalDate(j.NextTime)
+	err := dao.db.WithContext(ctx).Create(&j).Error
+	if me, ok := err.(*mysql.MySQLError); ok {
+		const duplicateErr uint16 = 1062
+		if me.Number == duplicateErr {
+			return 0, ErrDuplicateJob
+		}
+	}
+	return j.Id, err
+}
+
+func (dao *GORMJobDAO) Update(ctx context.Context, j Job) error {
+	res := dao.db.WithContext(ctx).Model(&Job{}).
+		Where("id = ?", j.Id).Updates(map[string]any{
+		"executor":        j.Executor,
+		"target":          j.Target,
+		"expression":      j.Expression,
+		"cfg":             j.Cfg,
+		"max_retries":     j.MaxRetries,
+		"backoff":         j.Backoff,
+		"timeout":         j.Timeout,
+		"max_failures":    j.MaxFailures,
+		"upstream_policy": j.UpstreamPolicy,
+		"label":           j.Label,
+		"weight":          j.Weight,
+		"next_time":       j.NextTime,
+		"logical_date":    ownLogicalDate(j.NextTime),
+		"utime":           time.Now().UnixMilli(),
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (dao *GORMJobDAO) GetById(ctx context.Context, id int64) (Job, error) {
+	var j Job
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&j).Error
+	return j, err
+}
+
+func (dao *GORMJobDAO) Preempt(ctx context.Context, n JobNode) (Job, error) {
 	db := dao.db.WithContext(ctx)
 	for {
 		var j Job
@@ -34,8 +164,20 @@ func (dao *GORMJobDAO) Preempt(ctx context.Context) (Job, error) {
 		// 比如说，续约是一分钟，那么 utime 距离当下，必然在一分钟内
 		// 我们可以说连续 utime < 当前三分钟前，就认为续约失败了
 		ddl := now - (time.Minute * 3).Milliseconds()
-		err := db.Where("(status = ? AND next_time <?) OR (status = ? AND utime < ?)",
-			jobStatusWaiting, now, jobStatusRunning, ddl).
+		alive := now - jobNodeTTL.Milliseconds()
+		// 有上游的任务，要等上游在同一个逻辑日期都执行成功了才能抢。
+		// 上游失败就跳过的任务，上游执行失败或者跳过了也可以抢，抢到之后再跳过。
+		// 到期超过 jobPreferGrace 还没有人抢的，就不管负载了
+		due := dao.db.Where("status = ? AND next_time < ? AND NOT EXISTS (?)",
+			jobStatusWaiting, now, dao.blockedByUpstream()).
+			Where("next_time < ? OR NOT EXISTS (?)",
+				now-jobPreferGrace.Milliseconds(), dao.lessLoadedNode(n, alive))
+		// 节点不再发心跳的，不需要等续约超时
+		orphan := dao.db.Where("status = ? AND (utime < ? OR EXISTS (?))",
+			jobStatusRunning, ddl, dao.deadOwner(alive))
+		err := db.Where("label = '' OR FIND_IN_SET(label, ?) > 0", n.Labels).
+			Where("weight <= ?", n.Capacity-n.Load).
+			Where(due.Or(orphan)).
 			First(&j).Error
 		if err != nil {
 			return j, err
@@ -45,6 +187,7 @@ func (dao *GORMJobDAO) Preempt(ctx context.Context) (Job, error) {
 			Updates(map[string]any{
 				"status":  jobStatusRunning,
 				"version": j.Version + 1,
+				"owner":   n.Name,
 				"utime":   now,
 			})
 		if res.Error != nil {
@@ -54,42 +197,216 @@ func (dao *GORMJobDAO) Preempt(ctx context.Context) (Job, error) {
 			// 没抢到
 			continue
 		}
+		j.Owner = n.Name
 		return j, err
 	}
 }
 
-func (dao *GORMJobDAO) Release(ctx context.Context, jid int64) error {
+// Release 只释放还在运行的，已经进入 dead 状态的不能被改回去。
+// 续约超时被别的节点抢走的，也不能被原来的节点释放掉
+func (dao *GORMJobDAO) Release(ctx context.Context, jid int64, owner string) error {
 	now := time.Now().UnixMilli()
 	return dao.db.WithContext(ctx).Model(&Job{}).
-		Where("id = ?", jid).Updates(map[string]any{
+		Where("id = ? AND owner = ? AND status = ?", jid, owner, jobStatusRunning).Updates(map[string]any{
 		"status": jobStatusWaiting,
+		"owner":  "",
 		"utime":  now,
 	}).Error
 }
 
-func (dao *GORMJobDAO) UpdateUtime(ctx context.Context, jid int64) error {
+func (dao *GORMJobDAO) UpdateUtime(ctx context.Context, jid int64, owner string) error {
 	now := time.Now().UnixMilli()
 	return dao.db.WithContext(ctx).Model(&Job{}).
-		Where("id = ?", jid).Updates(map[string]any{
+		Where("id = ? AND owner = ?", jid, owner).Updates(map[string]any{
 		"utime": now,
 	}).Error
 }
 
-func (dao *GORMJobDAO) UpdateNextTime(ctx context.Context, jid int64, t time.Time) error {
+func (dao *GORMJobDAO) UpdateNextTime(ctx context.Context, jid int64, owner string, t time.Time, date string) error {
 	now := time.Now().UnixMilli()
-	return dao.db.WithContext(ctx).Model(&Job{}).
-		Where("id = ?", jid).Updates(map[string]any{
-		"utime":     now,
-		"next_time": t.UnixMilli(),
-	}).Error
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&Job{}).
+			Where("id = ? AND owner = ?", jid, owner).Updates(map[string]any{
+			"utime":        now,
+			"next_time":    t.UnixMilli(),
+			"logical_date": nextLogicalDate(date, t.UnixMilli()),
+			"failures":     0,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return dao.upsertInstance(tx, jid, date, jobInstanceSuccess, now)
+	})
+}
+
+func (dao *GORMJobDAO) IncrFailures(ctx context.Context, jid int64, owner string, t time.Time, maxFailures int, date string) error {
+	now := time.Now().UnixMilli()
+	updates := map[string]any{
+		"utime":        now,
+		"next_time":    t.UnixMilli(),
+		"logical_date": nextLogicalDate(date, t.UnixMilli()),
+		"failures":     gorm.Expr("failures + 1"),
+	}
+	if maxFailures > 0 {
+		updates["status"] = gorm.Expr("CASE WHEN failures + 1 >= ? THEN ? ELSE status END",
+			maxFailures, jobStatusDead)
+	}
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&Job{}).
+			Where("id = ? AND owner = ? AND status = ?", jid, owner, jobStatusRunning).Updates(updates).Error
+		if err != nil {
+			return err
+		}
+		return dao.upsertInstance(tx, jid, date, jobInstanceFailed, now)
+	})
+}
+
+func (dao *GORMJobDAO) Skip(ctx context.Context, jid int64, owner string, t time.Time, date string) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&Job{}).
+			Where("id = ? AND owner = ? AND status = ?", jid, owner, jobStatusRunning).Updates(map[string]any{
+			"status":       jobStatusWaiting,
+			"owner":        "",
+			"utime":        now,
+			"next_time":    t.UnixMilli(),
+			"logical_date": nextLogicalDate(date, t.UnixMilli()),
+		}).Error
+		if err != nil {
+			return err
+		}
+		return dao.upsertInstance(tx, jid, date, jobInstanceSkipped, now)
+	})
+}
+
+func (dao *GORMJobDAO) List(ctx context.Context, offset int, limit int) ([]Job, error) {
+	var res []Job
+	err := dao.db.WithContext(ctx).
+		Order("id").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMJobDAO) ListByIds(ctx context.Context, ids []int64) ([]Job, error) {
+	var res []Job
+	err := dao.db.WithContext(ctx).
+		Where("id IN ?", ids).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMJobDAO) ListByStatus(ctx context.Context, status int, offset int, limit int) ([]Job, error) {
+	var res []Job
+	err := dao.db.WithContext(ctx).
+		Where("status = ?", status).
+		Order("utime DESC").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMJobDAO) Pause(ctx context.Context, jid int64) error {
+	return dao.transit(ctx, jid, []int{jobStatusWaiting, jobStatusRunning},
+		map[string]any{
+			"status": jobStatusPaused,
+		})
+}
+
+func (dao *GORMJobDAO) Resume(ctx context.Context, jid int64, t time.Time) error {
+	return dao.transit(ctx, jid, []int{jobStatusPaused, jobStatusDead},
+		map[string]any{
+			"status":       jobStatusWaiting,
+			"owner":        "",
+			"failures":     0,
+			"next_time":    t.UnixMilli(),
+			"logical_date": ownLogicalDate(t.UnixMilli()),
+		})
+}
+
+func (dao *GORMJobDAO) Trigger(ctx context.Context, jid int64, date string) error {
+	updates := map[string]any{
+		"next_time": time.Now().UnixMilli(),
+	}
+	if date != "" {
+		updates["logical_date"] = date
+	}
+	return dao.transit(ctx, jid, []int{jobStatusWaiting}, updates)
+}
+
+func (dao *GORMJobDAO) Delete(ctx context.Context, jid int64) error {
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id = ? AND status <> ?", jid, jobStatusRunning).
+			Delete(&Job{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrJobStatusConflict
+		}
+		return tx.Where("job_id = ? OR upstream_id = ?", jid, jid).
+			Delete(&JobDependency{}).Error
+	})
+}
+
+// transit 只有处于 from 里面某个状态的任务才会被修改
+func (dao *GORMJobDAO) transit(ctx context.Context, jid int64, from []int, updates map[string]any) error {
+	updates["utime"] = time.Now().UnixMilli()
+	res := dao.db.WithContext(ctx).Model(&Job{}).
+		Where("id = ? AND status IN ?", jid, from).
+		Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrJobStatusConflict
+	}
+	return nil
+}
+
+func (dao *GORMJobDAO) InsertRun(ctx context.Context, r JobRun) error {
+	r.Ctime = time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Create(&r).Error
+}
+
+func (dao *GORMJobDAO) ListRuns(ctx context.Context, jid int64, offset int, limit int) ([]JobRun, error) {
+	var res []JobRun
+	err := dao.db.WithContext(ctx).
+		Where("job_id = ?", jid).
+		Order("start DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
 }
 
 type Job struct {
 	Id         int64  `gorm:"primaryKey,autoIncrement"`
 	Name       string `gorm:"type:varchar(128);unique"`
 	Executor   string
+	Target     string
 	Expression string
 	Cfg        string
+
+	MaxRetries int
+	// Backoff 和 Timeout 都是毫秒数
+	Backoff     int64
+	Timeout     int64
+	MaxFailures int
+	// Failures 连续失败的次数
+	Failures int
+	// Owner 抢占了这个任务的节点
+	Owner string
+	// UpstreamPolicy 上游失败的时候怎么办
+	UpstreamPolicy uint8
+	// Label 为空就是哪个节点都可以执行
+	Label  string `gorm:"type:varchar(64)"`
+	Weight int64  `gorm:"default:1"`
+
+	// LogicalDate 下一次执行的逻辑日期，格式是 20060102。
+	// 没有上游的任务一般就是 NextTime 那天，补跑的时候是要补的那天。
+	// 有上游的任务是上游执行过的那天，为空就是还在等上游
+	LogicalDate string `gorm:"type:varchar(8)"`
+
 	// 状态来表达，是不是可以抢占，有没有被人抢占
 	Status int
 
@@ -108,4 +425,20 @@ const (
 	jobStatusRunning
 	// jobStatusPaused 不再需要调度了
 	jobStatusPaused
+	// jobStatusDead 连续失败太多次，要管理员手动恢复
+	jobStatusDead
 )
+
+// JobRun 任务的执行记录
+type JobRun struct {
+	Id      int64 `gorm:"primaryKey,autoIncrement"`
+	JobId   int64 `gorm:"index:idx_job_start"`
+	Node    string
+	Attempt int
+	// Start 和 End 都是毫秒数
+	Start  int64 `gorm:"index:idx_job_start"`
+	End    int64
+	Result uint8
+	Err    string `gorm:"type:varchar(1024)"`
+	Ctime  int64
+}
@@ -0,0 +1,164 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// logicalDateLayout 和 domain.JobLogicalDateLayout 保持一致
+const logicalDateLayout = "20060102"
+
+func logicalDate(ms int64) string {
+	return time.UnixMilli(ms).Format(logicalDateLayout)
+}
+
+// hasUpstream 用在 UPDATE jobs 里面，判断任务有没有上游
+const hasUpstream = "EXISTS (SELECT 1 FROM job_dependencies WHERE job_dependencies.job_id = jobs.id)"
+
+// ownLogicalDate 没有上游的任务，逻辑日期就是 nextTime 那天。
+// 有上游的任务，逻辑日期跟着上游走，这里不修改
+func ownLogicalDate(nextTime int64) clause.Expr {
+	return gorm.Expr("CASE WHEN "+hasUpstream+" THEN logical_date ELSE ? END", logicalDate(nextTime))
+}
+
+// nextLogicalDate 任务执行完 date 这个逻辑日期之后，下一次的逻辑日期。
+// 有上游的任务，是上游已经有结果的下一个逻辑日期，这样上下游跨过零点也能对上。
+// 上游还没有结果就是空的，等上游执行完再填上
+func nextLogicalDate(date string, nextTime int64) clause.Expr {
+	return gorm.Expr("CASE WHEN "+hasUpstream+" THEN COALESCE((SELECT MIN(job_instances.logical_date) FROM job_instances "+
+		"JOIN job_dependencies ON job_instances.job_id = job_dependencies.upstream_id "+
+		"WHERE job_dependencies.job_id = jobs.id AND job_instances.logical_date > ?), '') ELSE ? END",
+		date, logicalDate(nextTime))
+}
+
+// blockedByUpstream 查出来还有上游没有完成的依赖关系，
+// 外面要用 NOT EXISTS 把这种任务排除掉
+func (dao *GORMJobDAO) blockedByUpstream() *gorm.DB {
+	done := dao.db.Model(&JobInstance{}).Select("1").
+		Where("job_instances.job_id = job_dependencies.upstream_id AND job_instances.logical_date = jobs.logical_date").
+		Where("job_instances.status = ? OR (jobs.upstream_policy = ? AND job_instances.status IN ?)",
+			jobInstanceSuccess, jobUpstreamSkip, []int{int(jobInstanceFailed), int(jobInstanceSkipped)})
+	return dao.db.Model(&JobDependency{}).Select("1").
+		Where("job_dependencies.job_id = jobs.id AND NOT EXISTS (?)", done)
+}
+
+// upsertInstance 同一个逻辑日期补跑的话，以最后一次的结果为准。
+// 正在等上游的下游任务，逻辑日期就是这一次的
+func (dao *GORMJobDAO) upsertInstance(tx *gorm.DB, jid int64, date string, status uint8, now int64) error {
+	if date == "" {
+		// 加上逻辑日期之前的任务
+		return nil
+	}
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "job_id"}, {Name: "logical_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "utime"}),
+	}).Create(&JobInstance{
+		JobId:       jid,
+		LogicalDate: date,
+		Status:      status,
+		Ctime:       now,
+		Utime:       now,
+	}).Error
+	if err != nil {
+		return err
+	}
+	return tx.Model(&Job{}).
+		Where("logical_date = '' AND id IN (?)",
+			dao.db.Model(&JobDependency{}).Select("job_id").Where("upstream_id = ?", jid)).
+		Update("logical_date", date).Error
+}
+
+func (dao *GORMJobDAO) SetUpstreams(ctx context.Context, jid int64, upstreams []int64) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("job_id = ?", jid).Delete(&JobDependency{}).Error
+		if err != nil {
+			return err
+		}
+		if len(upstreams) == 0 {
+			// 没有上游了，逻辑日期按照自己的下次执行时间来算
+			var j Job
+			err = tx.Select("next_time").Where("id = ?", jid).First(&j).Error
+			if err != nil {
+				return err
+			}
+			return tx.Model(&Job{}).
+				Where("id = ? AND status <> ?", jid, jobStatusRunning).
+				Update("logical_date", logicalDate(j.NextTime)).Error
+		}
+		// 从上游下一次执行完的逻辑日期开始
+		err = tx.Model(&Job{}).
+			Where("id = ? AND status <> ?", jid, jobStatusRunning).
+			Update("logical_date", "").Error
+		if err != nil {
+			return err
+		}
+		deps := make([]JobDependency, 0, len(upstreams))
+		for _, up := range upstreams {
+			deps = append(deps, JobDependency{
+				JobId:      jid,
+				UpstreamId: up,
+				Ctime:      now,
+			})
+		}
+		return tx.Create(&deps).Error
+	})
+}
+
+func (dao *GORMJobDAO) ListDependencies(ctx context.Context) ([]JobDependency, error) {
+	var res []JobDependency
+	err := dao.db.WithContext(ctx).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMJobDAO) HasUnsuccessfulUpstream(ctx context.Context, jid int64, date string) (bool, error) {
+	succeeded := dao.db.Model(&JobInstance{}).Select("1").
+		Where("job_instances.job_id = job_dependencies.upstream_id AND job_instances.logical_date = ? AND job_instances.status = ?",
+			date, jobInstanceSuccess)
+	var cnt int64
+	err := dao.db.WithContext(ctx).Model(&JobDependency{}).
+		Where("job_id = ? AND NOT EXISTS (?)", jid, succeeded).
+		Count(&cnt).Error
+	return cnt > 0, err
+}
+
+func (dao *GORMJobDAO) ListInstances(ctx context.Context, date string) ([]JobInstance, error) {
+	var res []JobInstance
+	err := dao.db.WithContext(ctx).
+		Where("logical_date = ?", date).
+		Find(&res).Error
+	return res, err
+}
+
+// JobDependency JobId 要等 UpstreamId 执行成功之后才能执行
+type JobDependency struct {
+	Id         int64 `gorm:"primaryKey,autoIncrement"`
+	JobId      int64 `gorm:"uniqueIndex:idx_job_upstream"`
+	UpstreamId int64 `gorm:"uniqueIndex:idx_job_upstream;index"`
+	Ctime      int64
+}
+
+// JobInstance 任务在某个逻辑日期的最终结果，重试和补跑都只有一条
+type JobInstance struct {
+	Id          int64  `gorm:"primaryKey,autoIncrement"`
+	JobId       int64  `gorm:"uniqueIndex:idx_job_date"`
+	LogicalDate string `gorm:"type:varchar(8);uniqueIndex:idx_job_date;index"`
+	Status      uint8
+	Ctime       int64
+	Utime       int64
+}
+
+const (
+	jobInstanceSuccess uint8 = iota + 1
+	jobInstanceFailed
+	jobInstanceSkipped
+)
+
+const (
+	// jobUpstreamBlock 上游失败了就一直等，直到上游补跑成功
+	jobUpstreamBlock uint8 = iota
+	// jobUpstreamSkip 上游失败了，这个逻辑日期就跳过
+	jobUpstreamSkip
+)
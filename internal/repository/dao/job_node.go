@@ -0,0 +1,61 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+const (
+	// jobNodeTTL 超过这个时间没有心跳，就认为节点已经挂了，
+	// 它抢占的任务可以马上被别的节点抢走
+	jobNodeTTL = time.Second * 15
+	// jobPreferGrace 任务到期之后，先留这么久给负载更低的节点
+	jobPreferGrace = time.Second * 2
+)
+
+// lessLoadedNode 还活着、能执行这个任务、并且负载比 n 低的节点。
+// 负载是 load / capacity，这里交叉相乘来比较
+func (dao *GORMJobDAO) lessLoadedNode(n JobNode, alive int64) *gorm.DB {
+	return dao.db.Model(&JobNode{}).Select("1").
+		Where("job_nodes.name <> ? AND job_nodes.utime >= ?", n.Name, alive).
+		Where("jobs.label = '' OR FIND_IN_SET(jobs.label, job_nodes.labels) > 0").
+		Where("job_nodes.capacity - job_nodes.cur_load >= jobs.weight").
+		Where("job_nodes.cur_load * ? < ? * job_nodes.capacity", n.Capacity, n.Load)
+}
+
+// deadOwner 抢占了任务的节点已经不发心跳了
+func (dao *GORMJobDAO) deadOwner(alive int64) *gorm.DB {
+	return dao.db.Model(&JobNode{}).Select("1").
+		Where("job_nodes.name = jobs.owner AND job_nodes.utime < ?", alive)
+}
+
+func (dao *GORMJobDAO) Heartbeat(ctx context.Context, n JobNode) error {
+	now := time.Now().UnixMilli()
+	n.Ctime = now
+	n.Utime = now
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"labels", "capacity", "cur_load", "utime"}),
+	}).Create(&n).Error
+}
+
+func (dao *GORMJobDAO) ListNodes(ctx context.Context) ([]JobNode, error) {
+	var res []JobNode
+	err := dao.db.WithContext(ctx).Order("name").Find(&res).Error
+	return res, err
+}
+
+// JobNode 调度节点的心跳
+type JobNode struct {
+	Id   int64  `gorm:"primaryKey,autoIncrement"`
+	Name string `gorm:"type:varchar(128);unique"`
+	// Labels 逗号分隔，方便用 FIND_IN_SET 匹配
+	Labels   string `gorm:"type:varchar(512)"`
+	Capacity int64
+	// Load 是 MySQL 的关键字，所以换个列名
+	Load  int64 `gorm:"column:cur_load"`
+	Ctime int64
+	Utime int64
+}
@@ -0,0 +1,333 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestGORMJobDAO_Insert(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(t *testing.T) *sql.DB
+
+		wantErr error
+	}{
+		{
+			name: "同名的任务用配置覆盖，表达式没变就不改下次执行时间",
+			mock: func(t *testing.T) *sql.DB {
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectExec("INSERT INTO `jobs` .* ON DUPLICATE KEY UPDATE " +
+					regexp.QuoteMeta("`next_time`=IF(expression = VALUES(expression), next_time, VALUES(next_time)),"+
+						"`logical_date`=IF(expression = VALUES(expression) OR "+hasUpstream+", logical_date, VALUES(logical_date)),"+
+						"`executor`=VALUES(`executor`)") + ".*" +
+					regexp.QuoteMeta("`expression`=VALUES(`expression`)")).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				return db
+			},
+		},
+		{
+			name: "数据库错误",
+			mock: func(t *testing.T) *sql.DB {
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectExec("INSERT INTO `jobs` .*").
+					WillReturnError(errors.New("数据库错误"))
+				return db
+			},
+			wantErr: errors.New("数据库错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dao := NewGORMJobDAO(openMockDB(t, tc.mock(t)))
+			err := dao.Insert(context.Background(), Job{
+				Name:       "member_renew",
+				Executor:   "grpc",
+				Target:     "reward",
+				Expression: "0 0 * * * ?",
+				NextTime:   1700000000000,
+			})
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestGORMJobDAO_IncrFailures(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectBegin()
+	// 只有还在运行的任务才会变成 dead，执行的时候被暂停的不受影响。
+	// 已经被别的节点抢走的也不受影响
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `jobs` SET `failures`=failures + 1,")+".*"+
+		regexp.QuoteMeta("`status`=CASE WHEN failures + 1 >= ? THEN ? ELSE status END")+".*"+
+		regexp.QuoteMeta("WHERE id = ? AND owner = ? AND status = ?")).
+		WithArgs("20231115", sqlmock.AnyArg(), sqlmock.AnyArg(),
+			3, jobStatusDead, sqlmock.AnyArg(), int64(1), "node-1", jobStatusRunning).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO `job_instances` .*").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE `jobs` SET `logical_date`=.*").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	dao := NewGORMJobDAO(openMockDB(t, sqlDB))
+	err = dao.IncrFailures(context.Background(), 1, "node-1", time.UnixMilli(1700000000000), 3, "20231115")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGORMJobDAO_UpdateNextTime(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectBegin()
+	// 有上游的任务，下一个逻辑日期是上游执行过的，而不是自己的下次执行时间那天
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `jobs` SET `failures`=?,`logical_date`=CASE WHEN "+hasUpstream+
+		" THEN COALESCE((SELECT MIN(job_instances.logical_date) FROM job_instances")+".*"+
+		regexp.QuoteMeta("job_instances.logical_date > ?), '') ELSE ? END")+".*"+
+		regexp.QuoteMeta("WHERE id = ? AND owner = ?")).
+		WithArgs(0, "20231115", "20231116", int64(1700100000000), sqlmock.AnyArg(), int64(1), "node-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO `job_instances` .*").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	// 等上游的下游任务，从这个逻辑日期开始执行，跨过零点也没关系
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `jobs` SET `logical_date`=? WHERE logical_date = '' AND id IN "+
+		"(SELECT job_id FROM `job_dependencies` WHERE upstream_id = ?)")).
+		WithArgs("20231115", int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	dao := NewGORMJobDAO(openMockDB(t, sqlDB))
+	err = dao.UpdateNextTime(context.Background(), 1, "node-1", time.UnixMilli(1700100000000), "20231115")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGORMJobDAO_Preempt(t *testing.T) {
+	node := JobNode{Name: "node-1", Labels: "gpu", Capacity: 10, Load: 4}
+	// 负载更低的节点先抢，任务的 owner 不发心跳了就可以马上抢走
+	selectSQL := "SELECT \\* FROM `jobs` .*" +
+		regexp.QuoteMeta("job_nodes.name <> ? AND job_nodes.utime >= ?") + ".*" +
+		regexp.QuoteMeta("FIND_IN_SET(jobs.label, job_nodes.labels) > 0") + ".*" +
+		regexp.QuoteMeta("job_nodes.capacity - job_nodes.cur_load >= jobs.weight") + ".*" +
+		regexp.QuoteMeta("job_nodes.cur_load * ? < ? * job_nodes.capacity") + ".*" +
+		regexp.QuoteMeta("job_nodes.name = jobs.owner AND job_nodes.utime < ?")
+	rows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "name", "version"}).AddRow(1, "job", 3)
+	}
+	testCases := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+
+		wantJob Job
+		wantErr error
+	}{
+		{
+			name: "抢占成功",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL).WillReturnRows(rows())
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `jobs` SET `owner`=?,`status`=?,`utime`=?,`version`=? WHERE id = ? AND version = ?")).
+					WithArgs("node-1", jobStatusRunning, sqlmock.AnyArg(), 4, int64(1), 3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantJob: Job{Id: 1, Name: "job", Version: 3, Owner: "node-1"},
+		},
+		{
+			name: "被别人抢走了，重新抢",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL).WillReturnRows(rows())
+				mock.ExpectExec("UPDATE `jobs` .*").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(selectSQL).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "version"}).AddRow(2, "job2", 1))
+				mock.ExpectExec("UPDATE `jobs` .*").
+					WithArgs("node-1", jobStatusRunning, sqlmock.AnyArg(), 2, int64(2), 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantJob: Job{Id: 2, Name: "job2", Version: 1, Owner: "node-1"},
+		},
+		{
+			name: "没有可以抢的任务",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			wantErr: gorm.ErrRecordNotFound,
+		},
+		{
+			name: "更新失败",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL).WillReturnRows(rows())
+				mock.ExpectExec("UPDATE `jobs` .*").
+					WillReturnError(errors.New("mock db error"))
+			},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			tc.mock(mock)
+			j, err := NewGORMJobDAO(openMockDB(t, sqlDB)).Preempt(context.Background(), node)
+			assert.Equal(t, tc.wantErr, err)
+			if err == nil {
+				assert.Equal(t, tc.wantJob, j)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGORMJobDAO_lessLoadedNode(t *testing.T) {
+	sqlDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	dao := NewGORMJobDAO(openMockDB(t, sqlDB)).(*GORMJobDAO)
+	stmt := dao.lessLoadedNode(JobNode{Name: "node-1", Capacity: 10, Load: 4}, 1000).
+		Session(&gorm.Session{DryRun: true}).Find(&[]JobNode{}).Statement
+	assert.Equal(t, "SELECT 1 FROM `job_nodes` WHERE (job_nodes.name <> ? AND job_nodes.utime >= ?) "+
+		"AND (jobs.label = '' OR FIND_IN_SET(jobs.label, job_nodes.labels) > 0) "+
+		"AND job_nodes.capacity - job_nodes.cur_load >= jobs.weight "+
+		"AND job_nodes.cur_load * ? < ? * job_nodes.capacity", stmt.SQL.String())
+	// 交叉相乘：别的节点 load / capacity < 4 / 10
+	assert.Equal(t, []any{"node-1", int64(1000), int64(10), int64(4)}, stmt.Vars)
+}
+
+func TestGORMJobDAO_deadOwner(t *testing.T) {
+	sqlDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	dao := NewGORMJobDAO(openMockDB(t, sqlDB)).(*GORMJobDAO)
+	stmt := dao.deadOwner(1000).
+		Session(&gorm.Session{DryRun: true}).Find(&[]JobNode{}).Statement
+	assert.Equal(t, "SELECT 1 FROM `job_nodes` WHERE job_nodes.name = jobs.owner AND job_nodes.utime < ?",
+		stmt.SQL.String())
+	assert.Equal(t, []any{int64(1000)}, stmt.Vars)
+}
+
+func TestGORMJobDAO_blockedByUpstream(t *testing.T) {
+	sqlDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	dao := NewGORMJobDAO(openMockDB(t, sqlDB)).(*GORMJobDAO)
+	stmt := dao.blockedByUpstream().
+		Session(&gorm.Session{DryRun: true}).Find(&[]JobDependency{}).Statement
+	// 失败和跳过要展开成两个参数，不然 IN 永远匹配不上
+	assert.Contains(t, stmt.SQL.String(), "job_instances.status IN (?,?)")
+	assert.Equal(t, []any{jobInstanceSuccess, jobUpstreamSkip,
+		int(jobInstanceFailed), int(jobInstanceSkipped)}, stmt.Vars)
+}
+
+func TestGORMJobDAO_transit(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+		call func(dao JobDAO) error
+
+		wantErr error
+	}{
+		{
+			name: "暂停等待调度的任务",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `jobs` SET `status`=?,`utime`=? WHERE id = ? AND status IN (?,?)")).
+					WithArgs(jobStatusPaused, sqlmock.AnyArg(), int64(1), jobStatusWaiting, jobStatusRunning).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			call: func(dao JobDAO) error {
+				return dao.Pause(context.Background(), 1)
+			},
+		},
+		{
+			name: "暂停的任务不能再暂停",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE `jobs` .*").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			call: func(dao JobDAO) error {
+				return dao.Pause(context.Background(), 1)
+			},
+			wantErr: ErrJobStatusConflict,
+		},
+		{
+			name: "恢复暂停的或者 dead 的任务",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE `jobs` SET .* "+
+					regexp.QuoteMeta("WHERE id = ? AND status IN (?,?)")).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+						sqlmock.AnyArg(), sqlmock.AnyArg(), int64(1), jobStatusPaused, jobStatusDead).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			call: func(dao JobDAO) error {
+				return dao.Resume(context.Background(), 1, time.Now())
+			},
+		},
+		{
+			name: "只能触发等待调度的任务",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE `jobs` SET .* "+
+					regexp.QuoteMeta("WHERE id = ? AND status IN (?)")).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), int64(1), jobStatusWaiting).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			call: func(dao JobDAO) error {
+				return dao.Trigger(context.Background(), 1, "20231115")
+			},
+			wantErr: ErrJobStatusConflict,
+		},
+		{
+			name: "正在运行的任务不能删除",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `jobs` WHERE id = ? AND status <> ?")).
+					WithArgs(int64(1), jobStatusRunning).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			call: func(dao JobDAO) error {
+				return dao.Delete(context.Background(), 1)
+			},
+			wantErr: ErrJobStatusConflict,
+		},
+		{
+			name: "删除任务的时候把依赖关系也删掉",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM `jobs` .*").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `job_dependencies` WHERE job_id = ? OR upstream_id = ?")).
+					WithArgs(int64(1), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+			call: func(dao JobDAO) error {
+				return dao.Delete(context.Background(), 1)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			tc.mock(mock)
+			err = tc.call(NewGORMJobDAO(openMockDB(t, sqlDB)))
+			assert.Equal(t, tc.wantErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func openMockDB(t *testing.T, sqlDB *sql.DB) *gorm.DB {
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+	return db
+}
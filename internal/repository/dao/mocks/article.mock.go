@@ -39,19 +39,47 @@ func (m *MockArticleDAO) EXPECT() *MockArticleDAOMockRecorder {
 	return m.recorder
 }
 
+// AcceptCollaborator mocks base method.
+func (m *MockArticleDAO) AcceptCollaborator(ctx context.Context, aid, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptCollaborator", ctx, aid, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcceptCollaborator indicates an expected call of AcceptCollaborator.
+func (mr *MockArticleDAOMockRecorder) AcceptCollaborator(ctx, aid, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptCollaborator", reflect.TypeOf((*MockArticleDAO)(nil).AcceptCollaborator), ctx, aid, uid)
+}
+
+// DeleteCollaborator mocks base method.
+func (m *MockArticleDAO) DeleteCollaborator(ctx context.Context, operator, aid, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollaborator", ctx, operator, aid, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCollaborator indicates an expected call of DeleteCollaborator.
+func (mr *MockArticleDAOMockRecorder) DeleteCollaborator(ctx, operator, aid, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollaborator", reflect.TypeOf((*MockArticleDAO)(nil).DeleteCollaborator), ctx, operator, aid, uid)
+}
+
 // GetByAuthor mocks base method.
-func (m *MockArticleDAO) GetByAuthor(ctx context.Context, uid int64, offset, limit int) ([]dao.Article, error) {
+func (m *MockArticleDAO) GetByAuthor(ctx context.Context, uid, utime, id int64, limit int) ([]dao.Article, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByAuthor", ctx, uid, offset, limit)
+	ret := m.ctrl.Call(m, "GetByAuthor", ctx, uid, utime, id, limit)
 	ret0, _ := ret[0].([]dao.Article)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByAuthor indicates an expected call of GetByAuthor.
-func (mr *MockArticleDAOMockRecorder) GetByAuthor(ctx, uid, offset, limit any) *gomock.Call {
+func (mr *MockArticleDAOMockRecorder) GetByAuthor(ctx, uid, utime, id, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAuthor", reflect.TypeOf((*MockArticleDAO)(nil).GetByAuthor), ctx, uid, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAuthor", reflect.TypeOf((*MockArticleDAO)(nil).GetByAuthor), ctx, uid, utime, id, limit)
 }
 
 // GetById mocks base method.
@@ -84,6 +112,21 @@ func (mr *MockArticleDAOMockRecorder) GetPubById(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPubById", reflect.TypeOf((*MockArticleDAO)(nil).GetPubById), ctx, id)
 }
 
+// GetRevisionById mocks base method.
+func (m *MockArticleDAO) GetRevisionById(ctx context.Context, id int64) (dao.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisionById", ctx, id)
+	ret0, _ := ret[0].(dao.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisionById indicates an expected call of GetRevisionById.
+func (mr *MockArticleDAOMockRecorder) GetRevisionById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisionById", reflect.TypeOf((*MockArticleDAO)(nil).GetRevisionById), ctx, id)
+}
+
 // Insert mocks base method.
 func (m *MockArticleDAO) Insert(ctx context.Context, art dao.Article) (int64, error) {
 	m.ctrl.T.Helper()
@@ -99,6 +142,110 @@ func (mr *MockArticleDAOMockRecorder) Insert(ctx, art any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockArticleDAO)(nil).Insert), ctx, art)
 }
 
+// InsertCollaborator mocks base method.
+func (m *MockArticleDAO) InsertCollaborator(ctx context.Context, c dao.ArticleCollaborator) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertCollaborator", ctx, c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertCollaborator indicates an expected call of InsertCollaborator.
+func (mr *MockArticleDAOMockRecorder) InsertCollaborator(ctx, c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertCollaborator", reflect.TypeOf((*MockArticleDAO)(nil).InsertCollaborator), ctx, c)
+}
+
+// ListCollaborators mocks base method.
+func (m *MockArticleDAO) ListCollaborators(ctx context.Context, aid int64) ([]dao.ArticleCollaborator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCollaborators", ctx, aid)
+	ret0, _ := ret[0].([]dao.ArticleCollaborator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCollaborators indicates an expected call of ListCollaborators.
+func (mr *MockArticleDAOMockRecorder) ListCollaborators(ctx, aid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCollaborators", reflect.TypeOf((*MockArticleDAO)(nil).ListCollaborators), ctx, aid)
+}
+
+// ListDueScheduled mocks base method.
+func (m *MockArticleDAO) ListDueScheduled(ctx context.Context, publishAt int64, limit int) ([]dao.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueScheduled", ctx, publishAt, limit)
+	ret0, _ := ret[0].([]dao.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueScheduled indicates an expected call of ListDueScheduled.
+func (mr *MockArticleDAOMockRecorder) ListDueScheduled(ctx, publishAt, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueScheduled", reflect.TypeOf((*MockArticleDAO)(nil).ListDueScheduled), ctx, publishAt, limit)
+}
+
+// ListPub mocks base method.
+func (m *MockArticleDAO) ListPub(ctx context.Context, utime, id int64, limit int) ([]dao.PublishedArticle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPub", ctx, utime, id, limit)
+	ret0, _ := ret[0].([]dao.PublishedArticle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPub indicates an expected call of ListPub.
+func (mr *MockArticleDAOMockRecorder) ListPub(ctx, utime, id, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPub", reflect.TypeOf((*MockArticleDAO)(nil).ListPub), ctx, utime, id, limit)
+}
+
+// ListPubByIds mocks base method.
+func (m *MockArticleDAO) ListPubByIds(ctx context.Context, ids []int64) ([]dao.PublishedArticle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPubByIds", ctx, ids)
+	ret0, _ := ret[0].([]dao.PublishedArticle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPubByIds indicates an expected call of ListPubByIds.
+func (mr *MockArticleDAOMockRecorder) ListPubByIds(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPubByIds", reflect.TypeOf((*MockArticleDAO)(nil).ListPubByIds), ctx, ids)
+}
+
+// ListRevisions mocks base method.
+func (m *MockArticleDAO) ListRevisions(ctx context.Context, artId int64, offset, limit int) ([]dao.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevisions", ctx, artId, offset, limit)
+	ret0, _ := ret[0].([]dao.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevisions indicates an expected call of ListRevisions.
+func (mr *MockArticleDAOMockRecorder) ListRevisions(ctx, artId, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevisions", reflect.TypeOf((*MockArticleDAO)(nil).ListRevisions), ctx, artId, offset, limit)
+}
+
+// ListScheduled mocks base method.
+func (m *MockArticleDAO) ListScheduled(ctx context.Context, uid int64) ([]dao.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduled", ctx, uid)
+	ret0, _ := ret[0].([]dao.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduled indicates an expected call of ListScheduled.
+func (mr *MockArticleDAOMockRecorder) ListScheduled(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduled", reflect.TypeOf((*MockArticleDAO)(nil).ListScheduled), ctx, uid)
+}
+
 // Sync mocks base method.
 func (m *MockArticleDAO) Sync(ctx context.Context, entity dao.Article) (int64, error) {
 	m.ctrl.T.Helper()
@@ -141,3 +288,17 @@ func (mr *MockArticleDAOMockRecorder) UpdateById(ctx, entity any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateById", reflect.TypeOf((*MockArticleDAO)(nil).UpdateById), ctx, entity)
 }
+
+// UpdateSchedule mocks base method.
+func (m *MockArticleDAO) UpdateSchedule(ctx context.Context, uid, id int64, status uint8, publishAt int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSchedule", ctx, uid, id, status, publishAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSchedule indicates an expected call of UpdateSchedule.
+func (mr *MockArticleDAOMockRecorder) UpdateSchedule(ctx, uid, id, status, publishAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSchedule", reflect.TypeOf((*MockArticleDAO)(nil).UpdateSchedule), ctx, uid, id, status, publishAt)
+}
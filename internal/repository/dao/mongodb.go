@@ -14,17 +14,25 @@ type MongoDBArticleDAO struct {
 	node    *snowflake.Node
 	col     *mongo.Collection
 	liveCol *mongo.Collection
+	revCol  *mongo.Collection
+	// 合作者
+	collabCol *mongo.Collection
 
 	// 演示 mongodb 中的事务
 	client *mongo.Client
 }
 
-func (m *MongoDBArticleDAO) ListPub(ctx context.Context, start time.Time, offset int, limit int) ([]PublishedArticle, error) {
+func (m *MongoDBArticleDAO) ListPub(ctx context.Context, utime int64, id int64, limit int) ([]PublishedArticle, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m *MongoDBArticleDAO) GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]Article, error) {
+func (m *MongoDBArticleDAO) ListPubByIds(ctx context.Context, ids []int64) ([]PublishedArticle, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MongoDBArticleDAO) GetByAuthor(ctx context.Context, uid int64, utime int64, id int64, limit int) ([]Article, error) {
 	//TODO implement me
 	panic("implement me")
 }
@@ -39,36 +47,212 @@ func (m *MongoDBArticleDAO) GetPubById(ctx context.Context, id int64) (Published
 	panic("implement me")
 }
 
+func (m *MongoDBArticleDAO) ListRevisions(ctx context.Context, artId int64, offset int, limit int) ([]ArticleRevision, error) {
+	filter := bson.D{bson.E{Key: "article_id", Value: artId}}
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "id", Value: -1}}).
+		SetSkip(int64(offset)).SetLimit(int64(limit))
+	cursor, err := m.revCol.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []ArticleRevision
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) GetRevisionById(ctx context.Context, id int64) (ArticleRevision, error) {
+	var res ArticleRevision
+	err := m.revCol.FindOne(ctx, bson.D{bson.E{Key: "id", Value: id}}).Decode(&res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) ListScheduled(ctx context.Context, uid int64) ([]Article, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MongoDBArticleDAO) ListDueScheduled(ctx context.Context, publishAt int64, limit int) ([]Article, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MongoDBArticleDAO) UpdateSchedule(ctx context.Context, uid int64, id int64, status uint8, publishAt int64) error {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (m *MongoDBArticleDAO) Insert(ctx context.Context, art Article) (int64, error) {
 	now := time.Now().UnixMilli()
 	art.Ctime = now
 	art.Utime = now
 	art.Id = m.node.Generate().Int64()
 	_, err := m.col.InsertOne(ctx, &art)
-	return art.Id, err
+	if err != nil {
+		return 0, err
+	}
+	return art.Id, m.insertRevision(ctx, art, now)
+}
+
+// insertRevision 没有用事务，所以极端情况下会出现文章改了但是没有历史版本的情况
+func (m *MongoDBArticleDAO) insertRevision(ctx context.Context, art Article, now int64) error {
+	rev := newArticleRevision(art, now)
+	rev.Id = m.node.Generate().Int64()
+	_, err := m.revCol.InsertOne(ctx, rev)
+	return err
 }
 
 func (m *MongoDBArticleDAO) UpdateById(ctx context.Context, art Article) error {
+	_, err := m.update(ctx, art)
+	return err
+}
+
+// update 和 GORM 的实现一样，返回文章真正的创作者
+func (m *MongoDBArticleDAO) update(ctx context.Context, art Article) (int64, error) {
+	owner, err := m.checkEditable(ctx, art.Id, art.AuthorId)
+	if err != nil {
+		return 0, err
+	}
 	now := time.Now().UnixMilli()
-	filter := bson.D{bson.E{"id", art.Id},
-		bson.E{"author_id", art.AuthorId}}
+	filter := bson.D{bson.E{"id", art.Id}}
+	if art.Status != articleStatusPublished {
+		// 审核中的草稿不能修改，只有审核通过的时候会同步过来
+		filter = append(filter, bson.E{"status", bson.M{"$ne": articleStatusReviewing}})
+	}
 	set := bson.D{bson.E{"$set", bson.M{
-		"title":   art.Title,
-		"content": art.Content,
-		"status":  art.Status,
-		"utime":   now,
+		"title":      art.Title,
+		"content":    art.Content,
+		"status":     art.Status,
+		"publish_at": art.PublishAt,
+		"utime":      now,
 	}}}
 	res, err := m.col.UpdateOne(ctx, filter, set)
+	if err != nil {
+		return 0, err
+	}
+	if res.MatchedCount == 0 {
+		return 0, ErrArticleReviewing
+	}
+	return owner, m.insertRevision(ctx, art, now)
+}
+
+// checkEditable 创作者本人或者已经接受邀请的编辑才能修改，返回文章真正的创作者。
+// mongodb 没有 JOIN，所以分两步查
+func (m *MongoDBArticleDAO) checkEditable(ctx context.Context, id int64, uid int64) (int64, error) {
+	var art Article
+	err := m.col.FindOne(ctx, bson.D{bson.E{Key: "id", Value: id}},
+		options.FindOne().SetProjection(bson.D{bson.E{Key: "author_id", Value: 1}})).
+		Decode(&art)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, ErrArticleNoPermission
+	}
+	if err != nil {
+		return 0, err
+	}
+	if art.AuthorId == uid {
+		return uid, nil
+	}
+	cnt, err := m.collabCol.CountDocuments(ctx, bson.D{
+		bson.E{Key: "article_id", Value: id},
+		bson.E{Key: "uid", Value: uid},
+		bson.E{Key: "role", Value: collaboratorRoleEditor},
+		bson.E{Key: "status", Value: collaboratorStatusAccepted},
+	})
+	if err != nil {
+		return 0, err
+	}
+	if cnt == 0 {
+		// 创作者不对，说明有人在瞎搞
+		return 0, ErrArticleNoPermission
+	}
+	return art.AuthorId, nil
+}
+
+func (m *MongoDBArticleDAO) InsertCollaborator(ctx context.Context, c ArticleCollaborator) error {
+	cnt, err := m.col.CountDocuments(ctx, bson.D{
+		bson.E{Key: "id", Value: c.ArticleId},
+		bson.E{Key: "author_id", Value: c.InviterId},
+	})
+	if err != nil {
+		return err
+	}
+	if cnt == 0 {
+		return ErrArticleNoPermission
+	}
+	now := time.Now().UnixMilli()
+	filter := bson.D{bson.E{Key: "article_id", Value: c.ArticleId},
+		bson.E{Key: "uid", Value: c.Uid}}
+	set := bson.D{
+		bson.E{Key: "$set", Value: bson.M{
+			"role":       c.Role,
+			"status":     c.Status,
+			"inviter_id": c.InviterId,
+			"utime":      now,
+		}},
+		bson.E{Key: "$setOnInsert", Value: bson.M{
+			"id":    m.node.Generate().Int64(),
+			"ctime": now,
+		}},
+	}
+	_, err = m.collabCol.UpdateOne(ctx, filter, set, options.Update().SetUpsert(true))
+	return err
+}
+
+func (m *MongoDBArticleDAO) AcceptCollaborator(ctx context.Context, aid int64, uid int64) error {
+	filter := bson.D{bson.E{Key: "article_id", Value: aid},
+		bson.E{Key: "uid", Value: uid},
+		bson.E{Key: "status", Value: collaboratorStatusInvited}}
+	set := bson.D{bson.E{Key: "$set", Value: bson.M{
+		"status": collaboratorStatusAccepted,
+		"utime":  time.Now().UnixMilli(),
+	}}}
+	res, err := m.collabCol.UpdateOne(ctx, filter, set)
 	if err != nil {
 		return err
 	}
 	if res.ModifiedCount == 0 {
-		// 创作者不对，说明有人在瞎搞
-		return errors.New("ID 不对或者创作者不对")
+		return ErrArticleNoPermission
+	}
+	return nil
+}
+
+func (m *MongoDBArticleDAO) DeleteCollaborator(ctx context.Context, operator int64, aid int64, uid int64) error {
+	if operator != uid {
+		cnt, err := m.col.CountDocuments(ctx, bson.D{
+			bson.E{Key: "id", Value: aid},
+			bson.E{Key: "author_id", Value: operator},
+		})
+		if err != nil {
+			return err
+		}
+		if cnt == 0 {
+			return ErrArticleNoPermission
+		}
+	}
+	res, err := m.collabCol.DeleteOne(ctx, bson.D{
+		bson.E{Key: "article_id", Value: aid},
+		bson.E{Key: "uid", Value: uid},
+	})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrArticleNoPermission
 	}
 	return nil
 }
 
+func (m *MongoDBArticleDAO) ListCollaborators(ctx context.Context, aid int64) ([]ArticleCollaborator, error) {
+	cursor, err := m.collabCol.Find(ctx, bson.D{bson.E{Key: "article_id", Value: aid}},
+		options.Find().SetSort(bson.D{bson.E{Key: "ctime", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var res []ArticleCollaborator
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
 // SyncWithTX 使用 mongodb 事务的实现
 func (m *MongoDBArticleDAO) SyncWithTX(ctx context.Context, art Article) (int64, error) {
 	var (
@@ -89,7 +273,7 @@ func (m *MongoDBArticleDAO) SyncWithTX(ctx context.Context, art Article) (int64,
 
 	_, err = sess.WithTransaction(ctx, func(ctx mongo.SessionContext) (interface{}, error) {
 		if id > 0 {
-			err = m.UpdateById(ctx, art)
+			art.AuthorId, err = m.update(ctx, art)
 		} else {
 			id, err = m.Insert(ctx, art)
 		}
@@ -119,7 +303,8 @@ func (m *MongoDBArticleDAO) Sync(ctx context.Context, art Article) (int64, error
 		err error
 	)
 	if id > 0 {
-		err = m.UpdateById(ctx, art)
+		// 可能是编辑在发表，线上库里面的作者还是创作者本人
+		art.AuthorId, err = m.update(ctx, art)
 	} else {
 		id, err = m.Insert(ctx, art)
 	}
@@ -142,17 +327,17 @@ func (m *MongoDBArticleDAO) Sync(ctx context.Context, art Article) (int64, error
 }
 
 func (m *MongoDBArticleDAO) SyncStatus(ctx context.Context, uid int64, id int64, status uint8) error {
-	filter := bson.D{bson.E{Key: "id", Value: id},
-		bson.E{Key: "author_id", Value: uid}}
+	_, err := m.checkEditable(ctx, id, uid)
+	if err != nil {
+		return err
+	}
+	filter := bson.D{bson.E{Key: "id", Value: id}}
 	sets := bson.D{bson.E{Key: "$set",
 		Value: bson.D{bson.E{Key: "status", Value: status}}}}
-	res, err := m.col.UpdateOne(ctx, filter, sets)
+	_, err = m.col.UpdateOne(ctx, filter, sets)
 	if err != nil {
 		return err
 	}
-	if res.ModifiedCount != 1 {
-		return errors.New("ID 不对或者创作者不对")
-	}
 	_, err = m.liveCol.UpdateOne(ctx, filter, sets)
 	return err
 }
@@ -161,9 +346,11 @@ var _ ArticleDAO = &MongoDBArticleDAO{}
 
 func NewMongoDBArticleDAO(mdb *mongo.Database, node *snowflake.Node) *MongoDBArticleDAO {
 	return &MongoDBArticleDAO{
-		node:    node,
-		liveCol: mdb.Collection("published_articles"),
-		col:     mdb.Collection("articles"),
+		node:      node,
+		liveCol:   mdb.Collection("published_articles"),
+		col:       mdb.Collection("articles"),
+		revCol:    mdb.Collection("article_revisions"),
+		collabCol: mdb.Collection("article_collaborators"),
 	}
 }
 
@@ -171,9 +358,11 @@ func NewMongoDBArticleDAO(mdb *mongo.Database, node *snowflake.Node) *MongoDBArt
 func NewMongoDBArticleDAOV1(client *mongo.Client, node *snowflake.Node) *MongoDBArticleDAO {
 	mdb := client.Database("webook")
 	return &MongoDBArticleDAO{
-		node:    node,
-		liveCol: mdb.Collection("published_articles"),
-		col:     mdb.Collection("articles"),
-		client:  client,
+		node:      node,
+		liveCol:   mdb.Collection("published_articles"),
+		col:       mdb.Collection("articles"),
+		revCol:    mdb.Collection("article_revisions"),
+		collabCol: mdb.Collection("article_collaborators"),
+		client:    client,
 	}
 }
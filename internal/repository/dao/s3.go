@@ -3,7 +3,6 @@ package dao
 import (
 	"bytes"
 	"context"
-	"errors"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/ecodeclub/ekit"
 	"gorm.io/gorm"
@@ -20,20 +19,21 @@ type ArticleS3DAO struct {
 func (a *ArticleS3DAO) SyncStatus(ctx context.Context, uid int64, id int64, status uint8) error {
 	now := time.Now().UnixMilli()
 	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		res := tx.Model(&Article{}).
-			Where("id = ? and author_id = ?", uid, id).
+		_, err := a.checkEditable(tx, id, uid)
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&Article{}).
+			Where("id = ?", id).
 			Updates(map[string]any{
 				"utime":  now,
 				"status": status,
-			})
-		if res.Error != nil {
-			return res.Error
-		}
-		if res.RowsAffected != 1 {
-			return errors.New("ID 不对或者创作者不对")
+			}).Error
+		if err != nil {
+			return err
 		}
 		return tx.Model(&PublishedArticleV2{}).
-			Where("id = ?", uid).
+			Where("id = ?", id).
 			Updates(map[string]any{
 				"utime":  now,
 				"status": status,
@@ -60,7 +60,8 @@ func (a *ArticleS3DAO) Sync(ctx context.Context, art Article) (int64, error) {
 		)
 		dao := NewArticleGORMDAO(tx)
 		if id > 0 {
-			err = dao.UpdateById(ctx, art)
+			// 可能是编辑在发表，线上库里面的作者还是创作者本人
+			art.AuthorId, err = a.update(tx, art, time.Now().UnixMilli())
 		} else {
 			id, err = dao.Insert(ctx, art)
 		}
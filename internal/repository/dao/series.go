@@ -0,0 +1,184 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+var ErrSeriesArticleInvalid = errors.New("文章不存在，或者不属于这个创作者")
+
+type SeriesDAO interface {
+	Insert(ctx context.Context, s Series) (int64, error)
+	UpdateById(ctx context.Context, s Series) error
+	Delete(ctx context.Context, uid int64, id int64) error
+	GetById(ctx context.Context, id int64) (Series, error)
+	GetByAuthor(ctx context.Context, uid int64) ([]Series, error)
+	// SetArticles 整个替换掉系列里面的文章，aids 的顺序就是章节顺序
+	SetArticles(ctx context.Context, uid int64, id int64, aids []int64) error
+	// ListArticles 系列里面的全部文章，包括草稿
+	ListArticles(ctx context.Context, id int64) ([]Article, error)
+	// ListPubArticles 系列里面已经发表的文章，只是目录用，不会查询内容
+	ListPubArticles(ctx context.Context, id int64) ([]PublishedArticle, error)
+	// GetByArticle 找到文章所在的系列
+	GetByArticle(ctx context.Context, aid int64) (Series, error)
+}
+
+type SeriesGORMDAO struct {
+	db *gorm.DB
+}
+
+func NewSeriesGORMDAO(db *gorm.DB) SeriesDAO {
+	return &SeriesGORMDAO{db: db}
+}
+
+func (s *SeriesGORMDAO) Insert(ctx context.Context, se Series) (int64, error) {
+	now := time.Now().UnixMilli()
+	se.Ctime = now
+	se.Utime = now
+	err := s.db.WithContext(ctx).Create(&se).Error
+	return se.Id, err
+}
+
+func (s *SeriesGORMDAO) UpdateById(ctx context.Context, se Series) error {
+	res := s.db.WithContext(ctx).Model(&Series{}).
+		Where("id = ? AND author_id = ?", se.Id, se.AuthorId).
+		Updates(map[string]any{
+			"title":       se.Title,
+			"description": se.Description,
+			"utime":       time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("ID 不对或者创作者不对")
+	}
+	return nil
+}
+
+func (s *SeriesGORMDAO) Delete(ctx context.Context, uid int64, id int64) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id = ? AND author_id = ?", id, uid).Delete(&Series{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errors.New("ID 不对或者创作者不对")
+		}
+		return tx.Where("series_id = ?", id).Delete(&SeriesArticle{}).Error
+	})
+}
+
+func (s *SeriesGORMDAO) GetById(ctx context.Context, id int64) (Series, error) {
+	var res Series
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&res).Error
+	return res, err
+}
+
+func (s *SeriesGORMDAO) GetByAuthor(ctx context.Context, uid int64) ([]Series, error) {
+	var res []Series
+	err := s.db.WithContext(ctx).Where("author_id = ?", uid).
+		Order("utime DESC").Find(&res).Error
+	return res, err
+}
+
+func (s *SeriesGORMDAO) SetArticles(ctx context.Context, uid int64, id int64, aids []int64) error {
+	now := time.Now().UnixMilli()
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Series{}).
+			Where("id = ? AND author_id = ?", id, uid).
+			Update("utime", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errors.New("ID 不对或者创作者不对")
+		}
+		if len(aids) > 0 {
+			// 只能把自己的文章放进去
+			var cnt int64
+			err := tx.Model(&Article{}).
+				Where("id IN ? AND author_id = ?", aids, uid).
+				Count(&cnt).Error
+			if err != nil {
+				return err
+			}
+			if cnt != int64(len(aids)) {
+				return ErrSeriesArticleInvalid
+			}
+		}
+		err := tx.Where("series_id = ?", id).Delete(&SeriesArticle{}).Error
+		if err != nil || len(aids) == 0 {
+			return err
+		}
+		sas := make([]SeriesArticle, 0, len(aids))
+		for i, aid := range aids {
+			sas = append(sas, SeriesArticle{
+				SeriesId:  id,
+				ArticleId: aid,
+				Seq:       i,
+				Ctime:     now,
+			})
+		}
+		// 一篇文章只能属于一个系列，由唯一索引来保证
+		return tx.Create(&sas).Error
+	})
+}
+
+func (s *SeriesGORMDAO) ListArticles(ctx context.Context, id int64) ([]Article, error) {
+	var res []Article
+	err := s.db.WithContext(ctx).Model(&Article{}).
+		Select("articles.*").
+		Joins("JOIN series_articles ON series_articles.article_id = articles.id").
+		Where("series_articles.series_id = ?", id).
+		Order("series_articles.seq ASC").
+		Find(&res).Error
+	return res, err
+}
+
+func (s *SeriesGORMDAO) ListPubArticles(ctx context.Context, id int64) ([]PublishedArticle, error) {
+	var res []PublishedArticle
+	const ArticleStatusPublished = 2
+	// 撤回的文章还在 series_articles 里面，只是读者看不到，
+	// 重新发表之后就会回到原来的位置
+	err := s.db.WithContext(ctx).Model(&PublishedArticle{}).
+		Select("published_articles.id, published_articles.title, published_articles.abstract, "+
+			"published_articles.members_only, published_articles.ctime").
+		Joins("JOIN series_articles ON series_articles.article_id = published_articles.id").
+		Where("series_articles.series_id = ? AND published_articles.status = ?",
+			id, ArticleStatusPublished).
+		Order("series_articles.seq ASC").
+		Find(&res).Error
+	return res, err
+}
+
+func (s *SeriesGORMDAO) GetByArticle(ctx context.Context, aid int64) (Series, error) {
+	var sa SeriesArticle
+	err := s.db.WithContext(ctx).Where("article_id = ?", aid).First(&sa).Error
+	if err != nil {
+		return Series{}, err
+	}
+	return s.GetById(ctx, sa.SeriesId)
+}
+
+type Series struct {
+	Id          int64  `gorm:"primaryKey,autoIncrement"`
+	Title       string `gorm:"type=varchar(256)"`
+	Description string `gorm:"type=varchar(4096)"`
+	AuthorId    int64  `gorm:"index"`
+	Ctime       int64
+	Utime       int64
+}
+
+// SeriesArticle 系列和文章的关联关系
+type SeriesArticle struct {
+	Id       int64 `gorm:"primaryKey,autoIncrement"`
+	SeriesId int64 `gorm:"index:idx_series_seq"`
+	// 一篇文章只能在一个系列里面
+	ArticleId int64 `gorm:"uniqueIndex"`
+	// 章节顺序，从 0 开始
+	Seq   int `gorm:"index:idx_series_seq"`
+	Ctime int64
+}
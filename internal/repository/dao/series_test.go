@@ -0,0 +1,32 @@
+package dao
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+)
+
+func TestSeriesGORMDAO_ListPubArticles(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	// 目录不需要内容，不然一个系列几十章，全部内容都要查出来
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT published_articles.id, published_articles.title, "+
+		"published_articles.abstract, published_articles.members_only, published_articles.ctime "+
+		"FROM `published_articles` JOIN series_articles ON series_articles.article_id = published_articles.id "+
+		"WHERE series_articles.series_id = ? AND published_articles.status = ? ORDER BY series_articles.seq ASC")).
+		WithArgs(int64(1), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "abstract", "members_only", "ctime"}).
+			AddRow(1, "第一章", "第一章的摘要", false, 1000).
+			AddRow(2, "第二章", "第二章的摘要", true, 2000))
+	dao := NewSeriesGORMDAO(openMockDB(t, sqlDB))
+	res, err := dao.ListPubArticles(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []PublishedArticle{
+		{Id: 1, Title: "第一章", Abstract: "第一章的摘要", Ctime: 1000},
+		{Id: 2, Title: "第二章", Abstract: "第二章的摘要", MembersOnly: true, Ctime: 2000},
+	}, res)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -108,6 +108,9 @@ type User struct {
 	WechatOpenId  sql.NullString `gorm:"unique"`
 	WechatUnionId sql.NullString
 
+	// Role 只能直接改数据库，0 是普通用户
+	Role uint8
+
 	// 时区，UTC 0 的毫秒数
 	// 创建时间
 	Ctime int64
@@ -4,16 +4,49 @@ import (
 	"context"
 	"gitee.com/geekbang/basic-go/webook/internal/domain"
 	"gitee.com/geekbang/basic-go/webook/internal/repository/dao"
+	"github.com/ecodeclub/ekit/slice"
+	"strings"
 	"time"
 )
 
+var (
+	ErrJobStatusConflict = dao.ErrJobStatusConflict
+	ErrDuplicateJob      = dao.ErrDuplicateJob
+	ErrJobNotFound       = dao.ErrRecordNotFound
+)
+
+//go:generate mockgen -source=./job.go -package=repomocks -destination=./mocks/job.mock.go CronJobRepository
 type CronJobRepository interface {
-	Preempt(ctx context.Context) (domain.Job, error)
-	Release(ctx context.Context, jid int64) error
-	UpdateUtime(ctx context.Context, id int64) error
-	UpdateNextTime(ctx context.Context, id int64, time time.Time) error
+	AddJob(ctx context.Context, j domain.Job) error
+	Create(ctx context.Context, j domain.Job) (int64, error)
+	Update(ctx context.Context, j domain.Job) error
+	GetById(ctx context.Context, id int64) (domain.Job, error)
+	Preempt(ctx context.Context, n domain.JobNode) (domain.Job, error)
+	Release(ctx context.Context, jid int64, owner string) error
+	UpdateUtime(ctx context.Context, id int64, owner string) error
+	UpdateNextTime(ctx context.Context, id int64, owner string, time time.Time, date string) error
+	IncrFailures(ctx context.Context, id int64, owner string, time time.Time, maxFailures int, date string) error
+	Skip(ctx context.Context, id int64, owner string, time time.Time, date string) error
+	List(ctx context.Context, offset int, limit int) ([]domain.Job, error)
+	ListByIds(ctx context.Context, ids []int64) ([]domain.Job, error)
+	ListByStatus(ctx context.Context, status domain.JobStatus, offset int, limit int) ([]domain.Job, error)
+	Pause(ctx context.Context, id int64) error
+	Resume(ctx context.Context, id int64, time time.Time) error
+	Trigger(ctx context.Context, id int64, date string) error
+	Delete(ctx context.Context, id int64) error
+	SetUpstreams(ctx context.Context, id int64, upstreams []int64) error
+	ListDependencies(ctx context.Context) ([]domain.JobDependency, error)
+	HasUnsuccessfulUpstream(ctx context.Context, id int64, date string) (bool, error)
+	ListInstances(ctx context.Context, date string) ([]domain.JobInstance, error)
+	Heartbeat(ctx context.Context, n domain.JobNode) error
+	ListNodes(ctx context.Context) ([]domain.JobNode, error)
+	AddRun(ctx context.Context, r domain.JobRun) error
+	ListRuns(ctx context.Context, jid int64, offset int, limit int) ([]domain.JobRun, error)
 }
 
+// maxRunErrLen 执行记录里面的错误信息最多保留多少个字符
+const maxRunErrLen = 1024
+
 type PreemptJobRepository struct {
 	dao dao.JobDAO
 }
@@ -22,24 +55,246 @@ func NewPreemptJobRepository(dao dao.JobDAO) CronJobRepository {
 	return &PreemptJobRepository{dao: dao}
 }
 
-func (p *PreemptJobRepository) Preempt(ctx context.Context) (domain.Job, error) {
-	j, err := p.dao.Preempt(ctx)
-	return domain.Job{
-		Id:         j.Id,
-		Expression: j.Expression,
-		Executor:   j.Executor,
-		Name:       j.Name,
-	}, err
+func (p *PreemptJobRepository) AddJob(ctx context.Context, j domain.Job) error {
+	return p.dao.Insert(ctx, p.toEntity(j))
+}
+
+func (p *PreemptJobRepository) Create(ctx context.Context, j domain.Job) (int64, error) {
+	return p.dao.Create(ctx, p.toEntity(j))
+}
+
+func (p *PreemptJobRepository) Update(ctx context.Context, j domain.Job) error {
+	return p.dao.Update(ctx, p.toEntity(j))
+}
+
+func (p *PreemptJobRepository) GetById(ctx context.Context, id int64) (domain.Job, error) {
+	j, err := p.dao.GetById(ctx, id)
+	if err != nil {
+		return domain.Job{}, err
+	}
+	return p.toDomain(j), nil
+}
+
+func (p *PreemptJobRepository) Preempt(ctx context.Context, n domain.JobNode) (domain.Job, error) {
+	j, err := p.dao.Preempt(ctx, p.toNodeEntity(n))
+	return p.toDomain(j), err
+}
+
+func (p *PreemptJobRepository) Release(ctx context.Context, jid int64, owner string) error {
+	return p.dao.Release(ctx, jid, owner)
+}
+
+func (p *PreemptJobRepository) UpdateUtime(ctx context.Context, id int64, owner string) error {
+	return p.dao.UpdateUtime(ctx, id, owner)
+}
+
+func (p *PreemptJobRepository) UpdateNextTime(ctx context.Context, id int64, owner string, time time.Time, date string) error {
+	return p.dao.UpdateNextTime(ctx, id, owner, time, date)
+}
+
+func (p *PreemptJobRepository) IncrFailures(ctx context.Context, id int64, owner string, time time.Time, maxFailures int, date string) error {
+	return p.dao.IncrFailures(ctx, id, owner, time, maxFailures, date)
+}
+
+func (p *PreemptJobRepository) Skip(ctx context.Context, id int64, owner string, time time.Time, date string) error {
+	return p.dao.Skip(ctx, id, owner, time, date)
+}
+
+func (p *PreemptJobRepository) List(ctx context.Context, offset int, limit int) ([]domain.Job, error) {
+	js, err := p.dao.List(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(js, func(idx int, src dao.Job) domain.Job {
+		return p.toDomain(src)
+	}), nil
+}
+
+func (p *PreemptJobRepository) ListByIds(ctx context.Context, ids []int64) ([]domain.Job, error) {
+	js, err := p.dao.ListByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(js, func(idx int, src dao.Job) domain.Job {
+		return p.toDomain(src)
+	}), nil
+}
+
+func (p *PreemptJobRepository) ListByStatus(ctx context.Context, status domain.JobStatus,
+	offset int, limit int) ([]domain.Job, error) {
+	js, err := p.dao.ListByStatus(ctx, int(status), offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(js, func(idx int, src dao.Job) domain.Job {
+		return p.toDomain(src)
+	}), nil
 }
 
-func (p *PreemptJobRepository) Release(ctx context.Context, jid int64) error {
-	return p.dao.Release(ctx, jid)
+func (p *PreemptJobRepository) Pause(ctx context.Context, id int64) error {
+	return p.dao.Pause(ctx, id)
 }
 
-func (p *PreemptJobRepository) UpdateUtime(ctx context.Context, id int64) error {
-	return p.dao.UpdateUtime(ctx, id)
+func (p *PreemptJobRepository) Resume(ctx context.Context, id int64, time time.Time) error {
+	return p.dao.Resume(ctx, id, time)
+}
+
+func (p *PreemptJobRepository) Trigger(ctx context.Context, id int64, date string) error {
+	return p.dao.Trigger(ctx, id, date)
+}
+
+func (p *PreemptJobRepository) Delete(ctx context.Context, id int64) error {
+	return p.dao.Delete(ctx, id)
+}
+
+func (p *PreemptJobRepository) SetUpstreams(ctx context.Context, id int64, upstreams []int64) error {
+	return p.dao.SetUpstreams(ctx, id, upstreams)
+}
+
+func (p *PreemptJobRepository) ListDependencies(ctx context.Context) ([]domain.JobDependency, error) {
+	deps, err := p.dao.ListDependencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(deps, func(idx int, src dao.JobDependency) domain.JobDependency {
+		return domain.JobDependency{
+			JobId:      src.JobId,
+			UpstreamId: src.UpstreamId,
+		}
+	}), nil
+}
+
+func (p *PreemptJobRepository) HasUnsuccessfulUpstream(ctx context.Context, id int64, date string) (bool, error) {
+	return p.dao.HasUnsuccessfulUpstream(ctx, id, date)
+}
+
+func (p *PreemptJobRepository) ListInstances(ctx context.Context, date string) ([]domain.JobInstance, error) {
+	ins, err := p.dao.ListInstances(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(ins, func(idx int, src dao.JobInstance) domain.JobInstance {
+		return domain.JobInstance{
+			JobId:       src.JobId,
+			LogicalDate: src.LogicalDate,
+			Status:      domain.JobInstanceStatus(src.Status),
+			Utime:       time.UnixMilli(src.Utime),
+		}
+	}), nil
+}
+
+func (p *PreemptJobRepository) Heartbeat(ctx context.Context, n domain.JobNode) error {
+	return p.dao.Heartbeat(ctx, p.toNodeEntity(n))
+}
+
+func (p *PreemptJobRepository) ListNodes(ctx context.Context) ([]domain.JobNode, error) {
+	ns, err := p.dao.ListNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(ns, func(idx int, src dao.JobNode) domain.JobNode {
+		var labels []string
+		if src.Labels != "" {
+			labels = strings.Split(src.Labels, ",")
+		}
+		return domain.JobNode{
+			Name:     src.Name,
+			Labels:   labels,
+			Capacity: src.Capacity,
+			Load:     src.Load,
+			Utime:    time.UnixMilli(src.Utime),
+		}
+	}), nil
+}
+
+func (p *PreemptJobRepository) AddRun(ctx context.Context, r domain.JobRun) error {
+	errMsg := []rune(r.Err)
+	if len(errMsg) > maxRunErrLen {
+		errMsg = errMsg[:maxRunErrLen]
+	}
+	return p.dao.InsertRun(ctx, dao.JobRun{
+		JobId:   r.JobId,
+		Node:    r.Node,
+		Attempt: r.Attempt,
+		Start:   r.Start.UnixMilli(),
+		End:     r.End.UnixMilli(),
+		Result:  r.Result.ToUint8(),
+		Err:     string(errMsg),
+	})
+}
+
+func (p *PreemptJobRepository) ListRuns(ctx context.Context, jid int64,
+	offset int, limit int) ([]domain.JobRun, error) {
+	rs, err := p.dao.ListRuns(ctx, jid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(rs, func(idx int, src dao.JobRun) domain.JobRun {
+		return domain.JobRun{
+			Id:      src.Id,
+			JobId:   src.JobId,
+			Node:    src.Node,
+			Attempt: src.Attempt,
+			Start:   time.UnixMilli(src.Start),
+			End:     time.UnixMilli(src.End),
+			Result:  domain.JobRunResult(src.Result),
+			Err:     src.Err,
+		}
+	}), nil
+}
+
+func (p *PreemptJobRepository) toDomain(j dao.Job) domain.Job {
+	return domain.Job{
+		Id:          j.Id,
+		Expression:  j.Expression,
+		Executor:    j.Executor,
+		Target:      j.Target,
+		Name:        j.Name,
+		Cfg:         j.Cfg,
+		MaxRetries:  j.MaxRetries,
+		Backoff:     time.Duration(j.Backoff) * time.Millisecond,
+		Timeout:     time.Duration(j.Timeout) * time.Millisecond,
+		MaxFailures: j.MaxFailures,
+		Failures:    j.Failures,
+		Label:       j.Label,
+		Weight:      j.Weight,
+		Status:      domain.JobStatus(j.Status),
+		Owner:       j.Owner,
+		NextRunAt:   time.UnixMilli(j.NextTime),
+		LogicalDate: j.LogicalDate,
+		Ctime:       time.UnixMilli(j.Ctime),
+		Utime:       time.UnixMilli(j.Utime),
+
+		UpstreamPolicy: domain.JobUpstreamPolicy(j.UpstreamPolicy),
+	}
+}
+
+// toEntity 下一次执行时间按照表达式重新计算
+func (p *PreemptJobRepository) toEntity(j domain.Job) dao.Job {
+	return dao.Job{
+		Id:          j.Id,
+		Name:        j.Name,
+		Executor:    j.Executor,
+		Target:      j.Target,
+		Expression:  j.Expression,
+		Cfg:         j.Cfg,
+		MaxRetries:  j.MaxRetries,
+		Backoff:     j.Backoff.Milliseconds(),
+		Timeout:     j.Timeout.Milliseconds(),
+		MaxFailures: j.MaxFailures,
+		Label:       j.Label,
+		Weight:      j.Cost(),
+		NextTime:    j.NextTime().UnixMilli(),
+
+		UpstreamPolicy: j.UpstreamPolicy.ToUint8(),
+	}
 }
 
-func (p *PreemptJobRepository) UpdateNextTime(ctx context.Context, id int64, time time.Time) error {
-	return p.dao.UpdateNextTime(ctx, id, time)
+func (p *PreemptJobRepository) toNodeEntity(n domain.JobNode) dao.JobNode {
+	return dao.JobNode{
+		Name:     n.Name,
+		Labels:   strings.Join(n.Labels, ","),
+		Capacity: n.Capacity,
+		Load:     n.Load,
+	}
 }
@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./article_review.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./article_review.go -package=repomocks -destination=./mocks/article_review.mock.go ArticleReviewRepository
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "gitee.com/geekbang/basic-go/webook/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArticleReviewRepository is a mock of ArticleReviewRepository interface.
+type MockArticleReviewRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockArticleReviewRepositoryMockRecorder
+}
+
+// MockArticleReviewRepositoryMockRecorder is the mock recorder for MockArticleReviewRepository.
+type MockArticleReviewRepositoryMockRecorder struct {
+	mock *MockArticleReviewRepository
+}
+
+// NewMockArticleReviewRepository creates a new mock instance.
+func NewMockArticleReviewRepository(ctrl *gomock.Controller) *MockArticleReviewRepository {
+	mock := &MockArticleReviewRepository{ctrl: ctrl}
+	mock.recorder = &MockArticleReviewRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArticleReviewRepository) EXPECT() *MockArticleReviewRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockArticleReviewRepository) Claim(ctx context.Context, aid, reviewer int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, aid, reviewer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockArticleReviewRepositoryMockRecorder) Claim(ctx, aid, reviewer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockArticleReviewRepository)(nil).Claim), ctx, aid, reviewer)
+}
+
+// GetByArticle mocks base method.
+func (m *MockArticleReviewRepository) GetByArticle(ctx context.Context, aid int64) (domain.ArticleReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByArticle", ctx, aid)
+	ret0, _ := ret[0].(domain.ArticleReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByArticle indicates an expected call of GetByArticle.
+func (mr *MockArticleReviewRepositoryMockRecorder) GetByArticle(ctx, aid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByArticle", reflect.TypeOf((*MockArticleReviewRepository)(nil).GetByArticle), ctx, aid)
+}
+
+// ListQueue mocks base method.
+func (m *MockArticleReviewRepository) ListQueue(ctx context.Context, reviewer int64, offset, limit int) ([]domain.ArticleReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListQueue", ctx, reviewer, offset, limit)
+	ret0, _ := ret[0].([]domain.ArticleReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListQueue indicates an expected call of ListQueue.
+func (mr *MockArticleReviewRepositoryMockRecorder) ListQueue(ctx, reviewer, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListQueue", reflect.TypeOf((*MockArticleReviewRepository)(nil).ListQueue), ctx, reviewer, offset, limit)
+}
+
+// Submit mocks base method.
+func (m *MockArticleReviewRepository) Submit(ctx context.Context, r domain.ArticleReview, from []domain.ReviewStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Submit", ctx, r, from)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Submit indicates an expected call of Submit.
+func (mr *MockArticleReviewRepositoryMockRecorder) Submit(ctx, r, from any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Submit", reflect.TypeOf((*MockArticleReviewRepository)(nil).Submit), ctx, r, from)
+}
+
+// Transit mocks base method.
+func (m *MockArticleReviewRepository) Transit(ctx context.Context, aid, reviewer int64, from []domain.ReviewStatus, to domain.ReviewStatus, comment string) (domain.ArticleReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transit", ctx, aid, reviewer, from, to, comment)
+	ret0, _ := ret[0].(domain.ArticleReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transit indicates an expected call of Transit.
+func (mr *MockArticleReviewRepositoryMockRecorder) Transit(ctx, aid, reviewer, from, to, comment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transit", reflect.TypeOf((*MockArticleReviewRepository)(nil).Transit), ctx, aid, reviewer, from, to, comment)
+}
@@ -0,0 +1,402 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./job.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./job.go -package=repomocks -destination=./mocks/job.mock.go CronJobRepository
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "gitee.com/geekbang/basic-go/webook/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCronJobRepository is a mock of CronJobRepository interface.
+type MockCronJobRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCronJobRepositoryMockRecorder
+}
+
+// MockCronJobRepositoryMockRecorder is the mock recorder for MockCronJobRepository.
+type MockCronJobRepositoryMockRecorder struct {
+	mock *MockCronJobRepository
+}
+
+// NewMockCronJobRepository creates a new mock instance.
+func NewMockCronJobRepository(ctrl *gomock.Controller) *MockCronJobRepository {
+	mock := &MockCronJobRepository{ctrl: ctrl}
+	mock.recorder = &MockCronJobRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCronJobRepository) EXPECT() *MockCronJobRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddJob mocks base method.
+func (m *MockCronJobRepository) AddJob(ctx context.Context, j domain.Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddJob", ctx, j)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddJob indicates an expected call of AddJob.
+func (mr *MockCronJobRepositoryMockRecorder) AddJob(ctx, j any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddJob", reflect.TypeOf((*MockCronJobRepository)(nil).AddJob), ctx, j)
+}
+
+// AddRun mocks base method.
+func (m *MockCronJobRepository) AddRun(ctx context.Context, r domain.JobRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRun", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRun indicates an expected call of AddRun.
+func (mr *MockCronJobRepositoryMockRecorder) AddRun(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRun", reflect.TypeOf((*MockCronJobRepository)(nil).AddRun), ctx, r)
+}
+
+// Create mocks base method.
+func (m *MockCronJobRepository) Create(ctx context.Context, j domain.Job) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, j)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCronJobRepositoryMockRecorder) Create(ctx, j any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCronJobRepository)(nil).Create), ctx, j)
+}
+
+// Delete mocks base method.
+func (m *MockCronJobRepository) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCronJobRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCronJobRepository)(nil).Delete), ctx, id)
+}
+
+// GetById mocks base method.
+func (m *MockCronJobRepository) GetById(ctx context.Context, id int64) (domain.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id)
+	ret0, _ := ret[0].(domain.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockCronJobRepositoryMockRecorder) GetById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockCronJobRepository)(nil).GetById), ctx, id)
+}
+
+// HasUnsuccessfulUpstream mocks base method.
+func (m *MockCronJobRepository) HasUnsuccessfulUpstream(ctx context.Context, id int64, date string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasUnsuccessfulUpstream", ctx, id, date)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasUnsuccessfulUpstream indicates an expected call of HasUnsuccessfulUpstream.
+func (mr *MockCronJobRepositoryMockRecorder) HasUnsuccessfulUpstream(ctx, id, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasUnsuccessfulUpstream", reflect.TypeOf((*MockCronJobRepository)(nil).HasUnsuccessfulUpstream), ctx, id, date)
+}
+
+// Heartbeat mocks base method.
+func (m *MockCronJobRepository) Heartbeat(ctx context.Context, n domain.JobNode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Heartbeat", ctx, n)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Heartbeat indicates an expected call of Heartbeat.
+func (mr *MockCronJobRepositoryMockRecorder) Heartbeat(ctx, n any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Heartbeat", reflect.TypeOf((*MockCronJobRepository)(nil).Heartbeat), ctx, n)
+}
+
+// IncrFailures mocks base method.
+func (m *MockCronJobRepository) IncrFailures(ctx context.Context, id int64, owner string, time time.Time, maxFailures int, date string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrFailures", ctx, id, owner, time, maxFailures, date)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrFailures indicates an expected call of IncrFailures.
+func (mr *MockCronJobRepositoryMockRecorder) IncrFailures(ctx, id, owner, time, maxFailures, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrFailures", reflect.TypeOf((*MockCronJobRepository)(nil).IncrFailures), ctx, id, owner, time, maxFailures, date)
+}
+
+// List mocks base method.
+func (m *MockCronJobRepository) List(ctx context.Context, offset, limit int) ([]domain.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, offset, limit)
+	ret0, _ := ret[0].([]domain.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockCronJobRepositoryMockRecorder) List(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCronJobRepository)(nil).List), ctx, offset, limit)
+}
+
+// ListByIds mocks base method.
+func (m *MockCronJobRepository) ListByIds(ctx context.Context, ids []int64) ([]domain.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByIds", ctx, ids)
+	ret0, _ := ret[0].([]domain.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByIds indicates an expected call of ListByIds.
+func (mr *MockCronJobRepositoryMockRecorder) ListByIds(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByIds", reflect.TypeOf((*MockCronJobRepository)(nil).ListByIds), ctx, ids)
+}
+
+// ListByStatus mocks base method.
+func (m *MockCronJobRepository) ListByStatus(ctx context.Context, status domain.JobStatus, offset, limit int) ([]domain.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByStatus", ctx, status, offset, limit)
+	ret0, _ := ret[0].([]domain.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByStatus indicates an expected call of ListByStatus.
+func (mr *MockCronJobRepositoryMockRecorder) ListByStatus(ctx, status, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByStatus", reflect.TypeOf((*MockCronJobRepository)(nil).ListByStatus), ctx, status, offset, limit)
+}
+
+// ListDependencies mocks base method.
+func (m *MockCronJobRepository) ListDependencies(ctx context.Context) ([]domain.JobDependency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDependencies", ctx)
+	ret0, _ := ret[0].([]domain.JobDependency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDependencies indicates an expected call of ListDependencies.
+func (mr *MockCronJobRepositoryMockRecorder) ListDependencies(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDependencies", reflect.TypeOf((*MockCronJobRepository)(nil).ListDependencies), ctx)
+}
+
+// ListInstances mocks base method.
+func (m *MockCronJobRepository) ListInstances(ctx context.Context, date string) ([]domain.JobInstance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInstances", ctx, date)
+	ret0, _ := ret[0].([]domain.JobInstance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInstances indicates an expected call of ListInstances.
+func (mr *MockCronJobRepositoryMockRecorder) ListInstances(ctx, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstances", reflect.TypeOf((*MockCronJobRepository)(nil).ListInstances), ctx, date)
+}
+
+// ListNodes mocks base method.
+func (m *MockCronJobRepository) ListNodes(ctx context.Context) ([]domain.JobNode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNodes", ctx)
+	ret0, _ := ret[0].([]domain.JobNode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNodes indicates an expected call of ListNodes.
+func (mr *MockCronJobRepositoryMockRecorder) ListNodes(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNodes", reflect.TypeOf((*MockCronJobRepository)(nil).ListNodes), ctx)
+}
+
+// ListRuns mocks base method.
+func (m *MockCronJobRepository) ListRuns(ctx context.Context, jid int64, offset, limit int) ([]domain.JobRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRuns", ctx, jid, offset, limit)
+	ret0, _ := ret[0].([]domain.JobRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRuns indicates an expected call of ListRuns.
+func (mr *MockCronJobRepositoryMockRecorder) ListRuns(ctx, jid, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRuns", reflect.TypeOf((*MockCronJobRepository)(nil).ListRuns), ctx, jid, offset, limit)
+}
+
+// Pause mocks base method.
+func (m *MockCronJobRepository) Pause(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pause", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Pause indicates an expected call of Pause.
+func (mr *MockCronJobRepositoryMockRecorder) Pause(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockCronJobRepository)(nil).Pause), ctx, id)
+}
+
+// Preempt mocks base method.
+func (m *MockCronJobRepository) Preempt(ctx context.Context, n domain.JobNode) (domain.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Preempt", ctx, n)
+	ret0, _ := ret[0].(domain.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Preempt indicates an expected call of Preempt.
+func (mr *MockCronJobRepositoryMockRecorder) Preempt(ctx, n any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Preempt", reflect.TypeOf((*MockCronJobRepository)(nil).Preempt), ctx, n)
+}
+
+// Release mocks base method.
+func (m *MockCronJobRepository) Release(ctx context.Context, jid int64, owner string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, jid, owner)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockCronJobRepositoryMockRecorder) Release(ctx, jid, owner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockCronJobRepository)(nil).Release), ctx, jid, owner)
+}
+
+// Resume mocks base method.
+func (m *MockCronJobRepository) Resume(ctx context.Context, id int64, time time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resume", ctx, id, time)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resume indicates an expected call of Resume.
+func (mr *MockCronJobRepositoryMockRecorder) Resume(ctx, id, time any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockCronJobRepository)(nil).Resume), ctx, id, time)
+}
+
+// SetUpstreams mocks base method.
+func (m *MockCronJobRepository) SetUpstreams(ctx context.Context, id int64, upstreams []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUpstreams", ctx, id, upstreams)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUpstreams indicates an expected call of SetUpstreams.
+func (mr *MockCronJobRepositoryMockRecorder) SetUpstreams(ctx, id, upstreams any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUpstreams", reflect.TypeOf((*MockCronJobRepository)(nil).SetUpstreams), ctx, id, upstreams)
+}
+
+// Skip mocks base method.
+func (m *MockCronJobRepository) Skip(ctx context.Context, id int64, owner string, time time.Time, date string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Skip", ctx, id, owner, time, date)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Skip indicates an expected call of Skip.
+func (mr *MockCronJobRepositoryMockRecorder) Skip(ctx, id, owner, time, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Skip", reflect.TypeOf((*MockCronJobRepository)(nil).Skip), ctx, id, owner, time, date)
+}
+
+// Trigger mocks base method.
+func (m *MockCronJobRepository) Trigger(ctx context.Context, id int64, date string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trigger", ctx, id, date)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Trigger indicates an expected call of Trigger.
+func (mr *MockCronJobRepositoryMockRecorder) Trigger(ctx, id, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trigger", reflect.TypeOf((*MockCronJobRepository)(nil).Trigger), ctx, id, date)
+}
+
+// Update mocks base method.
+func (m *MockCronJobRepository) Update(ctx context.Context, j domain.Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, j)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCronJobRepositoryMockRecorder) Update(ctx, j any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCronJobRepository)(nil).Update), ctx, j)
+}
+
+// UpdateNextTime mocks base method.
+func (m *MockCronJobRepository) UpdateNextTime(ctx context.Context, id int64, owner string, time time.Time, date string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNextTime", ctx, id, owner, time, date)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNextTime indicates an expected call of UpdateNextTime.
+func (mr *MockCronJobRepositoryMockRecorder) UpdateNextTime(ctx, id, owner, time, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNextTime", reflect.TypeOf((*MockCronJobRepository)(nil).UpdateNextTime), ctx, id, owner, time, date)
+}
+
+// UpdateUtime mocks base method.
+func (m *MockCronJobRepository) UpdateUtime(ctx context.Context, id int64, owner string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUtime", ctx, id, owner)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUtime indicates an expected call of UpdateUtime.
+func (mr *MockCronJobRepositoryMockRecorder) UpdateUtime(ctx, id, owner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUtime", reflect.TypeOf((*MockCronJobRepository)(nil).UpdateUtime), ctx, id, owner)
+}
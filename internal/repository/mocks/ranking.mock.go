@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./ranking.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./ranking.go -package=repomocks -destination=./mocks/ranking.mock.go RankingRepository
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "gitee.com/geekbang/basic-go/webook/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRankingRepository is a mock of RankingRepository interface.
+type MockRankingRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRankingRepositoryMockRecorder
+}
+
+// MockRankingRepositoryMockRecorder is the mock recorder for MockRankingRepository.
+type MockRankingRepositoryMockRecorder struct {
+	mock *MockRankingRepository
+}
+
+// NewMockRankingRepository creates a new mock instance.
+func NewMockRankingRepository(ctrl *gomock.Controller) *MockRankingRepository {
+	mock := &MockRankingRepository{ctrl: ctrl}
+	mock.recorder = &MockRankingRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRankingRepository) EXPECT() *MockRankingRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetTopIds mocks base method.
+func (m *MockRankingRepository) GetTopIds(ctx context.Context, key string, n int) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopIds", ctx, key, n)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopIds indicates an expected call of GetTopIds.
+func (mr *MockRankingRepositoryMockRecorder) GetTopIds(ctx, key, n any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopIds", reflect.TypeOf((*MockRankingRepository)(nil).GetTopIds), ctx, key, n)
+}
+
+// GetTopN mocks base method.
+func (m *MockRankingRepository) GetTopN(ctx context.Context, key string) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopN", ctx, key)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopN indicates an expected call of GetTopN.
+func (mr *MockRankingRepositoryMockRecorder) GetTopN(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopN", reflect.TypeOf((*MockRankingRepository)(nil).GetTopN), ctx, key)
+}
+
+// IncrScore mocks base method.
+func (m *MockRankingRepository) IncrScore(ctx context.Context, key string, aid int64, weight float64, at time.Time, halfLife time.Duration, capacity int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrScore", ctx, key, aid, weight, at, halfLife, capacity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrScore indicates an expected call of IncrScore.
+func (mr *MockRankingRepositoryMockRecorder) IncrScore(ctx, key, aid, weight, at, halfLife, capacity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrScore", reflect.TypeOf((*MockRankingRepository)(nil).IncrScore), ctx, key, aid, weight, at, halfLife, capacity)
+}
+
+// ReplaceScores mocks base method.
+func (m *MockRankingRepository) ReplaceScores(ctx context.Context, key string, scores map[int64]float64, epoch time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceScores", ctx, key, scores, epoch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceScores indicates an expected call of ReplaceScores.
+func (mr *MockRankingRepositoryMockRecorder) ReplaceScores(ctx, key, scores, epoch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceScores", reflect.TypeOf((*MockRankingRepository)(nil).ReplaceScores), ctx, key, scores, epoch)
+}
+
+// ReplaceTopN mocks base method.
+func (m *MockRankingRepository) ReplaceTopN(ctx context.Context, key string, arts []domain.Article) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceTopN", ctx, key, arts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceTopN indicates an expected call of ReplaceTopN.
+func (mr *MockRankingRepositoryMockRecorder) ReplaceTopN(ctx, key, arts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceTopN", reflect.TypeOf((*MockRankingRepository)(nil).ReplaceTopN), ctx, key, arts)
+}
@@ -4,11 +4,21 @@ import (
 	"context"
 	"gitee.com/geekbang/basic-go/webook/internal/domain"
 	"gitee.com/geekbang/basic-go/webook/internal/repository/cache"
+	"time"
 )
 
+//go:generate mockgen -source=./ranking.go -package=repomocks -destination=./mocks/ranking.mock.go RankingRepository
 type RankingRepository interface {
-	ReplaceTopN(ctx context.Context, arts []domain.Article) error
-	GetTopN(ctx context.Context) ([]domain.Article, error)
+	// ReplaceTopN key 是热榜的名字
+	ReplaceTopN(ctx context.Context, key string, arts []domain.Article) error
+	GetTopN(ctx context.Context, key string) ([]domain.Article, error)
+
+	// 下面是实时热榜用的
+
+	IncrScore(ctx context.Context, key string, aid int64, weight float64,
+		at time.Time, halfLife time.Duration, capacity int) error
+	ReplaceScores(ctx context.Context, key string, scores map[int64]float64, epoch time.Time) error
+	GetTopIds(ctx context.Context, key string, n int) ([]int64, error)
 }
 
 type CachedRankingRepository struct {
@@ -29,7 +39,8 @@ func (repo *CachedRankingRepository) GetTopNV1(ctx context.Context) ([]domain.Ar
 	if err == nil {
 		return res, nil
 	}
-	res, err = repo.redisCache.Get(ctx)
+	// 本地缓存只缓存了全站热榜
+	res, err = repo.redisCache.Get(ctx, domain.DefaultRankingList)
 	if err != nil {
 		return repo.localCache.ForceGet(ctx)
 	}
@@ -37,8 +48,8 @@ func (repo *CachedRankingRepository) GetTopNV1(ctx context.Context) ([]domain.Ar
 	return res, nil
 }
 
-func (repo *CachedRankingRepository) GetTopN(ctx context.Context) ([]domain.Article, error) {
-	return repo.cache.Get(ctx)
+func (repo *CachedRankingRepository) GetTopN(ctx context.Context, key string) ([]domain.Article, error) {
+	return repo.cache.Get(ctx, key)
 }
 
 func NewCachedRankingRepository(cache cache.RankingCache) RankingRepository {
@@ -47,9 +58,23 @@ func NewCachedRankingRepository(cache cache.RankingCache) RankingRepository {
 
 func (repo *CachedRankingRepository) ReplaceTopNV1(ctx context.Context, arts []domain.Article) error {
 	_ = repo.localCache.Set(ctx, arts)
-	return repo.redisCache.Set(ctx, arts)
+	return repo.redisCache.Set(ctx, domain.DefaultRankingList, arts)
+}
+
+func (repo *CachedRankingRepository) ReplaceTopN(ctx context.Context, key string, arts []domain.Article) error {
+	return repo.cache.Set(ctx, key, arts)
+}
+
+func (repo *CachedRankingRepository) IncrScore(ctx context.Context, key string, aid int64, weight float64,
+	at time.Time, halfLife time.Duration, capacity int) error {
+	return repo.cache.IncrScore(ctx, key, aid, weight, at, halfLife, capacity)
+}
+
+func (repo *CachedRankingRepository) ReplaceScores(ctx context.Context, key string,
+	scores map[int64]float64, epoch time.Time) error {
+	return repo.cache.ReplaceScores(ctx, key, scores, epoch)
 }
 
-func (repo *CachedRankingRepository) ReplaceTopN(ctx context.Context, arts []domain.Article) error {
-	return repo.cache.Set(ctx, arts)
+func (repo *CachedRankingRepository) GetTopIds(ctx context.Context, key string, n int) ([]int64, error) {
+	return repo.cache.GetTopIds(ctx, key, n)
 }
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"gitee.com/geekbang/basic-go/webook/internal/repository/dao"
+	"github.com/ecodeclub/ekit/slice"
+	"gorm.io/gorm"
+	"time"
+)
+
+var ErrSeriesArticleInvalid = dao.ErrSeriesArticleInvalid
+
+//go:generate mockgen -source=./series.go -package=repomocks -destination=./mocks/series.mock.go SeriesRepository
+type SeriesRepository interface {
+	Create(ctx context.Context, s domain.Series) (int64, error)
+	Update(ctx context.Context, s domain.Series) error
+	Delete(ctx context.Context, uid int64, id int64) error
+	// GetById 创作者查看，包含全部文章
+	GetById(ctx context.Context, id int64) (domain.Series, error)
+	// GetByAuthor 不包含文章
+	GetByAuthor(ctx context.Context, uid int64) ([]domain.Series, error)
+	SetArticles(ctx context.Context, uid int64, id int64, aids []int64) error
+	// GetPubById 读者查看，只包含已经发表的文章
+	GetPubById(ctx context.Context, id int64) (domain.Series, error)
+	// GetNav 文章不在任何系列里面的时候，返回零值
+	GetNav(ctx context.Context, aid int64) (domain.SeriesNav, error)
+}
+
+type SeriesGORMRepository struct {
+	dao      dao.SeriesDAO
+	userRepo UserRepository
+}
+
+func NewSeriesRepository(dao dao.SeriesDAO, userRepo UserRepository) SeriesRepository {
+	return &SeriesGORMRepository{dao: dao, userRepo: userRepo}
+}
+
+func (s *SeriesGORMRepository) Create(ctx context.Context, se domain.Series) (int64, error) {
+	return s.dao.Insert(ctx, s.toEntity(se))
+}
+
+func (s *SeriesGORMRepository) Update(ctx context.Context, se domain.Series) error {
+	return s.dao.UpdateById(ctx, s.toEntity(se))
+}
+
+func (s *SeriesGORMRepository) Delete(ctx context.Context, uid int64, id int64) error {
+	return s.dao.Delete(ctx, uid, id)
+}
+
+func (s *SeriesGORMRepository) GetById(ctx context.Context, id int64) (domain.Series, error) {
+	se, err := s.dao.GetById(ctx, id)
+	if err != nil {
+		return domain.Series{}, err
+	}
+	arts, err := s.dao.ListArticles(ctx, id)
+	if err != nil {
+		return domain.Series{}, err
+	}
+	res := s.toDomain(se)
+	res.Articles = slice.Map[dao.Article, domain.Article](arts, func(idx int, src dao.Article) domain.Article {
+		return s.articleToDomain(src)
+	})
+	return res, nil
+}
+
+func (s *SeriesGORMRepository) GetByAuthor(ctx context.Context, uid int64) ([]domain.Series, error) {
+	ses, err := s.dao.GetByAuthor(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.Series, domain.Series](ses, func(idx int, src dao.Series) domain.Series {
+		return s.toDomain(src)
+	}), nil
+}
+
+func (s *SeriesGORMRepository) SetArticles(ctx context.Context, uid int64, id int64, aids []int64) error {
+	return s.dao.SetArticles(ctx, uid, id, aids)
+}
+
+func (s *SeriesGORMRepository) GetPubById(ctx context.Context, id int64) (domain.Series, error) {
+	se, err := s.dao.GetById(ctx, id)
+	if err != nil {
+		return domain.Series{}, err
+	}
+	arts, err := s.dao.ListPubArticles(ctx, id)
+	if err != nil {
+		return domain.Series{}, err
+	}
+	res := s.toDomain(se)
+	res.Articles = slice.Map[dao.PublishedArticle, domain.Article](arts,
+		func(idx int, src dao.PublishedArticle) domain.Article {
+			return s.pubArticleToDomain(src)
+		})
+	author, err := s.userRepo.FindById(ctx, se.AuthorId)
+	if err != nil {
+		return domain.Series{}, err
+	}
+	res.Author.Name = author.Nickname
+	return res, nil
+}
+
+func (s *SeriesGORMRepository) GetNav(ctx context.Context, aid int64) (domain.SeriesNav, error) {
+	se, err := s.dao.GetByArticle(ctx, aid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.SeriesNav{}, nil
+	}
+	if err != nil {
+		return domain.SeriesNav{}, err
+	}
+	arts, err := s.dao.ListPubArticles(ctx, se.Id)
+	if err != nil {
+		return domain.SeriesNav{}, err
+	}
+	res := domain.SeriesNav{
+		SeriesId:    se.Id,
+		SeriesTitle: se.Title,
+	}
+	for i, art := range arts {
+		if art.Id != aid {
+			continue
+		}
+		if i > 0 {
+			res.Prev = domain.SeriesChapter{Id: arts[i-1].Id, Title: arts[i-1].Title}
+		}
+		if i < len(arts)-1 {
+			res.Next = domain.SeriesChapter{Id: arts[i+1].Id, Title: arts[i+1].Title}
+		}
+		break
+	}
+	return res, nil
+}
+
+func (s *SeriesGORMRepository) toEntity(se domain.Series) dao.Series {
+	return dao.Series{
+		Id:          se.Id,
+		Title:       se.Title,
+		Description: se.Description,
+		AuthorId:    se.Author.Id,
+	}
+}
+
+func (s *SeriesGORMRepository) toDomain(se dao.Series) domain.Series {
+	return domain.Series{
+		Id:          se.Id,
+		Title:       se.Title,
+		Description: se.Description,
+		Author: domain.Author{
+			Id: se.AuthorId,
+		},
+		Ctime: time.UnixMilli(se.Ctime),
+		Utime: time.UnixMilli(se.Utime),
+	}
+}
+
+// pubArticleToDomain 读者看到的目录只有标题和摘要，
+// 会员专享的章节连摘要也不给，要看就得去文章详情那边校验会员
+func (s *SeriesGORMRepository) pubArticleToDomain(art dao.PublishedArticle) domain.Article {
+	res := domain.Article{
+		Id:          art.Id,
+		Title:       art.Title,
+		Status:      domain.ArticleStatusPublished,
+		MembersOnly: art.MembersOnly,
+		Ctime:       time.UnixMilli(art.Ctime),
+	}
+	if !art.MembersOnly {
+		res.Rendered.Abstract = art.Abstract
+	}
+	return res
+}
+
+func (s *SeriesGORMRepository) articleToDomain(art dao.Article) domain.Article {
+	return domain.Article{
+		Id:      art.Id,
+		Title:   art.Title,
+		Content: art.Content,
+		Author: domain.Author{
+			Id: art.AuthorId,
+		},
+		Status: domain.ArticleStatus(art.Status),
+		Ctime:  time.UnixMilli(art.Ctime),
+		Utime:  time.UnixMilli(art.Utime),
+	}
+}
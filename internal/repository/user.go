@@ -110,6 +110,7 @@ func (repo *CachedUserRepository) toDomain(u dao.User) domain.User {
 			OpenId:  u.WechatOpenId.String,
 			UnionId: u.WechatUnionId.String,
 		},
+		Role: domain.UserRole(u.Role),
 	}
 }
 
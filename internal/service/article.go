@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	rewardv1 "gitee.com/geekbang/basic-go/webook/api/proto/gen/reward/v1"
 	"gitee.com/geekbang/basic-go/webook/internal/domain"
 	"gitee.com/geekbang/basic-go/webook/internal/events/article"
 	"gitee.com/geekbang/basic-go/webook/internal/repository"
@@ -10,20 +11,69 @@ import (
 	"time"
 )
 
+var (
+	// ErrIllegalRevision 历史版本不存在，或者不属于这篇文章，或者文章不属于这个人
+	ErrIllegalRevision = errors.New("非法的历史版本")
+	// ErrInvalidPublishTime 定时发表的时间必须在将来
+	ErrInvalidPublishTime = errors.New("定时发表的时间不对")
+	// ErrIllegalCollaborator 角色不对，或者邀请了自己
+	ErrIllegalCollaborator = errors.New("非法的合作者")
+	// ErrArticleNoPermission 文章不存在，或者没有权限
+	ErrArticleNoPermission = repository.ErrArticleNoPermission
+	// ErrMembersOnly 文章只有作者的付费会员才能看
+	ErrMembersOnly = errors.New("仅会员可见")
+)
+
 //go:generate mockgen -source=./article.go -package=svcmocks -destination=./mocks/article.mock.go ArticleService
 type ArticleService interface {
 	Save(ctx context.Context, art domain.Article) (int64, error)
+	// Publish 需要审核的时候，只会保存草稿并提交审核
 	Publish(ctx context.Context, art domain.Article) (int64, error)
 	Withdraw(ctx context.Context, uid int64, id int64) error
-	GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error)
+	// GetByAuthor 按照更新时间倒序，cursor 为零值的时候就是第一页。
+	// 下一页的游标用 domain.NextArticleCursor 计算
+	GetByAuthor(ctx context.Context, uid int64, cursor domain.ArticleCursor, limit int) ([]domain.Article, error)
 	GetById(ctx context.Context, id int64) (domain.Article, error)
 	GetPubById(ctx context.Context, id, uid int64) (domain.Article, error)
-	ListPub(ctx context.Context, start time.Time, offset, limit int) ([]domain.Article, error)
+	// ListPub 和 GetByAuthor 一样的分页方式
+	ListPub(ctx context.Context, cursor domain.ArticleCursor, limit int) ([]domain.Article, error)
+	// ListPubByIds 批量查询线上库，不保证顺序，也不会发阅读消息
+	ListPubByIds(ctx context.Context, ids []int64) ([]domain.Article, error)
+	// ListRevisions 创作者查看自己文章的历史版本，最新的在前面
+	ListRevisions(ctx context.Context, uid, id int64, offset, limit int) ([]domain.ArticleRevision, error)
+	// DiffRevisions 比较同一篇文章的两个历史版本
+	DiffRevisions(ctx context.Context, uid, id, from, to int64) (domain.ArticleRevisionDiff, error)
+	// Rollback 回滚到某个历史版本，会生成一个新的草稿版本。
+	// publish 为 true 的时候，会接着重新发表
+	Rollback(ctx context.Context, uid, id, rid int64, publish bool) (int64, error)
+	// SchedulePublish 定时发表，到了 art.PublishAt 才会真的发表
+	SchedulePublish(ctx context.Context, art domain.Article) (int64, error)
+	// ListScheduled 还没有发表的定时文章，按照发表时间排序
+	ListScheduled(ctx context.Context, uid int64) ([]domain.Article, error)
+	Reschedule(ctx context.Context, uid, id int64, publishAt time.Time) error
+	// CancelSchedule 取消定时发表，文章回到草稿状态
+	CancelSchedule(ctx context.Context, uid, id int64) error
+	// PublishDue 发表所有已经到期的定时文章，由分布式任务调度调用，
+	// 所以同一时刻只会有一个节点在执行
+	PublishDue(ctx context.Context) error
+	// InviteCollaborator 只有创作者本人可以邀请合作者
+	InviteCollaborator(ctx context.Context, uid, id, invitee int64, role domain.CollaboratorRole) error
+	AcceptCollaborator(ctx context.Context, uid, id int64) error
+	// RemoveCollaborator 创作者可以移除任何人，合作者可以自己退出
+	RemoveCollaborator(ctx context.Context, uid, id, target int64) error
+	// ListCollaborators 创作者和合作者都可以查看，包括还没有接受邀请的
+	ListCollaborators(ctx context.Context, uid, id int64) ([]domain.ArticleCollaborator, error)
 }
 
 type articleService struct {
-	repo     repository.ArticleRepository
-	producer article.Producer
+	repo       repository.ArticleRepository
+	seriesRepo repository.SeriesRepository
+	reviewSvc  ArticleReviewService
+	producer   article.Producer
+	// rewardSvc 会员专享的文章要检查读者是不是作者的会员
+	rewardSvc rewardv1.RewardServiceClient
+	// 每次发表多少篇到期的定时文章
+	dueBatchSize int
 
 	userRepo repository.UserRepository
 
@@ -34,12 +84,29 @@ type articleService struct {
 }
 
 func (a *articleService) ListPub(ctx context.Context,
-	start time.Time, offset, limit int) ([]domain.Article, error) {
-	return a.repo.ListPub(ctx, start, offset, limit)
+	cursor domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	return a.repo.ListPub(ctx, cursor, limit)
+}
+
+func (a *articleService) ListPubByIds(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	return a.repo.ListPubByIds(ctx, ids)
 }
 
 func (a *articleService) GetPubById(ctx context.Context, id, uid int64) (domain.Article, error) {
 	res, err := a.repo.GetPubById(ctx, id)
+	if err == nil {
+		err = a.checkMembersOnly(ctx, res, uid)
+	}
+	if err == nil {
+		// 系列导航拿不到，不影响读者看文章
+		nav, er := a.seriesRepo.GetNav(ctx, id)
+		if er != nil {
+			a.l.Error("查询文章所在的系列失败",
+				logger.Int64("aid", id),
+				logger.Error(er))
+		}
+		res.Series = nav
+	}
 	go func() {
 		if err == nil {
 			// 在这里发一个消息
@@ -59,12 +126,206 @@ func (a *articleService) GetPubById(ctx context.Context, id, uid int64) (domain.
 	return res, err
 }
 
+func (a *articleService) ListRevisions(ctx context.Context, uid, id int64, offset, limit int) ([]domain.ArticleRevision, error) {
+	_, err := a.checkAuthor(ctx, uid, id)
+	if err != nil {
+		return nil, err
+	}
+	return a.repo.ListRevisions(ctx, id, offset, limit)
+}
+
+func (a *articleService) DiffRevisions(ctx context.Context, uid, id, from, to int64) (domain.ArticleRevisionDiff, error) {
+	_, err := a.checkAuthor(ctx, uid, id)
+	if err != nil {
+		return domain.ArticleRevisionDiff{}, err
+	}
+	fromRev, err := a.getRevision(ctx, id, from)
+	if err != nil {
+		return domain.ArticleRevisionDiff{}, err
+	}
+	toRev, err := a.getRevision(ctx, id, to)
+	if err != nil {
+		return domain.ArticleRevisionDiff{}, err
+	}
+	return domain.ArticleRevisionDiff{
+		From:    fromRev,
+		To:      toRev,
+		Title:   diffLines(fromRev.Title, toRev.Title),
+		Content: diffLines(fromRev.Content, toRev.Content),
+	}, nil
+}
+
+func (a *articleService) Rollback(ctx context.Context, uid, id, rid int64, publish bool) (int64, error) {
+	cur, err := a.checkAuthor(ctx, uid, id)
+	if err != nil {
+		return 0, err
+	}
+	rev, err := a.getRevision(ctx, id, rid)
+	if err != nil {
+		return 0, err
+	}
+	art := domain.Article{
+		Id:      id,
+		Title:   rev.Title,
+		Content: rev.Content,
+		Author: domain.Author{
+			Id: uid,
+		},
+		// 历史版本里面没有记录，回滚的时候保持现在的设置
+		MembersOnly: cur.MembersOnly,
+	}
+	// 先保存为草稿，这样回滚本身也会留下一个历史版本
+	_, err = a.Save(ctx, art)
+	if err != nil {
+		return 0, err
+	}
+	if !publish {
+		return id, nil
+	}
+	return a.Publish(ctx, art)
+}
+
+func (a *articleService) SchedulePublish(ctx context.Context, art domain.Article) (int64, error) {
+	if !art.PublishAt.After(time.Now()) {
+		return 0, ErrInvalidPublishTime
+	}
+	art.Status = domain.ArticleStatusScheduled
+	if art.Id > 0 {
+		err := a.repo.Update(ctx, art)
+		return art.Id, err
+	}
+	return a.repo.Create(ctx, art)
+}
+
+func (a *articleService) ListScheduled(ctx context.Context, uid int64) ([]domain.Article, error) {
+	return a.repo.ListScheduled(ctx, uid)
+}
+
+func (a *articleService) Reschedule(ctx context.Context, uid, id int64, publishAt time.Time) error {
+	if !publishAt.After(time.Now()) {
+		return ErrInvalidPublishTime
+	}
+	return a.repo.UpdateSchedule(ctx, uid, id, domain.ArticleStatusScheduled, publishAt)
+}
+
+func (a *articleService) CancelSchedule(ctx context.Context, uid, id int64) error {
+	return a.repo.UpdateSchedule(ctx, uid, id, domain.ArticleStatusUnpublished, time.Time{})
+}
+
+func (a *articleService) PublishDue(ctx context.Context) error {
+	for {
+		arts, err := a.repo.ListDueScheduled(ctx, time.Now(), a.dueBatchSize)
+		if err != nil {
+			return err
+		}
+		succeeded := 0
+		for _, art := range arts {
+			art.PublishAt = time.Time{}
+			_, err = a.Publish(ctx, art)
+			if err != nil {
+				// 下一次调度的时候还会再试
+				a.l.Error("定时发表文章失败",
+					logger.Int64("aid", art.Id),
+					logger.Error(err))
+				continue
+			}
+			succeeded++
+		}
+		// 没有取够一批，说明已经没有了；
+		// 一篇都没成功，继续下去也只会拿到同一批
+		if len(arts) < a.dueBatchSize || succeeded == 0 {
+			return nil
+		}
+	}
+}
+
+func (a *articleService) InviteCollaborator(ctx context.Context, uid, id, invitee int64, role domain.CollaboratorRole) error {
+	if !role.Valid() || invitee == uid {
+		return ErrIllegalCollaborator
+	}
+	return a.repo.InviteCollaborator(ctx, domain.ArticleCollaborator{
+		ArticleId: id,
+		User: domain.Author{
+			Id: invitee,
+		},
+		Role:      role,
+		Status:    domain.CollaboratorStatusInvited,
+		InviterId: uid,
+	})
+}
+
+func (a *articleService) AcceptCollaborator(ctx context.Context, uid, id int64) error {
+	return a.repo.AcceptCollaborator(ctx, id, uid)
+}
+
+func (a *articleService) RemoveCollaborator(ctx context.Context, uid, id, target int64) error {
+	return a.repo.RemoveCollaborator(ctx, uid, id, target)
+}
+
+func (a *articleService) ListCollaborators(ctx context.Context, uid, id int64) ([]domain.ArticleCollaborator, error) {
+	art, err := a.repo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !art.CanView(uid) {
+		// 被邀请了但是还没接受的人，也可以看到邀请
+		for _, c := range art.Collaborators {
+			if c.User.Id == uid {
+				return art.Collaborators, nil
+			}
+		}
+		return nil, ErrArticleNoPermission
+	}
+	return art.Collaborators, nil
+}
+
+// checkAuthor 合作者也可以看历史版本，能不能回滚由修改的时候来检查
+func (a *articleService) checkAuthor(ctx context.Context, uid, id int64) (domain.Article, error) {
+	art, err := a.repo.GetById(ctx, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	if !art.CanView(uid) {
+		return domain.Article{}, ErrIllegalRevision
+	}
+	return art, nil
+}
+
+// checkMembersOnly 会员专享的文章，只有作者、合作者和作者的会员才能看
+func (a *articleService) checkMembersOnly(ctx context.Context, art domain.Article, uid int64) error {
+	if !art.MembersOnly || art.CanView(uid) {
+		return nil
+	}
+	resp, err := a.rewardSvc.CheckEntitlement(ctx, &rewardv1.CheckEntitlementRequest{
+		Uid:        uid,
+		CreatorUid: art.Author.Id,
+	})
+	if err != nil {
+		return err
+	}
+	if !resp.GetEntitled() {
+		return ErrMembersOnly
+	}
+	return nil
+}
+
+func (a *articleService) getRevision(ctx context.Context, id, rid int64) (domain.ArticleRevision, error) {
+	rev, err := a.repo.GetRevisionById(ctx, rid)
+	if err != nil {
+		return domain.ArticleRevision{}, err
+	}
+	if rev.ArticleId != id {
+		return domain.ArticleRevision{}, ErrIllegalRevision
+	}
+	return rev, nil
+}
+
 func (a *articleService) GetById(ctx context.Context, id int64) (domain.Article, error) {
 	return a.repo.GetById(ctx, id)
 }
 
-func (a *articleService) GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error) {
-	return a.repo.GetByAuthor(ctx, uid, offset, limit)
+func (a *articleService) GetByAuthor(ctx context.Context, uid int64, cursor domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	return a.repo.GetByAuthor(ctx, uid, cursor, limit)
 }
 
 func (a *articleService) Withdraw(ctx context.Context, uid int64, id int64) error {
@@ -78,10 +339,27 @@ func (a *articleService) Withdraw(ctx context.Context, uid int64, id int64) erro
 }
 
 func (a *articleService) Publish(ctx context.Context, art domain.Article) (int64, error) {
+	if a.reviewSvc.NeedReview(art) {
+		return a.submitReview(ctx, art)
+	}
+	rendered, err := renderMarkdown(art.Content)
+	if err != nil {
+		return 0, err
+	}
+	art.Rendered = rendered
 	art.Status = domain.ArticleStatusPublished
 	return a.repo.Sync(ctx, art)
 }
 
+// submitReview 先保存成草稿，再把草稿提交审核，审核通过之后才会同步到线上库
+func (a *articleService) submitReview(ctx context.Context, art domain.Article) (int64, error) {
+	id, err := a.Save(ctx, art)
+	if err != nil {
+		return 0, err
+	}
+	return id, a.reviewSvc.Submit(ctx, art.Author.Id, id)
+}
+
 func (a *articleService) PublishV1(ctx context.Context, art domain.Article) (int64, error) {
 	// 想到这里要先操作制作库
 	// 这里操作线上库
@@ -129,10 +407,18 @@ func NewArticleServiceV1(
 }
 
 func NewArticleService(repo repository.ArticleRepository,
-	producer article.Producer) ArticleService {
+	seriesRepo repository.SeriesRepository,
+	reviewSvc ArticleReviewService,
+	producer article.Producer,
+	rewardSvc rewardv1.RewardServiceClient, l logger.LoggerV1) ArticleService {
 	return &articleService{
-		repo:     repo,
-		producer: producer,
+		repo:         repo,
+		seriesRepo:   seriesRepo,
+		reviewSvc:    reviewSvc,
+		producer:     producer,
+		rewardSvc:    rewardSvc,
+		dueBatchSize: 100,
+		l:            l,
 	}
 }
 
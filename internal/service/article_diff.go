@@ -0,0 +1,62 @@
+package service
+
+import (
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"strings"
+)
+
+// diffLines 按行比较两段文本，基于最长公共子序列。
+// 时间和空间复杂度都是 O(m*n)，文章的行数不会太多，所以问题不大
+func diffLines(from, to string) []domain.DiffLine {
+	a := splitLines(from)
+	b := splitLines(to)
+	m, n := len(a), len(b)
+	// lcs[i][j] 是 a[i:] 和 b[j:] 的最长公共子序列的长度
+	lcs := make([][]int, m+1)
+	for i := range lcs {
+		lcs[i] = make([]int, n+1)
+	}
+	for i := m - 1; i >= 0; i-- {
+		for j := n - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	res := make([]domain.DiffLine, 0, m+n)
+	i, j := 0, 0
+	for i < m && j < n {
+		switch {
+		case a[i] == b[j]:
+			res = append(res, domain.DiffLine{Op: domain.DiffOpEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			res = append(res, domain.DiffLine{Op: domain.DiffOpDelete, Text: a[i]})
+			i++
+		default:
+			res = append(res, domain.DiffLine{Op: domain.DiffOpInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < m; i++ {
+		res = append(res, domain.DiffLine{Op: domain.DiffOpDelete, Text: a[i]})
+	}
+	for ; j < n; j++ {
+		res = append(res, domain.DiffLine{Op: domain.DiffOpInsert, Text: b[j]})
+	}
+	return res
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_diffLines(t *testing.T) {
+	testCases := []struct {
+		name string
+		from string
+		to   string
+		want []domain.DiffLine
+	}{
+		{
+			name: "完全一样",
+			from: "a\nb",
+			to:   "a\nb",
+			want: []domain.DiffLine{
+				{Op: domain.DiffOpEqual, Text: "a"},
+				{Op: domain.DiffOpEqual, Text: "b"},
+			},
+		},
+		{
+			name: "从空到有",
+			from: "",
+			to:   "a",
+			want: []domain.DiffLine{
+				{Op: domain.DiffOpInsert, Text: "a"},
+			},
+		},
+		{
+			name: "修改了中间一行",
+			from: "a\nb\nc",
+			to:   "a\nd\nc",
+			want: []domain.DiffLine{
+				{Op: domain.DiffOpEqual, Text: "a"},
+				{Op: domain.DiffOpDelete, Text: "b"},
+				{Op: domain.DiffOpInsert, Text: "d"},
+				{Op: domain.DiffOpEqual, Text: "c"},
+			},
+		},
+		{
+			name: "删掉了最后一行",
+			from: "a\nb",
+			to:   "a",
+			want: []domain.DiffLine{
+				{Op: domain.DiffOpEqual, Text: "a"},
+				{Op: domain.DiffOpDelete, Text: "b"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, diffLines(tc.from, tc.to))
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"bytes"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"strings"
+	"unicode"
+)
+
+const (
+	// 摘要最多多少个字符
+	abstractLen = 128
+	// 每分钟能读多少个字，英文按照单词算
+	wordsPerMinute = 300
+)
+
+var (
+	markdown = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		// 给标题生成 id，目录靠它来跳转
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+	// UGCPolicy 是专门给用户生成的内容准备的，
+	// 会去掉 script、on 开头的事件属性、javascript: 链接之类的东西
+	htmlPolicy = bluemonday.UGCPolicy()
+)
+
+// renderMarkdown 发表的时候调用，一次性算出 HTML、目录、摘要和阅读时间，
+// 读者看文章的时候就不需要再解析一遍了
+func renderMarkdown(content string) (domain.RenderedContent, error) {
+	src := []byte(content)
+	doc := markdown.Parser().Parse(text.NewReader(src))
+
+	var (
+		toc   []domain.TocItem
+		plain strings.Builder
+	)
+	err := ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			// 块之间加个空格，免得两段话粘在一起
+			if n.Type() == ast.TypeBlock {
+				plain.WriteByte(' ')
+			}
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Heading:
+			var anchor string
+			if id, ok := node.AttributeString("id"); ok {
+				if val, ok := id.([]byte); ok {
+					anchor = string(val)
+				}
+			}
+			toc = append(toc, domain.TocItem{
+				Level:  node.Level,
+				Anchor: anchor,
+				Title:  string(node.Text(src)),
+			})
+		case *ast.Text:
+			plain.Write(node.Segment.Value(src))
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				plain.WriteByte(' ')
+			}
+		case *ast.String:
+			plain.Write(node.Value)
+		case *ast.CodeBlock, *ast.FencedCodeBlock, *ast.HTMLBlock, *ast.RawHTML:
+			// 代码和 HTML 不算进摘要
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	if err != nil {
+		return domain.RenderedContent{}, err
+	}
+
+	var buf bytes.Buffer
+	err = markdown.Renderer().Render(&buf, src, doc)
+	if err != nil {
+		return domain.RenderedContent{}, err
+	}
+	// goldmark 默认已经不输出原始 HTML 了，这里再过滤一遍，多一层保险
+	html := htmlPolicy.SanitizeBytes(buf.Bytes())
+	words := strings.Join(strings.Fields(plain.String()), " ")
+	return domain.RenderedContent{
+		HTML:           string(html),
+		Toc:            toc,
+		Abstract:       plainAbstract(words),
+		ReadingMinutes: readingMinutes(words),
+	}, nil
+}
+
+func plainAbstract(words string) string {
+	str := []rune(words)
+	if len(str) > abstractLen {
+		str = str[:abstractLen]
+	}
+	return string(str)
+}
+
+// readingMinutes 中文一个字算一个，英文和数字一个单词算一个。
+// 不足一分钟的按照一分钟算
+func readingMinutes(words string) int {
+	cnt := 0
+	inWord := false
+	for _, r := range words {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			cnt++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				cnt++
+				inWord = true
+			}
+		default:
+			inWord = false
+		}
+	}
+	return (cnt + wordsPerMinute - 1) / wordsPerMinute
+}
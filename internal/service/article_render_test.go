@@ -0,0 +1,60 @@
+package service
+
+import (
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+
+		wantToc      []domain.TocItem
+		wantAbstract string
+		wantMinutes  int
+		// HTML 里面不能出现的内容
+		forbidden []string
+	}{
+		{
+			name:    "空内容",
+			content: "",
+		},
+		{
+			name: "标题、代码和危险内容",
+			content: "# Hello World\n\n这是**第一段**。\n\n" +
+				"```go\nfmt.Println(1)\n```\n\n" +
+				"## Section Two\n\n" +
+				"<script>alert(1)</script>\n\n" +
+				"[link](javascript:alert(1))\n",
+			wantToc: []domain.TocItem{
+				{Level: 1, Anchor: "hello-world", Title: "Hello World"},
+				{Level: 2, Anchor: "section-two", Title: "Section Two"},
+			},
+			wantAbstract: "Hello World 这是第一段。 Section Two link",
+			wantMinutes:  1,
+			forbidden:    []string{"<script", "javascript:"},
+		},
+		{
+			name:         "摘要截断",
+			content:      strings.Repeat("字", 600),
+			wantAbstract: strings.Repeat("字", 128),
+			wantMinutes:  2,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := renderMarkdown(tc.content)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantToc, res.Toc)
+			assert.Equal(t, tc.wantAbstract, res.Abstract)
+			assert.Equal(t, tc.wantMinutes, res.ReadingMinutes)
+			for _, f := range tc.forbidden {
+				assert.NotContains(t, res.HTML, f)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"gitee.com/geekbang/basic-go/webook/internal/events/article"
+	"gitee.com/geekbang/basic-go/webook/internal/repository"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+)
+
+var (
+	// ErrReviewStatusConflict 审核单的状态不允许这个操作，或者不是你认领的
+	ErrReviewStatusConflict = repository.ErrReviewStatusConflict
+	// ErrArticleReviewing 审核中的文章不能修改
+	ErrArticleReviewing = repository.ErrArticleReviewing
+	ErrNotReviewer      = errors.New("没有审核权限")
+	// ErrArticleNotReviewing 文章已经不在审核中了，比如作者在审核的时候撤回了
+	ErrArticleNotReviewing = errors.New("文章不在审核中")
+)
+
+// ReviewConfig 审核相关的配置
+type ReviewConfig struct {
+	// Enabled 为 false 的时候，发表不需要审核，直接同步到线上库
+	Enabled bool
+	// Reviewers 可以审核文章的用户，他们自己的文章也不需要审核
+	Reviewers []int64
+	// TrustedAuthors 这些作者的文章不需要审核
+	TrustedAuthors []int64
+}
+
+//go:generate mockgen -source=./article_review.go -package=svcmocks -destination=./mocks/article_review.mock.go ArticleReviewService
+type ArticleReviewService interface {
+	// NeedReview 这篇文章发表之前要不要审核
+	NeedReview(art domain.Article) bool
+	IsReviewer(uid int64) bool
+	// Submit 把制作库里面的草稿提交审核，uid 要能修改这篇文章
+	Submit(ctx context.Context, uid, aid int64) error
+	// Queue 等待认领的，和 reviewer 自己认领了还没有处理完的
+	Queue(ctx context.Context, reviewer int64, offset, limit int) ([]domain.ArticleReview, error)
+	Claim(ctx context.Context, reviewer, aid int64) error
+	// Approve 审核通过，把提交审核时候的内容同步到线上库
+	Approve(ctx context.Context, reviewer, aid int64, comment string) error
+	// RequestChanges 作者修改之后可以重新提交
+	RequestChanges(ctx context.Context, reviewer, aid int64, comment string) error
+	Reject(ctx context.Context, reviewer, aid int64, comment string) error
+	// Detail 作者、合作者和审核的人可以查看审核单和全部审核记录
+	Detail(ctx context.Context, uid, aid int64) (domain.ArticleReview, error)
+}
+
+type articleReviewService struct {
+	repo      repository.ArticleReviewRepository
+	artRepo   repository.ArticleRepository
+	producer  article.Producer
+	enabled   bool
+	reviewers map[int64]struct{}
+	trusted   map[int64]struct{}
+	l         logger.LoggerV1
+}
+
+func NewArticleReviewService(repo repository.ArticleReviewRepository,
+	artRepo repository.ArticleRepository,
+	producer article.Producer,
+	cfg ReviewConfig,
+	l logger.LoggerV1) ArticleReviewService {
+	reviewers := make(map[int64]struct{}, len(cfg.Reviewers))
+	for _, uid := range cfg.Reviewers {
+		reviewers[uid] = struct{}{}
+	}
+	trusted := make(map[int64]struct{}, len(cfg.TrustedAuthors))
+	for _, uid := range cfg.TrustedAuthors {
+		trusted[uid] = struct{}{}
+	}
+	return &articleReviewService{
+		repo:      repo,
+		artRepo:   artRepo,
+		producer:  producer,
+		enabled:   cfg.Enabled,
+		reviewers: reviewers,
+		trusted:   trusted,
+		l:         l,
+	}
+}
+
+// NeedReview 打开了审核的时候，除了审核的人和信任的作者，其他人的文章都要审核
+func (s *articleReviewService) NeedReview(art domain.Article) bool {
+	if !s.enabled {
+		return false
+	}
+	uid := art.Author.Id
+	if _, ok := s.trusted[uid]; ok {
+		return false
+	}
+	return !s.IsReviewer(uid)
+}
+
+func (s *articleReviewService) IsReviewer(uid int64) bool {
+	_, ok := s.reviewers[uid]
+	return ok
+}
+
+func (s *articleReviewService) Submit(ctx context.Context, uid, aid int64) error {
+	art, err := s.artRepo.GetById(ctx, aid)
+	if err != nil {
+		return err
+	}
+	if !art.CanEdit(uid) {
+		return ErrArticleNoPermission
+	}
+	err = s.repo.Submit(ctx, domain.ArticleReview{
+		ArticleId: aid,
+		AuthorId:  art.Author.Id,
+		Title:     art.Title,
+		Content:   art.Content,
+	}, domain.ReviewSourceStatus(domain.ReviewStatusSubmitted))
+	if err != nil {
+		return err
+	}
+	s.produce(article.ReviewEvent{
+		Aid:      aid,
+		AuthorId: art.Author.Id,
+		Status:   domain.ReviewStatusSubmitted.ToUint8(),
+	})
+	return nil
+}
+
+func (s *articleReviewService) Queue(ctx context.Context, reviewer int64, offset, limit int) ([]domain.ArticleReview, error) {
+	if !s.IsReviewer(reviewer) {
+		return nil, ErrNotReviewer
+	}
+	return s.repo.ListQueue(ctx, reviewer, offset, limit)
+}
+
+func (s *articleReviewService) Claim(ctx context.Context, reviewer, aid int64) error {
+	if !s.IsReviewer(reviewer) {
+		return ErrNotReviewer
+	}
+	err := s.repo.Claim(ctx, aid, reviewer)
+	if err != nil {
+		return err
+	}
+	s.produce(article.ReviewEvent{
+		Aid:        aid,
+		ReviewerId: reviewer,
+		Status:     domain.ReviewStatusInReview.ToUint8(),
+	})
+	return nil
+}
+
+func (s *articleReviewService) Approve(ctx context.Context, reviewer, aid int64, comment string) error {
+	if !s.IsReviewer(reviewer) {
+		return ErrNotReviewer
+	}
+	// 作者在审核的时候撤回了，就不能再发表出去
+	cur, err := s.artRepo.GetById(ctx, aid)
+	if err != nil {
+		return err
+	}
+	if cur.Status != domain.ArticleStatusReviewing {
+		return ErrArticleNotReviewing
+	}
+	r, err := s.transit(ctx, reviewer, aid, domain.ReviewStatusApproved, comment)
+	if err != nil {
+		return err
+	}
+	art := domain.Article{
+		Id:      aid,
+		Title:   r.Title,
+		Content: r.Content,
+		Author: domain.Author{
+			Id: r.AuthorId,
+		},
+		Status:      domain.ArticleStatusPublished,
+		MembersOnly: cur.MembersOnly,
+	}
+	art.Rendered, err = renderMarkdown(art.Content)
+	if err != nil {
+		return err
+	}
+	// 同步失败的话，审核单已经是审核通过了，再审核通过一次就可以重试
+	_, err = s.artRepo.Sync(ctx, art)
+	if err != nil {
+		return err
+	}
+	s.produce(article.ReviewEvent{
+		Aid:         aid,
+		AuthorId:    r.AuthorId,
+		ReviewerId:  reviewer,
+		Status:      domain.ReviewStatusApproved.ToUint8(),
+		Comment:     comment,
+		Title:       r.Title,
+		Content:     r.Content,
+		MembersOnly: cur.MembersOnly,
+		Utime:       r.Utime.UnixMilli(),
+	})
+	return nil
+}
+
+func (s *articleReviewService) RequestChanges(ctx context.Context, reviewer, aid int64, comment string) error {
+	return s.transitAndProduce(ctx, reviewer, aid, domain.ReviewStatusChangesRequested, comment)
+}
+
+func (s *articleReviewService) Reject(ctx context.Context, reviewer, aid int64, comment string) error {
+	return s.transitAndProduce(ctx, reviewer, aid, domain.ReviewStatusRejected, comment)
+}
+
+func (s *articleReviewService) Detail(ctx context.Context, uid, aid int64) (domain.ArticleReview, error) {
+	if !s.IsReviewer(uid) {
+		art, err := s.artRepo.GetById(ctx, aid)
+		if err != nil {
+			return domain.ArticleReview{}, err
+		}
+		if !art.CanView(uid) {
+			return domain.ArticleReview{}, ErrArticleNoPermission
+		}
+	}
+	return s.repo.GetByArticle(ctx, aid)
+}
+
+func (s *articleReviewService) transitAndProduce(ctx context.Context,
+	reviewer, aid int64, to domain.ReviewStatus, comment string) error {
+	r, err := s.transit(ctx, reviewer, aid, to, comment)
+	if err != nil {
+		return err
+	}
+	s.produce(article.ReviewEvent{
+		Aid:        aid,
+		AuthorId:   r.AuthorId,
+		ReviewerId: reviewer,
+		Status:     to.ToUint8(),
+		Comment:    comment,
+		Utime:      r.Utime.UnixMilli(),
+	})
+	return nil
+}
+
+func (s *articleReviewService) transit(ctx context.Context,
+	reviewer, aid int64, to domain.ReviewStatus, comment string) (domain.ArticleReview, error) {
+	if !s.IsReviewer(reviewer) {
+		return domain.ArticleReview{}, ErrNotReviewer
+	}
+	return s.repo.Transit(ctx, aid, reviewer, domain.ReviewSourceStatus(to), to, comment)
+}
+
+// produce 审核单已经变更成功了，消息发送失败只记录日志
+func (s *articleReviewService) produce(evt article.ReviewEvent) {
+	err := s.producer.ProduceReviewEvent(evt)
+	if err != nil {
+		s.l.Error("发送 ReviewEvent 失败",
+			logger.Int64("aid", evt.Aid),
+			logger.Int64("status", int64(evt.Status)),
+			logger.Error(err))
+	}
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"gitee.com/geekbang/basic-go/webook/internal/events/article"
+	evtmocks "gitee.com/geekbang/basic-go/webook/internal/events/article/mocks"
+	"gitee.com/geekbang/basic-go/webook/internal/repository"
+	repomocks "gitee.com/geekbang/basic-go/webook/internal/repository/mocks"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func Test_articleReviewService_Approve(t *testing.T) {
+	approved := domain.ArticleReview{
+		ArticleId:  1,
+		AuthorId:   123,
+		ReviewerId: 9,
+		Status:     domain.ReviewStatusApproved,
+		Title:      "提交时候的标题",
+		Content:    "提交时候的内容",
+		Utime:      time.UnixMilli(100),
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.ArticleReviewRepository,
+			repository.ArticleRepository, article.Producer)
+
+		reviewer int64
+		wantErr  error
+	}{
+		{
+			name: "审核通过，同步快照到线上库",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleReviewRepository,
+				repository.ArticleRepository, article.Producer) {
+				repo := repomocks.NewMockArticleReviewRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				producer := evtmocks.NewMockProducer(ctrl)
+				artRepo.EXPECT().GetById(gomock.Any(), int64(1)).Return(domain.Article{
+					Id:          1,
+					Status:      domain.ArticleStatusReviewing,
+					MembersOnly: true,
+				}, nil)
+				repo.EXPECT().Transit(gomock.Any(), int64(1), int64(9),
+					[]domain.ReviewStatus{domain.ReviewStatusInReview, domain.ReviewStatusApproved},
+					domain.ReviewStatusApproved, "很好").Return(approved, nil)
+				artRepo.EXPECT().Sync(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, art domain.Article) (int64, error) {
+						assert.Equal(t, int64(123), art.Author.Id)
+						assert.Equal(t, "提交时候的标题", art.Title)
+						assert.Equal(t, domain.ArticleStatusPublished, art.Status)
+						assert.True(t, art.MembersOnly)
+						assert.Contains(t, art.Rendered.HTML, "提交时候的内容")
+						return 1, nil
+					})
+				// 内容交给 producer 去掉
+				producer.EXPECT().ProduceReviewEvent(article.ReviewEvent{
+					Aid:         1,
+					AuthorId:    123,
+					ReviewerId:  9,
+					Status:      domain.ReviewStatusApproved.ToUint8(),
+					Comment:     "很好",
+					Title:       "提交时候的标题",
+					Content:     "提交时候的内容",
+					MembersOnly: true,
+					Utime:       100,
+				}).Return(nil)
+				return repo, artRepo, producer
+			},
+			reviewer: 9,
+		},
+		{
+			name: "不是审核的人",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleReviewRepository,
+				repository.ArticleRepository, article.Producer) {
+				return repomocks.NewMockArticleReviewRepository(ctrl),
+					repomocks.NewMockArticleRepository(ctrl),
+					evtmocks.NewMockProducer(ctrl)
+			},
+			reviewer: 10,
+			wantErr:  ErrNotReviewer,
+		},
+		{
+			name: "状态不对",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleReviewRepository,
+				repository.ArticleRepository, article.Producer) {
+				repo := repomocks.NewMockArticleReviewRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetById(gomock.Any(), int64(1)).Return(domain.Article{
+					Id:     1,
+					Status: domain.ArticleStatusReviewing,
+				}, nil)
+				repo.EXPECT().Transit(gomock.Any(), int64(1), int64(9),
+					gomock.Any(), domain.ReviewStatusApproved, "很好").
+					Return(domain.ArticleReview{}, repository.ErrReviewStatusConflict)
+				return repo, artRepo, evtmocks.NewMockProducer(ctrl)
+			},
+			reviewer: 9,
+			wantErr:  ErrReviewStatusConflict,
+		},
+		{
+			name: "同步线上库失败，不发消息",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleReviewRepository,
+				repository.ArticleRepository, article.Producer) {
+				repo := repomocks.NewMockArticleReviewRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetById(gomock.Any(), int64(1)).Return(domain.Article{
+					Id:     1,
+					Status: domain.ArticleStatusReviewing,
+				}, nil)
+				repo.EXPECT().Transit(gomock.Any(), int64(1), int64(9),
+					gomock.Any(), domain.ReviewStatusApproved, "很好").Return(approved, nil)
+				artRepo.EXPECT().Sync(gomock.Any(), gomock.Any()).
+					Return(int64(0), errors.New("mock db error"))
+				return repo, artRepo, evtmocks.NewMockProducer(ctrl)
+			},
+			reviewer: 9,
+			wantErr:  errors.New("mock db error"),
+		},
+		{
+			name: "作者在审核的时候撤回了，不能发表",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleReviewRepository,
+				repository.ArticleRepository, article.Producer) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetById(gomock.Any(), int64(1)).Return(domain.Article{
+					Id:     1,
+					Status: domain.ArticleStatusPrivate,
+				}, nil)
+				return repomocks.NewMockArticleReviewRepository(ctrl), artRepo,
+					evtmocks.NewMockProducer(ctrl)
+			},
+			reviewer: 9,
+			wantErr:  ErrArticleNotReviewing,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, artRepo, producer := tc.mock(ctrl)
+			svc := NewArticleReviewService(repo, artRepo, producer, ReviewConfig{
+				Enabled:   true,
+				Reviewers: []int64{9},
+			}, logger.NewNopLogger())
+			err := svc.Approve(context.Background(), tc.reviewer, 1, "很好")
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_articleReviewService_Submit(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.ArticleReviewRepository,
+			repository.ArticleRepository, article.Producer)
+
+		uid     int64
+		wantErr error
+	}{
+		{
+			name: "合作的编辑提交，审核单记录的是创作者",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleReviewRepository,
+				repository.ArticleRepository, article.Producer) {
+				repo := repomocks.NewMockArticleReviewRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				producer := evtmocks.NewMockProducer(ctrl)
+				artRepo.EXPECT().GetById(gomock.Any(), int64(1)).Return(domain.Article{
+					Id:      1,
+					Title:   "我的标题",
+					Content: "我的内容",
+					Author:  domain.Author{Id: 123},
+					Collaborators: []domain.ArticleCollaborator{
+						{
+							User:   domain.Author{Id: 456},
+							Role:   domain.CollaboratorRoleEditor,
+							Status: domain.CollaboratorStatusAccepted,
+						},
+					},
+				}, nil)
+				repo.EXPECT().Submit(gomock.Any(), domain.ArticleReview{
+					ArticleId: 1,
+					AuthorId:  123,
+					Title:     "我的标题",
+					Content:   "我的内容",
+				}, domain.ReviewSourceStatus(domain.ReviewStatusSubmitted)).Return(nil)
+				producer.EXPECT().ProduceReviewEvent(article.ReviewEvent{
+					Aid:      1,
+					AuthorId: 123,
+					Status:   domain.ReviewStatusSubmitted.ToUint8(),
+				}).Return(errors.New("消息发送失败不影响提交"))
+				return repo, artRepo, producer
+			},
+			uid: 456,
+		},
+		{
+			name: "审阅的人不能提交",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleReviewRepository,
+				repository.ArticleRepository, article.Producer) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetById(gomock.Any(), int64(1)).Return(domain.Article{
+					Id:     1,
+					Author: domain.Author{Id: 123},
+					Collaborators: []domain.ArticleCollaborator{
+						{
+							User:   domain.Author{Id: 789},
+							Role:   domain.CollaboratorRoleReviewer,
+							Status: domain.CollaboratorStatusAccepted,
+						},
+					},
+				}, nil)
+				return repomocks.NewMockArticleReviewRepository(ctrl), artRepo,
+					evtmocks.NewMockProducer(ctrl)
+			},
+			uid:     789,
+			wantErr: ErrArticleNoPermission,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, artRepo, producer := tc.mock(ctrl)
+			svc := NewArticleReviewService(repo, artRepo, producer, ReviewConfig{
+				Enabled: true,
+			}, logger.NewNopLogger())
+			err := svc.Submit(context.Background(), tc.uid, 1)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_articleReviewService_NeedReview(t *testing.T) {
+	svc := NewArticleReviewService(nil, nil, nil, ReviewConfig{
+		Enabled:        true,
+		Reviewers:      []int64{9},
+		TrustedAuthors: []int64{7},
+	}, logger.NewNopLogger())
+	testCases := []struct {
+		name   string
+		author int64
+		want   bool
+	}{
+		{name: "普通作者", author: 123, want: true},
+		{name: "信任的作者", author: 7},
+		{name: "审核的人", author: 9},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := svc.NeedReview(domain.Article{Author: domain.Author{Id: tc.author}})
+			assert.Equal(t, tc.want, got)
+		})
+	}
+	closed := NewArticleReviewService(nil, nil, nil, ReviewConfig{}, logger.NewNopLogger())
+	assert.False(t, closed.NeedReview(domain.Article{Author: domain.Author{Id: 123}}))
+}
@@ -3,12 +3,14 @@ package service
 import (
 	"context"
 	"errors"
+	rewardv1 "gitee.com/geekbang/basic-go/webook/api/proto/gen/reward/v1"
 	"gitee.com/geekbang/basic-go/webook/internal/domain"
 	"gitee.com/geekbang/basic-go/webook/internal/repository"
 	repomocks "gitee.com/geekbang/basic-go/webook/internal/repository/mocks"
 	"gitee.com/geekbang/basic-go/webook/pkg/logger"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
 	"testing"
 )
 
@@ -214,3 +216,166 @@ func Test_articleService_Publish(t *testing.T) {
 		})
 	}
 }
+
+func Test_articleService_InviteCollaborator(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.ArticleRepository
+
+		invitee int64
+		role    domain.CollaboratorRole
+		wantErr error
+	}{
+		{
+			name: "邀请成功",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().InviteCollaborator(gomock.Any(), domain.ArticleCollaborator{
+					ArticleId: 1,
+					User:      domain.Author{Id: 456},
+					Role:      domain.CollaboratorRoleEditor,
+					Status:    domain.CollaboratorStatusInvited,
+					InviterId: 123,
+				}).Return(nil)
+				return repo
+			},
+			invitee: 456,
+			role:    domain.CollaboratorRoleEditor,
+		},
+		{
+			name: "非法角色",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				return repomocks.NewMockArticleRepository(ctrl)
+			},
+			invitee: 456,
+			role:    domain.CollaboratorRoleUnknown,
+			wantErr: ErrIllegalCollaborator,
+		},
+		{
+			name: "邀请自己",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				return repomocks.NewMockArticleRepository(ctrl)
+			},
+			invitee: 123,
+			role:    domain.CollaboratorRoleReviewer,
+			wantErr: ErrIllegalCollaborator,
+		},
+		{
+			name: "不是创作者",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().InviteCollaborator(gomock.Any(), gomock.Any()).
+					Return(repository.ErrArticleNoPermission)
+				return repo
+			},
+			invitee: 456,
+			role:    domain.CollaboratorRoleReviewer,
+			wantErr: ErrArticleNoPermission,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewArticleService(tc.mock(ctrl), nil, nil, nil, nil, logger.NewNopLogger())
+			err := svc.InviteCollaborator(context.Background(), 123, 1, tc.invitee, tc.role)
+			assert.True(t, errors.Is(err, tc.wantErr))
+		})
+	}
+}
+
+func Test_articleService_checkMembersOnly(t *testing.T) {
+	membersOnly := domain.Article{Id: 1, Author: domain.Author{Id: 123}, MembersOnly: true,
+		Collaborators: []domain.ArticleCollaborator{
+			{User: domain.Author{Id: 456}, Status: domain.CollaboratorStatusAccepted},
+			{User: domain.Author{Id: 789}, Status: domain.CollaboratorStatusInvited},
+		}}
+	testCases := []struct {
+		name      string
+		rewardSvc *fakeRewardClient
+		art       domain.Article
+		uid       int64
+
+		wantChecked bool
+		wantErr     error
+	}{
+		{
+			name:      "不是会员专享",
+			rewardSvc: &fakeRewardClient{},
+			art:       domain.Article{Id: 1, Author: domain.Author{Id: 123}},
+			uid:       1024,
+		},
+		{
+			name:      "作者本人",
+			rewardSvc: &fakeRewardClient{},
+			art:       membersOnly,
+			uid:       123,
+		},
+		{
+			name:      "合作者",
+			rewardSvc: &fakeRewardClient{},
+			art:       membersOnly,
+			uid:       456,
+		},
+		{
+			name:        "还没接受邀请的合作者要是会员",
+			rewardSvc:   &fakeRewardClient{},
+			art:         membersOnly,
+			uid:         789,
+			wantChecked: true,
+			wantErr:     ErrMembersOnly,
+		},
+		{
+			name:        "会员",
+			rewardSvc:   &fakeRewardClient{entitled: true},
+			art:         membersOnly,
+			uid:         1024,
+			wantChecked: true,
+		},
+		{
+			name:        "不是会员",
+			rewardSvc:   &fakeRewardClient{},
+			art:         membersOnly,
+			uid:         1024,
+			wantChecked: true,
+			wantErr:     ErrMembersOnly,
+		},
+		{
+			// 查不到就不给看，不能因为打赏服务出问题就把内容放出去
+			name:        "查询失败",
+			rewardSvc:   &fakeRewardClient{err: errors.New("mock error")},
+			art:         membersOnly,
+			uid:         1024,
+			wantChecked: true,
+			wantErr:     errors.New("mock error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &articleService{rewardSvc: tc.rewardSvc}
+			err := svc.checkMembersOnly(context.Background(), tc.art, tc.uid)
+			assert.Equal(t, tc.wantErr, err)
+			var want *rewardv1.CheckEntitlementRequest
+			if tc.wantChecked {
+				want = &rewardv1.CheckEntitlementRequest{Uid: tc.uid, CreatorUid: 123}
+			}
+			assert.Equal(t, want, tc.rewardSvc.req)
+		})
+	}
+}
+
+type fakeRewardClient struct {
+	rewardv1.RewardServiceClient
+	entitled bool
+	err      error
+	req      *rewardv1.CheckEntitlementRequest
+}
+
+func (f *fakeRewardClient) CheckEntitlement(ctx context.Context,
+	in *rewardv1.CheckEntitlementRequest, opts ...grpc.CallOption) (*rewardv1.CheckEntitlementResponse, error) {
+	f.req = in
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &rewardv1.CheckEntitlementResponse{Entitled: f.entitled}, nil
+}
@@ -2,17 +2,62 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"gitee.com/geekbang/basic-go/webook/internal/domain"
 	"gitee.com/geekbang/basic-go/webook/internal/repository"
 	"gitee.com/geekbang/basic-go/webook/pkg/logger"
 	"time"
 )
 
+var (
+	ErrJobStatusConflict     = repository.ErrJobStatusConflict
+	ErrDuplicateJob          = repository.ErrDuplicateJob
+	ErrJobNotFound           = repository.ErrJobNotFound
+	ErrInvalidCronExpression = errors.New("Cron 表达式不对")
+	ErrJobDependencyCycle    = errors.New("任务的依赖关系形成了环")
+)
+
+//go:generate mockgen -source=./job.go -package=svcmocks -destination=./mocks/job.mock.go CronJobService
 type CronJobService interface {
-	Preempt(ctx context.Context) (domain.Job, error)
+	// AddJob 注册一个任务，已经注册过的同名任务会按照 j 更新配置
+	AddJob(ctx context.Context, j domain.Job) error
+	// Preempt n 是当前节点和它现在的负载，只会抢这个节点能执行的任务。
+	// 上游失败就跳过的任务，抢到之后发现上游没有成功，就直接跳过这个逻辑日期，再继续抢
+	Preempt(ctx context.Context, n domain.JobNode) (domain.Job, error)
+	// Heartbeat 节点定时上报负载
+	Heartbeat(ctx context.Context, n domain.JobNode) error
+	// ResetNextTime 执行成功之后调用
 	ResetNextTime(ctx context.Context, j domain.Job) error
+	// Fail 重试之后还是失败，下次按时再调度。
+	// 连续失败太多次的，进入 dead 状态
+	Fail(ctx context.Context, j domain.Job) error
+	// RecordRun 记录一次执行，包括重试
+	RecordRun(ctx context.Context, r domain.JobRun) error
 	//Release(ctx context.Context, job domain.Job) error
-	// 暴露 job 的增删改查方法
+
+	// 下面是给管理员用的
+
+	// Create 表达式不对的时候返回 ErrInvalidCronExpression
+	Create(ctx context.Context, j domain.Job) (int64, error)
+	// Update 修改配置，下一次执行时间按照新的表达式计算
+	Update(ctx context.Context, j domain.Job) error
+	GetById(ctx context.Context, jid int64) (domain.Job, error)
+	List(ctx context.Context, offset, limit int) ([]domain.Job, error)
+	ListByStatus(ctx context.Context, status domain.JobStatus, offset, limit int) ([]domain.Job, error)
+	ListRuns(ctx context.Context, jid int64, offset, limit int) ([]domain.JobRun, error)
+	Pause(ctx context.Context, jid int64) error
+	// Resume 恢复调度暂停的或者 dead 状态的任务
+	Resume(ctx context.Context, jid int64) error
+	// Trigger 马上执行一次，之后还是按照表达式调度。
+	// date 是要补跑的逻辑日期，为空就是下一次调度的逻辑日期
+	Trigger(ctx context.Context, jid int64, date string) error
+	Delete(ctx context.Context, jid int64) error
+	// SetUpstreams 覆盖任务的全部上游，会形成环的返回 ErrJobDependencyCycle
+	SetUpstreams(ctx context.Context, jid int64, upstreams []int64) error
+	// RunGraph 某个逻辑日期里面，有依赖关系的任务的执行情况
+	RunGraph(ctx context.Context, date string) (domain.JobRunGraph, error)
+	ListNodes(ctx context.Context) ([]domain.JobNode, error)
 }
 
 type cronJobService struct {
@@ -27,40 +72,101 @@ func NewCronJobService(repo repository.CronJobRepository, l logger.LoggerV1) Cro
 		refreshInterval: time.Minute}
 }
 
-func (c *cronJobService) Preempt(ctx context.Context) (domain.Job, error) {
-	j, err := c.repo.Preempt(ctx)
+func (c *cronJobService) AddJob(ctx context.Context, j domain.Job) error {
+	return c.repo.AddJob(ctx, j)
+}
+
+func (c *cronJobService) Preempt(ctx context.Context, n domain.JobNode) (domain.Job, error) {
+	j, err := c.preemptRunnable(ctx, n)
 	if err != nil {
 		return domain.Job{}, err
 	}
 	ticker := time.NewTicker(c.refreshInterval)
 	go func() {
 		for range ticker.C {
-			c.refresh(j.Id)
+			c.refresh(j.Id, j.Owner)
 		}
 	}()
 	j.CancelFunc = func() {
 		ticker.Stop()
-		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-		defer cancel()
-		err := c.repo.Release(ctx, j.Id)
-		if err != nil {
-			c.l.Error("释放 job 失败",
-				logger.Error(err),
-				logger.Int64("jib", j.Id))
-		}
+		c.release(j.Id, j.Owner)
 	}
 	return j, err
 }
+func (c *cronJobService) Heartbeat(ctx context.Context, n domain.JobNode) error {
+	return c.repo.Heartbeat(ctx, n)
+}
+
+func (c *cronJobService) ListNodes(ctx context.Context) ([]domain.JobNode, error) {
+	return c.repo.ListNodes(ctx)
+}
+
 func (c *cronJobService) ResetNextTime(ctx context.Context, j domain.Job) error {
 	nextTime := j.NextTime()
-	return c.repo.UpdateNextTime(ctx, j.Id, nextTime)
+	return c.repo.UpdateNextTime(ctx, j.Id, j.Owner, nextTime, j.LogicalDate)
+}
+
+func (c *cronJobService) Fail(ctx context.Context, j domain.Job) error {
+	return c.repo.IncrFailures(ctx, j.Id, j.Owner, j.NextTime(), j.MaxFailures, j.LogicalDate)
+}
+
+func (c *cronJobService) RecordRun(ctx context.Context, r domain.JobRun) error {
+	return c.repo.AddRun(ctx, r)
+}
+
+func (c *cronJobService) Create(ctx context.Context, j domain.Job) (int64, error) {
+	if err := j.ValidateExpression(); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidCronExpression, err)
+	}
+	return c.repo.Create(ctx, j)
+}
+
+func (c *cronJobService) Update(ctx context.Context, j domain.Job) error {
+	if err := j.ValidateExpression(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCronExpression, err)
+	}
+	return c.repo.Update(ctx, j)
+}
+
+func (c *cronJobService) GetById(ctx context.Context, jid int64) (domain.Job, error) {
+	return c.repo.GetById(ctx, jid)
+}
+
+func (c *cronJobService) List(ctx context.Context, offset, limit int) ([]domain.Job, error) {
+	return c.repo.List(ctx, offset, limit)
+}
+
+func (c *cronJobService) ListByStatus(ctx context.Context, status domain.JobStatus,
+	offset, limit int) ([]domain.Job, error) {
+	return c.repo.ListByStatus(ctx, status, offset, limit)
+}
+
+func (c *cronJobService) ListRuns(ctx context.Context, jid int64, offset, limit int) ([]domain.JobRun, error) {
+	return c.repo.ListRuns(ctx, jid, offset, limit)
+}
+
+func (c *cronJobService) Pause(ctx context.Context, jid int64) error {
+	return c.repo.Pause(ctx, jid)
+}
+
+func (c *cronJobService) Resume(ctx context.Context, jid int64) error {
+	// 恢复之后马上就可以被抢占执行
+	return c.repo.Resume(ctx, jid, time.Now())
+}
+
+func (c *cronJobService) Trigger(ctx context.Context, jid int64, date string) error {
+	return c.repo.Trigger(ctx, jid, date)
+}
+
+func (c *cronJobService) Delete(ctx context.Context, jid int64) error {
+	return c.repo.Delete(ctx, jid)
 }
 
-func (c *cronJobService) refresh(id int64) {
+func (c *cronJobService) refresh(id int64, owner string) {
 	// 本质上就是更新一下更新时间
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
-	err := c.repo.UpdateUtime(ctx, id)
+	err := c.repo.UpdateUtime(ctx, id, owner)
 	if err != nil {
 		c.l.Error("续约失败", logger.Error(err),
 			logger.Int64("jid", id))
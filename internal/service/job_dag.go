@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"time"
+)
+
+// preemptRunnable 数据库只保证了上游都有结果，
+// 上游失败就跳过的任务，还要在这里看一下上游是不是都成功了
+func (c *cronJobService) preemptRunnable(ctx context.Context, n domain.JobNode) (domain.Job, error) {
+	for {
+		j, err := c.repo.Preempt(ctx, n)
+		if err != nil {
+			return domain.Job{}, err
+		}
+		if j.UpstreamPolicy != domain.JobUpstreamPolicySkip {
+			return j, nil
+		}
+		failed, err := c.repo.HasUnsuccessfulUpstream(ctx, j.Id, j.LogicalDate)
+		if err != nil {
+			c.release(j.Id, j.Owner)
+			return domain.Job{}, err
+		}
+		if !failed {
+			return j, nil
+		}
+		// 跳过之后，这个任务在这个逻辑日期也有了结果，下游也会跟着跳过或者等待
+		err = c.repo.Skip(ctx, j.Id, j.Owner, j.NextTime(), j.LogicalDate)
+		if err != nil {
+			c.release(j.Id, j.Owner)
+			return domain.Job{}, err
+		}
+		c.l.Info("上游执行失败，跳过任务",
+			logger.Int64("jid", j.Id),
+			logger.String("date", j.LogicalDate))
+	}
+}
+
+func (c *cronJobService) release(jid int64, owner string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := c.repo.Release(ctx, jid, owner)
+	if err != nil {
+		c.l.Error("释放 job 失败",
+			logger.Error(err),
+			logger.Int64("jid", jid))
+	}
+}
+
+func (c *cronJobService) SetUpstreams(ctx context.Context, jid int64, upstreams []int64) error {
+	upstreams = dedupIds(upstreams)
+	if len(upstreams) > 0 {
+		js, err := c.repo.ListByIds(ctx, upstreams)
+		if err != nil {
+			return err
+		}
+		if len(js) != len(upstreams) {
+			return ErrJobNotFound
+		}
+	}
+	deps, err := c.repo.ListDependencies(ctx)
+	if err != nil {
+		return err
+	}
+	graph := make(map[int64][]int64, len(deps))
+	for _, dep := range deps {
+		if dep.JobId == jid {
+			continue
+		}
+		graph[dep.JobId] = append(graph[dep.JobId], dep.UpstreamId)
+	}
+	graph[jid] = upstreams
+	if hasCycle(graph, jid) {
+		return ErrJobDependencyCycle
+	}
+	return c.repo.SetUpstreams(ctx, jid, upstreams)
+}
+
+// hasCycle 沿着上游一直往上找，能够回到 start 就是有环。
+// 修改之前的依赖关系是没有环的，所以只需要从 start 开始找
+func hasCycle(graph map[int64][]int64, start int64) bool {
+	visited := make(map[int64]bool, len(graph))
+	var dfs func(id int64) bool
+	dfs = func(id int64) bool {
+		for _, up := range graph[id] {
+			if up == start {
+				return true
+			}
+			if visited[up] {
+				continue
+			}
+			visited[up] = true
+			if dfs(up) {
+				return true
+			}
+		}
+		return false
+	}
+	return dfs(start)
+}
+
+func dedupIds(ids []int64) []int64 {
+	seen := make(map[int64]struct{}, len(ids))
+	res := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		res = append(res, id)
+	}
+	return res
+}
+
+func (c *cronJobService) RunGraph(ctx context.Context, date string) (domain.JobRunGraph, error) {
+	graph := domain.JobRunGraph{LogicalDate: date}
+	deps, err := c.repo.ListDependencies(ctx)
+	if err != nil {
+		return graph, err
+	}
+	if len(deps) == 0 {
+		return graph, nil
+	}
+	ids := make([]int64, 0, len(deps)*2)
+	for _, dep := range deps {
+		ids = append(ids, dep.JobId, dep.UpstreamId)
+	}
+	js, err := c.repo.ListByIds(ctx, dedupIds(ids))
+	if err != nil {
+		return graph, err
+	}
+	ins, err := c.repo.ListInstances(ctx, date)
+	if err != nil {
+		return graph, err
+	}
+	status := make(map[int64]domain.JobInstanceStatus, len(ins))
+	for _, in := range ins {
+		status[in.JobId] = in.Status
+	}
+	graph.Edges = deps
+	graph.Nodes = make([]domain.JobRunNode, 0, len(js))
+	for _, j := range js {
+		graph.Nodes = append(graph.Nodes, domain.JobRunNode{
+			Job:    j,
+			Status: status[j.Id],
+		})
+	}
+	return graph, nil
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"gitee.com/geekbang/basic-go/webook/internal/repository"
+	repomocks "gitee.com/geekbang/basic-go/webook/internal/repository/mocks"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func Test_cronJobService_SetUpstreams(t *testing.T) {
+	// 1 <- 2 <- 3，也就是 3 依赖 2，2 依赖 1
+	chain := []domain.JobDependency{
+		{JobId: 2, UpstreamId: 1},
+		{JobId: 3, UpstreamId: 2},
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.CronJobRepository
+
+		jid       int64
+		upstreams []int64
+		wantErr   error
+	}{
+		{
+			name: "扇入，重复的上游去掉",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().ListByIds(gomock.Any(), []int64{1, 2}).
+					Return([]domain.Job{{Id: 1}, {Id: 2}}, nil)
+				repo.EXPECT().ListDependencies(gomock.Any()).Return(chain, nil)
+				repo.EXPECT().SetUpstreams(gomock.Any(), int64(4), []int64{1, 2}).Return(nil)
+				return repo
+			},
+			jid:       4,
+			upstreams: []int64{1, 2, 1},
+		},
+		{
+			name: "形成环",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().ListByIds(gomock.Any(), []int64{3}).
+					Return([]domain.Job{{Id: 3}}, nil)
+				repo.EXPECT().ListDependencies(gomock.Any()).Return(chain, nil)
+				return repo
+			},
+			jid:       1,
+			upstreams: []int64{3},
+			wantErr:   ErrJobDependencyCycle,
+		},
+		{
+			name: "依赖自己",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().ListByIds(gomock.Any(), []int64{2}).
+					Return([]domain.Job{{Id: 2}}, nil)
+				repo.EXPECT().ListDependencies(gomock.Any()).Return(chain, nil)
+				return repo
+			},
+			jid:       2,
+			upstreams: []int64{2},
+			wantErr:   ErrJobDependencyCycle,
+		},
+		{
+			name: "替换掉原本的上游",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().ListByIds(gomock.Any(), []int64{1}).
+					Return([]domain.Job{{Id: 1}}, nil)
+				repo.EXPECT().ListDependencies(gomock.Any()).Return(chain, nil)
+				repo.EXPECT().SetUpstreams(gomock.Any(), int64(3), []int64{1}).Return(nil)
+				return repo
+			},
+			jid:       3,
+			upstreams: []int64{1},
+		},
+		{
+			name: "上游不存在",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().ListByIds(gomock.Any(), []int64{5}).
+					Return([]domain.Job{}, nil)
+				return repo
+			},
+			jid:       4,
+			upstreams: []int64{5},
+			wantErr:   ErrJobNotFound,
+		},
+		{
+			name: "查询依赖关系失败",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().ListDependencies(gomock.Any()).
+					Return(nil, errors.New("mock db error"))
+				return repo
+			},
+			jid:     4,
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewCronJobService(tc.mock(ctrl), logger.NewNopLogger())
+			err := svc.SetUpstreams(context.Background(), tc.jid, tc.upstreams)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_cronJobService_preemptRunnable(t *testing.T) {
+	node := domain.JobNode{Name: "node-1"}
+	skipJob := domain.Job{Id: 2, Expression: "0 10 0 * * ?", LogicalDate: "20231115", Owner: "node-1",
+		UpstreamPolicy: domain.JobUpstreamPolicySkip}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.CronJobRepository
+
+		wantJob domain.Job
+		wantErr error
+	}{
+		{
+			name: "上游失败就阻塞的任务，不用再检查上游",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().Preempt(gomock.Any(), node).
+					Return(domain.Job{Id: 1, LogicalDate: "20231115"}, nil)
+				return repo
+			},
+			wantJob: domain.Job{Id: 1, LogicalDate: "20231115"},
+		},
+		{
+			name: "上游都成功了",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().Preempt(gomock.Any(), node).Return(skipJob, nil)
+				repo.EXPECT().HasUnsuccessfulUpstream(gomock.Any(), int64(2), "20231115").
+					Return(false, nil)
+				return repo
+			},
+			wantJob: skipJob,
+		},
+		{
+			name: "上游失败，跳过之后抢下一个任务",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				first := repo.EXPECT().Preempt(gomock.Any(), node).Return(skipJob, nil)
+				repo.EXPECT().HasUnsuccessfulUpstream(gomock.Any(), int64(2), "20231115").
+					Return(true, nil)
+				repo.EXPECT().Skip(gomock.Any(), int64(2), "node-1", gomock.Any(), "20231115").Return(nil)
+				repo.EXPECT().Preempt(gomock.Any(), node).After(first).
+					Return(domain.Job{Id: 3}, nil)
+				return repo
+			},
+			wantJob: domain.Job{Id: 3},
+		},
+		{
+			name: "检查上游失败，释放任务",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().Preempt(gomock.Any(), node).Return(skipJob, nil)
+				repo.EXPECT().HasUnsuccessfulUpstream(gomock.Any(), int64(2), "20231115").
+					Return(false, errors.New("mock db error"))
+				repo.EXPECT().Release(gomock.Any(), int64(2), "node-1").Return(nil)
+				return repo
+			},
+			wantErr: errors.New("mock db error"),
+		},
+		{
+			name: "跳过失败，释放任务",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().Preempt(gomock.Any(), node).Return(skipJob, nil)
+				repo.EXPECT().HasUnsuccessfulUpstream(gomock.Any(), int64(2), "20231115").
+					Return(true, nil)
+				repo.EXPECT().Skip(gomock.Any(), int64(2), "node-1", gomock.Any(), "20231115").
+					Return(errors.New("mock db error"))
+				repo.EXPECT().Release(gomock.Any(), int64(2), "node-1").Return(nil)
+				return repo
+			},
+			wantErr: errors.New("mock db error"),
+		},
+		{
+			name: "抢占失败",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().Preempt(gomock.Any(), node).
+					Return(domain.Job{}, errors.New("mock db error"))
+				return repo
+			},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewCronJobService(tc.mock(ctrl), logger.NewNopLogger()).(*cronJobService)
+			j, err := svc.preemptRunnable(context.Background(), node)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantJob, j)
+		})
+	}
+}
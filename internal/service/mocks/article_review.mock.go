@@ -0,0 +1,168 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./article_review.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./article_review.go -package=svcmocks -destination=./mocks/article_review.mock.go ArticleReviewService
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "gitee.com/geekbang/basic-go/webook/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArticleReviewService is a mock of ArticleReviewService interface.
+type MockArticleReviewService struct {
+	ctrl     *gomock.Controller
+	recorder *MockArticleReviewServiceMockRecorder
+}
+
+// MockArticleReviewServiceMockRecorder is the mock recorder for MockArticleReviewService.
+type MockArticleReviewServiceMockRecorder struct {
+	mock *MockArticleReviewService
+}
+
+// NewMockArticleReviewService creates a new mock instance.
+func NewMockArticleReviewService(ctrl *gomock.Controller) *MockArticleReviewService {
+	mock := &MockArticleReviewService{ctrl: ctrl}
+	mock.recorder = &MockArticleReviewServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArticleReviewService) EXPECT() *MockArticleReviewServiceMockRecorder {
+	return m.recorder
+}
+
+// Approve mocks base method.
+func (m *MockArticleReviewService) Approve(ctx context.Context, reviewer, aid int64, comment string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Approve", ctx, reviewer, aid, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Approve indicates an expected call of Approve.
+func (mr *MockArticleReviewServiceMockRecorder) Approve(ctx, reviewer, aid, comment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Approve", reflect.TypeOf((*MockArticleReviewService)(nil).Approve), ctx, reviewer, aid, comment)
+}
+
+// Claim mocks base method.
+func (m *MockArticleReviewService) Claim(ctx context.Context, reviewer, aid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, reviewer, aid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockArticleReviewServiceMockRecorder) Claim(ctx, reviewer, aid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockArticleReviewService)(nil).Claim), ctx, reviewer, aid)
+}
+
+// Detail mocks base method.
+func (m *MockArticleReviewService) Detail(ctx context.Context, uid, aid int64) (domain.ArticleReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Detail", ctx, uid, aid)
+	ret0, _ := ret[0].(domain.ArticleReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Detail indicates an expected call of Detail.
+func (mr *MockArticleReviewServiceMockRecorder) Detail(ctx, uid, aid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Detail", reflect.TypeOf((*MockArticleReviewService)(nil).Detail), ctx, uid, aid)
+}
+
+// IsReviewer mocks base method.
+func (m *MockArticleReviewService) IsReviewer(uid int64) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsReviewer", uid)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsReviewer indicates an expected call of IsReviewer.
+func (mr *MockArticleReviewServiceMockRecorder) IsReviewer(uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsReviewer", reflect.TypeOf((*MockArticleReviewService)(nil).IsReviewer), uid)
+}
+
+// NeedReview mocks base method.
+func (m *MockArticleReviewService) NeedReview(art domain.Article) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NeedReview", art)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NeedReview indicates an expected call of NeedReview.
+func (mr *MockArticleReviewServiceMockRecorder) NeedReview(art any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NeedReview", reflect.TypeOf((*MockArticleReviewService)(nil).NeedReview), art)
+}
+
+// Queue mocks base method.
+func (m *MockArticleReviewService) Queue(ctx context.Context, reviewer int64, offset, limit int) ([]domain.ArticleReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Queue", ctx, reviewer, offset, limit)
+	ret0, _ := ret[0].([]domain.ArticleReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Queue indicates an expected call of Queue.
+func (mr *MockArticleReviewServiceMockRecorder) Queue(ctx, reviewer, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Queue", reflect.TypeOf((*MockArticleReviewService)(nil).Queue), ctx, reviewer, offset, limit)
+}
+
+// Reject mocks base method.
+func (m *MockArticleReviewService) Reject(ctx context.Context, reviewer, aid int64, comment string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reject", ctx, reviewer, aid, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reject indicates an expected call of Reject.
+func (mr *MockArticleReviewServiceMockRecorder) Reject(ctx, reviewer, aid, comment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reject", reflect.TypeOf((*MockArticleReviewService)(nil).Reject), ctx, reviewer, aid, comment)
+}
+
+// RequestChanges mocks base method.
+func (m *MockArticleReviewService) RequestChanges(ctx context.Context, reviewer, aid int64, comment string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestChanges", ctx, reviewer, aid, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestChanges indicates an expected call of RequestChanges.
+func (mr *MockArticleReviewServiceMockRecorder) RequestChanges(ctx, reviewer, aid, comment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestChanges", reflect.TypeOf((*MockArticleReviewService)(nil).RequestChanges), ctx, reviewer, aid, comment)
+}
+
+// Submit mocks base method.
+func (m *MockArticleReviewService) Submit(ctx context.Context, uid, aid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Submit", ctx, uid, aid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Submit indicates an expected call of Submit.
+func (mr *MockArticleReviewServiceMockRecorder) Submit(ctx, uid, aid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Submit", reflect.TypeOf((*MockArticleReviewService)(nil).Submit), ctx, uid, aid)
+}
@@ -11,6 +11,7 @@ package svcmocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "gitee.com/geekbang/basic-go/webook/internal/domain"
 	gomock "go.uber.org/mock/gomock"
@@ -54,6 +55,35 @@ func (mr *MockRankingServiceMockRecorder) GetTopN(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopN", reflect.TypeOf((*MockRankingService)(nil).GetTopN), ctx)
 }
 
+// GetTopNByKey mocks base method.
+func (m *MockRankingService) GetTopNByKey(ctx context.Context, key string) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopNByKey", ctx, key)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopNByKey indicates an expected call of GetTopNByKey.
+func (mr *MockRankingServiceMockRecorder) GetTopNByKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopNByKey", reflect.TypeOf((*MockRankingService)(nil).GetTopNByKey), ctx, key)
+}
+
+// IncrScore mocks base method.
+func (m *MockRankingService) IncrScore(ctx context.Context, aid int64, signal domain.RankingSignal, delta int64, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrScore", ctx, aid, signal, delta, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrScore indicates an expected call of IncrScore.
+func (mr *MockRankingServiceMockRecorder) IncrScore(ctx, aid, signal, delta, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrScore", reflect.TypeOf((*MockRankingService)(nil).IncrScore), ctx, aid, signal, delta, at)
+}
+
 // TopN mocks base method.
 func (m *MockRankingService) TopN(ctx context.Context) error {
 	m.ctrl.T.Helper()
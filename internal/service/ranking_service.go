@@ -3,140 +3,237 @@ package service
 import (
 	"context"
 	intrv1 "gitee.com/geekbang/basic-go/webook/api/proto/gen/intr/v1"
+	tagv1 "gitee.com/geekbang/basic-go/webook/api/proto/gen/tag/v1"
 	"gitee.com/geekbang/basic-go/webook/internal/domain"
 	"gitee.com/geekbang/basic-go/webook/internal/repository"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
 	"github.com/ecodeclub/ekit/queue"
 	"github.com/ecodeclub/ekit/slice"
-	"math"
 	"time"
 )
 
 //go:generate mockgen -source=./ranking_service.go -package=svcmocks -destination=./mocks/ranking_service.mock.go RankingService
 type RankingService interface {
-	// TopN 前 100 的
+	// TopN 一次把所有配置的热榜都算出来
 	TopN(ctx context.Context) error
+	// GetTopN 全站热榜
 	GetTopN(ctx context.Context) ([]domain.Article, error)
+	// GetTopNByKey 按照热榜的名字来查询
+	GetTopNByKey(ctx context.Context, key string) ([]domain.Article, error)
+	// IncrScore at 这个时候 aid 多了 delta 次 signal 行为，只影响实时热榜。
+	// delta 为负数就是撤销，比如说取消点赞
+	IncrScore(ctx context.Context, aid int64, signal domain.RankingSignal, delta int64, at time.Time) error
+}
+
+// RankingConfig 热度算法和热榜的配置。
+// 热榜里面的 Strategy 必须是 Strategies 里面的某一个
+type RankingConfig struct {
+	Strategies []RankingStrategy
+	Lists      []domain.RankingList
+}
+
+// DefaultRankingConfig 七天之内，只看点赞数的全站前 100
+func DefaultRankingConfig() RankingConfig {
+	return RankingConfig{
+		Strategies: []RankingStrategy{NewLikeRankingStrategy()},
+		Lists: []domain.RankingList{
+			{
+				Key:      domain.DefaultRankingList,
+				Strategy: "like",
+				Window:   7 * 24 * time.Hour,
+				N:        100,
+			},
+		},
+	}
 }
 
 type BatchRankingService struct {
-	// 用来取点赞数
+	// 用来取阅读、点赞和收藏数
 	intrSvc intrv1.InteractiveServiceClient
+	// 用来取文章的标签，没有按照标签分的热榜的时候不会调用
+	tagSvc tagv1.TagServiceClient
 
 	// 用来查找文章
 	artSvc ArticleService
 
-	batchSize int
-	scoreFunc func(likeCnt int64, utime time.Time) float64
-	n         int
+	batchSize  int
+	strategies map[string]RankingStrategy
+	lists      []domain.RankingList
 
 	repo repository.RankingRepository
+	l    logger.LoggerV1
 }
 
 func NewBatchRankingService(intrSvc intrv1.InteractiveServiceClient,
-	artSvc ArticleService, repo repository.RankingRepository) RankingService {
+	tagSvc tagv1.TagServiceClient,
+	artSvc ArticleService, repo repository.RankingRepository,
+	cfg RankingConfig, l logger.LoggerV1) RankingService {
+	strategies := make(map[string]RankingStrategy, len(cfg.Strategies))
+	for _, s := range cfg.Strategies {
+		if s == nil {
+			continue
+		}
+		strategies[s.Name()] = s
+	}
+	// 热度算法不存在的热榜算不出来，直接跳过
+	lists := make([]domain.RankingList, 0, len(cfg.Lists))
+	for _, rl := range cfg.Lists {
+		if _, ok := strategies[rl.Strategy]; !ok {
+			l.Error("热榜用了不存在的热度算法，跳过",
+				logger.String("key", rl.Key),
+				logger.String("strategy", rl.Strategy))
+			continue
+		}
+		lists = append(lists, rl)
+	}
 	return &BatchRankingService{
-		intrSvc:   intrSvc,
-		artSvc:    artSvc,
-		batchSize: 100,
-		n:         100,
-		repo:      repo,
-		scoreFunc: func(likeCnt int64, utime time.Time) float64 {
-			// 时间
-			duration := time.Since(utime).Seconds()
-			return float64(likeCnt-1) / math.Pow(duration+2, 1.5)
-		},
+		intrSvc:    intrSvc,
+		tagSvc:     tagSvc,
+		artSvc:     artSvc,
+		batchSize:  100,
+		strategies: strategies,
+		lists:      lists,
+		repo:       repo,
+		l:          l,
 	}
 }
 
 func (b *BatchRankingService) GetTopN(ctx context.Context) ([]domain.Article, error) {
-	return b.repo.GetTopN(ctx)
+	return b.GetTopNByKey(ctx, domain.DefaultRankingList)
+}
+
+func (b *BatchRankingService) GetTopNByKey(ctx context.Context, key string) ([]domain.Article, error) {
+	for _, l := range b.lists {
+		if l.Key == key && l.Streaming() {
+			arts, err := b.streamTopN(ctx, l)
+			if err == nil {
+				return arts, nil
+			}
+			// 实时热榜出了问题或者还没有数据，就用定时任务算出来的
+			if err != errStreamEmpty {
+				b.l.Error("查询实时热榜失败",
+					logger.String("key", key),
+					logger.Error(err))
+			}
+			break
+		}
+	}
+	return b.repo.GetTopN(ctx, key)
 }
 
 func (b *BatchRankingService) TopN(ctx context.Context) error {
-	arts, err := b.topN(ctx)
+	now := time.Now()
+	lists, err := b.topN(ctx, now)
 	if err != nil {
 		return err
 	}
 	// 最终是要放到缓存里面的
-	// 存到缓存里面
-	return b.repo.ReplaceTopN(ctx, arts)
+	// 每个热榜单独存
+	for _, l := range b.lists {
+		eles := lists[l.Key]
+		arts := slice.Map(eles, func(idx int, src rankingScore) domain.Article {
+			return src.art
+		})
+		err = b.repo.ReplaceTopN(ctx, l.Key, arts)
+		if err != nil {
+			return err
+		}
+		if !l.Streaming() {
+			continue
+		}
+		// 纠正实时热榜累加出来的偏差，衰减的起点也从现在重新算
+		scores := make(map[int64]float64, len(eles))
+		for _, ele := range eles {
+			scores[ele.art.Id] = ele.score
+		}
+		err = b.repo.ReplaceScores(ctx, l.Key, scores, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (b *BatchRankingService) topN(ctx context.Context) ([]domain.Article, error) {
-	offset := 0
-	start := time.Now()
-	ddl := start.Add(-7 * 24 * time.Hour)
-
-	type Score struct {
-		score float64
-		art   domain.Article
-	}
-	topN := queue.NewPriorityQueue[Score](b.n,
-		func(src Score, dst Score) int {
-			if src.score > dst.score {
-				return 1
-			} else if src.score == dst.score {
-				return 0
-			} else {
-				return -1
-			}
-		})
+type rankingScore struct {
+	score float64
+	art   domain.Article
+}
+
+// topN 只扫一遍文章，同时计算所有的热榜，返回值的 key 是热榜的名字。
+// 实时热榜的分数是以 start 为衰减起点的分数，可以直接覆盖 Redis 里面的
+func (b *BatchRankingService) topN(ctx context.Context, start time.Time) (map[string][]rankingScore, error) {
+	// 按照最长的那个窗口来扫
+	var window time.Duration
+	topNs := make([]*queue.PriorityQueue[rankingScore], len(b.lists))
+	for i, l := range b.lists {
+		if l.Window > window {
+			window = l.Window
+		}
+		topNs[i] = queue.NewPriorityQueue[rankingScore](l.N,
+			func(src rankingScore, dst rankingScore) int {
+				if src.score > dst.score {
+					return 1
+				} else if src.score == dst.score {
+					return 0
+				} else {
+					return -1
+				}
+			})
+	}
+	ddl := start.Add(-window)
+	// 从现在往前翻，id 为 0 意味着 utime 等于 start 的都不要
+	cursor := domain.ArticleCursor{Utime: start.UnixMilli()}
 
 	for {
 		// 取数据
-		arts, err := b.artSvc.ListPub(ctx, start, offset, b.batchSize)
+		arts, err := b.artSvc.ListPub(ctx, cursor, b.batchSize)
 		if err != nil {
 			return nil, err
 		}
-		//if len(arts) == 0 {
-		//	break
-		//}
-		ids := slice.Map(arts, func(idx int, art domain.Article) int64 {
-			return art.Id
-		})
-		// 取点赞数
-		// 2014.1.9 答疑
-		// 直接用 Get 就是遍历
-		// 普遍来说，性能差
-		for _, id := range ids {
-			b.intrSvc.Get(ctx, &intrv1.GetRequest{
-				Biz: "article", BizId: id,
-			})
+		signals, err := b.signals(ctx, arts)
+		if err != nil {
+			return nil, err
 		}
-		intrResp, err := b.intrSvc.GetByIds(ctx, &intrv1.GetByIdsRequest{
-			Biz: "article", Ids: ids,
-		})
+		tags, err := b.tags(ctx, arts)
 		if err != nil {
 			return nil, err
 		}
-		intrMap := intrResp.Intrs
 		for _, art := range arts {
-			intr := intrMap[art.Id]
-			//intr, ok := intrMap[art.Id]
-			//if !ok {
-			//	continue
-			//}
-			score := b.scoreFunc(intr.LikeCnt, art.Utime)
-			ele := Score{
-				score: score,
-				art:   art,
-			}
-			err = topN.Enqueue(ele)
-			if err == queue.ErrOutOfCapacity {
-				// 这个也是满了
-				// 拿出最小的元素
-				minEle, _ := topN.Dequeue()
-				if minEle.score < score {
-					_ = topN.Enqueue(ele)
+			for i, l := range b.lists {
+				if art.Utime.Before(start.Add(-l.Window)) {
+					continue
+				}
+				if _, ok := tags[art.Id][l.Tag]; l.Tag != "" && !ok {
+					continue
+				}
+				strategy, ok := b.strategies[l.Strategy]
+				if !ok {
+					continue
+				}
+				ele := rankingScore{art: art}
+				if l.Streaming() {
+					ele.score = streamScore(strategy, signals[art.Id], art.Utime, start, l.HalfLife)
 				} else {
-					_ = topN.Enqueue(minEle)
+					ele.score = strategy.Score(signals[art.Id], art.Utime)
+				}
+				topN := topNs[i]
+				err = topN.Enqueue(ele)
+				if err == queue.ErrOutOfCapacity {
+					// 这个也是满了
+					// 拿出最小的元素
+					minEle, _ := topN.Dequeue()
+					if minEle.score < ele.score {
+						_ = topN.Enqueue(ele)
+					} else {
+						_ = topN.Enqueue(minEle)
+					}
 				}
 			}
 		}
-		offset = offset + len(arts)
 		// 没有取够一批，我们就直接中断执行
 		// 没有下一批了
-		if len(arts) < b.batchSize ||
+		cursor = domain.NextArticleCursor(arts, b.batchSize)
+		if cursor.IsZero() ||
 			// 这个是一个优化
 			arts[len(arts)-1].Utime.Before(ddl) {
 			break
@@ -144,10 +241,70 @@ func (b *BatchRankingService) topN(ctx context.Context) ([]domain.Article, error
 	}
 
 	// 这边 topN 里面就是最终结果
-	res := make([]domain.Article, topN.Len())
-	for i := topN.Len() - 1; i >= 0; i-- {
-		ele, _ := topN.Dequeue()
-		res[i] = ele.art
+	res := make(map[string][]rankingScore, len(b.lists))
+	for i, l := range b.lists {
+		topN := topNs[i]
+		eles := make([]rankingScore, topN.Len())
+		for j := topN.Len() - 1; j >= 0; j-- {
+			eles[j], _ = topN.Dequeue()
+		}
+		res[l.Key] = eles
+	}
+	return res, nil
+}
+
+// signals 批量查询这一批文章的热度数据
+func (b *BatchRankingService) signals(ctx context.Context,
+	arts []domain.Article) (map[int64]domain.RankingSignals, error) {
+	ids := slice.Map(arts, func(idx int, art domain.Article) int64 {
+		return art.Id
+	})
+	res := make(map[int64]domain.RankingSignals, len(ids))
+	intrResp, err := b.intrSvc.GetByIds(ctx, &intrv1.GetByIdsRequest{
+		Biz: "article", Ids: ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for id, intr := range intrResp.GetIntrs() {
+		res[id] = domain.RankingSignals{
+			ReadCnt:    intr.GetReadCnt(),
+			LikeCnt:    intr.GetLikeCnt(),
+			CollectCnt: intr.GetCollectCnt(),
+		}
+	}
+	return res, nil
+}
+
+// tags 一次查出这一批文章的标签名字，只有作者自己打的标签才算
+func (b *BatchRankingService) tags(ctx context.Context,
+	arts []domain.Article) (map[int64]map[string]struct{}, error) {
+	needTags := false
+	for _, l := range b.lists {
+		if l.Tag != "" {
+			needTags = true
+			break
+		}
+	}
+	if !needTags {
+		return nil, nil
+	}
+	resp, err := b.tagSvc.BatchGetBizTags(ctx, &tagv1.BatchGetBizTagsRequest{
+		Biz: "article",
+		BizIds: slice.Map(arts, func(idx int, art domain.Article) int64 {
+			return art.Id
+		}),
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[int64]map[string]struct{}, len(resp.GetBizTags()))
+	for id, tags := range resp.GetBizTags() {
+		names := make(map[string]struct{}, len(tags.GetTags()))
+		for _, t := range tags.GetTags() {
+			names[t.GetName()] = struct{}{}
+		}
+		res[id] = names
 	}
 	return res, nil
 }
@@ -2,12 +2,19 @@ package service
 
 import (
 	"context"
-	domain2 "gitee.com/geekbang/basic-go/webook/interactive/domain"
-	"gitee.com/geekbang/basic-go/webook/interactive/service"
+	"errors"
+	intrv1 "gitee.com/geekbang/basic-go/webook/api/proto/gen/intr/v1"
+	intrmocks "gitee.com/geekbang/basic-go/webook/api/proto/gen/intr/v1/mocks"
+	tagv1 "gitee.com/geekbang/basic-go/webook/api/proto/gen/tag/v1"
 	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"gitee.com/geekbang/basic-go/webook/internal/repository"
+	repomocks "gitee.com/geekbang/basic-go/webook/internal/repository/mocks"
 	svcmocks "gitee.com/geekbang/basic-go/webook/internal/service/mocks"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/ecodeclub/ekit/slice"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
 	"testing"
 	"time"
 )
@@ -18,60 +25,72 @@ func TestBatchRankingService_TopN(t *testing.T) {
 	testCases := []struct {
 		name string
 
-		mock func(ctrl *gomock.Controller) (service.InteractiveService, ArticleService)
+		mock func(ctrl *gomock.Controller) (intrv1.InteractiveServiceClient, ArticleService)
 
-		wantArts []domain.Article
+		wantArts map[string][]domain.Article
 		wantErr  error
 	}{
 		{
-			name: "成功获取",
-			mock: func(ctrl *gomock.Controller) (service.InteractiveService, ArticleService) {
-				intrSvc := svcmocks.NewMockInteractiveService(ctrl)
+			name: "一次算出所有热榜",
+			mock: func(ctrl *gomock.Controller) (intrv1.InteractiveServiceClient, ArticleService) {
+				intrSvc := intrmocks.NewMockInteractiveServiceClient(ctrl)
 				artSvc := svcmocks.NewMockArticleService(ctrl)
 				// 先模拟批量获取数据
 				// 先模拟第一批
-				artSvc.EXPECT().ListPub(gomock.Any(), gomock.Any(), 0, 2).
+				artSvc.EXPECT().ListPub(gomock.Any(), gomock.Any(), 2).
 					Return([]domain.Article{
 						{Id: 1, Utime: now},
 						{Id: 2, Utime: now},
 					}, nil)
 				// 模拟第二批
-				artSvc.EXPECT().ListPub(gomock.Any(), gomock.Any(), 2, 2).
+				artSvc.EXPECT().ListPub(gomock.Any(),
+					domain.ArticleCursor{Utime: now.UnixMilli(), Id: 2}, 2).
 					Return([]domain.Article{
 						{Id: 3, Utime: now},
 						{Id: 4, Utime: now},
 					}, nil)
 				// 模拟第三批
-				artSvc.EXPECT().ListPub(gomock.Any(), gomock.Any(), 4, 2).
+				artSvc.EXPECT().ListPub(gomock.Any(),
+					domain.ArticleCursor{Utime: now.UnixMilli(), Id: 4}, 2).
 					// 没数据了
 					Return([]domain.Article{}, nil)
 
-				// 第一批的点赞数据
-				intrSvc.EXPECT().GetByIds(gomock.Any(), "article", []int64{1, 2}).
-					Return(map[int64]domain2.Interactive{
-						1: {LikeCnt: 1},
-						2: {LikeCnt: 2},
-					}, nil)
-
-				// 第二批的点赞数据
-				intrSvc.EXPECT().GetByIds(gomock.Any(), "article", []int64{3, 4}).
-					Return(map[int64]domain2.Interactive{
-						3: {LikeCnt: 3},
-						4: {LikeCnt: 4},
-					}, nil)
-
-				// 第三批的点赞数据
-				intrSvc.EXPECT().GetByIds(gomock.Any(), "article", []int64{}).
-					Return(map[int64]domain2.Interactive{}, nil)
-
+				gomock.InOrder(
+					// 第一批的点赞数据
+					intrSvc.EXPECT().GetByIds(gomock.Any(), gomock.Any()).
+						Return(&intrv1.GetByIdsResponse{
+							Intrs: map[int64]*intrv1.Interactive{
+								1: {LikeCnt: 1, ReadCnt: 10},
+								2: {LikeCnt: 2, ReadCnt: 1},
+							},
+						}, nil),
+					// 第二批的点赞数据
+					intrSvc.EXPECT().GetByIds(gomock.Any(), gomock.Any()).
+						Return(&intrv1.GetByIdsResponse{
+							Intrs: map[int64]*intrv1.Interactive{
+								3: {LikeCnt: 3, ReadCnt: 5},
+								// 4 没有阅读
+								4: {LikeCnt: 4},
+							},
+						}, nil),
+					// 第三批的点赞数据
+					intrSvc.EXPECT().GetByIds(gomock.Any(), gomock.Any()).
+						Return(&intrv1.GetByIdsResponse{}, nil),
+				)
 				return intrSvc, artSvc
 			},
 
 			wantErr: nil,
-			wantArts: []domain.Article{
-				{Id: 4, Utime: now},
-				{Id: 3, Utime: now},
-				{Id: 2, Utime: now},
+			wantArts: map[string][]domain.Article{
+				"like": {
+					{Id: 4, Utime: now},
+					{Id: 3, Utime: now},
+					{Id: 2, Utime: now},
+				},
+				"read": {
+					{Id: 1, Utime: now},
+					{Id: 3, Utime: now},
+				},
 			},
 		},
 	}
@@ -81,16 +100,248 @@ func TestBatchRankingService_TopN(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			intrSvc, artSvc := tc.mock(ctrl)
-			svc := &BatchRankingService{
-				intrSvc:   intrSvc,
-				artSvc:    artSvc,
-				batchSize: batchSize,
-				n:         3,
-				scoreFunc: func(likeCnt int64, utime time.Time) float64 {
-					return float64(likeCnt)
-				},
+			// gravity 为 0 就是不考虑时间衰减
+			svc := NewBatchRankingService(intrSvc, nil, artSvc, nil,
+				RankingConfig{
+					Strategies: []RankingStrategy{
+						NewWeightedRankingStrategy("like", RankingWeights{Like: 1}, 0),
+						NewWeightedRankingStrategy("read", RankingWeights{Read: 1}, 0),
+					},
+					Lists: []domain.RankingList{
+						{Key: "like", Strategy: "like", Window: time.Hour, N: 3},
+						{Key: "read", Strategy: "read", Window: time.Hour, N: 2},
+					},
+				}, logger.NewNopLogger()).(*BatchRankingService)
+			svc.batchSize = batchSize
+			lists, err := svc.topN(context.Background(), now)
+			assert.Equal(t, tc.wantErr, err)
+			arts := make(map[string][]domain.Article, len(lists))
+			for key, eles := range lists {
+				arts[key] = slice.Map(eles, func(idx int, src rankingScore) domain.Article {
+					return src.art
+				})
 			}
-			arts, err := svc.topN(context.Background())
+			assert.Equal(t, tc.wantArts, arts)
+		})
+	}
+}
+
+// 按照标签分的热榜，每一批文章只查一次标签；热度算法不存在的热榜直接跳过
+func TestBatchRankingService_topNByTag(t *testing.T) {
+	now := time.Now()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	intrSvc := intrmocks.NewMockInteractiveServiceClient(ctrl)
+	artSvc := svcmocks.NewMockArticleService(ctrl)
+	artSvc.EXPECT().ListPub(gomock.Any(), gomock.Any(), 100).
+		Return([]domain.Article{
+			{Id: 1, Utime: now},
+			{Id: 2, Utime: now},
+			{Id: 3, Utime: now},
+		}, nil)
+	intrSvc.EXPECT().GetByIds(gomock.Any(), gomock.Any()).
+		Return(&intrv1.GetByIdsResponse{
+			Intrs: map[int64]*intrv1.Interactive{
+				1: {LikeCnt: 1},
+				2: {LikeCnt: 2},
+				3: {LikeCnt: 3},
+			},
+		}, nil)
+	tagSvc := &fakeTagClient{
+		resp: &tagv1.BatchGetBizTagsResponse{
+			BizTags: map[int64]*tagv1.BizTags{
+				1: {Tags: []*tagv1.Tag{{Name: "Go"}}},
+				3: {Tags: []*tagv1.Tag{{Name: "Go"}, {Name: "Java"}}},
+			},
+		},
+	}
+	svc := NewBatchRankingService(intrSvc, tagSvc, artSvc, nil,
+		RankingConfig{
+			Strategies: []RankingStrategy{
+				NewWeightedRankingStrategy("like", RankingWeights{Like: 1}, 0),
+				nil,
+			},
+			Lists: []domain.RankingList{
+				{Key: "go", Strategy: "like", Tag: "Go", Window: time.Hour, N: 10},
+				{Key: "missing", Strategy: "missing", Window: time.Hour, N: 10},
+			},
+		}, logger.NewNopLogger()).(*BatchRankingService)
+	lists, err := svc.topN(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, tagSvc.calls)
+	assert.Equal(t, []int64{1, 2, 3}, tagSvc.req.GetBizIds())
+	ids := slice.Map(lists["go"], func(idx int, src rankingScore) int64 {
+		return src.art.Id
+	})
+	assert.Equal(t, []int64{3, 1}, ids)
+	_, ok := lists["missing"]
+	assert.False(t, ok)
+}
+
+type fakeTagClient struct {
+	tagv1.TagServiceClient
+	calls int
+	req   *tagv1.BatchGetBizTagsRequest
+	resp  *tagv1.BatchGetBizTagsResponse
+}
+
+func (f *fakeTagClient) BatchGetBizTags(ctx context.Context, in *tagv1.BatchGetBizTagsRequest,
+	opts ...grpc.CallOption) (*tagv1.BatchGetBizTagsResponse, error) {
+	f.calls++
+	f.req = in
+	return f.resp, nil
+}
+
+func TestBatchRankingService_IncrScore(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.RankingRepository
+
+		signal  domain.RankingSignal
+		delta   int64
+		wantErr error
+	}{
+		{
+			name: "只更新用得上这项数据的实时热榜",
+			mock: func(ctrl *gomock.Controller) repository.RankingRepository {
+				repo := repomocks.NewMockRankingRepository(ctrl)
+				repo.EXPECT().IncrScore(gomock.Any(), "balanced", int64(1), float64(2),
+					now, time.Hour, 50).Return(nil)
+				return repo
+			},
+			signal: domain.RankingSignalCollect,
+			delta:  1,
+		},
+		{
+			name: "取消点赞",
+			mock: func(ctrl *gomock.Controller) repository.RankingRepository {
+				repo := repomocks.NewMockRankingRepository(ctrl)
+				repo.EXPECT().IncrScore(gomock.Any(), "like", int64(1), float64(-1),
+					now, 2*time.Hour, 15).Return(nil)
+				repo.EXPECT().IncrScore(gomock.Any(), "balanced", int64(1), float64(-1),
+					now, time.Hour, 50).Return(nil)
+				return repo
+			},
+			signal: domain.RankingSignalLike,
+			delta:  -1,
+		},
+		{
+			name: "没有热榜用得上",
+			mock: func(ctrl *gomock.Controller) repository.RankingRepository {
+				return repomocks.NewMockRankingRepository(ctrl)
+			},
+			signal: domain.RankingSignalRead,
+			delta:  1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewBatchRankingService(nil, nil, nil, tc.mock(ctrl),
+				RankingConfig{
+					Strategies: []RankingStrategy{
+						NewWeightedRankingStrategy("like", RankingWeights{Like: 1}, 0),
+						NewWeightedRankingStrategy("balanced", RankingWeights{Like: 1, Collect: 2}, 0),
+					},
+					Lists: []domain.RankingList{
+						{Key: "like", Strategy: "like", Window: time.Hour, N: 3, HalfLife: 2 * time.Hour},
+						{Key: "balanced", Strategy: "balanced", Window: time.Hour, N: 10, HalfLife: time.Hour},
+						// 不是实时热榜
+						{Key: "batch", Strategy: "balanced", Window: time.Hour, N: 10},
+					},
+				}, logger.NewNopLogger())
+			err := svc.IncrScore(context.Background(), 1, tc.signal, tc.delta, now)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestBatchRankingService_GetTopNByKey(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (ArticleService, repository.RankingRepository)
+
+		key      string
+		wantArts []domain.Article
+		wantErr  error
+	}{
+		{
+			name: "实时热榜，去掉窗口之外和已经撤回的",
+			mock: func(ctrl *gomock.Controller) (ArticleService, repository.RankingRepository) {
+				artSvc := svcmocks.NewMockArticleService(ctrl)
+				repo := repomocks.NewMockRankingRepository(ctrl)
+				repo.EXPECT().GetTopIds(gomock.Any(), "hot", 10).
+					Return([]int64{3, 1, 2, 4}, nil)
+				// 2 已经撤回了
+				artSvc.EXPECT().ListPubByIds(gomock.Any(), []int64{3, 1, 2, 4}).
+					Return([]domain.Article{
+						{Id: 1, Utime: now},
+						{Id: 3, Utime: now.Add(-2 * time.Hour)},
+						{Id: 4, Utime: now},
+					}, nil)
+				return artSvc, repo
+			},
+			key: "hot",
+			wantArts: []domain.Article{
+				{Id: 1, Utime: now},
+				{Id: 4, Utime: now},
+			},
+		},
+		{
+			name: "实时热榜查询失败，用定时任务的结果",
+			mock: func(ctrl *gomock.Controller) (ArticleService, repository.RankingRepository) {
+				repo := repomocks.NewMockRankingRepository(ctrl)
+				repo.EXPECT().GetTopIds(gomock.Any(), "hot", 10).
+					Return(nil, errors.New("mock redis error"))
+				repo.EXPECT().GetTopN(gomock.Any(), "hot").
+					Return([]domain.Article{{Id: 3}}, nil)
+				return svcmocks.NewMockArticleService(ctrl), repo
+			},
+			key:      "hot",
+			wantArts: []domain.Article{{Id: 3}},
+		},
+		{
+			name: "实时热榜还没有数据，用定时任务的结果",
+			mock: func(ctrl *gomock.Controller) (ArticleService, repository.RankingRepository) {
+				repo := repomocks.NewMockRankingRepository(ctrl)
+				repo.EXPECT().GetTopIds(gomock.Any(), "hot", 10).
+					Return([]int64{}, nil)
+				repo.EXPECT().GetTopN(gomock.Any(), "hot").
+					Return([]domain.Article{{Id: 6}}, nil)
+				return svcmocks.NewMockArticleService(ctrl), repo
+			},
+			key:      "hot",
+			wantArts: []domain.Article{{Id: 6}},
+		},
+		{
+			name: "不是实时热榜",
+			mock: func(ctrl *gomock.Controller) (ArticleService, repository.RankingRepository) {
+				repo := repomocks.NewMockRankingRepository(ctrl)
+				repo.EXPECT().GetTopN(gomock.Any(), "batch").
+					Return([]domain.Article{{Id: 5}}, nil)
+				return svcmocks.NewMockArticleService(ctrl), repo
+			},
+			key:      "batch",
+			wantArts: []domain.Article{{Id: 5}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			artSvc, repo := tc.mock(ctrl)
+			svc := NewBatchRankingService(nil, nil, artSvc, repo,
+				RankingConfig{
+					Strategies: []RankingStrategy{NewLikeRankingStrategy()},
+					Lists: []domain.RankingList{
+						{Key: "hot", Strategy: "like", Window: time.Hour, N: 2, HalfLife: time.Hour},
+						{Key: "batch", Strategy: "like", Window: time.Hour, N: 2},
+					},
+				}, logger.NewNopLogger())
+			arts, err := svc.GetTopNByKey(context.Background(), tc.key)
 			assert.Equal(t, tc.wantErr, err)
 			assert.Equal(t, tc.wantArts, arts)
 		})
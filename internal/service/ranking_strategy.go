@@ -0,0 +1,68 @@
+package service
+
+import (
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"math"
+	"time"
+)
+
+// RankingStrategy 热度算法，BatchRankingService 按照 Name 来找
+type RankingStrategy interface {
+	Name() string
+	// Weight 一次行为值多少分，实时热榜按照这个来累加
+	Weight(signal domain.RankingSignal) float64
+	// Score 分数越高越靠前
+	Score(signals domain.RankingSignals, utime time.Time) float64
+}
+
+// RankingWeights 每一项数据的权重，为 0 就是不考虑
+type RankingWeights struct {
+	Read    float64
+	Like    float64
+	Collect float64
+}
+
+// WeightedRankingStrategy 各项数据加权求和之后，再按照时间衰减。
+// Gravity 越大，衰减越快
+type WeightedRankingStrategy struct {
+	name    string
+	weights RankingWeights
+	gravity float64
+}
+
+func NewWeightedRankingStrategy(name string, weights RankingWeights, gravity float64) RankingStrategy {
+	return &WeightedRankingStrategy{
+		name:    name,
+		weights: weights,
+		gravity: gravity,
+	}
+}
+
+func (w *WeightedRankingStrategy) Name() string {
+	return w.name
+}
+
+func (w *WeightedRankingStrategy) Weight(signal domain.RankingSignal) float64 {
+	switch signal {
+	case domain.RankingSignalRead:
+		return w.weights.Read
+	case domain.RankingSignalLike:
+		return w.weights.Like
+	case domain.RankingSignalCollect:
+		return w.weights.Collect
+	}
+	return 0
+}
+
+func (w *WeightedRankingStrategy) Score(signals domain.RankingSignals, utime time.Time) float64 {
+	total := w.weights.Read*float64(signals.ReadCnt) +
+		w.weights.Like*float64(signals.LikeCnt) +
+		w.weights.Collect*float64(signals.CollectCnt)
+	duration := time.Since(utime).Seconds()
+	return (total - 1) / math.Pow(duration+2, w.gravity)
+}
+
+// NewLikeRankingStrategy 只看点赞数，也是默认的热度算法
+func NewLikeRankingStrategy() RankingStrategy {
+	return NewWeightedRankingStrategy("like", RankingWeights{Like: 1}, 1.5)
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"math"
+	"time"
+)
+
+// streamCapacity 实时热榜在 Redis 里面最多保留 N 的多少倍，
+// 多出来的用来补上超出窗口或者已经撤回的文章
+const streamCapacity = 5
+
+// IncrScore 在所有实时热榜上累加 signal 的分数
+func (b *BatchRankingService) IncrScore(ctx context.Context, aid int64,
+	signal domain.RankingSignal, delta int64, at time.Time) error {
+	for _, l := range b.lists {
+		if !l.Streaming() {
+			continue
+		}
+		strategy, ok := b.strategies[l.Strategy]
+		if !ok {
+			continue
+		}
+		weight := strategy.Weight(signal)
+		if weight == 0 {
+			continue
+		}
+		err := b.repo.IncrScore(ctx, l.Key, aid, weight*float64(delta),
+			at, l.HalfLife, l.N*streamCapacity)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errStreamEmpty Redis 里面还没有实时热榜，比如刚上线或者 key 过期了
+var errStreamEmpty = errors.New("实时热榜没有数据")
+
+// streamTopN 按照 Redis 里面的分数排序，再去掉窗口之外和已经不在线上的文章
+func (b *BatchRankingService) streamTopN(ctx context.Context, l domain.RankingList) ([]domain.Article, error) {
+	ids, err := b.repo.GetTopIds(ctx, l.Key, l.N*streamCapacity)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, errStreamEmpty
+	}
+	arts, err := b.artSvc.ListPubByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	artMap := make(map[int64]domain.Article, len(arts))
+	for _, art := range arts {
+		artMap[art.Id] = art
+	}
+	ddl := time.Now().Add(-l.Window)
+	res := make([]domain.Article, 0, l.N)
+	for _, id := range ids {
+		art, ok := artMap[id]
+		if !ok || art.Utime.Before(ddl) {
+			continue
+		}
+		art.Content = art.Abstract()
+		res = append(res, art)
+		if len(res) == l.N {
+			break
+		}
+	}
+	if len(res) == 0 {
+		// 都过期或者撤回了，说明 Redis 里面的数据太旧了
+		return nil, errStreamEmpty
+	}
+	return res, nil
+}
+
+// streamScore 实时热榜的分数：各项数据加权求和，当作都是在 utime 发生的，
+// 以 epoch 为起点，每过一个 halfLife 分数翻倍。
+// 这样后来的行为分数更高，也就等价于之前的分数都衰减了
+func streamScore(strategy RankingStrategy, signals domain.RankingSignals,
+	utime time.Time, epoch time.Time, halfLife time.Duration) float64 {
+	total := strategy.Weight(domain.RankingSignalRead)*float64(signals.ReadCnt) +
+		strategy.Weight(domain.RankingSignalLike)*float64(signals.LikeCnt) +
+		strategy.Weight(domain.RankingSignalCollect)*float64(signals.CollectCnt)
+	return total * math.Pow(2, float64(utime.Sub(epoch))/float64(halfLife))
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"gitee.com/geekbang/basic-go/webook/internal/repository"
+)
+
+var (
+	// ErrIllegalSeries 系列不存在，或者不属于这个创作者
+	ErrIllegalSeries = errors.New("非法的系列")
+	// ErrSeriesArticleInvalid 放进系列的文章不存在，或者不属于这个创作者
+	ErrSeriesArticleInvalid = repository.ErrSeriesArticleInvalid
+)
+
+//go:generate mockgen -source=./series.go -package=svcmocks -destination=./mocks/series.mock.go SeriesService
+type SeriesService interface {
+	Create(ctx context.Context, s domain.Series) (int64, error)
+	Update(ctx context.Context, s domain.Series) error
+	Delete(ctx context.Context, uid, id int64) error
+	// SetArticles 重新设置系列里面的文章，aids 的顺序就是章节顺序
+	SetArticles(ctx context.Context, uid, id int64, aids []int64) error
+	// GetById 创作者查看自己的系列，包括草稿
+	GetById(ctx context.Context, uid, id int64) (domain.Series, error)
+	ListByAuthor(ctx context.Context, uid int64) ([]domain.Series, error)
+	// GetPub 读者查看系列，只有已经发表的文章
+	GetPub(ctx context.Context, id int64) (domain.Series, error)
+}
+
+type seriesService struct {
+	repo repository.SeriesRepository
+}
+
+func NewSeriesService(repo repository.SeriesRepository) SeriesService {
+	return &seriesService{repo: repo}
+}
+
+func (s *seriesService) Create(ctx context.Context, se domain.Series) (int64, error) {
+	return s.repo.Create(ctx, se)
+}
+
+func (s *seriesService) Update(ctx context.Context, se domain.Series) error {
+	return s.repo.Update(ctx, se)
+}
+
+func (s *seriesService) Delete(ctx context.Context, uid, id int64) error {
+	return s.repo.Delete(ctx, uid, id)
+}
+
+func (s *seriesService) SetArticles(ctx context.Context, uid, id int64, aids []int64) error {
+	// 同一篇文章不能出现两次
+	seen := make(map[int64]struct{}, len(aids))
+	for _, aid := range aids {
+		if _, ok := seen[aid]; ok {
+			return ErrSeriesArticleInvalid
+		}
+		seen[aid] = struct{}{}
+	}
+	return s.repo.SetArticles(ctx, uid, id, aids)
+}
+
+func (s *seriesService) GetById(ctx context.Context, uid, id int64) (domain.Series, error) {
+	res, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return domain.Series{}, err
+	}
+	if res.Author.Id != uid {
+		return domain.Series{}, ErrIllegalSeries
+	}
+	return res, nil
+}
+
+func (s *seriesService) ListByAuthor(ctx context.Context, uid int64) ([]domain.Series, error) {
+	return s.repo.GetByAuthor(ctx, uid)
+}
+
+func (s *seriesService) GetPub(ctx context.Context, id int64) (domain.Series, error) {
+	return s.repo.GetPubById(ctx, id)
+}
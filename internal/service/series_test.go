@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"gitee.com/geekbang/basic-go/webook/internal/repository"
+	repomocks "gitee.com/geekbang/basic-go/webook/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func Test_seriesService_SetArticles(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.SeriesRepository
+
+		aids    []int64
+		wantErr error
+	}{
+		{
+			name: "设置成功",
+			mock: func(ctrl *gomock.Controller) repository.SeriesRepository {
+				repo := repomocks.NewMockSeriesRepository(ctrl)
+				repo.EXPECT().SetArticles(gomock.Any(), int64(123), int64(1),
+					[]int64{3, 1, 2}).Return(nil)
+				return repo
+			},
+			aids: []int64{3, 1, 2},
+		},
+		{
+			name: "文章重复",
+			mock: func(ctrl *gomock.Controller) repository.SeriesRepository {
+				return repomocks.NewMockSeriesRepository(ctrl)
+			},
+			aids:    []int64{3, 1, 3},
+			wantErr: ErrSeriesArticleInvalid,
+		},
+		{
+			name: "文章不是自己的",
+			mock: func(ctrl *gomock.Controller) repository.SeriesRepository {
+				repo := repomocks.NewMockSeriesRepository(ctrl)
+				repo.EXPECT().SetArticles(gomock.Any(), int64(123), int64(1),
+					[]int64{4}).Return(repository.ErrSeriesArticleInvalid)
+				return repo
+			},
+			aids:    []int64{4},
+			wantErr: ErrSeriesArticleInvalid,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewSeriesService(tc.mock(ctrl))
+			err := svc.SetArticles(context.Background(), 123, 1, tc.aids)
+			assert.True(t, errors.Is(err, tc.wantErr))
+		})
+	}
+}
+
+func Test_seriesService_GetById(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.SeriesRepository
+
+		wantSeries domain.Series
+		wantErr    error
+	}{
+		{
+			name: "查询成功",
+			mock: func(ctrl *gomock.Controller) repository.SeriesRepository {
+				repo := repomocks.NewMockSeriesRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(domain.Series{
+					Id:     1,
+					Title:  "我的系列",
+					Author: domain.Author{Id: 123},
+				}, nil)
+				return repo
+			},
+			wantSeries: domain.Series{
+				Id:     1,
+				Title:  "我的系列",
+				Author: domain.Author{Id: 123},
+			},
+		},
+		{
+			name: "不是自己的系列",
+			mock: func(ctrl *gomock.Controller) repository.SeriesRepository {
+				repo := repomocks.NewMockSeriesRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(domain.Series{
+					Id:     1,
+					Title:  "别人的系列",
+					Author: domain.Author{Id: 234},
+				}, nil)
+				return repo
+			},
+			wantErr: ErrIllegalSeries,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewSeriesService(tc.mock(ctrl))
+			s, err := svc.GetById(context.Background(), 123, 1)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantSeries, s)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package web
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	intrv1 "gitee.com/geekbang/basic-go/webook/api/proto/gen/intr/v1"
 	rewardv1 "gitee.com/geekbang/basic-go/webook/api/proto/gen/reward/v1"
@@ -17,6 +19,10 @@ import (
 	"time"
 )
 
+// rewardStatsTimeout 文章详情里面查打赏统计的超时时间。
+// 打赏统计可有可无，不能因为它拖慢看文章
+const rewardStatsTimeout = 100 * time.Millisecond
+
 type ArticleHandler struct {
 	svc     service.ArticleService
 	intrSvc intrv1.InteractiveServiceClient
@@ -46,10 +52,26 @@ func (h *ArticleHandler) RegisterRoutes(server *gin.Engine) {
 	g.POST("/publish", ginx.WrapBodyAndClaims(h.Publish))
 	g.POST("/withdraw", ginx.WrapBodyAndClaims(h.Withdraw))
 
+	// 定时发表
+	g.GET("/scheduled", ginx.WrapClaims(h.ListScheduled))
+	g.POST("/scheduled/reschedule", ginx.WrapBodyAndClaims(h.Reschedule))
+	g.POST("/scheduled/cancel", ginx.WrapBodyAndClaims(h.CancelSchedule))
+
+	// 历史版本
+	g.GET("/revisions/:id", ginx.WrapClaims(h.Revisions))
+	g.GET("/revisions/:id/diff", ginx.WrapClaims(h.RevisionDiff))
+	g.POST("/rollback", ginx.WrapBodyAndClaims(h.Rollback))
+
+	// 合作者
+	g.GET("/collaborators/:id", ginx.WrapClaims(h.Collaborators))
+	g.POST("/collaborators/invite", ginx.WrapBodyAndClaims(h.InviteCollaborator))
+	g.POST("/collaborators/accept", ginx.WrapBodyAndClaims(h.AcceptCollaborator))
+	g.POST("/collaborators/remove", ginx.WrapBodyAndClaims(h.RemoveCollaborator))
+
 	// 创作者接口
 	g.GET("/detail/:id", h.Detail)
 	// 按照道理来说，这边就是 GET 方法
-	// /list?offset=?&limit=?
+	// /list?cursor=?&limit=?
 	g.POST("/list", h.List)
 
 	pub := g.Group("/pub")
@@ -64,6 +86,10 @@ func (h *ArticleHandler) RegisterRoutes(server *gin.Engine) {
 // Edit 接收 Article 输入，返回一个 ID，文章的 ID
 func (h *ArticleHandler) Edit(ctx *gin.Context,
 	req ArticleEditReq, uc jwt.UserClaims) (ginx.Result, error) {
+	membersOnly, err := h.membersOnly(ctx, req.Id, req.MembersOnly)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
 	id, err := h.svc.Save(ctx, domain.Article{
 		Id:      req.Id,
 		Title:   req.Title,
@@ -71,7 +97,11 @@ func (h *ArticleHandler) Edit(ctx *gin.Context,
 		Author: domain.Author{
 			Id: uc.Uid,
 		},
+		MembersOnly: membersOnly,
 	})
+	if errors.Is(err, service.ErrArticleReviewing) {
+		return ginx.Result{Code: 4, Msg: "文章正在审核，不能修改"}, err
+	}
 	if err != nil {
 		return ginx.Result{
 			Msg: "系统错误",
@@ -93,14 +123,27 @@ func (h *ArticleHandler) Publish(ctx *gin.Context,
 	//	})
 	//	return
 	//}
-	id, err := h.svc.Publish(ctx, domain.Article{
+	membersOnly, err := h.membersOnly(ctx, req.Id, req.MembersOnly)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	art := domain.Article{
 		Id:      req.Id,
 		Title:   req.Title,
 		Content: req.Content,
 		Author: domain.Author{
 			Id: uc.Uid,
 		},
-	})
+		MembersOnly: membersOnly,
+	}
+	if req.PublishAt > 0 {
+		return h.schedulePublish(ctx, art, req.PublishAt)
+	}
+	id, err := h.svc.Publish(ctx, art)
+	if errors.Is(err, service.ErrArticleReviewing) ||
+		errors.Is(err, service.ErrReviewStatusConflict) {
+		return ginx.Result{Code: 4, Msg: "文章正在审核"}, err
+	}
 	if err != nil {
 		return ginx.Result{
 			Msg:  "系统错误",
@@ -112,9 +155,87 @@ func (h *ArticleHandler) Publish(ctx *gin.Context,
 	}, nil
 }
 
+// membersOnly 前端没有传的时候，新文章不是会员专享，修改的文章保持原样
+func (h *ArticleHandler) membersOnly(ctx *gin.Context, id int64, req *bool) (bool, error) {
+	if req != nil {
+		return *req, nil
+	}
+	if id <= 0 {
+		return false, nil
+	}
+	art, err := h.svc.GetById(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("查询文章失败 aid %d %w", id, err)
+	}
+	return art.MembersOnly, nil
+}
+
+func (h *ArticleHandler) schedulePublish(ctx *gin.Context,
+	art domain.Article, publishAt int64) (ginx.Result, error) {
+	art.PublishAt = time.UnixMilli(publishAt)
+	id, err := h.svc.SchedulePublish(ctx, art)
+	if errors.Is(err, service.ErrInvalidPublishTime) {
+		return ginx.Result{Code: 4, Msg: "定时发表的时间必须在将来"}, err
+	}
+	if err != nil {
+		return ginx.Result{
+			Msg:  "系统错误",
+			Code: 5,
+		}, fmt.Errorf("定时发表文章失败 aid %d, uid %d %w", art.Id, art.Author.Id, err)
+	}
+	return ginx.Result{
+		Data: id,
+	}, nil
+}
+
+func (h *ArticleHandler) ListScheduled(ctx *gin.Context, uc jwt.UserClaims) (ginx.Result, error) {
+	arts, err := h.svc.ListScheduled(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{
+		Data: slice.Map[domain.Article, ArticleVo](arts, func(idx int, src domain.Article) ArticleVo {
+			return ArticleVo{
+				Id:        src.Id,
+				Title:     src.Title,
+				Abstract:  src.Abstract(),
+				AuthorId:  src.Author.Id,
+				Status:    src.Status.ToUint8(),
+				Ctime:     src.Ctime.Format(time.DateTime),
+				Utime:     src.Utime.Format(time.DateTime),
+				PublishAt: src.PublishAt.Format(time.DateTime),
+			}
+		}),
+	}, nil
+}
+
+func (h *ArticleHandler) Reschedule(ctx *gin.Context,
+	req ArticleRescheduleReq, uc jwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.Reschedule(ctx, uc.Uid, req.Id, time.UnixMilli(req.PublishAt))
+	if errors.Is(err, service.ErrInvalidPublishTime) {
+		return ginx.Result{Code: 4, Msg: "定时发表的时间必须在将来"}, err
+	}
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
+
+func (h *ArticleHandler) CancelSchedule(ctx *gin.Context,
+	req ArticleCancelScheduleReq, uc jwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.CancelSchedule(ctx, uc.Uid, req.Id)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
+
 func (h *ArticleHandler) Withdraw(ctx *gin.Context,
 	req ArticleWithdrawReq, uc jwt.UserClaims) (ginx.Result, error) {
 	err := h.svc.Withdraw(ctx, uc.Uid, req.Id)
+	if errors.Is(err, service.ErrArticleNoPermission) {
+		return ginx.Result{Code: 4, Msg: "没有权限"}, err
+	}
 	if err != nil {
 		return ginx.Result{
 			Msg:  "系统错误",
@@ -126,14 +247,163 @@ func (h *ArticleHandler) Withdraw(ctx *gin.Context,
 	}, nil
 }
 
+func (h *ArticleHandler) Collaborators(ctx *gin.Context, uc jwt.UserClaims) (ginx.Result, error) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return ginx.Result{Code: 4, Msg: "id 参数错误"}, err
+	}
+	collabs, err := h.svc.ListCollaborators(ctx, uc.Uid, id)
+	if errors.Is(err, service.ErrArticleNoPermission) {
+		return ginx.Result{Code: 4, Msg: "非法访问"}, err
+	}
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{
+		Data: slice.Map[domain.ArticleCollaborator, CollaboratorVo](collabs,
+			func(idx int, src domain.ArticleCollaborator) CollaboratorVo {
+				return newCollaboratorVo(src)
+			}),
+	}, nil
+}
+
+func (h *ArticleHandler) InviteCollaborator(ctx *gin.Context,
+	req CollaboratorInviteReq, uc jwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.InviteCollaborator(ctx, uc.Uid, req.Id, req.Uid,
+		domain.CollaboratorRole(req.Role))
+	switch {
+	case errors.Is(err, service.ErrIllegalCollaborator):
+		return ginx.Result{Code: 4, Msg: "角色不对，或者邀请了自己"}, err
+	case errors.Is(err, service.ErrArticleNoPermission):
+		return ginx.Result{Code: 4, Msg: "只有创作者可以邀请"}, err
+	case err != nil:
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
+
+func (h *ArticleHandler) AcceptCollaborator(ctx *gin.Context,
+	req CollaboratorAcceptReq, uc jwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.AcceptCollaborator(ctx, uc.Uid, req.Id)
+	if errors.Is(err, service.ErrArticleNoPermission) {
+		return ginx.Result{Code: 4, Msg: "没有邀请，或者已经接受过了"}, err
+	}
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
+
+func (h *ArticleHandler) RemoveCollaborator(ctx *gin.Context,
+	req CollaboratorRemoveReq, uc jwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.RemoveCollaborator(ctx, uc.Uid, req.Id, req.Uid)
+	if errors.Is(err, service.ErrArticleNoPermission) {
+		return ginx.Result{Code: 4, Msg: "没有权限"}, err
+	}
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
+
+// Revisions 查看历史版本，/revisions/:id?offset=0&limit=10
+func (h *ArticleHandler) Revisions(ctx *gin.Context, uc jwt.UserClaims) (ginx.Result, error) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return ginx.Result{Code: 4, Msg: "id 参数错误"}, err
+	}
+	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+	revs, err := h.svc.ListRevisions(ctx, uc.Uid, id, offset, limit)
+	if errors.Is(err, service.ErrIllegalRevision) {
+		return ginx.Result{Code: 4, Msg: "非法访问"}, err
+	}
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{
+		Data: slice.Map[domain.ArticleRevision, ArticleRevisionVo](revs,
+			func(idx int, src domain.ArticleRevision) ArticleRevisionVo {
+				vo := newArticleRevisionVo(src)
+				// 列表不需要内容
+				vo.Content = ""
+				return vo
+			}),
+	}, nil
+}
+
+// RevisionDiff 比较两个历史版本，/revisions/:id/diff?from=1&to=2
+func (h *ArticleHandler) RevisionDiff(ctx *gin.Context, uc jwt.UserClaims) (ginx.Result, error) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return ginx.Result{Code: 4, Msg: "id 参数错误"}, err
+	}
+	from, err := strconv.ParseInt(ctx.Query("from"), 10, 64)
+	if err != nil {
+		return ginx.Result{Code: 4, Msg: "from 参数错误"}, err
+	}
+	to, err := strconv.ParseInt(ctx.Query("to"), 10, 64)
+	if err != nil {
+		return ginx.Result{Code: 4, Msg: "to 参数错误"}, err
+	}
+	diff, err := h.svc.DiffRevisions(ctx, uc.Uid, id, from, to)
+	if errors.Is(err, service.ErrIllegalRevision) {
+		return ginx.Result{Code: 4, Msg: "非法访问"}, err
+	}
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	toVo := func(idx int, src domain.DiffLine) DiffLineVo {
+		return DiffLineVo{Op: uint8(src.Op), Text: src.Text}
+	}
+	return ginx.Result{
+		Data: ArticleRevisionDiffVo{
+			From:    newArticleRevisionVo(diff.From),
+			To:      newArticleRevisionVo(diff.To),
+			Title:   slice.Map[domain.DiffLine, DiffLineVo](diff.Title, toVo),
+			Content: slice.Map[domain.DiffLine, DiffLineVo](diff.Content, toVo),
+		},
+	}, nil
+}
+
+func (h *ArticleHandler) Rollback(ctx *gin.Context,
+	req ArticleRollbackReq, uc jwt.UserClaims) (ginx.Result, error) {
+	id, err := h.svc.Rollback(ctx, uc.Uid, req.Id, req.Rid, req.Publish)
+	if errors.Is(err, service.ErrIllegalRevision) {
+		return ginx.Result{Code: 4, Msg: "非法访问"}, err
+	}
+	if err != nil {
+		return ginx.Result{
+			Msg:  "系统错误",
+			Code: 5,
+		}, fmt.Errorf("回滚文章失败 aid %d, rid %d, uid %d %w", req.Id, req.Rid, uc.Uid, err)
+	}
+	return ginx.Result{
+		Data: id,
+	}, nil
+}
+
 func (h *ArticleHandler) List(ctx *gin.Context) {
-	var page Page
-	if err := ctx.Bind(&page); err != nil {
+	var req ArticleListReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	cursor, err := domain.ParseArticleCursor(req.Cursor)
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 4,
+			Msg:  "cursor 参数错误",
+		})
 		return
 	}
-	// 我要不要检测一下？
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 10
+	}
 	uc := ctx.MustGet("user").(jwt.UserClaims)
-	arts, err := h.svc.GetByAuthor(ctx, uc.Uid, page.Offset, page.Limit)
+	arts, err := h.svc.GetByAuthor(ctx, uc.Uid, cursor, req.Limit)
 	if err != nil {
 		ctx.JSON(http.StatusOK, ginx.Result{
 			Code: 5,
@@ -141,26 +411,29 @@ func (h *ArticleHandler) List(ctx *gin.Context) {
 		})
 		h.l.Error("查找文章列表失败",
 			logger.Error(err),
-			logger.Int("offset", page.Offset),
-			logger.Int("limit", page.Limit),
+			logger.String("cursor", req.Cursor),
+			logger.Int("limit", req.Limit),
 			logger.Int64("uid", uc.Uid))
 		return
 	}
 	ctx.JSON(http.StatusOK, ginx.Result{
-		Data: slice.Map[domain.Article, ArticleVo](arts, func(idx int, src domain.Article) ArticleVo {
-			return ArticleVo{
-				Id:       src.Id,
-				Title:    src.Title,
-				Abstract: src.Abstract(),
-
-				//Content:  src.Content,
-				AuthorId: src.Author.Id,
-				// 列表，你不需要
-				Status: src.Status.ToUint8(),
-				Ctime:  src.Ctime.Format(time.DateTime),
-				Utime:  src.Utime.Format(time.DateTime),
-			}
-		}),
+		Data: ArticleListVo{
+			Articles: slice.Map[domain.Article, ArticleVo](arts, func(idx int, src domain.Article) ArticleVo {
+				return ArticleVo{
+					Id:       src.Id,
+					Title:    src.Title,
+					Abstract: src.Abstract(),
+
+					//Content:  src.Content,
+					AuthorId: src.Author.Id,
+					// 列表，你不需要
+					Status: src.Status.ToUint8(),
+					Ctime:  src.Ctime.Format(time.DateTime),
+					Utime:  src.Utime.Format(time.DateTime),
+				}
+			}),
+			NextCursor: domain.NextArticleCursor(arts, req.Limit).Encode(),
+		},
 	})
 }
 
@@ -189,7 +462,7 @@ func (h *ArticleHandler) Detail(ctx *gin.Context) {
 		return
 	}
 	uc := ctx.MustGet("user").(jwt.UserClaims)
-	if art.Author.Id != uc.Uid {
+	if !art.CanView(uc.Uid) {
 		// 有人在搞鬼
 		ctx.JSON(http.StatusOK, ginx.Result{
 			Msg:  "系统错误",
@@ -206,8 +479,9 @@ func (h *ArticleHandler) Detail(ctx *gin.Context) {
 		Title: art.Title,
 		//Abstract: art.Abstract(),
 
-		Content:  art.Content,
-		AuthorId: art.Author.Id,
+		Content:     art.Content,
+		AuthorId:    art.Author.Id,
+		MembersOnly: art.MembersOnly,
 		// 列表，你不需要
 		Status: art.Status.ToUint8(),
 		Ctime:  art.Ctime.Format(time.DateTime),
@@ -231,9 +505,10 @@ func (h *ArticleHandler) PubDetail(ctx *gin.Context) {
 	}
 
 	var (
-		eg   errgroup.Group
-		art  domain.Article
-		intr *intrv1.GetResponse
+		eg     errgroup.Group
+		art    domain.Article
+		intr   *intrv1.GetResponse
+		reward *rewardv1.GetTargetStatsResponse
 	)
 
 	uc := ctx.MustGet("user").(jwt.UserClaims)
@@ -249,9 +524,32 @@ func (h *ArticleHandler) PubDetail(ctx *gin.Context) {
 		})
 		return er
 	})
+	eg.Go(func() error {
+		rctx, cancel := context.WithTimeout(ctx, rewardStatsTimeout)
+		defer cancel()
+		res, er := h.reward.GetTargetStats(rctx, &rewardv1.GetTargetStatsRequest{
+			Biz: h.biz, BizId: id, N: 10,
+		})
+		if er != nil {
+			// 打赏的统计不是必须的，查不到或者超时了也可以看文章，只是不展示打赏
+			h.l.Warn("查询文章打赏统计失败",
+				logger.Int64("aid", id),
+				logger.Error(er))
+			return nil
+		}
+		reward = res
+		return nil
+	})
 
 	// 等待结果
 	err = eg.Wait()
+	if errors.Is(err, service.ErrMembersOnly) {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Msg:  "仅会员可见",
+			Code: 4,
+		})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusOK, ginx.Result{
 			Msg:  "系统错误",
@@ -282,7 +580,19 @@ func (h *ArticleHandler) PubDetail(ctx *gin.Context) {
 			Id:    art.Id,
 			Title: art.Title,
 
-			Content:    art.Content,
+			Content:        art.Content,
+			Abstract:       art.Abstract(),
+			Authors:        newAuthorVos(art),
+			Html:           art.Rendered.HTML,
+			ReadingMinutes: art.Rendered.ReadingMinutes,
+			Toc: slice.Map[domain.TocItem, TocItemVo](art.Rendered.Toc,
+				func(idx int, src domain.TocItem) TocItemVo {
+					return TocItemVo{
+						Level:  src.Level,
+						Anchor: src.Anchor,
+						Title:  src.Title,
+					}
+				}),
 			AuthorId:   art.Author.Id,
 			AuthorName: art.Author.Name,
 			ReadCnt:    intr.Intr.ReadCnt,
@@ -290,10 +600,14 @@ func (h *ArticleHandler) PubDetail(ctx *gin.Context) {
 			LikeCnt:    intr.Intr.LikeCnt,
 			Liked:      intr.Intr.Liked,
 			Collected:  intr.Intr.Collected,
+			Reward:     newRewardStatsVo(reward),
+
+			MembersOnly: art.MembersOnly,
 
 			Status: art.Status.ToUint8(),
 			Ctime:  art.Ctime.Format(time.DateTime),
 			Utime:  art.Utime.Format(time.DateTime),
+			Series: newSeriesNavVo(art.Series),
 		},
 	})
 }
@@ -353,6 +667,7 @@ func (h *ArticleHandler) Reward(ctx *gin.Context, req ArticleRewardReq, uc jwt.U
 		TargetUid: artResp.Author.Id,
 		Uid:       uc.Uid,
 		Amt:       req.Amt,
+		Currency:  req.Currency,
 	})
 	if err != nil {
 		return ginx.Result{Msg: "系统错误"}, err
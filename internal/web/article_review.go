@@ -0,0 +1,114 @@
+package web
+
+import (
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"gitee.com/geekbang/basic-go/webook/internal/service"
+	"gitee.com/geekbang/basic-go/webook/internal/web/jwt"
+	"gitee.com/geekbang/basic-go/webook/pkg/ginx"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"strconv"
+)
+
+type ArticleReviewHandler struct {
+	svc service.ArticleReviewService
+	l   logger.LoggerV1
+}
+
+func NewArticleReviewHandler(l logger.LoggerV1, svc service.ArticleReviewService) *ArticleReviewHandler {
+	return &ArticleReviewHandler{
+		l:   l,
+		svc: svc,
+	}
+}
+
+func (h *ArticleReviewHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/articles/review")
+
+	// 作者和合作者查看审核进度
+	g.GET("/detail/:id", ginx.WrapClaims(h.Detail))
+
+	// 审核的人
+	g.POST("/queue", ginx.WrapBodyAndClaims(h.Queue))
+	g.POST("/claim", ginx.WrapBodyAndClaims(h.Claim))
+	g.POST("/approve", ginx.WrapBodyAndClaims(h.Approve))
+	g.POST("/request_changes", ginx.WrapBodyAndClaims(h.RequestChanges))
+	g.POST("/reject", ginx.WrapBodyAndClaims(h.Reject))
+}
+
+func (h *ArticleReviewHandler) Detail(ctx *gin.Context, uc jwt.UserClaims) (ginx.Result, error) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return ginx.Result{Code: 4, Msg: "id 参数错误"}, err
+	}
+	r, err := h.svc.Detail(ctx, uc.Uid, id)
+	if errors.Is(err, service.ErrArticleNoPermission) {
+		return ginx.Result{Code: 4, Msg: "非法访问"}, err
+	}
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Data: newReviewVo(r)}, nil
+}
+
+func (h *ArticleReviewHandler) Queue(ctx *gin.Context,
+	req ReviewQueueReq, uc jwt.UserClaims) (ginx.Result, error) {
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 100
+	}
+	rs, err := h.svc.Queue(ctx, uc.Uid, req.Offset, req.Limit)
+	if errors.Is(err, service.ErrNotReviewer) {
+		return ginx.Result{Code: 4, Msg: "没有审核权限"}, err
+	}
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{
+		Data: slice.Map[domain.ArticleReview, ReviewVo](rs,
+			func(idx int, src domain.ArticleReview) ReviewVo {
+				return newReviewVo(src)
+			}),
+	}, nil
+}
+
+func (h *ArticleReviewHandler) Claim(ctx *gin.Context,
+	req ReviewClaimReq, uc jwt.UserClaims) (ginx.Result, error) {
+	return h.result(h.svc.Claim(ctx, uc.Uid, req.Id))
+}
+
+func (h *ArticleReviewHandler) Approve(ctx *gin.Context,
+	req ReviewDecisionReq, uc jwt.UserClaims) (ginx.Result, error) {
+	return h.result(h.svc.Approve(ctx, uc.Uid, req.Id, req.Comment))
+}
+
+func (h *ArticleReviewHandler) RequestChanges(ctx *gin.Context,
+	req ReviewDecisionReq, uc jwt.UserClaims) (ginx.Result, error) {
+	if req.Comment == "" {
+		return ginx.Result{Code: 4, Msg: "要告诉作者改什么"}, nil
+	}
+	return h.result(h.svc.RequestChanges(ctx, uc.Uid, req.Id, req.Comment))
+}
+
+func (h *ArticleReviewHandler) Reject(ctx *gin.Context,
+	req ReviewDecisionReq, uc jwt.UserClaims) (ginx.Result, error) {
+	if req.Comment == "" {
+		return ginx.Result{Code: 4, Msg: "要告诉作者为什么不通过"}, nil
+	}
+	return h.result(h.svc.Reject(ctx, uc.Uid, req.Id, req.Comment))
+}
+
+func (h *ArticleReviewHandler) result(err error) (ginx.Result, error) {
+	switch {
+	case errors.Is(err, service.ErrNotReviewer):
+		return ginx.Result{Code: 4, Msg: "没有审核权限"}, err
+	case errors.Is(err, service.ErrReviewStatusConflict):
+		return ginx.Result{Code: 4, Msg: "审核单的状态不对，或者不是你认领的"}, err
+	case errors.Is(err, service.ErrArticleNotReviewing):
+		return ginx.Result{Code: 4, Msg: "文章已经不在审核中了"}, err
+	case err != nil:
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
@@ -16,6 +16,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestArticleHandler_Publish(t *testing.T) {
@@ -57,6 +58,7 @@ func TestArticleHandler_Publish(t *testing.T) {
 			name: "修改并且发表成功",
 			mock: func(ctrl *gomock.Controller) service.ArticleService {
 				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().GetById(gomock.Any(), int64(1)).Return(domain.Article{Id: 1}, nil)
 				svc.EXPECT().Publish(gomock.Any(), domain.Article{
 					Id:      1,
 					Title:   "新的标题",
@@ -81,6 +83,35 @@ func TestArticleHandler_Publish(t *testing.T) {
 				Data: float64(1),
 			},
 		},
+		{
+			name: "定时发表成功",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().GetById(gomock.Any(), int64(1)).Return(domain.Article{Id: 1}, nil)
+				svc.EXPECT().SchedulePublish(gomock.Any(), domain.Article{
+					Id:      1,
+					Title:   "新的标题",
+					Content: "新的内容",
+					Author: domain.Author{
+						Id: 123,
+					},
+					PublishAt: time.UnixMilli(4102444800000),
+				}).Return(int64(1), nil)
+				return svc
+			},
+			reqBody: `
+{
+"id": 1,
+ "title": "新的标题",
+ "content": "新的内容",
+ "publishAt": 4102444800000
+}
+`,
+			wantCode: 200,
+			wantRes: ginx.Result{
+				Data: float64(1),
+			},
+		},
 		{
 			name: "输入有误",
 			mock: func(ctrl *gomock.Controller) service.ArticleService {
@@ -100,6 +131,7 @@ func TestArticleHandler_Publish(t *testing.T) {
 			name: "publish错误",
 			mock: func(ctrl *gomock.Controller) service.ArticleService {
 				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().GetById(gomock.Any(), int64(1)).Return(domain.Article{Id: 1}, nil)
 				svc.EXPECT().Publish(gomock.Any(), domain.Article{
 					Id:      1,
 					Title:   "新的标题",
@@ -127,6 +159,7 @@ func TestArticleHandler_Publish(t *testing.T) {
 		},
 	}
 
+	initGinxCounter()
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
@@ -134,7 +167,7 @@ func TestArticleHandler_Publish(t *testing.T) {
 
 			// 构造 handler
 			svc := tc.mock(ctrl)
-			hdl := NewArticleHandler(logger.NewNopLogger(), svc)
+			hdl := NewArticleHandler(logger.NewNopLogger(), svc, nil, nil)
 
 			// 准备服务器，注册路由
 			server := gin.Default()
@@ -166,3 +199,98 @@ func TestArticleHandler_Publish(t *testing.T) {
 		})
 	}
 }
+
+func TestArticleHandler_Edit(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) service.ArticleService
+
+		reqBody string
+		wantRes ginx.Result
+	}{
+		{
+			name: "新建，默认不是会员专享",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().Save(gomock.Any(), domain.Article{
+					Title:   "我的标题",
+					Content: "我的内容",
+					Author:  domain.Author{Id: 123},
+				}).Return(int64(1), nil)
+				return svc
+			},
+			reqBody: `{"title": "我的标题", "content": "我的内容"}`,
+			wantRes: ginx.Result{Data: float64(1)},
+		},
+		{
+			name: "修改，没传会员专享就保持原样",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().GetById(gomock.Any(), int64(1)).
+					Return(domain.Article{Id: 1, MembersOnly: true}, nil)
+				svc.EXPECT().Save(gomock.Any(), domain.Article{
+					Id:          1,
+					Title:       "新的标题",
+					Content:     "新的内容",
+					Author:      domain.Author{Id: 123},
+					MembersOnly: true,
+				}).Return(int64(1), nil)
+				return svc
+			},
+			reqBody: `{"id": 1, "title": "新的标题", "content": "新的内容"}`,
+			wantRes: ginx.Result{Data: float64(1)},
+		},
+		{
+			name: "修改，取消会员专享",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().Save(gomock.Any(), domain.Article{
+					Id:      1,
+					Title:   "新的标题",
+					Content: "新的内容",
+					Author:  domain.Author{Id: 123},
+				}).Return(int64(1), nil)
+				return svc
+			},
+			reqBody: `{"id": 1, "title": "新的标题", "content": "新的内容", "membersOnly": false}`,
+			wantRes: ginx.Result{Data: float64(1)},
+		},
+		{
+			name: "查询原来的文章失败",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().GetById(gomock.Any(), int64(1)).
+					Return(domain.Article{}, errors.New("mock error"))
+				return svc
+			},
+			reqBody: `{"id": 1, "title": "新的标题", "content": "新的内容"}`,
+			wantRes: ginx.Result{Code: 5, Msg: "系统错误"},
+		},
+	}
+	initGinxCounter()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			hdl := NewArticleHandler(logger.NewNopLogger(), tc.mock(ctrl), nil, nil)
+			server := gin.Default()
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("user", ijwt.UserClaims{
+					Uid: 123,
+				})
+			})
+			hdl.RegisterRoutes(server)
+			req, err := http.NewRequest(http.MethodPost,
+				"/articles/edit", bytes.NewReader([]byte(tc.reqBody)))
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res ginx.Result
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
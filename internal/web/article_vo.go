@@ -1,5 +1,12 @@
 package web
 
+import (
+	rewardv1 "gitee.com/geekbang/basic-go/webook/api/proto/gen/reward/v1"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+)
+
 type ArticleVo struct {
 	Id         int64  `json:"id,omitempty"`
 	Title      string `json:"title,omitempty"`
@@ -10,30 +17,263 @@ type ArticleVo struct {
 	Status     uint8  `json:"status,omitempty"`
 	Ctime      string `json:"ctime,omitempty"`
 	Utime      string `json:"utime,omitempty"`
+	// 定时发表的时间
+	PublishAt string `json:"publishAt,omitempty"`
+	// 只有作者的付费会员才能看
+	MembersOnly bool `json:"membersOnly,omitempty"`
+	// 下面是渲染之后的内容，只有读者查看的时候才有
+	Html           string      `json:"html,omitempty"`
+	Toc            []TocItemVo `json:"toc,omitempty"`
+	ReadingMinutes int         `json:"readingMinutes,omitempty"`
 
 	ReadCnt    int64 `json:"readCnt"`
 	LikeCnt    int64 `json:"likeCnt"`
 	CollectCnt int64 `json:"collectCnt"`
 	Liked      bool  `json:"liked"`
 	Collected  bool  `json:"collected"`
+
+	// 文章所在的系列，只有读者查看的时候才有
+	Series *SeriesNavVo `json:"series,omitempty"`
+	// 所有的作者，第一个是创作者本人，只有读者查看的时候才有
+	Authors []AuthorVo `json:"authors,omitempty"`
+	// 打赏的统计，只有读者查看的时候才有
+	Reward *RewardStatsVo `json:"reward,omitempty"`
+}
+
+type RewardStatsVo struct {
+	Currency     string `json:"currency"`
+	Cnt          int64  `json:"cnt"`
+	Amt          int64  `json:"amt"`
+	SupporterCnt int64  `json:"supporterCnt"`
+	// 打赏最多的人，金额从大到小
+	Supporters []SupporterVo `json:"supporters"`
+}
+
+type SupporterVo struct {
+	Uid int64 `json:"uid"`
+	Amt int64 `json:"amt"`
+}
+
+func newRewardStatsVo(st *rewardv1.GetTargetStatsResponse) *RewardStatsVo {
+	if st == nil {
+		return nil
+	}
+	return &RewardStatsVo{
+		Currency:     st.Currency,
+		Cnt:          st.Cnt,
+		Amt:          st.Amt,
+		SupporterCnt: st.SupporterCnt,
+		Supporters: slice.Map(st.Supporters, func(idx int, src *rewardv1.Supporter) SupporterVo {
+			return SupporterVo{Uid: src.Uid, Amt: src.Amt}
+		}),
+	}
+}
+
+type AuthorVo struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+func newAuthorVos(art domain.Article) []AuthorVo {
+	res := make([]AuthorVo, 0, len(art.Collaborators)+1)
+	res = append(res, AuthorVo{Id: art.Author.Id, Name: art.Author.Name})
+	for _, c := range art.Collaborators {
+		res = append(res, AuthorVo{Id: c.User.Id, Name: c.User.Name})
+	}
+	return res
+}
+
+type CollaboratorVo struct {
+	Uid int64 `json:"uid"`
+	// 1 编辑，2 审阅
+	Role uint8 `json:"role"`
+	// 1 已邀请，2 已接受
+	Status    uint8  `json:"status"`
+	InviterId int64  `json:"inviterId"`
+	Ctime     string `json:"ctime"`
+}
+
+func newCollaboratorVo(c domain.ArticleCollaborator) CollaboratorVo {
+	return CollaboratorVo{
+		Uid:       c.User.Id,
+		Role:      c.Role.ToUint8(),
+		Status:    c.Status.ToUint8(),
+		InviterId: c.InviterId,
+		Ctime:     c.Ctime.Format(time.DateTime),
+	}
+}
+
+type ArticleListVo struct {
+	Articles []ArticleVo `json:"articles"`
+	// 为空说明没有下一页了
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+type TocItemVo struct {
+	Level  int    `json:"level"`
+	Anchor string `json:"anchor"`
+	Title  string `json:"title"`
+}
+
+type ArticleRevisionVo struct {
+	Id        int64  `json:"id"`
+	ArticleId int64  `json:"articleId"`
+	AuthorId  int64  `json:"authorId"`
+	Title     string `json:"title"`
+	Content   string `json:"content,omitempty"`
+	Status    uint8  `json:"status"`
+	Ctime     string `json:"ctime"`
+}
+
+func newArticleRevisionVo(rev domain.ArticleRevision) ArticleRevisionVo {
+	return ArticleRevisionVo{
+		Id:        rev.Id,
+		ArticleId: rev.ArticleId,
+		AuthorId:  rev.Author.Id,
+		Title:     rev.Title,
+		Content:   rev.Content,
+		Status:    rev.Status.ToUint8(),
+		Ctime:     rev.Ctime.Format(time.DateTime),
+	}
+}
+
+type DiffLineVo struct {
+	// 0 没有变化，1 新增，2 删除
+	Op   uint8  `json:"op"`
+	Text string `json:"text"`
+}
+
+type ArticleRevisionDiffVo struct {
+	From    ArticleRevisionVo `json:"from"`
+	To      ArticleRevisionVo `json:"to"`
+	Title   []DiffLineVo      `json:"title"`
+	Content []DiffLineVo      `json:"content"`
 }
 
 type PublishReq struct {
 	Id      int64
 	Title   string `json:"title"`
 	Content string `json:"content"`
+	// 定时发表的时间，毫秒数。不传就是立刻发表
+	PublishAt int64 `json:"publishAt"`
+	// 只有付费会员才能看，不传的话修改文章的时候保持原样
+	MembersOnly *bool `json:"membersOnly"`
+}
+
+type ArticleRescheduleReq struct {
+	Id int64 `json:"id"`
+	// 新的发表时间，毫秒数
+	PublishAt int64 `json:"publishAt"`
+}
+
+type ArticleCancelScheduleReq struct {
+	Id int64 `json:"id"`
+}
+
+type ArticleListReq struct {
+	// 上一页返回的 nextCursor，第一页不传
+	Cursor string `json:"cursor"`
+	Limit  int    `json:"limit"`
 }
 
 type ArticleEditReq struct {
 	Id      int64
 	Title   string `json:"title"`
 	Content string `json:"content"`
+	// MembersOnly 不传的话修改文章的时候保持原样
+	MembersOnly *bool `json:"membersOnly"`
 }
 
 type ArticleWithdrawReq struct {
 	Id int64
 }
 
+type ArticleRollbackReq struct {
+	Id  int64 `json:"id"`
+	Rid int64 `json:"rid"`
+	// 回滚之后是否立刻重新发表
+	Publish bool `json:"publish"`
+}
+
+type CollaboratorInviteReq struct {
+	// 文章 ID
+	Id int64 `json:"id"`
+	// 被邀请的人
+	Uid int64 `json:"uid"`
+	// 1 编辑，2 审阅
+	Role uint8 `json:"role"`
+}
+
+type CollaboratorAcceptReq struct {
+	Id int64 `json:"id"`
+}
+
+type CollaboratorRemoveReq struct {
+	Id  int64 `json:"id"`
+	Uid int64 `json:"uid"`
+}
+
+type ReviewQueueReq struct {
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+type ReviewClaimReq struct {
+	Id int64 `json:"id"`
+}
+
+type ReviewDecisionReq struct {
+	// 文章 ID
+	Id      int64  `json:"id"`
+	Comment string `json:"comment"`
+}
+
+type ReviewVo struct {
+	Id         int64 `json:"id"`
+	AuthorId   int64 `json:"authorId"`
+	ReviewerId int64 `json:"reviewerId"`
+	// 1 已提交，2 审核中，3 需要修改，4 通过，5 不通过
+	Status   uint8             `json:"status"`
+	Title    string            `json:"title"`
+	Content  string            `json:"content"`
+	Comments []ReviewCommentVo `json:"comments,omitempty"`
+	Ctime    string            `json:"ctime"`
+	Utime    string            `json:"utime"`
+}
+
+type ReviewCommentVo struct {
+	ReviewerId int64  `json:"reviewerId"`
+	Status     uint8  `json:"status"`
+	Comment    string `json:"comment"`
+	Ctime      string `json:"ctime"`
+}
+
+func newReviewVo(r domain.ArticleReview) ReviewVo {
+	if r.Status == domain.ReviewStatusUnknown {
+		// 没有提交过审核
+		return ReviewVo{Id: r.ArticleId}
+	}
+	return ReviewVo{
+		Id:         r.ArticleId,
+		AuthorId:   r.AuthorId,
+		ReviewerId: r.ReviewerId,
+		Status:     r.Status.ToUint8(),
+		Title:      r.Title,
+		Content:    r.Content,
+		Comments: slice.Map[domain.ReviewComment, ReviewCommentVo](r.Comments,
+			func(idx int, src domain.ReviewComment) ReviewCommentVo {
+				return ReviewCommentVo{
+					ReviewerId: src.ReviewerId,
+					Status:     src.Status.ToUint8(),
+					Comment:    src.Comment,
+					Ctime:      src.Ctime.Format(time.DateTime),
+				}
+			}),
+		Ctime: r.Ctime.Format(time.DateTime),
+		Utime: r.Utime.Format(time.DateTime),
+	}
+}
+
 type ArticleLikeReq struct {
 	Id int64 `json:"id"`
 	// true 是点赞，false 是不点赞
@@ -48,4 +288,57 @@ type ArticleCollectReq struct {
 type ArticleRewardReq struct {
 	Id  int64 `json:"id"`
 	Amt int64 `json:"amt"`
+	// Currency 可以不传，默认是作者的币种
+	Currency string `json:"currency"`
+}
+
+type SeriesVo struct {
+	Id          int64       `json:"id"`
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	AuthorId    int64       `json:"authorId"`
+	AuthorName  string      `json:"authorName,omitempty"`
+	Articles    []ArticleVo `json:"articles,omitempty"`
+	Ctime       string      `json:"ctime"`
+	Utime       string      `json:"utime"`
+}
+
+// SeriesNavVo 读者看文章的时候，所属的系列以及上一篇、下一篇
+type SeriesNavVo struct {
+	Id        int64  `json:"id"`
+	Title     string `json:"title"`
+	PrevId    int64  `json:"prevId,omitempty"`
+	PrevTitle string `json:"prevTitle,omitempty"`
+	NextId    int64  `json:"nextId,omitempty"`
+	NextTitle string `json:"nextTitle,omitempty"`
+}
+
+func newSeriesNavVo(nav domain.SeriesNav) *SeriesNavVo {
+	if nav.SeriesId == 0 {
+		return nil
+	}
+	return &SeriesNavVo{
+		Id:        nav.SeriesId,
+		Title:     nav.SeriesTitle,
+		PrevId:    nav.Prev.Id,
+		PrevTitle: nav.Prev.Title,
+		NextId:    nav.Next.Id,
+		NextTitle: nav.Next.Title,
+	}
+}
+
+type SeriesEditReq struct {
+	Id          int64  `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+type SeriesDeleteReq struct {
+	Id int64 `json:"id"`
+}
+
+type SeriesArticlesReq struct {
+	Id int64 `json:"id"`
+	// 按照章节顺序排列的文章 ID
+	Aids []int64 `json:"aids"`
 }
@@ -0,0 +1,220 @@
+package web
+
+import (
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"gitee.com/geekbang/basic-go/webook/internal/service"
+	"gitee.com/geekbang/basic-go/webook/internal/web/middleware"
+	"gitee.com/geekbang/basic-go/webook/pkg/ginx"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+)
+
+// JobHandler 给管理员管理分布式任务
+type JobHandler struct {
+	svc service.CronJobService
+	l   logger.LoggerV1
+}
+
+func NewJobHandler(l logger.LoggerV1, svc service.CronJobService) *JobHandler {
+	return &JobHandler{
+		l:   l,
+		svc: svc,
+	}
+}
+
+func (h *JobHandler) RegisterRoutes(server *gin.Engine) {
+	// 登录校验在全局的 middleware 里面，这里只需要校验是不是管理员
+	g := server.Group("/admin/jobs", middleware.NewAdminMiddlewareBuilder().CheckAdmin())
+	g.POST("/list", ginx.WrapBody(h.List))
+	g.GET("/detail/:id", ginx.Wrap(h.Detail))
+	g.POST("/create", ginx.WrapBody(h.Create))
+	g.POST("/update", ginx.WrapBody(h.Update))
+	g.POST("/pause", ginx.WrapBody(h.Pause))
+	g.POST("/resume", ginx.WrapBody(h.Resume))
+	g.POST("/trigger", ginx.WrapBody(h.Trigger))
+	g.POST("/delete", ginx.WrapBody(h.Delete))
+	g.POST("/runs", ginx.WrapBody(h.ListRuns))
+	g.POST("/upstreams", ginx.WrapBody(h.SetUpstreams))
+	g.GET("/graph", ginx.Wrap(h.RunGraph))
+	g.GET("/nodes", ginx.Wrap(h.ListNodes))
+}
+
+func (h *JobHandler) List(ctx *gin.Context, req JobListReq) (ginx.Result, error) {
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 100
+	}
+	var (
+		js  []domain.Job
+		err error
+	)
+	if req.Status == nil {
+		js, err = h.svc.List(ctx, req.Offset, req.Limit)
+	} else {
+		js, err = h.svc.ListByStatus(ctx, domain.JobStatus(*req.Status), req.Offset, req.Limit)
+	}
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{
+		Data: slice.Map[domain.Job, JobVo](js, func(idx int, src domain.Job) JobVo {
+			return newJobVo(src)
+		}),
+	}, nil
+}
+
+func (h *JobHandler) Detail(ctx *gin.Context) (ginx.Result, error) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return ginx.Result{Code: 4, Msg: "id 参数错误"}, err
+	}
+	j, err := h.svc.GetById(ctx, id)
+	if errors.Is(err, service.ErrJobNotFound) {
+		return ginx.Result{Code: 4, Msg: "任务不存在"}, err
+	}
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Data: newJobVo(j)}, nil
+}
+
+func (h *JobHandler) Create(ctx *gin.Context, req JobEditReq) (ginx.Result, error) {
+	if req.Name == "" || req.Executor == "" {
+		return ginx.Result{Code: 4, Msg: "名字和执行器不能为空"}, nil
+	}
+	id, err := h.svc.Create(ctx, req.toDomain())
+	return h.editResult(id, err)
+}
+
+func (h *JobHandler) Update(ctx *gin.Context, req JobEditReq) (ginx.Result, error) {
+	if req.Executor == "" {
+		return ginx.Result{Code: 4, Msg: "执行器不能为空"}, nil
+	}
+	err := h.svc.Update(ctx, req.toDomain())
+	return h.editResult(req.Id, err)
+}
+
+func (h *JobHandler) editResult(id int64, err error) (ginx.Result, error) {
+	switch {
+	case err == nil:
+		return ginx.Result{Data: id}, nil
+	case errors.Is(err, service.ErrInvalidCronExpression):
+		return ginx.Result{Code: 4, Msg: "Cron 表达式不对"}, err
+	case errors.Is(err, service.ErrDuplicateJob):
+		return ginx.Result{Code: 4, Msg: "任务名字冲突"}, err
+	case errors.Is(err, service.ErrJobNotFound):
+		return ginx.Result{Code: 4, Msg: "任务不存在"}, err
+	default:
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+}
+
+func (h *JobHandler) Pause(ctx *gin.Context, req JobIdReq) (ginx.Result, error) {
+	return h.statusResult(h.svc.Pause(ctx, req.Id))
+}
+
+func (h *JobHandler) Resume(ctx *gin.Context, req JobIdReq) (ginx.Result, error) {
+	return h.statusResult(h.svc.Resume(ctx, req.Id))
+}
+
+func (h *JobHandler) Trigger(ctx *gin.Context, req JobTriggerReq) (ginx.Result, error) {
+	if req.LogicalDate != "" {
+		if _, err := time.Parse(domain.JobLogicalDateLayout, req.LogicalDate); err != nil {
+			return ginx.Result{Code: 4, Msg: "逻辑日期格式不对"}, err
+		}
+	}
+	return h.statusResult(h.svc.Trigger(ctx, req.Id, req.LogicalDate))
+}
+
+func (h *JobHandler) Delete(ctx *gin.Context, req JobIdReq) (ginx.Result, error) {
+	return h.statusResult(h.svc.Delete(ctx, req.Id))
+}
+
+// statusResult 任务不存在也当作状态不对
+func (h *JobHandler) statusResult(err error) (ginx.Result, error) {
+	if errors.Is(err, service.ErrJobStatusConflict) {
+		return ginx.Result{Code: 4, Msg: "任务不存在或者状态不对"}, err
+	}
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
+
+func (h *JobHandler) ListRuns(ctx *gin.Context, req JobRunListReq) (ginx.Result, error) {
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 100
+	}
+	rs, err := h.svc.ListRuns(ctx, req.Id, req.Offset, req.Limit)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{
+		Data: slice.Map[domain.JobRun, JobRunVo](rs, func(idx int, src domain.JobRun) JobRunVo {
+			return newJobRunVo(src)
+		}),
+	}, nil
+}
+
+func (h *JobHandler) SetUpstreams(ctx *gin.Context, req JobUpstreamsReq) (ginx.Result, error) {
+	err := h.svc.SetUpstreams(ctx, req.Id, req.Upstreams)
+	switch {
+	case err == nil:
+		return ginx.Result{Msg: "OK"}, nil
+	case errors.Is(err, service.ErrJobDependencyCycle):
+		return ginx.Result{Code: 4, Msg: "依赖关系形成了环"}, err
+	case errors.Is(err, service.ErrJobNotFound):
+		return ginx.Result{Code: 4, Msg: "上游任务不存在"}, err
+	default:
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+}
+
+// RunGraph date 不传就是今天
+func (h *JobHandler) RunGraph(ctx *gin.Context) (ginx.Result, error) {
+	date := ctx.Query("date")
+	if date == "" {
+		date = time.Now().Format(domain.JobLogicalDateLayout)
+	} else if _, err := time.Parse(domain.JobLogicalDateLayout, date); err != nil {
+		return ginx.Result{Code: 4, Msg: "逻辑日期格式不对"}, err
+	}
+	graph, err := h.svc.RunGraph(ctx, date)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Data: newJobRunGraphVo(graph)}, nil
+}
+
+func (h *JobHandler) ListNodes(ctx *gin.Context) (ginx.Result, error) {
+	ns, err := h.svc.ListNodes(ctx)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{
+		Data: slice.Map[domain.JobNode, JobNodeVo](ns, func(idx int, src domain.JobNode) JobNodeVo {
+			return newJobNodeVo(src)
+		}),
+	}, nil
+}
+
+func (r JobEditReq) toDomain() domain.Job {
+	return domain.Job{
+		Id:          r.Id,
+		Name:        r.Name,
+		Executor:    r.Executor,
+		Target:      r.Target,
+		Expression:  r.Expression,
+		Cfg:         r.Cfg,
+		MaxRetries:  r.MaxRetries,
+		Backoff:     time.Duration(r.Backoff) * time.Millisecond,
+		Timeout:     time.Duration(r.Timeout) * time.Millisecond,
+		MaxFailures: r.MaxFailures,
+
+		UpstreamPolicy: domain.JobUpstreamPolicy(r.UpstreamPolicy),
+		Label:          r.Label,
+		Weight:         r.Weight,
+	}
+}
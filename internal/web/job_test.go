@@ -0,0 +1,237 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"gitee.com/geekbang/basic-go/webook/internal/service"
+	svcmocks "gitee.com/geekbang/basic-go/webook/internal/service/mocks"
+	ijwt "gitee.com/geekbang/basic-go/webook/internal/web/jwt"
+	"gitee.com/geekbang/basic-go/webook/pkg/ginx"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestJobHandler(t *testing.T) {
+	admin := ijwt.UserClaims{Uid: 1, Role: domain.UserRoleAdmin.ToUint8()}
+	testCases := []struct {
+		name   string
+		mock   func(ctrl *gomock.Controller) service.CronJobService
+		claims ijwt.UserClaims
+
+		path     string
+		reqBody  string
+		wantCode int
+		wantRes  ginx.Result
+	}{
+		{
+			name: "创建任务",
+			mock: func(ctrl *gomock.Controller) service.CronJobService {
+				svc := svcmocks.NewMockCronJobService(ctrl)
+				svc.EXPECT().Create(gomock.Any(), domain.Job{
+					Name:       "my_job",
+					Executor:   "http",
+					Expression: "*/5 * * * * ?",
+				}).Return(int64(1), nil)
+				return svc
+			},
+			claims:   admin,
+			path:     "/admin/jobs/create",
+			reqBody:  `{"name":"my_job","executor":"http","expression":"*/5 * * * * ?"}`,
+			wantCode: http.StatusOK,
+			wantRes:  ginx.Result{Data: float64(1)},
+		},
+		{
+			name: "创建任务没有名字",
+			mock: func(ctrl *gomock.Controller) service.CronJobService {
+				return svcmocks.NewMockCronJobService(ctrl)
+			},
+			claims:   admin,
+			path:     "/admin/jobs/create",
+			reqBody:  `{"executor":"http"}`,
+			wantCode: http.StatusOK,
+			wantRes:  ginx.Result{Code: 4, Msg: "名字和执行器不能为空"},
+		},
+		{
+			name: "Cron 表达式不对",
+			mock: func(ctrl *gomock.Controller) service.CronJobService {
+				svc := svcmocks.NewMockCronJobService(ctrl)
+				svc.EXPECT().Create(gomock.Any(), gomock.Any()).
+					Return(int64(0), fmt.Errorf("%w: %w", service.ErrInvalidCronExpression, errors.New("mock")))
+				return svc
+			},
+			claims:   admin,
+			path:     "/admin/jobs/create",
+			reqBody:  `{"name":"my_job","executor":"http","expression":"abc"}`,
+			wantCode: http.StatusOK,
+			wantRes:  ginx.Result{Code: 4, Msg: "Cron 表达式不对"},
+		},
+		{
+			name: "任务名字冲突",
+			mock: func(ctrl *gomock.Controller) service.CronJobService {
+				svc := svcmocks.NewMockCronJobService(ctrl)
+				svc.EXPECT().Create(gomock.Any(), gomock.Any()).
+					Return(int64(0), service.ErrDuplicateJob)
+				return svc
+			},
+			claims:   admin,
+			path:     "/admin/jobs/create",
+			reqBody:  `{"name":"my_job","executor":"http"}`,
+			wantCode: http.StatusOK,
+			wantRes:  ginx.Result{Code: 4, Msg: "任务名字冲突"},
+		},
+		{
+			name: "修改不存在的任务",
+			mock: func(ctrl *gomock.Controller) service.CronJobService {
+				svc := svcmocks.NewMockCronJobService(ctrl)
+				svc.EXPECT().Update(gomock.Any(), gomock.Any()).
+					Return(service.ErrJobNotFound)
+				return svc
+			},
+			claims:   admin,
+			path:     "/admin/jobs/update",
+			reqBody:  `{"id":1,"executor":"http"}`,
+			wantCode: http.StatusOK,
+			wantRes:  ginx.Result{Code: 4, Msg: "任务不存在"},
+		},
+		{
+			name: "恢复任务",
+			mock: func(ctrl *gomock.Controller) service.CronJobService {
+				svc := svcmocks.NewMockCronJobService(ctrl)
+				svc.EXPECT().Resume(gomock.Any(), int64(1)).Return(nil)
+				return svc
+			},
+			claims:   admin,
+			path:     "/admin/jobs/resume",
+			reqBody:  `{"id":1}`,
+			wantCode: http.StatusOK,
+			wantRes:  ginx.Result{Msg: "OK"},
+		},
+		{
+			name: "暂停的时候状态不对",
+			mock: func(ctrl *gomock.Controller) service.CronJobService {
+				svc := svcmocks.NewMockCronJobService(ctrl)
+				svc.EXPECT().Pause(gomock.Any(), int64(1)).Return(service.ErrJobStatusConflict)
+				return svc
+			},
+			claims:   admin,
+			path:     "/admin/jobs/pause",
+			reqBody:  `{"id":1}`,
+			wantCode: http.StatusOK,
+			wantRes:  ginx.Result{Code: 4, Msg: "任务不存在或者状态不对"},
+		},
+		{
+			name: "补跑的逻辑日期格式不对",
+			mock: func(ctrl *gomock.Controller) service.CronJobService {
+				return svcmocks.NewMockCronJobService(ctrl)
+			},
+			claims:   admin,
+			path:     "/admin/jobs/trigger",
+			reqBody:  `{"id":1,"logicalDate":"2023-11-15"}`,
+			wantCode: http.StatusOK,
+			wantRes:  ginx.Result{Code: 4, Msg: "逻辑日期格式不对"},
+		},
+		{
+			name: "补跑",
+			mock: func(ctrl *gomock.Controller) service.CronJobService {
+				svc := svcmocks.NewMockCronJobService(ctrl)
+				svc.EXPECT().Trigger(gomock.Any(), int64(1), "20231115").Return(nil)
+				return svc
+			},
+			claims:   admin,
+			path:     "/admin/jobs/trigger",
+			reqBody:  `{"id":1,"logicalDate":"20231115"}`,
+			wantCode: http.StatusOK,
+			wantRes:  ginx.Result{Msg: "OK"},
+		},
+		{
+			name: "删除的时候系统错误",
+			mock: func(ctrl *gomock.Controller) service.CronJobService {
+				svc := svcmocks.NewMockCronJobService(ctrl)
+				svc.EXPECT().Delete(gomock.Any(), int64(1)).Return(errors.New("mock db error"))
+				return svc
+			},
+			claims:   admin,
+			path:     "/admin/jobs/delete",
+			reqBody:  `{"id":1}`,
+			wantCode: http.StatusOK,
+			wantRes:  ginx.Result{Code: 5, Msg: "系统错误"},
+		},
+		{
+			name: "依赖关系形成了环",
+			mock: func(ctrl *gomock.Controller) service.CronJobService {
+				svc := svcmocks.NewMockCronJobService(ctrl)
+				svc.EXPECT().SetUpstreams(gomock.Any(), int64(1), []int64{2}).
+					Return(service.ErrJobDependencyCycle)
+				return svc
+			},
+			claims:   admin,
+			path:     "/admin/jobs/upstreams",
+			reqBody:  `{"id":1,"upstreams":[2]}`,
+			wantCode: http.StatusOK,
+			wantRes:  ginx.Result{Code: 4, Msg: "依赖关系形成了环"},
+		},
+		{
+			name: "不是管理员",
+			mock: func(ctrl *gomock.Controller) service.CronJobService {
+				return svcmocks.NewMockCronJobService(ctrl)
+			},
+			claims:   ijwt.UserClaims{Uid: 2},
+			path:     "/admin/jobs/pause",
+			reqBody:  `{"id":1}`,
+			wantCode: http.StatusForbidden,
+		},
+	}
+	initGinxCounter()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			hdl := NewJobHandler(logger.NewNopLogger(), tc.mock(ctrl))
+
+			server := gin.Default()
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("user", tc.claims)
+			})
+			hdl.RegisterRoutes(server)
+
+			req, err := http.NewRequest(http.MethodPost, tc.path,
+				bytes.NewReader([]byte(tc.reqBody)))
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+			assert.Equal(t, tc.wantCode, recorder.Code)
+			if recorder.Code != http.StatusOK {
+				return
+			}
+			var res ginx.Result
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+var ginxCounterOnce sync.Once
+
+// initGinxCounter ginx 统计业务错误码的 vector 是在 ioc 里面初始化的，
+// 测试里面不初始化就会 panic。重复注册也会 panic，所以只初始化一次
+func initGinxCounter() {
+	ginxCounterOnce.Do(func() {
+		ginx.InitCounter(prometheus.CounterOpts{
+			Namespace: "webook_test",
+			Name:      "biz_code",
+			Help:      "统计业务错误码",
+		})
+	})
+}
@@ -0,0 +1,184 @@
+package web
+
+import (
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+)
+
+type JobListReq struct {
+	// Status 不传就是所有的任务
+	Status *uint8 `json:"status"`
+	Offset int    `json:"offset"`
+	Limit  int    `json:"limit"`
+}
+
+type JobRunListReq struct {
+	// Id 任务的 ID
+	Id     int64 `json:"id"`
+	Offset int   `json:"offset"`
+	Limit  int   `json:"limit"`
+}
+
+type JobIdReq struct {
+	Id int64 `json:"id"`
+}
+
+type JobTriggerReq struct {
+	Id int64 `json:"id"`
+	// LogicalDate 补跑哪一天，格式是 20060102，不传就是下一次调度的那天
+	LogicalDate string `json:"logicalDate"`
+}
+
+type JobUpstreamsReq struct {
+	Id int64 `json:"id"`
+	// Upstreams 全部的上游，传空就是去掉所有的依赖
+	Upstreams []int64 `json:"upstreams"`
+}
+
+// JobEditReq 创建的时候不需要 Id，修改的时候 Name 不能改
+type JobEditReq struct {
+	Id         int64  `json:"id"`
+	Name       string `json:"name"`
+	Executor   string `json:"executor"`
+	Target     string `json:"target"`
+	Expression string `json:"expression"`
+	Cfg        string `json:"cfg"`
+	MaxRetries int    `json:"maxRetries"`
+	// Backoff 和 Timeout 都是毫秒数
+	Backoff     int64 `json:"backoff"`
+	Timeout     int64 `json:"timeout"`
+	MaxFailures int   `json:"maxFailures"`
+	// UpstreamPolicy 0-上游失败就等待 1-上游失败就跳过
+	UpstreamPolicy uint8 `json:"upstreamPolicy"`
+	// Label 只有带了这个标签的节点能执行，不传就是哪个节点都可以
+	Label  string `json:"label"`
+	Weight int64  `json:"weight"`
+}
+
+type JobVo struct {
+	Id         int64  `json:"id"`
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+	Executor   string `json:"executor"`
+	Target     string `json:"target"`
+	Cfg        string `json:"cfg"`
+	Status     uint8  `json:"status"`
+	// Owner 正在执行这个任务的节点
+	Owner       string `json:"owner"`
+	NextTime    string `json:"nextTime"`
+	LogicalDate string `json:"logicalDate"`
+	MaxRetries  int    `json:"maxRetries"`
+	Backoff     int64  `json:"backoff"`
+	Timeout     int64  `json:"timeout"`
+	MaxFailures int    `json:"maxFailures"`
+	Failures    int    `json:"failures"`
+	Ctime       string `json:"ctime"`
+	Utime       string `json:"utime"`
+	// UpstreamPolicy 0-上游失败就等待 1-上游失败就跳过
+	UpstreamPolicy uint8  `json:"upstreamPolicy"`
+	Label          string `json:"label"`
+	Weight         int64  `json:"weight"`
+}
+
+func newJobVo(j domain.Job) JobVo {
+	return JobVo{
+		Id:          j.Id,
+		Name:        j.Name,
+		Expression:  j.Expression,
+		Executor:    j.Executor,
+		Target:      j.Target,
+		Cfg:         j.Cfg,
+		Status:      j.Status.ToUint8(),
+		Owner:       j.Owner,
+		NextTime:    j.NextRunAt.Format(time.DateTime),
+		LogicalDate: j.LogicalDate,
+		MaxRetries:  j.MaxRetries,
+		Backoff:     j.Backoff.Milliseconds(),
+		Timeout:     j.Timeout.Milliseconds(),
+		MaxFailures: j.MaxFailures,
+		Failures:    j.Failures,
+		Ctime:       j.Ctime.Format(time.DateTime),
+		Utime:       j.Utime.Format(time.DateTime),
+
+		UpstreamPolicy: j.UpstreamPolicy.ToUint8(),
+		Label:          j.Label,
+		Weight:         j.Cost(),
+	}
+}
+
+type JobRunVo struct {
+	Id      int64  `json:"id"`
+	Node    string `json:"node"`
+	Attempt int    `json:"attempt"`
+	Start   string `json:"start"`
+	End     string `json:"end"`
+	Result  uint8  `json:"result"`
+	Err     string `json:"err"`
+}
+
+func newJobRunVo(r domain.JobRun) JobRunVo {
+	return JobRunVo{
+		Id:      r.Id,
+		Node:    r.Node,
+		Attempt: r.Attempt,
+		Start:   r.Start.Format(time.DateTime),
+		End:     r.End.Format(time.DateTime),
+		Result:  r.Result.ToUint8(),
+		Err:     r.Err,
+	}
+}
+
+type JobRunGraphVo struct {
+	LogicalDate string            `json:"logicalDate"`
+	Nodes       []JobRunNodeVo    `json:"nodes"`
+	Edges       []JobDependencyVo `json:"edges"`
+}
+
+type JobRunNodeVo struct {
+	Job JobVo `json:"job"`
+	// Status 这个逻辑日期的结果 0-还没有执行 1-成功 2-失败 3-跳过
+	Status uint8 `json:"status"`
+}
+
+type JobDependencyVo struct {
+	JobId      int64 `json:"jobId"`
+	UpstreamId int64 `json:"upstreamId"`
+}
+
+func newJobRunGraphVo(g domain.JobRunGraph) JobRunGraphVo {
+	return JobRunGraphVo{
+		LogicalDate: g.LogicalDate,
+		Nodes: slice.Map(g.Nodes, func(idx int, src domain.JobRunNode) JobRunNodeVo {
+			return JobRunNodeVo{
+				Job:    newJobVo(src.Job),
+				Status: src.Status.ToUint8(),
+			}
+		}),
+		Edges: slice.Map(g.Edges, func(idx int, src domain.JobDependency) JobDependencyVo {
+			return JobDependencyVo{
+				JobId:      src.JobId,
+				UpstreamId: src.UpstreamId,
+			}
+		}),
+	}
+}
+
+type JobNodeVo struct {
+	Name     string   `json:"name"`
+	Labels   []string `json:"labels"`
+	Capacity int64    `json:"capacity"`
+	Load     int64    `json:"load"`
+	// Heartbeat 最后一次心跳的时间
+	Heartbeat string `json:"heartbeat"`
+}
+
+func newJobNodeVo(n domain.JobNode) JobNodeVo {
+	return JobNodeVo{
+		Name:      n.Name,
+		Labels:    n.Labels,
+		Capacity:  n.Capacity,
+		Load:      n.Load,
+		Heartbeat: n.Utime.Format(time.DateTime),
+	}
+}
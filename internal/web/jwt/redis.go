@@ -3,6 +3,7 @@ package jwt
 import (
 	"errors"
 	"fmt"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -52,13 +53,13 @@ func (h *RedisJWTHandler) ExtractToken(ctx *gin.Context) string {
 
 var _ Handler = &RedisJWTHandler{}
 
-func (h *RedisJWTHandler) SetLoginToken(ctx *gin.Context, uid int64) error {
+func (h *RedisJWTHandler) SetLoginToken(ctx *gin.Context, uid int64, role uint8) error {
 	ssid := uuid.New().String()
-	err := h.setRefreshToken(ctx, uid, ssid)
+	err := h.setRefreshToken(ctx, uid, role, ssid)
 	if err != nil {
 		return err
 	}
-	return h.SetJWTToken(ctx, uid, ssid)
+	return h.SetJWTToken(ctx, uid, role, ssid)
 }
 
 func (h *RedisJWTHandler) ClearToken(ctx *gin.Context) error {
@@ -71,9 +72,10 @@ func (h *RedisJWTHandler) ClearToken(ctx *gin.Context) error {
 		"", h.rcExpiration).Err()
 }
 
-func (h *RedisJWTHandler) SetJWTToken(ctx *gin.Context, uid int64, ssid string) error {
+func (h *RedisJWTHandler) SetJWTToken(ctx *gin.Context, uid int64, role uint8, ssid string) error {
 	uc := UserClaims{
 		Uid:       uid,
+		Role:      role,
 		Ssid:      ssid,
 		UserAgent: ctx.GetHeader("User-Agent"),
 		RegisteredClaims: jwt.RegisteredClaims{
@@ -90,9 +92,10 @@ func (h *RedisJWTHandler) SetJWTToken(ctx *gin.Context, uid int64, ssid string)
 	return nil
 }
 
-func (h *RedisJWTHandler) setRefreshToken(ctx *gin.Context, uid int64, ssid string) error {
+func (h *RedisJWTHandler) setRefreshToken(ctx *gin.Context, uid int64, role uint8, ssid string) error {
 	rc := RefreshClaims{
 		Uid:  uid,
+		Role: role,
 		Ssid: ssid,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(h.rcExpiration)),
@@ -113,12 +116,19 @@ var RCJWTKey = []byte("k6CswdUm77WKcbM68UQUuxVsHSpTCwgA")
 type RefreshClaims struct {
 	jwt.RegisteredClaims
 	Uid  int64
+	Role uint8
 	Ssid string
 }
 
 type UserClaims struct {
 	jwt.RegisteredClaims
-	Uid       int64
+	Uid int64
+	// Role 登录时候的角色，修改了角色要重新登录才生效
+	Role      uint8
 	Ssid      string
 	UserAgent string
 }
+
+func (uc UserClaims) IsAdmin() bool {
+	return uc.Role == domain.UserRoleAdmin.ToUint8()
+}
@@ -5,7 +5,8 @@ import "github.com/gin-gonic/gin"
 type Handler interface {
 	ClearToken(ctx *gin.Context) error
 	ExtractToken(ctx *gin.Context) string
-	SetLoginToken(ctx *gin.Context, uid int64) error
-	SetJWTToken(ctx *gin.Context, uid int64, ssid string) error
+	// SetLoginToken role 是用户的角色，刷新 token 的时候沿用
+	SetLoginToken(ctx *gin.Context, uid int64, role uint8) error
+	SetJWTToken(ctx *gin.Context, uid int64, role uint8, ssid string) error
 	CheckSession(ctx *gin.Context, ssid string) error
 }
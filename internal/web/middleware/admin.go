@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	ijwt "gitee.com/geekbang/basic-go/webook/internal/web/jwt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type AdminMiddlewareBuilder struct {
+}
+
+func NewAdminMiddlewareBuilder() *AdminMiddlewareBuilder {
+	return &AdminMiddlewareBuilder{}
+}
+
+// CheckAdmin 要放在登录校验后面，只有管理员才能访问
+func (m *AdminMiddlewareBuilder) CheckAdmin() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		val, ok := ctx.Get("user")
+		if !ok {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		uc, ok := val.(ijwt.UserClaims)
+		if !ok || !uc.IsAdmin() {
+			ctx.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+	}
+}
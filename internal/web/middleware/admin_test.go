@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	ijwt "gitee.com/geekbang/basic-go/webook/internal/web/jwt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminMiddlewareBuilder_CheckAdmin(t *testing.T) {
+	testCases := []struct {
+		name   string
+		claims any
+
+		wantCode int
+	}{
+		{
+			name: "管理员",
+			claims: ijwt.UserClaims{
+				Uid:  1,
+				Role: domain.UserRoleAdmin.ToUint8(),
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "没有登录",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "普通用户",
+			claims:   ijwt.UserClaims{Uid: 1},
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "类型不对",
+			claims:   "admin",
+			wantCode: http.StatusForbidden,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := gin.New()
+			server.Use(func(ctx *gin.Context) {
+				if tc.claims != nil {
+					ctx.Set("user", tc.claims)
+				}
+			})
+			server.GET("/admin/test", NewAdminMiddlewareBuilder().CheckAdmin(),
+				func(ctx *gin.Context) {
+					ctx.String(http.StatusOK, "OK")
+				})
+			req, err := http.NewRequest(http.MethodGet, "/admin/test", nil)
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+			assert.Equal(t, tc.wantCode, recorder.Code)
+		})
+	}
+}
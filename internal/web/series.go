@@ -0,0 +1,161 @@
+package web
+
+import (
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"gitee.com/geekbang/basic-go/webook/internal/service"
+	"gitee.com/geekbang/basic-go/webook/internal/web/jwt"
+	"gitee.com/geekbang/basic-go/webook/pkg/ginx"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+)
+
+type SeriesHandler struct {
+	svc service.SeriesService
+	l   logger.LoggerV1
+}
+
+func NewSeriesHandler(l logger.LoggerV1, svc service.SeriesService) *SeriesHandler {
+	return &SeriesHandler{
+		l:   l,
+		svc: svc,
+	}
+}
+
+func (h *SeriesHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/series")
+
+	// 创作者接口
+	g.POST("/create", ginx.WrapBodyAndClaims(h.Create))
+	g.POST("/edit", ginx.WrapBodyAndClaims(h.Edit))
+	g.POST("/delete", ginx.WrapBodyAndClaims(h.Delete))
+	g.POST("/articles", ginx.WrapBodyAndClaims(h.SetArticles))
+	g.GET("/detail/:id", ginx.WrapClaims(h.Detail))
+	g.GET("/list", ginx.WrapClaims(h.List))
+
+	// 读者接口
+	g.GET("/pub/:id", ginx.Wrap(h.PubDetail))
+}
+
+func (h *SeriesHandler) Create(ctx *gin.Context,
+	req SeriesEditReq, uc jwt.UserClaims) (ginx.Result, error) {
+	if req.Title == "" {
+		return ginx.Result{Code: 4, Msg: "标题不能为空"}, nil
+	}
+	id, err := h.svc.Create(ctx, domain.Series{
+		Title:       req.Title,
+		Description: req.Description,
+		Author: domain.Author{
+			Id: uc.Uid,
+		},
+	})
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Data: id}, nil
+}
+
+func (h *SeriesHandler) Edit(ctx *gin.Context,
+	req SeriesEditReq, uc jwt.UserClaims) (ginx.Result, error) {
+	if req.Title == "" {
+		return ginx.Result{Code: 4, Msg: "标题不能为空"}, nil
+	}
+	err := h.svc.Update(ctx, domain.Series{
+		Id:          req.Id,
+		Title:       req.Title,
+		Description: req.Description,
+		Author: domain.Author{
+			Id: uc.Uid,
+		},
+	})
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
+
+func (h *SeriesHandler) Delete(ctx *gin.Context,
+	req SeriesDeleteReq, uc jwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.Delete(ctx, uc.Uid, req.Id)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
+
+func (h *SeriesHandler) SetArticles(ctx *gin.Context,
+	req SeriesArticlesReq, uc jwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.SetArticles(ctx, uc.Uid, req.Id, req.Aids)
+	if errors.Is(err, service.ErrSeriesArticleInvalid) {
+		return ginx.Result{Code: 4, Msg: "文章不存在，重复，或者不是你的"}, err
+	}
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
+
+func (h *SeriesHandler) Detail(ctx *gin.Context, uc jwt.UserClaims) (ginx.Result, error) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return ginx.Result{Code: 4, Msg: "id 参数错误"}, err
+	}
+	s, err := h.svc.GetById(ctx, uc.Uid, id)
+	if errors.Is(err, service.ErrIllegalSeries) {
+		return ginx.Result{Code: 4, Msg: "非法访问"}, err
+	}
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Data: newSeriesVo(s)}, nil
+}
+
+func (h *SeriesHandler) List(ctx *gin.Context, uc jwt.UserClaims) (ginx.Result, error) {
+	ss, err := h.svc.ListByAuthor(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{
+		Data: slice.Map[domain.Series, SeriesVo](ss, func(idx int, src domain.Series) SeriesVo {
+			return newSeriesVo(src)
+		}),
+	}, nil
+}
+
+func (h *SeriesHandler) PubDetail(ctx *gin.Context) (ginx.Result, error) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return ginx.Result{Code: 4, Msg: "id 参数错误"}, err
+	}
+	s, err := h.svc.GetPub(ctx, id)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Data: newSeriesVo(s)}, nil
+}
+
+func newSeriesVo(s domain.Series) SeriesVo {
+	return SeriesVo{
+		Id:          s.Id,
+		Title:       s.Title,
+		Description: s.Description,
+		AuthorId:    s.Author.Id,
+		AuthorName:  s.Author.Name,
+		Articles: slice.Map[domain.Article, ArticleVo](s.Articles,
+			func(idx int, src domain.Article) ArticleVo {
+				return ArticleVo{
+					Id:       src.Id,
+					Title:    src.Title,
+					Abstract: src.Abstract(),
+					Status:   src.Status.ToUint8(),
+					Ctime:    src.Ctime.Format(time.DateTime),
+					Utime:    src.Utime.Format(time.DateTime),
+				}
+			}),
+		Ctime: s.Ctime.Format(time.DateTime),
+		Utime: s.Utime.Format(time.DateTime),
+	}
+}
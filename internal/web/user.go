@@ -88,7 +88,7 @@ func (h *UserHandler) LoginSMS(ctx *gin.Context,
 			Msg:  "系统错误",
 		}, err
 	}
-	err = h.SetLoginToken(ctx, u.Id)
+	err = h.SetLoginToken(ctx, u.Id, u.Role.ToUint8())
 	if err != nil {
 		return ginx.Result{
 			Code: 5,
@@ -194,7 +194,7 @@ func (h *UserHandler) LoginJWT(ctx *gin.Context, req LoginJWTReq) (ginx.Result,
 	u, err := h.svc.Login(ctx, req.Email, req.Password)
 	switch err {
 	case nil:
-		err = h.SetLoginToken(ctx, u.Id)
+		err = h.SetLoginToken(ctx, u.Id, u.Role.ToUint8())
 		if err != nil {
 			return ginx.Result{
 				Code: 5,
@@ -330,7 +330,7 @@ func (h *UserHandler) RefreshToken(ctx *gin.Context) {
 		return
 	}
 
-	err = h.SetJWTToken(ctx, rc.Uid, rc.Ssid)
+	err = h.SetJWTToken(ctx, rc.Uid, rc.Role, rc.Ssid)
 	if err != nil {
 		ctx.AbortWithStatus(http.StatusUnauthorized)
 		return
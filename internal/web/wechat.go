@@ -88,7 +88,7 @@ func (o *OAuth2WechatHandler) Callback(ctx *gin.Context) {
 		})
 		return
 	}
-	err = o.SetLoginToken(ctx, u.Id)
+	err = o.SetLoginToken(ctx, u.Id, u.Role.ToUint8())
 	if err != nil {
 		ctx.String(http.StatusOK, "系统错误")
 		return
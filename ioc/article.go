@@ -0,0 +1,25 @@
+package ioc
+
+import (
+	"gitee.com/geekbang/basic-go/webook/internal/service"
+	"github.com/spf13/viper"
+)
+
+// InitArticleReviewConfig 默认不需要审核
+func InitArticleReviewConfig() service.ReviewConfig {
+	type Config struct {
+		Enabled        bool    `yaml:"enabled"`
+		Reviewers      []int64 `yaml:"reviewers"`
+		TrustedAuthors []int64 `yaml:"trustedAuthors"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("article.review", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return service.ReviewConfig{
+		Enabled:        cfg.Enabled,
+		Reviewers:      cfg.Reviewers,
+		TrustedAuthors: cfg.TrustedAuthors,
+	}
+}
@@ -0,0 +1,40 @@
+package ioc
+
+import (
+	tagv1 "gitee.com/geekbang/basic-go/webook/api/proto/gen/tag/v1"
+	"github.com/spf13/viper"
+	etcdv3 "go.etcd.io/etcd/client/v3"
+	resolver2 "go.etcd.io/etcd/client/v3/naming/resolver"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// initClientConn 通过 etcd 做服务发现，key 是 grpc.client 下面的配置
+func initClientConn(client *etcdv3.Client, key string) *grpc.ClientConn {
+	type Config struct {
+		Addr   string `yaml:"addr"`
+		Secure bool   `yaml:"secure"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("grpc.client."+key, &cfg)
+	if err != nil {
+		panic(err)
+	}
+	resolver, err := resolver2.NewBuilder(client)
+	if err != nil {
+		panic(err)
+	}
+	opts := []grpc.DialOption{grpc.WithResolvers(resolver)}
+	if !cfg.Secure {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+	cc, err := grpc.Dial(cfg.Addr, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return cc
+}
+
+func InitTagClient(client *etcdv3.Client) tagv1.TagServiceClient {
+	return tagv1.NewTagServiceClient(initClientConn(client, "tag"))
+}
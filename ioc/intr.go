@@ -7,36 +7,12 @@ import (
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 	etcdv3 "go.etcd.io/etcd/client/v3"
-	resolver2 "go.etcd.io/etcd/client/v3/naming/resolver"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
 func InitIntrClientV1(client *etcdv3.Client) intrv1.InteractiveServiceClient {
-	type Config struct {
-		Addr   string `yaml:"addr"`
-		Secure bool   `yaml:"secure"`
-	}
-	var cfg Config
-	err := viper.UnmarshalKey("grpc.client.intr", &cfg)
-	if err != nil {
-		panic(err)
-	}
-	resolver, err := resolver2.NewBuilder(client)
-	if err != nil {
-		panic(err)
-	}
-	opts := []grpc.DialOption{grpc.WithResolvers(resolver)}
-	if !cfg.Secure {
-		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	}
-
-	cc, err := grpc.Dial(cfg.Addr, opts...)
-	if err != nil {
-		panic(err)
-	}
-	remote := intrv1.NewInteractiveServiceClient(cc)
-	return remote
+	return intrv1.NewInteractiveServiceClient(initClientConn(client, "intr"))
 }
 
 func InitIntrClient(svc service.InteractiveService) intrv1.InteractiveServiceClient {
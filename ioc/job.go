@@ -1,12 +1,17 @@
 package ioc
 
 import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
 	"gitee.com/geekbang/basic-go/webook/internal/job"
 	"gitee.com/geekbang/basic-go/webook/internal/service"
 	"gitee.com/geekbang/basic-go/webook/pkg/logger"
 	rlock "github.com/gotomicro/redis-lock"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/robfig/cron/v3"
+	"github.com/spf13/viper"
+	etcdv3 "go.etcd.io/etcd/client/v3"
+	"net/http"
 	"time"
 )
 
@@ -35,3 +40,150 @@ func InitJobs(l logger.LoggerV1, rjob *job.RankingJob) *cron.Cron {
 	}
 	return expr
 }
+
+// InitScheduler 基于 MySQL 抢占的分布式任务调度
+func InitScheduler(l logger.LoggerV1,
+	local *job.LocalFuncExecutor,
+	httpExec *job.HttpExecutor,
+	grpcExec *job.GrpcExecutor,
+	svc service.CronJobService) *job.Scheduler {
+	type Node struct {
+		Name     string   `yaml:"name"`
+		Labels   []string `yaml:"labels"`
+		Capacity int64    `yaml:"capacity"`
+	}
+	var node Node
+	err := viper.UnmarshalKey("job.node", &node)
+	if err != nil {
+		panic(err)
+	}
+	res := job.NewScheduler(svc, l, job.SchedulerConfig{
+		Node:     node.Name,
+		Labels:   node.Labels,
+		Capacity: node.Capacity,
+	})
+	res.RegisterExecutor(local)
+	res.RegisterExecutor(httpExec)
+	res.RegisterExecutor(grpcExec)
+	registerRemoteJobs(svc)
+	return res
+}
+
+// InitHttpExecutor endpoint 在 job.http 下面配置
+func InitHttpExecutor() *job.HttpExecutor {
+	type Endpoint struct {
+		URL     string        `yaml:"url"`
+		Token   string        `yaml:"token"`
+		Timeout time.Duration `yaml:"timeout"`
+	}
+	var cfg map[string]Endpoint
+	err := viper.UnmarshalKey("job.http", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	endpoints := make(map[string]job.HttpEndpoint, len(cfg))
+	for name, ep := range cfg {
+		endpoints[name] = job.HttpEndpoint{
+			URL:     ep.URL,
+			Token:   ep.Token,
+			Timeout: ep.Timeout,
+		}
+	}
+	return job.NewHttpExecutor(&http.Client{}, endpoints)
+}
+
+// InitGrpcExecutor 可以调用的服务在 job.grpc 下面配置
+func InitGrpcExecutor(client *etcdv3.Client) *job.GrpcExecutor {
+	type Service struct {
+		Token   string        `yaml:"token"`
+		Timeout time.Duration `yaml:"timeout"`
+	}
+	var cfg map[string]Service
+	err := viper.UnmarshalKey("job.grpc", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	services := make(map[string]job.GrpcService, len(cfg))
+	for name, svc := range cfg {
+		services[name] = job.GrpcService{
+			Token:   svc.Token,
+			Timeout: svc.Timeout,
+		}
+	}
+	return job.NewGrpcExecutor(client, services)
+}
+
+// registerRemoteJobs 其它服务的任务在 job.remote 下面配置，由这里统一调度。
+// 每次启动都会用配置覆盖数据库里面的同名任务
+func registerRemoteJobs(svc service.CronJobService) {
+	type Job struct {
+		Name        string        `yaml:"name"`
+		Executor    string        `yaml:"executor"`
+		Target      string        `yaml:"target"`
+		Expression  string        `yaml:"expression"`
+		Cfg         string        `yaml:"cfg"`
+		MaxRetries  int           `yaml:"maxRetries"`
+		Backoff     time.Duration `yaml:"backoff"`
+		Timeout     time.Duration `yaml:"timeout"`
+		MaxFailures int           `yaml:"maxFailures"`
+		Label       string        `yaml:"label"`
+		Weight      int64         `yaml:"weight"`
+	}
+	var cfg []Job
+	err := viper.UnmarshalKey("job.remote", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	for _, j := range cfg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		err = svc.AddJob(ctx, domain.Job{
+			Name:        j.Name,
+			Executor:    j.Executor,
+			Target:      j.Target,
+			Expression:  j.Expression,
+			Cfg:         j.Cfg,
+			MaxRetries:  j.MaxRetries,
+			Backoff:     j.Backoff,
+			Timeout:     j.Timeout,
+			MaxFailures: j.MaxFailures,
+			Label:       j.Label,
+			Weight:      j.Weight,
+		})
+		cancel()
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func InitLocalFuncExecutor(svc service.CronJobService,
+	artSvc service.ArticleService) *job.LocalFuncExecutor {
+	res := job.NewLocalFuncExecutor()
+	// 定时发表文章，每 30 秒扫描一次到期的文章
+	registerLocalJob(svc, res, domain.Job{
+		Name:       "publish_scheduled_articles",
+		Expression: "*/30 * * * * ?",
+		MaxRetries: 2,
+		Backoff:    time.Second,
+		Timeout:    time.Second * 20,
+		// 差不多连续五分钟都发表不了
+		MaxFailures: 10,
+	}, func(ctx context.Context, j domain.Job) error {
+		return artSvc.PublishDue(ctx)
+	})
+	return res
+}
+
+// registerLocalJob 注册本地方法，并且确保数据库里面有这个任务
+func registerLocalJob(svc service.CronJobService,
+	exec *job.LocalFuncExecutor,
+	j domain.Job, fn func(ctx context.Context, j domain.Job) error) {
+	exec.RegisterFunc(j.Name, fn)
+	j.Executor = exec.Name()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := svc.AddJob(ctx, j)
+	if err != nil {
+		panic(err)
+	}
+}
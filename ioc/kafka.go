@@ -2,6 +2,7 @@ package ioc
 
 import (
 	"gitee.com/geekbang/basic-go/webook/internal/events"
+	"gitee.com/geekbang/basic-go/webook/internal/events/ranking"
 	"github.com/IBM/sarama"
 	"github.com/spf13/viper"
 )
@@ -32,6 +33,6 @@ func InitSyncProducer(c sarama.Client) sarama.SyncProducer {
 	return p
 }
 
-func InitConsumers() []events.Consumer {
-	return []events.Consumer{}
+func InitConsumers(rankingConsumer *ranking.RankingEventConsumer) []events.Consumer {
+	return []events.Consumer{rankingConsumer}
 }
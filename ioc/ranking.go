@@ -0,0 +1,76 @@
+package ioc
+
+import (
+	"fmt"
+	"gitee.com/geekbang/basic-go/webook/internal/domain"
+	"gitee.com/geekbang/basic-go/webook/internal/service"
+	"github.com/spf13/viper"
+	"time"
+)
+
+// InitRankingConfig 没有配置的时候，用 service.DefaultRankingConfig
+func InitRankingConfig() service.RankingConfig {
+	type Strategy struct {
+		Name    string  `yaml:"name"`
+		Read    float64 `yaml:"read"`
+		Like    float64 `yaml:"like"`
+		Collect float64 `yaml:"collect"`
+		Gravity float64 `yaml:"gravity"`
+	}
+	type List struct {
+		Key      string        `yaml:"key"`
+		Strategy string        `yaml:"strategy"`
+		Tag      string        `yaml:"tag"`
+		Window   time.Duration `yaml:"window"`
+		N        int           `yaml:"n"`
+		HalfLife time.Duration `yaml:"halfLife"`
+	}
+	type Config struct {
+		Strategies []Strategy `yaml:"strategies"`
+		Lists      []List     `yaml:"lists"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("ranking", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	res := service.DefaultRankingConfig()
+	if len(cfg.Lists) == 0 {
+		return res
+	}
+	// 默认的 like 算法总是可以用
+	for _, s := range cfg.Strategies {
+		res.Strategies = append(res.Strategies, service.NewWeightedRankingStrategy(s.Name,
+			service.RankingWeights{
+				Read:    s.Read,
+				Like:    s.Like,
+				Collect: s.Collect,
+			}, s.Gravity))
+	}
+	names := make(map[string]struct{}, len(res.Strategies))
+	for _, s := range res.Strategies {
+		names[s.Name()] = struct{}{}
+	}
+	res.Lists = make([]domain.RankingList, 0, len(cfg.Lists))
+	for _, l := range cfg.Lists {
+		if _, ok := names[l.Strategy]; !ok {
+			panic(fmt.Errorf("热榜 %s 用了不存在的热度算法 %s", l.Key, l.Strategy))
+		}
+		if l.Window <= 0 || l.N <= 0 {
+			panic(fmt.Errorf("热榜 %s 的 window 和 n 必须大于 0", l.Key))
+		}
+		// 消息里面没有标签，实时热榜只能是全站的
+		if l.HalfLife > 0 && l.Tag != "" {
+			panic(fmt.Errorf("热榜 %s 按照标签统计，不能是实时热榜", l.Key))
+		}
+		res.Lists = append(res.Lists, domain.RankingList{
+			Key:      l.Key,
+			Strategy: l.Strategy,
+			Tag:      l.Tag,
+			Window:   l.Window,
+			N:        l.N,
+			HalfLife: l.HalfLife,
+		})
+	}
+	return res
+}
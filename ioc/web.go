@@ -29,12 +29,18 @@ import (
 func InitWebServer(mdls []gin.HandlerFunc,
 	userHdl *web.UserHandler,
 	artHdl *web.ArticleHandler,
+	seriesHdl *web.SeriesHandler,
+	reviewHdl *web.ArticleReviewHandler,
+	jobHdl *web.JobHandler,
 	wechatHdl *web.OAuth2WechatHandler) *gin.Engine {
 	server := gin.Default()
 	server.Use(mdls...)
 	userHdl.RegisterRoutes(server)
 	wechatHdl.RegisterRoutes(server)
 	artHdl.RegisterRoutes(server)
+	seriesHdl.RegisterRoutes(server)
+	reviewHdl.RegisterRoutes(server)
+	jobHdl.RegisterRoutes(server)
 	return server
 }
 
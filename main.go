@@ -39,6 +39,13 @@ func main() {
 		// 等待定时任务退出
 		<-app.cron.Stop().Done()
 	}()
+	schedulerCtx, schedulerCancel := context.WithCancel(context.Background())
+	defer schedulerCancel()
+	go func() {
+		// 退出的时候 ctx 被取消，这里会返回 context.Canceled
+		err := app.scheduler.Schedule(schedulerCtx)
+		log.Println("任务调度退出", err)
+	}()
 	server := app.server
 	server.GET("/hello", func(ctx *gin.Context) {
 		ctx.String(http.StatusOK, "hello，启动成功了！")
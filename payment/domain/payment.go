@@ -1,5 +1,13 @@
 package domain
 
+import (
+	"slices"
+	"time"
+)
+
+// PaymentTimeout 预支付之后多久没有付钱，订单就关闭
+const PaymentTimeout = time.Minute * 30
+
 type Amount struct {
 	// 如果要支持国际化，那么这个是不能少的
 	Currency string
@@ -20,18 +28,91 @@ type Payment struct {
 	Status PaymentStatus
 	// 第三方那边返回的 ID
 	TxnID string
+
+	// Channel 用哪个渠道支付
+	Channel PaymentChannel
+	// ExpireTime 到了这个时间还没有付钱，就会关闭订单
+	ExpireTime time.Time
+	// PaidTime 渠道返回的付钱时间，没有返回的话是零值
+	PaidTime time.Time
+}
+
+// PaymentChannel 支付渠道
+type PaymentChannel uint8
+
+func (c PaymentChannel) AsUint8() uint8 {
+	return uint8(c)
+}
+
+func (c PaymentChannel) String() string {
+	switch c {
+	case PaymentChannelWechat:
+		return "wechat"
+	case PaymentChannelAlipay:
+		return "alipay"
+	case PaymentChannelSandbox:
+		return "sandbox"
+	default:
+		return "unknown"
+	}
+}
+
+// PaymentChannelFromString 是 String 的逆过程，不认识的就是 PaymentChannelUnknown
+func PaymentChannelFromString(s string) PaymentChannel {
+	for _, c := range []PaymentChannel{PaymentChannelWechat, PaymentChannelAlipay, PaymentChannelSandbox} {
+		if c.String() == s {
+			return c
+		}
+	}
+	return PaymentChannelUnknown
 }
 
+const (
+	PaymentChannelUnknown PaymentChannel = iota
+	PaymentChannelWechat
+	PaymentChannelAlipay
+	// PaymentChannelSandbox 本地沙箱，用来离线测试
+	PaymentChannelSandbox
+)
+
 type PaymentStatus uint8
 
 func (s PaymentStatus) AsUint8() uint8 {
 	return uint8(s)
 }
 
+// Payed 已经付过钱了，包括后面退了款的
+func (s PaymentStatus) Payed() bool {
+	return s == PaymentStatusSuccess || s == PaymentStatusRefund
+}
+
+// paymentTransitions 每个状态可以从哪些状态变过来。
+// 付过钱的只能变成已退款，已退款的不会再变；
+// 失败或者关闭之后又收到支付成功，说明用户确实付了钱，要记下来
+var paymentTransitions = map[PaymentStatus][]PaymentStatus{
+	// 只是补上第三方的交易 ID
+	PaymentStatusInit:    {PaymentStatusInit},
+	PaymentStatusSuccess: {PaymentStatusInit, PaymentStatusFailed, PaymentStatusClosed},
+	PaymentStatusFailed:  {PaymentStatusInit},
+	PaymentStatusClosed:  {PaymentStatusInit},
+	PaymentStatusRefund:  {PaymentStatusSuccess},
+}
+
+// TransitFrom 可以从哪些状态变成 s
+func (s PaymentStatus) TransitFrom() []PaymentStatus {
+	return paymentTransitions[s]
+}
+
+func (s PaymentStatus) CanTransitTo(next PaymentStatus) bool {
+	return slices.Contains(next.TransitFrom(), s)
+}
+
 const (
 	PaymentStatusUnknown = iota
 	PaymentStatusInit
 	PaymentStatusSuccess
 	PaymentStatusFailed
 	PaymentStatusRefund
+	// PaymentStatusClosed 过期没有付钱，第三方的订单也关掉了
+	PaymentStatusClosed
 )
@@ -0,0 +1,55 @@
+package domain
+
+// ReconcileDateLayout 账单日期的格式
+const ReconcileDateLayout = "20060102"
+
+// BillItem 第三方对账单里面的一笔交易
+type BillItem struct {
+	BizTradeNO string
+	TxnID      string
+	Amt        int64
+	Currency   string
+	// Status 已经退了款的交易是 PaymentStatusRefund
+	Status PaymentStatus
+}
+
+// ReconcileReport 某个渠道某一天的对账结果
+type ReconcileReport struct {
+	Channel PaymentChannel
+	Date    string
+	// BillCount 账单里面有多少笔交易
+	BillCount int
+	Diffs     []ReconcileDiff
+}
+
+// ReconcileDiff 对不上的一笔交易
+type ReconcileDiff struct {
+	BizTradeNO string
+	TxnID      string
+	Type       ReconcileDiffType
+
+	LocalAmt    int64
+	BillAmt     int64
+	LocalStatus PaymentStatus
+	BillStatus  PaymentStatus
+	// Fixed 已经按照账单自动修复了
+	Fixed bool
+}
+
+type ReconcileDiffType uint8
+
+func (t ReconcileDiffType) AsUint8() uint8 {
+	return uint8(t)
+}
+
+const (
+	ReconcileDiffUnknown ReconcileDiffType = iota
+	// ReconcileDiffMissing 账单里面有，我们这边没有这笔支付
+	ReconcileDiffMissing
+	// ReconcileDiffExtra 我们这边是已支付，第三方那边没有
+	ReconcileDiffExtra
+	// ReconcileDiffAmount 金额或者币种对不上
+	ReconcileDiffAmount
+	// ReconcileDiffStatus 支付状态或者第三方流水号对不上
+	ReconcileDiffStatus
+)
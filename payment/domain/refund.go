@@ -0,0 +1,36 @@
+package domain
+
+// Refund 一次退款，一笔支付可以有多次部分退款
+type Refund struct {
+	BizTradeNO string
+	// RefundNO 业务方决定怎么生成，用来去重
+	RefundNO string
+	// Amt 退多少钱，创建的时候为 0 就是把剩下的全部退掉
+	Amt Amount
+	// PaymentAmt 原本支付了多少钱，第三方退款的时候要用
+	PaymentAmt int64
+	Reason     string
+
+	Status RefundStatus
+	// 第三方那边返回的退款 ID
+	TxnID string
+}
+
+type RefundStatus uint8
+
+func (s RefundStatus) AsUint8() uint8 {
+	return uint8(s)
+}
+
+// Completed 退款已经有了最终结果
+func (s RefundStatus) Completed() bool {
+	return s == RefundStatusSuccess || s == RefundStatusFailed
+}
+
+const (
+	RefundStatusUnknown RefundStatus = iota
+	// RefundStatusInit 退款处理中
+	RefundStatusInit
+	RefundStatusSuccess
+	RefundStatusFailed
+)
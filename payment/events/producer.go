@@ -1,7 +0,0 @@
-package events
-
-import "context"
-
-type Producer interface {
-	ProducePaymentEvent(ctx context.Context, evt PaymentEvent) error
-}
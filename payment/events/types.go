@@ -6,9 +6,26 @@ package events
 type PaymentEvent struct {
 	BizTradeNO string
 	Status     uint8
+	// PaidTime 支付成功的时间，毫秒，其它状态是 0
+	PaidTime int64
 	// Detail
 }
 
 func (PaymentEvent) Topic() string {
 	return "payment_events"
 }
+
+// RefundEvent 退款有了最终结果之后发出来
+type RefundEvent struct {
+	BizTradeNO string
+	RefundNO   string
+	Amt        int64
+	Currency   string
+	Status     uint8
+	// PaymentStatus 全部退完之后就是已退款
+	PaymentStatus uint8
+}
+
+func (RefundEvent) Topic() string {
+	return "payment_refund_events"
+}
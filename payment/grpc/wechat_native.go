@@ -4,16 +4,17 @@ import (
 	"context"
 	pmtv1 "gitee.com/geekbang/basic-go/webook/api/proto/gen/payment/v1"
 	"gitee.com/geekbang/basic-go/webook/payment/domain"
-	"gitee.com/geekbang/basic-go/webook/payment/service/wechat"
+	"gitee.com/geekbang/basic-go/webook/payment/service"
 	"google.golang.org/grpc"
 )
 
 type WechatServiceServer struct {
 	pmtv1.UnimplementedWechatPaymentServiceServer
-	svc *wechat.NativePaymentService
+	svc service.PaymentService
 }
 
-func NewWechatServiceServer(svc *wechat.NativePaymentService) *WechatServiceServer {
+// NewWechatServiceServer 名字是历史原因，现在所有的渠道都是走这里
+func NewWechatServiceServer(svc service.PaymentService) *WechatServiceServer {
 	return &WechatServiceServer{svc: svc}
 }
 
@@ -39,6 +40,8 @@ func (s *WechatServiceServer) NativePrePay(ctx context.Context, request *pmtv1.P
 		},
 		BizTradeNO:  request.BizTradeNo,
 		Description: request.Description,
+		// 两者取值都是一样的，直接转
+		Channel: domain.PaymentChannel(request.Channel),
 	})
 	if err != nil {
 		return nil, err
@@ -47,3 +50,32 @@ func (s *WechatServiceServer) NativePrePay(ctx context.Context, request *pmtv1.P
 		CodeUrl: codeURL,
 	}, nil
 }
+
+func (s *WechatServiceServer) Refund(ctx context.Context, req *pmtv1.RefundRequest) (*pmtv1.RefundResponse, error) {
+	r, err := s.svc.Refund(ctx, domain.Refund{
+		BizTradeNO: req.GetBizTradeNo(),
+		RefundNO:   req.GetRefundNo(),
+		Amt: domain.Amount{
+			Total: req.GetAmt(),
+		},
+		Reason: req.GetReason(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pmtv1.RefundResponse{
+		Status: pmtv1.RefundStatus(r.Status),
+		Amt:    r.Amt.Total,
+	}, nil
+}
+
+func (s *WechatServiceServer) GetRefund(ctx context.Context, req *pmtv1.GetRefundRequest) (*pmtv1.GetRefundResponse, error) {
+	r, err := s.svc.GetRefund(ctx, req.GetRefundNo())
+	if err != nil {
+		return nil, err
+	}
+	return &pmtv1.GetRefundResponse{
+		Status: pmtv1.RefundStatus(r.Status),
+		Amt:    r.Amt.Total,
+	}, nil
+}
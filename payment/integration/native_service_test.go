@@ -5,7 +5,7 @@ import (
 	"gitee.com/geekbang/basic-go/webook/payment/domain"
 	"gitee.com/geekbang/basic-go/webook/payment/integration/startup"
 	"gitee.com/geekbang/basic-go/webook/payment/repository/dao"
-	"gitee.com/geekbang/basic-go/webook/payment/service/wechat"
+	"gitee.com/geekbang/basic-go/webook/payment/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -16,7 +16,7 @@ import (
 
 type WechatNativeServiceTestSuite struct {
 	suite.Suite
-	svc *wechat.NativePaymentService
+	svc service.PaymentService
 	db  *gorm.DB
 }
 
@@ -25,7 +25,7 @@ func TestWechatNativeService(t *testing.T) {
 }
 
 func (s *WechatNativeServiceTestSuite) SetupSuite() {
-	s.svc = startup.InitWechatNativeService()
+	s.svc = startup.InitPaymentService()
 	s.db = startup.InitTestDB()
 }
 
@@ -51,6 +51,7 @@ func (s *WechatNativeServiceTestSuite) TestPrepay() {
 				},
 				BizTradeNO:  bizNo1,
 				Description: "我在这边买了一个产品",
+				Channel:     domain.PaymentChannelWechat,
 			},
 			after: func(t *testing.T) {
 				var pmt dao.Payment
@@ -68,6 +69,7 @@ func (s *WechatNativeServiceTestSuite) TestPrepay() {
 					BizTradeNO:  bizNo1,
 					Description: "我在这边买了一个产品",
 					Status:      domain.PaymentStatusInit,
+					Channel:     domain.PaymentChannelWechat.AsUint8(),
 				}, pmt)
 			},
 		},
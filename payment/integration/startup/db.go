@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"gitee.com/geekbang/basic-go/webook/payment/repository/dao"
+	"gitee.com/geekbang/basic-go/webook/pkg/outbox"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"log"
@@ -37,6 +38,10 @@ func InitTestDB() *gorm.DB {
 		if err != nil {
 			panic(err)
 		}
+		err = outbox.InitTables(db)
+		if err != nil {
+			panic(err)
+		}
 		//db = db.Debug()
 	}
 	return db
@@ -6,20 +6,26 @@ import (
 	"gitee.com/geekbang/basic-go/webook/payment/ioc"
 	"gitee.com/geekbang/basic-go/webook/payment/repository"
 	"gitee.com/geekbang/basic-go/webook/payment/repository/dao"
-	"gitee.com/geekbang/basic-go/webook/payment/service/wechat"
+	"gitee.com/geekbang/basic-go/webook/payment/service"
 	"github.com/google/wire"
 )
 
 var thirdPartySet = wire.NewSet(ioc.InitLogger, InitTestDB)
 
-var wechatNativeSvcSet = wire.NewSet(
+var paymentSvcSet = wire.NewSet(
 	ioc.InitWechatClient,
 	dao.NewPaymentGORMDAO,
+	dao.NewRefundGORMDAO,
 	repository.NewPaymentRepository,
+	repository.NewRefundRepository,
 	ioc.InitWechatNativeService,
+	ioc.InitAlipayService,
+	ioc.InitSandboxService,
+	ioc.InitProviders,
+	service.NewPaymentService,
 	ioc.InitWechatConfig)
 
-func InitWechatNativeService() *wechat.NativePaymentService {
-	wire.Build(wechatNativeSvcSet, thirdPartySet)
-	return new(wechat.NativePaymentService)
+func InitPaymentService() service.PaymentService {
+	wire.Build(paymentSvcSet, thirdPartySet)
+	return nil
 }
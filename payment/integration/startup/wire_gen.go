@@ -10,25 +10,31 @@ import (
 	"gitee.com/geekbang/basic-go/webook/payment/ioc"
 	"gitee.com/geekbang/basic-go/webook/payment/repository"
 	"gitee.com/geekbang/basic-go/webook/payment/repository/dao"
-	"gitee.com/geekbang/basic-go/webook/payment/service/wechat"
+	"gitee.com/geekbang/basic-go/webook/payment/service"
 	"github.com/google/wire"
 )
 
 // Injectors from wire.go:
 
-func InitWechatNativeService() *wechat.NativePaymentService {
-	wechatConfig := ioc.InitWechatConfig()
-	client := ioc.InitWechatClient(wechatConfig)
+func InitPaymentService() service.PaymentService {
 	gormDB := InitTestDB()
 	paymentDAO := dao.NewPaymentGORMDAO(gormDB)
 	paymentRepository := repository.NewPaymentRepository(paymentDAO)
+	refundDAO := dao.NewRefundGORMDAO(gormDB)
+	refundRepository := repository.NewRefundRepository(refundDAO)
+	wechatConfig := ioc.InitWechatConfig()
+	client := ioc.InitWechatClient(wechatConfig)
 	loggerV1 := ioc.InitLogger()
-	nativePaymentService := ioc.InitWechatNativeService(client, paymentRepository, loggerV1, wechatConfig)
-	return nativePaymentService
+	nativePaymentService := ioc.InitWechatNativeService(client, loggerV1, wechatConfig)
+	alipayPaymentService := ioc.InitAlipayService(loggerV1)
+	sandboxPaymentService := ioc.InitSandboxService(loggerV1)
+	v := ioc.InitProviders(nativePaymentService, alipayPaymentService, sandboxPaymentService)
+	paymentService := service.NewPaymentService(paymentRepository, refundRepository, v, loggerV1)
+	return paymentService
 }
 
 // wire.go:
 
 var thirdPartySet = wire.NewSet(ioc.InitLogger, InitTestDB)
 
-var wechatNativeSvcSet = wire.NewSet(ioc.InitWechatClient, dao.NewPaymentGORMDAO, repository.NewPaymentRepository, ioc.InitWechatNativeService, ioc.InitWechatConfig)
+var paymentSvcSet = wire.NewSet(ioc.InitWechatClient, dao.NewPaymentGORMDAO, dao.NewRefundGORMDAO, repository.NewPaymentRepository, repository.NewRefundRepository, ioc.InitWechatNativeService, ioc.InitAlipayService, ioc.InitSandboxService, ioc.InitProviders, service.NewPaymentService, ioc.InitWechatConfig)
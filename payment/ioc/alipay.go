@@ -0,0 +1,39 @@
+package ioc
+
+import (
+	"gitee.com/geekbang/basic-go/webook/payment/service/alipay"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	aliclient "github.com/smartwalle/alipay/v3"
+	"github.com/spf13/viper"
+	"os"
+)
+
+// InitAlipayService 没有配置支付宝的时候返回 nil
+func InitAlipayService(l logger.LoggerV1) *alipay.PaymentService {
+	type Config struct {
+		AppID      string `yaml:"appID"`
+		Production bool   `yaml:"production"`
+		NotifyURL  string `yaml:"notifyURL"`
+		// ReturnURL 配置了就用电脑网站支付，没有配置就是扫码支付
+		ReturnURL string `yaml:"returnURL"`
+	}
+	if !viper.IsSet("alipay") {
+		return nil
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("alipay", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	// 和微信一样，密钥不放在配置文件里面
+	client, err := aliclient.New(cfg.AppID, os.Getenv("ALIPAY_PRIVATE_KEY"), cfg.Production)
+	if err != nil {
+		panic(err)
+	}
+	// 用来验证支付宝的响应和异步通知
+	err = client.LoadAliPayPublicKey(os.Getenv("ALIPAY_PUBLIC_KEY"))
+	if err != nil {
+		panic(err)
+	}
+	return alipay.NewPaymentService(client, cfg.AppID, cfg.NotifyURL, cfg.ReturnURL, l)
+}
@@ -3,6 +3,7 @@ package ioc
 import (
 	"fmt"
 	"gitee.com/geekbang/basic-go/webook/payment/repository/dao"
+	"gitee.com/geekbang/basic-go/webook/pkg/outbox"
 	"github.com/spf13/viper"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -27,5 +28,9 @@ func InitDB() *gorm.DB {
 	if err != nil {
 		panic(err)
 	}
+	err = outbox.InitTables(db)
+	if err != nil {
+		panic(err)
+	}
 	return db
 }
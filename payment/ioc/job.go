@@ -0,0 +1,44 @@
+package ioc
+
+import (
+	"gitee.com/geekbang/basic-go/webook/payment/job"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"gitee.com/geekbang/basic-go/webook/pkg/outbox"
+	"gitee.com/geekbang/basic-go/webook/pkg/wego"
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/viper"
+)
+
+func InitJobs(l logger.LoggerV1, rjob *job.ReconcileJob, sjob *job.SyncRefundJob) *cron.Cron {
+	// 默认每天上午十点对前一天的账，这个时候微信的账单肯定已经出来了
+	spec := viper.GetString("reconcile.cron")
+	if spec == "" {
+		spec = "0 0 10 * * *"
+	}
+	// 默认每分钟同步一次还在处理中的退款
+	refundSpec := viper.GetString("refund.syncCron")
+	if refundSpec == "" {
+		refundSpec = "0 * * * * *"
+	}
+	expr := cron.New(cron.WithSeconds())
+	addJob(expr, l, spec, rjob)
+	addJob(expr, l, refundSpec, sjob)
+	return expr
+}
+
+func addJob(expr *cron.Cron, l logger.LoggerV1, spec string, j job.Job) {
+	_, err := expr.AddFunc(spec, func() {
+		err := j.Run()
+		if err != nil {
+			l.Error("运行任务失败", logger.Error(err),
+				logger.String("job", j.Name()))
+		}
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func InitDaemons(ob *outbox.Outbox, closer *job.ClosePaymentJob) []wego.Daemon {
+	return []wego.Daemon{ob, closer}
+}
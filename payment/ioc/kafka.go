@@ -1,9 +1,11 @@
 package ioc
 
 import (
-	"gitee.com/geekbang/basic-go/webook/payment/events"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"gitee.com/geekbang/basic-go/webook/pkg/outbox"
 	"github.com/IBM/sarama"
 	"github.com/spf13/viper"
+	"gorm.io/gorm"
 )
 
 func InitKafka() sarama.Client {
@@ -25,10 +27,19 @@ func InitKafka() sarama.Client {
 	return client
 }
 
-func InitProducer(client sarama.Client) events.Producer {
-	res, err := events.NewSaramaProducer(client)
+// InitOutbox 支付事件和退款事件都是通过 outbox 发出去的
+func InitOutbox(db *gorm.DB, client sarama.Client, l logger.LoggerV1) *outbox.Outbox {
+	var cfg outbox.Config
+	err := viper.UnmarshalKey("outbox", &cfg)
 	if err != nil {
 		panic(err)
 	}
-	return res
+	if cfg.Name == "" {
+		cfg.Name = "payment"
+	}
+	producer, err := sarama.NewSyncProducerFromClient(client)
+	if err != nil {
+		panic(err)
+	}
+	return outbox.NewOutbox(db, producer, l, cfg)
 }
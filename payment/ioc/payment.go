@@ -0,0 +1,22 @@
+package ioc
+
+import (
+	"gitee.com/geekbang/basic-go/webook/payment/service"
+	"gitee.com/geekbang/basic-go/webook/payment/service/alipay"
+	"gitee.com/geekbang/basic-go/webook/payment/service/sandbox"
+	"gitee.com/geekbang/basic-go/webook/payment/service/wechat"
+)
+
+// InitProviders 没有配置的渠道是 nil，要跳过
+func InitProviders(wechatSvc *wechat.NativePaymentService,
+	alipaySvc *alipay.PaymentService,
+	sandboxSvc *sandbox.PaymentService) []service.Provider {
+	res := []service.Provider{wechatSvc}
+	if alipaySvc != nil {
+		res = append(res, alipaySvc)
+	}
+	if sandboxSvc != nil {
+		res = append(res, sandboxSvc)
+	}
+	return res
+}
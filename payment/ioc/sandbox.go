@@ -0,0 +1,25 @@
+package ioc
+
+import (
+	"gitee.com/geekbang/basic-go/webook/payment/service/sandbox"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/spf13/viper"
+)
+
+// InitSandboxService 只有 sandbox.enabled 为 true 的时候才打开沙箱，
+// 线上千万不要打开
+func InitSandboxService(l logger.LoggerV1) *sandbox.PaymentService {
+	if !viper.GetBool("sandbox.enabled") {
+		return nil
+	}
+	var cfg sandbox.Config
+	err := viper.UnmarshalKey("sandbox", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	if cfg.Secret == "" {
+		// 没有密钥的话谁都可以伪造通知
+		panic("沙箱没有配置密钥")
+	}
+	return sandbox.NewPaymentService(cfg, l)
+}
@@ -1,16 +1,25 @@
 package ioc
 
 import (
+	"crypto/subtle"
 	"gitee.com/geekbang/basic-go/webook/payment/web"
 	"gitee.com/geekbang/basic-go/webook/pkg/ginx"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"gitee.com/geekbang/basic-go/webook/pkg/outbox"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/viper"
+	"net/http"
 )
 
-func InitGinServer(hdl *web.WechatHandler) *ginx.Server {
+// InitGinServer 对外的 server，只有第三方的回调
+func InitGinServer(hdl *web.WechatHandler,
+	aliHdl *web.AlipayHandler,
+	sandboxHdl *web.SandboxHandler) *ginx.Server {
 	engine := gin.Default()
 	hdl.RegisterRoutes(engine)
+	aliHdl.RegisterRoutes(engine)
+	sandboxHdl.RegisterRoutes(engine)
 	addr := viper.GetString("http.addr")
 	ginx.InitCounter(prometheus.CounterOpts{
 		Namespace: "daming_geektime",
@@ -22,3 +31,44 @@ func InitGinServer(hdl *web.WechatHandler) *ginx.Server {
 		Addr:   addr,
 	}
 }
+
+// InitAdminServer 管理后台的 server，只在内网监听，
+// 请求还要带上 Authorization: Bearer {token}
+func InitAdminServer(reconcileHdl *web.ReconcileHandler,
+	ob *outbox.Outbox,
+	l logger.LoggerV1) *ginx.Server {
+	type Config struct {
+		Addr  string `yaml:"addr"`
+		Token string `yaml:"token"`
+	}
+	cfg := Config{
+		Addr: "127.0.0.1:8071",
+	}
+	err := viper.UnmarshalKey("admin", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	if cfg.Token == "" {
+		l.Warn("没有配置管理后台的 token，所有管理接口都会拒绝访问")
+	}
+	engine := gin.Default()
+	engine.Use(checkAdminToken(cfg.Token))
+	reconcileHdl.RegisterRoutes(engine)
+	// 管理发送失败的支付事件和退款事件
+	outbox.NewHandler(ob).RegisterRoutes(engine.Group("/pay/outbox"))
+	return &ginx.Server{
+		Engine: engine,
+		Addr:   cfg.Addr,
+	}
+}
+
+// checkAdminToken 没有配置 token 的时候全部拒绝，免得忘了配置就对外开放了
+func checkAdminToken(token string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		auth := []byte(ctx.GetHeader("Authorization"))
+		if token == "" || subtle.ConstantTimeCompare(auth, []byte("Bearer "+token)) != 1 {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+	}
+}
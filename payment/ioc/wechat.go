@@ -2,7 +2,6 @@ package ioc
 
 import (
 	"context"
-	"gitee.com/geekbang/basic-go/webook/payment/repository"
 	"gitee.com/geekbang/basic-go/webook/payment/service/wechat"
 	"gitee.com/geekbang/basic-go/webook/pkg/logger"
 	"github.com/wechatpay-apiv3/wechatpay-go/core"
@@ -11,11 +10,16 @@ import (
 	"github.com/wechatpay-apiv3/wechatpay-go/core/notify"
 	"github.com/wechatpay-apiv3/wechatpay-go/core/option"
 	"github.com/wechatpay-apiv3/wechatpay-go/services/payments/native"
+	"github.com/wechatpay-apiv3/wechatpay-go/services/refunddomestic"
 	"github.com/wechatpay-apiv3/wechatpay-go/utils"
 	"os"
 )
 
 func InitWechatClient(cfg WechatConfig) *core.Client {
+	return newWechatClient(cfg)
+}
+
+func newWechatClient(cfg WechatConfig, opts ...core.ClientOption) *core.Client {
 	// 使用 utils 提供的函数从本地文件中加载商户私钥，商户私钥会用来生成请求的签名
 	mchPrivateKey, err := utils.LoadPrivateKeyWithPath(
 		// 注意这个文件我没有上传，所以你需要准备一个
@@ -32,6 +36,7 @@ func InitWechatClient(cfg WechatConfig) *core.Client {
 		option.WithWechatPayAutoAuthCipher(
 			cfg.MchID, cfg.MchSerialNum,
 			mchPrivateKey, cfg.MchKey),
+		opts...,
 	)
 	if err != nil {
 		panic(err)
@@ -41,12 +46,17 @@ func InitWechatClient(cfg WechatConfig) *core.Client {
 
 func InitWechatNativeService(
 	cli *core.Client,
-	repo repository.PaymentRepository,
 	l logger.LoggerV1,
 	cfg WechatConfig) *wechat.NativePaymentService {
-	return wechat.NewNativePaymentService(cfg.AppID, cfg.MchID, repo, &native.NativeApiService{
-		Client: cli,
-	}, l)
+	// 下载账单文件的应答是没有签名的，所以要单独用一个不验签的 client
+	billCli := newWechatClient(cfg, option.WithoutValidator())
+	return wechat.NewNativePaymentService(cfg.AppID, cfg.MchID,
+		&native.NativeApiService{
+			Client: cli,
+		},
+		&refunddomestic.RefundsApiService{
+			Client: cli,
+		}, billCli, l)
 }
 
 func InitWechatNotifyHandler(cfg WechatConfig) *notify.Handler {
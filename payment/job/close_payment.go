@@ -0,0 +1,78 @@
+package job
+
+import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/payment/service"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"time"
+)
+
+// ClosePaymentJob 一直运行的延时任务，订单一过期就关掉。
+// 它不是定时任务，每次都睡到最早的那个订单过期为止，
+// 但是最多睡 maxWait，这样别的实例新建的订单也能及时处理
+type ClosePaymentJob struct {
+	svc       service.PaymentService
+	l         logger.LoggerV1
+	batchSize int
+	maxWait   time.Duration
+}
+
+func NewClosePaymentJob(svc service.PaymentService, l logger.LoggerV1) *ClosePaymentJob {
+	return &ClosePaymentJob{
+		svc:       svc,
+		l:         l,
+		batchSize: 100,
+		maxWait:   time.Second * 5,
+	}
+}
+
+// Start 阻塞直到 ctx 被取消。
+// 多个实例一起运行也没关系，关单只会对未支付的订单生效
+func (j *ClosePaymentJob) Start(ctx context.Context) error {
+	for {
+		n, err := j.closeBatch(ctx)
+		if err != nil {
+			j.l.Error("关闭过期订单失败", logger.Error(err))
+		}
+		if n == j.batchSize && err == nil {
+			// 还有过期的，马上处理下一批
+			if ctx.Err() != nil {
+				return nil
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(j.wait(ctx)):
+		}
+	}
+}
+
+func (j *ClosePaymentJob) closeBatch(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*30)
+	defer cancel()
+	return j.svc.CloseExpiredPayments(ctx, j.batchSize)
+}
+
+// wait 距离下一个订单过期还有多久
+func (j *ClosePaymentJob) wait(ctx context.Context) time.Duration {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	t, err := j.svc.NextExpireTime(ctx)
+	if err != nil {
+		j.l.Error("查询下一个过期时间失败", logger.Error(err))
+		return j.maxWait
+	}
+	if t.IsZero() {
+		return j.maxWait
+	}
+	d := time.Until(t)
+	if d < 0 {
+		return 0
+	}
+	if d > j.maxWait {
+		return j.maxWait
+	}
+	return d
+}
@@ -0,0 +1,115 @@
+package job
+
+import (
+	"context"
+	"errors"
+	svcmocks "gitee.com/geekbang/basic-go/webook/payment/service/mocks"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestClosePaymentJob_Start(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc := svcmocks.NewMockPaymentService(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	gomock.InOrder(
+		// 一批处理满了，马上处理下一批，不用等
+		svc.EXPECT().CloseExpiredPayments(gomock.Any(), 2).Return(2, nil),
+		svc.EXPECT().CloseExpiredPayments(gomock.Any(), 2).Return(1, nil),
+		svc.EXPECT().NextExpireTime(gomock.Any()).Return(time.Time{}, nil),
+		// 出错了也要等一下再试
+		svc.EXPECT().CloseExpiredPayments(gomock.Any(), 2).
+			DoAndReturn(func(ctx context.Context, limit int) (int, error) {
+				cancel()
+				return 2, errors.New("mock db error")
+			}),
+		svc.EXPECT().NextExpireTime(gomock.Any()).Return(time.Time{}, nil).MaxTimes(1),
+	)
+	j := NewClosePaymentJob(svc, logger.NewNopLogger())
+	j.batchSize = 2
+	j.maxWait = time.Millisecond * 10
+
+	done := make(chan error)
+	go func() {
+		done <- j.Start(ctx)
+	}()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second * 3):
+		t.Fatal("ctx 取消之后没有退出")
+	}
+}
+
+func TestClosePaymentJob_wait(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) *svcmocks.MockPaymentService
+
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{
+			name: "没有要过期的订单",
+			mock: func(ctrl *gomock.Controller) *svcmocks.MockPaymentService {
+				svc := svcmocks.NewMockPaymentService(ctrl)
+				svc.EXPECT().NextExpireTime(gomock.Any()).Return(time.Time{}, nil)
+				return svc
+			},
+			wantMin: time.Second * 5,
+			wantMax: time.Second * 5,
+		},
+		{
+			name: "睡到最早的订单过期",
+			mock: func(ctrl *gomock.Controller) *svcmocks.MockPaymentService {
+				svc := svcmocks.NewMockPaymentService(ctrl)
+				svc.EXPECT().NextExpireTime(gomock.Any()).Return(time.Now().Add(time.Second*2), nil)
+				return svc
+			},
+			wantMin: time.Second,
+			wantMax: time.Second * 2,
+		},
+		{
+			name: "最多睡 maxWait",
+			mock: func(ctrl *gomock.Controller) *svcmocks.MockPaymentService {
+				svc := svcmocks.NewMockPaymentService(ctrl)
+				svc.EXPECT().NextExpireTime(gomock.Any()).Return(time.Now().Add(time.Hour), nil)
+				return svc
+			},
+			wantMin: time.Second * 5,
+			wantMax: time.Second * 5,
+		},
+		{
+			name: "已经过期了",
+			mock: func(ctrl *gomock.Controller) *svcmocks.MockPaymentService {
+				svc := svcmocks.NewMockPaymentService(ctrl)
+				svc.EXPECT().NextExpireTime(gomock.Any()).Return(time.Now().Add(-time.Second), nil)
+				return svc
+			},
+		},
+		{
+			name: "查询失败",
+			mock: func(ctrl *gomock.Controller) *svcmocks.MockPaymentService {
+				svc := svcmocks.NewMockPaymentService(ctrl)
+				svc.EXPECT().NextExpireTime(gomock.Any()).Return(time.Time{}, errors.New("mock db error"))
+				return svc
+			},
+			wantMin: time.Second * 5,
+			wantMax: time.Second * 5,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			j := NewClosePaymentJob(tc.mock(ctrl), logger.NewNopLogger())
+			d := j.wait(context.Background())
+			assert.True(t, d >= tc.wantMin && d <= tc.wantMax, d)
+		})
+	}
+}
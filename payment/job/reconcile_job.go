@@ -0,0 +1,45 @@
+package job
+
+import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/payment/service"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"time"
+)
+
+// ReconcileJob 每天对一次前一天的账，第三方的账单一般第二天上午才能下载
+type ReconcileJob struct {
+	svc service.ReconcileService
+	l   logger.LoggerV1
+}
+
+func NewReconcileJob(svc service.ReconcileService, l logger.LoggerV1) *ReconcileJob {
+	return &ReconcileJob{svc: svc, l: l}
+}
+
+func (r *ReconcileJob) Name() string {
+	return "reconcile_job"
+}
+
+func (r *ReconcileJob) Run() error {
+	date := time.Now().AddDate(0, 0, -1)
+	for _, channel := range r.svc.BillChannels() {
+		// 账单可能很大，给多一点时间
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+		report, err := r.svc.Reconcile(ctx, channel, date)
+		cancel()
+		if err != nil {
+			// 一个渠道失败了，不影响别的渠道
+			r.l.Error("对账失败", logger.Error(err),
+				logger.String("channel", channel.String()))
+			continue
+		}
+		if len(report.Diffs) > 0 {
+			r.l.Warn("对账有差异",
+				logger.String("channel", channel.String()),
+				logger.String("date", report.Date),
+				logger.Int("diffs", len(report.Diffs)))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package job
+
+import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/payment/service"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"time"
+)
+
+// SyncRefundJob 回调丢了或者发起退款的时候超时了，退款会一直停在处理中，
+// 这里定时去第三方查一下结果
+type SyncRefundJob struct {
+	svc service.PaymentService
+	l   logger.LoggerV1
+	// delay 发起之后多久还没有结果，才去查
+	delay time.Duration
+}
+
+func NewSyncRefundJob(svc service.PaymentService, l logger.LoggerV1) *SyncRefundJob {
+	return &SyncRefundJob{svc: svc, l: l, delay: time.Minute * 5}
+}
+
+func (s *SyncRefundJob) Name() string {
+	return "sync_refund_job"
+}
+
+func (s *SyncRefundJob) Run() error {
+	t := time.Now().Add(-s.delay)
+	offset := 0
+	const limit = 100
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		refunds, err := s.svc.FindPendingRefunds(ctx, offset, limit, t)
+		cancel()
+		if err != nil {
+			return err
+		}
+		for _, r := range refunds {
+			ctx, cancel = context.WithTimeout(context.Background(), time.Second*3)
+			err = s.svc.SyncRefund(ctx, r.RefundNO)
+			cancel()
+			if err != nil {
+				s.l.Error("同步退款状态失败", logger.Error(err),
+					logger.String("refund_no", r.RefundNO))
+			}
+		}
+		if len(refunds) < limit {
+			return nil
+		}
+		offset = offset + len(refunds)
+	}
+}
@@ -2,13 +2,13 @@ package job
 
 import (
 	"context"
-	"gitee.com/geekbang/basic-go/webook/payment/service/wechat"
+	"gitee.com/geekbang/basic-go/webook/payment/service"
 	"gitee.com/geekbang/basic-go/webook/pkg/logger"
 	"time"
 )
 
 type SyncWechatOrderJob struct {
-	svc *wechat.NativePaymentService
+	svc service.PaymentService
 	l   logger.LoggerV1
 }
 
@@ -19,7 +19,7 @@ func (s *SyncWechatOrderJob) Name() string {
 // 我这个定时任务，多久运行一次？
 // 不必特别频繁，比如说一分钟运行一次
 func (s *SyncWechatOrderJob) Run() error {
-	// 定时找到超时的微信支付订单，然后发起同步
+	// 定时找到超时的支付订单，然后发起同步，不管是哪个渠道的
 	// 针对过期订单
 	t := time.Now().Add(-time.Minute * 31)
 	//t := time.Now().Add(-time.Minute * 5)
@@ -35,7 +35,7 @@ func (s *SyncWechatOrderJob) Run() error {
 		}
 		for _, pmt := range pmts {
 			ctx, cancel = context.WithTimeout(context.Background(), time.Second*3)
-			err = s.svc.SyncWechatInfo(ctx, pmt.BizTradeNO)
+			err = s.svc.SyncPayment(ctx, pmt.BizTradeNO)
 			cancel()
 			if err != nil {
 				s.l.Error("同步微信订单状态失败", logger.Error(err),
@@ -0,0 +1,7 @@
+package job
+
+// Job 定时任务，由 cron 调度
+type Job interface {
+	Name() string
+	Run() error
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/pkg/wego"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
@@ -8,6 +10,26 @@ import (
 func main() {
 	initViperV2Watch()
 	app := InitApp()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, d := range app.Daemons {
+		// 退出的时候 ctx 被取消，outbox 会让出转发权
+		go func(d wego.Daemon) {
+			err := d.Start(ctx)
+			if err != nil {
+				panic(err)
+			}
+		}(d)
+	}
+	app.Cron.Start()
+	defer func() {
+		// 等待定时任务退出
+		<-app.Cron.Stop().Done()
+	}()
+	go func() {
+		err := app.AdminServer.Start()
+		panic(err)
+	}()
 	go func() {
 		err := app.GRPCServer.Serve()
 		panic(err)
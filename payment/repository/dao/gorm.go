@@ -3,6 +3,7 @@ package dao
 import (
 	"context"
 	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"gitee.com/geekbang/basic-go/webook/pkg/outbox"
 	"gorm.io/gorm"
 	"time"
 )
@@ -17,6 +18,24 @@ func (p *PaymentGORMDAO) GetPayment(ctx context.Context, bizTradeNO string) (Pay
 	return res, err
 }
 
+func (p *PaymentGORMDAO) FindByBizTradeNOs(ctx context.Context, bizTradeNOs []string) ([]Payment, error) {
+	var res []Payment
+	err := p.db.WithContext(ctx).Where("biz_trade_no IN ?", bizTradeNOs).Find(&res).Error
+	return res, err
+}
+
+func (p *PaymentGORMDAO) FindPayedPayments(ctx context.Context,
+	channel uint8, start, end int64, offset, limit int) ([]Payment, error) {
+	var res []Payment
+	err := p.db.WithContext(ctx).
+		Where("channel = ? AND status IN ? AND ctime >= ? AND ctime < ?",
+			channel, []int{int(domain.PaymentStatusSuccess), int(domain.PaymentStatusRefund)},
+			start, end).
+		Order("id").
+		Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
 func (p *PaymentGORMDAO) FindExpiredPayment(
 	ctx context.Context,
 	offset int, limit int, t time.Time) ([]Payment, error) {
@@ -30,13 +49,72 @@ func (p *PaymentGORMDAO) FindExpiredPayment(
 
 func (p *PaymentGORMDAO) UpdateTxnIDAndStatus(ctx context.Context,
 	bizTradeNo string,
-	txnID string, status domain.PaymentStatus) error {
+	txnID string, status domain.PaymentStatus, msgs ...outbox.Message) error {
+	// 不能用 []uint8，GORM 会把它当成一个 []byte，IN 就永远匹配不上
+	from := make([]int, 0, len(status.TransitFrom()))
+	for _, s := range status.TransitFrom() {
+		from = append(from, int(s))
+	}
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 并发的通知以数据库里面的状态为准，不合法的状态变化直接忽略
+		res := tx.Model(&Payment{}).
+			Where("biz_trade_no = ? AND status IN ?", bizTradeNo, from).
+			Updates(map[string]any{
+				"txn_id": txnID,
+				"status": status.AsUint8(),
+				"utime":  time.Now().UnixMilli(),
+			})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return outbox.Save(tx, msgs...)
+	})
+}
+
+func (p *PaymentGORMDAO) FindToClose(ctx context.Context, t int64, limit int) ([]Payment, error) {
+	var res []Payment
+	err := p.db.WithContext(ctx).
+		Where("status = ? AND expire_time > 0 AND expire_time <= ?",
+			uint8(domain.PaymentStatusInit), t).
+		Order("expire_time").Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (p *PaymentGORMDAO) NextExpireTime(ctx context.Context) (int64, error) {
+	var res int64
+	err := p.db.WithContext(ctx).Model(&Payment{}).
+		Select("COALESCE(MIN(expire_time), 0)").
+		Where("status = ? AND expire_time > 0", uint8(domain.PaymentStatusInit)).
+		Scan(&res).Error
+	return res, err
+}
+
+func (p *PaymentGORMDAO) Close(ctx context.Context, bizTradeNO string, msgs ...outbox.Message) (bool, error) {
+	closed := false
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Payment{}).
+			Where("biz_trade_no = ? AND status = ?", bizTradeNO, uint8(domain.PaymentStatusInit)).
+			Updates(map[string]any{
+				"status": uint8(domain.PaymentStatusClosed),
+				"utime":  time.Now().UnixMilli(),
+			})
+		if res.Error != nil || res.RowsAffected == 0 {
+			// 已经付了钱，或者别的实例已经关掉了
+			return res.Error
+		}
+		closed = true
+		return outbox.Save(tx, msgs...)
+	})
+	return closed, err
+}
+
+func (p *PaymentGORMDAO) DelayClose(ctx context.Context, bizTradeNO string, t int64) error {
 	return p.db.WithContext(ctx).Model(&Payment{}).
-		Where("biz_trade_no = ?", bizTradeNo).
+		Where("biz_trade_no = ? AND status = ?", bizTradeNO, uint8(domain.PaymentStatusInit)).
 		Updates(map[string]any{
-			"txn_id": txnID,
-			"status": status.AsUint8(),
-			"utime":  time.Now().UnixMilli(),
+			"expire_time": t,
+			"utime":       time.Now().UnixMilli(),
 		}).Error
 }
 
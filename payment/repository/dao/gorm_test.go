@@ -0,0 +1,81 @@
+package dao
+
+import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"gitee.com/geekbang/basic-go/webook/payment/events"
+	"gitee.com/geekbang/basic-go/webook/pkg/outbox"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+)
+
+func TestPaymentGORMDAO_UpdateTxnIDAndStatus(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "未支付的变成支付成功，发送消息",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `payments` SET `status`=?,`txn_id`=?,`utime`=? "+
+					"WHERE biz_trade_no = ? AND status IN (?,?,?)")).
+					WithArgs(uint8(domain.PaymentStatusSuccess), "txn-1", sqlmock.AnyArg(), "reward-1",
+						uint8(domain.PaymentStatusInit), uint8(domain.PaymentStatusFailed),
+						uint8(domain.PaymentStatusClosed)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO `outbox_msgs` .*").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "已经退款了，不会变回支付成功，也不发消息",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `payments` .*").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			tc.mock(mock)
+			dao := NewPaymentGORMDAO(openMockDB(t, sqlDB))
+			err = dao.UpdateTxnIDAndStatus(context.Background(), "reward-1", "txn-1",
+				domain.PaymentStatusSuccess, outbox.NewMessage("reward-1", events.PaymentEvent{
+					BizTradeNO: "reward-1",
+					Status:     domain.PaymentStatusSuccess,
+				}))
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPaymentGORMDAO_FindPayedPayments(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	// 支付成功和退款的都要展开成两个参数，不然对账查不到多出来的支付
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `payments` "+
+		"WHERE channel = ? AND status IN (?,?) AND ctime >= ? AND ctime < ? ORDER BY id LIMIT ? OFFSET ?")).
+		WithArgs(uint8(1), uint8(domain.PaymentStatusSuccess), uint8(domain.PaymentStatusRefund),
+			int64(1000), int64(2000), 10, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "biz_trade_no", "status"}).
+			AddRow(1, "reward-1", uint8(domain.PaymentStatusSuccess)).
+			AddRow(2, "reward-2", uint8(domain.PaymentStatusRefund)))
+	dao := NewPaymentGORMDAO(openMockDB(t, sqlDB))
+	res, err := dao.FindPayedPayments(context.Background(), 1, 1000, 2000, 20, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []Payment{
+		{Id: 1, BizTradeNO: "reward-1", Status: uint8(domain.PaymentStatusSuccess)},
+		{Id: 2, BizTradeNO: "reward-2", Status: uint8(domain.PaymentStatusRefund)},
+	}, res)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
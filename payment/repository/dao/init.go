@@ -3,5 +3,5 @@ package dao
 import "gorm.io/gorm"
 
 func InitTables(db *gorm.DB) error {
-	return db.AutoMigrate(&Payment{})
+	return db.AutoMigrate(&Payment{}, &Refund{}, &ReconcileReport{}, &ReconcileDiff{})
 }
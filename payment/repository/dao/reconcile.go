@@ -0,0 +1,93 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+var ErrReportNotFound = gorm.ErrRecordNotFound
+
+type ReconcileDAO interface {
+	// SaveReport 同一个渠道同一天重复对账的话，以最后一次为准
+	SaveReport(ctx context.Context, r ReconcileReport, diffs []ReconcileDiff) error
+	GetReport(ctx context.Context, channel uint8, date string) (ReconcileReport, []ReconcileDiff, error)
+}
+
+type ReconcileGORMDAO struct {
+	db *gorm.DB
+}
+
+func NewReconcileGORMDAO(db *gorm.DB) ReconcileDAO {
+	return &ReconcileGORMDAO{db: db}
+}
+
+func (dao *ReconcileGORMDAO) SaveReport(ctx context.Context, r ReconcileReport, diffs []ReconcileDiff) error {
+	now := time.Now().UnixMilli()
+	r.Ctime = now
+	r.Utime = now
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "channel"}, {Name: "date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"bill_count", "diff_count", "utime"}),
+		}).Create(&r).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("channel = ? AND date = ?", r.Channel, r.Date).
+			Delete(&ReconcileDiff{}).Error
+		if err != nil || len(diffs) == 0 {
+			return err
+		}
+		for i := range diffs {
+			diffs[i].Channel = r.Channel
+			diffs[i].Date = r.Date
+			diffs[i].Ctime = now
+		}
+		return tx.Create(&diffs).Error
+	})
+}
+
+func (dao *ReconcileGORMDAO) GetReport(ctx context.Context,
+	channel uint8, date string) (ReconcileReport, []ReconcileDiff, error) {
+	var r ReconcileReport
+	err := dao.db.WithContext(ctx).
+		Where("channel = ? AND date = ?", channel, date).
+		First(&r).Error
+	if err != nil {
+		return ReconcileReport{}, nil, err
+	}
+	var diffs []ReconcileDiff
+	err = dao.db.WithContext(ctx).
+		Where("channel = ? AND date = ?", channel, date).
+		Order("id").Find(&diffs).Error
+	return r, diffs, err
+}
+
+// ReconcileReport 每个渠道每天一条
+type ReconcileReport struct {
+	Id        int64  `gorm:"primaryKey,autoIncrement"`
+	Channel   uint8  `gorm:"uniqueIndex:idx_channel_date"`
+	Date      string `gorm:"type:varchar(8);uniqueIndex:idx_channel_date"`
+	BillCount int64
+	DiffCount int64
+	Ctime     int64
+	Utime     int64
+}
+
+type ReconcileDiff struct {
+	Id         int64  `gorm:"primaryKey,autoIncrement"`
+	Channel    uint8  `gorm:"index:idx_channel_date"`
+	Date       string `gorm:"type:varchar(8);index:idx_channel_date"`
+	BizTradeNO string `gorm:"column:biz_trade_no;type:varchar(256)"`
+	TxnID      string `gorm:"column:txn_id;type:varchar(128)"`
+	Type       uint8
+
+	LocalAmt    int64
+	BillAmt     int64
+	LocalStatus uint8
+	BillStatus  uint8
+	Fixed       bool
+	Ctime       int64
+}
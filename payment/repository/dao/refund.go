@@ -0,0 +1,206 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"gitee.com/geekbang/basic-go/webook/pkg/outbox"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+var (
+	ErrPaymentNotRefundable = errors.New("支付没有成功，不能退款")
+	ErrRefundExceeded       = errors.New("退款金额超过了可以退的金额")
+	ErrDuplicateRefund      = errors.New("退款单号重复")
+)
+
+type RefundDAO interface {
+	// Insert 会锁住支付记录，确保所有没有失败的退款加起来不会超过支付的金额。
+	// Amt 为 0 就是把剩下的全部退掉，返回的 Refund 里面是实际要退的金额。
+	// 退款单号已经有了就返回 ErrDuplicateRefund，不管支付现在还能不能退
+	Insert(ctx context.Context, r Refund) (Refund, error)
+	// UpdateTxnIDAndStatus 只会更新还在处理中的退款，全部退完之后，支付记录也会变成已退款。
+	// msgs 拿到更新之后的退款和支付状态，生成的消息在同一个事务里面写入 outbox。
+	// 退款已经有了结果的话，直接返回，不会再生成消息
+	UpdateTxnIDAndStatus(ctx context.Context, refundNO string, txnID string,
+		status domain.RefundStatus, msgs RefundMsgFunc) (Refund, error)
+	GetRefund(ctx context.Context, refundNO string) (Refund, error)
+	// FindPending 在 t 之前更新过，还在处理中的退款
+	FindPending(ctx context.Context, offset int, limit int, t time.Time) ([]Refund, error)
+	// FindPendingByBizTradeNO 某一笔支付还在处理中的退款
+	FindPendingByBizTradeNO(ctx context.Context, bizTradeNO string) ([]Refund, error)
+}
+
+type RefundMsgFunc func(r Refund, pmtStatus uint8) []outbox.Message
+
+type RefundGORMDAO struct {
+	db *gorm.DB
+}
+
+func NewRefundGORMDAO(db *gorm.DB) RefundDAO {
+	return &RefundGORMDAO{db: db}
+}
+
+func (r *RefundGORMDAO) Insert(ctx context.Context, refund Refund) (Refund, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var pmt Payment
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("biz_trade_no = ?", refund.BizTradeNO).
+			First(&pmt).Error
+		if err != nil {
+			return err
+		}
+		// 重复的退款单号要先查，不然全部退完之后重试，
+		// 会变成不能退款或者超过金额，调用者就没办法接着处理原来的退款了
+		var cnt int64
+		err = tx.Model(&Refund{}).Where("refund_no = ?", refund.RefundNO).Count(&cnt).Error
+		if err != nil {
+			return err
+		}
+		if cnt > 0 {
+			return ErrDuplicateRefund
+		}
+		if pmt.Status != domain.PaymentStatusSuccess {
+			return ErrPaymentNotRefundable
+		}
+		// 处理中和已经成功的都要算进去
+		var refunded int64
+		err = tx.Model(&Refund{}).
+			Select("COALESCE(SUM(amt), 0)").
+			Where("biz_trade_no = ? AND status <> ?",
+				refund.BizTradeNO, domain.RefundStatusFailed.AsUint8()).
+			Scan(&refunded).Error
+		if err != nil {
+			return err
+		}
+		if refund.Amt == 0 {
+			refund.Amt = pmt.Amt - refunded
+		}
+		if refund.Amt <= 0 || refunded+refund.Amt > pmt.Amt {
+			return ErrRefundExceeded
+		}
+		now := time.Now().UnixMilli()
+		refund.Currency = pmt.Currency
+		refund.PaymentAmt = pmt.Amt
+		refund.Status = domain.RefundStatusInit.AsUint8()
+		refund.Ctime = now
+		refund.Utime = now
+		err = tx.Create(&refund).Error
+		if me, ok := err.(*mysql.MySQLError); ok {
+			const duplicateErr uint16 = 1062
+			if me.Number == duplicateErr {
+				return ErrDuplicateRefund
+			}
+		}
+		return err
+	})
+	return refund, err
+}
+
+func (r *RefundGORMDAO) UpdateTxnIDAndStatus(ctx context.Context,
+	refundNO string, txnID string, status domain.RefundStatus, msgs RefundMsgFunc) (Refund, error) {
+	var res Refund
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UnixMilli()
+		updates := map[string]any{
+			"status": status.AsUint8(),
+			"utime":  now,
+		}
+		if txnID != "" {
+			updates["txn_id"] = txnID
+		}
+		// 重复的或者乱序的回调不能覆盖已经有结果的退款
+		upd := tx.Model(&Refund{}).
+			Where("refund_no = ? AND status = ?", refundNO, domain.RefundStatusInit.AsUint8()).
+			Updates(updates)
+		if upd.Error != nil {
+			return upd.Error
+		}
+		err := tx.Where("refund_no = ?", refundNO).First(&res).Error
+		if err != nil || upd.RowsAffected == 0 {
+			return err
+		}
+		pmtStatus, err := r.refundPayment(tx, res, now)
+		if err != nil {
+			return err
+		}
+		return outbox.Save(tx, msgs(res, pmtStatus)...)
+	})
+	return res, err
+}
+
+// refundPayment 退款成功并且全部退完了，就把支付记录标记为已退款。
+// 返回支付记录最新的状态
+func (r *RefundGORMDAO) refundPayment(tx *gorm.DB, res Refund, now int64) (uint8, error) {
+	var pmt Payment
+	// 锁住支付记录，并发的退款回调才不会都以为自己不是最后一笔
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("biz_trade_no = ?", res.BizTradeNO).First(&pmt).Error
+	if err != nil {
+		return 0, err
+	}
+	if res.Status != domain.RefundStatusSuccess.AsUint8() {
+		return pmt.Status, nil
+	}
+	var refunded int64
+	err = tx.Model(&Refund{}).
+		Select("COALESCE(SUM(amt), 0)").
+		Where("biz_trade_no = ? AND status = ?",
+			res.BizTradeNO, domain.RefundStatusSuccess.AsUint8()).
+		Scan(&refunded).Error
+	if err != nil {
+		return 0, err
+	}
+	if refunded < res.PaymentAmt {
+		return pmt.Status, nil
+	}
+	status := uint8(domain.PaymentStatusRefund)
+	return status, tx.Model(&Payment{}).
+		Where("biz_trade_no = ?", res.BizTradeNO).
+		Updates(map[string]any{
+			"status": status,
+			"utime":  now,
+		}).Error
+}
+
+func (r *RefundGORMDAO) GetRefund(ctx context.Context, refundNO string) (Refund, error) {
+	var res Refund
+	err := r.db.WithContext(ctx).Where("refund_no = ?", refundNO).First(&res).Error
+	return res, err
+}
+
+func (r *RefundGORMDAO) FindPending(ctx context.Context, offset int, limit int, t time.Time) ([]Refund, error) {
+	var res []Refund
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND utime < ?", domain.RefundStatusInit.AsUint8(), t.UnixMilli()).
+		Order("id").Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (r *RefundGORMDAO) FindPendingByBizTradeNO(ctx context.Context, bizTradeNO string) ([]Refund, error) {
+	var res []Refund
+	err := r.db.WithContext(ctx).
+		Where("biz_trade_no = ? AND status = ?", bizTradeNO, domain.RefundStatusInit.AsUint8()).
+		Order("id").Find(&res).Error
+	return res, err
+}
+
+type Refund struct {
+	Id         int64  `gorm:"primaryKey,autoIncrement"`
+	BizTradeNO string `gorm:"column:biz_trade_no;type:varchar(256);index"`
+	RefundNO   string `gorm:"column:refund_no;type:varchar(256);unique"`
+	Amt        int64
+	Currency   string
+	// PaymentAmt 冗余下来，第三方退款和判断是不是全部退完了都要用
+	PaymentAmt int64
+	Reason     string
+	// 第三方支付平台的退款 ID
+	TxnID  sql.NullString `gorm:"column:txn_id;type:varchar(128);unique"`
+	Status uint8
+	Utime  int64
+	Ctime  int64
+}
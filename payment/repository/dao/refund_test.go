@@ -0,0 +1,185 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"gitee.com/geekbang/basic-go/webook/payment/events"
+	"gitee.com/geekbang/basic-go/webook/pkg/outbox"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+)
+
+func TestRefundGORMDAO_UpdateTxnIDAndStatus(t *testing.T) {
+	refundCols := []string{"id", "biz_trade_no", "refund_no", "amt", "payment_amt", "status"}
+	testCases := []struct {
+		name   string
+		mock   func(mock sqlmock.Sqlmock)
+		status domain.RefundStatus
+
+		wantRefund Refund
+		wantMsgs   bool
+	}{
+		{
+			name:   "处理中的退款成功了",
+			status: domain.RefundStatusSuccess,
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `refunds` SET `status`=?,`txn_id`=?,`utime`=? "+
+					"WHERE refund_no = ? AND status = ?")).
+					WithArgs(domain.RefundStatusSuccess.AsUint8(), "txn-1", sqlmock.AnyArg(),
+						"refund-1", domain.RefundStatusInit.AsUint8()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT \\* FROM `refunds` .*").
+					WillReturnRows(sqlmock.NewRows(refundCols).
+						AddRow(1, "pmt-1", "refund-1", 50, 100, domain.RefundStatusSuccess.AsUint8()))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `payments` WHERE biz_trade_no = ? " +
+					"ORDER BY `payments`.`id` LIMIT ? FOR UPDATE")).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).
+						AddRow(1, uint8(domain.PaymentStatusSuccess)))
+				// 还没有全部退完
+				mock.ExpectQuery("SELECT COALESCE\\(SUM\\(amt\\), 0\\) FROM `refunds` .*").
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(50))
+				mock.ExpectExec("INSERT INTO `outbox_msgs` .*").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			wantRefund: Refund{Id: 1, BizTradeNO: "pmt-1", RefundNO: "refund-1", Amt: 50, PaymentAmt: 100,
+				Status: domain.RefundStatusSuccess.AsUint8()},
+			wantMsgs: true,
+		},
+		{
+			name:   "已经有结果了，重复的或者乱序的回调不会覆盖",
+			status: domain.RefundStatusFailed,
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `refunds` .*").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT \\* FROM `refunds` .*").
+					WillReturnRows(sqlmock.NewRows(refundCols).
+						AddRow(1, "pmt-1", "refund-1", 50, 100, domain.RefundStatusSuccess.AsUint8()))
+				mock.ExpectCommit()
+			},
+			wantRefund: Refund{Id: 1, BizTradeNO: "pmt-1", RefundNO: "refund-1", Amt: 50, PaymentAmt: 100,
+				Status: domain.RefundStatusSuccess.AsUint8()},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			tc.mock(mock)
+			dao := NewRefundGORMDAO(openMockDB(t, sqlDB))
+			called := false
+			res, err := dao.UpdateTxnIDAndStatus(context.Background(), "refund-1", "txn-1", tc.status,
+				func(r Refund, pmtStatus uint8) []outbox.Message {
+					called = true
+					return []outbox.Message{outbox.NewMessage(r.BizTradeNO, events.RefundEvent{BizTradeNO: r.BizTradeNO})}
+				})
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRefund, res)
+			assert.Equal(t, tc.wantMsgs, called)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRefundGORMDAO_Insert(t *testing.T) {
+	pmtSQL := regexp.QuoteMeta("SELECT * FROM `payments` WHERE biz_trade_no = ? " +
+		"ORDER BY `payments`.`id` LIMIT ? FOR UPDATE")
+	dupSQL := regexp.QuoteMeta("SELECT count(*) FROM `refunds` WHERE refund_no = ?")
+	pmtCols := []string{"id", "biz_trade_no", "amt", "currency", "status"}
+	testCases := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+		amt  int64
+
+		wantAmt int64
+		wantErr error
+	}{
+		{
+			name: "退掉剩下的全部",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(pmtSQL).WithArgs("pmt-1", 1).
+					WillReturnRows(sqlmock.NewRows(pmtCols).
+						AddRow(1, "pmt-1", 100, "CNY", uint8(domain.PaymentStatusSuccess)))
+				mock.ExpectQuery(dupSQL).WithArgs("refund-1").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectQuery("SELECT COALESCE\\(SUM\\(amt\\), 0\\) FROM `refunds` .*").
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(30))
+				mock.ExpectExec("INSERT INTO `refunds` .*").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			wantAmt: 70,
+		},
+		{
+			// 全部退完之后支付已经是已退款了，重试也要拿到重复的错误
+			name: "退款单号重复",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(pmtSQL).WithArgs("pmt-1", 1).
+					WillReturnRows(sqlmock.NewRows(pmtCols).
+						AddRow(1, "pmt-1", 100, "CNY", uint8(domain.PaymentStatusRefund)))
+				mock.ExpectQuery(dupSQL).WithArgs("refund-1").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectRollback()
+			},
+			wantErr: ErrDuplicateRefund,
+		},
+		{
+			name: "超过了可以退的金额",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(pmtSQL).WithArgs("pmt-1", 1).
+					WillReturnRows(sqlmock.NewRows(pmtCols).
+						AddRow(1, "pmt-1", 100, "CNY", uint8(domain.PaymentStatusSuccess)))
+				mock.ExpectQuery(dupSQL).WithArgs("refund-1").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectQuery("SELECT COALESCE\\(SUM\\(amt\\), 0\\) FROM `refunds` .*").
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(80))
+				mock.ExpectRollback()
+			},
+			amt:     50,
+			wantErr: ErrRefundExceeded,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			tc.mock(mock)
+			dao := NewRefundGORMDAO(openMockDB(t, sqlDB))
+			res, err := dao.Insert(context.Background(), Refund{
+				BizTradeNO: "pmt-1",
+				RefundNO:   "refund-1",
+				Amt:        tc.amt,
+			})
+			assert.Equal(t, tc.wantErr, err)
+			if err == nil {
+				assert.Equal(t, tc.wantAmt, res.Amt)
+				assert.Equal(t, "CNY", res.Currency)
+				assert.Equal(t, int64(100), res.PaymentAmt)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func openMockDB(t *testing.T, sqlDB *sql.DB) *gorm.DB {
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+	return db
+}
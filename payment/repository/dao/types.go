@@ -4,14 +4,31 @@ import (
 	"context"
 	"database/sql"
 	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"gitee.com/geekbang/basic-go/webook/pkg/outbox"
 	"time"
 )
 
 type PaymentDAO interface {
 	Insert(ctx context.Context, pmt Payment) error
-	UpdateTxnIDAndStatus(ctx context.Context, bizTradeNo string, txnID string, status domain.PaymentStatus) error
+	// UpdateTxnIDAndStatus 只有当前状态可以变成 status 的时候才会更新，否则什么都不做。
+	// 更新了的话，msgs 会和支付状态在同一个事务里面写入 outbox
+	UpdateTxnIDAndStatus(ctx context.Context, bizTradeNo string, txnID string,
+		status domain.PaymentStatus, msgs ...outbox.Message) error
 	FindExpiredPayment(ctx context.Context, offset int, limit int, t time.Time) ([]Payment, error)
 	GetPayment(ctx context.Context, bizTradeNO string) (Payment, error)
+	FindByBizTradeNOs(ctx context.Context, bizTradeNOs []string) ([]Payment, error)
+	// FindPayedPayments 某个渠道在 [start, end) 创建的，已经付了钱的支付
+	FindPayedPayments(ctx context.Context, channel uint8, start, end int64, offset, limit int) ([]Payment, error)
+
+	// FindToClose 到了 t 还没有付钱的，按照过期时间排序
+	FindToClose(ctx context.Context, t int64, limit int) ([]Payment, error)
+	// NextExpireTime 最早过期的那个未支付订单的过期时间，没有的话返回 0
+	NextExpireTime(ctx context.Context) (int64, error)
+	// Close 只会关闭还没有付钱的，返回是否真的关闭了。
+	// 关闭了的话 msgs 会在同一个事务里面写入 outbox
+	Close(ctx context.Context, bizTradeNO string, msgs ...outbox.Message) (bool, error)
+	// DelayClose 关闭失败了，推迟到 t 再关
+	DelayClose(ctx context.Context, bizTradeNO string, t int64) error
 }
 
 type Payment struct {
@@ -27,7 +44,6 @@ type Payment struct {
 	// 而是要求调用者直接 BizID 和 Biz 去找业务方要
 	// 管得越少，系统越稳
 	Description string `gorm:"description"`
-	// 后续可以考虑增加字段，来标记是用的是微信支付亦或是支付宝支付
 	// 也可以考虑提供一个巨大的 BLOB 字段，
 	// 来存储和支付有关的其它字段
 	//ExtraData string
@@ -38,7 +54,12 @@ type Payment struct {
 	// 第三方支付平台的事务 ID，唯一的
 	TxnID sql.NullString `gorm:"column:txn_id;type:varchar(128);unique"`
 
-	Status uint8
+	Status uint8 `gorm:"index:idx_status_expire_time"`
 	Utime  int64
 	Ctime  int64
+	// ExpireTime 以前的数据是 0，不会被自动关闭
+	ExpireTime int64 `gorm:"index:idx_status_expire_time"`
+
+	// Channel 微信、支付宝还是沙箱，以前的数据都是微信支付
+	Channel uint8 `gorm:"default:1"`
 }
@@ -14,6 +14,8 @@ import (
 	time "time"
 
 	domain "gitee.com/geekbang/basic-go/webook/payment/domain"
+	repository "gitee.com/geekbang/basic-go/webook/payment/repository"
+	outbox "gitee.com/geekbang/basic-go/webook/pkg/outbox"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -54,6 +56,55 @@ func (mr *MockPaymentRepositoryMockRecorder) AddPayment(ctx, pmt any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPayment", reflect.TypeOf((*MockPaymentRepository)(nil).AddPayment), ctx, pmt)
 }
 
+// ClosePayment mocks base method.
+func (m *MockPaymentRepository) ClosePayment(ctx context.Context, bizTradeNO string, msgs ...outbox.Message) (bool, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, bizTradeNO}
+	for _, a := range msgs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ClosePayment", varargs...)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClosePayment indicates an expected call of ClosePayment.
+func (mr *MockPaymentRepositoryMockRecorder) ClosePayment(ctx, bizTradeNO any, msgs ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, bizTradeNO}, msgs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClosePayment", reflect.TypeOf((*MockPaymentRepository)(nil).ClosePayment), varargs...)
+}
+
+// DelayClose mocks base method.
+func (m *MockPaymentRepository) DelayClose(ctx context.Context, bizTradeNO string, t time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DelayClose", ctx, bizTradeNO, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DelayClose indicates an expected call of DelayClose.
+func (mr *MockPaymentRepositoryMockRecorder) DelayClose(ctx, bizTradeNO, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DelayClose", reflect.TypeOf((*MockPaymentRepository)(nil).DelayClose), ctx, bizTradeNO, t)
+}
+
+// FindByBizTradeNOs mocks base method.
+func (m *MockPaymentRepository) FindByBizTradeNOs(ctx context.Context, bizTradeNOs []string) ([]domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByBizTradeNOs", ctx, bizTradeNOs)
+	ret0, _ := ret[0].([]domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByBizTradeNOs indicates an expected call of FindByBizTradeNOs.
+func (mr *MockPaymentRepositoryMockRecorder) FindByBizTradeNOs(ctx, bizTradeNOs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByBizTradeNOs", reflect.TypeOf((*MockPaymentRepository)(nil).FindByBizTradeNOs), ctx, bizTradeNOs)
+}
+
 // FindExpiredPayment mocks base method.
 func (m *MockPaymentRepository) FindExpiredPayment(ctx context.Context, offset, limit int, t time.Time) ([]domain.Payment, error) {
 	m.ctrl.T.Helper()
@@ -69,6 +120,36 @@ func (mr *MockPaymentRepositoryMockRecorder) FindExpiredPayment(ctx, offset, lim
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExpiredPayment", reflect.TypeOf((*MockPaymentRepository)(nil).FindExpiredPayment), ctx, offset, limit, t)
 }
 
+// FindPayedPayments mocks base method.
+func (m *MockPaymentRepository) FindPayedPayments(ctx context.Context, channel domain.PaymentChannel, start, end time.Time, offset, limit int) ([]domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPayedPayments", ctx, channel, start, end, offset, limit)
+	ret0, _ := ret[0].([]domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPayedPayments indicates an expected call of FindPayedPayments.
+func (mr *MockPaymentRepositoryMockRecorder) FindPayedPayments(ctx, channel, start, end, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPayedPayments", reflect.TypeOf((*MockPaymentRepository)(nil).FindPayedPayments), ctx, channel, start, end, offset, limit)
+}
+
+// FindToClose mocks base method.
+func (m *MockPaymentRepository) FindToClose(ctx context.Context, t time.Time, limit int) ([]domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindToClose", ctx, t, limit)
+	ret0, _ := ret[0].([]domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindToClose indicates an expected call of FindToClose.
+func (mr *MockPaymentRepositoryMockRecorder) FindToClose(ctx, t, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindToClose", reflect.TypeOf((*MockPaymentRepository)(nil).FindToClose), ctx, t, limit)
+}
+
 // GetPayment mocks base method.
 func (m *MockPaymentRepository) GetPayment(ctx context.Context, bizTradeNO string) (domain.Payment, error) {
 	m.ctrl.T.Helper()
@@ -84,16 +165,186 @@ func (mr *MockPaymentRepositoryMockRecorder) GetPayment(ctx, bizTradeNO any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayment", reflect.TypeOf((*MockPaymentRepository)(nil).GetPayment), ctx, bizTradeNO)
 }
 
+// NextExpireTime mocks base method.
+func (m *MockPaymentRepository) NextExpireTime(ctx context.Context) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextExpireTime", ctx)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextExpireTime indicates an expected call of NextExpireTime.
+func (mr *MockPaymentRepositoryMockRecorder) NextExpireTime(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextExpireTime", reflect.TypeOf((*MockPaymentRepository)(nil).NextExpireTime), ctx)
+}
+
 // UpdatePayment mocks base method.
-func (m *MockPaymentRepository) UpdatePayment(ctx context.Context, pmt domain.Payment) error {
+func (m *MockPaymentRepository) UpdatePayment(ctx context.Context, pmt domain.Payment, msgs ...outbox.Message) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdatePayment", ctx, pmt)
+	varargs := []any{ctx, pmt}
+	for _, a := range msgs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdatePayment", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdatePayment indicates an expected call of UpdatePayment.
-func (mr *MockPaymentRepositoryMockRecorder) UpdatePayment(ctx, pmt any) *gomock.Call {
+func (mr *MockPaymentRepositoryMockRecorder) UpdatePayment(ctx, pmt any, msgs ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, pmt}, msgs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePayment", reflect.TypeOf((*MockPaymentRepository)(nil).UpdatePayment), varargs...)
+}
+
+// MockRefundRepository is a mock of RefundRepository interface.
+type MockRefundRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefundRepositoryMockRecorder
+}
+
+// MockRefundRepositoryMockRecorder is the mock recorder for MockRefundRepository.
+type MockRefundRepositoryMockRecorder struct {
+	mock *MockRefundRepository
+}
+
+// NewMockRefundRepository creates a new mock instance.
+func NewMockRefundRepository(ctrl *gomock.Controller) *MockRefundRepository {
+	mock := &MockRefundRepository{ctrl: ctrl}
+	mock.recorder = &MockRefundRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefundRepository) EXPECT() *MockRefundRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddRefund mocks base method.
+func (m *MockRefundRepository) AddRefund(ctx context.Context, r domain.Refund) (domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRefund", ctx, r)
+	ret0, _ := ret[0].(domain.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddRefund indicates an expected call of AddRefund.
+func (mr *MockRefundRepositoryMockRecorder) AddRefund(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRefund", reflect.TypeOf((*MockRefundRepository)(nil).AddRefund), ctx, r)
+}
+
+// FindPendingRefunds mocks base method.
+func (m *MockRefundRepository) FindPendingRefunds(ctx context.Context, offset, limit int, t time.Time) ([]domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPendingRefunds", ctx, offset, limit, t)
+	ret0, _ := ret[0].([]domain.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPendingRefunds indicates an expected call of FindPendingRefunds.
+func (mr *MockRefundRepositoryMockRecorder) FindPendingRefunds(ctx, offset, limit, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPendingRefunds", reflect.TypeOf((*MockRefundRepository)(nil).FindPendingRefunds), ctx, offset, limit, t)
+}
+
+// FindPendingRefundsByBizTradeNO mocks base method.
+func (m *MockRefundRepository) FindPendingRefundsByBizTradeNO(ctx context.Context, bizTradeNO string) ([]domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPendingRefundsByBizTradeNO", ctx, bizTradeNO)
+	ret0, _ := ret[0].([]domain.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPendingRefundsByBizTradeNO indicates an expected call of FindPendingRefundsByBizTradeNO.
+func (mr *MockRefundRepositoryMockRecorder) FindPendingRefundsByBizTradeNO(ctx, bizTradeNO any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPendingRefundsByBizTradeNO", reflect.TypeOf((*MockRefundRepository)(nil).FindPendingRefundsByBizTradeNO), ctx, bizTradeNO)
+}
+
+// GetRefund mocks base method.
+func (m *MockRefundRepository) GetRefund(ctx context.Context, refundNO string) (domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefund", ctx, refundNO)
+	ret0, _ := ret[0].(domain.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefund indicates an expected call of GetRefund.
+func (mr *MockRefundRepositoryMockRecorder) GetRefund(ctx, refundNO any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefund", reflect.TypeOf((*MockRefundRepository)(nil).GetRefund), ctx, refundNO)
+}
+
+// UpdateRefund mocks base method.
+func (m *MockRefundRepository) UpdateRefund(ctx context.Context, r domain.Refund, msgs repository.RefundMsgFunc) (domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRefund", ctx, r, msgs)
+	ret0, _ := ret[0].(domain.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRefund indicates an expected call of UpdateRefund.
+func (mr *MockRefundRepositoryMockRecorder) UpdateRefund(ctx, r, msgs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRefund", reflect.TypeOf((*MockRefundRepository)(nil).UpdateRefund), ctx, r, msgs)
+}
+
+// MockReconcileRepository is a mock of ReconcileRepository interface.
+type MockReconcileRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReconcileRepositoryMockRecorder
+}
+
+// MockReconcileRepositoryMockRecorder is the mock recorder for MockReconcileRepository.
+type MockReconcileRepositoryMockRecorder struct {
+	mock *MockReconcileRepository
+}
+
+// NewMockReconcileRepository creates a new mock instance.
+func NewMockReconcileRepository(ctrl *gomock.Controller) *MockReconcileRepository {
+	mock := &MockReconcileRepository{ctrl: ctrl}
+	mock.recorder = &MockReconcileRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReconcileRepository) EXPECT() *MockReconcileRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetReport mocks base method.
+func (m *MockReconcileRepository) GetReport(ctx context.Context, channel domain.PaymentChannel, date string) (domain.ReconcileReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReport", ctx, channel, date)
+	ret0, _ := ret[0].(domain.ReconcileReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReport indicates an expected call of GetReport.
+func (mr *MockReconcileRepositoryMockRecorder) GetReport(ctx, channel, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReport", reflect.TypeOf((*MockReconcileRepository)(nil).GetReport), ctx, channel, date)
+}
+
+// SaveReport mocks base method.
+func (m *MockReconcileRepository) SaveReport(ctx context.Context, r domain.ReconcileReport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveReport", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveReport indicates an expected call of SaveReport.
+func (mr *MockReconcileRepositoryMockRecorder) SaveReport(ctx, r any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePayment", reflect.TypeOf((*MockPaymentRepository)(nil).UpdatePayment), ctx, pmt)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveReport", reflect.TypeOf((*MockReconcileRepository)(nil).SaveReport), ctx, r)
 }
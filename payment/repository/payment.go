@@ -4,6 +4,8 @@ import (
 	"context"
 	"gitee.com/geekbang/basic-go/webook/payment/domain"
 	"gitee.com/geekbang/basic-go/webook/payment/repository/dao"
+	"gitee.com/geekbang/basic-go/webook/pkg/outbox"
+	"github.com/ecodeclub/ekit/slice"
 	"time"
 )
 
@@ -28,6 +30,45 @@ func (p *paymentRepository) FindExpiredPayment(ctx context.Context, offset int,
 	return res, nil
 }
 
+func (p *paymentRepository) FindByBizTradeNOs(ctx context.Context, bizTradeNOs []string) ([]domain.Payment, error) {
+	pmts, err := p.dao.FindByBizTradeNOs(ctx, bizTradeNOs)
+	return slice.Map(pmts, func(idx int, src dao.Payment) domain.Payment {
+		return p.toDomain(src)
+	}), err
+}
+
+func (p *paymentRepository) FindPayedPayments(ctx context.Context, channel domain.PaymentChannel,
+	start, end time.Time, offset, limit int) ([]domain.Payment, error) {
+	pmts, err := p.dao.FindPayedPayments(ctx, channel.AsUint8(),
+		start.UnixMilli(), end.UnixMilli(), offset, limit)
+	return slice.Map(pmts, func(idx int, src dao.Payment) domain.Payment {
+		return p.toDomain(src)
+	}), err
+}
+
+func (p *paymentRepository) FindToClose(ctx context.Context, t time.Time, limit int) ([]domain.Payment, error) {
+	pmts, err := p.dao.FindToClose(ctx, t.UnixMilli(), limit)
+	return slice.Map(pmts, func(idx int, src dao.Payment) domain.Payment {
+		return p.toDomain(src)
+	}), err
+}
+
+func (p *paymentRepository) NextExpireTime(ctx context.Context) (time.Time, error) {
+	t, err := p.dao.NextExpireTime(ctx)
+	if err != nil || t == 0 {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(t), nil
+}
+
+func (p *paymentRepository) ClosePayment(ctx context.Context, bizTradeNO string, msgs ...outbox.Message) (bool, error) {
+	return p.dao.Close(ctx, bizTradeNO, msgs...)
+}
+
+func (p *paymentRepository) DelayClose(ctx context.Context, bizTradeNO string, t time.Time) error {
+	return p.dao.DelayClose(ctx, bizTradeNO, t.UnixMilli())
+}
+
 func (p *paymentRepository) AddPayment(ctx context.Context, pmt domain.Payment) error {
 	return p.dao.Insert(ctx, p.toEntity(pmt))
 }
@@ -42,9 +83,19 @@ func (p *paymentRepository) toDomain(pmt dao.Payment) domain.Payment {
 		Description: pmt.Description,
 		Status:      domain.PaymentStatus(pmt.Status),
 		TxnID:       pmt.TxnID.String,
+		Channel:     domain.PaymentChannel(pmt.Channel),
+		ExpireTime:  p.toTime(pmt.ExpireTime),
 	}
 }
 
+// toTime 0 代表没有设置
+func (p *paymentRepository) toTime(t int64) time.Time {
+	if t == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(t)
+}
+
 func (p *paymentRepository) toEntity(pmt domain.Payment) dao.Payment {
 	return dao.Payment{
 		Amt:         pmt.Amt.Total,
@@ -52,11 +103,20 @@ func (p *paymentRepository) toEntity(pmt domain.Payment) dao.Payment {
 		BizTradeNO:  pmt.BizTradeNO,
 		Description: pmt.Description,
 		Status:      domain.PaymentStatusInit,
+		Channel:     pmt.Channel.AsUint8(),
+		ExpireTime:  p.toMilli(pmt.ExpireTime),
+	}
+}
+
+func (p *paymentRepository) toMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
 	}
+	return t.UnixMilli()
 }
 
-func (p *paymentRepository) UpdatePayment(ctx context.Context, pmt domain.Payment) error {
-	return p.dao.UpdateTxnIDAndStatus(ctx, pmt.BizTradeNO, pmt.TxnID, pmt.Status)
+func (p *paymentRepository) UpdatePayment(ctx context.Context, pmt domain.Payment, msgs ...outbox.Message) error {
+	return p.dao.UpdateTxnIDAndStatus(ctx, pmt.BizTradeNO, pmt.TxnID, pmt.Status, msgs...)
 }
 
 func NewPaymentRepository(d dao.PaymentDAO) PaymentRepository {
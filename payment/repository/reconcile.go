@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"gitee.com/geekbang/basic-go/webook/payment/repository/dao"
+	"github.com/ecodeclub/ekit/slice"
+)
+
+var ErrReportNotFound = dao.ErrReportNotFound
+
+type reconcileRepository struct {
+	dao dao.ReconcileDAO
+}
+
+func NewReconcileRepository(d dao.ReconcileDAO) ReconcileRepository {
+	return &reconcileRepository{dao: d}
+}
+
+func (r *reconcileRepository) SaveReport(ctx context.Context, report domain.ReconcileReport) error {
+	return r.dao.SaveReport(ctx, dao.ReconcileReport{
+		Channel:   report.Channel.AsUint8(),
+		Date:      report.Date,
+		BillCount: int64(report.BillCount),
+		DiffCount: int64(len(report.Diffs)),
+	}, slice.Map(report.Diffs, func(idx int, src domain.ReconcileDiff) dao.ReconcileDiff {
+		return dao.ReconcileDiff{
+			BizTradeNO:  src.BizTradeNO,
+			TxnID:       src.TxnID,
+			Type:        src.Type.AsUint8(),
+			LocalAmt:    src.LocalAmt,
+			BillAmt:     src.BillAmt,
+			LocalStatus: src.LocalStatus.AsUint8(),
+			BillStatus:  src.BillStatus.AsUint8(),
+			Fixed:       src.Fixed,
+		}
+	}))
+}
+
+func (r *reconcileRepository) GetReport(ctx context.Context,
+	channel domain.PaymentChannel, date string) (domain.ReconcileReport, error) {
+	report, diffs, err := r.dao.GetReport(ctx, channel.AsUint8(), date)
+	if err != nil {
+		return domain.ReconcileReport{}, err
+	}
+	return domain.ReconcileReport{
+		Channel:   domain.PaymentChannel(report.Channel),
+		Date:      report.Date,
+		BillCount: int(report.BillCount),
+		Diffs: slice.Map(diffs, func(idx int, src dao.ReconcileDiff) domain.ReconcileDiff {
+			return domain.ReconcileDiff{
+				BizTradeNO:  src.BizTradeNO,
+				TxnID:       src.TxnID,
+				Type:        domain.ReconcileDiffType(src.Type),
+				LocalAmt:    src.LocalAmt,
+				BillAmt:     src.BillAmt,
+				LocalStatus: domain.PaymentStatus(src.LocalStatus),
+				BillStatus:  domain.PaymentStatus(src.BillStatus),
+				Fixed:       src.Fixed,
+			}
+		}),
+	}, nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"gitee.com/geekbang/basic-go/webook/payment/repository/dao"
+	"gitee.com/geekbang/basic-go/webook/pkg/outbox"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+)
+
+var (
+	ErrPaymentNotRefundable = dao.ErrPaymentNotRefundable
+	ErrRefundExceeded       = dao.ErrRefundExceeded
+	ErrDuplicateRefund      = dao.ErrDuplicateRefund
+)
+
+type refundRepository struct {
+	dao dao.RefundDAO
+}
+
+func NewRefundRepository(d dao.RefundDAO) RefundRepository {
+	return &refundRepository{dao: d}
+}
+
+func (r *refundRepository) AddRefund(ctx context.Context, refund domain.Refund) (domain.Refund, error) {
+	res, err := r.dao.Insert(ctx, r.toEntity(refund))
+	return r.toDomain(res), err
+}
+
+func (r *refundRepository) UpdateRefund(ctx context.Context,
+	refund domain.Refund, msgs RefundMsgFunc) (domain.Refund, error) {
+	res, err := r.dao.UpdateTxnIDAndStatus(ctx, refund.RefundNO, refund.TxnID, refund.Status,
+		func(res dao.Refund, pmtStatus uint8) []outbox.Message {
+			return msgs(r.toDomain(res), domain.PaymentStatus(pmtStatus))
+		})
+	return r.toDomain(res), err
+}
+
+func (r *refundRepository) GetRefund(ctx context.Context, refundNO string) (domain.Refund, error) {
+	res, err := r.dao.GetRefund(ctx, refundNO)
+	return r.toDomain(res), err
+}
+
+func (r *refundRepository) FindPendingRefunds(ctx context.Context,
+	offset int, limit int, t time.Time) ([]domain.Refund, error) {
+	refunds, err := r.dao.FindPending(ctx, offset, limit, t)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(refunds, func(idx int, src dao.Refund) domain.Refund {
+		return r.toDomain(src)
+	}), nil
+}
+
+func (r *refundRepository) FindPendingRefundsByBizTradeNO(ctx context.Context,
+	bizTradeNO string) ([]domain.Refund, error) {
+	refunds, err := r.dao.FindPendingByBizTradeNO(ctx, bizTradeNO)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(refunds, func(idx int, src dao.Refund) domain.Refund {
+		return r.toDomain(src)
+	}), nil
+}
+
+func (r *refundRepository) toEntity(refund domain.Refund) dao.Refund {
+	return dao.Refund{
+		BizTradeNO: refund.BizTradeNO,
+		RefundNO:   refund.RefundNO,
+		Amt:        refund.Amt.Total,
+		Currency:   refund.Amt.Currency,
+		PaymentAmt: refund.PaymentAmt,
+		Reason:     refund.Reason,
+		TxnID: sql.NullString{
+			String: refund.TxnID,
+			Valid:  refund.TxnID != "",
+		},
+		Status: refund.Status.AsUint8(),
+	}
+}
+
+func (r *refundRepository) toDomain(refund dao.Refund) domain.Refund {
+	return domain.Refund{
+		BizTradeNO: refund.BizTradeNO,
+		RefundNO:   refund.RefundNO,
+		Amt: domain.Amount{
+			Currency: refund.Currency,
+			Total:    refund.Amt,
+		},
+		PaymentAmt: refund.PaymentAmt,
+		Reason:     refund.Reason,
+		Status:     domain.RefundStatus(refund.Status),
+		TxnID:      refund.TxnID.String,
+	}
+}
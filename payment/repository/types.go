@@ -3,14 +3,44 @@ package repository
 import (
 	"context"
 	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"gitee.com/geekbang/basic-go/webook/pkg/outbox"
 	"time"
 )
 
 //go:generate mockgen -source=types.go -destination=mocks/payment.mock.go --package=repomocks PaymentRepository
 type PaymentRepository interface {
 	AddPayment(ctx context.Context, pmt domain.Payment) error
-	// UpdatePayment 这个设计有点差，因为
-	UpdatePayment(ctx context.Context, pmt domain.Payment) error
+	// UpdatePayment msgs 和支付状态在同一个事务里面提交，之后由 outbox 发出去
+	UpdatePayment(ctx context.Context, pmt domain.Payment, msgs ...outbox.Message) error
 	FindExpiredPayment(ctx context.Context, offset int, limit int, t time.Time) ([]domain.Payment, error)
 	GetPayment(ctx context.Context, bizTradeNO string) (domain.Payment, error)
+	FindByBizTradeNOs(ctx context.Context, bizTradeNOs []string) ([]domain.Payment, error)
+	// FindPayedPayments 某个渠道在 [start, end) 创建的，已经付了钱的支付
+	FindPayedPayments(ctx context.Context, channel domain.PaymentChannel,
+		start, end time.Time, offset, limit int) ([]domain.Payment, error)
+	// FindToClose 到了 t 还没有付钱的
+	FindToClose(ctx context.Context, t time.Time, limit int) ([]domain.Payment, error)
+	// NextExpireTime 没有未支付的订单的话，返回零值
+	NextExpireTime(ctx context.Context) (time.Time, error)
+	// ClosePayment 返回是否真的关闭了，关闭了才会发送 msgs
+	ClosePayment(ctx context.Context, bizTradeNO string, msgs ...outbox.Message) (bool, error)
+	DelayClose(ctx context.Context, bizTradeNO string, t time.Time) error
+}
+
+type RefundMsgFunc func(r domain.Refund, pmtStatus domain.PaymentStatus) []outbox.Message
+
+type RefundRepository interface {
+	// AddRefund 返回的 Refund 里面是实际要退的金额和原本支付的金额
+	AddRefund(ctx context.Context, r domain.Refund) (domain.Refund, error)
+	// UpdateRefund msgs 拿到更新之后的退款和支付状态，生成要发送的消息
+	UpdateRefund(ctx context.Context, r domain.Refund, msgs RefundMsgFunc) (domain.Refund, error)
+	GetRefund(ctx context.Context, refundNO string) (domain.Refund, error)
+	// FindPendingRefunds 在 t 之前更新过，还在处理中的退款
+	FindPendingRefunds(ctx context.Context, offset int, limit int, t time.Time) ([]domain.Refund, error)
+	FindPendingRefundsByBizTradeNO(ctx context.Context, bizTradeNO string) ([]domain.Refund, error)
+}
+
+type ReconcileRepository interface {
+	SaveReport(ctx context.Context, r domain.ReconcileReport) error
+	GetReport(ctx context.Context, channel domain.PaymentChannel, date string) (domain.ReconcileReport, error)
 }
@@ -0,0 +1,246 @@
+package alipay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"gitee.com/geekbang/basic-go/webook/payment/service"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/smartwalle/alipay/v3"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var (
+	errUnknownTradeStatus = errors.New("未知的支付宝交易状态")
+	errAlipayFailure      = errors.New("支付宝返回了错误")
+	errAppIDMismatch      = errors.New("支付宝通知的 app_id 不是我们的")
+	errInvalidAmount      = errors.New("支付宝的金额格式不对")
+)
+
+// Client 用到的支付宝接口，就是 *alipay.Client
+type Client interface {
+	TradePagePay(param alipay.TradePagePay) (*url.URL, error)
+	TradePreCreate(ctx context.Context, param alipay.TradePreCreate) (*alipay.TradePreCreateRsp, error)
+	TradeQuery(ctx context.Context, param alipay.TradeQuery) (*alipay.TradeQueryRsp, error)
+	TradeClose(ctx context.Context, param alipay.TradeClose) (*alipay.TradeCloseRsp, error)
+	TradeRefund(ctx context.Context, param alipay.TradeRefund) (*alipay.TradeRefundRsp, error)
+	TradeFastPayRefundQuery(ctx context.Context,
+		param alipay.TradeFastPayRefundQuery) (*alipay.TradeFastPayRefundQueryRsp, error)
+	DecodeNotification(values url.Values) (*alipay.Notification, error)
+}
+
+// 支付宝的对账单是压缩包，格式和微信的也不一样，暂时没有实现 service.BillProvider
+var _ service.Provider = (*PaymentService)(nil)
+
+// PaymentService 支付宝渠道，支持扫码支付和电脑网站支付
+type PaymentService struct {
+	client Client
+	// appID 异步通知里面的 app_id 必须是这个
+	appID string
+	// 异步通知的 URL
+	notifyURL string
+	// returnURL 不为空就用电脑网站支付，付完钱跳回这个页面；
+	// 为空就是扫码支付
+	returnURL string
+
+	l logger.LoggerV1
+
+	// 支付宝的交易状态
+	// WAIT_BUYER_PAY：交易创建，等待买家付款
+	// TRADE_CLOSED：未付款交易超时关闭，或支付完成后全额退款
+	// TRADE_SUCCESS：交易支付成功
+	// TRADE_FINISHED：交易结束，不可退款
+	tradeStatusToStatus map[alipay.TradeStatus]domain.PaymentStatus
+}
+
+func NewPaymentService(client Client, appID string,
+	notifyURL string, returnURL string,
+	l logger.LoggerV1) *PaymentService {
+	return &PaymentService{
+		client:    client,
+		appID:     appID,
+		notifyURL: notifyURL,
+		returnURL: returnURL,
+		l:         l,
+		tradeStatusToStatus: map[alipay.TradeStatus]domain.PaymentStatus{
+			alipay.TradeStatusWaitBuyerPay: domain.PaymentStatusInit,
+			alipay.TradeStatusClosed:       domain.PaymentStatusClosed,
+			alipay.TradeStatusSuccess:      domain.PaymentStatusSuccess,
+			alipay.TradeStatusFinished:     domain.PaymentStatusSuccess,
+		},
+	}
+}
+
+func (a *PaymentService) Channel() domain.PaymentChannel {
+	return domain.PaymentChannelAlipay
+}
+
+func (a *PaymentService) Prepay(ctx context.Context, pmt domain.Payment) (string, error) {
+	trade := alipay.Trade{
+		NotifyURL:   a.notifyURL,
+		Subject:     pmt.Description,
+		OutTradeNo:  pmt.BizTradeNO,
+		TotalAmount: toYuan(pmt.Amt.Total),
+		// 和我们自己的过期时间保持一致，到期之后我们会主动关单
+		TimeExpire: pmt.ExpireTime.Format("2006-01-02 15:04:05"),
+	}
+	if a.returnURL != "" {
+		trade.ReturnURL = a.returnURL
+		trade.ProductCode = "FAST_INSTANT_TRADE_PAY"
+		u, err := a.client.TradePagePay(alipay.TradePagePay{Trade: trade})
+		if err != nil {
+			return "", err
+		}
+		return u.String(), nil
+	}
+	trade.ProductCode = "FACE_TO_FACE_PAYMENT"
+	resp, err := a.client.TradePreCreate(ctx, alipay.TradePreCreate{Trade: trade})
+	if err != nil {
+		return "", err
+	}
+	if resp.IsFailure() {
+		return "", fmt.Errorf("%w, %s %s", errAlipayFailure, resp.Code, resp.SubMsg)
+	}
+	return resp.QRCode, nil
+}
+
+func (a *PaymentService) QueryPayment(ctx context.Context, bizTradeNO string) (domain.Payment, error) {
+	resp, err := a.client.TradeQuery(ctx, alipay.TradeQuery{OutTradeNo: bizTradeNO})
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	if resp.IsFailure() {
+		// 用户还没有扫码的话，支付宝那边是查不到这个交易的
+		if resp.SubCode == "ACQ.TRADE_NOT_EXIST" {
+			return domain.Payment{BizTradeNO: bizTradeNO, Status: domain.PaymentStatusInit}, nil
+		}
+		return domain.Payment{}, fmt.Errorf("%w, %s %s", errAlipayFailure, resp.Code, resp.SubMsg)
+	}
+	return a.toPayment(bizTradeNO, resp.TradeNo, resp.TradeStatus)
+}
+
+func (a *PaymentService) ClosePayment(ctx context.Context, bizTradeNO string) error {
+	resp, err := a.client.TradeClose(ctx, alipay.TradeClose{OutTradeNo: bizTradeNO})
+	if err != nil {
+		return err
+	}
+	// 用户没有扫码的话支付宝那边还没有这个交易，也就不用关了
+	if resp.IsFailure() && resp.SubCode != "ACQ.TRADE_NOT_EXIST" {
+		return fmt.Errorf("%w, %s %s", errAlipayFailure, resp.Code, resp.SubMsg)
+	}
+	return nil
+}
+
+func (a *PaymentService) Refund(ctx context.Context, r domain.Refund) (domain.Refund, error) {
+	resp, err := a.client.TradeRefund(ctx, alipay.TradeRefund{
+		OutTradeNo:   r.BizTradeNO,
+		OutRequestNo: r.RefundNO,
+		RefundAmount: toYuan(r.Amt.Total),
+		RefundReason: r.Reason,
+	})
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	if resp.IsFailure() {
+		return domain.Refund{}, fmt.Errorf("%w, %s %s", errAlipayFailure, resp.Code, resp.SubMsg)
+	}
+	// 支付宝的退款接口是同步的，资金发生了变化就是退款成功了。
+	// 同一个退款单号重复退款的时候资金不会再变化，所以要再查一下，
+	// 不然已经成功的退款会被当成处理中
+	if resp.FundChange == "Y" {
+		return domain.Refund{RefundNO: r.RefundNO, TxnID: resp.TradeNo, Status: domain.RefundStatusSuccess}, nil
+	}
+	return a.QueryRefund(ctx, r)
+}
+
+func (a *PaymentService) QueryRefund(ctx context.Context, r domain.Refund) (domain.Refund, error) {
+	resp, err := a.client.TradeFastPayRefundQuery(ctx, alipay.TradeFastPayRefundQuery{
+		OutTradeNo:   r.BizTradeNO,
+		OutRequestNo: r.RefundNO,
+	})
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	if resp.IsFailure() {
+		return domain.Refund{}, fmt.Errorf("%w, %s %s", errAlipayFailure, resp.Code, resp.SubMsg)
+	}
+	res := domain.Refund{RefundNO: r.RefundNO, TxnID: resp.TradeNo, Status: domain.RefundStatusInit}
+	switch {
+	case resp.RefundStatus == "REFUND_SUCCESS":
+		res.Status = domain.RefundStatusSuccess
+	case resp.OutRequestNo == "":
+		// 查不到这笔退款，说明支付宝没有受理
+		res.Status = domain.RefundStatusFailed
+	}
+	return res, nil
+}
+
+// DecodeNotification 验签并且解析支付宝的异步通知。
+// 退款也是通过这个通知过来的，这个时候带着 out_biz_no，返回的 Refund 不为 nil。
+// 返回的 Payment 里面带着通知的金额，由调用者和我们的支付记录比较
+func (a *PaymentService) DecodeNotification(values url.Values) (domain.Payment, *domain.Refund, error) {
+	noti, err := a.client.DecodeNotification(values)
+	if err != nil {
+		return domain.Payment{}, nil, err
+	}
+	// 同一个支付宝公钥验签通过的，也可能是别的应用的通知
+	if noti.AppId != a.appID {
+		return domain.Payment{}, nil, fmt.Errorf("%w, app_id 是 %s", errAppIDMismatch, noti.AppId)
+	}
+	if noti.OutBizNo != "" {
+		return domain.Payment{}, &domain.Refund{
+			BizTradeNO: noti.OutTradeNo,
+			RefundNO:   noti.OutBizNo,
+			TxnID:      noti.TradeNo,
+			Status:     domain.RefundStatusSuccess,
+		}, nil
+	}
+	pmt, err := a.toPayment(noti.OutTradeNo, noti.TradeNo, noti.TradeStatus)
+	if err != nil {
+		return domain.Payment{}, nil, err
+	}
+	total, err := toFen(noti.TotalAmount)
+	if err != nil {
+		return domain.Payment{}, nil, err
+	}
+	// 支付宝只有人民币
+	pmt.Amt = domain.Amount{Currency: "CNY", Total: total}
+	return pmt, nil, nil
+}
+
+func (a *PaymentService) toPayment(bizTradeNO, tradeNO string, st alipay.TradeStatus) (domain.Payment, error) {
+	status, ok := a.tradeStatusToStatus[st]
+	if !ok {
+		return domain.Payment{}, fmt.Errorf("%w, 支付宝的状态是 %s", errUnknownTradeStatus, st)
+	}
+	return domain.Payment{
+		BizTradeNO: bizTradeNO,
+		TxnID:      tradeNO,
+		Status:     status,
+		Channel:    domain.PaymentChannelAlipay,
+	}, nil
+}
+
+// toFen 是 toYuan 的逆过程，最多两位小数，不能是负数
+func toFen(yuan string) (int64, error) {
+	intPart, fracPart, _ := strings.Cut(yuan, ".")
+	if intPart == "" || len(fracPart) > 2 {
+		return 0, fmt.Errorf("%w, %s", errInvalidAmount, yuan)
+	}
+	for len(fracPart) < 2 {
+		fracPart += "0"
+	}
+	total, err := strconv.ParseUint(intPart+fracPart, 10, 63)
+	if err != nil {
+		return 0, fmt.Errorf("%w, %s", errInvalidAmount, yuan)
+	}
+	return int64(total), nil
+}
+
+// toYuan 我们的金额是分，支付宝用的是元，保留两位小数
+func toYuan(total int64) string {
+	return fmt.Sprintf("%d.%02d", total/100, total%100)
+}
@@ -0,0 +1,178 @@
+package alipay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/smartwalle/alipay/v3"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+)
+
+func TestPaymentService_DecodeNotification(t *testing.T) {
+	testCases := []struct {
+		name string
+		noti *alipay.Notification
+		err  error
+
+		wantPmt    domain.Payment
+		wantRefund *domain.Refund
+		wantErr    error
+	}{
+		{
+			name: "支付成功",
+			noti: &alipay.Notification{AppId: "app-1", OutTradeNo: "reward-1", TradeNo: "txn-1",
+				TradeStatus: alipay.TradeStatusSuccess, TotalAmount: "12.30"},
+			wantPmt: domain.Payment{BizTradeNO: "reward-1", TxnID: "txn-1",
+				Status: domain.PaymentStatusSuccess, Channel: domain.PaymentChannelAlipay,
+				Amt: domain.Amount{Currency: "CNY", Total: 1230}},
+		},
+		{
+			name: "退款成功",
+			noti: &alipay.Notification{AppId: "app-1", OutTradeNo: "reward-1", TradeNo: "txn-1",
+				OutBizNo: "refund-1", TradeStatus: alipay.TradeStatusSuccess, TotalAmount: "12.30"},
+			wantRefund: &domain.Refund{BizTradeNO: "reward-1", RefundNO: "refund-1", TxnID: "txn-1",
+				Status: domain.RefundStatusSuccess},
+		},
+		{
+			name: "别的应用的通知",
+			noti: &alipay.Notification{AppId: "app-2", OutTradeNo: "reward-1", TradeNo: "txn-1",
+				TradeStatus: alipay.TradeStatusSuccess, TotalAmount: "12.30"},
+			wantErr: fmt.Errorf("%w, app_id 是 %s", errAppIDMismatch, "app-2"),
+		},
+		{
+			name: "金额格式不对",
+			noti: &alipay.Notification{AppId: "app-1", OutTradeNo: "reward-1", TradeNo: "txn-1",
+				TradeStatus: alipay.TradeStatusSuccess, TotalAmount: "-12.30"},
+			wantErr: fmt.Errorf("%w, %s", errInvalidAmount, "-12.30"),
+		},
+		{
+			name: "不认识的交易状态",
+			noti: &alipay.Notification{AppId: "app-1", OutTradeNo: "reward-1", TradeNo: "txn-1",
+				TradeStatus: "UNKNOWN", TotalAmount: "12.30"},
+			wantErr: fmt.Errorf("%w, 支付宝的状态是 %s", errUnknownTradeStatus, "UNKNOWN"),
+		},
+		{
+			name:    "验签失败",
+			err:     errors.New("验签失败"),
+			wantErr: errors.New("验签失败"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := NewPaymentService(&fakeClient{noti: tc.noti, err: tc.err}, "app-1",
+				"", "", logger.NewNopLogger())
+			pmt, refund, err := svc.DecodeNotification(url.Values{})
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantPmt, pmt)
+			assert.Equal(t, tc.wantRefund, refund)
+		})
+	}
+}
+
+func TestPaymentService_Refund(t *testing.T) {
+	success := alipay.Error{Code: alipay.CodeSuccess}
+	testCases := []struct {
+		name   string
+		client *fakeClient
+
+		wantRefund domain.Refund
+		wantErr    error
+	}{
+		{
+			name: "资金发生了变化，退款成功",
+			client: &fakeClient{refund: &alipay.TradeRefundRsp{Error: success,
+				TradeNo: "txn-1", FundChange: "Y"}},
+			wantRefund: domain.Refund{RefundNO: "refund-1", TxnID: "txn-1", Status: domain.RefundStatusSuccess},
+		},
+		{
+			name: "重复退款，资金没有变化，查出来已经成功了",
+			client: &fakeClient{
+				refund: &alipay.TradeRefundRsp{Error: success, TradeNo: "txn-1", FundChange: "N"},
+				refundQuery: &alipay.TradeFastPayRefundQueryRsp{Error: success, TradeNo: "txn-1",
+					OutRequestNo: "refund-1", RefundStatus: "REFUND_SUCCESS"},
+			},
+			wantRefund: domain.Refund{RefundNO: "refund-1", TxnID: "txn-1", Status: domain.RefundStatusSuccess},
+		},
+		{
+			name: "资金没有变化，还在处理中",
+			client: &fakeClient{
+				refund: &alipay.TradeRefundRsp{Error: success, TradeNo: "txn-1", FundChange: "N"},
+				refundQuery: &alipay.TradeFastPayRefundQueryRsp{Error: success, TradeNo: "txn-1",
+					OutRequestNo: "refund-1"},
+			},
+			wantRefund: domain.Refund{RefundNO: "refund-1", TxnID: "txn-1", Status: domain.RefundStatusInit},
+		},
+		{
+			name: "支付宝返回了错误",
+			client: &fakeClient{refund: &alipay.TradeRefundRsp{
+				Error: alipay.Error{Code: "40004", SubMsg: "交易不存在"}}},
+			wantErr: fmt.Errorf("%w, %s %s", errAlipayFailure, "40004", "交易不存在"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := NewPaymentService(tc.client, "app-1", "", "", logger.NewNopLogger())
+			refund, err := svc.Refund(context.Background(), domain.Refund{
+				BizTradeNO: "reward-1",
+				RefundNO:   "refund-1",
+				Amt:        domain.Amount{Currency: "CNY", Total: 1230},
+			})
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantRefund, refund)
+			if tc.client.refundReq.OutRequestNo != "" {
+				assert.Equal(t, "12.30", tc.client.refundReq.RefundAmount)
+			}
+		})
+	}
+}
+
+func Test_toFen(t *testing.T) {
+	testCases := []struct {
+		yuan    string
+		want    int64
+		wantErr bool
+	}{
+		{yuan: "12.30", want: 1230},
+		{yuan: "12.3", want: 1230},
+		{yuan: "12", want: 1200},
+		{yuan: "0.01", want: 1},
+		{yuan: "-1.00", wantErr: true},
+		{yuan: "1.001", wantErr: true},
+		{yuan: "", wantErr: true},
+		{yuan: "abc", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.yuan, func(t *testing.T) {
+			fen, err := toFen(tc.yuan)
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.want, fen)
+		})
+	}
+}
+
+type fakeClient struct {
+	Client
+	noti        *alipay.Notification
+	err         error
+	refund      *alipay.TradeRefundRsp
+	refundReq   alipay.TradeRefund
+	refundQuery *alipay.TradeFastPayRefundQueryRsp
+}
+
+func (f *fakeClient) DecodeNotification(values url.Values) (*alipay.Notification, error) {
+	return f.noti, f.err
+}
+
+func (f *fakeClient) TradeRefund(ctx context.Context, param alipay.TradeRefund) (*alipay.TradeRefundRsp, error) {
+	f.refundReq = param
+	return f.refund, nil
+}
+
+func (f *fakeClient) TradeFastPayRefundQuery(ctx context.Context,
+	param alipay.TradeFastPayRefundQuery) (*alipay.TradeFastPayRefundQueryRsp, error) {
+	return f.refundQuery, nil
+}
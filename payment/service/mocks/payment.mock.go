@@ -0,0 +1,580 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./types.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./types.go -destination=mocks/payment.mock.go -package=svcmocks PaymentService Provider BillProvider ReconcileService
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+	time "time"
+
+	domain "gitee.com/geekbang/basic-go/webook/payment/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPaymentService is a mock of PaymentService interface.
+type MockPaymentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentServiceMockRecorder
+}
+
+// MockPaymentServiceMockRecorder is the mock recorder for MockPaymentService.
+type MockPaymentServiceMockRecorder struct {
+	mock *MockPaymentService
+}
+
+// NewMockPaymentService creates a new mock instance.
+func NewMockPaymentService(ctrl *gomock.Controller) *MockPaymentService {
+	mock := &MockPaymentService{ctrl: ctrl}
+	mock.recorder = &MockPaymentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentService) EXPECT() *MockPaymentServiceMockRecorder {
+	return m.recorder
+}
+
+// CloseExpiredPayments mocks base method.
+func (m *MockPaymentService) CloseExpiredPayments(ctx context.Context, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseExpiredPayments", ctx, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseExpiredPayments indicates an expected call of CloseExpiredPayments.
+func (mr *MockPaymentServiceMockRecorder) CloseExpiredPayments(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseExpiredPayments", reflect.TypeOf((*MockPaymentService)(nil).CloseExpiredPayments), ctx, limit)
+}
+
+// ClosePayment mocks base method.
+func (m *MockPaymentService) ClosePayment(ctx context.Context, bizTradeNO string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClosePayment", ctx, bizTradeNO)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClosePayment indicates an expected call of ClosePayment.
+func (mr *MockPaymentServiceMockRecorder) ClosePayment(ctx, bizTradeNO any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClosePayment", reflect.TypeOf((*MockPaymentService)(nil).ClosePayment), ctx, bizTradeNO)
+}
+
+// FindExpiredPayment mocks base method.
+func (m *MockPaymentService) FindExpiredPayment(ctx context.Context, offset, limit int, t time.Time) ([]domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExpiredPayment", ctx, offset, limit, t)
+	ret0, _ := ret[0].([]domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindExpiredPayment indicates an expected call of FindExpiredPayment.
+func (mr *MockPaymentServiceMockRecorder) FindExpiredPayment(ctx, offset, limit, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExpiredPayment", reflect.TypeOf((*MockPaymentService)(nil).FindExpiredPayment), ctx, offset, limit, t)
+}
+
+// FindPendingRefunds mocks base method.
+func (m *MockPaymentService) FindPendingRefunds(ctx context.Context, offset, limit int, t time.Time) ([]domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPendingRefunds", ctx, offset, limit, t)
+	ret0, _ := ret[0].([]domain.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPendingRefunds indicates an expected call of FindPendingRefunds.
+func (mr *MockPaymentServiceMockRecorder) FindPendingRefunds(ctx, offset, limit, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPendingRefunds", reflect.TypeOf((*MockPaymentService)(nil).FindPendingRefunds), ctx, offset, limit, t)
+}
+
+// GetPayment mocks base method.
+func (m *MockPaymentService) GetPayment(ctx context.Context, bizTradeNO string) (domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPayment", ctx, bizTradeNO)
+	ret0, _ := ret[0].(domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPayment indicates an expected call of GetPayment.
+func (mr *MockPaymentServiceMockRecorder) GetPayment(ctx, bizTradeNO any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayment", reflect.TypeOf((*MockPaymentService)(nil).GetPayment), ctx, bizTradeNO)
+}
+
+// GetRefund mocks base method.
+func (m *MockPaymentService) GetRefund(ctx context.Context, refundNO string) (domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefund", ctx, refundNO)
+	ret0, _ := ret[0].(domain.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefund indicates an expected call of GetRefund.
+func (mr *MockPaymentServiceMockRecorder) GetRefund(ctx, refundNO any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefund", reflect.TypeOf((*MockPaymentService)(nil).GetRefund), ctx, refundNO)
+}
+
+// HandlePaymentNotify mocks base method.
+func (m *MockPaymentService) HandlePaymentNotify(ctx context.Context, pmt domain.Payment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandlePaymentNotify", ctx, pmt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandlePaymentNotify indicates an expected call of HandlePaymentNotify.
+func (mr *MockPaymentServiceMockRecorder) HandlePaymentNotify(ctx, pmt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandlePaymentNotify", reflect.TypeOf((*MockPaymentService)(nil).HandlePaymentNotify), ctx, pmt)
+}
+
+// HandleRefundNotify mocks base method.
+func (m *MockPaymentService) HandleRefundNotify(ctx context.Context, r domain.Refund) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleRefundNotify", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleRefundNotify indicates an expected call of HandleRefundNotify.
+func (mr *MockPaymentServiceMockRecorder) HandleRefundNotify(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleRefundNotify", reflect.TypeOf((*MockPaymentService)(nil).HandleRefundNotify), ctx, r)
+}
+
+// NextExpireTime mocks base method.
+func (m *MockPaymentService) NextExpireTime(ctx context.Context) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextExpireTime", ctx)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextExpireTime indicates an expected call of NextExpireTime.
+func (mr *MockPaymentServiceMockRecorder) NextExpireTime(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextExpireTime", reflect.TypeOf((*MockPaymentService)(nil).NextExpireTime), ctx)
+}
+
+// Prepay mocks base method.
+func (m *MockPaymentService) Prepay(ctx context.Context, pmt domain.Payment) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prepay", ctx, pmt)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prepay indicates an expected call of Prepay.
+func (mr *MockPaymentServiceMockRecorder) Prepay(ctx, pmt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prepay", reflect.TypeOf((*MockPaymentService)(nil).Prepay), ctx, pmt)
+}
+
+// Refund mocks base method.
+func (m *MockPaymentService) Refund(ctx context.Context, r domain.Refund) (domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", ctx, r)
+	ret0, _ := ret[0].(domain.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockPaymentServiceMockRecorder) Refund(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockPaymentService)(nil).Refund), ctx, r)
+}
+
+// SyncPayment mocks base method.
+func (m *MockPaymentService) SyncPayment(ctx context.Context, bizTradeNO string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncPayment", ctx, bizTradeNO)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncPayment indicates an expected call of SyncPayment.
+func (mr *MockPaymentServiceMockRecorder) SyncPayment(ctx, bizTradeNO any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncPayment", reflect.TypeOf((*MockPaymentService)(nil).SyncPayment), ctx, bizTradeNO)
+}
+
+// SyncPaymentRefunds mocks base method.
+func (m *MockPaymentService) SyncPaymentRefunds(ctx context.Context, bizTradeNO string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncPaymentRefunds", ctx, bizTradeNO)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncPaymentRefunds indicates an expected call of SyncPaymentRefunds.
+func (mr *MockPaymentServiceMockRecorder) SyncPaymentRefunds(ctx, bizTradeNO any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncPaymentRefunds", reflect.TypeOf((*MockPaymentService)(nil).SyncPaymentRefunds), ctx, bizTradeNO)
+}
+
+// SyncRefund mocks base method.
+func (m *MockPaymentService) SyncRefund(ctx context.Context, refundNO string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncRefund", ctx, refundNO)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncRefund indicates an expected call of SyncRefund.
+func (mr *MockPaymentServiceMockRecorder) SyncRefund(ctx, refundNO any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncRefund", reflect.TypeOf((*MockPaymentService)(nil).SyncRefund), ctx, refundNO)
+}
+
+// MockProvider is a mock of Provider interface.
+type MockProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockProviderMockRecorder
+}
+
+// MockProviderMockRecorder is the mock recorder for MockProvider.
+type MockProviderMockRecorder struct {
+	mock *MockProvider
+}
+
+// NewMockProvider creates a new mock instance.
+func NewMockProvider(ctrl *gomock.Controller) *MockProvider {
+	mock := &MockProvider{ctrl: ctrl}
+	mock.recorder = &MockProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProvider) EXPECT() *MockProviderMockRecorder {
+	return m.recorder
+}
+
+// Channel mocks base method.
+func (m *MockProvider) Channel() domain.PaymentChannel {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Channel")
+	ret0, _ := ret[0].(domain.PaymentChannel)
+	return ret0
+}
+
+// Channel indicates an expected call of Channel.
+func (mr *MockProviderMockRecorder) Channel() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Channel", reflect.TypeOf((*MockProvider)(nil).Channel))
+}
+
+// ClosePayment mocks base method.
+func (m *MockProvider) ClosePayment(ctx context.Context, bizTradeNO string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClosePayment", ctx, bizTradeNO)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClosePayment indicates an expected call of ClosePayment.
+func (mr *MockProviderMockRecorder) ClosePayment(ctx, bizTradeNO any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClosePayment", reflect.TypeOf((*MockProvider)(nil).ClosePayment), ctx, bizTradeNO)
+}
+
+// Prepay mocks base method.
+func (m *MockProvider) Prepay(ctx context.Context, pmt domain.Payment) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prepay", ctx, pmt)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prepay indicates an expected call of Prepay.
+func (mr *MockProviderMockRecorder) Prepay(ctx, pmt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prepay", reflect.TypeOf((*MockProvider)(nil).Prepay), ctx, pmt)
+}
+
+// QueryPayment mocks base method.
+func (m *MockProvider) QueryPayment(ctx context.Context, bizTradeNO string) (domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryPayment", ctx, bizTradeNO)
+	ret0, _ := ret[0].(domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryPayment indicates an expected call of QueryPayment.
+func (mr *MockProviderMockRecorder) QueryPayment(ctx, bizTradeNO any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryPayment", reflect.TypeOf((*MockProvider)(nil).QueryPayment), ctx, bizTradeNO)
+}
+
+// QueryRefund mocks base method.
+func (m *MockProvider) QueryRefund(ctx context.Context, r domain.Refund) (domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryRefund", ctx, r)
+	ret0, _ := ret[0].(domain.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryRefund indicates an expected call of QueryRefund.
+func (mr *MockProviderMockRecorder) QueryRefund(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRefund", reflect.TypeOf((*MockProvider)(nil).QueryRefund), ctx, r)
+}
+
+// Refund mocks base method.
+func (m *MockProvider) Refund(ctx context.Context, r domain.Refund) (domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", ctx, r)
+	ret0, _ := ret[0].(domain.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockProviderMockRecorder) Refund(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockProvider)(nil).Refund), ctx, r)
+}
+
+// MockBillProvider is a mock of BillProvider interface.
+type MockBillProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockBillProviderMockRecorder
+}
+
+// MockBillProviderMockRecorder is the mock recorder for MockBillProvider.
+type MockBillProviderMockRecorder struct {
+	mock *MockBillProvider
+}
+
+// NewMockBillProvider creates a new mock instance.
+func NewMockBillProvider(ctrl *gomock.Controller) *MockBillProvider {
+	mock := &MockBillProvider{ctrl: ctrl}
+	mock.recorder = &MockBillProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBillProvider) EXPECT() *MockBillProviderMockRecorder {
+	return m.recorder
+}
+
+// Channel mocks base method.
+func (m *MockBillProvider) Channel() domain.PaymentChannel {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Channel")
+	ret0, _ := ret[0].(domain.PaymentChannel)
+	return ret0
+}
+
+// Channel indicates an expected call of Channel.
+func (mr *MockBillProviderMockRecorder) Channel() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Channel", reflect.TypeOf((*MockBillProvider)(nil).Channel))
+}
+
+// ClosePayment mocks base method.
+func (m *MockBillProvider) ClosePayment(ctx context.Context, bizTradeNO string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClosePayment", ctx, bizTradeNO)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClosePayment indicates an expected call of ClosePayment.
+func (mr *MockBillProviderMockRecorder) ClosePayment(ctx, bizTradeNO any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClosePayment", reflect.TypeOf((*MockBillProvider)(nil).ClosePayment), ctx, bizTradeNO)
+}
+
+// DownloadBill mocks base method.
+func (m *MockBillProvider) DownloadBill(ctx context.Context, date time.Time) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadBill", ctx, date)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadBill indicates an expected call of DownloadBill.
+func (mr *MockBillProviderMockRecorder) DownloadBill(ctx, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadBill", reflect.TypeOf((*MockBillProvider)(nil).DownloadBill), ctx, date)
+}
+
+// ParseBill mocks base method.
+func (m *MockBillProvider) ParseBill(r io.Reader) ([]domain.BillItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseBill", r)
+	ret0, _ := ret[0].([]domain.BillItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseBill indicates an expected call of ParseBill.
+func (mr *MockBillProviderMockRecorder) ParseBill(r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseBill", reflect.TypeOf((*MockBillProvider)(nil).ParseBill), r)
+}
+
+// Prepay mocks base method.
+func (m *MockBillProvider) Prepay(ctx context.Context, pmt domain.Payment) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prepay", ctx, pmt)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prepay indicates an expected call of Prepay.
+func (mr *MockBillProviderMockRecorder) Prepay(ctx, pmt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prepay", reflect.TypeOf((*MockBillProvider)(nil).Prepay), ctx, pmt)
+}
+
+// QueryPayment mocks base method.
+func (m *MockBillProvider) QueryPayment(ctx context.Context, bizTradeNO string) (domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryPayment", ctx, bizTradeNO)
+	ret0, _ := ret[0].(domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryPayment indicates an expected call of QueryPayment.
+func (mr *MockBillProviderMockRecorder) QueryPayment(ctx, bizTradeNO any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryPayment", reflect.TypeOf((*MockBillProvider)(nil).QueryPayment), ctx, bizTradeNO)
+}
+
+// QueryRefund mocks base method.
+func (m *MockBillProvider) QueryRefund(ctx context.Context, r domain.Refund) (domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryRefund", ctx, r)
+	ret0, _ := ret[0].(domain.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryRefund indicates an expected call of QueryRefund.
+func (mr *MockBillProviderMockRecorder) QueryRefund(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRefund", reflect.TypeOf((*MockBillProvider)(nil).QueryRefund), ctx, r)
+}
+
+// Refund mocks base method.
+func (m *MockBillProvider) Refund(ctx context.Context, r domain.Refund) (domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", ctx, r)
+	ret0, _ := ret[0].(domain.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockBillProviderMockRecorder) Refund(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockBillProvider)(nil).Refund), ctx, r)
+}
+
+// MockReconcileService is a mock of ReconcileService interface.
+type MockReconcileService struct {
+	ctrl     *gomock.Controller
+	recorder *MockReconcileServiceMockRecorder
+}
+
+// MockReconcileServiceMockRecorder is the mock recorder for MockReconcileService.
+type MockReconcileServiceMockRecorder struct {
+	mock *MockReconcileService
+}
+
+// NewMockReconcileService creates a new mock instance.
+func NewMockReconcileService(ctrl *gomock.Controller) *MockReconcileService {
+	mock := &MockReconcileService{ctrl: ctrl}
+	mock.recorder = &MockReconcileServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReconcileService) EXPECT() *MockReconcileServiceMockRecorder {
+	return m.recorder
+}
+
+// BillChannels mocks base method.
+func (m *MockReconcileService) BillChannels() []domain.PaymentChannel {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BillChannels")
+	ret0, _ := ret[0].([]domain.PaymentChannel)
+	return ret0
+}
+
+// BillChannels indicates an expected call of BillChannels.
+func (mr *MockReconcileServiceMockRecorder) BillChannels() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BillChannels", reflect.TypeOf((*MockReconcileService)(nil).BillChannels))
+}
+
+// GetReport mocks base method.
+func (m *MockReconcileService) GetReport(ctx context.Context, channel domain.PaymentChannel, date time.Time) (domain.ReconcileReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReport", ctx, channel, date)
+	ret0, _ := ret[0].(domain.ReconcileReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReport indicates an expected call of GetReport.
+func (mr *MockReconcileServiceMockRecorder) GetReport(ctx, channel, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReport", reflect.TypeOf((*MockReconcileService)(nil).GetReport), ctx, channel, date)
+}
+
+// Import mocks base method.
+func (m *MockReconcileService) Import(ctx context.Context, channel domain.PaymentChannel, date time.Time, bill io.Reader) (domain.ReconcileReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, channel, date, bill)
+	ret0, _ := ret[0].(domain.ReconcileReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockReconcileServiceMockRecorder) Import(ctx, channel, date, bill any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockReconcileService)(nil).Import), ctx, channel, date, bill)
+}
+
+// Reconcile mocks base method.
+func (m *MockReconcileService) Reconcile(ctx context.Context, channel domain.PaymentChannel, date time.Time) (domain.ReconcileReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconcile", ctx, channel, date)
+	ret0, _ := ret[0].(domain.ReconcileReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reconcile indicates an expected call of Reconcile.
+func (mr *MockReconcileServiceMockRecorder) Reconcile(ctx, channel, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockReconcileService)(nil).Reconcile), ctx, channel, date)
+}
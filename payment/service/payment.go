@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"gitee.com/geekbang/basic-go/webook/payment/events"
+	"gitee.com/geekbang/basic-go/webook/payment/repository"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"gitee.com/geekbang/basic-go/webook/pkg/outbox"
+	"time"
+)
+
+var (
+	ErrUnknownChannel       = errors.New("不支持的支付渠道")
+	ErrChannelMismatch      = errors.New("通知的渠道和支付的渠道对不上")
+	ErrAmountMismatch       = errors.New("通知的金额和支付的金额对不上")
+	ErrPaymentNotRefundable = repository.ErrPaymentNotRefundable
+	ErrRefundExceeded       = repository.ErrRefundExceeded
+	ErrDuplicateRefund      = repository.ErrDuplicateRefund
+)
+
+// closeRetryInterval 关单失败之后多久再试
+const closeRetryInterval = time.Minute
+
+type paymentService struct {
+	providers map[domain.PaymentChannel]Provider
+	// 自己的支付记录
+	repo       repository.PaymentRepository
+	refundRepo repository.RefundRepository
+
+	l logger.LoggerV1
+}
+
+func NewPaymentService(repo repository.PaymentRepository,
+	refundRepo repository.RefundRepository,
+	providers []Provider,
+	l logger.LoggerV1) PaymentService {
+	m := make(map[domain.PaymentChannel]Provider, len(providers))
+	for _, p := range providers {
+		m[p.Channel()] = p
+	}
+	return &paymentService{
+		providers:  m,
+		repo:       repo,
+		refundRepo: refundRepo,
+		l:          l,
+	}
+}
+
+func (s *paymentService) provider(c domain.PaymentChannel) (Provider, error) {
+	c = normalizeChannel(c)
+	p, ok := s.providers[c]
+	if !ok {
+		return nil, fmt.Errorf("%w, 渠道 %d", ErrUnknownChannel, c)
+	}
+	return p, nil
+}
+
+func normalizeChannel(c domain.PaymentChannel) domain.PaymentChannel {
+	if c == domain.PaymentChannelUnknown {
+		// 以前只有微信支付
+		return domain.PaymentChannelWechat
+	}
+	return c
+}
+
+func (s *paymentService) Prepay(ctx context.Context, pmt domain.Payment) (string, error) {
+	p, err := s.provider(pmt.Channel)
+	if err != nil {
+		return "", err
+	}
+	pmt.Channel = p.Channel()
+	pmt.Status = domain.PaymentStatusInit
+	// 第三方的过期时间也是这个，到期之后我们主动去关单
+	pmt.ExpireTime = time.Now().Add(domain.PaymentTimeout)
+	err = s.repo.AddPayment(ctx, pmt)
+	if err != nil {
+		return "", err
+	}
+	return p.Prepay(ctx, pmt)
+}
+
+func (s *paymentService) GetPayment(ctx context.Context, bizTradeNO string) (domain.Payment, error) {
+	return s.repo.GetPayment(ctx, bizTradeNO)
+}
+
+func (s *paymentService) FindExpiredPayment(ctx context.Context, offset, limit int, t time.Time) ([]domain.Payment, error) {
+	return s.repo.FindExpiredPayment(ctx, offset, limit, t)
+}
+
+func (s *paymentService) SyncPayment(ctx context.Context, bizTradeNO string) error {
+	// 对账
+	pmt, err := s.repo.GetPayment(ctx, bizTradeNO)
+	if err != nil {
+		return err
+	}
+	p, err := s.provider(pmt.Channel)
+	if err != nil {
+		return err
+	}
+	res, err := p.QueryPayment(ctx, bizTradeNO)
+	if err != nil {
+		return err
+	}
+	return s.updatePayment(ctx, pmt, res)
+}
+
+func (s *paymentService) HandlePaymentNotify(ctx context.Context, pmt domain.Payment) error {
+	old, err := s.repo.GetPayment(ctx, pmt.BizTradeNO)
+	if err != nil {
+		return err
+	}
+	// 每个渠道的通知只能改这个渠道的支付，
+	// 不然拿到沙箱的密钥，就可以把微信和支付宝的订单改成支付成功
+	if normalizeChannel(pmt.Channel) != normalizeChannel(old.Channel) {
+		return fmt.Errorf("%w, 支付的渠道是 %s，通知的渠道是 %s", ErrChannelMismatch,
+			normalizeChannel(old.Channel), normalizeChannel(pmt.Channel))
+	}
+	// 通知里面带了金额的，要和我们下单的金额一样
+	if pmt.Amt.Total != 0 && (pmt.Amt.Total != old.Amt.Total || pmt.Amt.Currency != old.Amt.Currency) {
+		return fmt.Errorf("%w, 支付的金额是 %d %s，通知的金额是 %d %s", ErrAmountMismatch,
+			old.Amt.Total, old.Amt.Currency, pmt.Amt.Total, pmt.Amt.Currency)
+	}
+	return s.updatePayment(ctx, old, pmt)
+}
+
+func (s *paymentService) updatePayment(ctx context.Context, old domain.Payment, pmt domain.Payment) error {
+	// 已经付过钱的，不能再退回到未支付或者失败，退了款的也不能再变回支付成功。
+	// 比如说支付宝全额退款之后，交易状态会变成 TRADE_CLOSED，
+	// 或者退款之后才收到迟到的支付成功通知
+	if !old.Status.CanTransitTo(pmt.Status) {
+		s.l.Warn("忽略不合法的支付状态变化",
+			logger.String("biz_trade_no", pmt.BizTradeNO),
+			logger.Int64("old", int64(old.Status)),
+			logger.Int64("new", int64(pmt.Status)))
+		return nil
+	}
+	// 更新支付状态的同时把通知业务方的事件写进 outbox，
+	// 这样只要状态更新成功了，事件至少会发出去一次
+	evt := events.PaymentEvent{
+		BizTradeNO: pmt.BizTradeNO,
+		Status:     pmt.Status.AsUint8(),
+	}
+	if pmt.Status == domain.PaymentStatusSuccess {
+		// 业务方按照这个时间算会员的周期之类的，不能用消费消息的时间
+		paidTime := pmt.PaidTime
+		if paidTime.IsZero() {
+			paidTime = time.Now()
+		}
+		evt.PaidTime = paidTime.UnixMilli()
+	}
+	return s.repo.UpdatePayment(ctx, pmt, outbox.NewMessage(pmt.BizTradeNO, evt))
+}
+
+func (s *paymentService) ClosePayment(ctx context.Context, bizTradeNO string) error {
+	pmt, err := s.repo.GetPayment(ctx, bizTradeNO)
+	if err != nil {
+		return err
+	}
+	if pmt.Status != domain.PaymentStatusInit {
+		return nil
+	}
+	p, err := s.provider(pmt.Channel)
+	if err != nil {
+		return err
+	}
+	// 用户可能在过期前一刻付了钱，但是通知还没到，
+	// 所以关单之前先查一下
+	res, err := p.QueryPayment(ctx, bizTradeNO)
+	if err != nil {
+		return err
+	}
+	if res.Status != domain.PaymentStatusInit {
+		return s.updatePayment(ctx, pmt, res)
+	}
+	err = p.ClosePayment(ctx, bizTradeNO)
+	if err != nil {
+		return err
+	}
+	closed, err := s.repo.ClosePayment(ctx, bizTradeNO, outbox.NewMessage(bizTradeNO, events.PaymentEvent{
+		BizTradeNO: bizTradeNO,
+		Status:     domain.PaymentStatusClosed,
+	}))
+	if err == nil && !closed {
+		s.l.Warn("关闭订单的时候支付状态已经变了",
+			logger.String("biz_trade_no", bizTradeNO))
+	}
+	return err
+}
+
+func (s *paymentService) CloseExpiredPayments(ctx context.Context, limit int) (int, error) {
+	pmts, err := s.repo.FindToClose(ctx, time.Now(), limit)
+	if err != nil {
+		return 0, err
+	}
+	for _, pmt := range pmts {
+		err = s.ClosePayment(ctx, pmt.BizTradeNO)
+		if err == nil {
+			continue
+		}
+		s.l.Error("关闭过期订单失败", logger.Error(err),
+			logger.String("biz_trade_no", pmt.BizTradeNO))
+		// 推迟一会再重试，不然会一直卡在这个订单上
+		err = s.repo.DelayClose(ctx, pmt.BizTradeNO, time.Now().Add(closeRetryInterval))
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(pmts), nil
+}
+
+func (s *paymentService) NextExpireTime(ctx context.Context) (time.Time, error) {
+	return s.repo.NextExpireTime(ctx)
+}
@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"gitee.com/geekbang/basic-go/webook/payment/events"
+	"gitee.com/geekbang/basic-go/webook/payment/repository"
+	repomocks "gitee.com/geekbang/basic-go/webook/payment/repository/mocks"
+	svcmocks "gitee.com/geekbang/basic-go/webook/payment/service/mocks"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"gitee.com/geekbang/basic-go/webook/pkg/outbox"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func providers(ctrl *gomock.Controller) (*svcmocks.MockProvider, *svcmocks.MockProvider) {
+	wechat := svcmocks.NewMockProvider(ctrl)
+	wechat.EXPECT().Channel().Return(domain.PaymentChannelWechat).AnyTimes()
+	sandbox := svcmocks.NewMockProvider(ctrl)
+	sandbox.EXPECT().Channel().Return(domain.PaymentChannelSandbox).AnyTimes()
+	return wechat, sandbox
+}
+
+// prepayMatcher 过期时间是 time.Now 算出来的，只检查设置了，其它字段要相等
+type prepayMatcher struct {
+	want domain.Payment
+}
+
+func (m prepayMatcher) Matches(x any) bool {
+	pmt, ok := x.(domain.Payment)
+	if !ok || pmt.ExpireTime.IsZero() {
+		return false
+	}
+	pmt.ExpireTime = time.Time{}
+	return pmt == m.want
+}
+
+func (m prepayMatcher) String() string {
+	return fmt.Sprintf("带过期时间的 %v", m.want)
+}
+
+func Test_paymentService_Prepay(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.PaymentRepository, []Provider)
+
+		pmt     domain.Payment
+		wantURL string
+		wantErr error
+	}{
+		{
+			name: "不传渠道就是微信",
+			mock: func(ctrl *gomock.Controller) (repository.PaymentRepository, []Provider) {
+				repo := repomocks.NewMockPaymentRepository(ctrl)
+				wechat, sandbox := providers(ctrl)
+				repo.EXPECT().AddPayment(gomock.Any(), prepayMatcher{want: domain.Payment{
+					BizTradeNO: "reward-1",
+					Status:     domain.PaymentStatusInit,
+					Channel:    domain.PaymentChannelWechat,
+				}}).Return(nil)
+				wechat.EXPECT().Prepay(gomock.Any(), gomock.Any()).Return("weixin://wxpay", nil)
+				return repo, []Provider{wechat, sandbox}
+			},
+			pmt:     domain.Payment{BizTradeNO: "reward-1"},
+			wantURL: "weixin://wxpay",
+		},
+		{
+			name: "沙箱",
+			mock: func(ctrl *gomock.Controller) (repository.PaymentRepository, []Provider) {
+				repo := repomocks.NewMockPaymentRepository(ctrl)
+				wechat, sandbox := providers(ctrl)
+				repo.EXPECT().AddPayment(gomock.Any(), prepayMatcher{want: domain.Payment{
+					BizTradeNO: "reward-2",
+					Status:     domain.PaymentStatusInit,
+					Channel:    domain.PaymentChannelSandbox,
+				}}).Return(nil)
+				sandbox.EXPECT().Prepay(gomock.Any(), gomock.Any()).
+					Return("http://localhost/pay/sandbox/pay", nil)
+				return repo, []Provider{wechat, sandbox}
+			},
+			pmt:     domain.Payment{BizTradeNO: "reward-2", Channel: domain.PaymentChannelSandbox},
+			wantURL: "http://localhost/pay/sandbox/pay",
+		},
+		{
+			name: "没有配置的渠道",
+			mock: func(ctrl *gomock.Controller) (repository.PaymentRepository, []Provider) {
+				wechat, sandbox := providers(ctrl)
+				return repomocks.NewMockPaymentRepository(ctrl), []Provider{wechat, sandbox}
+			},
+			pmt:     domain.Payment{BizTradeNO: "reward-3", Channel: domain.PaymentChannelAlipay},
+			wantErr: ErrUnknownChannel,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, ps := tc.mock(ctrl)
+			svc := NewPaymentService(repo, nil, ps, logger.NewNopLogger())
+			url, err := svc.Prepay(context.Background(), tc.pmt)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.wantURL, url)
+		})
+	}
+}
+
+func Test_paymentService_HandlePaymentNotify(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.PaymentRepository
+
+		pmt     domain.Payment
+		wantErr error
+	}{
+		{
+			name: "支付成功",
+			mock: func(ctrl *gomock.Controller) repository.PaymentRepository {
+				repo := repomocks.NewMockPaymentRepository(ctrl)
+				repo.EXPECT().GetPayment(gomock.Any(), "reward-1").
+					Return(domain.Payment{BizTradeNO: "reward-1", Status: domain.PaymentStatusInit}, nil)
+				repo.EXPECT().UpdatePayment(gomock.Any(), domain.Payment{
+					BizTradeNO: "reward-1",
+					TxnID:      "txn-1",
+					Status:     domain.PaymentStatusSuccess,
+					PaidTime:   time.UnixMilli(1000),
+				}, outbox.Message{
+					Topic: "payment_events",
+					Key:   "reward-1",
+					Value: events.PaymentEvent{
+						BizTradeNO: "reward-1",
+						Status:     domain.PaymentStatusSuccess,
+						PaidTime:   1000,
+					},
+				}).Return(nil)
+				return repo
+			},
+			pmt: domain.Payment{BizTradeNO: "reward-1", TxnID: "txn-1", Status: domain.PaymentStatusSuccess,
+				PaidTime: time.UnixMilli(1000)},
+		},
+		{
+			name: "已经付过钱的不会回退",
+			mock: func(ctrl *gomock.Controller) repository.PaymentRepository {
+				repo := repomocks.NewMockPaymentRepository(ctrl)
+				repo.EXPECT().GetPayment(gomock.Any(), "reward-1").
+					Return(domain.Payment{BizTradeNO: "reward-1", Status: domain.PaymentStatusRefund}, nil)
+				return repo
+			},
+			pmt: domain.Payment{BizTradeNO: "reward-1", TxnID: "txn-1", Status: domain.PaymentStatusFailed},
+		},
+		{
+			name: "沙箱的通知不能改微信的支付",
+			mock: func(ctrl *gomock.Controller) repository.PaymentRepository {
+				repo := repomocks.NewMockPaymentRepository(ctrl)
+				repo.EXPECT().GetPayment(gomock.Any(), "reward-1").
+					Return(domain.Payment{BizTradeNO: "reward-1", Status: domain.PaymentStatusInit,
+						Channel: domain.PaymentChannelWechat}, nil)
+				return repo
+			},
+			pmt: domain.Payment{BizTradeNO: "reward-1", TxnID: "txn-1", Status: domain.PaymentStatusSuccess,
+				Channel: domain.PaymentChannelSandbox},
+			wantErr: fmt.Errorf("%w, 支付的渠道是 %s，通知的渠道是 %s", ErrChannelMismatch,
+				domain.PaymentChannelWechat, domain.PaymentChannelSandbox),
+		},
+		{
+			name: "通知的金额对不上",
+			mock: func(ctrl *gomock.Controller) repository.PaymentRepository {
+				repo := repomocks.NewMockPaymentRepository(ctrl)
+				repo.EXPECT().GetPayment(gomock.Any(), "reward-1").
+					Return(domain.Payment{BizTradeNO: "reward-1", Status: domain.PaymentStatusInit,
+						Amt: domain.Amount{Currency: "CNY", Total: 100}}, nil)
+				return repo
+			},
+			pmt: domain.Payment{BizTradeNO: "reward-1", TxnID: "txn-1", Status: domain.PaymentStatusSuccess,
+				Amt: domain.Amount{Currency: "CNY", Total: 1}},
+			wantErr: fmt.Errorf("%w, 支付的金额是 %d %s，通知的金额是 %d %s", ErrAmountMismatch,
+				int64(100), "CNY", int64(1), "CNY"),
+		},
+		{
+			name: "退款之后迟到的支付成功通知",
+			mock: func(ctrl *gomock.Controller) repository.PaymentRepository {
+				repo := repomocks.NewMockPaymentRepository(ctrl)
+				repo.EXPECT().GetPayment(gomock.Any(), "reward-1").
+					Return(domain.Payment{BizTradeNO: "reward-1", Status: domain.PaymentStatusRefund}, nil)
+				return repo
+			},
+			pmt: domain.Payment{BizTradeNO: "reward-1", TxnID: "txn-1", Status: domain.PaymentStatusSuccess},
+		},
+		{
+			name: "重复的支付成功通知",
+			mock: func(ctrl *gomock.Controller) repository.PaymentRepository {
+				repo := repomocks.NewMockPaymentRepository(ctrl)
+				repo.EXPECT().GetPayment(gomock.Any(), "reward-1").
+					Return(domain.Payment{BizTradeNO: "reward-1", Status: domain.PaymentStatusSuccess}, nil)
+				return repo
+			},
+			pmt: domain.Payment{BizTradeNO: "reward-1", TxnID: "txn-1", Status: domain.PaymentStatusSuccess},
+		},
+		{
+			name: "更新失败",
+			mock: func(ctrl *gomock.Controller) repository.PaymentRepository {
+				repo := repomocks.NewMockPaymentRepository(ctrl)
+				repo.EXPECT().GetPayment(gomock.Any(), "reward-1").
+					Return(domain.Payment{BizTradeNO: "reward-1", Status: domain.PaymentStatusInit}, nil)
+				repo.EXPECT().UpdatePayment(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(errors.New("mock db error"))
+				return repo
+			},
+			pmt:     domain.Payment{BizTradeNO: "reward-1", Status: domain.PaymentStatusSuccess},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewPaymentService(tc.mock(ctrl), nil, nil, logger.NewNopLogger())
+			err := svc.HandlePaymentNotify(context.Background(), tc.pmt)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_paymentService_ClosePayment(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.PaymentRepository, []Provider)
+
+		wantErr error
+	}{
+		{
+			name: "关闭成功",
+			mock: func(ctrl *gomock.Controller) (repository.PaymentRepository, []Provider) {
+				repo := repomocks.NewMockPaymentRepository(ctrl)
+				wechat, sandbox := providers(ctrl)
+				repo.EXPECT().GetPayment(gomock.Any(), "reward-1").
+					Return(domain.Payment{BizTradeNO: "reward-1", Status: domain.PaymentStatusInit,
+						Channel: domain.PaymentChannelSandbox}, nil)
+				sandbox.EXPECT().QueryPayment(gomock.Any(), "reward-1").
+					Return(domain.Payment{BizTradeNO: "reward-1", Status: domain.PaymentStatusInit}, nil)
+				sandbox.EXPECT().ClosePayment(gomock.Any(), "reward-1").Return(nil)
+				repo.EXPECT().ClosePayment(gomock.Any(), "reward-1", outbox.Message{
+					Topic: "payment_events",
+					Key:   "reward-1",
+					Value: events.PaymentEvent{
+						BizTradeNO: "reward-1",
+						Status:     domain.PaymentStatusClosed,
+					},
+				}).Return(true, nil)
+				return repo, []Provider{wechat, sandbox}
+			},
+		},
+		{
+			name: "已经付过钱了",
+			mock: func(ctrl *gomock.Controller) (repository.PaymentRepository, []Provider) {
+				repo := repomocks.NewMockPaymentRepository(ctrl)
+				wechat, sandbox := providers(ctrl)
+				repo.EXPECT().GetPayment(gomock.Any(), "reward-1").
+					Return(domain.Payment{BizTradeNO: "reward-1", Status: domain.PaymentStatusSuccess}, nil)
+				return repo, []Provider{wechat, sandbox}
+			},
+		},
+		{
+			name: "关单前一刻付了钱",
+			mock: func(ctrl *gomock.Controller) (repository.PaymentRepository, []Provider) {
+				repo := repomocks.NewMockPaymentRepository(ctrl)
+				wechat, sandbox := providers(ctrl)
+				repo.EXPECT().GetPayment(gomock.Any(), "reward-1").
+					Return(domain.Payment{BizTradeNO: "reward-1", Status: domain.PaymentStatusInit,
+						Channel: domain.PaymentChannelWechat}, nil)
+				paid := domain.Payment{BizTradeNO: "reward-1", TxnID: "txn-1", Status: domain.PaymentStatusSuccess}
+				wechat.EXPECT().QueryPayment(gomock.Any(), "reward-1").Return(paid, nil)
+				repo.EXPECT().UpdatePayment(gomock.Any(), paid, gomock.Any()).Return(nil)
+				return repo, []Provider{wechat, sandbox}
+			},
+		},
+		{
+			name: "第三方关单失败",
+			mock: func(ctrl *gomock.Controller) (repository.PaymentRepository, []Provider) {
+				repo := repomocks.NewMockPaymentRepository(ctrl)
+				wechat, sandbox := providers(ctrl)
+				repo.EXPECT().GetPayment(gomock.Any(), "reward-1").
+					Return(domain.Payment{BizTradeNO: "reward-1", Status: domain.PaymentStatusInit,
+						Channel: domain.PaymentChannelWechat}, nil)
+				wechat.EXPECT().QueryPayment(gomock.Any(), "reward-1").
+					Return(domain.Payment{BizTradeNO: "reward-1", Status: domain.PaymentStatusInit}, nil)
+				wechat.EXPECT().ClosePayment(gomock.Any(), "reward-1").
+					Return(errors.New("mock close error"))
+				return repo, []Provider{wechat, sandbox}
+			},
+			wantErr: errors.New("mock close error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, ps := tc.mock(ctrl)
+			svc := NewPaymentService(repo, nil, ps, logger.NewNopLogger())
+			err := svc.ClosePayment(context.Background(), "reward-1")
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
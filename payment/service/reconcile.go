@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"gitee.com/geekbang/basic-go/webook/payment/repository"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"io"
+	"time"
+)
+
+var (
+	ErrBillNotSupported = errors.New("这个渠道不支持对账单")
+	ErrReportNotFound   = repository.ErrReportNotFound
+)
+
+type reconcileService struct {
+	svc           PaymentService
+	repo          repository.PaymentRepository
+	reconcileRepo repository.ReconcileRepository
+	providers     map[domain.PaymentChannel]BillProvider
+	l             logger.LoggerV1
+	batchSize     int
+}
+
+// NewReconcileService 只有实现了 BillProvider 的渠道才能对账
+func NewReconcileService(svc PaymentService,
+	repo repository.PaymentRepository,
+	reconcileRepo repository.ReconcileRepository,
+	providers []Provider,
+	l logger.LoggerV1) ReconcileService {
+	m := make(map[domain.PaymentChannel]BillProvider, len(providers))
+	for _, p := range providers {
+		if bp, ok := p.(BillProvider); ok {
+			m[p.Channel()] = bp
+		}
+	}
+	return &reconcileService{
+		svc:           svc,
+		repo:          repo,
+		reconcileRepo: reconcileRepo,
+		providers:     m,
+		l:             l,
+		batchSize:     100,
+	}
+}
+
+func (s *reconcileService) BillChannels() []domain.PaymentChannel {
+	res := make([]domain.PaymentChannel, 0, len(s.providers))
+	for c := range s.providers {
+		res = append(res, c)
+	}
+	return res
+}
+
+func (s *reconcileService) provider(channel domain.PaymentChannel) (BillProvider, error) {
+	p, ok := s.providers[channel]
+	if !ok {
+		return nil, ErrBillNotSupported
+	}
+	return p, nil
+}
+
+func (s *reconcileService) Reconcile(ctx context.Context,
+	channel domain.PaymentChannel, date time.Time) (domain.ReconcileReport, error) {
+	p, err := s.provider(channel)
+	if err != nil {
+		return domain.ReconcileReport{}, err
+	}
+	bill, err := p.DownloadBill(ctx, date)
+	if err != nil {
+		return domain.ReconcileReport{}, err
+	}
+	defer bill.Close()
+	return s.reconcile(ctx, p, date, bill)
+}
+
+func (s *reconcileService) Import(ctx context.Context,
+	channel domain.PaymentChannel, date time.Time, bill io.Reader) (domain.ReconcileReport, error) {
+	p, err := s.provider(channel)
+	if err != nil {
+		return domain.ReconcileReport{}, err
+	}
+	return s.reconcile(ctx, p, date, bill)
+}
+
+func (s *reconcileService) GetReport(ctx context.Context,
+	channel domain.PaymentChannel, date time.Time) (domain.ReconcileReport, error) {
+	return s.reconcileRepo.GetReport(ctx, channel, date.Format(domain.ReconcileDateLayout))
+}
+
+func (s *reconcileService) reconcile(ctx context.Context,
+	p BillProvider, date time.Time, bill io.Reader) (domain.ReconcileReport, error) {
+	items, err := p.ParseBill(bill)
+	if err != nil {
+		return domain.ReconcileReport{}, err
+	}
+	report := domain.ReconcileReport{
+		Channel:   p.Channel(),
+		Date:      date.Format(domain.ReconcileDateLayout),
+		BillCount: len(items),
+	}
+	seen := make(map[string]struct{}, len(items))
+	for start := 0; start < len(items); start += s.batchSize {
+		end := start + s.batchSize
+		if end > len(items) {
+			end = len(items)
+		}
+		diffs, err := s.compareBatch(ctx, p, items[start:end])
+		if err != nil {
+			return domain.ReconcileReport{}, err
+		}
+		report.Diffs = append(report.Diffs, diffs...)
+		for _, item := range items[start:end] {
+			seen[item.BizTradeNO] = struct{}{}
+		}
+	}
+	extra, err := s.findExtra(ctx, p, date, seen)
+	if err != nil {
+		return domain.ReconcileReport{}, err
+	}
+	report.Diffs = append(report.Diffs, extra...)
+	err = s.reconcileRepo.SaveReport(ctx, report)
+	return report, err
+}
+
+// compareBatch 以账单为准，一批一批地和我们的支付记录比较
+func (s *reconcileService) compareBatch(ctx context.Context,
+	p BillProvider, items []domain.BillItem) ([]domain.ReconcileDiff, error) {
+	nos := make([]string, 0, len(items))
+	for _, item := range items {
+		nos = append(nos, item.BizTradeNO)
+	}
+	pmts, err := s.repo.FindByBizTradeNOs(ctx, nos)
+	if err != nil {
+		return nil, err
+	}
+	local := make(map[string]domain.Payment, len(pmts))
+	for _, pmt := range pmts {
+		local[pmt.BizTradeNO] = pmt
+	}
+	var diffs []domain.ReconcileDiff
+	for _, item := range items {
+		pmt, ok := local[item.BizTradeNO]
+		diff := domain.ReconcileDiff{
+			BizTradeNO:  item.BizTradeNO,
+			TxnID:       item.TxnID,
+			LocalAmt:    pmt.Amt.Total,
+			BillAmt:     item.Amt,
+			LocalStatus: pmt.Status,
+			BillStatus:  item.Status,
+		}
+		switch {
+		case !ok:
+			diff.Type = domain.ReconcileDiffMissing
+		case pmt.Amt.Total != item.Amt || pmt.Amt.Currency != item.Currency:
+			// 金额不对一定要人来看
+			diff.Type = domain.ReconcileDiffAmount
+		case pmt.Status.Payed() != item.Status.Payed() || pmt.TxnID != item.TxnID:
+			diff.Type = domain.ReconcileDiffStatus
+			diff.Fixed = s.fix(ctx, p, pmt, item)
+		case item.Status == domain.PaymentStatusRefund && pmt.Status != domain.PaymentStatusRefund:
+			// 第三方已经退了款，我们这边的退款还没有结果
+			diff.Type = domain.ReconcileDiffStatus
+			diff.Fixed = s.fixRefund(ctx, pmt)
+		default:
+			continue
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// fix 只修复安全的情况：金额对得上，第三方说付了钱，我们这边还没有。
+// 账单可能是手动上传的，所以不能只看账单，要再问一下第三方，以查询的结果为准。
+// 走的是和支付回调一样的流程，所以业务方也会收到支付事件。
+// 已经退了款的交易不修复：我们这边没付钱就不可能发起退款，那是在别的地方退的，要人来看
+func (s *reconcileService) fix(ctx context.Context, p BillProvider,
+	pmt domain.Payment, item domain.BillItem) bool {
+	if pmt.Status.Payed() || item.Status != domain.PaymentStatusSuccess {
+		return false
+	}
+	res, err := p.QueryPayment(ctx, item.BizTradeNO)
+	if err != nil {
+		s.l.Error("对账查询第三方支付状态失败", logger.Error(err),
+			logger.String("biz_trade_no", item.BizTradeNO))
+		return false
+	}
+	if res.Status != domain.PaymentStatusSuccess || res.TxnID != item.TxnID {
+		s.l.Warn("账单和第三方查询的结果对不上，不修复",
+			logger.String("biz_trade_no", item.BizTradeNO),
+			logger.String("bill_txn_id", item.TxnID),
+			logger.String("txn_id", res.TxnID),
+			logger.Int("status", int(res.Status)))
+		return false
+	}
+	err = s.svc.HandlePaymentNotify(ctx, domain.Payment{
+		BizTradeNO: item.BizTradeNO,
+		TxnID:      res.TxnID,
+		Status:     res.Status,
+		Channel:    p.Channel(),
+	})
+	if err != nil {
+		s.l.Error("对账修复支付状态失败", logger.Error(err),
+			logger.String("biz_trade_no", item.BizTradeNO))
+		return false
+	}
+	return true
+}
+
+// fixRefund 退款走退款的流程，查一下这笔支付还在处理中的退款，
+// 全部退完之后支付记录会变成已退款
+func (s *reconcileService) fixRefund(ctx context.Context, pmt domain.Payment) bool {
+	if pmt.Status != domain.PaymentStatusSuccess {
+		return false
+	}
+	err := s.svc.SyncPaymentRefunds(ctx, pmt.BizTradeNO)
+	if err != nil {
+		s.l.Error("对账同步退款失败", logger.Error(err),
+			logger.String("biz_trade_no", pmt.BizTradeNO))
+		return false
+	}
+	res, err := s.repo.GetPayment(ctx, pmt.BizTradeNO)
+	if err != nil {
+		s.l.Error("对账查询支付记录失败", logger.Error(err),
+			logger.String("biz_trade_no", pmt.BizTradeNO))
+		return false
+	}
+	return res.Status == domain.PaymentStatusRefund
+}
+
+// findExtra 找出我们这边已经付了钱，但是账单里面没有的。
+// 前一天晚上下单，第二天才付钱的会出现在第二天的账单里面，
+// 所以要再问一下第三方，确实没有付钱才算
+func (s *reconcileService) findExtra(ctx context.Context, p BillProvider,
+	date time.Time, seen map[string]struct{}) ([]domain.ReconcileDiff, error) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	end := start.AddDate(0, 0, 1)
+	var diffs []domain.ReconcileDiff
+	for offset := 0; ; offset += s.batchSize {
+		pmts, err := s.repo.FindPayedPayments(ctx, p.Channel(), start, end, offset, s.batchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, pmt := range pmts {
+			if _, ok := seen[pmt.BizTradeNO]; ok {
+				continue
+			}
+			res, err := p.QueryPayment(ctx, pmt.BizTradeNO)
+			if err == nil && res.Status.Payed() {
+				continue
+			}
+			if err != nil {
+				s.l.Error("对账查询第三方支付状态失败", logger.Error(err),
+					logger.String("biz_trade_no", pmt.BizTradeNO))
+			}
+			diffs = append(diffs, domain.ReconcileDiff{
+				BizTradeNO:  pmt.BizTradeNO,
+				TxnID:       pmt.TxnID,
+				Type:        domain.ReconcileDiffExtra,
+				LocalAmt:    pmt.Amt.Total,
+				LocalStatus: pmt.Status,
+				BillStatus:  res.Status,
+			})
+		}
+		if len(pmts) < s.batchSize {
+			return diffs, nil
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"gitee.com/geekbang/basic-go/webook/payment/repository"
+	repomocks "gitee.com/geekbang/basic-go/webook/payment/repository/mocks"
+	svcmocks "gitee.com/geekbang/basic-go/webook/payment/service/mocks"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_reconcileService_Import(t *testing.T) {
+	date := time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	end := start.AddDate(0, 0, 1)
+	paid := func(no, txn string, amt int64) domain.Payment {
+		return domain.Payment{BizTradeNO: no, TxnID: txn,
+			Amt: domain.Amount{Total: amt, Currency: "CNY"}, Status: domain.PaymentStatusSuccess}
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (PaymentService, repository.PaymentRepository,
+			repository.ReconcileRepository, []Provider)
+
+		channel    domain.PaymentChannel
+		wantReport domain.ReconcileReport
+		wantErr    error
+	}{
+		{
+			name: "各种差异",
+			mock: func(ctrl *gomock.Controller) (PaymentService, repository.PaymentRepository,
+				repository.ReconcileRepository, []Provider) {
+				svc := svcmocks.NewMockPaymentService(ctrl)
+				repo := repomocks.NewMockPaymentRepository(ctrl)
+				reconcileRepo := repomocks.NewMockReconcileRepository(ctrl)
+				p := svcmocks.NewMockBillProvider(ctrl)
+				p.EXPECT().Channel().Return(domain.PaymentChannelWechat).AnyTimes()
+				p.EXPECT().ParseBill(gomock.Any()).Return([]domain.BillItem{
+					{BizTradeNO: "ok", TxnID: "txn-ok", Amt: 100, Currency: "CNY", Status: domain.PaymentStatusSuccess},
+					{BizTradeNO: "missing", TxnID: "txn-missing", Amt: 100, Currency: "CNY", Status: domain.PaymentStatusSuccess},
+					{BizTradeNO: "amt", TxnID: "txn-amt", Amt: 200, Currency: "CNY", Status: domain.PaymentStatusSuccess},
+					{BizTradeNO: "unpaid", TxnID: "txn-unpaid", Amt: 100, Currency: "CNY", Status: domain.PaymentStatusSuccess},
+					{BizTradeNO: "forged", TxnID: "txn-forged", Amt: 100, Currency: "CNY", Status: domain.PaymentStatusSuccess},
+					{BizTradeNO: "usd", TxnID: "txn-usd", Amt: 100, Currency: "USD", Status: domain.PaymentStatusSuccess},
+					{BizTradeNO: "refunded", TxnID: "txn-refunded", Amt: 100, Currency: "CNY", Status: domain.PaymentStatusRefund},
+					{BizTradeNO: "refunded-unpaid", TxnID: "txn-refunded-unpaid", Amt: 100, Currency: "CNY",
+						Status: domain.PaymentStatusRefund},
+				}, nil)
+				repo.EXPECT().FindByBizTradeNOs(gomock.Any(), []string{"ok", "missing", "amt", "unpaid", "forged",
+					"usd", "refunded", "refunded-unpaid"}).
+					Return([]domain.Payment{
+						paid("ok", "txn-ok", 100),
+						paid("amt", "txn-amt", 100),
+						{BizTradeNO: "unpaid", Amt: domain.Amount{Total: 100, Currency: "CNY"}, Status: domain.PaymentStatusInit},
+						{BizTradeNO: "forged", Amt: domain.Amount{Total: 100, Currency: "CNY"}, Status: domain.PaymentStatusInit},
+						paid("usd", "txn-usd", 100),
+						paid("refunded", "txn-refunded", 100),
+						{BizTradeNO: "refunded-unpaid", Amt: domain.Amount{Total: 100, Currency: "CNY"},
+							Status: domain.PaymentStatusInit},
+					}, nil)
+				// 第三方确认付了钱才修复
+				p.EXPECT().QueryPayment(gomock.Any(), "unpaid").
+					Return(paid("unpaid", "txn-unpaid", 100), nil)
+				svc.EXPECT().HandlePaymentNotify(gomock.Any(), domain.Payment{
+					BizTradeNO: "unpaid",
+					TxnID:      "txn-unpaid",
+					Status:     domain.PaymentStatusSuccess,
+					Channel:    domain.PaymentChannelWechat,
+				}).Return(nil)
+				// 账单里面说付了钱，第三方说没有，比如说上传的账单是伪造的
+				p.EXPECT().QueryPayment(gomock.Any(), "forged").
+					Return(domain.Payment{BizTradeNO: "forged", Status: domain.PaymentStatusInit}, nil)
+				// 退款走退款的流程，不会走支付回调
+				svc.EXPECT().SyncPaymentRefunds(gomock.Any(), "refunded").Return(nil)
+				repo.EXPECT().GetPayment(gomock.Any(), "refunded").
+					Return(domain.Payment{BizTradeNO: "refunded", Status: domain.PaymentStatusRefund}, nil)
+				repo.EXPECT().FindPayedPayments(gomock.Any(), domain.PaymentChannelWechat, start, end, 0, 100).
+					Return([]domain.Payment{
+						paid("ok", "txn-ok", 100),
+						paid("extra", "txn-extra", 100),
+						paid("cross-day", "txn-cross-day", 100),
+					}, nil)
+				p.EXPECT().QueryPayment(gomock.Any(), "extra").
+					Return(domain.Payment{BizTradeNO: "extra", Status: domain.PaymentStatusInit}, nil)
+				// 第二天才付的钱，在第二天的账单里面
+				p.EXPECT().QueryPayment(gomock.Any(), "cross-day").
+					Return(paid("cross-day", "txn-cross-day", 100), nil)
+				reconcileRepo.EXPECT().SaveReport(gomock.Any(), gomock.Any()).Return(nil)
+				return svc, repo, reconcileRepo, []Provider{p}
+			},
+			channel: domain.PaymentChannelWechat,
+			wantReport: domain.ReconcileReport{
+				Channel:   domain.PaymentChannelWechat,
+				Date:      "20240101",
+				BillCount: 8,
+				Diffs: []domain.ReconcileDiff{
+					{BizTradeNO: "missing", TxnID: "txn-missing", Type: domain.ReconcileDiffMissing,
+						BillAmt: 100, BillStatus: domain.PaymentStatusSuccess},
+					{BizTradeNO: "amt", TxnID: "txn-amt", Type: domain.ReconcileDiffAmount,
+						LocalAmt: 100, BillAmt: 200,
+						LocalStatus: domain.PaymentStatusSuccess, BillStatus: domain.PaymentStatusSuccess},
+					{BizTradeNO: "unpaid", TxnID: "txn-unpaid", Type: domain.ReconcileDiffStatus,
+						LocalAmt: 100, BillAmt: 100,
+						LocalStatus: domain.PaymentStatusInit, BillStatus: domain.PaymentStatusSuccess,
+						Fixed: true},
+					{BizTradeNO: "forged", TxnID: "txn-forged", Type: domain.ReconcileDiffStatus,
+						LocalAmt: 100, BillAmt: 100,
+						LocalStatus: domain.PaymentStatusInit, BillStatus: domain.PaymentStatusSuccess},
+					{BizTradeNO: "usd", TxnID: "txn-usd", Type: domain.ReconcileDiffAmount,
+						LocalAmt: 100, BillAmt: 100,
+						LocalStatus: domain.PaymentStatusSuccess, BillStatus: domain.PaymentStatusSuccess},
+					{BizTradeNO: "refunded", TxnID: "txn-refunded", Type: domain.ReconcileDiffStatus,
+						LocalAmt: 100, BillAmt: 100,
+						LocalStatus: domain.PaymentStatusSuccess, BillStatus: domain.PaymentStatusRefund,
+						Fixed: true},
+					// 我们这边没付钱，第三方那边已经退了款，要人来看
+					{BizTradeNO: "refunded-unpaid", TxnID: "txn-refunded-unpaid", Type: domain.ReconcileDiffStatus,
+						LocalAmt: 100, BillAmt: 100,
+						LocalStatus: domain.PaymentStatusInit, BillStatus: domain.PaymentStatusRefund},
+					{BizTradeNO: "extra", TxnID: "txn-extra", Type: domain.ReconcileDiffExtra,
+						LocalAmt: 100, LocalStatus: domain.PaymentStatusSuccess,
+						BillStatus: domain.PaymentStatusInit},
+				},
+			},
+		},
+		{
+			name: "渠道不支持对账单",
+			mock: func(ctrl *gomock.Controller) (PaymentService, repository.PaymentRepository,
+				repository.ReconcileRepository, []Provider) {
+				p := svcmocks.NewMockProvider(ctrl)
+				p.EXPECT().Channel().Return(domain.PaymentChannelAlipay).AnyTimes()
+				return svcmocks.NewMockPaymentService(ctrl),
+					repomocks.NewMockPaymentRepository(ctrl),
+					repomocks.NewMockReconcileRepository(ctrl), []Provider{p}
+			},
+			channel: domain.PaymentChannelAlipay,
+			wantErr: ErrBillNotSupported,
+		},
+		{
+			name: "解析账单失败",
+			mock: func(ctrl *gomock.Controller) (PaymentService, repository.PaymentRepository,
+				repository.ReconcileRepository, []Provider) {
+				p := svcmocks.NewMockBillProvider(ctrl)
+				p.EXPECT().Channel().Return(domain.PaymentChannelWechat).AnyTimes()
+				p.EXPECT().ParseBill(gomock.Any()).Return(nil, errors.New("mock parse error"))
+				return svcmocks.NewMockPaymentService(ctrl),
+					repomocks.NewMockPaymentRepository(ctrl),
+					repomocks.NewMockReconcileRepository(ctrl), []Provider{p}
+			},
+			channel: domain.PaymentChannelWechat,
+			wantErr: errors.New("mock parse error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc, repo, reconcileRepo, providers := tc.mock(ctrl)
+			s := NewReconcileService(svc, repo, reconcileRepo, providers, logger.NewNopLogger())
+			report, err := s.Import(context.Background(), tc.channel, date, strings.NewReader(""))
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantReport, report)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"gitee.com/geekbang/basic-go/webook/payment/events"
+	"gitee.com/geekbang/basic-go/webook/pkg/outbox"
+	"time"
+)
+
+// Refund 还没有结果的退款，会再向第三方发起一次，
+// 第三方那边也是按照退款单号去重的
+func (s *paymentService) Refund(ctx context.Context, r domain.Refund) (domain.Refund, error) {
+	pmt, err := s.repo.GetPayment(ctx, r.BizTradeNO)
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	p, err := s.provider(pmt.Channel)
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	r.Status = domain.RefundStatusInit
+	refund, err := s.refundRepo.AddRefund(ctx, r)
+	if errors.Is(err, ErrDuplicateRefund) {
+		refund, err = s.refundRepo.GetRefund(ctx, r.RefundNO)
+		if err != nil {
+			return domain.Refund{}, err
+		}
+		if refund.BizTradeNO != r.BizTradeNO {
+			return domain.Refund{}, ErrDuplicateRefund
+		}
+		if refund.Status.Completed() {
+			return refund, nil
+		}
+	} else if err != nil {
+		return domain.Refund{}, err
+	}
+	res, err := p.Refund(ctx, refund)
+	if err != nil {
+		// 不知道第三方到底有没有受理，保持处理中，后面依赖回调或者 SyncRefund
+		return refund, err
+	}
+	return s.updateRefund(ctx, refund.RefundNO, res)
+}
+
+func (s *paymentService) GetRefund(ctx context.Context, refundNO string) (domain.Refund, error) {
+	return s.refundRepo.GetRefund(ctx, refundNO)
+}
+
+// SyncRefund 主动查询第三方的退款结果
+func (s *paymentService) SyncRefund(ctx context.Context, refundNO string) error {
+	refund, err := s.refundRepo.GetRefund(ctx, refundNO)
+	if err != nil {
+		return err
+	}
+	pmt, err := s.repo.GetPayment(ctx, refund.BizTradeNO)
+	if err != nil {
+		return err
+	}
+	p, err := s.provider(pmt.Channel)
+	if err != nil {
+		return err
+	}
+	res, err := p.QueryRefund(ctx, refund)
+	if err != nil {
+		return err
+	}
+	_, err = s.updateRefund(ctx, refundNO, res)
+	return err
+}
+
+func (s *paymentService) SyncPaymentRefunds(ctx context.Context, bizTradeNO string) error {
+	refunds, err := s.refundRepo.FindPendingRefundsByBizTradeNO(ctx, bizTradeNO)
+	if err != nil {
+		return err
+	}
+	for _, r := range refunds {
+		err = s.SyncRefund(ctx, r.RefundNO)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *paymentService) FindPendingRefunds(ctx context.Context, offset, limit int, t time.Time) ([]domain.Refund, error) {
+	return s.refundRepo.FindPendingRefunds(ctx, offset, limit, t)
+}
+
+func (s *paymentService) HandleRefundNotify(ctx context.Context, r domain.Refund) error {
+	_, err := s.updateRefund(ctx, r.RefundNO, r)
+	return err
+}
+
+func (s *paymentService) updateRefund(ctx context.Context,
+	refundNO string, res domain.Refund) (domain.Refund, error) {
+	return s.refundRepo.UpdateRefund(ctx, domain.Refund{
+		RefundNO: refundNO,
+		TxnID:    res.TxnID,
+		Status:   res.Status,
+	}, func(refund domain.Refund, pmtStatus domain.PaymentStatus) []outbox.Message {
+		if !refund.Status.Completed() {
+			return nil
+		}
+		// 退款有了结果，通知业务方。全部退完之后，支付记录会变成已退款
+		return []outbox.Message{outbox.NewMessage(refund.BizTradeNO, events.RefundEvent{
+			BizTradeNO:    refund.BizTradeNO,
+			RefundNO:      refund.RefundNO,
+			Amt:           refund.Amt.Total,
+			Currency:      refund.Amt.Currency,
+			Status:        refund.Status.AsUint8(),
+			PaymentStatus: pmtStatus.AsUint8(),
+		})}
+	})
+}
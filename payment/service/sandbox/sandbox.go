@@ -0,0 +1,300 @@
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"gitee.com/geekbang/basic-go/webook/payment/service"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidSign     = errors.New("沙箱通知的签名不对")
+	ErrPaymentNotFound = errors.New("沙箱里面没有这笔支付")
+	ErrInvalidBill     = errors.New("沙箱账单格式不对")
+	ErrPaymentClosed   = errors.New("沙箱里面这笔支付已经关闭了")
+)
+
+var billHeader = []string{"biz_trade_no", "txn_id", "amt", "currency", "status"}
+
+var _ service.BillProvider = (*PaymentService)(nil)
+
+// Result 模拟的支付结果
+type Result string
+
+const (
+	ResultSuccess Result = "success"
+	ResultFailed  Result = "failed"
+	// ResultTimeout 用户一直不付钱，也不会有通知，只能等订单过期
+	ResultTimeout Result = "timeout"
+)
+
+type Config struct {
+	// Secret 通知的签名密钥
+	Secret string `yaml:"secret"`
+	// NotifyURL 模拟第三方回调我们的地址
+	NotifyURL string `yaml:"notifyURL"`
+	// PayURL 模拟的收银台，预支付返回的就是这个地址，
+	// 访问它并且带上 result 参数就相当于用户付了钱
+	PayURL string `yaml:"payURL"`
+	// AutoResult 不为空的话，预支付之后过 Delay 自动出结果，不需要访问收银台
+	AutoResult Result        `yaml:"autoResult"`
+	Delay      time.Duration `yaml:"delay"`
+}
+
+// PaymentService 本地沙箱渠道，不会真的扣钱，数据都在内存里面，
+// 用来在没有微信和支付宝的环境下把整个支付流程跑通
+type PaymentService struct {
+	cfg    Config
+	client *http.Client
+	l      logger.LoggerV1
+
+	mutex    sync.RWMutex
+	payments map[string]domain.Payment
+	refunds  map[string]domain.Refund
+	// payedAt 支付成功的时间，出账单用
+	payedAt map[string]time.Time
+}
+
+func NewPaymentService(cfg Config, l logger.LoggerV1) *PaymentService {
+	return &PaymentService{
+		cfg:      cfg,
+		client:   &http.Client{Timeout: time.Second * 3},
+		l:        l,
+		payments: make(map[string]domain.Payment),
+		refunds:  make(map[string]domain.Refund),
+		payedAt:  make(map[string]time.Time),
+	}
+}
+
+func (s *PaymentService) Channel() domain.PaymentChannel {
+	return domain.PaymentChannelSandbox
+}
+
+func (s *PaymentService) Prepay(ctx context.Context, pmt domain.Payment) (string, error) {
+	s.mutex.Lock()
+	s.payments[pmt.BizTradeNO] = domain.Payment{
+		Amt:        pmt.Amt,
+		BizTradeNO: pmt.BizTradeNO,
+		Status:     domain.PaymentStatusInit,
+		Channel:    domain.PaymentChannelSandbox,
+	}
+	s.mutex.Unlock()
+	if s.cfg.AutoResult != "" {
+		bizTradeNO := pmt.BizTradeNO
+		time.AfterFunc(s.cfg.Delay, func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+			defer cancel()
+			err := s.Pay(ctx, bizTradeNO, s.cfg.AutoResult)
+			if err != nil {
+				s.l.Error("沙箱自动支付失败", logger.Error(err),
+					logger.String("biz_trade_no", bizTradeNO))
+			}
+		})
+	}
+	return s.cfg.PayURL + "?biz_trade_no=" + url.QueryEscape(pmt.BizTradeNO), nil
+}
+
+// Pay 模拟用户在收银台付钱，然后像第三方一样回调 NotifyURL
+func (s *PaymentService) Pay(ctx context.Context, bizTradeNO string, result Result) error {
+	s.mutex.Lock()
+	pmt, ok := s.payments[bizTradeNO]
+	if !ok {
+		s.mutex.Unlock()
+		return ErrPaymentNotFound
+	}
+	if pmt.Status == domain.PaymentStatusClosed {
+		s.mutex.Unlock()
+		return ErrPaymentClosed
+	}
+	switch result {
+	case ResultSuccess:
+		pmt.Status = domain.PaymentStatusSuccess
+		pmt.TxnID = fmt.Sprintf("sandbox-%d", time.Now().UnixNano())
+		s.payedAt[bizTradeNO] = time.Now()
+	case ResultFailed:
+		pmt.Status = domain.PaymentStatusFailed
+	default:
+		// 超时就什么都不做
+		s.mutex.Unlock()
+		return nil
+	}
+	s.payments[bizTradeNO] = pmt
+	s.mutex.Unlock()
+	return s.notify(ctx, pmt)
+}
+
+func (s *PaymentService) QueryPayment(ctx context.Context, bizTradeNO string) (domain.Payment, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	pmt, ok := s.payments[bizTradeNO]
+	if !ok {
+		// 重启之后沙箱里面的数据就没了，当作没有付钱
+		return domain.Payment{BizTradeNO: bizTradeNO, Status: domain.PaymentStatusInit}, nil
+	}
+	return pmt, nil
+}
+
+// ClosePayment 关闭之后就不能再付钱了，已经付了钱的关不掉
+func (s *PaymentService) ClosePayment(ctx context.Context, bizTradeNO string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	pmt, ok := s.payments[bizTradeNO]
+	if !ok || pmt.Status != domain.PaymentStatusInit {
+		return nil
+	}
+	pmt.Status = domain.PaymentStatusClosed
+	s.payments[bizTradeNO] = pmt
+	return nil
+}
+
+// Refund 沙箱里面退款马上就成功
+func (s *PaymentService) Refund(ctx context.Context, r domain.Refund) (domain.Refund, error) {
+	res := domain.Refund{
+		RefundNO: r.RefundNO,
+		TxnID:    fmt.Sprintf("sandbox-refund-%d", time.Now().UnixNano()),
+		Status:   domain.RefundStatusSuccess,
+	}
+	s.mutex.Lock()
+	s.refunds[r.RefundNO] = res
+	s.mutex.Unlock()
+	return res, nil
+}
+
+func (s *PaymentService) QueryRefund(ctx context.Context, r domain.Refund) (domain.Refund, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	res, ok := s.refunds[r.RefundNO]
+	if !ok {
+		return domain.Refund{RefundNO: r.RefundNO, Status: domain.RefundStatusFailed}, nil
+	}
+	return res, nil
+}
+
+// DownloadBill 沙箱的账单就是内存里面当天支付成功的交易，
+// 格式是 biz_trade_no,txn_id,amt,currency,status
+func (s *PaymentService) DownloadBill(ctx context.Context, date time.Time) (io.ReadCloser, error) {
+	day := date.Format(domain.ReconcileDateLayout)
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	_ = w.Write(billHeader)
+	s.mutex.RLock()
+	for bizTradeNO, t := range s.payedAt {
+		if t.Format(domain.ReconcileDateLayout) != day {
+			continue
+		}
+		pmt := s.payments[bizTradeNO]
+		_ = w.Write([]string{pmt.BizTradeNO, pmt.TxnID,
+			strconv.FormatInt(pmt.Amt.Total, 10), pmt.Amt.Currency,
+			strconv.Itoa(int(pmt.Status))})
+	}
+	s.mutex.RUnlock()
+	w.Flush()
+	return io.NopCloser(buf), w.Error()
+}
+
+func (s *PaymentService) ParseBill(r io.Reader) ([]domain.BillItem, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	res := make([]domain.BillItem, 0, len(records)-1)
+	// 跳过表头
+	for _, record := range records[1:] {
+		if len(record) != len(billHeader) {
+			return nil, ErrInvalidBill
+		}
+		// 金额不能是负数
+		amt, err := strconv.ParseUint(record[2], 10, 63)
+		if err != nil {
+			return nil, fmt.Errorf("%w, 金额 %s", ErrInvalidBill, record[2])
+		}
+		status, err := strconv.ParseUint(record[4], 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("%w, 状态 %s", ErrInvalidBill, record[4])
+		}
+		res = append(res, domain.BillItem{
+			BizTradeNO: record[0],
+			TxnID:      record[1],
+			Amt:        int64(amt),
+			Currency:   record[3],
+			Status:     domain.PaymentStatus(status),
+		})
+	}
+	return res, nil
+}
+
+// VerifyNotification 校验沙箱回调的签名，并且解析出支付结果
+func (s *PaymentService) VerifyNotification(values url.Values) (domain.Payment, error) {
+	sign := values.Get("sign")
+	values.Del("sign")
+	if !hmac.Equal([]byte(sign), []byte(s.sign(values))) {
+		return domain.Payment{}, ErrInvalidSign
+	}
+	status, err := strconv.ParseUint(values.Get("status"), 10, 8)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	amt, err := strconv.ParseUint(values.Get("amt"), 10, 63)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	return domain.Payment{
+		Amt: domain.Amount{
+			Currency: values.Get("currency"),
+			Total:    int64(amt),
+		},
+		BizTradeNO: values.Get("biz_trade_no"),
+		TxnID:      values.Get("txn_id"),
+		Status:     domain.PaymentStatus(status),
+		Channel:    domain.PaymentChannelSandbox,
+	}, nil
+}
+
+func (s *PaymentService) notify(ctx context.Context, pmt domain.Payment) error {
+	values := url.Values{}
+	values.Set("biz_trade_no", pmt.BizTradeNO)
+	values.Set("txn_id", pmt.TxnID)
+	values.Set("status", strconv.Itoa(int(pmt.Status)))
+	values.Set("amt", strconv.FormatInt(pmt.Amt.Total, 10))
+	values.Set("currency", pmt.Amt.Currency)
+	values.Set("sign", s.sign(values))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		s.cfg.NotifyURL, strings.NewReader(values.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("沙箱回调失败，状态码 %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sign Encode 会按照 key 排序，所以两边算出来是一样的
+func (s *PaymentService) sign(values url.Values) string {
+	h := hmac.New(sha256.New, []byte(s.cfg.Secret))
+	h.Write([]byte(values.Encode()))
+	return hex.EncodeToString(h.Sum(nil))
+}
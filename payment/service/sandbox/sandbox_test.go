@@ -0,0 +1,187 @@
+package sandbox
+
+import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestPaymentService_Pay(t *testing.T) {
+	testCases := []struct {
+		name   string
+		before func(svc *PaymentService)
+		result Result
+
+		wantErr    error
+		wantNotify bool
+		wantStatus domain.PaymentStatus
+	}{
+		{
+			name:       "支付成功，回调带上金额",
+			result:     ResultSuccess,
+			wantNotify: true,
+			wantStatus: domain.PaymentStatusSuccess,
+		},
+		{
+			name:       "支付失败",
+			result:     ResultFailed,
+			wantNotify: true,
+			wantStatus: domain.PaymentStatusFailed,
+		},
+		{
+			name:       "超时，不回调",
+			result:     ResultTimeout,
+			wantStatus: domain.PaymentStatusInit,
+		},
+		{
+			name: "已经关闭了",
+			before: func(svc *PaymentService) {
+				_ = svc.ClosePayment(context.Background(), "reward-1")
+			},
+			result:     ResultSuccess,
+			wantErr:    ErrPaymentClosed,
+			wantStatus: domain.PaymentStatusClosed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var notified url.Values
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				notified = r.PostForm
+			}))
+			defer server.Close()
+			svc := NewPaymentService(Config{Secret: "secret", NotifyURL: server.URL},
+				logger.NewNopLogger())
+			ctx := context.Background()
+			_, err := svc.Prepay(ctx, domain.Payment{
+				BizTradeNO: "reward-1",
+				Amt:        domain.Amount{Currency: "CNY", Total: 100},
+			})
+			require.NoError(t, err)
+			if tc.before != nil {
+				tc.before(svc)
+			}
+
+			err = svc.Pay(ctx, "reward-1", tc.result)
+			assert.Equal(t, tc.wantErr, err)
+			pmt, err := svc.QueryPayment(ctx, "reward-1")
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantStatus, pmt.Status)
+			if !tc.wantNotify {
+				assert.Nil(t, notified)
+				return
+			}
+			// 回调我们的内容要能通过验签
+			res, err := svc.VerifyNotification(notified)
+			require.NoError(t, err)
+			assert.Equal(t, pmt, res)
+		})
+	}
+}
+
+func TestPaymentService_VerifyNotification(t *testing.T) {
+	svc := NewPaymentService(Config{Secret: "secret"}, logger.NewNopLogger())
+	values := url.Values{}
+	values.Set("biz_trade_no", "reward-1")
+	values.Set("txn_id", "sandbox-1")
+	values.Set("status", "2")
+	values.Set("amt", "100")
+	values.Set("currency", "CNY")
+	values.Set("sign", svc.sign(values))
+
+	pmt, err := svc.VerifyNotification(values)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Payment{
+		Amt:        domain.Amount{Currency: "CNY", Total: 100},
+		BizTradeNO: "reward-1",
+		TxnID:      "sandbox-1",
+		Status:     domain.PaymentStatusSuccess,
+		Channel:    domain.PaymentChannelSandbox,
+	}, pmt)
+
+	// 改了金额，签名就对不上了
+	values.Set("sign", svc.sign(values))
+	values.Set("amt", "1")
+	_, err = svc.VerifyNotification(values)
+	assert.Equal(t, ErrInvalidSign, err)
+
+	// 别的密钥签出来的
+	other := NewPaymentService(Config{Secret: "other"}, logger.NewNopLogger())
+	values.Set("sign", other.sign(values))
+	_, err = svc.VerifyNotification(values)
+	assert.Equal(t, ErrInvalidSign, err)
+}
+
+func TestPaymentService_Refund(t *testing.T) {
+	svc := NewPaymentService(Config{Secret: "secret"}, logger.NewNopLogger())
+	ctx := context.Background()
+
+	r, err := svc.QueryRefund(ctx, domain.Refund{RefundNO: "refund-1"})
+	require.NoError(t, err)
+	assert.Equal(t, domain.RefundStatusFailed, r.Status)
+
+	res, err := svc.Refund(ctx, domain.Refund{RefundNO: "refund-1"})
+	require.NoError(t, err)
+	assert.Equal(t, domain.RefundStatusSuccess, res.Status)
+	r, err = svc.QueryRefund(ctx, domain.Refund{RefundNO: "refund-1"})
+	require.NoError(t, err)
+	assert.Equal(t, res, r)
+}
+
+func TestPaymentService_ParseBill(t *testing.T) {
+	const header = "biz_trade_no,txn_id,amt,currency,status\n"
+	testCases := []struct {
+		name string
+		bill string
+
+		wantItems []domain.BillItem
+		wantErr   error
+	}{
+		{
+			name: "支付成功和已经退款的交易",
+			bill: header + "reward-1,sandbox-1,100,CNY,2\nreward-2,sandbox-2,200,USD,4\n",
+			wantItems: []domain.BillItem{
+				{BizTradeNO: "reward-1", TxnID: "sandbox-1", Amt: 100, Currency: "CNY",
+					Status: domain.PaymentStatusSuccess},
+				{BizTradeNO: "reward-2", TxnID: "sandbox-2", Amt: 200, Currency: "USD",
+					Status: domain.PaymentStatusRefund},
+			},
+		},
+		{
+			name:      "只有表头",
+			bill:      header,
+			wantItems: []domain.BillItem{},
+		},
+		{
+			name:    "少了币种这一列",
+			bill:    "biz_trade_no,txn_id,amt,status\nreward-1,sandbox-1,100,2\n",
+			wantErr: ErrInvalidBill,
+		},
+		{
+			name:    "负数金额",
+			bill:    header + "reward-1,sandbox-1,-100,CNY,2\n",
+			wantErr: ErrInvalidBill,
+		},
+		{
+			name:    "状态不对",
+			bill:    header + "reward-1,sandbox-1,100,CNY,abc\n",
+			wantErr: ErrInvalidBill,
+		},
+	}
+	svc := NewPaymentService(Config{Secret: "secret"}, logger.NewNopLogger())
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			items, err := svc.ParseBill(strings.NewReader(tc.bill))
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.wantItems, items)
+		})
+	}
+}
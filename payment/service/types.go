@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"io"
+	"time"
+)
+
+//go:generate mockgen -source=./types.go -destination=mocks/payment.mock.go -package=svcmocks PaymentService Provider BillProvider ReconcileService
+type PaymentService interface {
+	// Prepay 预支付，根据 pmt.Channel 找到对应的渠道。
+	// 返回的是二维码的内容或者支付页面的 URL
+	Prepay(ctx context.Context, pmt domain.Payment) (string, error)
+	GetPayment(ctx context.Context, bizTradeNO string) (domain.Payment, error)
+	// SyncPayment 主动去第三方查询支付结果
+	SyncPayment(ctx context.Context, bizTradeNO string) error
+	// HandlePaymentNotify 处理第三方的支付结果通知，验签是调用者的事情。
+	// pmt.Channel 是发出通知的渠道，和支付的渠道对不上返回 ErrChannelMismatch。
+	// pmt.Amt 不为空的话，和支付的金额对不上返回 ErrAmountMismatch
+	HandlePaymentNotify(ctx context.Context, pmt domain.Payment) error
+	FindExpiredPayment(ctx context.Context, offset, limit int, t time.Time) ([]domain.Payment, error)
+	// ClosePayment 关闭还没有付钱的订单，先查一下第三方，已经付了钱的就按照支付成功处理
+	ClosePayment(ctx context.Context, bizTradeNO string) error
+	// CloseExpiredPayments 关闭一批已经过期的订单，返回处理了多少个
+	CloseExpiredPayments(ctx context.Context, limit int) (int, error)
+	// NextExpireTime 下一个要过期的订单的过期时间，没有的话返回零值
+	NextExpireTime(ctx context.Context) (time.Time, error)
+
+	// Refund 发起退款，r.Amt.Total 为 0 就是把剩下的全部退掉。
+	// 同一个 RefundNO 重复调用是幂等的
+	Refund(ctx context.Context, r domain.Refund) (domain.Refund, error)
+	GetRefund(ctx context.Context, refundNO string) (domain.Refund, error)
+	SyncRefund(ctx context.Context, refundNO string) error
+	// SyncPaymentRefunds 主动查询某一笔支付所有还在处理中的退款
+	SyncPaymentRefunds(ctx context.Context, bizTradeNO string) error
+	// FindPendingRefunds 在 t 之前更新过，还在处理中的退款
+	FindPendingRefunds(ctx context.Context, offset, limit int, t time.Time) ([]domain.Refund, error)
+	// HandleRefundNotify 处理第三方的退款结果通知，验签是调用者的事情
+	HandleRefundNotify(ctx context.Context, r domain.Refund) error
+}
+
+// Provider 支付渠道，只负责和第三方打交道，不碰我们自己的数据
+type Provider interface {
+	Channel() domain.PaymentChannel
+	// Prepay 返回二维码的内容或者支付页面的 URL
+	Prepay(ctx context.Context, pmt domain.Payment) (string, error)
+	// QueryPayment 返回的 Payment 里面只有 BizTradeNO, TxnID 和 Status
+	QueryPayment(ctx context.Context, bizTradeNO string) (domain.Payment, error)
+	// ClosePayment 关闭还没有付钱的订单，关闭之后用户就付不了钱了。
+	// 第三方那边还没有这个订单也算成功
+	ClosePayment(ctx context.Context, bizTradeNO string) error
+	// Refund 返回的 Refund 里面只有 TxnID 和 Status
+	Refund(ctx context.Context, r domain.Refund) (domain.Refund, error)
+	QueryRefund(ctx context.Context, r domain.Refund) (domain.Refund, error)
+}
+
+// BillProvider 可以下载对账单的渠道
+type BillProvider interface {
+	Provider
+	// DownloadBill 下载某一天已经成功的交易的账单原文
+	DownloadBill(ctx context.Context, date time.Time) (io.ReadCloser, error)
+	// ParseBill 解析账单，手动导入的账单也是用这个
+	ParseBill(r io.Reader) ([]domain.BillItem, error)
+}
+
+// ReconcileService 拿第三方的账单和我们的支付记录对账
+type ReconcileService interface {
+	// Reconcile 下载某个渠道某一天的账单并且对账
+	Reconcile(ctx context.Context, channel domain.PaymentChannel, date time.Time) (domain.ReconcileReport, error)
+	// Import 用手动导入的账单对账，比如说下载接口出了问题
+	Import(ctx context.Context, channel domain.PaymentChannel, date time.Time, bill io.Reader) (domain.ReconcileReport, error)
+	GetReport(ctx context.Context, channel domain.PaymentChannel, date time.Time) (domain.ReconcileReport, error)
+	// BillChannels 可以下载对账单的渠道
+	BillChannels() []domain.PaymentChannel
+}
@@ -0,0 +1,154 @@
+package wechat
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"github.com/wechatpay-apiv3/wechatpay-go/core"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const tradeBillURL = "https://api.mch.weixin.qq.com/v3/bill/tradebill"
+
+var (
+	errBillHashMismatch = errors.New("微信账单的摘要对不上")
+	errInvalidBill      = errors.New("微信账单格式不对")
+)
+
+type tradeBill struct {
+	HashType    string `json:"hash_type"`
+	HashValue   string `json:"hash_value"`
+	DownloadURL string `json:"download_url"`
+}
+
+// DownloadBill 先申请交易账单拿到下载地址，再下载账单文件。
+// 账单里面只有支付成功的交易，当天没有交易的话微信会返回 NO_STATEMENT_EXIST
+func (n *NativePaymentService) DownloadBill(ctx context.Context, date time.Time) (io.ReadCloser, error) {
+	query := url.Values{}
+	query.Set("bill_date", date.Format("2006-01-02"))
+	query.Set("bill_type", "SUCCESS")
+	result, err := n.svc.Client.Get(ctx, tradeBillURL+"?"+query.Encode())
+	if err != nil {
+		var apiErr *core.APIError
+		if errors.As(err, &apiErr) && apiErr.Code == "NO_STATEMENT_EXIST" {
+			return io.NopCloser(strings.NewReader("")), nil
+		}
+		return nil, err
+	}
+	var bill tradeBill
+	err = core.UnMarshalResponse(result.Response, &bill)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err = n.billCli.Get(ctx, bill.DownloadURL)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Response.Body.Close()
+	data, err := io.ReadAll(result.Response.Body)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha1.Sum(data)
+	if !strings.EqualFold(hex.EncodeToString(sum[:]), bill.HashValue) {
+		return nil, errBillHashMismatch
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// ParseBill 解析微信的交易账单。
+// 第一行是表头，每个字段前面都有一个 `，
+// 最后两行是汇总，从"总交易单数"开始就不是交易了
+func (n *NativePaymentService) ParseBill(r io.Reader) ([]domain.BillItem, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	idx := make(map[string]int, len(header))
+	for i, name := range header {
+		// 文件开头可能有 BOM
+		idx[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	cols := make([]int, 0, 5)
+	for _, name := range []string{"微信订单号", "商户订单号", "交易状态", "订单金额", "货币种类"} {
+		i, ok := idx[name]
+		if !ok {
+			return nil, fmt.Errorf("%w, 缺少 %s", errInvalidBill, name)
+		}
+		cols = append(cols, i)
+	}
+
+	var res []domain.BillItem
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return res, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) > 0 && strings.HasPrefix(record[0], "总交易单数") {
+			return res, nil
+		}
+		field := func(i int) string {
+			if cols[i] >= len(record) {
+				return ""
+			}
+			return strings.TrimPrefix(strings.TrimSpace(record[cols[i]]), "`")
+		}
+		status, ok := n.nativeCBTypeToStatus[field(2)]
+		if !ok {
+			return nil, fmt.Errorf("%w, 微信的状态是 %s", errUnknownTransactionState, field(2))
+		}
+		amt, err := yuanToFen(field(3))
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, domain.BillItem{
+			TxnID:      field(0),
+			BizTradeNO: field(1),
+			Status:     status,
+			Amt:        amt,
+			Currency:   field(4),
+		})
+	}
+}
+
+// yuanToFen 账单里面的金额是元，精确到分。
+// 不用浮点数，免得 0.29 变成 28。支付成功的交易金额不会是负数，
+// 用 ParseUint 连同 "-0.50" 这种一起拒绝掉
+func yuanToFen(s string) (int64, error) {
+	parts := strings.SplitN(s, ".", 2)
+	yuan, fen := parts[0], ""
+	if len(parts) == 2 {
+		fen = parts[1]
+		if len(fen) == 0 || len(fen) > 2 {
+			return 0, fmt.Errorf("%w, 金额 %s", errInvalidBill, s)
+		}
+	}
+	fen = (fen + "00")[:2]
+	y, err := strconv.ParseUint(yuan, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w, 金额 %s", errInvalidBill, s)
+	}
+	f, err := strconv.ParseUint(fen, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("%w, 金额 %s", errInvalidBill, s)
+	}
+	return int64(y*100 + f), nil
+}
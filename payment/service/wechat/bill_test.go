@@ -0,0 +1,109 @@
+package wechat
+
+import (
+	"encoding/csv"
+	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestNativePaymentService_ParseBill(t *testing.T) {
+	const header = "\ufeff交易时间,微信订单号,商户订单号,交易状态,货币种类,订单金额\n"
+	testCases := []struct {
+		name string
+		bill string
+
+		wantItems []domain.BillItem
+		wantErr   error
+	}{
+		{
+			name: "支付成功和已经退款的交易",
+			bill: header +
+				"`2024-01-01 10:00:00,`txn-1,`reward-1,`SUCCESS,`CNY,`0.29\n" +
+				"`2024-01-01 11:00:00,`txn-2,`reward-2,`REFUND,`CNY,`12\n" +
+				"总交易单数,应结订单总金额\n" +
+				"`2,`12.29\n",
+			wantItems: []domain.BillItem{
+				{BizTradeNO: "reward-1", TxnID: "txn-1", Amt: 29, Currency: "CNY",
+					Status: domain.PaymentStatusSuccess},
+				{BizTradeNO: "reward-2", TxnID: "txn-2", Amt: 1200, Currency: "CNY",
+					Status: domain.PaymentStatusRefund},
+			},
+		},
+		{
+			name: "币种原样返回，由对账去比较",
+			bill: header + "`2024-01-01 10:00:00,`txn-1,`reward-1,`SUCCESS,`USD,`1.00\n",
+			wantItems: []domain.BillItem{
+				{BizTradeNO: "reward-1", TxnID: "txn-1", Amt: 100, Currency: "USD",
+					Status: domain.PaymentStatusSuccess},
+			},
+		},
+		{
+			name: "空账单",
+			bill: "",
+		},
+		{
+			name:    "缺少列",
+			bill:    "交易时间,微信订单号,商户订单号,交易状态,订单金额\n",
+			wantErr: errInvalidBill,
+		},
+		{
+			name:    "负数金额",
+			bill:    header + "`2024-01-01 10:00:00,`txn-1,`reward-1,`SUCCESS,`CNY,`-0.50\n",
+			wantErr: errInvalidBill,
+		},
+		{
+			name:    "金额格式不对",
+			bill:    header + "`2024-01-01 10:00:00,`txn-1,`reward-1,`SUCCESS,`CNY,`1.2.3\n",
+			wantErr: errInvalidBill,
+		},
+		{
+			name:    "不认识的交易状态",
+			bill:    header + "`2024-01-01 10:00:00,`txn-1,`reward-1,`UNKNOWN,`CNY,`1.00\n",
+			wantErr: errUnknownTransactionState,
+		},
+		{
+			name:    "CSV 格式不对",
+			bill:    header + "`2024-01-01 10:00:00,\"txn-1,`reward-1\n",
+			wantErr: csv.ErrQuote,
+		},
+	}
+	svc := NewNativePaymentService("", "", nil, nil, nil, logger.NewNopLogger())
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			items, err := svc.ParseBill(strings.NewReader(tc.bill))
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.wantItems, items)
+		})
+	}
+}
+
+func Test_yuanToFen(t *testing.T) {
+	testCases := []struct {
+		yuan    string
+		want    int64
+		wantErr bool
+	}{
+		{yuan: "12.34", want: 1234},
+		{yuan: "12.3", want: 1230},
+		{yuan: "12", want: 1200},
+		{yuan: "0.29", want: 29},
+		{yuan: "-1.00", wantErr: true},
+		{yuan: "-0.50", wantErr: true},
+		{yuan: "+1.00", wantErr: true},
+		{yuan: "1.234", wantErr: true},
+		{yuan: "1.", wantErr: true},
+		{yuan: "1.-5", wantErr: true},
+		{yuan: "", wantErr: true},
+		{yuan: "abc", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.yuan, func(t *testing.T) {
+			fen, err := yuanToFen(tc.yuan)
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.want, fen)
+		})
+	}
+}
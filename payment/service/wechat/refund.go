@@ -0,0 +1,74 @@
+package wechat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"github.com/wechatpay-apiv3/wechatpay-go/core"
+	"github.com/wechatpay-apiv3/wechatpay-go/services/refunddomestic"
+)
+
+var errUnknownRefundState = errors.New("未知的微信退款状态")
+
+// RefundNotify 退款结果通知解密之后的内容，微信的 SDK 里面没有定义
+type RefundNotify struct {
+	OutTradeNo   *string `json:"out_trade_no"`
+	OutRefundNo  *string `json:"out_refund_no"`
+	RefundId     *string `json:"refund_id"`
+	RefundStatus *string `json:"refund_status"`
+}
+
+func (n *NativePaymentService) Refund(ctx context.Context, r domain.Refund) (domain.Refund, error) {
+	resp, _, err := n.refundSvc.Create(ctx, refunddomestic.CreateRequest{
+		OutTradeNo:  core.String(r.BizTradeNO),
+		OutRefundNo: core.String(r.RefundNO),
+		Reason:      core.String(r.Reason),
+		NotifyUrl:   core.String(n.refundNotifyURL),
+		Amount: &refunddomestic.AmountReq{
+			Refund:   core.Int64(r.Amt.Total),
+			Total:    core.Int64(r.PaymentAmt),
+			Currency: core.String(r.Amt.Currency),
+		},
+	})
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	return n.toRefund(r.RefundNO, resp.RefundId, (*string)(resp.Status))
+}
+
+func (n *NativePaymentService) QueryRefund(ctx context.Context, r domain.Refund) (domain.Refund, error) {
+	resp, _, err := n.refundSvc.QueryByOutRefundNo(ctx, refunddomestic.QueryByOutRefundNoRequest{
+		OutRefundNo: core.String(r.RefundNO),
+	})
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	return n.toRefund(r.RefundNO, resp.RefundId, (*string)(resp.Status))
+}
+
+// ToRefund 把退款结果通知转换成我们的 Refund
+func (n *NativePaymentService) ToRefund(notify *RefundNotify) (domain.Refund, error) {
+	if notify.OutRefundNo == nil {
+		return domain.Refund{}, fmt.Errorf("%w, 微信没有返回退款单号", errUnknownRefundState)
+	}
+	return n.toRefund(*notify.OutRefundNo, notify.RefundId, notify.RefundStatus)
+}
+
+func (n *NativePaymentService) toRefund(refundNO string, txnID *string, state *string) (domain.Refund, error) {
+	if state == nil {
+		return domain.Refund{}, fmt.Errorf("%w, 微信没有返回退款状态", errUnknownRefundState)
+	}
+	status, ok := n.refundStatusToStatus[*state]
+	if !ok {
+		return domain.Refund{}, fmt.Errorf("%w, 微信的状态是 %s", errUnknownRefundState, *state)
+	}
+	r := domain.Refund{
+		RefundNO: refundNO,
+		Status:   status,
+	}
+	if txnID != nil {
+		r.TxnID = *txnID
+	}
+	return r, nil
+}
@@ -0,0 +1,65 @@
+package web
+
+import (
+	"gitee.com/geekbang/basic-go/webook/payment/service"
+	"gitee.com/geekbang/basic-go/webook/payment/service/alipay"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type AlipayHandler struct {
+	aliSvc *alipay.PaymentService
+	svc    service.PaymentService
+	l      logger.LoggerV1
+}
+
+func NewAlipayHandler(aliSvc *alipay.PaymentService,
+	svc service.PaymentService,
+	l logger.LoggerV1) *AlipayHandler {
+	return &AlipayHandler{aliSvc: aliSvc, svc: svc, l: l}
+}
+
+func (h *AlipayHandler) RegisterRoutes(server *gin.Engine) {
+	if h.aliSvc == nil {
+		// 没有配置支付宝
+		return
+	}
+	server.POST("/pay/alipay/callback", h.HandleNotify)
+}
+
+// HandleNotify 支付宝的支付和退款都是通过这个异步通知过来的，
+// 处理成功之后要返回 success，不然支付宝会一直重试
+func (h *AlipayHandler) HandleNotify(ctx *gin.Context) {
+	err := ctx.Request.ParseForm()
+	if err != nil {
+		ctx.String(http.StatusBadRequest, "参数解析失败")
+		return
+	}
+	pmt, refund, err := h.aliSvc.DecodeNotification(ctx.Request.Form)
+	if err != nil {
+		// 验签失败，绝大概率是黑客在尝试攻击你
+		ctx.String(http.StatusBadRequest, "参数解析失败")
+		h.l.Error("解析支付宝回调失败", logger.Error(err))
+		return
+	}
+	if refund != nil {
+		err = h.svc.HandleRefundNotify(ctx, *refund)
+		if err != nil {
+			ctx.String(http.StatusInternalServerError, "系统异常")
+			h.l.Error("处理支付宝退款回调失败", logger.Error(err),
+				logger.String("refund_no", refund.RefundNO))
+			return
+		}
+		ctx.String(http.StatusOK, "success")
+		return
+	}
+	err = h.svc.HandlePaymentNotify(ctx, pmt)
+	if err != nil {
+		ctx.String(http.StatusInternalServerError, "系统异常")
+		h.l.Error("处理支付宝支付回调失败", logger.Error(err),
+			logger.String("biz_trade_no", pmt.BizTradeNO))
+		return
+	}
+	ctx.String(http.StatusOK, "success")
+}
@@ -0,0 +1,135 @@
+package web
+
+import (
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/payment/domain"
+	"gitee.com/geekbang/basic-go/webook/payment/service"
+	"gitee.com/geekbang/basic-go/webook/pkg/ginx"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+var errUnknownChannel = errors.New("不认识的支付渠道")
+
+// ReconcileHandler 对账的接口，只给内部的运营后台用，注册在管理后台的 server 上面
+type ReconcileHandler struct {
+	svc service.ReconcileService
+	l   logger.LoggerV1
+}
+
+func NewReconcileHandler(svc service.ReconcileService, l logger.LoggerV1) *ReconcileHandler {
+	return &ReconcileHandler{svc: svc, l: l}
+}
+
+func (h *ReconcileHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/pay/reconcile")
+	// 参数都是 channel 和 date，比如说 channel=wechat&date=20240101
+	g.GET("/report", ginx.Wrap(h.Report))
+	// 重新下载账单对账
+	g.POST("/run", ginx.Wrap(h.Run))
+	// 下载不了账单的时候，可以手动上传，文件字段是 bill
+	g.POST("/import", ginx.Wrap(h.Import))
+}
+
+func (h *ReconcileHandler) Report(ctx *gin.Context) (ginx.Result, error) {
+	channel, date, err := h.parseParams(ctx)
+	if err != nil {
+		return ginx.Result{Code: 4, Msg: "channel 或者 date 参数错误"}, err
+	}
+	report, err := h.svc.GetReport(ctx, channel, date)
+	if errors.Is(err, service.ErrReportNotFound) {
+		return ginx.Result{Code: 4, Msg: "还没有对账"}, err
+	}
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Data: newReconcileReportVo(report)}, nil
+}
+
+func (h *ReconcileHandler) Run(ctx *gin.Context) (ginx.Result, error) {
+	channel, date, err := h.parseParams(ctx)
+	if err != nil {
+		return ginx.Result{Code: 4, Msg: "channel 或者 date 参数错误"}, err
+	}
+	report, err := h.svc.Reconcile(ctx, channel, date)
+	if errors.Is(err, service.ErrBillNotSupported) {
+		return ginx.Result{Code: 4, Msg: "这个渠道不支持对账"}, err
+	}
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Data: newReconcileReportVo(report)}, nil
+}
+
+func (h *ReconcileHandler) Import(ctx *gin.Context) (ginx.Result, error) {
+	channel, date, err := h.parseParams(ctx)
+	if err != nil {
+		return ginx.Result{Code: 4, Msg: "channel 或者 date 参数错误"}, err
+	}
+	fh, err := ctx.FormFile("bill")
+	if err != nil {
+		return ginx.Result{Code: 4, Msg: "没有上传账单"}, err
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	defer f.Close()
+	report, err := h.svc.Import(ctx, channel, date, f)
+	if errors.Is(err, service.ErrBillNotSupported) {
+		return ginx.Result{Code: 4, Msg: "这个渠道不支持对账"}, err
+	}
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Data: newReconcileReportVo(report)}, nil
+}
+
+func (h *ReconcileHandler) parseParams(ctx *gin.Context) (domain.PaymentChannel, time.Time, error) {
+	channel := domain.PaymentChannelFromString(ctx.Query("channel"))
+	if channel == domain.PaymentChannelUnknown {
+		return 0, time.Time{}, errUnknownChannel
+	}
+	date, err := time.ParseInLocation(domain.ReconcileDateLayout, ctx.Query("date"), time.Local)
+	return channel, date, err
+}
+
+type ReconcileReportVo struct {
+	Channel   string            `json:"channel"`
+	Date      string            `json:"date"`
+	BillCount int               `json:"billCount"`
+	Diffs     []ReconcileDiffVo `json:"diffs"`
+}
+
+type ReconcileDiffVo struct {
+	BizTradeNO  string `json:"bizTradeNO"`
+	TxnID       string `json:"txnID"`
+	Type        uint8  `json:"type"`
+	LocalAmt    int64  `json:"localAmt"`
+	BillAmt     int64  `json:"billAmt"`
+	LocalStatus uint8  `json:"localStatus"`
+	BillStatus  uint8  `json:"billStatus"`
+	Fixed       bool   `json:"fixed"`
+}
+
+func newReconcileReportVo(r domain.ReconcileReport) ReconcileReportVo {
+	return ReconcileReportVo{
+		Channel:   r.Channel.String(),
+		Date:      r.Date,
+		BillCount: r.BillCount,
+		Diffs: slice.Map(r.Diffs, func(idx int, src domain.ReconcileDiff) ReconcileDiffVo {
+			return ReconcileDiffVo{
+				BizTradeNO:  src.BizTradeNO,
+				TxnID:       src.TxnID,
+				Type:        src.Type.AsUint8(),
+				LocalAmt:    src.LocalAmt,
+				BillAmt:     src.BillAmt,
+				LocalStatus: src.LocalStatus.AsUint8(),
+				BillStatus:  src.BillStatus.AsUint8(),
+				Fixed:       src.Fixed,
+			}
+		}),
+	}
+}
@@ -0,0 +1,64 @@
+package web
+
+import (
+	"gitee.com/geekbang/basic-go/webook/payment/service"
+	"gitee.com/geekbang/basic-go/webook/payment/service/sandbox"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// SandboxHandler 沙箱的收银台和回调，只有打开了沙箱才会注册
+type SandboxHandler struct {
+	sandboxSvc *sandbox.PaymentService
+	svc        service.PaymentService
+	l          logger.LoggerV1
+}
+
+func NewSandboxHandler(sandboxSvc *sandbox.PaymentService,
+	svc service.PaymentService,
+	l logger.LoggerV1) *SandboxHandler {
+	return &SandboxHandler{sandboxSvc: sandboxSvc, svc: svc, l: l}
+}
+
+func (h *SandboxHandler) RegisterRoutes(server *gin.Engine) {
+	if h.sandboxSvc == nil {
+		return
+	}
+	// 模拟用户付钱，result 可以是 success, failed 和 timeout，默认是 success
+	server.GET("/pay/sandbox/pay", h.Pay)
+	server.POST("/pay/sandbox/callback", h.HandleNotify)
+}
+
+func (h *SandboxHandler) Pay(ctx *gin.Context) {
+	bizTradeNO := ctx.Query("biz_trade_no")
+	result := sandbox.Result(ctx.DefaultQuery("result", string(sandbox.ResultSuccess)))
+	err := h.sandboxSvc.Pay(ctx, bizTradeNO, result)
+	if err != nil {
+		ctx.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	ctx.String(http.StatusOK, string(result))
+}
+
+func (h *SandboxHandler) HandleNotify(ctx *gin.Context) {
+	err := ctx.Request.ParseForm()
+	if err != nil {
+		ctx.String(http.StatusBadRequest, "参数解析失败")
+		return
+	}
+	pmt, err := h.sandboxSvc.VerifyNotification(ctx.Request.PostForm)
+	if err != nil {
+		ctx.String(http.StatusBadRequest, "参数解析失败")
+		h.l.Error("解析沙箱回调失败", logger.Error(err))
+		return
+	}
+	err = h.svc.HandlePaymentNotify(ctx, pmt)
+	if err != nil {
+		ctx.String(http.StatusInternalServerError, "系统异常")
+		h.l.Error("处理沙箱回调失败", logger.Error(err),
+			logger.String("biz_trade_no", pmt.BizTradeNO))
+		return
+	}
+	ctx.String(http.StatusOK, "OK")
+}
@@ -1,6 +1,7 @@
 package web
 
 import (
+	"gitee.com/geekbang/basic-go/webook/payment/service"
 	"gitee.com/geekbang/basic-go/webook/payment/service/wechat"
 	"gitee.com/geekbang/basic-go/webook/pkg/logger"
 	"github.com/gin-gonic/gin"
@@ -13,14 +14,17 @@ type WechatHandler struct {
 	handler   *notify.Handler
 	l         logger.LoggerV1
 	nativeSvc *wechat.NativePaymentService
+	svc       service.PaymentService
 }
 
 func NewWechatHandler(handler *notify.Handler,
 	nativeSvc *wechat.NativePaymentService,
+	svc service.PaymentService,
 	l logger.LoggerV1) *WechatHandler {
 	return &WechatHandler{
 		handler:   handler,
 		nativeSvc: nativeSvc,
+		svc:       svc,
 		l:         l}
 }
 
@@ -29,6 +33,7 @@ func (h *WechatHandler) RegisterRoutes(server *gin.Engine) {
 		context.String(http.StatusOK, "我进来了")
 	})
 	server.Any("/pay/callback", h.HandleNative)
+	server.Any("/pay/refund/callback", h.HandleRefund)
 }
 
 func (h *WechatHandler) HandleNative(ctx *gin.Context) {
@@ -42,14 +47,45 @@ func (h *WechatHandler) HandleNative(ctx *gin.Context) {
 		// 绝大概率是黑客在尝试攻击你
 		return
 	}
+	pmt, err := h.nativeSvc.ToPayment(transaction)
+	if err != nil {
+		ctx.String(http.StatusBadRequest, "参数解析失败")
+		h.l.Error("微信支付回调的状态不对", logger.Error(err))
+		return
+	}
 	// 发送到 Kafka
-	err = h.nativeSvc.HandleCallback(ctx, transaction)
+	err = h.svc.HandlePaymentNotify(ctx, pmt)
 	if err != nil {
 		// 我在这里立刻触发对账
 		ctx.String(http.StatusInternalServerError, "系统异常")
 		// 说明你处理回到失败了
 		h.l.Error("处理微信支付回调失败", logger.Error(err),
-			logger.String("biz_trade_no", *transaction.OutTradeNo))
+			logger.String("biz_trade_no", pmt.BizTradeNO))
+		return
+	}
+	ctx.String(http.StatusOK, "OK")
+}
+
+func (h *WechatHandler) HandleRefund(ctx *gin.Context) {
+	notify := new(wechat.RefundNotify)
+	_, err := h.handler.ParseNotifyRequest(ctx, ctx.Request, notify)
+	if err != nil {
+		ctx.String(http.StatusBadRequest, "参数解析失败")
+		h.l.Error("解析微信退款回调失败", logger.Error(err))
+		return
+	}
+	r, err := h.nativeSvc.ToRefund(notify)
+	if err != nil {
+		ctx.String(http.StatusBadRequest, "参数解析失败")
+		h.l.Error("微信退款回调的状态不对", logger.Error(err))
+		return
+	}
+	err = h.svc.HandleRefundNotify(ctx, r)
+	if err != nil {
+		// 返回错误，微信会重新通知
+		ctx.String(http.StatusInternalServerError, "系统异常")
+		h.l.Error("处理微信退款回调失败", logger.Error(err),
+			logger.String("refund_no", r.RefundNO))
 		return
 	}
 	ctx.String(http.StatusOK, "OK")
@@ -5,8 +5,10 @@ package main
 import (
 	"gitee.com/geekbang/basic-go/webook/payment/grpc"
 	"gitee.com/geekbang/basic-go/webook/payment/ioc"
+	"gitee.com/geekbang/basic-go/webook/payment/job"
 	"gitee.com/geekbang/basic-go/webook/payment/repository"
 	"gitee.com/geekbang/basic-go/webook/payment/repository/dao"
+	"gitee.com/geekbang/basic-go/webook/payment/service"
 	"gitee.com/geekbang/basic-go/webook/payment/web"
 	"gitee.com/geekbang/basic-go/webook/pkg/wego"
 	"github.com/google/wire"
@@ -16,19 +18,37 @@ func InitApp() *wego.App {
 	wire.Build(
 		ioc.InitEtcdClient,
 		ioc.InitKafka,
-		ioc.InitProducer,
+		ioc.InitOutbox,
 		ioc.InitWechatClient,
 		dao.NewPaymentGORMDAO,
+		dao.NewRefundGORMDAO,
+		dao.NewReconcileGORMDAO,
 		ioc.InitDB,
 		repository.NewPaymentRepository,
+		repository.NewRefundRepository,
+		repository.NewReconcileRepository,
 		grpc.NewWechatServiceServer,
 		ioc.InitWechatNativeService,
+		ioc.InitAlipayService,
+		ioc.InitSandboxService,
+		ioc.InitProviders,
+		service.NewPaymentService,
+		service.NewReconcileService,
+		job.NewReconcileJob,
+		job.NewSyncRefundJob,
+		ioc.InitJobs,
+		job.NewClosePaymentJob,
+		ioc.InitDaemons,
 		ioc.InitWechatConfig,
 		ioc.InitWechatNotifyHandler,
 		ioc.InitGRPCServer,
 		web.NewWechatHandler,
+		web.NewAlipayHandler,
+		web.NewSandboxHandler,
+		web.NewReconcileHandler,
 		ioc.InitGinServer,
+		ioc.InitAdminServer,
 		ioc.InitLogger,
-		wire.Struct(new(wego.App), "WebServer", "GRPCServer"))
+		wire.Struct(new(wego.App), "WebServer", "AdminServer", "GRPCServer", "Cron", "Daemons"))
 	return new(wego.App)
 }
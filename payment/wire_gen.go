@@ -9,8 +9,10 @@ package main
 import (
 	"gitee.com/geekbang/basic-go/webook/payment/grpc"
 	"gitee.com/geekbang/basic-go/webook/payment/ioc"
+	"gitee.com/geekbang/basic-go/webook/payment/job"
 	"gitee.com/geekbang/basic-go/webook/payment/repository"
 	"gitee.com/geekbang/basic-go/webook/payment/repository/dao"
+	"gitee.com/geekbang/basic-go/webook/payment/service"
 	"gitee.com/geekbang/basic-go/webook/payment/web"
 	"gitee.com/geekbang/basic-go/webook/pkg/wego"
 )
@@ -21,21 +23,42 @@ func InitApp() *wego.App {
 	wechatConfig := ioc.InitWechatConfig()
 	handler := ioc.InitWechatNotifyHandler(wechatConfig)
 	client := ioc.InitWechatClient(wechatConfig)
+	loggerV1 := ioc.InitLogger()
+	nativePaymentService := ioc.InitWechatNativeService(client, loggerV1, wechatConfig)
 	db := ioc.InitDB()
 	paymentDAO := dao.NewPaymentGORMDAO(db)
 	paymentRepository := repository.NewPaymentRepository(paymentDAO)
-	loggerV1 := ioc.InitLogger()
+	refundDAO := dao.NewRefundGORMDAO(db)
+	refundRepository := repository.NewRefundRepository(refundDAO)
+	paymentService := ioc.InitAlipayService(loggerV1)
+	sandboxPaymentService := ioc.InitSandboxService(loggerV1)
+	v := ioc.InitProviders(nativePaymentService, paymentService, sandboxPaymentService)
+	servicePaymentService := service.NewPaymentService(paymentRepository, refundRepository, v, loggerV1)
+	wechatHandler := web.NewWechatHandler(handler, nativePaymentService, servicePaymentService, loggerV1)
+	alipayHandler := web.NewAlipayHandler(paymentService, servicePaymentService, loggerV1)
+	sandboxHandler := web.NewSandboxHandler(sandboxPaymentService, servicePaymentService, loggerV1)
+	reconcileDAO := dao.NewReconcileGORMDAO(db)
+	reconcileRepository := repository.NewReconcileRepository(reconcileDAO)
+	reconcileService := service.NewReconcileService(servicePaymentService, paymentRepository, reconcileRepository, v, loggerV1)
+	reconcileHandler := web.NewReconcileHandler(reconcileService, loggerV1)
 	saramaClient := ioc.InitKafka()
-	producer := ioc.InitProducer(saramaClient)
-	nativePaymentService := ioc.InitWechatNativeService(client, paymentRepository, loggerV1, producer, wechatConfig)
-	wechatHandler := web.NewWechatHandler(handler, nativePaymentService, loggerV1)
-	server := ioc.InitGinServer(wechatHandler)
-	wechatServiceServer := grpc.NewWechatServiceServer(nativePaymentService)
+	outboxOutbox := ioc.InitOutbox(db, saramaClient, loggerV1)
+	server := ioc.InitGinServer(wechatHandler, alipayHandler, sandboxHandler)
+	adminServer := ioc.InitAdminServer(reconcileHandler, outboxOutbox, loggerV1)
+	wechatServiceServer := grpc.NewWechatServiceServer(servicePaymentService)
 	clientv3Client := ioc.InitEtcdClient()
 	grpcxServer := ioc.InitGRPCServer(wechatServiceServer, clientv3Client, loggerV1)
+	reconcileJob := job.NewReconcileJob(reconcileService, loggerV1)
+	syncRefundJob := job.NewSyncRefundJob(servicePaymentService, loggerV1)
+	cron := ioc.InitJobs(loggerV1, reconcileJob, syncRefundJob)
+	closePaymentJob := job.NewClosePaymentJob(servicePaymentService, loggerV1)
+	v2 := ioc.InitDaemons(outboxOutbox, closePaymentJob)
 	app := &wego.App{
-		WebServer:  server,
-		GRPCServer: grpcxServer,
+		WebServer:   server,
+		AdminServer: adminServer,
+		GRPCServer:  grpcxServer,
+		Cron:        cron,
+		Daemons:     v2,
 	}
 	return app
 }
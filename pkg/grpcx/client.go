@@ -0,0 +1,18 @@
+package grpcx
+
+import (
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/naming/resolver"
+	"google.golang.org/grpc"
+)
+
+// NewEtcdClientConn 通过 etcd 发现 Server 注册的服务，name 和 Server.Name 一致
+func NewEtcdClientConn(client *clientv3.Client, name string,
+	opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	bd, err := resolver.NewBuilder(client)
+	if err != nil {
+		return nil, err
+	}
+	opts = append([]grpc.DialOption{grpc.WithResolvers(bd)}, opts...)
+	return grpc.Dial("etcd:///service/"+name, opts...)
+}
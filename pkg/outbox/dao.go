@@ -0,0 +1,240 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// InitTables 使用 outbox 的服务启动的时候要建表
+func InitTables(db *gorm.DB) error {
+	return db.AutoMigrate(&OutboxMsg{}, &OutboxLease{})
+}
+
+// Save 必须传入业务的事务，这样消息和业务数据要么一起提交，要么一起回滚
+func Save(tx *gorm.DB, msgs ...Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	now := time.Now().UnixMilli()
+	entities := make([]OutboxMsg, 0, len(msgs))
+	for _, msg := range msgs {
+		val, err := json.Marshal(msg.Value)
+		if err != nil {
+			return err
+		}
+		entities = append(entities, OutboxMsg{
+			Topic:    msg.Topic,
+			Key:      msg.Key,
+			Value:    val,
+			Status:   MsgStatusPending.AsUint8(),
+			NextTime: now,
+			Ctime:    now,
+			Utime:    now,
+		})
+	}
+	return tx.Create(&entities).Error
+}
+
+// errLeaseLost 转发权已经过期或者被别的实例抢走了
+var errLeaseLost = errors.New("outbox 转发权已经丢了")
+
+type dao struct {
+	db *gorm.DB
+}
+
+// lease 抢到的转发权。token 是栅栏，写消息状态的时候带上，
+// 转发权过期之后，旧的持有者就写不进去了
+type lease struct {
+	token  int64
+	expire time.Time
+}
+
+// acquire 抢占或者续约转发权，换了持有者 token 就加一
+func (d *dao) acquire(ctx context.Context, name, owner string, ttl time.Duration) (lease, bool, error) {
+	// 先算过期时间，本地认为的过期时间只会比数据库里面的早
+	now := time.Now()
+	expire := now.Add(ttl)
+	err := d.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&OutboxLease{Name: name, Ctime: now.UnixMilli(), Utime: now.UnixMilli()}).Error
+	if err != nil {
+		return lease{}, false, err
+	}
+	// MySQL 从左往右赋值，token 要在 owner 前面算
+	res := d.db.WithContext(ctx).Exec("UPDATE `outbox_leases` "+
+		"SET `token` = IF(`owner` = ?, `token`, `token` + 1), `owner` = ?, `expire` = ?, `utime` = ? "+
+		"WHERE `name` = ? AND (`owner` = ? OR `expire` < ?)",
+		owner, owner, expire.UnixMilli(), now.UnixMilli(), name, owner, now.UnixMilli())
+	if res.Error != nil || res.RowsAffected == 0 {
+		return lease{}, false, res.Error
+	}
+	var l OutboxLease
+	err = d.db.WithContext(ctx).Where("name = ?", name).First(&l).Error
+	if err != nil {
+		return lease{}, false, err
+	}
+	if l.Owner != owner {
+		// 刚抢到就过期了，被别人拿走了
+		return lease{}, false, nil
+	}
+	return lease{token: l.Token, expire: expire}, true, nil
+}
+
+func (d *dao) release(ctx context.Context, name, owner string) error {
+	return d.db.WithContext(ctx).Model(&OutboxLease{}).
+		Where("name = ? AND owner = ?", name, owner).
+		Updates(map[string]any{
+			"expire": 0,
+			"utime":  time.Now().UnixMilli(),
+		}).Error
+}
+
+// findReady 找出可以发送的消息。
+// 同一个 Key 前面还有没发出去的消息的话，后面的要等着
+func (d *dao) findReady(ctx context.Context, limit int) ([]OutboxMsg, error) {
+	now := time.Now().UnixMilli()
+	earlier := d.db.Table("outbox_msgs AS o").Select("1").
+		Where("o.msg_key = outbox_msgs.msg_key AND o.status = ? AND o.id < outbox_msgs.id",
+			MsgStatusPending.AsUint8())
+	var res []OutboxMsg
+	err := d.db.WithContext(ctx).
+		Where("status = ? AND next_time <= ?", MsgStatusPending.AsUint8(), now).
+		Where("msg_key = '' OR NOT EXISTS (?)", earlier).
+		Order("id").Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+// fenced 只有还持有转发权的时候才能更新消息
+func (d *dao) fenced(ctx context.Context, name string, l lease, id int64) *gorm.DB {
+	held := d.db.Model(&OutboxLease{}).Select("1").
+		Where("name = ? AND token = ? AND expire >= ?", name, l.token, time.Now().UnixMilli())
+	return d.db.WithContext(ctx).Model(&OutboxMsg{}).
+		Where("id = ? AND EXISTS (?)", id, held)
+}
+
+func (d *dao) markSent(ctx context.Context, name string, l lease, id int64) error {
+	res := d.fenced(ctx, name, l, id).
+		Updates(map[string]any{
+			"status": MsgStatusSent.AsUint8(),
+			"utime":  time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errLeaseLost
+	}
+	return nil
+}
+
+// markRetry 下一次 nextTime 之后才重试，failed 为 true 就不再重试了
+func (d *dao) markRetry(ctx context.Context, name string, l lease,
+	id int64, lastErr string, nextTime int64, failed bool) error {
+	status := MsgStatusPending
+	if failed {
+		status = MsgStatusFailed
+	}
+	if len(lastErr) > 1024 {
+		lastErr = lastErr[:1024]
+	}
+	res := d.fenced(ctx, name, l, id).
+		Updates(map[string]any{
+			"status":    status.AsUint8(),
+			"retries":   gorm.Expr("retries + 1"),
+			"last_err":  lastErr,
+			"next_time": nextTime,
+			"utime":     time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errLeaseLost
+	}
+	return nil
+}
+
+// deleteSentBefore 一次删一批，避免长时间锁表
+func (d *dao) deleteSentBefore(ctx context.Context, t int64, limit int) (int, error) {
+	var ids []int64
+	err := d.db.WithContext(ctx).Model(&OutboxMsg{}).
+		Where("status = ? AND utime < ?", MsgStatusSent.AsUint8(), t).
+		Limit(limit).Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	err = d.db.WithContext(ctx).Where("id IN ?", ids).Delete(&OutboxMsg{}).Error
+	return len(ids), err
+}
+
+func (d *dao) findFailed(ctx context.Context, offset, limit int) ([]OutboxMsg, error) {
+	var res []OutboxMsg
+	err := d.db.WithContext(ctx).
+		Where("status = ?", MsgStatusFailed.AsUint8()).
+		Order("id").Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+// retry 只有失败的消息才能重新发送，重试次数清零
+func (d *dao) retry(ctx context.Context, id int64) error {
+	now := time.Now().UnixMilli()
+	res := d.db.WithContext(ctx).Model(&OutboxMsg{}).
+		Where("id = ? AND status = ?", id, MsgStatusFailed.AsUint8()).
+		Updates(map[string]any{
+			"status":    MsgStatusPending.AsUint8(),
+			"retries":   0,
+			"next_time": now,
+			"utime":     now,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrMsgNotFailed
+	}
+	return nil
+}
+
+func (d *dao) discard(ctx context.Context, id int64) error {
+	res := d.db.WithContext(ctx).
+		Where("id = ? AND status = ?", id, MsgStatusFailed.AsUint8()).
+		Delete(&OutboxMsg{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrMsgNotFailed
+	}
+	return nil
+}
+
+type OutboxMsg struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	Topic string `gorm:"type:varchar(256)"`
+	// Key 是 MySQL 的关键字，所以换个列名
+	Key      string `gorm:"column:msg_key;type:varchar(256);index:idx_key_status"`
+	Value    []byte `gorm:"type:blob"`
+	Status   uint8  `gorm:"index:idx_status_next_time;index:idx_key_status"`
+	Retries  int
+	LastErr  string `gorm:"type:varchar(1024)"`
+	NextTime int64  `gorm:"index:idx_status_next_time"`
+	Ctime    int64
+	Utime    int64
+}
+
+// OutboxLease 转发权，同一个 Name 只有 Owner 可以转发
+type OutboxLease struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	Name  string `gorm:"type:varchar(128);unique"`
+	Owner string `gorm:"type:varchar(128)"`
+	// Token 每换一次持有者就加一
+	Token  int64
+	Expire int64
+	Ctime  int64
+	Utime  int64
+}
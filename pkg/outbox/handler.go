@@ -0,0 +1,86 @@
+package outbox
+
+import (
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/pkg/ginx"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 管理失败消息的接口，使用方自己决定挂在哪个路径下面，以及怎么鉴权。
+// 可以重新发送任意一条消息，千万不要挂在对外的 server 上面
+type Handler struct {
+	o *Outbox
+}
+
+func NewHandler(o *Outbox) *Handler {
+	return &Handler{o: o}
+}
+
+func (h *Handler) RegisterRoutes(g gin.IRouter) {
+	g.POST("/failed", ginx.WrapBody(h.ListFailed))
+	g.POST("/retry", ginx.WrapBody(h.Retry))
+	g.POST("/discard", ginx.WrapBody(h.Discard))
+}
+
+type ListFailedReq struct {
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+type MsgReq struct {
+	Id int64 `json:"id"`
+}
+
+type MsgVo struct {
+	Id      int64  `json:"id"`
+	Topic   string `json:"topic"`
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Retries int    `json:"retries"`
+	LastErr string `json:"lastErr"`
+	Ctime   int64  `json:"ctime"`
+	Utime   int64  `json:"utime"`
+}
+
+func (h *Handler) ListFailed(ctx *gin.Context, req ListFailedReq) (ginx.Result, error) {
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 100
+	}
+	msgs, err := h.o.ListFailed(ctx, req.Offset, req.Limit)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{
+		Data: slice.Map(msgs, func(idx int, src Msg) MsgVo {
+			return MsgVo{
+				Id:      src.Id,
+				Topic:   src.Topic,
+				Key:     src.Key,
+				Value:   string(src.Value),
+				Retries: src.Retries,
+				LastErr: src.LastErr,
+				Ctime:   src.Ctime.UnixMilli(),
+				Utime:   src.Utime.UnixMilli(),
+			}
+		}),
+	}, nil
+}
+
+func (h *Handler) Retry(ctx *gin.Context, req MsgReq) (ginx.Result, error) {
+	return h.result(h.o.Retry(ctx, req.Id))
+}
+
+func (h *Handler) Discard(ctx *gin.Context, req MsgReq) (ginx.Result, error) {
+	return h.result(h.o.Discard(ctx, req.Id))
+}
+
+func (h *Handler) result(err error) (ginx.Result, error) {
+	if errors.Is(err, ErrMsgNotFailed) {
+		return ginx.Result{Code: 4, Msg: "消息不存在或者不是失败状态"}, err
+	}
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
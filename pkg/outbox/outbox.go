@@ -0,0 +1,216 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/IBM/sarama"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"os"
+	"time"
+)
+
+var ErrMsgNotFailed = errors.New("消息不存在或者不是失败状态")
+
+// Outbox 把表里面的消息转发到 Kafka，也提供管理失败消息的方法
+type Outbox struct {
+	dao      *dao
+	producer sarama.SyncProducer
+	l        logger.LoggerV1
+	cfg      Config
+	// owner 当前实例的标识
+	owner string
+}
+
+func NewOutbox(db *gorm.DB, producer sarama.SyncProducer, l logger.LoggerV1, cfg Config) *Outbox {
+	host, _ := os.Hostname()
+	return &Outbox{
+		dao:      &dao{db: db},
+		producer: producer,
+		l:        l,
+		cfg:      cfg.withDefault(),
+		owner:    fmt.Sprintf("%s-%s", host, uuid.New().String()),
+	}
+}
+
+// Start 阻塞直到 ctx 被取消。
+// 多个实例都可以启动，但是只有抢到转发权的那个会真的转发
+func (o *Outbox) Start(ctx context.Context) error {
+	lastClean := time.Time{}
+	for {
+		busy := false
+		l, held, err := o.dao.acquire(ctx, o.cfg.Name, o.owner, o.cfg.LeaseTTL)
+		if err != nil {
+			o.l.Error("抢占 outbox 转发权失败", logger.Error(err),
+				logger.String("name", o.cfg.Name))
+		}
+		if held {
+			busy, err = o.relay(ctx, &l)
+			if err != nil {
+				o.l.Error("转发 outbox 消息失败", logger.Error(err),
+					logger.String("name", o.cfg.Name))
+			}
+			if time.Since(lastClean) > time.Hour {
+				o.clean(ctx)
+				lastClean = time.Now()
+			}
+		}
+		if busy {
+			// 还有消息，马上处理下一批
+			if ctx.Err() != nil {
+				return o.stop()
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return o.stop()
+		case <-time.After(o.cfg.Interval):
+		}
+	}
+}
+
+// stop 主动让出转发权，别的实例不用等租约过期
+func (o *Outbox) stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return o.dao.release(ctx, o.cfg.Name, o.owner)
+}
+
+// relay 转发一批消息，返回是否还有没处理完的。
+// 发送之前转发权剩下的时间不多了就续约，续不上就停下来，免得两个实例同时转发
+func (o *Outbox) relay(ctx context.Context, l *lease) (bool, error) {
+	msgs, err := o.dao.findReady(ctx, o.cfg.BatchSize)
+	if err != nil {
+		return false, err
+	}
+	// 同一个 Key 前面的发送失败了，这一批里面后面的就不能发了
+	blocked := make(map[string]struct{})
+	for _, msg := range msgs {
+		if _, ok := blocked[msg.Key]; ok && msg.Key != "" {
+			continue
+		}
+		err = o.renew(ctx, l)
+		if err != nil {
+			return false, err
+		}
+		err = o.send(msg)
+		if err == nil {
+			err = o.dao.markSent(ctx, o.cfg.Name, *l, msg.Id)
+			if errors.Is(err, errLeaseLost) {
+				return false, err
+			}
+			if err != nil {
+				// 下一次还会再发一次，消费者要幂等
+				o.l.Error("标记 outbox 消息发送成功失败", logger.Error(err),
+					logger.Int64("id", msg.Id))
+			}
+			continue
+		}
+		blocked[msg.Key] = struct{}{}
+		failed := msg.Retries+1 >= o.cfg.MaxRetries
+		next := time.Now().Add(o.backoff(msg.Retries)).UnixMilli()
+		err1 := o.dao.markRetry(ctx, o.cfg.Name, *l, msg.Id, err.Error(), next, failed)
+		if errors.Is(err1, errLeaseLost) {
+			return false, err1
+		}
+		if err1 != nil {
+			o.l.Error("记录 outbox 消息重试失败", logger.Error(err1),
+				logger.Int64("id", msg.Id))
+		}
+		if failed {
+			o.l.Error("outbox 消息重试次数用完了，需要人手工处理", logger.Error(err),
+				logger.Int64("id", msg.Id),
+				logger.String("topic", msg.Topic))
+		}
+	}
+	return len(msgs) == o.cfg.BatchSize, nil
+}
+
+// renew 转发权剩下不到一半的时候续约
+func (o *Outbox) renew(ctx context.Context, l *lease) error {
+	if time.Until(l.expire) > o.cfg.LeaseTTL/2 {
+		return nil
+	}
+	res, held, err := o.dao.acquire(ctx, o.cfg.Name, o.owner, o.cfg.LeaseTTL)
+	if err != nil {
+		return err
+	}
+	if !held || res.token != l.token {
+		// 中间被别的实例拿走过，这一批可能已经被它发过了
+		return errLeaseLost
+	}
+	*l = res
+	return nil
+}
+
+func (o *Outbox) send(msg OutboxMsg) error {
+	pm := &sarama.ProducerMessage{
+		Topic: msg.Topic,
+		Value: sarama.ByteEncoder(msg.Value),
+	}
+	if msg.Key != "" {
+		pm.Key = sarama.StringEncoder(msg.Key)
+	}
+	_, _, err := o.producer.SendMessage(pm)
+	return err
+}
+
+// backoff 指数退避，retries 是已经重试过的次数
+func (o *Outbox) backoff(retries int) time.Duration {
+	res := o.cfg.Backoff
+	for i := 0; i < retries && res < o.cfg.MaxBackoff; i++ {
+		res = res * 2
+	}
+	if res > o.cfg.MaxBackoff {
+		return o.cfg.MaxBackoff
+	}
+	return res
+}
+
+// clean 删掉过了保留期的已发送消息
+func (o *Outbox) clean(ctx context.Context) {
+	t := time.Now().Add(-o.cfg.Retention).UnixMilli()
+	for ctx.Err() == nil {
+		n, err := o.dao.deleteSentBefore(ctx, t, 1000)
+		if err != nil {
+			o.l.Error("清理 outbox 消息失败", logger.Error(err))
+			return
+		}
+		if n < 1000 {
+			return
+		}
+	}
+}
+
+// ListFailed 重试次数用完的消息
+func (o *Outbox) ListFailed(ctx context.Context, offset, limit int) ([]Msg, error) {
+	msgs, err := o.dao.findFailed(ctx, offset, limit)
+	return slice.Map(msgs, func(idx int, src OutboxMsg) Msg {
+		return Msg{
+			Id:      src.Id,
+			Topic:   src.Topic,
+			Key:     src.Key,
+			Value:   src.Value,
+			Status:  MsgStatus(src.Status),
+			Retries: src.Retries,
+			LastErr: src.LastErr,
+			Ctime:   time.UnixMilli(src.Ctime),
+			Utime:   time.UnixMilli(src.Utime),
+		}
+	}), err
+}
+
+// Retry 让失败的消息重新进入发送队列。
+// 注意它可能已经落后于同一个 Key 后面的消息了
+func (o *Outbox) Retry(ctx context.Context, id int64) error {
+	return o.dao.retry(ctx, id)
+}
+
+// Discard 确认失败的消息不需要再发送了
+func (o *Outbox) Discard(ctx context.Context, id int64) error {
+	return o.dao.discard(ctx, id)
+}
@@ -0,0 +1,364 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+	"time"
+)
+
+type testEvent struct {
+	Id int64
+}
+
+func (testEvent) Topic() string {
+	return "test_events"
+}
+
+func TestSave(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+		msgs []Message
+
+		wantErr error
+	}{
+		{
+			name: "没有消息",
+			mock: func(mock sqlmock.Sqlmock) {},
+		},
+		{
+			name: "批量插入",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO `outbox_msgs` .*").
+					WithArgs("test_events", "key-1", []byte(`{"Id":1}`), MsgStatusPending.AsUint8(),
+						0, "", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+						"test_events", "", []byte(`{"Id":2}`), MsgStatusPending.AsUint8(),
+						0, "", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 2))
+			},
+			msgs: []Message{
+				NewMessage("key-1", testEvent{Id: 1}),
+				NewMessage("", testEvent{Id: 2}),
+			},
+		},
+		{
+			name: "数据库错误",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO `outbox_msgs` .*").
+					WillReturnError(errors.New("mock db error"))
+			},
+			msgs:    []Message{NewMessage("key-1", testEvent{Id: 1})},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			tc.mock(mock)
+			err = Save(openMockDB(t, sqlDB), tc.msgs...)
+			assert.Equal(t, tc.wantErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestOutbox_backoff(t *testing.T) {
+	o := &Outbox{cfg: Config{
+		Backoff:    time.Second,
+		MaxBackoff: time.Second * 10,
+	}.withDefault()}
+	testCases := []struct {
+		retries int
+		want    time.Duration
+	}{
+		{retries: 0, want: time.Second},
+		{retries: 1, want: time.Second * 2},
+		{retries: 3, want: time.Second * 8},
+		{retries: 4, want: time.Second * 10},
+		{retries: 100, want: time.Second * 10},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, o.backoff(tc.retries))
+	}
+}
+
+func TestDao_acquire(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+
+		wantHeld  bool
+		wantToken int64
+		wantErr   error
+	}{
+		{
+			name: "抢到了",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO `outbox_leases` .*").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `outbox_leases` "+
+					"SET `token` = IF(`owner` = ?, `token`, `token` + 1), `owner` = ?, `expire` = ?, `utime` = ? "+
+					"WHERE `name` = ? AND (`owner` = ? OR `expire` < ?)")).
+					WithArgs("node-1", "node-1", sqlmock.AnyArg(), sqlmock.AnyArg(),
+						"payment", "node-1", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT \\* FROM `outbox_leases` WHERE name = \\?.*").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "owner", "token"}).
+						AddRow(1, "payment", "node-1", 3))
+			},
+			wantHeld:  true,
+			wantToken: 3,
+		},
+		{
+			name: "别人还持有",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO `outbox_leases` .*").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE `outbox_leases` .*").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "抢到之后马上被别人拿走了",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO `outbox_leases` .*").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE `outbox_leases` .*").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT \\* FROM `outbox_leases` .*").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "owner", "token"}).
+						AddRow(1, "payment", "node-2", 4))
+			},
+		},
+		{
+			name: "数据库错误",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO `outbox_leases` .*").
+					WillReturnError(errors.New("mock db error"))
+			},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			tc.mock(mock)
+			d := &dao{db: openMockDB(t, sqlDB)}
+			now := time.Now()
+			l, held, err := d.acquire(context.Background(), "payment", "node-1", time.Second*30)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantHeld, held)
+			assert.Equal(t, tc.wantToken, l.token)
+			if held {
+				assert.True(t, l.expire.After(now.Add(time.Second*29)))
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDao_release(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `outbox_leases` SET `expire`=?,`utime`=? "+
+		"WHERE name = ? AND owner = ?")).
+		WithArgs(0, sqlmock.AnyArg(), "payment", "node-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	d := &dao{db: openMockDB(t, sqlDB)}
+	err = d.release(context.Background(), "payment", "node-1")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDao_findReady(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	// 同一个 Key 前面还有没发出去的，后面的就不能查出来
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `outbox_msgs` "+
+		"WHERE (status = ? AND next_time <= ?) "+
+		"AND (msg_key = '' OR NOT EXISTS (SELECT 1 FROM outbox_msgs AS o "+
+		"WHERE o.msg_key = outbox_msgs.msg_key AND o.status = ? AND o.id < outbox_msgs.id)) "+
+		"ORDER BY id LIMIT ?")).
+		WithArgs(MsgStatusPending.AsUint8(), sqlmock.AnyArg(), MsgStatusPending.AsUint8(), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "topic", "msg_key"}).
+			AddRow(1, "test_events", "key-1").
+			AddRow(3, "test_events", ""))
+	d := &dao{db: openMockDB(t, sqlDB)}
+	msgs, err := d.findReady(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []OutboxMsg{
+		{Id: 1, Topic: "test_events", Key: "key-1"},
+		{Id: 3, Topic: "test_events"},
+	}, msgs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutbox_relay(t *testing.T) {
+	rows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "topic", "msg_key", "value", "retries"}).
+			AddRow(1, "test_events", "key-1", []byte(`{"Id":1}`), 0).
+			AddRow(2, "test_events", "key-1", []byte(`{"Id":2}`), 0).
+			AddRow(3, "test_events", "key-2", []byte(`{"Id":3}`), 9)
+	}
+	testCases := []struct {
+		name     string
+		mock     func(mock sqlmock.Sqlmock)
+		producer *fakeProducer
+		lease    lease
+
+		wantBusy  bool
+		wantErr   error
+		wantSent  []int64
+		wantToken int64
+	}{
+		{
+			name: "全部发送成功",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `outbox_msgs` .*").WillReturnRows(rows())
+				for i := 0; i < 3; i++ {
+					mock.ExpectExec("UPDATE `outbox_msgs` SET `status`=\\?,`utime`=\\? "+
+						"WHERE id = \\? AND EXISTS \\(SELECT 1 FROM `outbox_leases` "+
+						"WHERE name = \\? AND token = \\? AND expire >= \\?\\)").
+						WithArgs(MsgStatusSent.AsUint8(), sqlmock.AnyArg(), i+1, "test", 1, sqlmock.AnyArg()).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+			},
+			producer:  &fakeProducer{},
+			lease:     lease{token: 1, expire: time.Now().Add(time.Minute)},
+			wantSent:  []int64{1, 2, 3},
+			wantToken: 1,
+		},
+		{
+			name: "发送失败，同一个 Key 后面的不发，重试次数用完的标记为失败",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `outbox_msgs` .*").WillReturnRows(rows())
+				mock.ExpectExec("UPDATE `outbox_msgs` SET `last_err`=\\?,`next_time`=\\?,`retries`=retries \\+ 1,`status`=\\?.*").
+					WithArgs("mock send error", sqlmock.AnyArg(), MsgStatusPending.AsUint8(), sqlmock.AnyArg(),
+						1, "test", 1, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE `outbox_msgs` SET `last_err`=\\?,`next_time`=\\?,`retries`=retries \\+ 1,`status`=\\?.*").
+					WithArgs("mock send error", sqlmock.AnyArg(), MsgStatusFailed.AsUint8(), sqlmock.AnyArg(),
+						3, "test", 1, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			producer:  &fakeProducer{err: errors.New("mock send error")},
+			lease:     lease{token: 1, expire: time.Now().Add(time.Minute)},
+			wantToken: 1,
+		},
+		{
+			name: "快过期了，续约之后接着发",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `outbox_msgs` .*").WillReturnRows(rows())
+				mock.ExpectExec("INSERT INTO `outbox_leases` .*").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE `outbox_leases` .*").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT \\* FROM `outbox_leases` .*").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "owner", "token"}).
+						AddRow(1, "test", "node-1", 1))
+				for i := 0; i < 3; i++ {
+					mock.ExpectExec("UPDATE `outbox_msgs` .*").
+						WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+			},
+			producer:  &fakeProducer{},
+			lease:     lease{token: 1, expire: time.Now().Add(time.Second)},
+			wantSent:  []int64{1, 2, 3},
+			wantToken: 1,
+		},
+		{
+			name: "续约的时候发现被别人拿走过，一条都不发",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `outbox_msgs` .*").WillReturnRows(rows())
+				mock.ExpectExec("INSERT INTO `outbox_leases` .*").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE `outbox_leases` .*").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT \\* FROM `outbox_leases` .*").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "owner", "token"}).
+						AddRow(1, "test", "node-1", 3))
+			},
+			producer:  &fakeProducer{},
+			lease:     lease{token: 1, expire: time.Now().Add(-time.Second)},
+			wantErr:   errLeaseLost,
+			wantToken: 1,
+		},
+		{
+			name: "转发权已经丢了，标记不了就停下来",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `outbox_msgs` .*").WillReturnRows(rows())
+				mock.ExpectExec("UPDATE `outbox_msgs` .*").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			producer:  &fakeProducer{},
+			lease:     lease{token: 1, expire: time.Now().Add(time.Minute)},
+			wantErr:   errLeaseLost,
+			wantSent:  []int64{1},
+			wantToken: 1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			tc.mock(mock)
+			o := &Outbox{
+				dao:      &dao{db: openMockDB(t, sqlDB)},
+				producer: tc.producer,
+				l:        logger.NewNopLogger(),
+				cfg:      Config{Name: "test", LeaseTTL: time.Second * 30}.withDefault(),
+				owner:    "node-1",
+			}
+			l := tc.lease
+			busy, err := o.relay(context.Background(), &l)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantBusy, busy)
+			assert.Equal(t, tc.wantSent, tc.producer.sent)
+			assert.Equal(t, tc.wantToken, l.token)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func openMockDB(t *testing.T, sqlDB *sql.DB) *gorm.DB {
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+	return db
+}
+
+type fakeProducer struct {
+	sarama.SyncProducer
+	err error
+	// sent 发送成功的消息的 Id
+	sent []int64
+}
+
+func (f *fakeProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	if f.err != nil {
+		return 0, 0, f.err
+	}
+	val, _ := msg.Value.Encode()
+	var evt testEvent
+	_ = json.Unmarshal(val, &evt)
+	f.sent = append(f.sent, evt.Id)
+	return 0, 0, nil
+}
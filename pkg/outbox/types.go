@@ -0,0 +1,108 @@
+// Package outbox 通用的本地消息表。
+// 业务在自己的事务里面调用 Save 把消息和业务数据一起提交，
+// 后台的 Outbox 再把消息按顺序转发到 Kafka，失败了会重试。
+// 因为是至少一次，所以消费者一定要做到幂等
+package outbox
+
+import "time"
+
+// Event 自带 Topic 的事件
+type Event interface {
+	Topic() string
+}
+
+// Message 要发送的消息
+type Message struct {
+	Topic string
+	// Key 决定了 Kafka 的分区，Key 相同的消息严格按照写入的顺序发送。
+	// Key 为空的消息之间不保证顺序
+	Key string
+	// Value 会被序列化成 JSON
+	Value any
+}
+
+// NewMessage 用事件的 Topic
+func NewMessage(key string, evt Event) Message {
+	return Message{
+		Topic: evt.Topic(),
+		Key:   key,
+		Value: evt,
+	}
+}
+
+type Config struct {
+	// Name 同一个 Name 同一时刻只会有一个实例在转发，这样才能保证顺序
+	Name string `yaml:"name"`
+	// Interval 没有消息的时候，隔多久再查一次
+	Interval time.Duration `yaml:"interval"`
+	// BatchSize 一次最多转发多少条
+	BatchSize int `yaml:"batchSize"`
+	// MaxRetries 超过这个次数就标记为失败，需要人手工处理
+	MaxRetries int `yaml:"maxRetries"`
+	// Backoff 第一次重试的间隔，后面每次翻倍，最多 MaxBackoff
+	Backoff    time.Duration `yaml:"backoff"`
+	MaxBackoff time.Duration `yaml:"maxBackoff"`
+	// LeaseTTL 转发权的有效期，持有的实例挂了之后，过了这么久别的实例才能接手。
+	// 转发的过程中剩下不到一半就会续约，所以要比发送一条消息的超时时间的两倍长
+	LeaseTTL time.Duration `yaml:"leaseTTL"`
+	// Retention 发送成功的消息保留多久
+	Retention time.Duration `yaml:"retention"`
+}
+
+func (c Config) withDefault() Config {
+	if c.Name == "" {
+		c.Name = "default"
+	}
+	if c.Interval <= 0 {
+		c.Interval = time.Second
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 100
+	}
+	if c.MaxRetries <= 0 {
+		c.MaxRetries = 10
+	}
+	if c.Backoff <= 0 {
+		c.Backoff = time.Second
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = time.Minute * 5
+	}
+	if c.LeaseTTL <= 0 {
+		c.LeaseTTL = time.Second * 30
+	}
+	if c.Retention <= 0 {
+		c.Retention = time.Hour * 24 * 7
+	}
+	return c
+}
+
+// Msg 表里面的一条消息，管理失败消息的时候用
+type Msg struct {
+	Id      int64
+	Topic   string
+	Key     string
+	Value   []byte
+	Status  MsgStatus
+	Retries int
+	// LastErr 最后一次发送失败的原因
+	LastErr string
+	Ctime   time.Time
+	Utime   time.Time
+}
+
+type MsgStatus uint8
+
+func (s MsgStatus) AsUint8() uint8 {
+	return uint8(s)
+}
+
+const (
+	MsgStatusUnknown MsgStatus = iota
+	// MsgStatusPending 等待发送，包括正在重试的
+	MsgStatusPending
+	MsgStatusSent
+	// MsgStatusFailed 重试次数用完了，不会再自动发送。
+	// 它不会阻塞同一个 Key 后面的消息
+	MsgStatusFailed
+)
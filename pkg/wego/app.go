@@ -2,9 +2,11 @@
 package wego
 
 import (
+	"context"
 	"gitee.com/geekbang/basic-go/webook/pkg/ginx"
 	"gitee.com/geekbang/basic-go/webook/pkg/grpcx"
 	"gitee.com/geekbang/basic-go/webook/pkg/saramax"
+	"github.com/robfig/cron/v3"
 )
 
 // App 当你在 wire 里面使用这个结构体的时候，要注意不是所有的服务都需要全部字段，
@@ -12,5 +14,16 @@ import (
 type App struct {
 	GRPCServer *grpcx.Server
 	WebServer  *ginx.Server
-	Consumers  []saramax.Consumer
+	// AdminServer 管理后台的接口，只在内网监听，比如说对账
+	AdminServer *ginx.Server
+	Consumers   []saramax.Consumer
+	Cron        *cron.Cron
+	// Daemons 和服务一起启动，一直在后台运行的任务，
+	// 比如说 outbox 转发和延时关单
+	Daemons []Daemon
+}
+
+// Daemon 后台任务，Start 阻塞直到 ctx 被取消
+type Daemon interface {
+	Start(ctx context.Context) error
 }
@@ -0,0 +1,209 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidFeeRule = errors.New("抽成规则不合法")
+
+// FeeRateBase 抽成比例的单位是万分之一，1000 就是 10%。
+// 全部用整数算，不要用浮点数算钱
+const FeeRateBase = 10000
+
+// DefaultFeeRule 没有配置任何规则的时候用，Id 是 0
+var DefaultFeeRule = FeeRule{
+	Name: "默认抽成 10%",
+	Type: FeeRuleTypePercentage,
+	Rate: 1000,
+}
+
+type FeeRuleType uint8
+
+func (t FeeRuleType) AsUint8() uint8 {
+	return uint8(t)
+}
+
+const (
+	FeeRuleTypeUnknown FeeRuleType = iota
+	// FeeRuleTypePercentage 按照比例抽成
+	FeeRuleTypePercentage
+	// FeeRuleTypeFixed 每一笔固定抽多少
+	FeeRuleTypeFixed
+	// FeeRuleTypeTiered 阶梯抽成，每一段金额按照这一段的比例算，和个税一样
+	FeeRuleTypeTiered
+)
+
+// FeeTier 阶梯里面的一段，上一段的 UpTo 到这一段的 UpTo 按照 Rate 抽成
+type FeeTier struct {
+	// UpTo 这一段的上限，包含。0 代表没有上限，只有最后一段可以是 0
+	UpTo int64
+	Rate int64
+}
+
+// FeeRule 平台抽成的规则。
+// 匹配的时候作者单独的规则优先，然后是业务的规则，最后是全局的规则；
+// 同一个层级里面 Priority 大的优先，再然后是新的优先
+type FeeRule struct {
+	Id   int64
+	Name string
+	Type FeeRuleType
+	// Biz 为空就是所有业务都适用
+	Biz string
+	// TargetUid 不为 0 就是给某个作者单独设置的规则
+	TargetUid int64
+	// Currency 为空就是所有币种都适用，
+	// 但是有 Fixed、Tiers、Min 或者 Max 这种金额的规则必须指定币种
+	Currency string
+	// Rate 比例，单位是 FeeRateBase
+	Rate  int64
+	Fixed int64
+	Tiers []FeeTier
+	// Min 和 Max 是抽成的下限和上限，0 代表不限制
+	Min      int64
+	Max      int64
+	Priority int
+	// StartTime 和 EndTime 生效的时间段 [StartTime, EndTime)，零值代表不限制
+	StartTime time.Time
+	EndTime   time.Time
+	// RetireTime 修改规则不会改原来的，而是新建一个版本，
+	// 旧的版本从 RetireTime 开始不再生效。零值代表是现在的版本
+	RetireTime time.Time
+	Ctime      time.Time
+	Utime      time.Time
+}
+
+func (r FeeRule) Validate() error {
+	hasAmount := r.Fixed != 0 || len(r.Tiers) > 0 || r.Min != 0 || r.Max != 0
+	switch {
+	case hasAmount && r.Currency == "":
+		return fmt.Errorf("%w，有金额的规则必须指定币种", ErrInvalidFeeRule)
+	case r.Min < 0 || r.Max < 0 || (r.Max > 0 && r.Max < r.Min):
+		return fmt.Errorf("%w，上下限不对", ErrInvalidFeeRule)
+	case !r.EndTime.IsZero() && !r.EndTime.After(r.StartTime):
+		return fmt.Errorf("%w，结束时间要在开始时间之后", ErrInvalidFeeRule)
+	}
+	switch r.Type {
+	case FeeRuleTypePercentage:
+		return validRate(r.Rate)
+	case FeeRuleTypeFixed:
+		if r.Fixed <= 0 {
+			return fmt.Errorf("%w，固定抽成必须大于 0", ErrInvalidFeeRule)
+		}
+		return nil
+	case FeeRuleTypeTiered:
+		if len(r.Tiers) == 0 {
+			return fmt.Errorf("%w，阶梯抽成至少要有一段", ErrInvalidFeeRule)
+		}
+		var lower int64
+		for i, t := range r.Tiers {
+			if err := validRate(t.Rate); err != nil {
+				return err
+			}
+			last := i == len(r.Tiers)-1
+			if (t.UpTo == 0 && !last) || (t.UpTo != 0 && t.UpTo <= lower) {
+				return fmt.Errorf("%w，阶梯必须从小到大，只有最后一段可以没有上限", ErrInvalidFeeRule)
+			}
+			lower = t.UpTo
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w，不认识的规则类型", ErrInvalidFeeRule)
+	}
+}
+
+func validRate(rate int64) error {
+	if rate < 0 || rate > FeeRateBase {
+		return fmt.Errorf("%w，比例必须在 0 到 10000 之间", ErrInvalidFeeRule)
+	}
+	return nil
+}
+
+// Applies t 时刻的打赏 rw 能不能用这个规则。
+// 规则创建之前和被修改之后的打赏都不能用，这样新建或者修改规则都不会影响以前的打赏
+func (r FeeRule) Applies(rw Reward, t time.Time) bool {
+	return (r.Biz == "" || r.Biz == rw.Target.Biz) &&
+		(r.TargetUid == 0 || r.TargetUid == rw.Target.Uid) &&
+		(r.Currency == "" || r.Currency == rw.Currency) &&
+		(r.StartTime.IsZero() || !t.Before(r.StartTime)) &&
+		(r.EndTime.IsZero() || t.Before(r.EndTime)) &&
+		(r.Ctime.IsZero() || !t.Before(r.Ctime)) &&
+		(r.RetireTime.IsZero() || t.Before(r.RetireTime))
+}
+
+// Fee 平台抽成多少，单位和 amt 一样是最小单位。
+// 比例算出来不是整数的时候四舍五入，阶梯抽成是所有段加起来之后才四舍五入。
+// 抽成不会是负数，也不会超过 amt
+func (r FeeRule) Fee(amt int64) int64 {
+	var fee int64
+	switch r.Type {
+	case FeeRuleTypePercentage:
+		fee = roundDiv(amt*r.Rate, FeeRateBase)
+	case FeeRuleTypeFixed:
+		fee = r.Fixed
+	case FeeRuleTypeTiered:
+		var lower, sum int64
+		for _, t := range r.Tiers {
+			if amt <= lower {
+				break
+			}
+			upper := amt
+			if t.UpTo > 0 && t.UpTo < amt {
+				upper = t.UpTo
+			}
+			sum += (upper - lower) * t.Rate
+			lower = upper
+		}
+		fee = roundDiv(sum, FeeRateBase)
+	}
+	if r.Min > 0 && fee < r.Min {
+		fee = r.Min
+	}
+	if r.Max > 0 && fee > r.Max {
+		fee = r.Max
+	}
+	if fee > amt {
+		fee = amt
+	}
+	if fee < 0 {
+		fee = 0
+	}
+	return fee
+}
+
+// level 越具体的规则越优先
+func (r FeeRule) level() int {
+	res := 0
+	if r.TargetUid > 0 {
+		res += 2
+	}
+	if r.Biz != "" {
+		res++
+	}
+	return res
+}
+
+// MatchFeeRule 从 rules 里面找出 t 时刻的打赏 rw 要用的规则，
+// 一个都没有的话就用 DefaultFeeRule
+func MatchFeeRule(rules []FeeRule, rw Reward, t time.Time) FeeRule {
+	res := DefaultFeeRule
+	found := false
+	for _, r := range rules {
+		if !r.Applies(rw, t) {
+			continue
+		}
+		if !found || r.level() > res.level() ||
+			(r.level() == res.level() && (r.Priority > res.Priority ||
+				(r.Priority == res.Priority && r.Id > res.Id))) {
+			res = r
+			found = true
+		}
+	}
+	return res
+}
+
+// roundDiv 四舍五入的除法，n 不是负数
+func roundDiv(n, d int64) int64 {
+	return (n + d/2) / d
+}
@@ -0,0 +1,228 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestFeeRule_Fee(t *testing.T) {
+	tiers := []FeeTier{
+		{UpTo: 10000, Rate: 1000},
+		{UpTo: 100000, Rate: 500},
+		{Rate: 200},
+	}
+	testCases := []struct {
+		name string
+		rule FeeRule
+		amt  int64
+
+		want int64
+	}{
+		{
+			name: "按比例",
+			rule: FeeRule{Type: FeeRuleTypePercentage, Rate: 1000},
+			amt:  1000,
+			want: 100,
+		},
+		{
+			name: "按比例，四舍五入",
+			rule: FeeRule{Type: FeeRuleTypePercentage, Rate: 1000},
+			amt:  15,
+			want: 2,
+		},
+		{
+			name: "按比例，舍掉",
+			rule: FeeRule{Type: FeeRuleTypePercentage, Rate: 1000},
+			amt:  14,
+			want: 1,
+		},
+		{
+			name: "固定金额",
+			rule: FeeRule{Type: FeeRuleTypeFixed, Fixed: 50, Currency: "CNY"},
+			amt:  1000,
+			want: 50,
+		},
+		{
+			name: "固定金额不超过打赏金额",
+			rule: FeeRule{Type: FeeRuleTypeFixed, Fixed: 50, Currency: "CNY"},
+			amt:  30,
+			want: 30,
+		},
+		{
+			name: "阶梯，只落在第一段",
+			rule: FeeRule{Type: FeeRuleTypeTiered, Tiers: tiers, Currency: "CNY"},
+			amt:  5000,
+			want: 500,
+		},
+		{
+			name: "阶梯，跨了三段",
+			rule: FeeRule{Type: FeeRuleTypeTiered, Tiers: tiers, Currency: "CNY"},
+			amt:  200000,
+			// 10000 * 10% + 90000 * 5% + 100000 * 2%
+			want: 7500,
+		},
+		{
+			name: "阶梯，加起来之后才四舍五入",
+			rule: FeeRule{Type: FeeRuleTypeTiered, Tiers: tiers, Currency: "CNY"},
+			amt:  10005,
+			// 1000 + 0.25
+			want: 1000,
+		},
+		{
+			name: "下限",
+			rule: FeeRule{Type: FeeRuleTypePercentage, Rate: 1000, Min: 10, Currency: "CNY"},
+			amt:  50,
+			want: 10,
+		},
+		{
+			name: "上限",
+			rule: FeeRule{Type: FeeRuleTypePercentage, Rate: 1000, Max: 500, Currency: "CNY"},
+			amt:  100000,
+			want: 500,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.rule.Fee(tc.amt))
+		})
+	}
+}
+
+func TestFeeRule_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		rule    FeeRule
+		wantErr bool
+	}{
+		{
+			name: "按比例",
+			rule: FeeRule{Type: FeeRuleTypePercentage, Rate: 1000},
+		},
+		{
+			name:    "比例超过 100%",
+			rule:    FeeRule{Type: FeeRuleTypePercentage, Rate: 10001},
+			wantErr: true,
+		},
+		{
+			name:    "有金额但是没有币种",
+			rule:    FeeRule{Type: FeeRuleTypePercentage, Rate: 1000, Max: 100},
+			wantErr: true,
+		},
+		{
+			name:    "上限比下限小",
+			rule:    FeeRule{Type: FeeRuleTypePercentage, Rate: 1000, Min: 100, Max: 10, Currency: "CNY"},
+			wantErr: true,
+		},
+		{
+			name: "阶梯没有从小到大",
+			rule: FeeRule{Type: FeeRuleTypeTiered, Currency: "CNY",
+				Tiers: []FeeTier{{UpTo: 100, Rate: 1000}, {UpTo: 50, Rate: 500}}},
+			wantErr: true,
+		},
+		{
+			name: "阶梯中间没有上限",
+			rule: FeeRule{Type: FeeRuleTypeTiered, Currency: "CNY",
+				Tiers: []FeeTier{{Rate: 1000}, {UpTo: 50, Rate: 500}}},
+			wantErr: true,
+		},
+		{
+			name: "结束时间在开始时间之前",
+			rule: FeeRule{Type: FeeRuleTypePercentage, Rate: 1000,
+				StartTime: time.UnixMilli(2000), EndTime: time.UnixMilli(1000)},
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rule.Validate()
+			if tc.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidFeeRule)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMatchFeeRule(t *testing.T) {
+	now := time.UnixMilli(100000)
+	rw := Reward{Target: Target{Biz: "article", Uid: 123}, Currency: "CNY"}
+	global := FeeRule{Id: 1, Type: FeeRuleTypePercentage, Rate: 1000}
+	biz := FeeRule{Id: 2, Biz: "article", Type: FeeRuleTypePercentage, Rate: 800}
+	creator := FeeRule{Id: 3, TargetUid: 123, Type: FeeRuleTypePercentage, Rate: 500}
+	testCases := []struct {
+		name  string
+		rules []FeeRule
+		want  FeeRule
+	}{
+		{
+			name: "没有规则就用默认的",
+			want: DefaultFeeRule,
+		},
+		{
+			name:  "作者的规则优先",
+			rules: []FeeRule{global, creator, biz},
+			want:  creator,
+		},
+		{
+			name:  "业务的规则比全局的优先",
+			rules: []FeeRule{global, biz},
+			want:  biz,
+		},
+		{
+			name: "同一个层级 Priority 大的优先",
+			rules: []FeeRule{global,
+				{Id: 4, Type: FeeRuleTypePercentage, Rate: 0, Priority: 1}},
+			want: FeeRule{Id: 4, Type: FeeRuleTypePercentage, Rate: 0, Priority: 1},
+		},
+		{
+			name: "过期的规则不用",
+			rules: []FeeRule{global,
+				{Id: 5, TargetUid: 123, Type: FeeRuleTypePercentage, EndTime: now}},
+			want: global,
+		},
+		{
+			name: "还没生效的规则不用",
+			rules: []FeeRule{global,
+				{Id: 6, TargetUid: 123, Type: FeeRuleTypePercentage, StartTime: now.Add(time.Second)}},
+			want: global,
+		},
+		{
+			name: "币种不一样的规则不用",
+			rules: []FeeRule{global,
+				{Id: 7, TargetUid: 123, Type: FeeRuleTypeFixed, Fixed: 10, Currency: "USD"}},
+			want: global,
+		},
+		{
+			// 没有设置生效时间的规则，也不能用在创建之前的打赏上
+			name: "打赏之后才创建的规则不用",
+			rules: []FeeRule{global,
+				{Id: 8, TargetUid: 123, Type: FeeRuleTypePercentage, Ctime: now.Add(time.Second)}},
+			want: global,
+		},
+		{
+			name: "修改之前的打赏用旧的版本",
+			rules: []FeeRule{global,
+				{Id: 9, TargetUid: 123, Type: FeeRuleTypePercentage, Rate: 500,
+					Ctime: now.Add(-time.Hour), RetireTime: now.Add(time.Second)},
+				{Id: 10, TargetUid: 123, Type: FeeRuleTypePercentage, Rate: 300,
+					Ctime: now.Add(time.Second)}},
+			want: FeeRule{Id: 9, TargetUid: 123, Type: FeeRuleTypePercentage, Rate: 500,
+				Ctime: now.Add(-time.Hour), RetireTime: now.Add(time.Second)},
+		},
+		{
+			name: "修改之后的打赏用新的版本",
+			rules: []FeeRule{global,
+				{Id: 9, TargetUid: 123, Type: FeeRuleTypePercentage, Rate: 500,
+					Ctime: now.Add(-time.Hour), RetireTime: now},
+				{Id: 10, TargetUid: 123, Type: FeeRuleTypePercentage, Rate: 300, Ctime: now}},
+			want: FeeRule{Id: 10, TargetUid: 123, Type: FeeRuleTypePercentage, Rate: 300, Ctime: now},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, MatchFeeRule(tc.rules, rw, now))
+		})
+	}
+}
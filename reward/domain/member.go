@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrInvalidMemberPlan = errors.New("会员方案不合法")
+
+// MemberGracePeriod 到期没有续费的宽限期，宽限期内还是会员
+const MemberGracePeriod = time.Hour * 24 * 3
+
+// MemberRenewAhead 提前多久生成续费的订单
+const MemberRenewAhead = time.Hour * 24
+
+// MemberRenewRetryInterval 续费订单失败之后，隔多久再生成新的
+const MemberRenewRetryInterval = time.Hour * 12
+
+// MemberRenewMaxFailures 一个周期里面续费订单最多失败几次，超过了就等用户自己来订阅
+const MemberRenewMaxFailures = 3
+
+// MemberPlan 作者设置的付费会员方案，按月付费
+type MemberPlan struct {
+	Id         int64
+	CreatorUid int64
+	Name       string
+	// Price 每个月的价格，最小单位
+	Price    int64
+	Currency string
+	Status   MemberPlanStatus
+	Ctime    time.Time
+	Utime    time.Time
+}
+
+func (p MemberPlan) Validate() error {
+	if p.CreatorUid <= 0 || p.Name == "" || p.Price <= 0 || p.Currency == "" {
+		return ErrInvalidMemberPlan
+	}
+	return nil
+}
+
+type MemberPlanStatus uint8
+
+func (s MemberPlanStatus) AsUint8() uint8 {
+	return uint8(s)
+}
+
+const (
+	MemberPlanStatusUnknown MemberPlanStatus = iota
+	MemberPlanStatusActive
+	// MemberPlanStatusOffline 下线之后不能再订阅，已经订阅的人还可以续费
+	MemberPlanStatusOffline
+)
+
+// Subscription 一个用户对一个作者的订阅，一个作者只会有一条。
+//
+// 状态机：
+//
+//	付钱之后 -> Active
+//	Active 到期没有续费 -> PastDue
+//	PastDue 过了宽限期 -> Expired
+//	Active、PastDue 取消 -> Cancelled，到期之后 -> Expired
+//	任何状态付了续费的钱 -> Active
+type Subscription struct {
+	Id         int64
+	Uid        int64
+	CreatorUid int64
+	PlanId     int64
+	Status     SubscriptionStatus
+	// PeriodEnd 当前这个周期的结束时间
+	PeriodEnd time.Time
+	// RenewOrderId 还没有付钱的续费订单，0 代表没有
+	RenewOrderId int64
+	// RenewFailures 这个周期里面续费订单失败了几次，付了钱之后清零
+	RenewFailures int
+	// NextRenewTime 续费订单失败之后，这个时间之前不再生成新的
+	NextRenewTime time.Time
+	Ctime         time.Time
+	Utime         time.Time
+}
+
+// CanRenew t 时刻能不能生成续费订单
+func (s Subscription) CanRenew(t time.Time) bool {
+	if s.Status != SubscriptionStatusActive && s.Status != SubscriptionStatusPastDue {
+		return false
+	}
+	return s.RenewOrderId == 0 && s.RenewFailures < MemberRenewMaxFailures &&
+		!t.Before(s.NextRenewTime)
+}
+
+// Entitled t 时刻还是不是会员。
+// 取消了的在这个周期结束之前还是会员，没有续费的在宽限期内也还是会员
+func (s Subscription) Entitled(t time.Time) bool {
+	switch s.Status {
+	case SubscriptionStatusActive, SubscriptionStatusCancelled:
+		return t.Before(s.PeriodEnd)
+	case SubscriptionStatusPastDue:
+		return t.Before(s.PeriodEnd.Add(MemberGracePeriod))
+	default:
+		return false
+	}
+}
+
+// NextStatus 不考虑付钱的情况下，t 时刻应该变成什么状态
+func (s Subscription) NextStatus(t time.Time) SubscriptionStatus {
+	switch s.Status {
+	case SubscriptionStatusActive:
+		if !t.Before(s.PeriodEnd) {
+			return SubscriptionStatusPastDue
+		}
+	case SubscriptionStatusPastDue:
+		if !t.Before(s.PeriodEnd.Add(MemberGracePeriod)) {
+			return SubscriptionStatusExpired
+		}
+	case SubscriptionStatusCancelled:
+		if !t.Before(s.PeriodEnd) {
+			return SubscriptionStatusExpired
+		}
+	}
+	return s.Status
+}
+
+// NextPeriodEnd 在 paidAt 付了一个月的钱之后，周期的结束时间。
+// 提前续费的从当前周期结束开始算，过期了再付钱的从付钱的时候开始算
+func NextPeriodEnd(periodEnd time.Time, paidAt time.Time) time.Time {
+	start := periodEnd
+	if start.Before(paidAt) {
+		start = paidAt
+	}
+	return start.AddDate(0, 1, 0)
+}
+
+// RefundPeriodEnd 退了一个月的钱之后，周期的结束时间
+func RefundPeriodEnd(periodEnd time.Time) time.Time {
+	return periodEnd.AddDate(0, -1, 0)
+}
+
+type SubscriptionStatus uint8
+
+func (s SubscriptionStatus) AsUint8() uint8 {
+	return uint8(s)
+}
+
+const (
+	SubscriptionStatusUnknown SubscriptionStatus = iota
+	SubscriptionStatusActive
+	// SubscriptionStatusPastDue 到期了还没有续费，宽限期内还是会员
+	SubscriptionStatusPastDue
+	// SubscriptionStatusCancelled 取消了自动续费，这个周期结束之前还是会员
+	SubscriptionStatusCancelled
+	SubscriptionStatusExpired
+)
+
+// MemberOrder 订阅或者续费的订单，一个订单付一个月的钱
+type MemberOrder struct {
+	Id         int64
+	Uid        int64
+	CreatorUid int64
+	PlanId     int64
+	// SubscriptionId 续费的订单才有
+	SubscriptionId int64
+	Amt            int64
+	Currency       string
+	Status         MemberOrderStatus
+	// CodeURL 付钱的二维码，目前只支持扫码支付，续费也要用户自己扫
+	CodeURL string
+	Ctime   time.Time
+	Utime   time.Time
+}
+
+type MemberOrderStatus uint8
+
+func (s MemberOrderStatus) AsUint8() uint8 {
+	return uint8(s)
+}
+
+const (
+	MemberOrderStatusUnknown MemberOrderStatus = iota
+	MemberOrderStatusInit
+	MemberOrderStatusPaid
+	// MemberOrderStatusFailed 支付失败或者过期没有付钱
+	MemberOrderStatusFailed
+	// MemberOrderStatusRefunded 全额退款了，这个订单买的周期也收回来了
+	MemberOrderStatusRefunded
+)
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSubscription_Entitled(t *testing.T) {
+	end := time.UnixMilli(100000000)
+	testCases := []struct {
+		name string
+		sub  Subscription
+		t    time.Time
+
+		want bool
+	}{
+		{
+			name: "周期内",
+			sub:  Subscription{Status: SubscriptionStatusActive, PeriodEnd: end},
+			t:    end.Add(-time.Second),
+			want: true,
+		},
+		{
+			name: "到期了",
+			sub:  Subscription{Status: SubscriptionStatusActive, PeriodEnd: end},
+			t:    end,
+		},
+		{
+			name: "取消了，周期结束之前还是会员",
+			sub:  Subscription{Status: SubscriptionStatusCancelled, PeriodEnd: end},
+			t:    end.Add(-time.Second),
+			want: true,
+		},
+		{
+			name: "没有续费，宽限期内",
+			sub:  Subscription{Status: SubscriptionStatusPastDue, PeriodEnd: end},
+			t:    end.Add(MemberGracePeriod - time.Second),
+			want: true,
+		},
+		{
+			name: "没有续费，过了宽限期",
+			sub:  Subscription{Status: SubscriptionStatusPastDue, PeriodEnd: end},
+			t:    end.Add(MemberGracePeriod),
+		},
+		{
+			name: "已经过期",
+			sub:  Subscription{Status: SubscriptionStatusExpired, PeriodEnd: end},
+			t:    end.Add(-time.Second),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.sub.Entitled(tc.t))
+		})
+	}
+}
+
+func TestSubscription_NextStatus(t *testing.T) {
+	end := time.UnixMilli(100000000)
+	testCases := []struct {
+		name string
+		sub  Subscription
+		t    time.Time
+
+		want SubscriptionStatus
+	}{
+		{
+			name: "没到期",
+			sub:  Subscription{Status: SubscriptionStatusActive, PeriodEnd: end},
+			t:    end.Add(-time.Second),
+			want: SubscriptionStatusActive,
+		},
+		{
+			name: "到期没有续费",
+			sub:  Subscription{Status: SubscriptionStatusActive, PeriodEnd: end},
+			t:    end,
+			want: SubscriptionStatusPastDue,
+		},
+		{
+			name: "宽限期内",
+			sub:  Subscription{Status: SubscriptionStatusPastDue, PeriodEnd: end},
+			t:    end.Add(time.Hour),
+			want: SubscriptionStatusPastDue,
+		},
+		{
+			name: "过了宽限期",
+			sub:  Subscription{Status: SubscriptionStatusPastDue, PeriodEnd: end},
+			t:    end.Add(MemberGracePeriod),
+			want: SubscriptionStatusExpired,
+		},
+		{
+			name: "取消了，到期",
+			sub:  Subscription{Status: SubscriptionStatusCancelled, PeriodEnd: end},
+			t:    end,
+			want: SubscriptionStatusExpired,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.sub.NextStatus(tc.t))
+		})
+	}
+}
+
+func TestSubscription_CanRenew(t *testing.T) {
+	now := time.UnixMilli(100000000)
+	testCases := []struct {
+		name string
+		sub  Subscription
+
+		want bool
+	}{
+		{
+			name: "可以续费",
+			sub:  Subscription{Status: SubscriptionStatusPastDue},
+			want: true,
+		},
+		{
+			name: "已经有续费订单了",
+			sub:  Subscription{Status: SubscriptionStatusActive, RenewOrderId: 1},
+		},
+		{
+			name: "取消了",
+			sub:  Subscription{Status: SubscriptionStatusCancelled},
+		},
+		{
+			name: "刚刚失败过",
+			sub: Subscription{Status: SubscriptionStatusActive, RenewFailures: 1,
+				NextRenewTime: now.Add(time.Second)},
+		},
+		{
+			name: "失败之后过了间隔",
+			sub: Subscription{Status: SubscriptionStatusActive, RenewFailures: 1,
+				NextRenewTime: now},
+			want: true,
+		},
+		{
+			name: "失败太多次",
+			sub:  Subscription{Status: SubscriptionStatusPastDue, RenewFailures: MemberRenewMaxFailures},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.sub.CanRenew(now))
+		})
+	}
+}
+
+func TestNextPeriodEnd(t *testing.T) {
+	end := time.Date(2024, 3, 10, 0, 0, 0, 0, time.Local)
+	// 提前续费，从当前周期结束开始算
+	assert.Equal(t, time.Date(2024, 4, 10, 0, 0, 0, 0, time.Local),
+		NextPeriodEnd(end, end.Add(-time.Hour)))
+	// 过期之后再付钱，从付钱的时候开始算
+	paid := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+	assert.Equal(t, time.Date(2024, 4, 15, 0, 0, 0, 0, time.Local),
+		NextPeriodEnd(end, paid))
+	// 第一次订阅
+	assert.Equal(t, time.Date(2024, 4, 15, 0, 0, 0, 0, time.Local),
+		NextPeriodEnd(time.Time{}, paid))
+}
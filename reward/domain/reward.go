@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type Target struct {
 	// 因为什么而打赏
 	Biz   string
@@ -16,15 +18,24 @@ type Reward struct {
 	Id     int64
 	Uid    int64
 	Target Target
-	// 同样不着急引入货币。
 	Amt    int64
-	Status RewardStatus
+	// Currency 打赏的人选择的币种，入账的时候换成作者的币种
+	Currency string
+	Status   RewardStatus
+	// FeeRuleId 和 Fee 是支付成功之后算出来的平台抽成，
+	// Fee 的币种是 Currency。FeeRuleId 是 0 代表用的是 DefaultFeeRule
+	FeeRuleId int64
+	Fee       int64
+	// FeeComputed 已经算过抽成了，不能再用 FeeRuleId 判断，因为默认规则的 Id 也是 0
+	FeeComputed bool
+	Ctime       time.Time
 }
 
 // Completed 是否已经完成
 // 目前来说，也就是是否处理了支付回调
 func (r Reward) Completed() bool {
-	return r.Status == RewardStatusFailed || r.Status == RewardStatusPayed
+	return r.Status == RewardStatusFailed || r.Status == RewardStatusPayed ||
+		r.Status == RewardStatusRefunded
 }
 
 type RewardStatus uint8
@@ -38,6 +49,8 @@ const (
 	RewardStatusInit
 	RewardStatusPayed
 	RewardStatusFailed
+	// RewardStatusRefunded 全部退款了，部分退款的还是 RewardStatusPayed
+	RewardStatusRefunded
 )
 
 type CodeURL struct {
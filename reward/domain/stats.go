@@ -0,0 +1,47 @@
+package domain
+
+const (
+	// StatsDayLayout 作者按天统计的日期格式
+	StatsDayLayout = "20060102"
+	// StatsMonthLayout 作者按月统计的月份格式
+	StatsMonthLayout = "200601"
+)
+
+// TargetRewardStats 被打赏的东西的统计，比如说一篇文章。
+// 不同币种的打赏是分开统计的
+type TargetRewardStats struct {
+	Biz      string
+	BizId    int64
+	Currency string
+	// Cnt 打赏成功的次数
+	Cnt int64
+	Amt int64
+	// SupporterCnt 打赏过的人数
+	SupporterCnt int64
+	// Supporters 打赏最多的人，金额从大到小
+	Supporters []Supporter
+}
+
+// Supporter 打赏的人和他一共打赏了多少
+type Supporter struct {
+	Uid int64
+	Amt int64
+}
+
+// CreatorRewardStats 作者某一天和这一天所在的月份收到的打赏
+type CreatorRewardStats struct {
+	Uid      int64
+	Currency string
+	// Day 格式是 StatsDayLayout，Month 格式是 StatsMonthLayout
+	Day      string
+	Month    string
+	DaySum   RewardSum
+	MonthSum RewardSum
+}
+
+type RewardSum struct {
+	Cnt int64
+	Amt int64
+	// Earned 扣掉平台抽成之后作者拿到的
+	Earned int64
+}
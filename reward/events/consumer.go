@@ -14,6 +14,16 @@ import (
 type PaymentEvent struct {
 	BizTradeNO string
 	Status     uint8
+	// PaidTime 支付成功的时间，毫秒
+	PaidTime int64
+}
+
+// PaidAt 老的消息没有支付时间，只能用现在的时间
+func (p PaymentEvent) PaidAt() time.Time {
+	if p.PaidTime <= 0 {
+		return time.Now()
+	}
+	return time.UnixMilli(p.PaidTime)
 }
 
 func (p PaymentEvent) ToDomainStatus() domain.RewardStatus {
@@ -21,23 +31,49 @@ func (p PaymentEvent) ToDomainStatus() domain.RewardStatus {
 	//	PaymentStatusSuccess
 	//	PaymentStatusFailed
 	//	PaymentStatusRefund
+	//	PaymentStatusClosed
 	switch p.Status {
 	// 这里不能引用 payment 里面的定义，只能手写
 	case 1:
 		return domain.RewardStatusInit
 	case 2:
 		return domain.RewardStatusPayed
-	case 3, 4:
+	case 3:
+		return domain.RewardStatusFailed
+	case 4:
+		return domain.RewardStatusRefunded
+	case 5:
+		// 过期没有付钱，订单关掉了
 		return domain.RewardStatusFailed
 	default:
 		return domain.RewardStatusUnknown
 	}
 }
 
+// ToMemberOrderStatus 会员订单只关心付钱成功和失败
+func (p PaymentEvent) ToMemberOrderStatus() domain.MemberOrderStatus {
+	switch p.Status {
+	case 2:
+		return domain.MemberOrderStatusPaid
+	case 3, 5:
+		return domain.MemberOrderStatusFailed
+	default:
+		// 退款在退款事件里面处理
+		return domain.MemberOrderStatusUnknown
+	}
+}
+
 type PaymentEventConsumer struct {
-	client sarama.Client
-	l      logger.LoggerV1
-	svc    service.RewardService
+	client    sarama.Client
+	l         logger.LoggerV1
+	svc       service.RewardService
+	memberSvc service.MemberService
+}
+
+func NewPaymentEventConsumer(client sarama.Client,
+	l logger.LoggerV1, svc service.RewardService,
+	memberSvc service.MemberService) *PaymentEventConsumer {
+	return &PaymentEventConsumer{client: client, l: l, svc: svc, memberSvc: memberSvc}
 }
 
 // Start 这边就是自己启动 goroutine 了
@@ -61,10 +97,14 @@ func (r *PaymentEventConsumer) Start() error {
 func (r *PaymentEventConsumer) Consume(
 	msg *sarama.ConsumerMessage,
 	evt PaymentEvent) error {
-	if !strings.HasPrefix(evt.BizTradeNO, "reward") {
-		return nil
-	}
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
 	defer cancel()
-	return r.svc.UpdateReward(ctx, evt.BizTradeNO, evt.ToDomainStatus())
+	switch {
+	case strings.HasPrefix(evt.BizTradeNO, "reward"):
+		return r.svc.UpdateReward(ctx, evt.BizTradeNO, evt.ToDomainStatus())
+	case strings.HasPrefix(evt.BizTradeNO, "member"):
+		return r.memberSvc.HandlePayment(ctx, evt.BizTradeNO, evt.ToMemberOrderStatus(), evt.PaidAt())
+	default:
+		return nil
+	}
 }
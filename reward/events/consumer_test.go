@@ -0,0 +1,121 @@
+package events
+
+import (
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"gitee.com/geekbang/basic-go/webook/reward/domain"
+	"gitee.com/geekbang/basic-go/webook/reward/service"
+	svcmocks "gitee.com/geekbang/basic-go/webook/reward/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestPaymentEventConsumer_Consume(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (service.RewardService, service.MemberService)
+		evt  PaymentEvent
+	}{
+		{
+			name: "打赏",
+			mock: func(ctrl *gomock.Controller) (service.RewardService, service.MemberService) {
+				svc := svcmocks.NewMockRewardService(ctrl)
+				svc.EXPECT().UpdateReward(gomock.Any(), "reward-1", domain.RewardStatusPayed).Return(nil)
+				return svc, svcmocks.NewMockMemberService(ctrl)
+			},
+			evt: PaymentEvent{BizTradeNO: "reward-1", Status: 2, PaidTime: 1000},
+		},
+		{
+			// 按照付钱的时间算会员的周期
+			name: "会员付钱成功",
+			mock: func(ctrl *gomock.Controller) (service.RewardService, service.MemberService) {
+				memberSvc := svcmocks.NewMockMemberService(ctrl)
+				memberSvc.EXPECT().HandlePayment(gomock.Any(), "member-1",
+					domain.MemberOrderStatusPaid, time.UnixMilli(1000)).Return(nil)
+				return svcmocks.NewMockRewardService(ctrl), memberSvc
+			},
+			evt: PaymentEvent{BizTradeNO: "member-1", Status: 2, PaidTime: 1000},
+		},
+		{
+			name: "会员订单过期关掉了",
+			mock: func(ctrl *gomock.Controller) (service.RewardService, service.MemberService) {
+				memberSvc := svcmocks.NewMockMemberService(ctrl)
+				memberSvc.EXPECT().HandlePayment(gomock.Any(), "member-1",
+					domain.MemberOrderStatusFailed, gomock.Any()).Return(nil)
+				return svcmocks.NewMockRewardService(ctrl), memberSvc
+			},
+			evt: PaymentEvent{BizTradeNO: "member-1", Status: 5},
+		},
+		{
+			name: "别的业务",
+			mock: func(ctrl *gomock.Controller) (service.RewardService, service.MemberService) {
+				return svcmocks.NewMockRewardService(ctrl), svcmocks.NewMockMemberService(ctrl)
+			},
+			evt: PaymentEvent{BizTradeNO: "course-1", Status: 2},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc, memberSvc := tc.mock(ctrl)
+			c := NewPaymentEventConsumer(nil, logger.NewNopLogger(), svc, memberSvc)
+			err := c.Consume(nil, tc.evt)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRefundEventConsumer_Consume(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (service.RewardService, service.MemberService)
+		evt  RefundEvent
+	}{
+		{
+			name: "打赏退款",
+			mock: func(ctrl *gomock.Controller) (service.RewardService, service.MemberService) {
+				svc := svcmocks.NewMockRewardService(ctrl)
+				svc.EXPECT().HandleRefund(gomock.Any(), "reward-1", "refund-1", int64(100), true).Return(nil)
+				return svc, svcmocks.NewMockMemberService(ctrl)
+			},
+			evt: RefundEvent{BizTradeNO: "reward-1", RefundNO: "refund-1", Amt: 100, Status: 2, PaymentStatus: 4},
+		},
+		{
+			name: "会员全额退款",
+			mock: func(ctrl *gomock.Controller) (service.RewardService, service.MemberService) {
+				memberSvc := svcmocks.NewMockMemberService(ctrl)
+				memberSvc.EXPECT().HandleRefund(gomock.Any(), "member-1", true).Return(nil)
+				return svcmocks.NewMockRewardService(ctrl), memberSvc
+			},
+			evt: RefundEvent{BizTradeNO: "member-1", RefundNO: "refund-1", Amt: 100, Status: 2, PaymentStatus: 4},
+		},
+		{
+			name: "会员部分退款",
+			mock: func(ctrl *gomock.Controller) (service.RewardService, service.MemberService) {
+				memberSvc := svcmocks.NewMockMemberService(ctrl)
+				memberSvc.EXPECT().HandleRefund(gomock.Any(), "member-1", false).Return(nil)
+				return svcmocks.NewMockRewardService(ctrl), memberSvc
+			},
+			evt: RefundEvent{BizTradeNO: "member-1", RefundNO: "refund-1", Amt: 50, Status: 2, PaymentStatus: 2},
+		},
+		{
+			name: "退款失败",
+			mock: func(ctrl *gomock.Controller) (service.RewardService, service.MemberService) {
+				return svcmocks.NewMockRewardService(ctrl), svcmocks.NewMockMemberService(ctrl)
+			},
+			evt: RefundEvent{BizTradeNO: "member-1", RefundNO: "refund-1", Amt: 100, Status: 3, PaymentStatus: 2},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc, memberSvc := tc.mock(ctrl)
+			c := NewRefundEventConsumer(nil, logger.NewNopLogger(), svc, memberSvc)
+			err := c.Consume(nil, tc.evt)
+			assert.NoError(t, err)
+		})
+	}
+}
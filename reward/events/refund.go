@@ -0,0 +1,79 @@
+package events
+
+import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"gitee.com/geekbang/basic-go/webook/pkg/saramax"
+	"gitee.com/geekbang/basic-go/webook/reward/service"
+	"github.com/IBM/sarama"
+	"strings"
+	"time"
+)
+
+type RefundEvent struct {
+	BizTradeNO    string
+	RefundNO      string
+	Amt           int64
+	Currency      string
+	Status        uint8
+	PaymentStatus uint8
+}
+
+// Success 同样不能引用 payment 里面的定义，2 是退款成功
+func (r RefundEvent) Success() bool {
+	return r.Status == 2
+}
+
+// FullyRefunded 4 是支付记录已经全部退款了
+func (r RefundEvent) FullyRefunded() bool {
+	return r.PaymentStatus == 4
+}
+
+type RefundEventConsumer struct {
+	client    sarama.Client
+	l         logger.LoggerV1
+	svc       service.RewardService
+	memberSvc service.MemberService
+}
+
+func NewRefundEventConsumer(client sarama.Client,
+	l logger.LoggerV1, svc service.RewardService,
+	memberSvc service.MemberService) *RefundEventConsumer {
+	return &RefundEventConsumer{client: client, l: l, svc: svc, memberSvc: memberSvc}
+}
+
+func (r *RefundEventConsumer) Start() error {
+	cg, err := sarama.NewConsumerGroupFromClient("reward_refund",
+		r.client)
+	if err != nil {
+		return err
+	}
+	go func() {
+		err := cg.Consume(context.Background(),
+			[]string{"payment_refund_events"},
+			saramax.NewHandler[RefundEvent](r.l, r.Consume))
+		if err != nil {
+			r.l.Error("退出了消费循环异常", logger.Error(err))
+		}
+	}()
+	return err
+}
+
+func (r *RefundEventConsumer) Consume(
+	msg *sarama.ConsumerMessage,
+	evt RefundEvent) error {
+	// 退款失败的话，钱还在，什么都不用做
+	if !evt.Success() {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	switch {
+	case strings.HasPrefix(evt.BizTradeNO, "reward"):
+		return r.svc.HandleRefund(ctx, evt.BizTradeNO, evt.RefundNO, evt.Amt, evt.FullyRefunded())
+	case strings.HasPrefix(evt.BizTradeNO, "member"):
+		return r.memberSvc.HandleRefund(ctx, evt.BizTradeNO, evt.FullyRefunded())
+	default:
+		return nil
+	}
+}
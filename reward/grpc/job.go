@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"context"
+	jobv1 "gitee.com/geekbang/basic-go/webook/api/proto/gen/job/v1"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"gitee.com/geekbang/basic-go/webook/reward/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// JobNameMemberRenew 会员续费的任务，在中心调度器里面配置
+const JobNameMemberRenew = "member_renew"
+
+// JobExecutorServer 给中心调度器调用，执行 reward 里面的定时任务
+type JobExecutorServer struct {
+	jobv1.UnimplementedJobExecutorServiceServer
+	memberSvc service.MemberService
+	// token 调度器放在 authorization 元数据里面，为空就不校验
+	token string
+	l     logger.LoggerV1
+}
+
+func NewJobExecutorServer(memberSvc service.MemberService, token string, l logger.LoggerV1) *JobExecutorServer {
+	return &JobExecutorServer{memberSvc: memberSvc, token: token, l: l}
+}
+
+func (j *JobExecutorServer) Register(server *grpc.Server) {
+	jobv1.RegisterJobExecutorServiceServer(server, j)
+}
+
+func (j *JobExecutorServer) Execute(ctx context.Context,
+	req *jobv1.ExecuteRequest) (*jobv1.ExecuteResponse, error) {
+	if !j.authorized(ctx) {
+		return nil, status.Error(codes.Unauthenticated, "token 不对")
+	}
+	switch req.GetName() {
+	case JobNameMemberRenew:
+		err := j.memberSvc.RenewDue(ctx)
+		if err != nil {
+			j.l.Error("会员续费任务失败", logger.Error(err),
+				logger.Int64("job_id", req.GetJobId()))
+			return &jobv1.ExecuteResponse{Code: 1, Msg: err.Error()}, nil
+		}
+		return &jobv1.ExecuteResponse{}, nil
+	default:
+		return &jobv1.ExecuteResponse{Code: 2, Msg: "不认识的任务 " + req.GetName()}, nil
+	}
+}
+
+func (j *JobExecutorServer) authorized(ctx context.Context) bool {
+	if j.token == "" {
+		return true
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	vals := md.Get("authorization")
+	return len(vals) > 0 && vals[0] == "Bearer "+j.token
+}
@@ -0,0 +1,115 @@
+package grpc
+
+import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/api/proto/gen/reward/v1"
+	"gitee.com/geekbang/basic-go/webook/reward/domain"
+	"github.com/ecodeclub/ekit/slice"
+)
+
+func (r *RewardServiceServer) SaveMemberPlan(ctx context.Context,
+	req *rewardv1.SaveMemberPlanRequest) (*rewardv1.SaveMemberPlanResponse, error) {
+	p := req.GetPlan()
+	id, err := r.memberSvc.SavePlan(ctx, domain.MemberPlan{
+		Id:         p.GetId(),
+		CreatorUid: p.GetCreatorUid(),
+		Name:       p.GetName(),
+		Price:      p.GetPrice(),
+		Currency:   p.GetCurrency(),
+		Status:     domain.MemberPlanStatus(p.GetStatus()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rewardv1.SaveMemberPlanResponse{Id: id}, nil
+}
+
+func (r *RewardServiceServer) ListMemberPlans(ctx context.Context,
+	req *rewardv1.ListMemberPlansRequest) (*rewardv1.ListMemberPlansResponse, error) {
+	plans, err := r.memberSvc.ListPlans(ctx, req.GetCreatorUid())
+	if err != nil {
+		return nil, err
+	}
+	return &rewardv1.ListMemberPlansResponse{
+		Plans: slice.Map(plans, func(idx int, src domain.MemberPlan) *rewardv1.MemberPlan {
+			return &rewardv1.MemberPlan{
+				Id:         src.Id,
+				CreatorUid: src.CreatorUid,
+				Name:       src.Name,
+				Price:      src.Price,
+				Currency:   src.Currency,
+				// 两边取值是一样的
+				Status: rewardv1.MemberPlanStatus(src.Status),
+			}
+		}),
+	}, nil
+}
+
+func (r *RewardServiceServer) Subscribe(ctx context.Context,
+	req *rewardv1.SubscribeRequest) (*rewardv1.SubscribeResponse, error) {
+	o, err := r.memberSvc.Subscribe(ctx, req.GetUid(), req.GetPlanId())
+	if err != nil {
+		return nil, err
+	}
+	return &rewardv1.SubscribeResponse{Order: r.orderToDTO(o)}, nil
+}
+
+func (r *RewardServiceServer) GetMemberOrder(ctx context.Context,
+	req *rewardv1.GetMemberOrderRequest) (*rewardv1.GetMemberOrderResponse, error) {
+	o, err := r.memberSvc.GetOrder(ctx, req.GetId(), req.GetUid())
+	if err != nil {
+		return nil, err
+	}
+	return &rewardv1.GetMemberOrderResponse{Order: r.orderToDTO(o)}, nil
+}
+
+func (r *RewardServiceServer) GetSubscription(ctx context.Context,
+	req *rewardv1.GetSubscriptionRequest) (*rewardv1.GetSubscriptionResponse, error) {
+	sub, err := r.memberSvc.GetSubscription(ctx, req.GetUid(), req.GetCreatorUid())
+	if err != nil {
+		return nil, err
+	}
+	return &rewardv1.GetSubscriptionResponse{
+		Subscription: &rewardv1.Subscription{
+			Id:           sub.Id,
+			Uid:          sub.Uid,
+			CreatorUid:   sub.CreatorUid,
+			PlanId:       sub.PlanId,
+			Status:       rewardv1.SubscriptionStatus(sub.Status),
+			PeriodEnd:    sub.PeriodEnd.UnixMilli(),
+			RenewOrderId: sub.RenewOrderId,
+		},
+	}, nil
+}
+
+func (r *RewardServiceServer) CancelSubscription(ctx context.Context,
+	req *rewardv1.CancelSubscriptionRequest) (*rewardv1.CancelSubscriptionResponse, error) {
+	err := r.memberSvc.CancelSubscription(ctx, req.GetUid(), req.GetCreatorUid())
+	if err != nil {
+		return nil, err
+	}
+	return &rewardv1.CancelSubscriptionResponse{}, nil
+}
+
+func (r *RewardServiceServer) CheckEntitlement(ctx context.Context,
+	req *rewardv1.CheckEntitlementRequest) (*rewardv1.CheckEntitlementResponse, error) {
+	ok, err := r.memberSvc.CheckEntitlement(ctx, req.GetUid(), req.GetCreatorUid())
+	if err != nil {
+		return nil, err
+	}
+	return &rewardv1.CheckEntitlementResponse{Entitled: ok}, nil
+}
+
+func (r *RewardServiceServer) orderToDTO(o domain.MemberOrder) *rewardv1.MemberOrder {
+	return &rewardv1.MemberOrder{
+		Id:             o.Id,
+		Uid:            o.Uid,
+		CreatorUid:     o.CreatorUid,
+		PlanId:         o.PlanId,
+		SubscriptionId: o.SubscriptionId,
+		Amt:            o.Amt,
+		Currency:       o.Currency,
+		Status:         rewardv1.MemberOrderStatus(o.Status),
+		CodeUrl:        o.CodeURL,
+	}
+}
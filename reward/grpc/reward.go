@@ -5,16 +5,21 @@ import (
 	"gitee.com/geekbang/basic-go/webook/api/proto/gen/reward/v1"
 	"gitee.com/geekbang/basic-go/webook/reward/domain"
 	"gitee.com/geekbang/basic-go/webook/reward/service"
+	"github.com/ecodeclub/ekit/slice"
 	"google.golang.org/grpc"
+	"time"
 )
 
 type RewardServiceServer struct {
 	rewardv1.UnimplementedRewardServiceServer
-	svc service.RewardService
+	svc       service.RewardService
+	feeSvc    service.FeeRuleService
+	memberSvc service.MemberService
 }
 
-func NewRewardServiceServer(svc service.RewardService) *RewardServiceServer {
-	return &RewardServiceServer{svc: svc}
+func NewRewardServiceServer(svc service.RewardService, feeSvc service.FeeRuleService,
+	memberSvc service.MemberService) *RewardServiceServer {
+	return &RewardServiceServer{svc: svc, feeSvc: feeSvc, memberSvc: memberSvc}
 }
 
 func (r *RewardServiceServer) Register(server *grpc.Server) {
@@ -28,9 +33,10 @@ func (r *RewardServiceServer) PreReward(ctx context.Context, request *rewardv1.P
 			Biz:     request.Biz,
 			BizId:   request.BizId,
 			BizName: request.BizName,
-			Uid:     request.Uid,
+			Uid:     request.TargetUid,
 		},
-		Amt: request.Amt,
+		Amt:      request.Amt,
+		Currency: request.Currency,
 	})
 	return &rewardv1.PreRewardResponse{
 		CodeUrl: codeURL.URL,
@@ -49,3 +55,152 @@ func (r *RewardServiceServer) GetReward(ctx context.Context,
 		Status: rewardv1.RewardStatus(rw.Status),
 	}, nil
 }
+
+func (r *RewardServiceServer) GetTargetStats(ctx context.Context,
+	req *rewardv1.GetTargetStatsRequest) (*rewardv1.GetTargetStatsResponse, error) {
+	st, err := r.svc.GetTargetStats(ctx, req.GetBiz(), req.GetBizId(), req.GetCurrency(), r.topN(req.GetN()))
+	if err != nil {
+		return nil, err
+	}
+	return &rewardv1.GetTargetStatsResponse{
+		Currency:     st.Currency,
+		Cnt:          st.Cnt,
+		Amt:          st.Amt,
+		SupporterCnt: st.SupporterCnt,
+		Supporters:   r.supportersToDTO(st.Supporters),
+	}, nil
+}
+
+func (r *RewardServiceServer) GetCreatorStats(ctx context.Context,
+	req *rewardv1.GetCreatorStatsRequest) (*rewardv1.GetCreatorStatsResponse, error) {
+	day := time.Now()
+	if req.GetDay() != "" {
+		var err error
+		day, err = time.ParseInLocation(domain.StatsDayLayout, req.GetDay(), time.Local)
+		if err != nil {
+			return nil, err
+		}
+	}
+	st, err := r.svc.GetCreatorStats(ctx, req.GetUid(), req.GetCurrency(), day)
+	if err != nil {
+		return nil, err
+	}
+	return &rewardv1.GetCreatorStatsResponse{
+		Currency: st.Currency,
+		Day:      st.Day,
+		Month:    st.Month,
+		DaySum:   r.sumToDTO(st.DaySum),
+		MonthSum: r.sumToDTO(st.MonthSum),
+	}, nil
+}
+
+func (r *RewardServiceServer) TopSupporters(ctx context.Context,
+	req *rewardv1.TopSupportersRequest) (*rewardv1.TopSupportersResponse, error) {
+	sps, err := r.svc.TopSupporters(ctx, req.GetUid(), req.GetCurrency(), r.topN(req.GetN()))
+	if err != nil {
+		return nil, err
+	}
+	return &rewardv1.TopSupportersResponse{Supporters: r.supportersToDTO(sps)}, nil
+}
+
+// topN 没传或者传得太大的时候，最多返回 100 个
+func (r *RewardServiceServer) topN(n int32) int {
+	if n <= 0 || n > 100 {
+		return 100
+	}
+	return int(n)
+}
+
+func (r *RewardServiceServer) supportersToDTO(sps []domain.Supporter) []*rewardv1.Supporter {
+	return slice.Map(sps, func(idx int, src domain.Supporter) *rewardv1.Supporter {
+		return &rewardv1.Supporter{Uid: src.Uid, Amt: src.Amt}
+	})
+}
+
+func (r *RewardServiceServer) sumToDTO(sum domain.RewardSum) *rewardv1.RewardSum {
+	return &rewardv1.RewardSum{Cnt: sum.Cnt, Amt: sum.Amt, Earned: sum.Earned}
+}
+
+func (r *RewardServiceServer) SaveFeeRule(ctx context.Context,
+	req *rewardv1.SaveFeeRuleRequest) (*rewardv1.SaveFeeRuleResponse, error) {
+	id, err := r.feeSvc.SaveFeeRule(ctx, r.feeRuleToDomain(req.GetRule()))
+	if err != nil {
+		return nil, err
+	}
+	return &rewardv1.SaveFeeRuleResponse{Id: id}, nil
+}
+
+func (r *RewardServiceServer) GetFeeRule(ctx context.Context,
+	req *rewardv1.GetFeeRuleRequest) (*rewardv1.GetFeeRuleResponse, error) {
+	rule, err := r.feeSvc.GetFeeRule(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &rewardv1.GetFeeRuleResponse{Rule: r.feeRuleToDTO(rule)}, nil
+}
+
+func (r *RewardServiceServer) ListFeeRules(ctx context.Context,
+	req *rewardv1.ListFeeRulesRequest) (*rewardv1.ListFeeRulesResponse, error) {
+	rules, err := r.feeSvc.ListFeeRules(ctx, int(req.GetOffset()), int(req.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+	return &rewardv1.ListFeeRulesResponse{
+		Rules: slice.Map(rules, func(idx int, src domain.FeeRule) *rewardv1.FeeRule {
+			return r.feeRuleToDTO(src)
+		}),
+	}, nil
+}
+
+func (r *RewardServiceServer) feeRuleToDomain(rule *rewardv1.FeeRule) domain.FeeRule {
+	res := domain.FeeRule{
+		Id:        rule.GetId(),
+		Name:      rule.GetName(),
+		Type:      domain.FeeRuleType(rule.GetType()),
+		Biz:       rule.GetBiz(),
+		TargetUid: rule.GetTargetUid(),
+		Currency:  rule.GetCurrency(),
+		Rate:      rule.GetRate(),
+		Fixed:     rule.GetFixed(),
+		Tiers: slice.Map(rule.GetTiers(), func(idx int, src *rewardv1.FeeTier) domain.FeeTier {
+			return domain.FeeTier{UpTo: src.GetUpTo(), Rate: src.GetRate()}
+		}),
+		Min:      rule.GetMin(),
+		Max:      rule.GetMax(),
+		Priority: int(rule.GetPriority()),
+	}
+	if rule.GetStartTime() > 0 {
+		res.StartTime = time.UnixMilli(rule.GetStartTime())
+	}
+	if rule.GetEndTime() > 0 {
+		res.EndTime = time.UnixMilli(rule.GetEndTime())
+	}
+	return res
+}
+
+func (r *RewardServiceServer) feeRuleToDTO(rule domain.FeeRule) *rewardv1.FeeRule {
+	res := &rewardv1.FeeRule{
+		Id: rule.Id,
+		// 两边取值是一样的
+		Type:      rewardv1.FeeRuleType(rule.Type),
+		Name:      rule.Name,
+		Biz:       rule.Biz,
+		TargetUid: rule.TargetUid,
+		Currency:  rule.Currency,
+		Rate:      rule.Rate,
+		Fixed:     rule.Fixed,
+		Tiers: slice.Map(rule.Tiers, func(idx int, src domain.FeeTier) *rewardv1.FeeTier {
+			return &rewardv1.FeeTier{UpTo: src.UpTo, Rate: src.Rate}
+		}),
+		Min:      rule.Min,
+		Max:      rule.Max,
+		Priority: int32(rule.Priority),
+	}
+	if !rule.StartTime.IsZero() {
+		res.StartTime = rule.StartTime.UnixMilli()
+	}
+	if !rule.EndTime.IsZero() {
+		res.EndTime = rule.EndTime.UnixMilli()
+	}
+	return res
+}
@@ -0,0 +1,43 @@
+package grpc
+
+import (
+	"context"
+	rewardv1 "gitee.com/geekbang/basic-go/webook/api/proto/gen/reward/v1"
+	"gitee.com/geekbang/basic-go/webook/reward/domain"
+	svcmocks "gitee.com/geekbang/basic-go/webook/reward/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestRewardServiceServer_PreReward(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc := svcmocks.NewMockRewardService(ctrl)
+	// Uid 是打赏的人，钱要进 TargetUid 的账号
+	svc.EXPECT().PreReward(gomock.Any(), domain.Reward{
+		Uid: 1024,
+		Target: domain.Target{
+			Biz:     "article",
+			BizId:   1,
+			BizName: "测试文章",
+			Uid:     2048,
+		},
+		Amt:      100,
+		Currency: "CNY",
+	}).Return(domain.CodeURL{Rid: 1, URL: "weixin://wxpay/1"}, nil)
+	server := NewRewardServiceServer(svc, nil, nil)
+	resp, err := server.PreReward(context.Background(), &rewardv1.PreRewardRequest{
+		Biz:       "article",
+		BizId:     1,
+		BizName:   "测试文章",
+		Uid:       1024,
+		TargetUid: 2048,
+		Amt:       100,
+		Currency:  "CNY",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.Rid)
+	assert.Equal(t, "weixin://wxpay/1", resp.CodeUrl)
+}
@@ -71,9 +71,10 @@ func (s *WechatNativeRewardServiceTestSuite) TestPreReward() {
 					TargetUid: 1234,
 					Uid:       123,
 					Amount:    1,
+					Currency:  "CNY",
 				}, r)
 
-				codeURL, err := s.rdb.GetDel(ctx, s.codeURLKey("test", 1, 123)).Result()
+				codeURL, err := s.rdb.GetDel(ctx, s.codeURLKey("test", 1, 123, "CNY")).Result()
 				require.NoError(t, err)
 				assert.Equal(t, "test_url", codeURL)
 			},
@@ -98,13 +99,13 @@ func (s *WechatNativeRewardServiceTestSuite) TestPreReward() {
 			before: func(t *testing.T) {
 				ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
 				defer cancel()
-				err := s.rdb.Set(ctx, s.codeURLKey("test", 2, 123), "test_url_1", time.Minute).Err()
+				err := s.rdb.Set(ctx, s.codeURLKey("test", 2, 123, "CNY"), "test_url_1", time.Minute).Err()
 				require.NoError(t, err)
 			},
 			after: func(t *testing.T) {
 				ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
 				defer cancel()
-				codeURL, err := s.rdb.GetDel(ctx, s.codeURLKey("test", 2, 123)).Result()
+				codeURL, err := s.rdb.GetDel(ctx, s.codeURLKey("test", 2, 123, "CNY")).Result()
 				require.NoError(t, err)
 				assert.Equal(t, "test_url_1", codeURL)
 			},
@@ -145,9 +146,9 @@ func (s *WechatNativeRewardServiceTestSuite) TearDownTest() {
 	s.db.Exec("TRUNCATE TABLE rewards")
 }
 
-func (s *WechatNativeRewardServiceTestSuite) codeURLKey(biz string, bizId, uid int64) string {
-	return fmt.Sprintf("reward:code_url:%s:%d:%d",
-		biz, bizId, uid)
+func (s *WechatNativeRewardServiceTestSuite) codeURLKey(biz string, bizId, uid int64, currency string) string {
+	return fmt.Sprintf("reward:code_url:%s:%d:%d:%s",
+		biz, bizId, uid, currency)
 }
 
 func TestWechatNativeRewardService(t *testing.T) {
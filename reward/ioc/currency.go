@@ -0,0 +1,14 @@
+package ioc
+
+import (
+	"gitee.com/geekbang/basic-go/webook/reward/service"
+	"github.com/spf13/viper"
+)
+
+func InitBaseCurrency() service.BaseCurrency {
+	c := viper.GetString("reward.baseCurrency")
+	if c == "" {
+		return "CNY"
+	}
+	return service.BaseCurrency(c)
+}
@@ -10,6 +10,7 @@ import (
 )
 
 func InitGRPCxServer(reward *grpc2.RewardServiceServer,
+	job *grpc2.JobExecutorServer,
 	ecli *clientv3.Client,
 	l logger.LoggerV1) *grpcx.Server {
 	type Config struct {
@@ -24,6 +25,7 @@ func InitGRPCxServer(reward *grpc2.RewardServiceServer,
 	}
 	server := grpc.NewServer()
 	reward.Register(server)
+	job.Register(server)
 	return &grpcx.Server{
 		Server:     server,
 		Port:       cfg.Port,
@@ -0,0 +1,13 @@
+package ioc
+
+import (
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	grpc2 "gitee.com/geekbang/basic-go/webook/reward/grpc"
+	"gitee.com/geekbang/basic-go/webook/reward/service"
+	"github.com/spf13/viper"
+)
+
+// InitJobExecutorServer token 要和中心调度器 job.grpc.reward 里面配置的一样
+func InitJobExecutorServer(memberSvc service.MemberService, l logger.LoggerV1) *grpc2.JobExecutorServer {
+	return grpc2.NewJobExecutorServer(memberSvc, viper.GetString("job.token"), l)
+}
@@ -0,0 +1,30 @@
+package ioc
+
+import (
+	"gitee.com/geekbang/basic-go/webook/pkg/saramax"
+	"gitee.com/geekbang/basic-go/webook/reward/events"
+	"github.com/IBM/sarama"
+	"github.com/spf13/viper"
+)
+
+func InitKafka() sarama.Client {
+	type Config struct {
+		Addrs []string `yaml:"addrs"`
+	}
+	saramaCfg := sarama.NewConfig()
+	var cfg Config
+	err := viper.UnmarshalKey("kafka", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	client, err := sarama.NewClient(cfg.Addrs, saramaCfg)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+func InitConsumers(pmt *events.PaymentEventConsumer,
+	refund *events.RefundEventConsumer) []saramax.Consumer {
+	return []saramax.Consumer{pmt, refund}
+}
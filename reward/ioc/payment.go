@@ -33,3 +33,15 @@ func InitPaymentClient(etcdClient *etcdv3.Client) pmtv1.WechatPaymentServiceClie
 	}
 	return pmtv1.NewWechatPaymentServiceClient(cc)
 }
+
+// InitPaymentChannel 打赏用哪个渠道付钱，本地离线测试的时候可以配置成 sandbox
+func InitPaymentChannel() pmtv1.PaymentChannel {
+	switch viper.GetString("grpc.client.payment.channel") {
+	case "alipay":
+		return pmtv1.PaymentChannel_PaymentChannelAlipay
+	case "sandbox":
+		return pmtv1.PaymentChannel_PaymentChannelSandbox
+	default:
+		return pmtv1.PaymentChannel_PaymentChannelWechat
+	}
+}
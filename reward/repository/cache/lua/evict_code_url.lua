@@ -0,0 +1,11 @@
+-- 同一个用户对同一个东西的打赏共用一个 key，
+-- 只有缓存的还是这个打赏的二维码才删除，不然会把后面新的打赏的二维码也删掉
+local val = redis.call("GET", KEYS[1])
+if not val then
+    return 0
+end
+local cu = cjson.decode(val)
+if cu["Rid"] == tonumber(ARGV[1]) then
+    return redis.call("DEL", KEYS[1])
+end
+return 0
@@ -0,0 +1,39 @@
+-- 同一个打赏只统计一次，重复消费的时候直接返回 0
+local doneKey = KEYS[1]
+-- 被打赏的东西的总数，hash
+local targetKey = KEYS[2]
+-- 被打赏的东西的打赏人排行，sorted set
+local targetSupportersKey = KEYS[3]
+-- 作者每天和每个月的收入，hash
+local dayKey = KEYS[4]
+local monthKey = KEYS[5]
+-- 作者的打赏人排行，sorted set
+local creatorSupportersKey = KEYS[6]
+
+local uid = ARGV[1]
+local amt = tonumber(ARGV[2])
+-- 扣掉平台抽成之后作者拿到的
+local earned = tonumber(ARGV[3])
+-- 下面都是秒
+local doneTTL = tonumber(ARGV[4])
+local dayTTL = tonumber(ARGV[5])
+local monthTTL = tonumber(ARGV[6])
+
+if not redis.call("SET", doneKey, 1, "NX", "EX", doneTTL) then
+    return 0
+end
+
+redis.call("HINCRBY", targetKey, "cnt", 1)
+redis.call("HINCRBY", targetKey, "amt", amt)
+redis.call("ZINCRBY", targetSupportersKey, amt, uid)
+
+for _, key in ipairs({dayKey, monthKey}) do
+    redis.call("HINCRBY", key, "cnt", 1)
+    redis.call("HINCRBY", key, "amt", amt)
+    redis.call("HINCRBY", key, "earned", earned)
+end
+redis.call("EXPIRE", dayKey, dayTTL)
+redis.call("EXPIRE", monthKey, monthTTL)
+
+redis.call("ZINCRBY", creatorSupportersKey, amt, uid)
+return 1
@@ -0,0 +1,50 @@
+-- 同一笔退款只扣一次，重复消费的时候直接返回 0
+local refundKey = KEYS[1]
+local targetKey = KEYS[2]
+local targetSupportersKey = KEYS[3]
+local dayKey = KEYS[4]
+local monthKey = KEYS[5]
+local creatorSupportersKey = KEYS[6]
+
+local uid = ARGV[1]
+-- 退了多少钱
+local amt = tonumber(ARGV[2])
+-- 作者少拿了多少
+local earned = tonumber(ARGV[3])
+-- 全部退完了才算少了一次打赏，是 1 或者 0
+local cnt = tonumber(ARGV[4])
+local doneTTL = tonumber(ARGV[5])
+
+if not redis.call("SET", refundKey, 1, "NX", "EX", doneTTL) then
+    return 0
+end
+
+-- 0 不用扣，而且 -0 传给 HINCRBY 会报错
+local function decr(key, field, n)
+    if n > 0 then
+        redis.call("HINCRBY", key, field, 0 - n)
+    end
+end
+
+-- 已经过期或者没有统计过的就不扣了，不然会扣成负数
+if redis.call("EXISTS", targetKey) == 1 then
+    decr(targetKey, "cnt", cnt)
+    decr(targetKey, "amt", amt)
+end
+for _, key in ipairs({dayKey, monthKey}) do
+    if redis.call("EXISTS", key) == 1 then
+        decr(key, "cnt", cnt)
+        decr(key, "amt", amt)
+        decr(key, "earned", earned)
+    end
+end
+-- 扣完了就从排行榜里面去掉
+for _, key in ipairs({targetSupportersKey, creatorSupportersKey}) do
+    if redis.call("ZSCORE", key, uid) then
+        local score = tonumber(redis.call("ZINCRBY", key, 0 - amt, uid))
+        if score <= 0 then
+            redis.call("ZREM", key, uid)
+        end
+    end
+end
+return 1
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	_ "embed"
 	"encoding/json"
 	"fmt"
 	"gitee.com/geekbang/basic-go/webook/reward/domain"
@@ -9,6 +10,9 @@ import (
 	"time"
 )
 
+//go:embed lua/evict_code_url.lua
+var luaEvictCodeURL string
+
 type RewardRedisCache struct {
 	client redis.Cmdable
 }
@@ -38,7 +42,13 @@ func (c *RewardRedisCache) CachedCodeURL(ctx context.Context, cu domain.CodeURL,
 	return c.client.Set(ctx, key, data, time.Minute*30).Err()
 }
 
+// EvictCodeURL 只删除 r.Id 这个打赏的二维码
+func (c *RewardRedisCache) EvictCodeURL(ctx context.Context, r domain.Reward) error {
+	return c.client.Eval(ctx, luaEvictCodeURL, []string{c.codeURLKey(r)}, r.Id).Err()
+}
+
 func (c *RewardRedisCache) codeURLKey(r domain.Reward) string {
-	return fmt.Sprintf("reward:code_url:%s:%d:%d",
-		r.Target.Biz, r.Target.BizId, r.Uid)
+	// 换了币种要重新下单
+	return fmt.Sprintf("reward:code_url:%s:%d:%d:%s",
+		r.Target.Biz, r.Target.BizId, r.Uid, r.Currency)
 }
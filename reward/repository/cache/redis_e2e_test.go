@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/reward/domain"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestRewardRedisCache_EvictCodeURL_e2e(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{
+		Addr: "localhost:6379",
+	})
+	c := NewRewardRedisCache(rdb)
+	r := domain.Reward{
+		Id:       1,
+		Uid:      123,
+		Target:   domain.Target{Biz: "test", BizId: 1},
+		Currency: "CNY",
+	}
+	testCases := []struct {
+		name   string
+		before func(t *testing.T, ctx context.Context)
+
+		wantCached bool
+		wantCU     domain.CodeURL
+	}{
+		{
+			name: "缓存的就是这个打赏的二维码",
+			before: func(t *testing.T, ctx context.Context) {
+				err := c.CachedCodeURL(ctx, domain.CodeURL{Rid: 1, URL: "url-1"}, r)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "缓存的是同一个用户后面新的打赏，不能删",
+			before: func(t *testing.T, ctx context.Context) {
+				err := c.CachedCodeURL(ctx, domain.CodeURL{Rid: 2, URL: "url-2"}, r)
+				require.NoError(t, err)
+			},
+			wantCached: true,
+			wantCU:     domain.CodeURL{Rid: 2, URL: "url-2"},
+		},
+		{
+			name:   "没有缓存",
+			before: func(t *testing.T, ctx context.Context) {},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+			defer cancel()
+			tc.before(t, ctx)
+			err := c.EvictCodeURL(ctx, r)
+			require.NoError(t, err)
+			cu, err := c.GetCachedCodeURL(ctx, r)
+			if !tc.wantCached {
+				assert.Equal(t, redis.Nil, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantCU, cu)
+			rdb.Del(ctx, "reward:code_url:test:1:123:CNY")
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"gitee.com/geekbang/basic-go/webook/reward/domain"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+//go:embed lua/incr_stats.lua
+var luaIncrStats string
+
+//go:embed lua/refund_stats.lua
+var luaRefundStats string
+
+const (
+	// 重复消费一般都在几分钟之内，保留久一点
+	statsDoneTTL  = time.Hour * 24 * 7
+	statsDayTTL   = time.Hour * 24 * 90
+	statsMonthTTL = time.Hour * 24 * 730
+)
+
+type RewardStatsRedisCache struct {
+	client redis.Cmdable
+}
+
+func NewRewardStatsRedisCache(client redis.Cmdable) RewardStatsCache {
+	return &RewardStatsRedisCache{client: client}
+}
+
+func (c *RewardStatsRedisCache) IncrStats(ctx context.Context, r domain.Reward, at time.Time) error {
+	return c.client.Eval(ctx, luaIncrStats, []string{
+		fmt.Sprintf("reward:stats:done:%d", r.Id),
+		c.targetKey(r.Target.Biz, r.Target.BizId, r.Currency),
+		c.targetSupportersKey(r.Target.Biz, r.Target.BizId, r.Currency),
+		c.creatorKey(r.Target.Uid, r.Currency, at.Format(domain.StatsDayLayout)),
+		c.creatorKey(r.Target.Uid, r.Currency, at.Format(domain.StatsMonthLayout)),
+		c.creatorSupportersKey(r.Target.Uid, r.Currency),
+	}, r.Uid, r.Amt, r.Amt-r.Fee,
+		int64(statsDoneTTL.Seconds()),
+		int64(statsDayTTL.Seconds()),
+		int64(statsMonthTTL.Seconds())).Err()
+}
+
+func (c *RewardStatsRedisCache) RefundStats(ctx context.Context, r domain.Reward, refundNO string,
+	amt int64, fullyRefunded bool, at time.Time) error {
+	// 作者少拿的按照比例算，统计用的，差一点没关系
+	var earned int64
+	if r.Amt > 0 {
+		earned = amt * (r.Amt - r.Fee) / r.Amt
+	}
+	var cnt int64
+	if fullyRefunded {
+		cnt = 1
+	}
+	return c.client.Eval(ctx, luaRefundStats, []string{
+		fmt.Sprintf("reward:stats:refund:%d:%s", r.Id, refundNO),
+		c.targetKey(r.Target.Biz, r.Target.BizId, r.Currency),
+		c.targetSupportersKey(r.Target.Biz, r.Target.BizId, r.Currency),
+		c.creatorKey(r.Target.Uid, r.Currency, at.Format(domain.StatsDayLayout)),
+		c.creatorKey(r.Target.Uid, r.Currency, at.Format(domain.StatsMonthLayout)),
+		c.creatorSupportersKey(r.Target.Uid, r.Currency),
+	}, r.Uid, amt, earned, cnt, int64(statsDoneTTL.Seconds())).Err()
+}
+
+func (c *RewardStatsRedisCache) GetTargetStats(ctx context.Context, biz string, bizId int64,
+	currency string, n int) (domain.TargetRewardStats, error) {
+	supportersKey := c.targetSupportersKey(biz, bizId, currency)
+	pipe := c.client.Pipeline()
+	sumCmd := pipe.HGetAll(ctx, c.targetKey(biz, bizId, currency))
+	cntCmd := pipe.ZCard(ctx, supportersKey)
+	topCmd := pipe.ZRevRangeWithScores(ctx, supportersKey, 0, int64(n)-1)
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return domain.TargetRewardStats{}, err
+	}
+	sum := c.toSum(sumCmd.Val())
+	return domain.TargetRewardStats{
+		Biz:          biz,
+		BizId:        bizId,
+		Currency:     currency,
+		Cnt:          sum.Cnt,
+		Amt:          sum.Amt,
+		SupporterCnt: cntCmd.Val(),
+		Supporters:   c.toSupporters(topCmd.Val()),
+	}, nil
+}
+
+func (c *RewardStatsRedisCache) GetCreatorStats(ctx context.Context, uid int64,
+	currency string, day time.Time) (domain.CreatorRewardStats, error) {
+	res := domain.CreatorRewardStats{
+		Uid:      uid,
+		Currency: currency,
+		Day:      day.Format(domain.StatsDayLayout),
+		Month:    day.Format(domain.StatsMonthLayout),
+	}
+	pipe := c.client.Pipeline()
+	dayCmd := pipe.HGetAll(ctx, c.creatorKey(uid, currency, res.Day))
+	monthCmd := pipe.HGetAll(ctx, c.creatorKey(uid, currency, res.Month))
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return domain.CreatorRewardStats{}, err
+	}
+	res.DaySum = c.toSum(dayCmd.Val())
+	res.MonthSum = c.toSum(monthCmd.Val())
+	return res, nil
+}
+
+func (c *RewardStatsRedisCache) TopSupporters(ctx context.Context, uid int64,
+	currency string, n int) ([]domain.Supporter, error) {
+	zs, err := c.client.ZRevRangeWithScores(ctx, c.creatorSupportersKey(uid, currency), 0, int64(n)-1).Result()
+	if err != nil {
+		return nil, err
+	}
+	return c.toSupporters(zs), nil
+}
+
+func (c *RewardStatsRedisCache) toSum(vals map[string]string) domain.RewardSum {
+	// 没有的字段就是 0
+	cnt, _ := strconv.ParseInt(vals["cnt"], 10, 64)
+	amt, _ := strconv.ParseInt(vals["amt"], 10, 64)
+	earned, _ := strconv.ParseInt(vals["earned"], 10, 64)
+	return domain.RewardSum{Cnt: cnt, Amt: amt, Earned: earned}
+}
+
+func (c *RewardStatsRedisCache) toSupporters(zs []redis.Z) []domain.Supporter {
+	return slice.Map(zs, func(idx int, src redis.Z) domain.Supporter {
+		uid, _ := strconv.ParseInt(src.Member.(string), 10, 64)
+		return domain.Supporter{Uid: uid, Amt: int64(src.Score)}
+	})
+}
+
+func (c *RewardStatsRedisCache) targetKey(biz string, bizId int64, currency string) string {
+	return fmt.Sprintf("reward:stats:target:%s:%d:%s", biz, bizId, currency)
+}
+
+func (c *RewardStatsRedisCache) targetSupportersKey(biz string, bizId int64, currency string) string {
+	return fmt.Sprintf("reward:supporters:target:%s:%d:%s", biz, bizId, currency)
+}
+
+// creatorKey period 是日期或者月份，两种格式的长度不一样，不会冲突
+func (c *RewardStatsRedisCache) creatorKey(uid int64, currency string, period string) string {
+	return fmt.Sprintf("reward:stats:creator:%d:%s:%s", uid, currency, period)
+}
+
+func (c *RewardStatsRedisCache) creatorSupportersKey(uid int64, currency string) string {
+	return fmt.Sprintf("reward:supporters:creator:%d:%s", uid, currency)
+}
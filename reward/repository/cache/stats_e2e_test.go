@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/reward/domain"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestRewardStatsRedisCache_e2e(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{
+		Addr: "localhost:6379",
+	})
+	c := NewRewardStatsRedisCache(rdb)
+	at := time.Date(2024, 3, 15, 10, 0, 0, 0, time.Local)
+	r := domain.Reward{
+		Id:       1,
+		Uid:      123,
+		Target:   domain.Target{Biz: "test", BizId: 1, Uid: 456},
+		Amt:      1000,
+		Currency: "CNY",
+		Fee:      100,
+	}
+	keys := []string{
+		"reward:stats:done:1",
+		"reward:stats:refund:1:refund-1",
+		"reward:stats:refund:1:refund-2",
+		"reward:stats:target:test:1:CNY",
+		"reward:supporters:target:test:1:CNY",
+		"reward:stats:creator:456:CNY:20240315",
+		"reward:stats:creator:456:CNY:202403",
+		"reward:supporters:creator:456:CNY",
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	rdb.Del(ctx, keys...)
+	defer rdb.Del(ctx, keys...)
+
+	assertStats := func(t *testing.T, cnt, amt, earned int64, supporters []domain.Supporter) {
+		target, err := c.GetTargetStats(ctx, "test", 1, "CNY", 10)
+		require.NoError(t, err)
+		assert.Equal(t, cnt, target.Cnt)
+		assert.Equal(t, amt, target.Amt)
+		assert.Equal(t, supporters, target.Supporters)
+		creator, err := c.GetCreatorStats(ctx, 456, "CNY", at)
+		require.NoError(t, err)
+		want := domain.RewardSum{Cnt: cnt, Amt: amt, Earned: earned}
+		assert.Equal(t, want, creator.DaySum)
+		assert.Equal(t, want, creator.MonthSum)
+		top, err := c.TopSupporters(ctx, 456, "CNY", 10)
+		require.NoError(t, err)
+		assert.Equal(t, supporters, top)
+	}
+
+	// 还没有统计过的打赏退款，不会扣成负数
+	err := c.RefundStats(ctx, r, "refund-1", 300, false, at)
+	require.NoError(t, err)
+	assertStats(t, 0, 0, 0, []domain.Supporter{})
+	rdb.Del(ctx, "reward:stats:refund:1:refund-1")
+
+	// 重复消费只统计一次
+	for i := 0; i < 2; i++ {
+		err = c.IncrStats(ctx, r, at)
+		require.NoError(t, err)
+	}
+	assertStats(t, 1, 1000, 900, []domain.Supporter{{Uid: 123, Amt: 1000}})
+
+	// 退一部分，次数不变，同一笔退款只扣一次
+	for i := 0; i < 2; i++ {
+		err = c.RefundStats(ctx, r, "refund-1", 300, false, at)
+		require.NoError(t, err)
+	}
+	assertStats(t, 1, 700, 630, []domain.Supporter{{Uid: 123, Amt: 700}})
+
+	// 全部退完，次数减一，从排行榜里面去掉
+	err = c.RefundStats(ctx, r, "refund-2", 700, true, at)
+	require.NoError(t, err)
+	assertStats(t, 0, 0, 0, []domain.Supporter{})
+}
@@ -3,9 +3,29 @@ package cache
 import (
 	"context"
 	"gitee.com/geekbang/basic-go/webook/reward/domain"
+	"time"
 )
 
 type RewardCache interface {
 	GetCachedCodeURL(ctx context.Context, r domain.Reward) (domain.CodeURL, error)
 	CachedCodeURL(ctx context.Context, cu domain.CodeURL, r domain.Reward) error
+	// EvictCodeURL 缓存的还是 r.Id 这个打赏的二维码才删除
+	EvictCodeURL(ctx context.Context, r domain.Reward) error
+}
+
+// RewardStatsCache 打赏的统计和排行榜，只统计支付成功的打赏，退款了会扣回来。
+// 统计不保证准确，需要准确的金额去 account 查流水
+type RewardStatsCache interface {
+	// IncrStats at 是统计在哪一天，同一个打赏重复调用只会统计一次
+	IncrStats(ctx context.Context, r domain.Reward, at time.Time) error
+	// RefundStats 退款了 amt，从统计里面扣掉，at 要和 IncrStats 的一样。
+	// fullyRefunded 为 true 的时候打赏次数也减一。同一个 refundNO 只会扣一次
+	RefundStats(ctx context.Context, r domain.Reward, refundNO string,
+		amt int64, fullyRefunded bool, at time.Time) error
+	// GetTargetStats n 是返回打赏最多的前多少个人
+	GetTargetStats(ctx context.Context, biz string, bizId int64, currency string, n int) (domain.TargetRewardStats, error)
+	// GetCreatorStats 作者在 day 这一天和这个月的收入
+	GetCreatorStats(ctx context.Context, uid int64, currency string, day time.Time) (domain.CreatorRewardStats, error)
+	// TopSupporters 给作者打赏最多的前 n 个人
+	TopSupporters(ctx context.Context, uid int64, currency string, n int) ([]domain.Supporter, error)
 }
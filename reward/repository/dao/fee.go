@@ -0,0 +1,73 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+type FeeRuleGORMDAO struct {
+	db *gorm.DB
+}
+
+func NewFeeRuleGORMDAO(db *gorm.DB) FeeRuleDAO {
+	return &FeeRuleGORMDAO{db: db}
+}
+
+func (dao *FeeRuleGORMDAO) Insert(ctx context.Context, r FeeRule) (int64, error) {
+	now := time.Now().UnixMilli()
+	r.Ctime = now
+	r.Utime = now
+	err := dao.db.WithContext(ctx).Create(&r).Error
+	return r.Id, err
+}
+
+func (dao *FeeRuleGORMDAO) Update(ctx context.Context, r FeeRule) (int64, error) {
+	now := time.Now().UnixMilli()
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 旧的版本从现在开始不生效，在这之前的打赏还是用它算抽成
+		res := tx.Model(&FeeRule{}).
+			Where("id = ? AND retire_time = ?", r.Id, 0).
+			Updates(map[string]any{
+				"retire_time": now,
+				"utime":       now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrRecordNotFound
+		}
+		r.Id = 0
+		r.RetireTime = 0
+		r.Ctime = now
+		r.Utime = now
+		return tx.Create(&r).Error
+	})
+	return r.Id, err
+}
+
+func (dao *FeeRuleGORMDAO) GetById(ctx context.Context, id int64) (FeeRule, error) {
+	var res FeeRule
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&res).Error
+	return res, err
+}
+
+func (dao *FeeRuleGORMDAO) FindCandidates(ctx context.Context, biz string, targetUid int64) ([]FeeRule, error) {
+	var res []FeeRule
+	// 规则不会很多，生效时间交给上层判断，所以被修改掉的旧版本也要查出来
+	err := dao.db.WithContext(ctx).
+		Where("biz IN ? AND target_uid IN ?", []string{"", biz}, []int64{0, targetUid}).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *FeeRuleGORMDAO) List(ctx context.Context, offset, limit int) ([]FeeRule, error) {
+	var res []FeeRule
+	err := dao.db.WithContext(ctx).
+		Where("retire_time = ?", 0).
+		Order("id DESC").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
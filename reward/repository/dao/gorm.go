@@ -19,6 +19,24 @@ func (dao *RewardGORMDAO) UpdateStatus(ctx context.Context, rid int64, status ui
 		}).Error
 }
 
+func (dao *RewardGORMDAO) UpdateFee(ctx context.Context, rid int64, feeRuleId int64, fee int64) error {
+	res := dao.db.WithContext(ctx).Model(&Reward{}).
+		Where("id = ? AND fee_computed = ?", rid, false).
+		Updates(map[string]any{
+			"fee_rule_id":  feeRuleId,
+			"fee":          fee,
+			"fee_computed": true,
+			"utime":        time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrFeeComputed
+	}
+	return nil
+}
+
 func (dao *RewardGORMDAO) GetReward(ctx context.Context, rid int64) (Reward, error) {
 	// 通过 uid 来判定是自己的打赏，防止黑客捞数据
 	var r Reward
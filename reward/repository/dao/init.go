@@ -3,5 +3,6 @@ package dao
 import "gorm.io/gorm"
 
 func InitTables(db *gorm.DB) error {
-	return db.AutoMigrate(&Reward{})
+	return db.AutoMigrate(&Reward{}, &FeeRule{},
+		&MemberPlan{}, &Subscription{}, &MemberOrder{})
 }
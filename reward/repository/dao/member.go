@@ -0,0 +1,300 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// 对应 domain 里面的状态
+const (
+	subscriptionStatusActive    = 1
+	subscriptionStatusPastDue   = 2
+	subscriptionStatusCancelled = 3
+	subscriptionStatusExpired   = 4
+
+	memberOrderStatusInit     = 1
+	memberOrderStatusPaid     = 2
+	memberOrderStatusFailed   = 3
+	memberOrderStatusRefunded = 4
+)
+
+type MemberGORMDAO struct {
+	db *gorm.DB
+}
+
+func NewMemberGORMDAO(db *gorm.DB) MemberDAO {
+	return &MemberGORMDAO{db: db}
+}
+
+func (dao *MemberGORMDAO) InsertPlan(ctx context.Context, p MemberPlan) (int64, error) {
+	now := time.Now().UnixMilli()
+	p.Ctime = now
+	p.Utime = now
+	err := dao.db.WithContext(ctx).Create(&p).Error
+	return p.Id, err
+}
+
+func (dao *MemberGORMDAO) UpdatePlan(ctx context.Context, p MemberPlan) error {
+	res := dao.db.WithContext(ctx).Model(&MemberPlan{}).
+		Where("id = ? AND creator_uid = ?", p.Id, p.CreatorUid).
+		Updates(map[string]any{
+			"name":     p.Name,
+			"price":    p.Price,
+			"currency": p.Currency,
+			"status":   p.Status,
+			"utime":    time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (dao *MemberGORMDAO) GetPlan(ctx context.Context, id int64) (MemberPlan, error) {
+	var res MemberPlan
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&res).Error
+	return res, err
+}
+
+func (dao *MemberGORMDAO) FindPlansByCreator(ctx context.Context, creatorUid int64) ([]MemberPlan, error) {
+	var res []MemberPlan
+	err := dao.db.WithContext(ctx).
+		Where("creator_uid = ?", creatorUid).
+		Order("id ASC").
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *MemberGORMDAO) InsertOrder(ctx context.Context, o MemberOrder) (int64, error) {
+	now := time.Now().UnixMilli()
+	o.Ctime = now
+	o.Utime = now
+	err := dao.db.WithContext(ctx).Create(&o).Error
+	return o.Id, err
+}
+
+func (dao *MemberGORMDAO) InsertRenewOrder(ctx context.Context, o MemberOrder) (int64, error) {
+	now := time.Now().UnixMilli()
+	o.Ctime = now
+	o.Utime = now
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&o).Error
+		if err != nil {
+			return err
+		}
+		res := tx.Model(&Subscription{}).
+			Where("id = ? AND renew_order_id = 0", o.SubscriptionId).
+			Updates(map[string]any{
+				"renew_order_id": o.Id,
+				"utime":          now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrSubscriptionStatus
+		}
+		return nil
+	})
+	return o.Id, err
+}
+
+func (dao *MemberGORMDAO) GetOrder(ctx context.Context, oid int64) (MemberOrder, error) {
+	var res MemberOrder
+	err := dao.db.WithContext(ctx).Where("id = ?", oid).First(&res).Error
+	return res, err
+}
+
+func (dao *MemberGORMDAO) UpdateOrderCodeURL(ctx context.Context, oid int64, codeURL string) error {
+	return dao.db.WithContext(ctx).Model(&MemberOrder{}).
+		Where("id = ?", oid).
+		Updates(map[string]any{
+			"code_url": codeURL,
+			"utime":    time.Now().UnixMilli(),
+		}).Error
+}
+
+func (dao *MemberGORMDAO) PayOrder(ctx context.Context, oid int64,
+	nextPeriodEnd func(periodEnd int64) int64) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var o MemberOrder
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", oid).First(&o).Error
+		if err != nil {
+			return err
+		}
+		if o.Status == memberOrderStatusPaid {
+			// 重复的支付消息
+			return nil
+		}
+		// 失败的订单也有可能最后付了钱，比如关单的时候用户刚好扫码付了，
+		// 钱已经收了，那就还是要给用户延长
+		var sub Subscription
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("uid = ? AND creator_uid = ?", o.Uid, o.CreatorUid).
+			First(&sub).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			sub = Subscription{
+				Uid:        o.Uid,
+				CreatorUid: o.CreatorUid,
+				PlanId:     o.PlanId,
+				Status:     subscriptionStatusActive,
+				PeriodEnd:  nextPeriodEnd(0),
+				Ctime:      now,
+				Utime:      now,
+			}
+			err = tx.Create(&sub).Error
+		case err == nil:
+			updates := map[string]any{
+				"plan_id":    o.PlanId,
+				"status":     subscriptionStatusActive,
+				"period_end": nextPeriodEnd(sub.PeriodEnd),
+				"utime":      now,
+			}
+			if sub.RenewOrderId == o.Id {
+				updates["renew_order_id"] = 0
+			}
+			// 新的周期重新开始算续费失败的次数
+			updates["renew_failures"] = 0
+			updates["next_renew_time"] = 0
+			err = tx.Model(&Subscription{}).Where("id = ?", sub.Id).
+				Updates(updates).Error
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&MemberOrder{}).Where("id = ?", oid).
+			Updates(map[string]any{
+				"status":          memberOrderStatusPaid,
+				"subscription_id": sub.Id,
+				"utime":           now,
+			}).Error
+	})
+}
+
+func (dao *MemberGORMDAO) FailOrder(ctx context.Context, oid int64, nextRenewTime int64) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&MemberOrder{}).
+			Where("id = ? AND status = ?", oid, memberOrderStatusInit).
+			Updates(map[string]any{
+				"status": memberOrderStatusFailed,
+				"utime":  now,
+			})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return tx.Model(&Subscription{}).
+			Where("renew_order_id = ?", oid).
+			Updates(map[string]any{
+				"renew_order_id":  0,
+				"renew_failures":  gorm.Expr("renew_failures + 1"),
+				"next_renew_time": nextRenewTime,
+				"utime":           now,
+			}).Error
+	})
+}
+
+func (dao *MemberGORMDAO) RefundOrder(ctx context.Context, oid int64,
+	prevPeriodEnd func(periodEnd int64) int64) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var o MemberOrder
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", oid).First(&o).Error
+		if err != nil {
+			return err
+		}
+		if o.Status != memberOrderStatusPaid {
+			// 重复的退款消息，或者没付钱的订单，没有周期可以收回
+			return nil
+		}
+		var sub Subscription
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", o.SubscriptionId).First(&sub).Error
+		if err != nil {
+			return err
+		}
+		periodEnd := prevPeriodEnd(sub.PeriodEnd)
+		updates := map[string]any{
+			"period_end": periodEnd,
+			"utime":      now,
+		}
+		if periodEnd <= now {
+			// 直接过期，不然到期任务会把它变成 PastDue，又多了一个宽限期
+			updates["status"] = subscriptionStatusExpired
+		}
+		err = tx.Model(&Subscription{}).Where("id = ?", sub.Id).
+			Updates(updates).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&MemberOrder{}).Where("id = ?", oid).
+			Updates(map[string]any{
+				"status": memberOrderStatusRefunded,
+				"utime":  now,
+			}).Error
+	})
+}
+
+func (dao *MemberGORMDAO) GetSubscription(ctx context.Context, uid int64, creatorUid int64) (Subscription, error) {
+	var res Subscription
+	err := dao.db.WithContext(ctx).
+		Where("uid = ? AND creator_uid = ?", uid, creatorUid).
+		First(&res).Error
+	return res, err
+}
+
+func (dao *MemberGORMDAO) CancelSubscription(ctx context.Context, uid int64, creatorUid int64) error {
+	res := dao.db.WithContext(ctx).Model(&Subscription{}).
+		Where("uid = ? AND creator_uid = ? AND status IN ?", uid, creatorUid,
+			[]int{subscriptionStatusActive, subscriptionStatusPastDue}).
+		Updates(map[string]any{
+			"status": subscriptionStatusCancelled,
+			"utime":  time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrSubscriptionStatus
+	}
+	return nil
+}
+
+func (dao *MemberGORMDAO) FindDueSubscriptions(ctx context.Context,
+	before int64, afterId int64, limit int) ([]Subscription, error) {
+	var res []Subscription
+	err := dao.db.WithContext(ctx).
+		Where("period_end < ? AND id > ? AND status IN ?", before, afterId,
+			[]int{subscriptionStatusActive, subscriptionStatusPastDue, subscriptionStatusCancelled}).
+		Order("id ASC").
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *MemberGORMDAO) UpdateSubscriptionStatus(ctx context.Context, id int64,
+	periodEnd int64, from uint8, to uint8) error {
+	res := dao.db.WithContext(ctx).Model(&Subscription{}).
+		Where("id = ? AND status = ? AND period_end = ?", id, from, periodEnd).
+		Updates(map[string]any{
+			"status": to,
+			"utime":  time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrSubscriptionStatus
+	}
+	return nil
+}
@@ -0,0 +1,327 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var (
+	lockOrderSQL = regexp.QuoteMeta("SELECT * FROM `member_orders` WHERE id = ? " +
+		"ORDER BY `member_orders`.`id` LIMIT ? FOR UPDATE")
+	orderCols = []string{"id", "uid", "creator_uid", "plan_id", "subscription_id", "status"}
+	subCols   = []string{"id", "uid", "creator_uid", "plan_id", "status", "period_end", "renew_order_id", "renew_failures"}
+)
+
+func TestMemberGORMDAO_PayOrder(t *testing.T) {
+	lockSubSQL := regexp.QuoteMeta("SELECT * FROM `subscriptions` WHERE uid = ? AND creator_uid = ? " +
+		"ORDER BY `subscriptions`.`id` LIMIT ? FOR UPDATE")
+	payOrderSQL := regexp.QuoteMeta("UPDATE `member_orders` SET `status`=?,`subscription_id`=?,`utime`=? WHERE id = ?")
+	testCases := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+
+		wantErr error
+	}{
+		{
+			// 续费失败的次数也要清零
+			name: "付了续费订单",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockOrderSQL).WithArgs(int64(1), 1).
+					WillReturnRows(sqlmock.NewRows(orderCols).AddRow(1, 1024, 2048, 1, 3, memberOrderStatusInit))
+				mock.ExpectQuery(lockSubSQL).WithArgs(int64(1024), int64(2048), 1).
+					WillReturnRows(sqlmock.NewRows(subCols).AddRow(3, 1024, 2048, 1, subscriptionStatusPastDue, 1000, 1, 2))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `subscriptions` SET `next_renew_time`=?,`period_end`=?,"+
+					"`plan_id`=?,`renew_failures`=?,`renew_order_id`=?,`status`=?,`utime`=? WHERE id = ?")).
+					WithArgs(0, int64(1500), int64(1), 0, 0, subscriptionStatusActive, sqlmock.AnyArg(), int64(3)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(payOrderSQL).
+					WithArgs(memberOrderStatusPaid, int64(3), sqlmock.AnyArg(), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "第一次订阅",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockOrderSQL).
+					WillReturnRows(sqlmock.NewRows(orderCols).AddRow(1, 1024, 2048, 1, 0, memberOrderStatusInit))
+				mock.ExpectQuery(lockSubSQL).WillReturnRows(sqlmock.NewRows(subCols))
+				mock.ExpectExec("INSERT INTO `subscriptions` .*").
+					WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectExec(payOrderSQL).
+					WithArgs(memberOrderStatusPaid, int64(3), sqlmock.AnyArg(), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "重复的支付消息",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockOrderSQL).
+					WillReturnRows(sqlmock.NewRows(orderCols).AddRow(1, 1024, 2048, 1, 3, memberOrderStatusPaid))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "订单不存在",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockOrderSQL).WillReturnRows(sqlmock.NewRows(orderCols))
+				mock.ExpectRollback()
+			},
+			wantErr: gorm.ErrRecordNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			tc.mock(mock)
+			dao := NewMemberGORMDAO(openMockDB(t, sqlDB))
+			err = dao.PayOrder(context.Background(), 1, func(periodEnd int64) int64 {
+				return periodEnd + 500
+			})
+			assert.Equal(t, tc.wantErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMemberGORMDAO_FailOrder(t *testing.T) {
+	failOrderSQL := regexp.QuoteMeta("UPDATE `member_orders` SET `status`=?,`utime`=? WHERE id = ? AND status = ?")
+	testCases := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "续费订单失败，记一次失败",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(failOrderSQL).
+					WithArgs(memberOrderStatusFailed, sqlmock.AnyArg(), int64(1), memberOrderStatusInit).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `subscriptions` SET `next_renew_time`=?,"+
+					"`renew_failures`=renew_failures + 1,`renew_order_id`=?,`utime`=? WHERE renew_order_id = ?")).
+					WithArgs(int64(2000), 0, sqlmock.AnyArg(), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			// 比如已经付了钱，不能再改成失败，也不能动订阅
+			name: "订单已经不是初始状态",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(failOrderSQL).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			tc.mock(mock)
+			dao := NewMemberGORMDAO(openMockDB(t, sqlDB))
+			err = dao.FailOrder(context.Background(), 1, 2000)
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMemberGORMDAO_RefundOrder(t *testing.T) {
+	lockSubSQL := regexp.QuoteMeta("SELECT * FROM `subscriptions` WHERE id = ? " +
+		"ORDER BY `subscriptions`.`id` LIMIT ? FOR UPDATE")
+	refundOrderSQL := regexp.QuoteMeta("UPDATE `member_orders` SET `status`=?,`utime`=? WHERE id = ?")
+	future := time.Now().Add(time.Hour * 24 * 60).UnixMilli()
+	testCases := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+		// prevPeriodEnd 退款之后的周期结束时间
+		prevPeriodEnd int64
+	}{
+		{
+			name: "退掉提前续费的一个月",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockOrderSQL).
+					WillReturnRows(sqlmock.NewRows(orderCols).AddRow(1, 1024, 2048, 1, 3, memberOrderStatusPaid))
+				mock.ExpectQuery(lockSubSQL).WithArgs(int64(3), 1).
+					WillReturnRows(sqlmock.NewRows(subCols).AddRow(3, 1024, 2048, 1, subscriptionStatusActive, future+1000, 0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `subscriptions` SET `period_end`=?,`utime`=? WHERE id = ?")).
+					WithArgs(future, sqlmock.AnyArg(), int64(3)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(refundOrderSQL).
+					WithArgs(memberOrderStatusRefunded, sqlmock.AnyArg(), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			prevPeriodEnd: future,
+		},
+		{
+			// 不能变成 PastDue，不然还有宽限期
+			name: "退完已经结束了，直接过期",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockOrderSQL).
+					WillReturnRows(sqlmock.NewRows(orderCols).AddRow(1, 1024, 2048, 1, 3, memberOrderStatusPaid))
+				mock.ExpectQuery(lockSubSQL).
+					WillReturnRows(sqlmock.NewRows(subCols).AddRow(3, 1024, 2048, 1, subscriptionStatusActive, 2000, 0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `subscriptions` SET `period_end`=?,`status`=?,`utime`=? WHERE id = ?")).
+					WithArgs(int64(1000), subscriptionStatusExpired, sqlmock.AnyArg(), int64(3)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(refundOrderSQL).
+					WithArgs(memberOrderStatusRefunded, sqlmock.AnyArg(), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			prevPeriodEnd: 1000,
+		},
+		{
+			name: "重复的退款消息",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockOrderSQL).
+					WillReturnRows(sqlmock.NewRows(orderCols).AddRow(1, 1024, 2048, 1, 3, memberOrderStatusRefunded))
+				mock.ExpectCommit()
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			tc.mock(mock)
+			dao := NewMemberGORMDAO(openMockDB(t, sqlDB))
+			err = dao.RefundOrder(context.Background(), 1, func(periodEnd int64) int64 {
+				return tc.prevPeriodEnd
+			})
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMemberGORMDAO_UpdateSubscriptionStatus(t *testing.T) {
+	updateSQL := regexp.QuoteMeta("UPDATE `subscriptions` SET `status`=?,`utime`=? " +
+		"WHERE id = ? AND status = ? AND period_end = ?")
+	testCases := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+
+		wantErr error
+	}{
+		{
+			name: "到期没有续费",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(updateSQL).
+					WithArgs(uint8(subscriptionStatusPastDue), sqlmock.AnyArg(), int64(3),
+						uint8(subscriptionStatusActive), int64(1000)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			// 付了钱状态还是 Active，但是周期变了
+			name: "刚刚付了钱",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(updateSQL).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: ErrSubscriptionStatus,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			tc.mock(mock)
+			dao := NewMemberGORMDAO(openMockDB(t, sqlDB))
+			err = dao.UpdateSubscriptionStatus(context.Background(), 3, 1000,
+				subscriptionStatusActive, subscriptionStatusPastDue)
+			assert.Equal(t, tc.wantErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMemberGORMDAO_CancelSubscription(t *testing.T) {
+	cancelSQL := regexp.QuoteMeta("UPDATE `subscriptions` SET `status`=?,`utime`=? " +
+		"WHERE uid = ? AND creator_uid = ? AND status IN (?,?)")
+	testCases := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+
+		wantErr error
+	}{
+		{
+			name: "取消成功",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(cancelSQL).
+					WithArgs(subscriptionStatusCancelled, sqlmock.AnyArg(), int64(1024), int64(2048),
+						subscriptionStatusActive, subscriptionStatusPastDue).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "已经过期了",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(cancelSQL).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: ErrSubscriptionStatus,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			tc.mock(mock)
+			dao := NewMemberGORMDAO(openMockDB(t, sqlDB))
+			err = dao.CancelSubscription(context.Background(), 1024, 2048)
+			assert.Equal(t, tc.wantErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMemberGORMDAO_FindDueSubscriptions(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `subscriptions` "+
+		"WHERE period_end < ? AND id > ? AND status IN (?,?,?) ORDER BY id ASC LIMIT ?")).
+		WithArgs(int64(2000), int64(0), subscriptionStatusActive, subscriptionStatusPastDue,
+			subscriptionStatusCancelled, 10).
+		WillReturnRows(sqlmock.NewRows(subCols).
+			AddRow(3, 1024, 2048, 1, subscriptionStatusActive, 1000, 0, 0))
+	dao := NewMemberGORMDAO(openMockDB(t, sqlDB))
+	subs, err := dao.FindDueSubscriptions(context.Background(), 2000, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []Subscription{{Id: 3, Uid: 1024, CreatorUid: 2048, PlanId: 1,
+		Status: subscriptionStatusActive, PeriodEnd: 1000}}, subs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func openMockDB(t *testing.T, sqlDB *sql.DB) *gorm.DB {
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+	return db
+}
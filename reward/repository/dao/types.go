@@ -2,12 +2,25 @@ package dao
 
 import (
 	"context"
+	"errors"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRecordNotFound = gorm.ErrRecordNotFound
+	// ErrSubscriptionStatus 订阅的状态不对，或者已经有没付钱的续费订单
+	ErrSubscriptionStatus = errors.New("订阅状态不对")
+	// ErrFeeComputed 这个打赏已经算过抽成了
+	ErrFeeComputed = errors.New("已经算过抽成了")
 )
 
 type RewardDAO interface {
 	Insert(ctx context.Context, r Reward) (int64, error)
 	GetReward(ctx context.Context, rid int64) (Reward, error)
 	UpdateStatus(ctx context.Context, rid int64, status uint8) error
+	// UpdateFee 记录平台抽成和用的规则，方便财务核对。
+	// 只能记一次，已经记过了返回 ErrFeeComputed
+	UpdateFee(ctx context.Context, rid int64, feeRuleId int64, fee int64) error
 }
 
 type Reward struct {
@@ -23,6 +36,126 @@ type Reward struct {
 	// 打赏的人
 	Uid    int64
 	Amount int64
-	Ctime  int64
-	Utime  int64
+	// 以前的数据是空字符串，都是人民币
+	Currency string `gorm:"type:varchar(8)"`
+	// 平台抽成，支付成功之后才有
+	FeeRuleId int64
+	Fee       int64
+	// FeeComputed 算过抽成了，默认规则的 FeeRuleId 是 0，所以要单独记一下
+	FeeComputed bool
+	Ctime       int64
+	Utime       int64
+}
+
+type FeeRuleDAO interface {
+	Insert(ctx context.Context, r FeeRule) (int64, error)
+	// Update 不改原来的规则，而是插入一个新的版本，返回新版本的 id。
+	// 原来的规则不存在或者已经不是现在的版本了，返回 ErrRecordNotFound
+	Update(ctx context.Context, r FeeRule) (int64, error)
+	GetById(ctx context.Context, id int64) (FeeRule, error)
+	// FindCandidates biz 或者 targetUid 匹配，或者没有限制的规则
+	FindCandidates(ctx context.Context, biz string, targetUid int64) ([]FeeRule, error)
+	// List 现在的版本，不包括被修改掉的
+	List(ctx context.Context, offset, limit int) ([]FeeRule, error)
+}
+
+type FeeRule struct {
+	Id   int64  `gorm:"primaryKey,autoIncrement"`
+	Name string `gorm:"type:varchar(128)"`
+	Type uint8
+	// 空字符串就是所有业务
+	Biz       string `gorm:"type:varchar(128);index:idx_biz_target_uid"`
+	TargetUid int64  `gorm:"index:idx_biz_target_uid"`
+	Currency  string `gorm:"type:varchar(8)"`
+	Rate      int64
+	Fixed     int64
+	// Tiers JSON 格式的 []domain.FeeTier
+	Tiers     string `gorm:"type:varchar(1024)"`
+	Min       int64
+	Max       int64
+	Priority  int
+	StartTime int64
+	// EndTime 0 代表一直有效
+	EndTime int64
+	// RetireTime 被新版本替换掉的时间，0 代表是现在的版本
+	RetireTime int64
+	Ctime      int64
+	Utime      int64
+}
+
+// MemberDAO 付费会员，方案、订阅和订单
+type MemberDAO interface {
+	InsertPlan(ctx context.Context, p MemberPlan) (int64, error)
+	// UpdatePlan 只能修改自己的方案
+	UpdatePlan(ctx context.Context, p MemberPlan) error
+	GetPlan(ctx context.Context, id int64) (MemberPlan, error)
+	FindPlansByCreator(ctx context.Context, creatorUid int64) ([]MemberPlan, error)
+
+	InsertOrder(ctx context.Context, o MemberOrder) (int64, error)
+	// InsertRenewOrder 插入续费订单并且记在订阅上，
+	// 订阅上已经有没付钱的续费订单的话返回 ErrSubscriptionStatus
+	InsertRenewOrder(ctx context.Context, o MemberOrder) (int64, error)
+	GetOrder(ctx context.Context, oid int64) (MemberOrder, error)
+	UpdateOrderCodeURL(ctx context.Context, oid int64, codeURL string) error
+	// PayOrder 标记订单已经付钱，并且把订阅延长一个周期。
+	// nextPeriodEnd 根据原本的周期结束时间算出新的，没有订阅的时候传入 0。
+	// 订单已经处理过的话什么也不做，所以重复调用是安全的
+	PayOrder(ctx context.Context, oid int64, nextPeriodEnd func(periodEnd int64) int64) error
+	// FailOrder 标记订单失败。如果是续费订单，记一次失败，nextRenewTime 之后才会生成新的
+	FailOrder(ctx context.Context, oid int64, nextRenewTime int64) error
+	// RefundOrder 标记已经付钱的订单全额退款，并且把订阅缩短一个周期，
+	// prevPeriodEnd 根据现在的周期结束时间算出退款之后的。缩短之后已经结束的订阅直接过期。
+	// 订单不是已经付钱的状态的话什么也不做，所以重复调用是安全的
+	RefundOrder(ctx context.Context, oid int64, prevPeriodEnd func(periodEnd int64) int64) error
+
+	GetSubscription(ctx context.Context, uid int64, creatorUid int64) (Subscription, error)
+	// CancelSubscription 只有 Active 和 PastDue 的订阅可以取消，否则返回 ErrSubscriptionStatus
+	CancelSubscription(ctx context.Context, uid int64, creatorUid int64) error
+	// FindDueSubscriptions 没有过期并且周期在 before 之前结束的订阅，按照 id 分页
+	FindDueSubscriptions(ctx context.Context, before int64, afterId int64, limit int) ([]Subscription, error)
+	// UpdateSubscriptionStatus 状态是 from 并且周期还是 periodEnd 的时候才会更新，
+	// 付了钱之后状态可能还是 Active，但是周期变了，这个时候返回 ErrSubscriptionStatus
+	UpdateSubscriptionStatus(ctx context.Context, id int64, periodEnd int64, from uint8, to uint8) error
+}
+
+type MemberPlan struct {
+	Id         int64  `gorm:"primaryKey,autoIncrement"`
+	CreatorUid int64  `gorm:"index"`
+	Name       string `gorm:"type:varchar(128)"`
+	// Price 每个月的价格
+	Price    int64
+	Currency string `gorm:"type:varchar(8)"`
+	Status   uint8
+	Ctime    int64
+	Utime    int64
+}
+
+// Subscription 一个用户对一个作者只有一条订阅
+type Subscription struct {
+	Id         int64 `gorm:"primaryKey,autoIncrement"`
+	Uid        int64 `gorm:"uniqueIndex:uid_creator_uid"`
+	CreatorUid int64 `gorm:"uniqueIndex:uid_creator_uid"`
+	PlanId     int64
+	Status     uint8
+	// PeriodEnd 续费的任务按照它来找快要到期的订阅
+	PeriodEnd     int64 `gorm:"index"`
+	RenewOrderId  int64
+	RenewFailures int
+	NextRenewTime int64
+	Ctime         int64
+	Utime         int64
+}
+
+type MemberOrder struct {
+	Id             int64 `gorm:"primaryKey,autoIncrement"`
+	Uid            int64 `gorm:"index"`
+	CreatorUid     int64
+	PlanId         int64
+	SubscriptionId int64
+	Amount         int64
+	Currency       string `gorm:"type:varchar(8)"`
+	Status         uint8
+	CodeURL        string `gorm:"type:varchar(512)"`
+	Ctime          int64
+	Utime          int64
 }
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gitee.com/geekbang/basic-go/webook/reward/domain"
+	"gitee.com/geekbang/basic-go/webook/reward/repository/dao"
+	"time"
+)
+
+var ErrFeeRuleNotFound = dao.ErrRecordNotFound
+
+type feeRuleRepository struct {
+	dao dao.FeeRuleDAO
+}
+
+func NewFeeRuleRepository(dao dao.FeeRuleDAO) FeeRuleRepository {
+	return &feeRuleRepository{dao: dao}
+}
+
+func (repo *feeRuleRepository) SaveFeeRule(ctx context.Context, r domain.FeeRule) (int64, error) {
+	entity, err := repo.toEntity(r)
+	if err != nil {
+		return 0, err
+	}
+	if r.Id > 0 {
+		return repo.dao.Update(ctx, entity)
+	}
+	return repo.dao.Insert(ctx, entity)
+}
+
+func (repo *feeRuleRepository) GetFeeRule(ctx context.Context, id int64) (domain.FeeRule, error) {
+	r, err := repo.dao.GetById(ctx, id)
+	if err != nil {
+		return domain.FeeRule{}, err
+	}
+	return repo.toDomain(r)
+}
+
+func (repo *feeRuleRepository) FindCandidates(ctx context.Context, biz string, targetUid int64) ([]domain.FeeRule, error) {
+	rules, err := repo.dao.FindCandidates(ctx, biz, targetUid)
+	if err != nil {
+		return nil, err
+	}
+	return repo.toDomains(rules)
+}
+
+func (repo *feeRuleRepository) ListFeeRules(ctx context.Context, offset, limit int) ([]domain.FeeRule, error) {
+	rules, err := repo.dao.List(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return repo.toDomains(rules)
+}
+
+// toDomains 有一条规则的数据坏了就返回错误，不能悄悄地少算一个规则
+func (repo *feeRuleRepository) toDomains(rules []dao.FeeRule) ([]domain.FeeRule, error) {
+	res := make([]domain.FeeRule, 0, len(rules))
+	for _, r := range rules {
+		dr, err := repo.toDomain(r)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, dr)
+	}
+	return res, nil
+}
+
+func (repo *feeRuleRepository) toEntity(r domain.FeeRule) (dao.FeeRule, error) {
+	var tiers []byte
+	if len(r.Tiers) > 0 {
+		var err error
+		tiers, err = json.Marshal(r.Tiers)
+		if err != nil {
+			return dao.FeeRule{}, err
+		}
+	}
+	return dao.FeeRule{
+		Id:        r.Id,
+		Name:      r.Name,
+		Type:      r.Type.AsUint8(),
+		Biz:       r.Biz,
+		TargetUid: r.TargetUid,
+		Currency:  r.Currency,
+		Rate:      r.Rate,
+		Fixed:     r.Fixed,
+		Tiers:     string(tiers),
+		Min:       r.Min,
+		Max:       r.Max,
+		Priority:  r.Priority,
+		StartTime: repo.toMilli(r.StartTime),
+		EndTime:   repo.toMilli(r.EndTime),
+	}, nil
+}
+
+func (repo *feeRuleRepository) toDomain(r dao.FeeRule) (domain.FeeRule, error) {
+	var tiers []domain.FeeTier
+	if r.Tiers != "" {
+		err := json.Unmarshal([]byte(r.Tiers), &tiers)
+		if err != nil {
+			return domain.FeeRule{}, fmt.Errorf("抽成规则 %d 的阶梯数据不对 %w", r.Id, err)
+		}
+	}
+	return domain.FeeRule{
+		Id:         r.Id,
+		Name:       r.Name,
+		Type:       domain.FeeRuleType(r.Type),
+		Biz:        r.Biz,
+		TargetUid:  r.TargetUid,
+		Currency:   r.Currency,
+		Rate:       r.Rate,
+		Fixed:      r.Fixed,
+		Tiers:      tiers,
+		Min:        r.Min,
+		Max:        r.Max,
+		Priority:   r.Priority,
+		StartTime:  repo.toTime(r.StartTime),
+		EndTime:    repo.toTime(r.EndTime),
+		RetireTime: repo.toTime(r.RetireTime),
+		Ctime:      time.UnixMilli(r.Ctime),
+		Utime:      time.UnixMilli(r.Utime),
+	}, nil
+}
+
+// toMilli 零值代表不限制，存 0
+func (repo *feeRuleRepository) toMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func (repo *feeRuleRepository) toTime(t int64) time.Time {
+	if t == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(t)
+}
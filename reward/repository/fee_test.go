@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/reward/domain"
+	"gitee.com/geekbang/basic-go/webook/reward/repository/dao"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestFeeRuleRepository_FindCandidates(t *testing.T) {
+	testCases := []struct {
+		name  string
+		rules []dao.FeeRule
+
+		want    []domain.FeeRule
+		wantErr bool
+	}{
+		{
+			name: "阶梯和版本",
+			rules: []dao.FeeRule{{Id: 1, Type: domain.FeeRuleTypeTiered.AsUint8(), Currency: "CNY",
+				Tiers:      `[{"UpTo":1000,"Rate":1000},{"UpTo":0,"Rate":500}]`,
+				RetireTime: 3000, Ctime: 1000, Utime: 3000}},
+			want: []domain.FeeRule{{Id: 1, Type: domain.FeeRuleTypeTiered, Currency: "CNY",
+				Tiers:      []domain.FeeTier{{UpTo: 1000, Rate: 1000}, {Rate: 500}},
+				RetireTime: time.UnixMilli(3000), Ctime: time.UnixMilli(1000), Utime: time.UnixMilli(3000)}},
+		},
+		{
+			// 少了一个规则的话，抽成就按照别的规则算了
+			name: "阶梯的数据坏了",
+			rules: []dao.FeeRule{{Id: 1, Type: domain.FeeRuleTypeTiered.AsUint8(), Currency: "CNY",
+				Tiers: `[{"UpTo":1000,`}},
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := NewFeeRuleRepository(&fakeFeeRuleDAO{rules: tc.rules})
+			rules, err := repo.FindCandidates(context.Background(), "article", 123)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, rules)
+		})
+	}
+}
+
+type fakeFeeRuleDAO struct {
+	dao.FeeRuleDAO
+	rules []dao.FeeRule
+}
+
+func (f *fakeFeeRuleDAO) FindCandidates(ctx context.Context, biz string, targetUid int64) ([]dao.FeeRule, error) {
+	return f.rules, nil
+}
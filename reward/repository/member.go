@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/reward/domain"
+	"gitee.com/geekbang/basic-go/webook/reward/repository/dao"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+)
+
+var (
+	ErrMemberNotFound     = dao.ErrRecordNotFound
+	ErrSubscriptionStatus = dao.ErrSubscriptionStatus
+)
+
+type memberRepository struct {
+	dao dao.MemberDAO
+}
+
+func NewMemberRepository(dao dao.MemberDAO) MemberRepository {
+	return &memberRepository{dao: dao}
+}
+
+func (repo *memberRepository) SavePlan(ctx context.Context, p domain.MemberPlan) (int64, error) {
+	entity := dao.MemberPlan{
+		Id:         p.Id,
+		CreatorUid: p.CreatorUid,
+		Name:       p.Name,
+		Price:      p.Price,
+		Currency:   p.Currency,
+		Status:     p.Status.AsUint8(),
+	}
+	if p.Id > 0 {
+		return p.Id, repo.dao.UpdatePlan(ctx, entity)
+	}
+	return repo.dao.InsertPlan(ctx, entity)
+}
+
+func (repo *memberRepository) GetPlan(ctx context.Context, id int64) (domain.MemberPlan, error) {
+	p, err := repo.dao.GetPlan(ctx, id)
+	if err != nil {
+		return domain.MemberPlan{}, err
+	}
+	return repo.planToDomain(p), nil
+}
+
+func (repo *memberRepository) FindPlansByCreator(ctx context.Context, creatorUid int64) ([]domain.MemberPlan, error) {
+	plans, err := repo.dao.FindPlansByCreator(ctx, creatorUid)
+	return slice.Map(plans, func(idx int, src dao.MemberPlan) domain.MemberPlan {
+		return repo.planToDomain(src)
+	}), err
+}
+
+func (repo *memberRepository) CreateOrder(ctx context.Context, o domain.MemberOrder) (int64, error) {
+	return repo.dao.InsertOrder(ctx, repo.orderToEntity(o))
+}
+
+func (repo *memberRepository) CreateRenewOrder(ctx context.Context, o domain.MemberOrder) (int64, error) {
+	return repo.dao.InsertRenewOrder(ctx, repo.orderToEntity(o))
+}
+
+func (repo *memberRepository) GetOrder(ctx context.Context, oid int64) (domain.MemberOrder, error) {
+	o, err := repo.dao.GetOrder(ctx, oid)
+	if err != nil {
+		return domain.MemberOrder{}, err
+	}
+	return domain.MemberOrder{
+		Id:             o.Id,
+		Uid:            o.Uid,
+		CreatorUid:     o.CreatorUid,
+		PlanId:         o.PlanId,
+		SubscriptionId: o.SubscriptionId,
+		Amt:            o.Amount,
+		Currency:       o.Currency,
+		Status:         domain.MemberOrderStatus(o.Status),
+		CodeURL:        o.CodeURL,
+		Ctime:          time.UnixMilli(o.Ctime),
+		Utime:          time.UnixMilli(o.Utime),
+	}, nil
+}
+
+func (repo *memberRepository) UpdateOrderCodeURL(ctx context.Context, oid int64, codeURL string) error {
+	return repo.dao.UpdateOrderCodeURL(ctx, oid, codeURL)
+}
+
+func (repo *memberRepository) PayOrder(ctx context.Context, oid int64, paidAt time.Time) error {
+	return repo.dao.PayOrder(ctx, oid, func(periodEnd int64) int64 {
+		var end time.Time
+		if periodEnd > 0 {
+			end = time.UnixMilli(periodEnd)
+		}
+		return domain.NextPeriodEnd(end, paidAt).UnixMilli()
+	})
+}
+
+func (repo *memberRepository) FailOrder(ctx context.Context, oid int64) error {
+	return repo.dao.FailOrder(ctx, oid, time.Now().Add(domain.MemberRenewRetryInterval).UnixMilli())
+}
+
+func (repo *memberRepository) RefundOrder(ctx context.Context, oid int64) error {
+	return repo.dao.RefundOrder(ctx, oid, func(periodEnd int64) int64 {
+		return domain.RefundPeriodEnd(time.UnixMilli(periodEnd)).UnixMilli()
+	})
+}
+
+func (repo *memberRepository) GetSubscription(ctx context.Context, uid int64, creatorUid int64) (domain.Subscription, error) {
+	sub, err := repo.dao.GetSubscription(ctx, uid, creatorUid)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	return repo.subToDomain(sub), nil
+}
+
+func (repo *memberRepository) CancelSubscription(ctx context.Context, uid int64, creatorUid int64) error {
+	return repo.dao.CancelSubscription(ctx, uid, creatorUid)
+}
+
+func (repo *memberRepository) FindDueSubscriptions(ctx context.Context,
+	before time.Time, afterId int64, limit int) ([]domain.Subscription, error) {
+	subs, err := repo.dao.FindDueSubscriptions(ctx, before.UnixMilli(), afterId, limit)
+	return slice.Map(subs, func(idx int, src dao.Subscription) domain.Subscription {
+		return repo.subToDomain(src)
+	}), err
+}
+
+func (repo *memberRepository) UpdateSubscriptionStatus(ctx context.Context,
+	sub domain.Subscription, to domain.SubscriptionStatus) error {
+	return repo.dao.UpdateSubscriptionStatus(ctx, sub.Id, sub.PeriodEnd.UnixMilli(),
+		sub.Status.AsUint8(), to.AsUint8())
+}
+
+func (repo *memberRepository) planToDomain(p dao.MemberPlan) domain.MemberPlan {
+	return domain.MemberPlan{
+		Id:         p.Id,
+		CreatorUid: p.CreatorUid,
+		Name:       p.Name,
+		Price:      p.Price,
+		Currency:   p.Currency,
+		Status:     domain.MemberPlanStatus(p.Status),
+		Ctime:      time.UnixMilli(p.Ctime),
+		Utime:      time.UnixMilli(p.Utime),
+	}
+}
+
+func (repo *memberRepository) orderToEntity(o domain.MemberOrder) dao.MemberOrder {
+	return dao.MemberOrder{
+		Id:             o.Id,
+		Uid:            o.Uid,
+		CreatorUid:     o.CreatorUid,
+		PlanId:         o.PlanId,
+		SubscriptionId: o.SubscriptionId,
+		Amount:         o.Amt,
+		Currency:       o.Currency,
+		Status:         o.Status.AsUint8(),
+		CodeURL:        o.CodeURL,
+	}
+}
+
+func (repo *memberRepository) subToDomain(sub dao.Subscription) domain.Subscription {
+	return domain.Subscription{
+		Id:            sub.Id,
+		Uid:           sub.Uid,
+		CreatorUid:    sub.CreatorUid,
+		PlanId:        sub.PlanId,
+		Status:        domain.SubscriptionStatus(sub.Status),
+		PeriodEnd:     time.UnixMilli(sub.PeriodEnd),
+		RenewOrderId:  sub.RenewOrderId,
+		RenewFailures: sub.RenewFailures,
+		NextRenewTime: time.UnixMilli(sub.NextRenewTime),
+		Ctime:         time.UnixMilli(sub.Ctime),
+		Utime:         time.UnixMilli(sub.Utime),
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"gitee.com/geekbang/basic-go/webook/reward/domain"
 	"gitee.com/geekbang/basic-go/webook/reward/repository/cache"
 	"gitee.com/geekbang/basic-go/webook/reward/repository/dao"
+	"time"
 )
 
+var ErrFeeComputed = dao.ErrFeeComputed
+
 type rewardRepository struct {
 	dao   dao.RewardDAO
 	cache cache.RewardCache
@@ -24,6 +27,10 @@ func (repo *rewardRepository) UpdateStatus(ctx context.Context, rid int64, statu
 	return repo.dao.UpdateStatus(ctx, rid, status.AsUint8())
 }
 
+func (repo *rewardRepository) UpdateFee(ctx context.Context, rid int64, feeRuleId int64, fee int64) error {
+	return repo.dao.UpdateFee(ctx, rid, feeRuleId, fee)
+}
+
 func (repo *rewardRepository) GetCachedCodeURL(ctx context.Context, r domain.Reward) (domain.CodeURL, error) {
 	return repo.cache.GetCachedCodeURL(ctx, r)
 }
@@ -32,6 +39,10 @@ func (repo *rewardRepository) CachedCodeURL(ctx context.Context, cu domain.CodeU
 	return repo.cache.CachedCodeURL(ctx, cu, r)
 }
 
+func (repo *rewardRepository) EvictCachedCodeURL(ctx context.Context, r domain.Reward) error {
+	return repo.cache.EvictCodeURL(ctx, r)
+}
+
 func (repo *rewardRepository) CreateReward(
 	ctx context.Context,
 	reward domain.Reward) (int64, error) {
@@ -47,10 +58,14 @@ func (repo *rewardRepository) toEntity(r domain.Reward) dao.Reward {
 		TargetUid: r.Target.Uid,
 		Uid:       r.Uid,
 		Amount:    r.Amt,
+		Currency:  r.Currency,
 	}
 }
 
 func (repo *rewardRepository) toDomain(r dao.Reward) domain.Reward {
+	if r.Currency == "" {
+		r.Currency = "CNY"
+	}
 	return domain.Reward{
 		Id:  r.Id,
 		Uid: r.Uid,
@@ -58,10 +73,15 @@ func (repo *rewardRepository) toDomain(r dao.Reward) domain.Reward {
 			Biz:     r.Biz,
 			BizId:   r.BizId,
 			BizName: r.BizName,
-			Uid:     r.Uid,
+			Uid:     r.TargetUid,
 		},
-		Amt:    r.Amount,
-		Status: domain.RewardStatus(r.Status),
+		Amt:         r.Amount,
+		Currency:    r.Currency,
+		Status:      domain.RewardStatus(r.Status),
+		FeeRuleId:   r.FeeRuleId,
+		Fee:         r.Fee,
+		FeeComputed: r.FeeComputed,
+		Ctime:       time.UnixMilli(r.Ctime),
 	}
 }
 
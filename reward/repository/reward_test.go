@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"gitee.com/geekbang/basic-go/webook/reward/domain"
+	"gitee.com/geekbang/basic-go/webook/reward/repository/dao"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRewardRepository_toDomain(t *testing.T) {
+	repo := &rewardRepository{}
+	r := domain.Reward{
+		Id:  1,
+		Uid: 1024,
+		Target: domain.Target{
+			Biz:     "article",
+			BizId:   1,
+			BizName: "测试文章",
+			Uid:     2048,
+		},
+		Amt:      100,
+		Currency: "CNY",
+		Status:   domain.RewardStatusPayed,
+		Ctime:    time.UnixMilli(1000),
+	}
+	entity := repo.toEntity(r)
+	// 打赏的人和被打赏的人不能混在一起，不然入账的时候钱就回到打赏的人那里了
+	assert.Equal(t, int64(1024), entity.Uid)
+	assert.Equal(t, int64(2048), entity.TargetUid)
+	entity.Id = 1
+	entity.Ctime = 1000
+	assert.Equal(t, r, repo.toDomain(entity))
+
+	// 以前的数据没有币种，都是人民币
+	assert.Equal(t, "CNY", repo.toDomain(dao.Reward{}).Currency)
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/reward/domain"
+	"gitee.com/geekbang/basic-go/webook/reward/repository/cache"
+	"time"
+)
+
+type rewardStatsRepository struct {
+	cache cache.RewardStatsCache
+}
+
+func NewRewardStatsRepository(c cache.RewardStatsCache) RewardStatsRepository {
+	return &rewardStatsRepository{cache: c}
+}
+
+func (repo *rewardStatsRepository) IncrStats(ctx context.Context, r domain.Reward, at time.Time) error {
+	return repo.cache.IncrStats(ctx, r, at)
+}
+
+func (repo *rewardStatsRepository) RefundStats(ctx context.Context, r domain.Reward, refundNO string,
+	amt int64, fullyRefunded bool, at time.Time) error {
+	return repo.cache.RefundStats(ctx, r, refundNO, amt, fullyRefunded, at)
+}
+
+func (repo *rewardStatsRepository) GetTargetStats(ctx context.Context, biz string, bizId int64,
+	currency string, n int) (domain.TargetRewardStats, error) {
+	return repo.cache.GetTargetStats(ctx, biz, bizId, currency, n)
+}
+
+func (repo *rewardStatsRepository) GetCreatorStats(ctx context.Context, uid int64,
+	currency string, day time.Time) (domain.CreatorRewardStats, error) {
+	return repo.cache.GetCreatorStats(ctx, uid, currency, day)
+}
+
+func (repo *rewardStatsRepository) TopSupporters(ctx context.Context, uid int64,
+	currency string, n int) ([]domain.Supporter, error) {
+	return repo.cache.TopSupporters(ctx, uid, currency, n)
+}
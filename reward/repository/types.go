@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"gitee.com/geekbang/basic-go/webook/reward/domain"
+	"time"
 )
 
 type RewardRepository interface {
@@ -13,5 +14,56 @@ type RewardRepository interface {
 	// 是希望调用者明白这个是我们缓存下来的，属于业务逻辑的一部分
 	GetCachedCodeURL(ctx context.Context, r domain.Reward) (domain.CodeURL, error)
 	CachedCodeURL(ctx context.Context, cu domain.CodeURL, r domain.Reward) error
+	// EvictCachedCodeURL 订单关闭之后二维码就付不了钱了，不能再给用户。
+	// 同一个用户后面新的打赏的二维码不会被删掉
+	EvictCachedCodeURL(ctx context.Context, r domain.Reward) error
 	UpdateStatus(ctx context.Context, rid int64, status domain.RewardStatus) error
+	// UpdateFee 记录平台抽成和用的规则，已经记过了返回 ErrFeeComputed
+	UpdateFee(ctx context.Context, rid int64, feeRuleId int64, fee int64) error
+}
+
+type FeeRuleRepository interface {
+	// SaveFeeRule Id 为 0 就是新建，不然就是生成一个新的版本，返回新版本的 id
+	SaveFeeRule(ctx context.Context, r domain.FeeRule) (int64, error)
+	GetFeeRule(ctx context.Context, id int64) (domain.FeeRule, error)
+	// FindCandidates 可能适用于 biz 和 targetUid 的规则，没有判断生效时间和币种
+	FindCandidates(ctx context.Context, biz string, targetUid int64) ([]domain.FeeRule, error)
+	ListFeeRules(ctx context.Context, offset, limit int) ([]domain.FeeRule, error)
+}
+
+// RewardStatsRepository 打赏的统计和排行榜，目前只放在 Redis 里面
+type RewardStatsRepository interface {
+	// IncrStats 支付成功的打赏计入统计，重复调用只会统计一次
+	IncrStats(ctx context.Context, r domain.Reward, at time.Time) error
+	// RefundStats 退款的钱从统计里面扣掉，同一个 refundNO 只会扣一次
+	RefundStats(ctx context.Context, r domain.Reward, refundNO string,
+		amt int64, fullyRefunded bool, at time.Time) error
+	GetTargetStats(ctx context.Context, biz string, bizId int64, currency string, n int) (domain.TargetRewardStats, error)
+	GetCreatorStats(ctx context.Context, uid int64, currency string, day time.Time) (domain.CreatorRewardStats, error)
+	TopSupporters(ctx context.Context, uid int64, currency string, n int) ([]domain.Supporter, error)
+}
+
+type MemberRepository interface {
+	// SavePlan Id 为 0 就是新建
+	SavePlan(ctx context.Context, p domain.MemberPlan) (int64, error)
+	GetPlan(ctx context.Context, id int64) (domain.MemberPlan, error)
+	FindPlansByCreator(ctx context.Context, creatorUid int64) ([]domain.MemberPlan, error)
+
+	CreateOrder(ctx context.Context, o domain.MemberOrder) (int64, error)
+	// CreateRenewOrder 订阅上已经有没付钱的续费订单的话返回 ErrSubscriptionStatus
+	CreateRenewOrder(ctx context.Context, o domain.MemberOrder) (int64, error)
+	GetOrder(ctx context.Context, oid int64) (domain.MemberOrder, error)
+	UpdateOrderCodeURL(ctx context.Context, oid int64, codeURL string) error
+	// PayOrder 在 paidAt 付了钱，订阅延长一个周期，重复调用是安全的
+	PayOrder(ctx context.Context, oid int64, paidAt time.Time) error
+	// FailOrder 如果是续费订单，MemberRenewRetryInterval 之后才会生成新的
+	FailOrder(ctx context.Context, oid int64) error
+	// RefundOrder 全额退款，收回这个订单买的周期，重复调用是安全的
+	RefundOrder(ctx context.Context, oid int64) error
+
+	GetSubscription(ctx context.Context, uid int64, creatorUid int64) (domain.Subscription, error)
+	CancelSubscription(ctx context.Context, uid int64, creatorUid int64) error
+	FindDueSubscriptions(ctx context.Context, before time.Time, afterId int64, limit int) ([]domain.Subscription, error)
+	// UpdateSubscriptionStatus sub 在这期间付了钱或者改了状态的话返回 ErrSubscriptionStatus
+	UpdateSubscriptionStatus(ctx context.Context, sub domain.Subscription, to domain.SubscriptionStatus) error
 }
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"gitee.com/geekbang/basic-go/webook/reward/domain"
+	"gitee.com/geekbang/basic-go/webook/reward/repository"
+)
+
+var ErrFeeRuleNotFound = repository.ErrFeeRuleNotFound
+
+type feeRuleService struct {
+	repo repository.FeeRuleRepository
+}
+
+func NewFeeRuleService(repo repository.FeeRuleRepository) FeeRuleService {
+	return &feeRuleService{repo: repo}
+}
+
+func (s *feeRuleService) SaveFeeRule(ctx context.Context, r domain.FeeRule) (int64, error) {
+	err := r.Validate()
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.SaveFeeRule(ctx, r)
+}
+
+func (s *feeRuleService) GetFeeRule(ctx context.Context, id int64) (domain.FeeRule, error) {
+	return s.repo.GetFeeRule(ctx, id)
+}
+
+func (s *feeRuleService) ListFeeRules(ctx context.Context, offset, limit int) ([]domain.FeeRule, error) {
+	return s.repo.ListFeeRules(ctx, offset, limit)
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	pmtv1 "gitee.com/geekbang/basic-go/webook/api/proto/gen/payment/v1"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"gitee.com/geekbang/basic-go/webook/reward/domain"
+	"gitee.com/geekbang/basic-go/webook/reward/repository"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMemberNotFound = repository.ErrMemberNotFound
+	// ErrSubscriptionStatus 订阅的状态不对，比如取消一个已经过期的订阅
+	ErrSubscriptionStatus = repository.ErrSubscriptionStatus
+	// ErrMemberPlanOffline 方案已经下线，不能再订阅
+	ErrMemberPlanOffline = errors.New("会员方案已经下线")
+	// ErrAlreadySubscribed 已经订阅了，到期会自动生成续费订单
+	ErrAlreadySubscribed = errors.New("已经是会员了")
+	ErrIllegalSubscribe  = errors.New("不能订阅自己")
+)
+
+const memberBizTradeNOPrefix = "member-"
+
+type memberService struct {
+	client pmtv1.WechatPaymentServiceClient
+	repo   repository.MemberRepository
+	l      logger.LoggerV1
+	// channel 和打赏用同一个渠道
+	channel pmtv1.PaymentChannel
+	// batchSize 续费的时候每次处理多少个订阅
+	batchSize int
+}
+
+func NewMemberService(client pmtv1.WechatPaymentServiceClient,
+	repo repository.MemberRepository,
+	l logger.LoggerV1,
+	channel pmtv1.PaymentChannel) MemberService {
+	return &memberService{client: client, repo: repo, l: l, channel: channel, batchSize: 100}
+}
+
+func (s *memberService) SavePlan(ctx context.Context, p domain.MemberPlan) (int64, error) {
+	err := p.Validate()
+	if err != nil {
+		return 0, err
+	}
+	// 和打赏一样，渠道收不了的币种订阅了也付不了钱
+	if !supportCurrency(s.channel, p.Currency) {
+		return 0, ErrUnsupportedCurrency
+	}
+	if p.Status == domain.MemberPlanStatusUnknown {
+		p.Status = domain.MemberPlanStatusActive
+	}
+	return s.repo.SavePlan(ctx, p)
+}
+
+func (s *memberService) ListPlans(ctx context.Context, creatorUid int64) ([]domain.MemberPlan, error) {
+	return s.repo.FindPlansByCreator(ctx, creatorUid)
+}
+
+func (s *memberService) Subscribe(ctx context.Context, uid int64, planId int64) (domain.MemberOrder, error) {
+	plan, err := s.repo.GetPlan(ctx, planId)
+	if err != nil {
+		return domain.MemberOrder{}, err
+	}
+	if plan.CreatorUid == uid {
+		return domain.MemberOrder{}, ErrIllegalSubscribe
+	}
+	sub, err := s.repo.GetSubscription(ctx, uid, plan.CreatorUid)
+	switch {
+	case err == nil:
+		if sub.Status == domain.SubscriptionStatusActive {
+			return domain.MemberOrder{}, ErrAlreadySubscribed
+		}
+		if sub.RenewOrderId > 0 {
+			// 没有续费的，让用户付之前的续费订单就可以
+			o, err := s.repo.GetOrder(ctx, sub.RenewOrderId)
+			if err == nil && o.Status == domain.MemberOrderStatusInit && o.CodeURL != "" {
+				return o, nil
+			}
+		}
+	case !errors.Is(err, ErrMemberNotFound):
+		return domain.MemberOrder{}, err
+	}
+	// 重新订阅的时候才检查，已经订阅的人在方案下线之后还可以续费
+	if plan.Status != domain.MemberPlanStatusActive {
+		return domain.MemberOrder{}, ErrMemberPlanOffline
+	}
+	o := domain.MemberOrder{
+		Uid:        uid,
+		CreatorUid: plan.CreatorUid,
+		PlanId:     plan.Id,
+		Amt:        plan.Price,
+		Currency:   plan.Currency,
+		Status:     domain.MemberOrderStatusInit,
+	}
+	o.Id, err = s.repo.CreateOrder(ctx, o)
+	if err != nil {
+		return domain.MemberOrder{}, err
+	}
+	o.CodeURL, err = s.prePay(ctx, o, plan)
+	return o, err
+}
+
+// prePay 下单拿到二维码，失败了就把订单关掉
+func (s *memberService) prePay(ctx context.Context, o domain.MemberOrder, plan domain.MemberPlan) (string, error) {
+	resp, err := s.client.NativePrePay(ctx, &pmtv1.PrePayRequest{
+		Amt: &pmtv1.Amount{
+			Total:    o.Amt,
+			Currency: o.Currency,
+		},
+		BizTradeNo:  s.bizTradeNO(o.Id),
+		Description: fmt.Sprintf("会员-%s", plan.Name),
+		Channel:     s.channel,
+	})
+	if err != nil {
+		err1 := s.repo.FailOrder(ctx, o.Id)
+		if err1 != nil {
+			s.l.Error("关闭会员订单失败", logger.Error(err1),
+				logger.Int64("oid", o.Id))
+		}
+		return "", err
+	}
+	err = s.repo.UpdateOrderCodeURL(ctx, o.Id, resp.CodeUrl)
+	if err != nil {
+		// 用户这一次还是可以付钱的，只是查订单的时候看不到二维码
+		s.l.Error("保存会员订单的二维码失败", logger.Error(err),
+			logger.Int64("oid", o.Id))
+	}
+	return resp.CodeUrl, nil
+}
+
+func (s *memberService) GetOrder(ctx context.Context, oid int64, uid int64) (domain.MemberOrder, error) {
+	o, err := s.repo.GetOrder(ctx, oid)
+	if err != nil {
+		return domain.MemberOrder{}, err
+	}
+	if o.Uid != uid {
+		return domain.MemberOrder{}, errors.New("非法访问别人的会员订单")
+	}
+	return o, nil
+}
+
+func (s *memberService) GetSubscription(ctx context.Context, uid int64, creatorUid int64) (domain.Subscription, error) {
+	return s.repo.GetSubscription(ctx, uid, creatorUid)
+}
+
+func (s *memberService) CancelSubscription(ctx context.Context, uid int64, creatorUid int64) error {
+	return s.repo.CancelSubscription(ctx, uid, creatorUid)
+}
+
+func (s *memberService) HandlePayment(ctx context.Context, bizTradeNO string,
+	status domain.MemberOrderStatus, paidAt time.Time) error {
+	oid, err := s.toOid(bizTradeNO)
+	if err != nil {
+		return err
+	}
+	switch status {
+	case domain.MemberOrderStatusPaid:
+		return s.repo.PayOrder(ctx, oid, paidAt)
+	case domain.MemberOrderStatusFailed:
+		return s.repo.FailOrder(ctx, oid)
+	default:
+		return nil
+	}
+}
+
+func (s *memberService) HandleRefund(ctx context.Context, bizTradeNO string, fullyRefunded bool) error {
+	if !fullyRefunded {
+		// 部分退款是和用户协商的补偿，会员还是照常
+		return nil
+	}
+	oid, err := s.toOid(bizTradeNO)
+	if err != nil {
+		return err
+	}
+	return s.repo.RefundOrder(ctx, oid)
+}
+
+func (s *memberService) RenewDue(ctx context.Context) error {
+	now := time.Now()
+	var (
+		afterId int64
+		failed  int
+	)
+	for {
+		subs, err := s.repo.FindDueSubscriptions(ctx, now.Add(domain.MemberRenewAhead), afterId, s.batchSize)
+		if err != nil {
+			return err
+		}
+		for _, sub := range subs {
+			err = s.renew(ctx, sub, now)
+			if err != nil {
+				failed++
+				s.l.Error("处理到期的订阅失败", logger.Error(err),
+					logger.Int64("sid", sub.Id))
+			}
+		}
+		if len(subs) < s.batchSize {
+			break
+		}
+		afterId = subs[len(subs)-1].Id
+	}
+	if failed > 0 {
+		// 下一次调度会重新处理，整个过程是幂等的
+		return fmt.Errorf("%d 个订阅处理失败", failed)
+	}
+	return nil
+}
+
+// renew 推进 sub 的状态，需要续费的话生成续费订单
+func (s *memberService) renew(ctx context.Context, sub domain.Subscription, now time.Time) error {
+	next := sub.NextStatus(now)
+	if next != sub.Status {
+		err := s.repo.UpdateSubscriptionStatus(ctx, sub, next)
+		if errors.Is(err, ErrSubscriptionStatus) {
+			// 刚刚付了钱或者取消了，查出来的 sub 已经过时了，下一次调度再处理
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		sub.Status = next
+	}
+	if !sub.CanRenew(now) {
+		return nil
+	}
+	// 按照方案现在的价格续费
+	plan, err := s.repo.GetPlan(ctx, sub.PlanId)
+	if err != nil {
+		return err
+	}
+	o := domain.MemberOrder{
+		Uid:            sub.Uid,
+		CreatorUid:     sub.CreatorUid,
+		PlanId:         plan.Id,
+		SubscriptionId: sub.Id,
+		Amt:            plan.Price,
+		Currency:       plan.Currency,
+		Status:         domain.MemberOrderStatusInit,
+	}
+	o.Id, err = s.repo.CreateRenewOrder(ctx, o)
+	if errors.Is(err, ErrSubscriptionStatus) {
+		// 别的地方已经生成了
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = s.prePay(ctx, o, plan)
+	return err
+}
+
+func (s *memberService) CheckEntitlement(ctx context.Context, uid int64, creatorUid int64) (bool, error) {
+	sub, err := s.repo.GetSubscription(ctx, uid, creatorUid)
+	if errors.Is(err, ErrMemberNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return sub.Entitled(time.Now()), nil
+}
+
+func (s *memberService) bizTradeNO(oid int64) string {
+	return fmt.Sprintf("%s%d", memberBizTradeNOPrefix, oid)
+}
+
+func (s *memberService) toOid(bizTradeNO string) (int64, error) {
+	return strconv.ParseInt(strings.TrimPrefix(bizTradeNO, memberBizTradeNOPrefix), 10, 64)
+}
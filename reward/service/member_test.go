@@ -0,0 +1,450 @@
+package service
+
+import (
+	"context"
+	"errors"
+	pmtv1 "gitee.com/geekbang/basic-go/webook/api/proto/gen/payment/v1"
+	pmtmocks "gitee.com/geekbang/basic-go/webook/api/proto/gen/payment/v1/mocks"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"gitee.com/geekbang/basic-go/webook/reward/domain"
+	"gitee.com/geekbang/basic-go/webook/reward/repository"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestMemberService_SavePlan(t *testing.T) {
+	testCases := []struct {
+		name    string
+		channel pmtv1.PaymentChannel
+		p       domain.MemberPlan
+
+		wantSaved domain.MemberPlan
+		wantErr   error
+	}{
+		{
+			name:      "新建默认上线",
+			channel:   pmtv1.PaymentChannel_PaymentChannelWechat,
+			p:         domain.MemberPlan{CreatorUid: 2048, Name: "月度会员", Price: 100, Currency: "CNY"},
+			wantSaved: domain.MemberPlan{CreatorUid: 2048, Name: "月度会员", Price: 100, Currency: "CNY", Status: domain.MemberPlanStatusActive},
+		},
+		{
+			// 订阅的人付不了钱
+			name:    "微信不收美元",
+			channel: pmtv1.PaymentChannel_PaymentChannelWechat,
+			p:       domain.MemberPlan{CreatorUid: 2048, Name: "月度会员", Price: 100, Currency: "USD"},
+			wantErr: ErrUnsupportedCurrency,
+		},
+		{
+			name:      "沙箱什么币种都收",
+			channel:   pmtv1.PaymentChannel_PaymentChannelSandbox,
+			p:         domain.MemberPlan{CreatorUid: 2048, Name: "月度会员", Price: 100, Currency: "USD"},
+			wantSaved: domain.MemberPlan{CreatorUid: 2048, Name: "月度会员", Price: 100, Currency: "USD", Status: domain.MemberPlanStatusActive},
+		},
+		{
+			name:    "价格不对",
+			channel: pmtv1.PaymentChannel_PaymentChannelWechat,
+			p:       domain.MemberPlan{CreatorUid: 2048, Name: "月度会员", Currency: "CNY"},
+			wantErr: domain.ErrInvalidMemberPlan,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakeMemberRepo{}
+			svc := NewMemberService(nil, repo, logger.NewNopLogger(), tc.channel)
+			_, err := svc.SavePlan(context.Background(), tc.p)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantSaved, repo.saved)
+		})
+	}
+}
+
+func TestMemberService_Subscribe(t *testing.T) {
+	plan := domain.MemberPlan{Id: 1, CreatorUid: 2048, Name: "月度会员", Price: 100,
+		Currency: "CNY", Status: domain.MemberPlanStatusActive}
+	prePay := &pmtv1.PrePayRequest{
+		Amt:         &pmtv1.Amount{Total: 100, Currency: "CNY"},
+		BizTradeNo:  "member-1",
+		Description: "会员-月度会员",
+		Channel:     pmtv1.PaymentChannel_PaymentChannelWechat,
+	}
+	newOrder := domain.MemberOrder{Id: 1, Uid: 1024, CreatorUid: 2048, PlanId: 1,
+		Amt: 100, Currency: "CNY", Status: domain.MemberOrderStatusInit}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) pmtv1.WechatPaymentServiceClient
+		repo *fakeMemberRepo
+		uid  int64
+
+		want       domain.MemberOrder
+		wantFailed []int64
+		wantErr    error
+	}{
+		{
+			name: "第一次订阅",
+			mock: func(ctrl *gomock.Controller) pmtv1.WechatPaymentServiceClient {
+				client := pmtmocks.NewMockWechatPaymentServiceClient(ctrl)
+				client.EXPECT().NativePrePay(gomock.Any(), prePay).
+					Return(&pmtv1.NativePrePayResponse{CodeUrl: "weixin://1"}, nil)
+				return client
+			},
+			repo: &fakeMemberRepo{plan: plan, subErr: ErrMemberNotFound},
+			uid:  1024,
+			want: func() domain.MemberOrder {
+				o := newOrder
+				o.CodeURL = "weixin://1"
+				return o
+			}(),
+		},
+		{
+			name: "订阅自己",
+			mock: func(ctrl *gomock.Controller) pmtv1.WechatPaymentServiceClient {
+				return pmtmocks.NewMockWechatPaymentServiceClient(ctrl)
+			},
+			repo:    &fakeMemberRepo{plan: plan},
+			uid:     2048,
+			wantErr: ErrIllegalSubscribe,
+		},
+		{
+			name: "已经是会员了",
+			mock: func(ctrl *gomock.Controller) pmtv1.WechatPaymentServiceClient {
+				return pmtmocks.NewMockWechatPaymentServiceClient(ctrl)
+			},
+			repo:    &fakeMemberRepo{plan: plan, sub: domain.Subscription{Id: 1, Status: domain.SubscriptionStatusActive}},
+			uid:     1024,
+			wantErr: ErrAlreadySubscribed,
+		},
+		{
+			name: "付之前的续费订单",
+			mock: func(ctrl *gomock.Controller) pmtv1.WechatPaymentServiceClient {
+				return pmtmocks.NewMockWechatPaymentServiceClient(ctrl)
+			},
+			repo: &fakeMemberRepo{plan: plan,
+				sub: domain.Subscription{Id: 1, Status: domain.SubscriptionStatusPastDue, RenewOrderId: 5},
+				order: domain.MemberOrder{Id: 5, Uid: 1024, Status: domain.MemberOrderStatusInit,
+					CodeURL: "weixin://5"}},
+			uid: 1024,
+			want: domain.MemberOrder{Id: 5, Uid: 1024, Status: domain.MemberOrderStatusInit,
+				CodeURL: "weixin://5"},
+		},
+		{
+			name: "方案下线了",
+			mock: func(ctrl *gomock.Controller) pmtv1.WechatPaymentServiceClient {
+				return pmtmocks.NewMockWechatPaymentServiceClient(ctrl)
+			},
+			repo: &fakeMemberRepo{plan: func() domain.MemberPlan {
+				p := plan
+				p.Status = domain.MemberPlanStatusOffline
+				return p
+			}(), subErr: ErrMemberNotFound},
+			uid:     1024,
+			wantErr: ErrMemberPlanOffline,
+		},
+		{
+			name: "下单失败，关掉订单",
+			mock: func(ctrl *gomock.Controller) pmtv1.WechatPaymentServiceClient {
+				client := pmtmocks.NewMockWechatPaymentServiceClient(ctrl)
+				client.EXPECT().NativePrePay(gomock.Any(), prePay).
+					Return(nil, errors.New("mock error"))
+				return client
+			},
+			repo:       &fakeMemberRepo{plan: plan, subErr: ErrMemberNotFound},
+			uid:        1024,
+			want:       newOrder,
+			wantFailed: []int64{1},
+			wantErr:    errors.New("mock error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewMemberService(tc.mock(ctrl), tc.repo, logger.NewNopLogger(),
+				pmtv1.PaymentChannel_PaymentChannelWechat)
+			o, err := svc.Subscribe(context.Background(), tc.uid, 1)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, o)
+			assert.Equal(t, tc.wantFailed, tc.repo.failed)
+		})
+	}
+}
+
+func TestMemberService_HandlePayment(t *testing.T) {
+	paidAt := time.UnixMilli(100000)
+	testCases := []struct {
+		name       string
+		bizTradeNO string
+		status     domain.MemberOrderStatus
+
+		wantPaid   []int64
+		wantFailed []int64
+		wantErr    bool
+	}{
+		{
+			name:       "付钱成功",
+			bizTradeNO: "member-1",
+			status:     domain.MemberOrderStatusPaid,
+			wantPaid:   []int64{1},
+		},
+		{
+			name:       "付钱失败",
+			bizTradeNO: "member-1",
+			status:     domain.MemberOrderStatusFailed,
+			wantFailed: []int64{1},
+		},
+		{
+			name:       "别的状态不处理",
+			bizTradeNO: "member-1",
+			status:     domain.MemberOrderStatusUnknown,
+		},
+		{
+			name:       "订单号不对",
+			bizTradeNO: "member-abc",
+			status:     domain.MemberOrderStatusPaid,
+			wantErr:    true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakeMemberRepo{}
+			svc := NewMemberService(nil, repo, logger.NewNopLogger(),
+				pmtv1.PaymentChannel_PaymentChannelWechat)
+			err := svc.HandlePayment(context.Background(), tc.bizTradeNO, tc.status, paidAt)
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.wantPaid, repo.paid)
+			assert.Equal(t, tc.wantFailed, repo.failed)
+			if len(tc.wantPaid) > 0 {
+				// 按照付钱的时间算周期，而不是处理消息的时间
+				assert.Equal(t, paidAt, repo.paidAt)
+			}
+		})
+	}
+}
+
+func TestMemberService_HandleRefund(t *testing.T) {
+	testCases := []struct {
+		name          string
+		fullyRefunded bool
+
+		wantRefunded []int64
+	}{
+		{
+			name:          "全额退款，收回会员",
+			fullyRefunded: true,
+			wantRefunded:  []int64{1},
+		},
+		{
+			name: "部分退款，还是会员",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakeMemberRepo{}
+			svc := NewMemberService(nil, repo, logger.NewNopLogger(),
+				pmtv1.PaymentChannel_PaymentChannelWechat)
+			err := svc.HandleRefund(context.Background(), "member-1", tc.fullyRefunded)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantRefunded, repo.refunded)
+		})
+	}
+}
+
+func TestMemberService_RenewDue(t *testing.T) {
+	now := time.Now()
+	plan := domain.MemberPlan{Id: 1, CreatorUid: 2048, Name: "月度会员", Price: 200,
+		Currency: "CNY", Status: domain.MemberPlanStatusOffline}
+	sub := func(status domain.SubscriptionStatus, periodEnd time.Time) domain.Subscription {
+		return domain.Subscription{Id: 3, Uid: 1024, CreatorUid: 2048, PlanId: 1,
+			Status: status, PeriodEnd: periodEnd}
+	}
+	renewOrder := domain.MemberOrder{Id: 1, Uid: 1024, CreatorUid: 2048, PlanId: 1,
+		SubscriptionId: 3, Amt: 200, Currency: "CNY", Status: domain.MemberOrderStatusInit}
+	prePayOK := func(ctrl *gomock.Controller) pmtv1.WechatPaymentServiceClient {
+		client := pmtmocks.NewMockWechatPaymentServiceClient(ctrl)
+		client.EXPECT().NativePrePay(gomock.Any(), gomock.Any()).
+			Return(&pmtv1.NativePrePayResponse{CodeUrl: "weixin://1"}, nil)
+		return client
+	}
+	noPrePay := func(ctrl *gomock.Controller) pmtv1.WechatPaymentServiceClient {
+		return pmtmocks.NewMockWechatPaymentServiceClient(ctrl)
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) pmtv1.WechatPaymentServiceClient
+		repo *fakeMemberRepo
+
+		wantStatus  []domain.SubscriptionStatus
+		wantCreated []domain.MemberOrder
+		wantFailed  []int64
+		wantErr     bool
+	}{
+		{
+			// 方案下线了也可以续费，按照现在的价格
+			name:        "快到期了，生成续费订单",
+			mock:        prePayOK,
+			repo:        &fakeMemberRepo{plan: plan, due: []domain.Subscription{sub(domain.SubscriptionStatusActive, now.Add(time.Hour))}},
+			wantCreated: []domain.MemberOrder{renewOrder},
+		},
+		{
+			name:        "到期没有续费",
+			mock:        prePayOK,
+			repo:        &fakeMemberRepo{plan: plan, due: []domain.Subscription{sub(domain.SubscriptionStatusActive, now.Add(-time.Hour))}},
+			wantStatus:  []domain.SubscriptionStatus{domain.SubscriptionStatusPastDue},
+			wantCreated: []domain.MemberOrder{renewOrder},
+		},
+		{
+			name: "刚刚付了钱，不再续费",
+			mock: noPrePay,
+			repo: &fakeMemberRepo{plan: plan, statusErr: ErrSubscriptionStatus,
+				due: []domain.Subscription{sub(domain.SubscriptionStatusActive, now.Add(-time.Hour))}},
+			wantStatus: []domain.SubscriptionStatus{domain.SubscriptionStatusPastDue},
+		},
+		{
+			name: "过了宽限期",
+			mock: noPrePay,
+			repo: &fakeMemberRepo{plan: plan,
+				due: []domain.Subscription{sub(domain.SubscriptionStatusPastDue, now.Add(-domain.MemberGracePeriod-time.Hour))}},
+			wantStatus: []domain.SubscriptionStatus{domain.SubscriptionStatusExpired},
+		},
+		{
+			name: "已经有续费订单了",
+			mock: noPrePay,
+			repo: &fakeMemberRepo{plan: plan, due: []domain.Subscription{func() domain.Subscription {
+				s := sub(domain.SubscriptionStatusPastDue, now.Add(-time.Hour))
+				s.RenewOrderId = 5
+				return s
+			}()}},
+		},
+		{
+			name: "刚刚失败过，等一段时间再续费",
+			mock: noPrePay,
+			repo: &fakeMemberRepo{plan: plan, due: []domain.Subscription{func() domain.Subscription {
+				s := sub(domain.SubscriptionStatusPastDue, now.Add(-time.Hour))
+				s.RenewFailures = 1
+				s.NextRenewTime = now.Add(time.Hour)
+				return s
+			}()}},
+		},
+		{
+			name: "失败太多次，不再续费",
+			mock: noPrePay,
+			repo: &fakeMemberRepo{plan: plan, due: []domain.Subscription{func() domain.Subscription {
+				s := sub(domain.SubscriptionStatusPastDue, now.Add(-time.Hour))
+				s.RenewFailures = domain.MemberRenewMaxFailures
+				return s
+			}()}},
+		},
+		{
+			name: "下单失败",
+			mock: func(ctrl *gomock.Controller) pmtv1.WechatPaymentServiceClient {
+				client := pmtmocks.NewMockWechatPaymentServiceClient(ctrl)
+				client.EXPECT().NativePrePay(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("mock error"))
+				return client
+			},
+			repo:        &fakeMemberRepo{plan: plan, due: []domain.Subscription{sub(domain.SubscriptionStatusActive, now.Add(time.Hour))}},
+			wantCreated: []domain.MemberOrder{renewOrder},
+			wantFailed:  []int64{1},
+			wantErr:     true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewMemberService(tc.mock(ctrl), tc.repo, logger.NewNopLogger(),
+				pmtv1.PaymentChannel_PaymentChannelWechat)
+			err := svc.RenewDue(context.Background())
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.wantStatus, tc.repo.status)
+			assert.Equal(t, tc.wantCreated, tc.repo.created)
+			assert.Equal(t, tc.wantFailed, tc.repo.failed)
+		})
+	}
+}
+
+// fakeMemberRepo 新建的订单 id 从 1 开始
+type fakeMemberRepo struct {
+	repository.MemberRepository
+	plan   domain.MemberPlan
+	sub    domain.Subscription
+	subErr error
+	order  domain.MemberOrder
+	// due 第一页返回的到期订阅
+	due       []domain.Subscription
+	statusErr error
+
+	saved    domain.MemberPlan
+	created  []domain.MemberOrder
+	status   []domain.SubscriptionStatus
+	paid     []int64
+	paidAt   time.Time
+	failed   []int64
+	refunded []int64
+}
+
+func (f *fakeMemberRepo) SavePlan(ctx context.Context, p domain.MemberPlan) (int64, error) {
+	f.saved = p
+	return 1, nil
+}
+
+func (f *fakeMemberRepo) GetPlan(ctx context.Context, id int64) (domain.MemberPlan, error) {
+	return f.plan, nil
+}
+
+func (f *fakeMemberRepo) GetSubscription(ctx context.Context, uid int64, creatorUid int64) (domain.Subscription, error) {
+	return f.sub, f.subErr
+}
+
+func (f *fakeMemberRepo) GetOrder(ctx context.Context, oid int64) (domain.MemberOrder, error) {
+	return f.order, nil
+}
+
+func (f *fakeMemberRepo) CreateOrder(ctx context.Context, o domain.MemberOrder) (int64, error) {
+	return f.create(o), nil
+}
+
+func (f *fakeMemberRepo) CreateRenewOrder(ctx context.Context, o domain.MemberOrder) (int64, error) {
+	return f.create(o), nil
+}
+
+func (f *fakeMemberRepo) create(o domain.MemberOrder) int64 {
+	o.Id = int64(len(f.created) + 1)
+	f.created = append(f.created, o)
+	return o.Id
+}
+
+func (f *fakeMemberRepo) UpdateOrderCodeURL(ctx context.Context, oid int64, codeURL string) error {
+	return nil
+}
+
+func (f *fakeMemberRepo) PayOrder(ctx context.Context, oid int64, paidAt time.Time) error {
+	f.paid = append(f.paid, oid)
+	f.paidAt = paidAt
+	return nil
+}
+
+func (f *fakeMemberRepo) FailOrder(ctx context.Context, oid int64) error {
+	f.failed = append(f.failed, oid)
+	return nil
+}
+
+func (f *fakeMemberRepo) RefundOrder(ctx context.Context, oid int64) error {
+	f.refunded = append(f.refunded, oid)
+	return nil
+}
+
+func (f *fakeMemberRepo) FindDueSubscriptions(ctx context.Context,
+	before time.Time, afterId int64, limit int) ([]domain.Subscription, error) {
+	if afterId > 0 {
+		return nil, nil
+	}
+	return f.due, nil
+}
+
+func (f *fakeMemberRepo) UpdateSubscriptionStatus(ctx context.Context,
+	sub domain.Subscription, to domain.SubscriptionStatus) error {
+	f.status = append(f.status, to)
+	return f.statusErr
+}
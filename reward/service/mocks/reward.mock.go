@@ -11,6 +11,7 @@ package svcmocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "gitee.com/geekbang/basic-go/webook/reward/domain"
 	gomock "go.uber.org/mock/gomock"
@@ -39,14 +40,72 @@ func (m *MockRewardService) EXPECT() *MockRewardServiceMockRecorder {
 	return m.recorder
 }
 
+// GetCreatorStats mocks base method.
+func (m *MockRewardService) GetCreatorStats(ctx context.Context, uid int64, currency string, day time.Time) (domain.CreatorRewardStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCreatorStats", ctx, uid, currency, day)
+	ret0, _ := ret[0].(domain.CreatorRewardStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCreatorStats indicates an expected call of GetCreatorStats.
+func (mr *MockRewardServiceMockRecorder) GetCreatorStats(ctx, uid, currency, day any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCreatorStats", reflect.TypeOf((*MockRewardService)(nil).GetCreatorStats), ctx, uid, currency, day)
+}
+
+// GetReward mocks base method.
+func (m *MockRewardService) GetReward(ctx context.Context, rid, uid int64) (domain.Reward, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReward", ctx, rid, uid)
+	ret0, _ := ret[0].(domain.Reward)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReward indicates an expected call of GetReward.
+func (mr *MockRewardServiceMockRecorder) GetReward(ctx, rid, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReward", reflect.TypeOf((*MockRewardService)(nil).GetReward), ctx, rid, uid)
+}
+
+// GetTargetStats mocks base method.
+func (m *MockRewardService) GetTargetStats(ctx context.Context, biz string, bizId int64, currency string, n int) (domain.TargetRewardStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTargetStats", ctx, biz, bizId, currency, n)
+	ret0, _ := ret[0].(domain.TargetRewardStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTargetStats indicates an expected call of GetTargetStats.
+func (mr *MockRewardServiceMockRecorder) GetTargetStats(ctx, biz, bizId, currency, n any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTargetStats", reflect.TypeOf((*MockRewardService)(nil).GetTargetStats), ctx, biz, bizId, currency, n)
+}
+
+// HandleRefund mocks base method.
+func (m *MockRewardService) HandleRefund(ctx context.Context, bizTradeNO, refundNO string, amt int64, fullyRefunded bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleRefund", ctx, bizTradeNO, refundNO, amt, fullyRefunded)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleRefund indicates an expected call of HandleRefund.
+func (mr *MockRewardServiceMockRecorder) HandleRefund(ctx, bizTradeNO, refundNO, amt, fullyRefunded any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleRefund", reflect.TypeOf((*MockRewardService)(nil).HandleRefund), ctx, bizTradeNO, refundNO, amt, fullyRefunded)
+}
+
 // PreReward mocks base method.
-func (m *MockRewardService) PreReward(ctx context.Context, r domain.Reward) (string, int64, error) {
+func (m *MockRewardService) PreReward(ctx context.Context, r domain.Reward) (domain.CodeURL, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "PreReward", ctx, r)
-	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(int64)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret0, _ := ret[0].(domain.CodeURL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // PreReward indicates an expected call of PreReward.
@@ -54,3 +113,269 @@ func (mr *MockRewardServiceMockRecorder) PreReward(ctx, r any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreReward", reflect.TypeOf((*MockRewardService)(nil).PreReward), ctx, r)
 }
+
+// TopSupporters mocks base method.
+func (m *MockRewardService) TopSupporters(ctx context.Context, uid int64, currency string, n int) ([]domain.Supporter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TopSupporters", ctx, uid, currency, n)
+	ret0, _ := ret[0].([]domain.Supporter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TopSupporters indicates an expected call of TopSupporters.
+func (mr *MockRewardServiceMockRecorder) TopSupporters(ctx, uid, currency, n any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopSupporters", reflect.TypeOf((*MockRewardService)(nil).TopSupporters), ctx, uid, currency, n)
+}
+
+// UpdateReward mocks base method.
+func (m *MockRewardService) UpdateReward(ctx context.Context, bizTradeNO string, status domain.RewardStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReward", ctx, bizTradeNO, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReward indicates an expected call of UpdateReward.
+func (mr *MockRewardServiceMockRecorder) UpdateReward(ctx, bizTradeNO, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReward", reflect.TypeOf((*MockRewardService)(nil).UpdateReward), ctx, bizTradeNO, status)
+}
+
+// MockFeeRuleService is a mock of FeeRuleService interface.
+type MockFeeRuleService struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeeRuleServiceMockRecorder
+}
+
+// MockFeeRuleServiceMockRecorder is the mock recorder for MockFeeRuleService.
+type MockFeeRuleServiceMockRecorder struct {
+	mock *MockFeeRuleService
+}
+
+// NewMockFeeRuleService creates a new mock instance.
+func NewMockFeeRuleService(ctrl *gomock.Controller) *MockFeeRuleService {
+	mock := &MockFeeRuleService{ctrl: ctrl}
+	mock.recorder = &MockFeeRuleServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeeRuleService) EXPECT() *MockFeeRuleServiceMockRecorder {
+	return m.recorder
+}
+
+// GetFeeRule mocks base method.
+func (m *MockFeeRuleService) GetFeeRule(ctx context.Context, id int64) (domain.FeeRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeeRule", ctx, id)
+	ret0, _ := ret[0].(domain.FeeRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeeRule indicates an expected call of GetFeeRule.
+func (mr *MockFeeRuleServiceMockRecorder) GetFeeRule(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeeRule", reflect.TypeOf((*MockFeeRuleService)(nil).GetFeeRule), ctx, id)
+}
+
+// ListFeeRules mocks base method.
+func (m *MockFeeRuleService) ListFeeRules(ctx context.Context, offset, limit int) ([]domain.FeeRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFeeRules", ctx, offset, limit)
+	ret0, _ := ret[0].([]domain.FeeRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFeeRules indicates an expected call of ListFeeRules.
+func (mr *MockFeeRuleServiceMockRecorder) ListFeeRules(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFeeRules", reflect.TypeOf((*MockFeeRuleService)(nil).ListFeeRules), ctx, offset, limit)
+}
+
+// SaveFeeRule mocks base method.
+func (m *MockFeeRuleService) SaveFeeRule(ctx context.Context, r domain.FeeRule) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveFeeRule", ctx, r)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveFeeRule indicates an expected call of SaveFeeRule.
+func (mr *MockFeeRuleServiceMockRecorder) SaveFeeRule(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFeeRule", reflect.TypeOf((*MockFeeRuleService)(nil).SaveFeeRule), ctx, r)
+}
+
+// MockMemberService is a mock of MemberService interface.
+type MockMemberService struct {
+	ctrl     *gomock.Controller
+	recorder *MockMemberServiceMockRecorder
+}
+
+// MockMemberServiceMockRecorder is the mock recorder for MockMemberService.
+type MockMemberServiceMockRecorder struct {
+	mock *MockMemberService
+}
+
+// NewMockMemberService creates a new mock instance.
+func NewMockMemberService(ctrl *gomock.Controller) *MockMemberService {
+	mock := &MockMemberService{ctrl: ctrl}
+	mock.recorder = &MockMemberServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMemberService) EXPECT() *MockMemberServiceMockRecorder {
+	return m.recorder
+}
+
+// CancelSubscription mocks base method.
+func (m *MockMemberService) CancelSubscription(ctx context.Context, uid, creatorUid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelSubscription", ctx, uid, creatorUid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelSubscription indicates an expected call of CancelSubscription.
+func (mr *MockMemberServiceMockRecorder) CancelSubscription(ctx, uid, creatorUid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSubscription", reflect.TypeOf((*MockMemberService)(nil).CancelSubscription), ctx, uid, creatorUid)
+}
+
+// CheckEntitlement mocks base method.
+func (m *MockMemberService) CheckEntitlement(ctx context.Context, uid, creatorUid int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckEntitlement", ctx, uid, creatorUid)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckEntitlement indicates an expected call of CheckEntitlement.
+func (mr *MockMemberServiceMockRecorder) CheckEntitlement(ctx, uid, creatorUid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckEntitlement", reflect.TypeOf((*MockMemberService)(nil).CheckEntitlement), ctx, uid, creatorUid)
+}
+
+// GetOrder mocks base method.
+func (m *MockMemberService) GetOrder(ctx context.Context, oid, uid int64) (domain.MemberOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrder", ctx, oid, uid)
+	ret0, _ := ret[0].(domain.MemberOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrder indicates an expected call of GetOrder.
+func (mr *MockMemberServiceMockRecorder) GetOrder(ctx, oid, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockMemberService)(nil).GetOrder), ctx, oid, uid)
+}
+
+// GetSubscription mocks base method.
+func (m *MockMemberService) GetSubscription(ctx context.Context, uid, creatorUid int64) (domain.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscription", ctx, uid, creatorUid)
+	ret0, _ := ret[0].(domain.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscription indicates an expected call of GetSubscription.
+func (mr *MockMemberServiceMockRecorder) GetSubscription(ctx, uid, creatorUid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscription", reflect.TypeOf((*MockMemberService)(nil).GetSubscription), ctx, uid, creatorUid)
+}
+
+// HandlePayment mocks base method.
+func (m *MockMemberService) HandlePayment(ctx context.Context, bizTradeNO string, status domain.MemberOrderStatus, paidAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandlePayment", ctx, bizTradeNO, status, paidAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandlePayment indicates an expected call of HandlePayment.
+func (mr *MockMemberServiceMockRecorder) HandlePayment(ctx, bizTradeNO, status, paidAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandlePayment", reflect.TypeOf((*MockMemberService)(nil).HandlePayment), ctx, bizTradeNO, status, paidAt)
+}
+
+// HandleRefund mocks base method.
+func (m *MockMemberService) HandleRefund(ctx context.Context, bizTradeNO string, fullyRefunded bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleRefund", ctx, bizTradeNO, fullyRefunded)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleRefund indicates an expected call of HandleRefund.
+func (mr *MockMemberServiceMockRecorder) HandleRefund(ctx, bizTradeNO, fullyRefunded any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleRefund", reflect.TypeOf((*MockMemberService)(nil).HandleRefund), ctx, bizTradeNO, fullyRefunded)
+}
+
+// ListPlans mocks base method.
+func (m *MockMemberService) ListPlans(ctx context.Context, creatorUid int64) ([]domain.MemberPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPlans", ctx, creatorUid)
+	ret0, _ := ret[0].([]domain.MemberPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPlans indicates an expected call of ListPlans.
+func (mr *MockMemberServiceMockRecorder) ListPlans(ctx, creatorUid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPlans", reflect.TypeOf((*MockMemberService)(nil).ListPlans), ctx, creatorUid)
+}
+
+// RenewDue mocks base method.
+func (m *MockMemberService) RenewDue(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewDue", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenewDue indicates an expected call of RenewDue.
+func (mr *MockMemberServiceMockRecorder) RenewDue(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewDue", reflect.TypeOf((*MockMemberService)(nil).RenewDue), ctx)
+}
+
+// SavePlan mocks base method.
+func (m *MockMemberService) SavePlan(ctx context.Context, p domain.MemberPlan) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePlan", ctx, p)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SavePlan indicates an expected call of SavePlan.
+func (mr *MockMemberServiceMockRecorder) SavePlan(ctx, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePlan", reflect.TypeOf((*MockMemberService)(nil).SavePlan), ctx, p)
+}
+
+// Subscribe mocks base method.
+func (m *MockMemberService) Subscribe(ctx context.Context, uid, planId int64) (domain.MemberOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, uid, planId)
+	ret0, _ := ret[0].(domain.MemberOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockMemberServiceMockRecorder) Subscribe(ctx, uid, planId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockMemberService)(nil).Subscribe), ctx, uid, planId)
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"gitee.com/geekbang/basic-go/webook/reward/domain"
+	"time"
 )
 
 //go:generate mockgen -source=./types.go -destination=mocks/reward.mock.go -package=svcmocks RewardService
@@ -14,4 +15,52 @@ type RewardService interface {
 		r domain.Reward) (domain.CodeURL, error)
 	GetReward(ctx context.Context, rid, uid int64) (domain.Reward, error)
 	UpdateReward(ctx context.Context, bizTradeNO string, status domain.RewardStatus) error
+	// HandleRefund 退款成功之后，把已经入账的钱按比例扣回来。
+	// fullyRefunded 为 true 说明已经全部退完了
+	HandleRefund(ctx context.Context, bizTradeNO string, refundNO string,
+		amt int64, fullyRefunded bool) error
+
+	// 下面是统计和排行榜，currency 为空就是作者的币种
+
+	// GetTargetStats 被打赏的东西的总数和打赏最多的前 n 个人
+	GetTargetStats(ctx context.Context, biz string, bizId int64, currency string, n int) (domain.TargetRewardStats, error)
+	// GetCreatorStats 作者在 day 这一天和这个月收到的打赏
+	GetCreatorStats(ctx context.Context, uid int64, currency string, day time.Time) (domain.CreatorRewardStats, error)
+	// TopSupporters 给作者打赏最多的前 n 个人
+	TopSupporters(ctx context.Context, uid int64, currency string, n int) ([]domain.Supporter, error)
+}
+
+// FeeRuleService 平台抽成规则的管理，给运营后台用
+type FeeRuleService interface {
+	// SaveFeeRule Id 为 0 就是新建。规则不合法返回 domain.ErrInvalidFeeRule。
+	// 修改不会动原来的规则，而是生成一个新的版本，返回新版本的 id，
+	// 这样已经打赏了的还是按照原来的规则算
+	SaveFeeRule(ctx context.Context, r domain.FeeRule) (int64, error)
+	GetFeeRule(ctx context.Context, id int64) (domain.FeeRule, error)
+	ListFeeRules(ctx context.Context, offset, limit int) ([]domain.FeeRule, error)
+}
+
+// MemberService 作者的付费会员，按月付费。
+// 目前只支持扫码支付，所以续费也是生成一个订单，等用户自己扫码付钱
+type MemberService interface {
+	// SavePlan 作者创建或者修改自己的会员方案，Id 为 0 就是新建
+	SavePlan(ctx context.Context, p domain.MemberPlan) (int64, error)
+	// ListPlans 作者所有的方案，包括已经下线的
+	ListPlans(ctx context.Context, creatorUid int64) ([]domain.MemberPlan, error)
+	// Subscribe 订阅或者重新订阅，返回要付钱的订单。
+	// 已经有没付钱的续费订单的话，直接返回那个订单
+	Subscribe(ctx context.Context, uid int64, planId int64) (domain.MemberOrder, error)
+	GetOrder(ctx context.Context, oid int64, uid int64) (domain.MemberOrder, error)
+	GetSubscription(ctx context.Context, uid int64, creatorUid int64) (domain.Subscription, error)
+	// CancelSubscription 取消之后不会再续费，这个周期结束之前还是会员
+	CancelSubscription(ctx context.Context, uid int64, creatorUid int64) error
+	// HandlePayment 支付的结果，paidAt 是付钱的时间，重复调用是安全的
+	HandlePayment(ctx context.Context, bizTradeNO string, status domain.MemberOrderStatus, paidAt time.Time) error
+	// HandleRefund 退款成功，全额退款的话收回会员，重复调用是安全的
+	HandleRefund(ctx context.Context, bizTradeNO string, fullyRefunded bool) error
+	// RenewDue 推进到期订阅的状态，给快要到期的订阅生成续费订单。
+	// 由分布式任务调度调用
+	RenewDue(ctx context.Context) error
+	// CheckEntitlement uid 现在是不是 creatorUid 的会员
+	CheckEntitlement(ctx context.Context, uid int64, creatorUid int64) (bool, error)
 }
@@ -11,16 +11,35 @@ import (
 	"gitee.com/geekbang/basic-go/webook/reward/repository"
 	"strconv"
 	"strings"
+	"time"
 )
 
+// ErrUnsupportedCurrency 支付渠道收不了这个币种的钱
+var ErrUnsupportedCurrency = errors.New("不支持的币种")
+
 type WechatNativeRewardService struct {
 	client pmtv1.WechatPaymentServiceClient
 	repo   repository.RewardRepository
-	l      logger.LoggerV1
-	acli   accountv1.AccountServiceClient
+	// feeRepo 平台抽成的规则
+	feeRepo   repository.FeeRuleRepository
+	statsRepo repository.RewardStatsRepository
+	l         logger.LoggerV1
+	acli      accountv1.AccountServiceClient
+	// channel 用哪个渠道付钱，名字里面的微信是历史原因
+	channel pmtv1.PaymentChannel
+	// base 作者账号的币种，打赏的钱都换成这个币种入账
+	base BaseCurrency
 }
 
+// BaseCurrency 作者账号的币种
+type BaseCurrency string
+
 func (s *WechatNativeRewardService) PreReward(ctx context.Context, r domain.Reward) (domain.CodeURL, error) {
+	r.Currency = s.currency(r.Currency)
+	// 先校验，不然用户付了钱之后才发现入不了账
+	if !supportCurrency(s.channel, r.Currency) {
+		return domain.CodeURL{}, ErrUnsupportedCurrency
+	}
 	// 缓存，可选的步骤
 	res, err := s.repo.GetCachedCodeURL(ctx, r)
 	if err == nil {
@@ -34,10 +53,11 @@ func (s *WechatNativeRewardService) PreReward(ctx context.Context, r domain.Rewa
 	pmtResp, err := s.client.NativePrePay(ctx, &pmtv1.PrePayRequest{
 		Amt: &pmtv1.Amount{
 			Total:    r.Amt,
-			Currency: "CNY",
+			Currency: r.Currency,
 		},
 		BizTradeNo:  fmt.Sprintf("reward-%d", rid),
 		Description: fmt.Sprintf("打赏-%s", r.Target.BizName),
+		Channel:     s.channel,
 	})
 	if err != nil {
 		return domain.CodeURL{}, err
@@ -62,30 +82,66 @@ func (s *WechatNativeRewardService) UpdateReward(ctx context.Context,
 	if err != nil {
 		return err
 	}
+	if status == domain.RewardStatusFailed {
+		// 支付订单已经关闭了，缓存的二维码不能再用，
+		// 用户下一次打赏要重新下单
+		r, err := s.repo.GetReward(ctx, rid)
+		if err != nil {
+			return err
+		}
+		err = s.repo.EvictCachedCodeURL(ctx, r)
+		if err != nil {
+			s.l.Error("删除缓存的二维码失败", logger.Error(err),
+				logger.Int64("rid", rid))
+		}
+		return nil
+	}
 	// 完成了支付，准备入账
 	if status == domain.RewardStatusPayed {
 		r, err := s.repo.GetReward(ctx, rid)
 		if err != nil {
 			return err
 		}
-		// webook 抽成
-		weAmt := int64(float64(r.Amt) * 0.1)
+		r, err = s.splitFee(ctx, r)
+		if err != nil {
+			return err
+		}
+		// 统计要在入账之前，入账成功之后崩了的话，重复消费的时候入账会报重复，
+		// 统计就丢了。统计自己会去重，重复调用没关系。
+		// 统计失败不影响入账，排行榜差一点没关系
+		err = s.statsRepo.IncrStats(ctx, r, r.Ctime)
+		if err != nil {
+			s.l.Error("统计打赏失败", logger.Error(err),
+				logger.Int64("rid", rid))
+		}
+		// webook 抽成，按照打赏的币种算，换汇交给 account
+		weAmt := r.Fee
 		_, err = s.acli.Credit(ctx, &accountv1.CreditRequest{
-			Biz:   "reward",
-			BizId: rid,
+			Biz:       "reward",
+			BizId:     rid,
+			FeeRuleId: r.FeeRuleId,
 			Items: []*accountv1.CreditItem{
 				{
 					AccountType: accountv1.AccountType_AccountTypeReward,
 					// 虽然可能为 0，但是也要记录出来
-					Amt:      weAmt,
-					Currency: "CNY",
+					Amt:            weAmt,
+					Currency:       r.Currency,
+					SettleCurrency: string(s.base),
 				},
 				{
-					Account:     r.Uid,
-					Uid:         r.Uid,
-					AccountType: accountv1.AccountType_AccountTypeReward,
-					Amt:         r.Amt - weAmt,
-					Currency:    "CNY",
+					Account:        r.Target.Uid,
+					Uid:            r.Target.Uid,
+					AccountType:    accountv1.AccountType_AccountTypeReward,
+					Amt:            r.Amt - weAmt,
+					Currency:       r.Currency,
+					SettleCurrency: string(s.base),
+				},
+				{
+					// 复式记账，钱是从支付渠道进来的
+					AccountType:    accountv1.AccountType_AccountTypeClearing,
+					Amt:            -r.Amt,
+					Currency:       r.Currency,
+					SettleCurrency: string(s.base),
 				},
 			},
 		})
@@ -100,6 +156,65 @@ func (s *WechatNativeRewardService) UpdateReward(ctx context.Context,
 	return nil
 }
 
+// splitFee 按照打赏那一刻生效的规则算平台抽成，记在打赏上。
+// 已经算过的直接用记下来的，重复消费的时候就算规则改了，分账也不会变
+func (s *WechatNativeRewardService) splitFee(ctx context.Context, r domain.Reward) (domain.Reward, error) {
+	if r.FeeComputed {
+		return r, nil
+	}
+	rules, err := s.feeRepo.FindCandidates(ctx, r.Target.Biz, r.Target.Uid)
+	if err != nil {
+		return r, err
+	}
+	rule := domain.MatchFeeRule(rules, r, r.Ctime)
+	err = s.repo.UpdateFee(ctx, r.Id, rule.Id, rule.Fee(r.Amt))
+	if errors.Is(err, repository.ErrFeeComputed) {
+		// 并发消费，别人先算好了，用别人记下来的
+		return s.repo.GetReward(ctx, r.Id)
+	}
+	if err != nil {
+		return r, err
+	}
+	r.FeeRuleId = rule.Id
+	r.Fee = rule.Fee(r.Amt)
+	r.FeeComputed = true
+	return r, nil
+}
+
+func (s *WechatNativeRewardService) HandleRefund(ctx context.Context,
+	bizTradeNO string, refundNO string, amt int64, fullyRefunded bool) error {
+	rid := s.toRid(bizTradeNO)
+	// 冲正是按照 refundNO 去重的，重复消费也没关系
+	_, err := s.acli.Reverse(ctx, &accountv1.ReverseRequest{
+		Biz:       "reward",
+		BizId:     rid,
+		ReverseNo: refundNO,
+		Amt:       amt,
+	})
+	if err != nil {
+		s.l.Error("冲正失败了，快来修数据啊！！！",
+			logger.String("biz_trade_no", bizTradeNO),
+			logger.String("refund_no", refundNO),
+			logger.Error(err))
+		return err
+	}
+	r, err := s.repo.GetReward(ctx, rid)
+	if err != nil {
+		return err
+	}
+	// 统计按照 refundNO 去重，和入账的时候一样按照打赏创建的时间统计
+	err = s.statsRepo.RefundStats(ctx, r, refundNO, amt, fullyRefunded, r.Ctime)
+	if err != nil {
+		s.l.Error("扣减打赏统计失败", logger.Error(err),
+			logger.Int64("rid", rid),
+			logger.String("refund_no", refundNO))
+	}
+	if !fullyRefunded {
+		return nil
+	}
+	return s.repo.UpdateStatus(ctx, rid, domain.RewardStatusRefunded)
+}
+
 func (s *WechatNativeRewardService) GetReward(ctx context.Context, rid, uid int64) (domain.Reward, error) {
 	// 快路径
 	res, err := s.repo.GetReward(ctx, rid)
@@ -131,8 +246,9 @@ func (s *WechatNativeRewardService) GetReward(ctx context.Context, rid, uid int6
 		case pmtv1.PaymentStatus_PaymentStatusInit:
 			res.Status = domain.RewardStatusInit
 		case pmtv1.PaymentStatus_PaymentStatusRefund:
-			res.Status = domain.RewardStatusFailed
-		case pmtv1.PaymentStatus_PaymentStatusFailed:
+			res.Status = domain.RewardStatusRefunded
+		case pmtv1.PaymentStatus_PaymentStatusFailed,
+			pmtv1.PaymentStatus_PaymentStatusClosed:
 			res.Status = domain.RewardStatusFailed
 		case pmtv1.PaymentStatus_PaymentStatusUnknown:
 		}
@@ -145,6 +261,36 @@ func (s *WechatNativeRewardService) GetReward(ctx context.Context, rid, uid int6
 	return res, nil
 }
 
+func (s *WechatNativeRewardService) GetTargetStats(ctx context.Context, biz string, bizId int64,
+	currency string, n int) (domain.TargetRewardStats, error) {
+	return s.statsRepo.GetTargetStats(ctx, biz, bizId, s.currency(currency), n)
+}
+
+func (s *WechatNativeRewardService) GetCreatorStats(ctx context.Context, uid int64,
+	currency string, day time.Time) (domain.CreatorRewardStats, error) {
+	return s.statsRepo.GetCreatorStats(ctx, uid, s.currency(currency), day)
+}
+
+func (s *WechatNativeRewardService) TopSupporters(ctx context.Context, uid int64,
+	currency string, n int) ([]domain.Supporter, error) {
+	return s.statsRepo.TopSupporters(ctx, uid, s.currency(currency), n)
+}
+
+func (s *WechatNativeRewardService) currency(c string) string {
+	if c == "" {
+		return string(s.base)
+	}
+	return c
+}
+
+// supportCurrency 微信和支付宝的扫码支付都只能收人民币，沙箱什么都收
+func supportCurrency(channel pmtv1.PaymentChannel, c string) bool {
+	if channel == pmtv1.PaymentChannel_PaymentChannelSandbox {
+		return true
+	}
+	return c == "CNY"
+}
+
 func (s *WechatNativeRewardService) bizTradeNO(rid int64) string {
 	return fmt.Sprintf("reward-%d", rid)
 }
@@ -158,8 +304,13 @@ func (s *WechatNativeRewardService) toRid(tradeNO string) int64 {
 func NewWechatNativeRewardService(
 	client pmtv1.WechatPaymentServiceClient,
 	repo repository.RewardRepository,
+	feeRepo repository.FeeRuleRepository,
+	statsRepo repository.RewardStatsRepository,
 	l logger.LoggerV1,
 	acli accountv1.AccountServiceClient,
+	channel pmtv1.PaymentChannel,
+	base BaseCurrency,
 ) RewardService {
-	return &WechatNativeRewardService{client: client, repo: repo, l: l, acli: acli}
+	return &WechatNativeRewardService{client: client, repo: repo, feeRepo: feeRepo, statsRepo: statsRepo,
+		l: l, acli: acli, channel: channel, base: base}
 }
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	pmtv1 "gitee.com/geekbang/basic-go/webook/api/proto/gen/payment/v1"
+	"gitee.com/geekbang/basic-go/webook/pkg/logger"
+	"gitee.com/geekbang/basic-go/webook/reward/domain"
+	"gitee.com/geekbang/basic-go/webook/reward/repository"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWechatNativeRewardService_PreReward(t *testing.T) {
+	cu := domain.CodeURL{Rid: 1, URL: "weixin://wxpay/1"}
+	testCases := []struct {
+		name    string
+		channel pmtv1.PaymentChannel
+		r       domain.Reward
+
+		want    domain.CodeURL
+		wantErr error
+	}{
+		{
+			// 付了钱也入不了账，所以下单之前就要拒绝
+			name:    "微信不收美元",
+			channel: pmtv1.PaymentChannel_PaymentChannelWechat,
+			r:       domain.Reward{Uid: 1, Amt: 100, Currency: "USD"},
+			wantErr: ErrUnsupportedCurrency,
+		},
+		{
+			name:    "没有币种就用作者的币种",
+			channel: pmtv1.PaymentChannel_PaymentChannelWechat,
+			r:       domain.Reward{Uid: 1, Amt: 100},
+			want:    cu,
+		},
+		{
+			name:    "沙箱什么币种都收",
+			channel: pmtv1.PaymentChannel_PaymentChannelSandbox,
+			r:       domain.Reward{Uid: 1, Amt: 100, Currency: "USD"},
+			want:    cu,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// 命中缓存就直接返回，不会去下单
+			repo := &fakeRewardRepo{cached: cu}
+			svc := NewWechatNativeRewardService(nil, repo, nil, nil,
+				logger.NewNopLogger(), nil, tc.channel, "CNY")
+			res, err := svc.PreReward(context.Background(), tc.r)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, res)
+		})
+	}
+}
+
+func TestWechatNativeRewardService_splitFee(t *testing.T) {
+	ctime := time.UnixMilli(100000)
+	rw := domain.Reward{Id: 1, Uid: 1024, Target: domain.Target{Biz: "article", BizId: 1, Uid: 2048},
+		Amt: 1000, Currency: "CNY", Status: domain.RewardStatusPayed, Ctime: ctime}
+	computed := func(ruleId, fee int64) domain.Reward {
+		r := rw
+		r.FeeRuleId = ruleId
+		r.Fee = fee
+		r.FeeComputed = true
+		return r
+	}
+	creatorRule := domain.FeeRule{Id: 3, TargetUid: 2048, Type: domain.FeeRuleTypePercentage,
+		Rate: 500, Ctime: ctime.Add(-time.Hour)}
+	testCases := []struct {
+		name    string
+		repo    *fakeRewardRepo
+		feeRepo *fakeFeeRuleRepo
+		r       domain.Reward
+
+		want        domain.Reward
+		wantUpdated []int64
+		wantErr     error
+	}{
+		{
+			name:        "按照作者的规则算",
+			repo:        &fakeRewardRepo{},
+			feeRepo:     &fakeFeeRuleRepo{rules: []domain.FeeRule{creatorRule}},
+			r:           rw,
+			want:        computed(3, 50),
+			wantUpdated: []int64{1, 3, 50},
+		},
+		{
+			name:        "没有规则用默认的",
+			repo:        &fakeRewardRepo{},
+			feeRepo:     &fakeFeeRuleRepo{},
+			r:           rw,
+			want:        computed(0, 100),
+			wantUpdated: []int64{1, 0, 100},
+		},
+		{
+			name: "打赏之后才创建的规则不用",
+			repo: &fakeRewardRepo{},
+			feeRepo: &fakeFeeRuleRepo{rules: []domain.FeeRule{{Id: 4, TargetUid: 2048,
+				Type: domain.FeeRuleTypePercentage, Rate: 0, Ctime: ctime.Add(time.Minute)}}},
+			r:           rw,
+			want:        computed(0, 100),
+			wantUpdated: []int64{1, 0, 100},
+		},
+		{
+			// 默认规则的 Id 也是 0，重复消费的时候不能重新算
+			name:    "已经按照默认规则算过了",
+			repo:    &fakeRewardRepo{},
+			feeRepo: &fakeFeeRuleRepo{},
+			r:       computed(0, 100),
+			want:    computed(0, 100),
+		},
+		{
+			name: "并发消费，别人先算好了",
+			repo: &fakeRewardRepo{updateFeeErr: repository.ErrFeeComputed,
+				stored: computed(3, 50)},
+			feeRepo:     &fakeFeeRuleRepo{},
+			r:           rw,
+			want:        computed(3, 50),
+			wantUpdated: []int64{1, 0, 100},
+		},
+		{
+			name:    "查询规则失败",
+			repo:    &fakeRewardRepo{},
+			feeRepo: &fakeFeeRuleRepo{err: errors.New("mock db error")},
+			r:       rw,
+			want:    rw,
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &WechatNativeRewardService{repo: tc.repo, feeRepo: tc.feeRepo,
+				l: logger.NewNopLogger()}
+			r, err := svc.splitFee(context.Background(), tc.r)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, r)
+			assert.Equal(t, tc.wantUpdated, tc.repo.updatedFee)
+		})
+	}
+}
+
+type fakeRewardRepo struct {
+	repository.RewardRepository
+	cached domain.CodeURL
+	// stored GetReward 返回的
+	stored       domain.Reward
+	updateFeeErr error
+	// updatedFee UpdateFee 的 rid, feeRuleId 和 fee
+	updatedFee []int64
+}
+
+func (f *fakeRewardRepo) GetCachedCodeURL(ctx context.Context, r domain.Reward) (domain.CodeURL, error) {
+	return f.cached, nil
+}
+
+func (f *fakeRewardRepo) GetReward(ctx context.Context, rid int64) (domain.Reward, error) {
+	return f.stored, nil
+}
+
+func (f *fakeRewardRepo) UpdateFee(ctx context.Context, rid int64, feeRuleId int64, fee int64) error {
+	f.updatedFee = []int64{rid, feeRuleId, fee}
+	return f.updateFeeErr
+}
+
+type fakeFeeRuleRepo struct {
+	repository.FeeRuleRepository
+	rules []domain.FeeRule
+	err   error
+}
+
+func (f *fakeFeeRuleRepo) FindCandidates(ctx context.Context, biz string, targetUid int64) ([]domain.FeeRule, error) {
+	return f.rules, f.err
+}
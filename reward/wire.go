@@ -4,6 +4,7 @@ package main
 
 import (
 	"gitee.com/geekbang/basic-go/webook/pkg/wego"
+	"gitee.com/geekbang/basic-go/webook/reward/events"
 	"gitee.com/geekbang/basic-go/webook/reward/grpc"
 	"gitee.com/geekbang/basic-go/webook/reward/ioc"
 	"gitee.com/geekbang/basic-go/webook/reward/repository"
@@ -17,7 +18,8 @@ var thirdPartySet = wire.NewSet(
 	ioc.InitDB,
 	ioc.InitLogger,
 	ioc.InitEtcdClient,
-	ioc.InitRedis)
+	ioc.InitRedis,
+	ioc.InitKafka)
 
 func Init() *wego.App {
 	wire.Build(thirdPartySet,
@@ -25,11 +27,25 @@ func Init() *wego.App {
 		ioc.InitAccountClient,
 		ioc.InitGRPCxServer,
 		ioc.InitPaymentClient,
+		ioc.InitPaymentChannel,
+		ioc.InitBaseCurrency,
 		repository.NewRewardRepository,
+		repository.NewFeeRuleRepository,
+		dao.NewFeeRuleGORMDAO,
+		service.NewFeeRuleService,
+		dao.NewMemberGORMDAO,
+		repository.NewMemberRepository,
+		service.NewMemberService,
+		ioc.InitJobExecutorServer,
 		cache.NewRewardRedisCache,
+		cache.NewRewardStatsRedisCache,
+		repository.NewRewardStatsRepository,
 		dao.NewRewardGORMDAO,
 		grpc.NewRewardServiceServer,
-		wire.Struct(new(wego.App), "GRPCServer"),
+		events.NewPaymentEventConsumer,
+		events.NewRefundEventConsumer,
+		ioc.InitConsumers,
+		wire.Struct(new(wego.App), "GRPCServer", "Consumers"),
 	)
 	return new(wego.App)
 }
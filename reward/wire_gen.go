@@ -8,6 +8,7 @@ package main
 
 import (
 	"gitee.com/geekbang/basic-go/webook/pkg/wego"
+	"gitee.com/geekbang/basic-go/webook/reward/events"
 	"gitee.com/geekbang/basic-go/webook/reward/grpc"
 	"gitee.com/geekbang/basic-go/webook/reward/ioc"
 	"gitee.com/geekbang/basic-go/webook/reward/repository"
@@ -27,17 +28,33 @@ func Init() *wego.App {
 	cmdable := ioc.InitRedis()
 	rewardCache := cache.NewRewardRedisCache(cmdable)
 	rewardRepository := repository.NewRewardRepository(rewardDAO, rewardCache)
+	feeRuleDAO := dao.NewFeeRuleGORMDAO(db)
+	feeRuleRepository := repository.NewFeeRuleRepository(feeRuleDAO)
+	rewardStatsCache := cache.NewRewardStatsRedisCache(cmdable)
+	rewardStatsRepository := repository.NewRewardStatsRepository(rewardStatsCache)
 	loggerV1 := ioc.InitLogger()
 	accountServiceClient := ioc.InitAccountClient(client)
-	rewardService := service.NewWechatNativeRewardService(wechatPaymentServiceClient, rewardRepository, loggerV1, accountServiceClient)
-	rewardServiceServer := grpc.NewRewardServiceServer(rewardService)
-	server := ioc.InitGRPCxServer(rewardServiceServer, client, loggerV1)
+	paymentChannel := ioc.InitPaymentChannel()
+	baseCurrency := ioc.InitBaseCurrency()
+	rewardService := service.NewWechatNativeRewardService(wechatPaymentServiceClient, rewardRepository, feeRuleRepository, rewardStatsRepository, loggerV1, accountServiceClient, paymentChannel, baseCurrency)
+	feeRuleService := service.NewFeeRuleService(feeRuleRepository)
+	memberDAO := dao.NewMemberGORMDAO(db)
+	memberRepository := repository.NewMemberRepository(memberDAO)
+	memberService := service.NewMemberService(wechatPaymentServiceClient, memberRepository, loggerV1, paymentChannel)
+	rewardServiceServer := grpc.NewRewardServiceServer(rewardService, feeRuleService, memberService)
+	jobExecutorServer := ioc.InitJobExecutorServer(memberService, loggerV1)
+	server := ioc.InitGRPCxServer(rewardServiceServer, jobExecutorServer, client, loggerV1)
+	saramaClient := ioc.InitKafka()
+	paymentEventConsumer := events.NewPaymentEventConsumer(saramaClient, loggerV1, rewardService, memberService)
+	refundEventConsumer := events.NewRefundEventConsumer(saramaClient, loggerV1, rewardService, memberService)
+	v := ioc.InitConsumers(paymentEventConsumer, refundEventConsumer)
 	app := &wego.App{
 		GRPCServer: server,
+		Consumers:  v,
 	}
 	return app
 }
 
 // wire.go:
 
-var thirdPartySet = wire.NewSet(ioc.InitDB, ioc.InitLogger, ioc.InitEtcdClient, ioc.InitRedis)
+var thirdPartySet = wire.NewSet(ioc.InitDB, ioc.InitLogger, ioc.InitEtcdClient, ioc.InitRedis, ioc.InitKafka)
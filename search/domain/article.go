@@ -6,4 +6,6 @@ type Article struct {
 	Status  int32
 	Content string
 	Tags    []string
+	// MembersOnly 会员专享的文章，搜不到内容，也不能返回内容
+	MembersOnly bool
 }
@@ -33,6 +33,8 @@ type ArticleEvent struct {
 	Title   string `json:"title"`
 	Status  int32  `json:"status"`
 	Content string `json:"content"`
+	// MembersOnly 会员专享的文章，Content 是空的
+	MembersOnly bool `json:"members_only"`
 }
 
 func (a *ArticleConsumer) Start() error {
@@ -60,10 +62,16 @@ func (a *ArticleConsumer) Consume(sg *sarama.ConsumerMessage,
 }
 
 func (a *ArticleConsumer) toDomain(article ArticleEvent) domain.Article {
-	return domain.Article{
-		Id:      article.Id,
-		Title:   article.Title,
-		Status:  article.Status,
-		Content: article.Content,
+	res := domain.Article{
+		Id:          article.Id,
+		Title:       article.Title,
+		Status:      article.Status,
+		Content:     article.Content,
+		MembersOnly: article.MembersOnly,
 	}
+	if res.MembersOnly {
+		// 以前的消息可能带了内容，不能存进去
+		res.Content = ""
+	}
+	return res
 }
@@ -40,12 +40,16 @@ func (s *SearchServiceServer) Search(ctx context.Context, request *searchv1.Sear
 		},
 		Article: &searchv1.ArticleResult{
 			Articles: slice.Map(resp.Articles, func(idx int, src domain.Article) *searchv1.Article {
-				return &searchv1.Article{
-					Id:      src.Id,
-					Title:   src.Title,
-					Status:  src.Status,
-					Content: src.Content,
+				res := &searchv1.Article{
+					Id:     src.Id,
+					Title:  src.Title,
+					Status: src.Status,
 				}
+				// 以前同步过来的会员专享文章可能还有内容
+				if !src.MembersOnly {
+					res.Content = src.Content
+				}
+				return res
 			}),
 		},
 	}, nil
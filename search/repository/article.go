@@ -51,16 +51,19 @@ func (a *articleRepository) SearchArticle(ctx context.Context,
 			Status:  src.Status,
 			Content: src.Content,
 			Tags:    src.Tags,
+
+			MembersOnly: src.MembersOnly,
 		}
 	}), nil
 }
 
 func (a *articleRepository) InputArticle(ctx context.Context, msg domain.Article) error {
 	return a.dao.InputArticle(ctx, dao.Article{
-		Id:      msg.Id,
-		Title:   msg.Title,
-		Status:  msg.Status,
-		Content: msg.Content,
+		Id:          msg.Id,
+		Title:       msg.Title,
+		Status:      msg.Status,
+		Content:     msg.Content,
+		MembersOnly: msg.MembersOnly,
 	})
 }
 
@@ -13,11 +13,12 @@ const ArticleIndexName = "article_index"
 const TagIndexName = "tags_index"
 
 type Article struct {
-	Id      int64    `json:"id"`
-	Title   string   `json:"title"`
-	Status  int32    `json:"status"`
-	Content string   `json:"content"`
-	Tags    []string `json:"tags"`
+	Id          int64    `json:"id"`
+	Title       string   `json:"title"`
+	Status      int32    `json:"status"`
+	Content     string   `json:"content"`
+	Tags        []string `json:"tags"`
+	MembersOnly bool     `json:"members_only"`
 }
 
 type ArticleElasticDAO struct {
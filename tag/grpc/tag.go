@@ -60,6 +60,22 @@ func (t *TagServiceServer) GetBizTags(ctx context.Context, req *tagv1.GetBizTags
 	}, nil
 }
 
+func (t *TagServiceServer) BatchGetBizTags(ctx context.Context, req *tagv1.BatchGetBizTagsRequest) (*tagv1.BatchGetBizTagsResponse, error) {
+	res, err := t.service.BatchGetBizTags(ctx, req.GetBiz(), req.GetBizIds())
+	if err != nil {
+		return nil, err
+	}
+	bizTags := make(map[int64]*tagv1.BizTags, len(res))
+	for bizId, tags := range res {
+		bizTags[bizId] = &tagv1.BizTags{
+			Tags: slice.Map(tags, func(idx int, src domain.Tag) *tagv1.Tag {
+				return t.toDTO(src)
+			}),
+		}
+	}
+	return &tagv1.BatchGetBizTagsResponse{BizTags: bizTags}, nil
+}
+
 func (t *TagServiceServer) toDTO(tag domain.Tag) *tagv1.Tag {
 	return &tagv1.Tag{
 		Id:   tag.Id,
@@ -36,6 +36,8 @@ type TagDAO interface {
 	CreateTagBiz(ctx context.Context, tagBiz []TagBiz) error
 	GetTagsByUid(ctx context.Context, uid int64) ([]Tag, error)
 	GetTagsByBiz(ctx context.Context, uid int64, biz string, bizId int64) ([]Tag, error)
+	// GetTagsByBizIds key 是 bizId，只要打标签的人自己的标签
+	GetTagsByBizIds(ctx context.Context, biz string, bizIds []int64) (map[int64][]Tag, error)
 	GetTags(ctx context.Context, offset, limit int) ([]Tag, error)
 	GetTagsById(ctx context.Context, ids []int64) ([]Tag, error)
 }
@@ -114,6 +116,22 @@ func (dao *GORMTagDAO) GetTagsByBiz(ctx context.Context, uid int64, biz string,
 	}), nil
 }
 
+func (dao *GORMTagDAO) GetTagsByBizIds(ctx context.Context, biz string, bizIds []int64) (map[int64][]Tag, error) {
+	var tagBizs []TagBiz
+	err := dao.db.WithContext(ctx).Model(&TagBiz{}).
+		InnerJoins("Tag", dao.db.Model(&Tag{})).
+		Where("Tag.uid = tag_bizs.uid AND biz = ? AND biz_id IN ?", biz, bizIds).
+		Find(&tagBizs).Error
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[int64][]Tag, len(bizIds))
+	for _, tb := range tagBizs {
+		res[tb.BizId] = append(res[tb.BizId], *tb.Tag)
+	}
+	return res, nil
+}
+
 func (dao *GORMTagDAO) GetTags(ctx context.Context, offset, limit int) ([]Tag, error) {
 	var res []Tag
 	err := dao.db.WithContext(ctx).Offset(offset).
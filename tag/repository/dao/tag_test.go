@@ -23,3 +23,17 @@ func TestGORMTagDAO_GetTagsByBiz(t *testing.T) {
 	}
 	t.Log(res)
 }
+
+func TestGORMTagDAO_GetTagsByBizIds(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("gorm.db?mode=memory"), &gorm.Config{
+		DryRun: true,
+	})
+	require.NoError(t, err)
+	db = db.Debug()
+	dao := NewGORMTagDAO(db)
+	res, err := dao.GetTagsByBizIds(context.Background(), "test", []int64{456, 789})
+	if err != nil {
+		return
+	}
+	t.Log(res)
+}
@@ -16,6 +16,7 @@ type TagRepository interface {
 	GetTags(ctx context.Context, uid int64) ([]domain.Tag, error)
 	GetTagsById(ctx context.Context, ids []int64) ([]domain.Tag, error)
 	GetBizTags(ctx context.Context, uid int64, biz string, bizId int64) ([]domain.Tag, error)
+	BatchGetBizTags(ctx context.Context, biz string, bizIds []int64) (map[int64][]domain.Tag, error)
 }
 
 type CachedTagRepository struct {
@@ -105,6 +106,20 @@ func (repo *CachedTagRepository) GetBizTags(ctx context.Context, uid int64, biz
 	}), nil
 }
 
+func (repo *CachedTagRepository) BatchGetBizTags(ctx context.Context, biz string, bizIds []int64) (map[int64][]domain.Tag, error) {
+	tags, err := repo.dao.GetTagsByBizIds(ctx, biz, bizIds)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[int64][]domain.Tag, len(tags))
+	for bizId, ts := range tags {
+		res[bizId] = slice.Map(ts, func(idx int, src dao.Tag) domain.Tag {
+			return repo.toDomain(src)
+		})
+	}
+	return res, nil
+}
+
 func (repo *CachedTagRepository) CreateTag(ctx context.Context, tag domain.Tag) (int64, error) {
 	id, err := repo.dao.CreateTag(ctx, repo.toEntity(tag))
 	if err != nil {
@@ -15,6 +15,8 @@ type TagService interface {
 	AttachTags(ctx context.Context, uid int64, biz string, bizId int64, tags []int64) error
 	GetTags(ctx context.Context, uid int64) ([]domain.Tag, error)
 	GetBizTags(ctx context.Context, uid int64, biz string, bizId int64) ([]domain.Tag, error)
+	// BatchGetBizTags key 是 bizId，只有作者自己打的标签
+	BatchGetBizTags(ctx context.Context, biz string, bizIds []int64) (map[int64][]domain.Tag, error)
 }
 
 type tagService struct {
@@ -57,6 +59,13 @@ func (svc *tagService) GetBizTags(ctx context.Context, uid int64, biz string, bi
 	return svc.repo.GetBizTags(ctx, uid, biz, bizId)
 }
 
+func (svc *tagService) BatchGetBizTags(ctx context.Context, biz string, bizIds []int64) (map[int64][]domain.Tag, error) {
+	if len(bizIds) == 0 {
+		return map[int64][]domain.Tag{}, nil
+	}
+	return svc.repo.BatchGetBizTags(ctx, biz, bizIds)
+}
+
 func (svc *tagService) CreateTag(ctx context.Context, uid int64, name string) (int64, error) {
 	return svc.repo.CreateTag(ctx, domain.Tag{
 		Uid:  uid,
@@ -8,6 +8,7 @@ import (
 	dao2 "gitee.com/geekbang/basic-go/webook/interactive/repository/dao"
 	service2 "gitee.com/geekbang/basic-go/webook/interactive/service"
 	"gitee.com/geekbang/basic-go/webook/internal/events/article"
+	"gitee.com/geekbang/basic-go/webook/internal/events/ranking"
 	"gitee.com/geekbang/basic-go/webook/internal/repository"
 	"gitee.com/geekbang/basic-go/webook/internal/repository/cache"
 	"gitee.com/geekbang/basic-go/webook/internal/repository/dao"
@@ -24,10 +25,36 @@ var interactiveSvcSet = wire.NewSet(dao2.NewGORMInteractiveDAO,
 	service2.NewInteractiveService,
 )
 
+var jobProviderSet = wire.NewSet(
+	dao.NewGORMJobDAO,
+	repository.NewPreemptJobRepository,
+	service.NewCronJobService,
+	ioc.InitLocalFuncExecutor,
+	ioc.InitHttpExecutor,
+	ioc.InitGrpcExecutor,
+	ioc.InitScheduler,
+)
+
+var seriesSvcSet = wire.NewSet(
+	dao.NewSeriesGORMDAO,
+	repository.NewSeriesRepository,
+	service.NewSeriesService,
+)
+
+var reviewSvcSet = wire.NewSet(
+	dao.NewArticleReviewGORMDAO,
+	repository.NewCachedArticleReviewRepository,
+	ioc.InitArticleReviewConfig,
+	service.NewArticleReviewService,
+)
+
 var rankingSvcSet = wire.NewSet(
 	cache.NewRankingRedisCache,
 	repository.NewCachedRankingRepository,
+	ioc.InitTagClient,
+	ioc.InitRankingConfig,
 	service.NewBatchRankingService,
+	ranking.NewRankingEventConsumer,
 )
 
 func InitWebServer() *App {
@@ -50,6 +77,9 @@ func InitWebServer() *App {
 		rankingSvcSet,
 		ioc.InitJobs,
 		ioc.InitRankingJob,
+		jobProviderSet,
+		seriesSvcSet,
+		reviewSvcSet,
 
 		article.NewSaramaSyncProducer,
 		//events.NewInteractiveReadEventConsumer,
@@ -74,6 +104,9 @@ func InitWebServer() *App {
 		// handler 部分
 		web.NewUserHandler,
 		web.NewArticleHandler,
+		web.NewSeriesHandler,
+		web.NewArticleReviewHandler,
+		web.NewJobHandler,
 		ijwt.NewRedisJWTHandler,
 		web.NewOAuth2WechatHandler,
 		ioc.InitGinMiddlewares,
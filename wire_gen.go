@@ -12,6 +12,7 @@ import (
 	dao2 "gitee.com/geekbang/basic-go/webook/interactive/repository/dao"
 	service2 "gitee.com/geekbang/basic-go/webook/interactive/service"
 	"gitee.com/geekbang/basic-go/webook/internal/events/article"
+	"gitee.com/geekbang/basic-go/webook/internal/events/ranking"
 	"gitee.com/geekbang/basic-go/webook/internal/repository"
 	"gitee.com/geekbang/basic-go/webook/internal/repository/cache"
 	"gitee.com/geekbang/basic-go/webook/internal/repository/dao"
@@ -49,25 +50,46 @@ func InitWebServer() *App {
 	client := ioc.InitSaramaClient()
 	syncProducer := ioc.InitSyncProducer(client)
 	producer := article.NewSaramaSyncProducer(syncProducer)
-	articleService := service.NewArticleService(articleRepository, producer)
+	seriesDAO := dao.NewSeriesGORMDAO(db)
+	seriesRepository := repository.NewSeriesRepository(seriesDAO, userRepository)
+	articleReviewDAO := dao.NewArticleReviewGORMDAO(db)
+	articleReviewRepository := repository.NewCachedArticleReviewRepository(articleReviewDAO, articleCache)
+	reviewConfig := ioc.InitArticleReviewConfig()
+	articleReviewService := service.NewArticleReviewService(articleReviewRepository, articleRepository, producer, reviewConfig, loggerV1)
 	rewardServiceClient := ioc.InitReward()
+	articleService := service.NewArticleService(articleRepository, seriesRepository, articleReviewService, producer, rewardServiceClient, loggerV1)
 	clientv3Client := ioc.InitEtcd()
 	interactiveServiceClient := ioc.InitIntrClientV1(clientv3Client)
 	articleHandler := web.NewArticleHandler(loggerV1, articleService, rewardServiceClient, interactiveServiceClient)
 	wechatService := ioc.InitWechatService(loggerV1)
 	oAuth2WechatHandler := web.NewOAuth2WechatHandler(wechatService, handler, userService)
-	engine := ioc.InitWebServer(v, userHandler, articleHandler, oAuth2WechatHandler)
-	v2 := ioc.InitConsumers()
+	seriesService := service.NewSeriesService(seriesRepository)
+	seriesHandler := web.NewSeriesHandler(loggerV1, seriesService)
+	articleReviewHandler := web.NewArticleReviewHandler(loggerV1, articleReviewService)
+	jobDAO := dao.NewGORMJobDAO(db)
+	cronJobRepository := repository.NewPreemptJobRepository(jobDAO)
+	cronJobService := service.NewCronJobService(cronJobRepository, loggerV1)
+	jobHandler := web.NewJobHandler(loggerV1, cronJobService)
+	engine := ioc.InitWebServer(v, userHandler, articleHandler, seriesHandler, articleReviewHandler, jobHandler, oAuth2WechatHandler)
 	rankingCache := cache.NewRankingRedisCache(cmdable)
 	rankingRepository := repository.NewCachedRankingRepository(rankingCache)
-	rankingService := service.NewBatchRankingService(interactiveServiceClient, articleService, rankingRepository)
+	tagServiceClient := ioc.InitTagClient(clientv3Client)
+	rankingConfig := ioc.InitRankingConfig()
+	rankingService := service.NewBatchRankingService(interactiveServiceClient, tagServiceClient, articleService, rankingRepository, rankingConfig, loggerV1)
+	rankingEventConsumer := ranking.NewRankingEventConsumer(client, rankingService, loggerV1)
+	v2 := ioc.InitConsumers(rankingEventConsumer)
 	rlockClient := ioc.InitRlockClient(cmdable)
 	rankingJob := ioc.InitRankingJob(rankingService, rlockClient, loggerV1)
 	cron := ioc.InitJobs(loggerV1, rankingJob)
+	localFuncExecutor := ioc.InitLocalFuncExecutor(cronJobService, articleService)
+	httpExecutor := ioc.InitHttpExecutor()
+	grpcExecutor := ioc.InitGrpcExecutor(clientv3Client)
+	scheduler := ioc.InitScheduler(loggerV1, localFuncExecutor, httpExecutor, grpcExecutor, cronJobService)
 	app := &App{
 		server:    engine,
 		consumers: v2,
 		cron:      cron,
+		scheduler: scheduler,
 	}
 	return app
 }
@@ -76,4 +98,10 @@ func InitWebServer() *App {
 
 var interactiveSvcSet = wire.NewSet(dao2.NewGORMInteractiveDAO, cache2.NewInteractiveRedisCache, repository2.NewCachedInteractiveRepository, service2.NewInteractiveService)
 
-var rankingSvcSet = wire.NewSet(cache.NewRankingRedisCache, repository.NewCachedRankingRepository, service.NewBatchRankingService)
+var jobProviderSet = wire.NewSet(dao.NewGORMJobDAO, repository.NewPreemptJobRepository, service.NewCronJobService, ioc.InitLocalFuncExecutor, ioc.InitHttpExecutor, ioc.InitGrpcExecutor, ioc.InitScheduler)
+
+var seriesSvcSet = wire.NewSet(dao.NewSeriesGORMDAO, repository.NewSeriesRepository, service.NewSeriesService)
+
+var reviewSvcSet = wire.NewSet(dao.NewArticleReviewGORMDAO, repository.NewCachedArticleReviewRepository, ioc.InitArticleReviewConfig, service.NewArticleReviewService)
+
+var rankingSvcSet = wire.NewSet(cache.NewRankingRedisCache, repository.NewCachedRankingRepository, ioc.InitTagClient, ioc.InitRankingConfig, service.NewBatchRankingService, ranking.NewRankingEventConsumer)